@@ -6,6 +6,7 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -56,11 +57,17 @@ type ArtifactObservation struct {
 type ArtifactSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              ArtifactParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A ArtifactStatus represents the observed state of an Artifact.
 type ArtifactStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             ArtifactObservation `json:"atProvider,omitempty"`
 }
 
@@ -71,6 +78,8 @@ type ArtifactStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="DIGEST",type="string",JSONPath=".status.atProvider.digest"
 // +kubebuilder:printcolumn:name="SIZE",type="integer",JSONPath=".status.atProvider.size"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 
@@ -97,6 +106,16 @@ func (mg *Artifact) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this Artifact.
+func (mg *Artifact) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Artifact.
+func (mg *Artifact) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Artifact.
 func (mg *Artifact) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
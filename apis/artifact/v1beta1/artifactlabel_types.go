@@ -0,0 +1,161 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Scopes of the label attached by an ArtifactLabel.
+const (
+	LabelScopeGlobal  = "Global"
+	LabelScopeProject = "Project"
+)
+
+// ArtifactLabelParameters defines which label is attached to which artifact.
+type ArtifactLabelParameters struct {
+	// ProjectName is the name of the project that holds the artifact.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="projectName is immutable"
+	ProjectName string `json:"projectName"`
+
+	// RepositoryName is the name of the repository within the project.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="repositoryName is immutable"
+	RepositoryName string `json:"repositoryName"`
+
+	// Reference is the tag or digest of the artifact. When a tag is moved to
+	// another artifact, the label is moved with it.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Reference string `json:"reference"`
+
+	// Label is the name of an existing Harbor label.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="label is immutable"
+	Label string `json:"label"`
+
+	// LabelScope is Global for a system label or Project for a label of the
+	// artifact's project.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Global;Project
+	// +kubebuilder:default=Global
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="labelScope is immutable"
+	LabelScope *string `json:"labelScope,omitempty"`
+}
+
+// ArtifactLabelObservation defines the observed state of an ArtifactLabel.
+type ArtifactLabelObservation struct {
+	// LabelID is the ID of the label in Harbor.
+	LabelID *int64 `json:"labelId,omitempty"`
+
+	// Digest is the digest of the artifact the label is attached to.
+	Digest *string `json:"digest,omitempty"`
+}
+
+// An ArtifactLabelSpec defines the desired state of an ArtifactLabel.
+type ArtifactLabelSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              ArtifactLabelParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// An ArtifactLabelStatus represents the observed state of an ArtifactLabel.
+type ArtifactLabelStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             ArtifactLabelObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="LABEL",type="string",JSONPath=".spec.forProvider.label"
+// +kubebuilder:printcolumn:name="DIGEST",type="string",JSONPath=".status.atProvider.digest"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+
+// An ArtifactLabel attaches an existing Harbor label to an artifact, for
+// example to mark an image as approved for promotion. Deleting it detaches
+// the label.
+type ArtifactLabel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ArtifactLabelSpec   `json:"spec"`
+	Status ArtifactLabelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ArtifactLabelList contains a list of ArtifactLabel.
+type ArtifactLabelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ArtifactLabel `json:"items"`
+}
+
+// GetCondition of this ArtifactLabel.
+func (mg *ArtifactLabel) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this ArtifactLabel.
+func (mg *ArtifactLabel) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this ArtifactLabel.
+func (mg *ArtifactLabel) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this ArtifactLabel.
+func (mg *ArtifactLabel) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this ArtifactLabel.
+func (mg *ArtifactLabel) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this ArtifactLabel.
+func (mg *ArtifactLabel) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this ArtifactLabel.
+func (mg *ArtifactLabel) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this ArtifactLabel.
+func (mg *ArtifactLabel) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this ArtifactLabel.
+func (mg *ArtifactLabel) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this ArtifactLabel.
+func (mg *ArtifactLabel) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
@@ -29,5 +29,11 @@ var (
 )
 
 func addKnownTypes(s *runtime.Scheme) error {
+	s.AddKnownTypes(SchemeGroupVersion,
+		&Artifact{},
+		&ArtifactList{},
+		&ArtifactLabel{},
+		&ArtifactLabelList{},
+	)
 	return nil
 }
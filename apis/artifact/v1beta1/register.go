@@ -29,3 +29,11 @@ var (
 	ArtifactKindAPIVersion   = ArtifactKind + "." + SchemeGroupVersion.String()
 	ArtifactGroupVersionKind = SchemeGroupVersion.WithKind(ArtifactKind)
 )
+
+// ArtifactLabel type metadata.
+var (
+	ArtifactLabelKind             = reflect.TypeOf(ArtifactLabel{}).Name()
+	ArtifactLabelGroupKind        = schema.GroupKind{Group: Group, Kind: ArtifactLabelKind}
+	ArtifactLabelKindAPIVersion   = ArtifactLabelKind + "." + SchemeGroupVersion.String()
+	ArtifactLabelGroupVersionKind = SchemeGroupVersion.WithKind(ArtifactLabelKind)
+)
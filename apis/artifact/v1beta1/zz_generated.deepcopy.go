@@ -9,6 +9,7 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/rossigee/provider-harbor/apis/common"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -39,6 +40,150 @@ func (in *Artifact) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactLabel) DeepCopyInto(out *ArtifactLabel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactLabel.
+func (in *ArtifactLabel) DeepCopy() *ArtifactLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArtifactLabel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactLabelList) DeepCopyInto(out *ArtifactLabelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ArtifactLabel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactLabelList.
+func (in *ArtifactLabelList) DeepCopy() *ArtifactLabelList {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactLabelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArtifactLabelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactLabelObservation) DeepCopyInto(out *ArtifactLabelObservation) {
+	*out = *in
+	if in.LabelID != nil {
+		in, out := &in.LabelID, &out.LabelID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Digest != nil {
+		in, out := &in.Digest, &out.Digest
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactLabelObservation.
+func (in *ArtifactLabelObservation) DeepCopy() *ArtifactLabelObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactLabelObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactLabelParameters) DeepCopyInto(out *ArtifactLabelParameters) {
+	*out = *in
+	if in.LabelScope != nil {
+		in, out := &in.LabelScope, &out.LabelScope
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactLabelParameters.
+func (in *ArtifactLabelParameters) DeepCopy() *ArtifactLabelParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactLabelParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactLabelSpec) DeepCopyInto(out *ArtifactLabelSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactLabelSpec.
+func (in *ArtifactLabelSpec) DeepCopy() *ArtifactLabelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactLabelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactLabelStatus) DeepCopyInto(out *ArtifactLabelStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactLabelStatus.
+func (in *ArtifactLabelStatus) DeepCopy() *ArtifactLabelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactLabelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArtifactList) DeepCopyInto(out *ArtifactList) {
 	*out = *in
@@ -144,6 +289,11 @@ func (in *ArtifactSpec) DeepCopyInto(out *ArtifactSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactSpec.
@@ -160,6 +310,7 @@ func (in *ArtifactSpec) DeepCopy() *ArtifactSpec {
 func (in *ArtifactStatus) DeepCopyInto(out *ArtifactStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	"fmt"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeHarborUnavailable is true while the Harbor instance of a managed
+// resource answers 503 Service Unavailable, such as during an upgrade.
+const TypeHarborUnavailable xpv1.ConditionType = "HarborUnavailable"
+
+// Reasons for the HarborUnavailable condition.
+const (
+	ReasonServiceUnavailable xpv1.ConditionReason = "ServiceUnavailable"
+	ReasonHarborAvailable    xpv1.ConditionReason = "Available"
+)
+
+// HarborUnavailable returns a condition indicating that reconciliation is
+// deferred until Harbor is asked again at the given time.
+func HarborUnavailable(retryAt time.Time) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHarborUnavailable,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonServiceUnavailable,
+		Message:            fmt.Sprintf("Harbor answers 503 Service Unavailable; reconciliation is deferred until %s", retryAt.UTC().Format(time.RFC3339)),
+	}
+}
+
+// HarborAvailable returns a condition indicating that Harbor answers again.
+func HarborAvailable() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHarborUnavailable,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHarborAvailable,
+	}
+}
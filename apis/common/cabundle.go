@@ -0,0 +1,48 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kinds of object a CA bundle can be read from.
+const (
+	CABundleKindSecret    = "Secret"
+	CABundleKindConfigMap = "ConfigMap"
+)
+
+// CABundleSource selects a PEM encoded CA bundle from a Secret or ConfigMap
+// in the managed resource's namespace, such as the Secret cert-manager writes
+// for a Certificate or the ConfigMap trust-manager writes for a Bundle.
+type CABundleSource struct {
+	// Kind of the object holding the bundle.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +kubebuilder:default=Secret
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the object holding the bundle.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key holding the bundle.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="ca.crt"
+	Key string `json:"key,omitempty"`
+}
+
+// CABundleObservation records the CA bundle last applied to Harbor.
+type CABundleObservation struct {
+	// Fingerprint is the SHA-256 of the bundle's certificates.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Certificates is the number of certificates in the bundle.
+	Certificates int `json:"certificates,omitempty"`
+
+	// NotAfter is when the first certificate in the bundle expires.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
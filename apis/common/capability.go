@@ -0,0 +1,48 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeCapabilityMissing is true when the Harbor account of a managed
+// resource's ProviderConfig lacks a capability its kind needs, so the
+// provider does not call Harbor for it.
+const TypeCapabilityMissing xpv1.ConditionType = "CapabilityMissing"
+
+// Reasons for the CapabilityMissing condition.
+const (
+	ReasonAccountLacksCapability xpv1.ConditionReason = "AccountLacksCapability"
+	ReasonCapabilityPresent      xpv1.ConditionReason = "CapabilityPresent"
+)
+
+// CapabilityMissing returns a condition indicating that the named account of
+// the named ProviderConfig lacks capability.
+func CapabilityMissing(providerConfig, account, capability string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCapabilityMissing,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAccountLacksCapability,
+		Message: fmt.Sprintf("Harbor account %s of ProviderConfig %s lacks the %s capability; "+
+			"use an account that has it, or see the ProviderConfig's status.capabilities", account, providerConfig, capability),
+	}
+}
+
+// CapabilityPresent returns a condition indicating that the account has the
+// capabilities the managed resource needs.
+func CapabilityPresent() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCapabilityMissing,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCapabilityPresent,
+	}
+}
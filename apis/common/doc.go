@@ -0,0 +1,7 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package common contains types shared by the managed resource APIs.
+// +kubebuilder:object:generate=true
+package common
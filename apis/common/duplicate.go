@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeTerminalDuplicate is true when an older managed resource already
+// manages the same Harbor object, so this one is left alone.
+const TypeTerminalDuplicate xpv1.ConditionType = "TerminalDuplicate"
+
+// Reasons for the TerminalDuplicate condition.
+const (
+	ReasonDuplicateOf xpv1.ConditionReason = "DuplicateOf"
+	ReasonSoleManager xpv1.ConditionReason = "SoleManager"
+)
+
+// TerminalDuplicate returns a condition indicating that the managed resource
+// named owner, in namespace/name form, already manages the Harbor object.
+func TerminalDuplicate(owner string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTerminalDuplicate,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDuplicateOf,
+		Message:            fmt.Sprintf("the Harbor object is already managed by %s; this resource will not change or delete it", owner),
+	}
+}
+
+// NotDuplicate returns a condition indicating that no other managed resource
+// manages the Harbor object.
+func NotDuplicate() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTerminalDuplicate,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSoleManager,
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxRecentExecutions is how many executions a policy's status lists.
+const MaxRecentExecutions = 5
+
+// ExecutionObservation reports one run of a Harbor policy, such as a
+// replication or a retention cleanup.
+type ExecutionObservation struct {
+	// ID of the execution in Harbor
+	ID string `json:"id"`
+
+	// Status of the execution as reported by Harbor, such as Running,
+	// Succeed or Failed for replications and Running, Success or Error for
+	// retention cleanups
+	Status string `json:"status"`
+
+	// Trigger that started the execution, such as MANUAL or SCHEDULE
+	Trigger string `json:"trigger,omitempty"`
+
+	// StartTime is when the execution started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is when the execution finished. It is unset while the
+	// execution is running.
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// Succeeded counts the tasks that succeeded
+	Succeeded int64 `json:"succeeded"`
+
+	// Failed counts the tasks that failed
+	Failed int64 `json:"failed"`
+
+	// Total counts all tasks of the execution
+	Total int64 `json:"total"`
+}
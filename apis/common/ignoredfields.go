@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeFieldsIgnored is true when Harbor left out fields the provider wrote,
+// as a Harbor older than the version that added them does. The provider
+// sets it only in strict mode.
+const TypeFieldsIgnored xpv1.ConditionType = "FieldsIgnored"
+
+// Reasons for the FieldsIgnored condition.
+const (
+	ReasonIgnoredByHarbor xpv1.ConditionReason = "IgnoredByHarbor"
+	ReasonNoneIgnored     xpv1.ConditionReason = "NoneIgnored"
+)
+
+// FieldsIgnored returns a condition listing the fields Harbor ignored.
+func FieldsIgnored(fields []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFieldsIgnored,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonIgnoredByHarbor,
+		Message:            "Harbor ignored " + strings.Join(fields, ", ") + "; it may be older than the version that supports them",
+	}
+}
+
+// NoFieldsIgnored returns a condition indicating that Harbor kept every
+// field the provider wrote.
+func NoFieldsIgnored() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFieldsIgnored,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoneIgnored,
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	"fmt"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A MaintenanceWindow is a recurring period during which the provider keeps
+// observing a managed resource but does not create, update or delete it in
+// Harbor.
+type MaintenanceWindow struct {
+	// Schedule is a five-field cron expression for when the window opens,
+	// such as "0 22 * * 5" for 22:00 every Friday. Times are UTC unless the
+	// expression starts with CRON_TZ=<zone>, for example
+	// "CRON_TZ=Europe/London 0 22 * * 5".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open, such as "2h".
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+}
+
+// TypeScheduledSuspension is true while a managed resource is inside one of
+// its maintenance windows.
+const TypeScheduledSuspension xpv1.ConditionType = "ScheduledSuspension"
+
+// Reasons for the ScheduledSuspension condition.
+const (
+	ReasonInMaintenanceWindow xpv1.ConditionReason = "InMaintenanceWindow"
+	ReasonOutsideMaintenance  xpv1.ConditionReason = "OutsideMaintenanceWindow"
+)
+
+// ScheduledSuspension returns a condition indicating that changes to the
+// resource in Harbor are deferred until the given time.
+func ScheduledSuspension(until time.Time) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeScheduledSuspension,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInMaintenanceWindow,
+		Message:            fmt.Sprintf("changes to Harbor are deferred until the maintenance window closes at %s", until.UTC().Format(time.RFC3339)),
+	}
+}
+
+// NoScheduledSuspension returns a condition indicating that the resource is
+// outside its maintenance windows.
+func NoScheduledSuspension() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeScheduledSuspension,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOutsideMaintenance,
+	}
+}
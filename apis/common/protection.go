@@ -0,0 +1,44 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeProtected is true when the ProviderConfig of a managed resource
+// protects the Harbor object it manages from being changed or deleted.
+const TypeProtected xpv1.ConditionType = "Protected"
+
+// Reasons for the Protected condition.
+const (
+	ReasonProtectedByPolicy xpv1.ConditionReason = "ProtectedByPolicy"
+	ReasonNotProtected      xpv1.ConditionReason = "NotProtected"
+)
+
+// Protected returns a condition indicating that the Harbor object is left
+// as it is, for the reason given in message.
+func Protected(message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeProtected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonProtectedByPolicy,
+		Message:            message + "; this resource will not change or delete it",
+	}
+}
+
+// NotProtected returns a condition indicating that no protection applies to
+// the Harbor object.
+func NotProtected() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeProtected,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotProtected,
+	}
+}
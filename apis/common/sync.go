@@ -0,0 +1,34 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SyncStatus records the last successful observation of a managed resource.
+type SyncStatus struct {
+	// LastSyncTime is when the resource was last successfully observed in
+	// Harbor.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Drift is true when the resource in Harbor differed from its desired
+	// state at that observation.
+	Drift *bool `json:"drift,omitempty"`
+
+	// AppliedGeneration is the last generation of the spec observed to be
+	// applied in Harbor.
+	AppliedGeneration *int64 `json:"appliedGeneration,omitempty"`
+
+	// PendingSince is when a later generation of the spec was first observed
+	// not yet applied in Harbor.
+	PendingSince *metav1.Time `json:"pendingSince,omitempty"`
+}
+
+// SetSynced records a successful observation.
+func (s *SyncStatus) SetSynced(t metav1.Time, drift bool) {
+	s.LastSyncTime = &t
+	s.Drift = &drift
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Harbor authentication modes.
+const (
+	AuthModeDatabase = "db_auth"
+	AuthModeLDAP     = "ldap_auth"
+	AuthModeOIDC     = "oidc_auth"
+	AuthModeHTTP     = "http_auth"
+	AuthModeUAA      = "uaa_auth"
+)
+
+// ConfigAuthParameters are the authentication settings of a Harbor instance.
+// Each setting that is left unset keeps the value Harbor already has.
+// +kubebuilder:validation:XValidation:rule="!has(self.authMode) || self.authMode != 'oidc_auth' || has(self.oidc)",message="oidc is required when authMode is oidc_auth"
+// +kubebuilder:validation:XValidation:rule="!has(self.authMode) || self.authMode != 'ldap_auth' || has(self.ldap)",message="ldap is required when authMode is ldap_auth"
+type ConfigAuthParameters struct {
+	// AuthMode is how Harbor authenticates users. Harbor only allows it to
+	// change while no user other than admin has been created or has logged
+	// in.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=db_auth;ldap_auth;oidc_auth;http_auth;uaa_auth
+	AuthMode *string `json:"authMode,omitempty"`
+
+	// SelfRegistration lets users sign themselves up when AuthMode is
+	// db_auth.
+	// +kubebuilder:validation:Optional
+	SelfRegistration *bool `json:"selfRegistration,omitempty"`
+
+	// OIDC configures the OpenID Connect provider Harbor signs users in
+	// with when AuthMode is oidc_auth.
+	// +kubebuilder:validation:Optional
+	OIDC *OIDCSettings `json:"oidc,omitempty"`
+
+	// LDAP configures the directory Harbor authenticates users against
+	// when AuthMode is ldap_auth.
+	// +kubebuilder:validation:Optional
+	LDAP *LDAPSettings `json:"ldap,omitempty"`
+}
+
+// OIDCSettings configure Harbor's OpenID Connect provider.
+type OIDCSettings struct {
+	// Name is the provider name shown on Harbor's login page.
+	// +kubebuilder:validation:Optional
+	Name *string `json:"name,omitempty"`
+
+	// Endpoint is the URL of the provider, at which its
+	// .well-known/openid-configuration document is served.
+	// +kubebuilder:validation:Optional
+	Endpoint *string `json:"endpoint,omitempty"`
+
+	// ClientID is the client ID Harbor is registered with at the provider.
+	// +kubebuilder:validation:Optional
+	ClientID *string `json:"clientId,omitempty"`
+
+	// ClientSecretSecretRef references the key of a Secret holding the
+	// client secret. A reference without a namespace is to a Secret in the
+	// ConfigAuth's namespace.
+	// +kubebuilder:validation:Optional
+	ClientSecretSecretRef *xpv1.SecretKeySelector `json:"clientSecretSecretRef,omitempty"`
+
+	// Scopes are the scopes Harbor requests. They must include openid, and
+	// offline_access for CLI secrets to keep working.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	Scopes []string `json:"scopes,omitempty"`
+
+	// GroupsClaim is the claim holding the groups a user belongs to.
+	// +kubebuilder:validation:Optional
+	GroupsClaim *string `json:"groupsClaim,omitempty"`
+
+	// AdminGroup is the group whose members are Harbor administrators.
+	// +kubebuilder:validation:Optional
+	AdminGroup *string `json:"adminGroup,omitempty"`
+
+	// GroupFilter is a regular expression of the groups Harbor imports.
+	// +kubebuilder:validation:Optional
+	GroupFilter *string `json:"groupFilter,omitempty"`
+
+	// UserClaim is the claim Harbor takes usernames from.
+	// +kubebuilder:validation:Optional
+	UserClaim *string `json:"userClaim,omitempty"`
+
+	// VerifyCert verifies the provider's TLS certificate.
+	// +kubebuilder:validation:Optional
+	VerifyCert *bool `json:"verifyCert,omitempty"`
+
+	// AutoOnboard creates users on their first login instead of asking
+	// them for a username.
+	// +kubebuilder:validation:Optional
+	AutoOnboard *bool `json:"autoOnboard,omitempty"`
+}
+
+// LDAPSettings configure the directory Harbor authenticates users against.
+type LDAPSettings struct {
+	// URL is the directory's ldap:// or ldaps:// URL.
+	// +kubebuilder:validation:Optional
+	URL *string `json:"url,omitempty"`
+
+	// BaseDN is the DN users are searched for under.
+	// +kubebuilder:validation:Optional
+	BaseDN *string `json:"baseDn,omitempty"`
+
+	// SearchDN is the DN Harbor binds as to search the directory.
+	// +kubebuilder:validation:Optional
+	SearchDN *string `json:"searchDn,omitempty"`
+
+	// SearchPasswordSecretRef references the key of a Secret holding
+	// SearchDN's password. A reference without a namespace is to a Secret
+	// in the ConfigAuth's namespace.
+	// +kubebuilder:validation:Optional
+	SearchPasswordSecretRef *xpv1.SecretKeySelector `json:"searchPasswordSecretRef,omitempty"`
+
+	// Filter is an LDAP filter users must also match.
+	// +kubebuilder:validation:Optional
+	Filter *string `json:"filter,omitempty"`
+
+	// UID is the attribute matched against the username users log in with.
+	// +kubebuilder:validation:Optional
+	UID *string `json:"uid,omitempty"`
+
+	// Scope is how far below BaseDN users are searched for: 0 for the base
+	// object only, 1 for one level and 2 for the whole subtree.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2
+	Scope *int64 `json:"scope,omitempty"`
+
+	// Timeout is how long, in seconds, Harbor waits for the directory.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Timeout *int64 `json:"timeout,omitempty"`
+
+	// VerifyCert verifies the directory's TLS certificate.
+	// +kubebuilder:validation:Optional
+	VerifyCert *bool `json:"verifyCert,omitempty"`
+
+	// GroupBaseDN is the DN groups are searched for under.
+	// +kubebuilder:validation:Optional
+	GroupBaseDN *string `json:"groupBaseDn,omitempty"`
+
+	// GroupSearchFilter is an LDAP filter groups must also match.
+	// +kubebuilder:validation:Optional
+	GroupSearchFilter *string `json:"groupSearchFilter,omitempty"`
+
+	// GroupAttributeName is the attribute holding a group's name.
+	// +kubebuilder:validation:Optional
+	GroupAttributeName *string `json:"groupAttributeName,omitempty"`
+
+	// GroupAdminDN is the DN of the group whose members are Harbor
+	// administrators.
+	// +kubebuilder:validation:Optional
+	GroupAdminDN *string `json:"groupAdminDn,omitempty"`
+
+	// GroupMembershipAttribute is the user attribute listing the groups a
+	// user belongs to.
+	// +kubebuilder:validation:Optional
+	GroupMembershipAttribute *string `json:"groupMembershipAttribute,omitempty"`
+
+	// GroupSearchScope is how far below GroupBaseDN groups are searched
+	// for, as for Scope.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2
+	GroupSearchScope *int64 `json:"groupSearchScope,omitempty"`
+}
+
+// ConfigAuthObservation is the current value of each authentication
+// setting. Harbor never returns the OIDC client secret or the LDAP search
+// password.
+type ConfigAuthObservation struct {
+	// AuthMode is how Harbor authenticates users.
+	AuthMode *string `json:"authMode,omitempty"`
+
+	// SelfRegistration is whether users may sign themselves up.
+	SelfRegistration *bool `json:"selfRegistration,omitempty"`
+
+	// OIDC is Harbor's OpenID Connect provider, if one is configured.
+	OIDC *OIDCSettings `json:"oidc,omitempty"`
+
+	// LDAP is the directory Harbor is configured with, if any.
+	LDAP *LDAPSettings `json:"ldap,omitempty"`
+
+	// SecretHash is a hash of the OIDC client secret and LDAP search
+	// password last written to Harbor, which is how changes to them are
+	// detected.
+	SecretHash *string `json:"secretHash,omitempty"`
+}
+
+// A ConfigAuthSpec defines the desired state of a ConfigAuth.
+type ConfigAuthSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              ConfigAuthParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// A ConfigAuthStatus represents the observed state of a ConfigAuth.
+type ConfigAuthStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             ConfigAuthObservation `json:"atProvider,omitempty"`
+}
+
+// A ConfigAuth manages how the Harbor instance its ProviderConfig points at
+// authenticates users: its authentication mode and OIDC or LDAP settings.
+// Harbor has one set of settings, so there should be one ConfigAuth per
+// ProviderConfig. Deleting a ConfigAuth leaves the settings as they are.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AUTH-MODE",type="string",JSONPath=".status.atProvider.authMode"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type ConfigAuth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ConfigAuthSpec   `json:"spec"`
+	Status ConfigAuthStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type ConfigAuthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConfigAuth `json:"items"`
+}
+
+// GetCondition of this ConfigAuth.
+func (mg *ConfigAuth) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this ConfigAuth.
+func (mg *ConfigAuth) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this ConfigAuth.
+func (mg *ConfigAuth) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this ConfigAuth.
+func (mg *ConfigAuth) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this ConfigAuth.
+func (mg *ConfigAuth) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this ConfigAuth.
+func (mg *ConfigAuth) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this ConfigAuth.
+func (mg *ConfigAuth) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this ConfigAuth.
+func (mg *ConfigAuth) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this ConfigAuth.
+func (mg *ConfigAuth) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this ConfigAuth.
+func (mg *ConfigAuth) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
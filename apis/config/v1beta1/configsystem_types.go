@@ -0,0 +1,193 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Project creation restrictions.
+const (
+	ProjectCreationEveryone  = "everyone"
+	ProjectCreationAdminOnly = "adminonly"
+)
+
+// ConfigSystemParameters are the system settings of a Harbor instance. Each
+// setting that is left unset keeps the value Harbor already has.
+type ConfigSystemParameters struct {
+	// TokenExpiration is how long, in minutes, tokens issued for the
+	// internal registry remain valid.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TokenExpiration *int64 `json:"tokenExpiration,omitempty"`
+
+	// ProjectCreationRestriction controls who may create projects.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=everyone;adminonly
+	ProjectCreationRestriction *string `json:"projectCreationRestriction,omitempty"`
+
+	// RobotTokenDuration is the default lifetime, in days, of robot account
+	// tokens.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	RobotTokenDuration *int64 `json:"robotTokenDuration,omitempty"`
+
+	// BannerMessage is shown at the top of every page of the Harbor UI.
+	// +kubebuilder:validation:Optional
+	BannerMessage *BannerMessage `json:"bannerMessage,omitempty"`
+
+	// ReadOnly puts Harbor in read-only mode, in which artifacts can be
+	// pulled but not pushed or deleted, for example during maintenance.
+	// +kubebuilder:validation:Optional
+	ReadOnly *bool `json:"readOnly,omitempty"`
+}
+
+// A BannerMessage is a message shown to every Harbor UI user.
+// +kubebuilder:validation:XValidation:rule="has(self.fromDate) == has(self.toDate)",message="fromDate and toDate must be set together"
+type BannerMessage struct {
+	// Message is the text of the banner. An empty message removes the
+	// banner.
+	// +kubebuilder:validation:Required
+	Message string `json:"message"`
+
+	// Type sets the colour of the banner.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=success;info;warning;danger
+	// +kubebuilder:default=info
+	Type *string `json:"type,omitempty"`
+
+	// Closable lets users dismiss the banner.
+	// +kubebuilder:validation:Optional
+	Closable *bool `json:"closable,omitempty"`
+
+	// FromDate is the first day, as MM/DD/YYYY, the banner is shown. The
+	// banner is shown only between FromDate and ToDate when both are set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(0[1-9]|1[0-2])/(0[1-9]|[12][0-9]|3[01])/[0-9]{4}$`
+	FromDate *string `json:"fromDate,omitempty"`
+
+	// ToDate is the last day, as MM/DD/YYYY, the banner is shown.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(0[1-9]|1[0-2])/(0[1-9]|[12][0-9]|3[01])/[0-9]{4}$`
+	ToDate *string `json:"toDate,omitempty"`
+}
+
+// ConfigSystemObservation is the current value of each system setting.
+type ConfigSystemObservation struct {
+	// TokenExpiration is the registry token lifetime in minutes.
+	TokenExpiration *int64 `json:"tokenExpiration,omitempty"`
+
+	// ProjectCreationRestriction is who may create projects.
+	ProjectCreationRestriction *string `json:"projectCreationRestriction,omitempty"`
+
+	// RobotTokenDuration is the default robot token lifetime in days.
+	RobotTokenDuration *int64 `json:"robotTokenDuration,omitempty"`
+
+	// BannerMessage is the banner currently shown, if any.
+	BannerMessage *BannerMessage `json:"bannerMessage,omitempty"`
+
+	// ReadOnly is whether Harbor is in read-only mode.
+	ReadOnly *bool `json:"readOnly,omitempty"`
+}
+
+// A ConfigSystemSpec defines the desired state of a ConfigSystem.
+type ConfigSystemSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              ConfigSystemParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// A ConfigSystemStatus represents the observed state of a ConfigSystem.
+type ConfigSystemStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             ConfigSystemObservation `json:"atProvider,omitempty"`
+}
+
+// A ConfigSystem manages the system settings of the Harbor instance its
+// ProviderConfig points at. Harbor has one set of settings, so there should
+// be one ConfigSystem per ProviderConfig. Deleting a ConfigSystem leaves the
+// settings as they are.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PROJECT-CREATION",type="string",JSONPath=".status.atProvider.projectCreationRestriction"
+// +kubebuilder:printcolumn:name="READ-ONLY",type="boolean",JSONPath=".status.atProvider.readOnly"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type ConfigSystem struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ConfigSystemSpec   `json:"spec"`
+	Status ConfigSystemStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type ConfigSystemList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConfigSystem `json:"items"`
+}
+
+// GetCondition of this ConfigSystem.
+func (mg *ConfigSystem) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this ConfigSystem.
+func (mg *ConfigSystem) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this ConfigSystem.
+func (mg *ConfigSystem) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this ConfigSystem.
+func (mg *ConfigSystem) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this ConfigSystem.
+func (mg *ConfigSystem) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this ConfigSystem.
+func (mg *ConfigSystem) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this ConfigSystem.
+func (mg *ConfigSystem) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this ConfigSystem.
+func (mg *ConfigSystem) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this ConfigSystem.
+func (mg *ConfigSystem) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this ConfigSystem.
+func (mg *ConfigSystem) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
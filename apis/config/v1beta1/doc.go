@@ -0,0 +1,9 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package v1beta1 contains the v1beta1 API of the harbor config provider.
+// +kubebuilder:object:generate=true
+// +groupName=config.harbor.m.crossplane.io
+// +versionName=v1beta1
+package v1beta1
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GarbageCollectionScheduleParameters are when and how Harbor runs garbage
+// collection.
+type GarbageCollectionScheduleParameters struct {
+	// Cron is when garbage collection runs, as a cron expression with six
+	// fields starting with seconds, such as "0 0 2 * * 6" for 02:00 every
+	// Saturday. Harbor evaluates it in UTC.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^\s*\S+(\s+\S+){5}\s*$`
+	Cron string `json:"cron"`
+
+	// DeleteUntagged deletes artifacts that have no tags.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	DeleteUntagged *bool `json:"deleteUntagged,omitempty"`
+
+	// Workers is how many workers delete blobs in parallel. Harbor chooses
+	// when it is unset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=5
+	Workers *int64 `json:"workers,omitempty"`
+
+	// DryRun reports what garbage collection would delete without deleting
+	// it.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	DryRun *bool `json:"dryRun,omitempty"`
+}
+
+// GarbageCollectionScheduleObservation is Harbor's current garbage
+// collection schedule.
+type GarbageCollectionScheduleObservation struct {
+	// Type is Custom for a schedule set by the provider, or Hourly, Daily
+	// or Weekly for one set in the Harbor UI.
+	Type *string `json:"type,omitempty"`
+
+	// Cron is the schedule's cron expression.
+	Cron *string `json:"cron,omitempty"`
+
+	// DeleteUntagged is whether untagged artifacts are deleted.
+	DeleteUntagged *bool `json:"deleteUntagged,omitempty"`
+
+	// Workers is how many workers delete blobs in parallel.
+	Workers *int64 `json:"workers,omitempty"`
+
+	// DryRun is whether garbage collection only reports what it would
+	// delete.
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// NextScheduledTime is when garbage collection runs next.
+	NextScheduledTime *metav1.Time `json:"nextScheduledTime,omitempty"`
+}
+
+// A GarbageCollectionScheduleSpec defines the desired state of a
+// GarbageCollectionSchedule.
+type GarbageCollectionScheduleSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              GarbageCollectionScheduleParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// A GarbageCollectionScheduleStatus represents the observed state of a
+// GarbageCollectionSchedule.
+type GarbageCollectionScheduleStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             GarbageCollectionScheduleObservation `json:"atProvider,omitempty"`
+}
+
+// A GarbageCollectionSchedule manages when the Harbor instance its
+// ProviderConfig points at runs garbage collection. Harbor has one schedule,
+// so there should be one GarbageCollectionSchedule per ProviderConfig.
+// Deleting a GarbageCollectionSchedule stops scheduled garbage collection.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="CRON",type="string",JSONPath=".spec.forProvider.cron"
+// +kubebuilder:printcolumn:name="NEXT-RUN",type="date",JSONPath=".status.atProvider.nextScheduledTime"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type GarbageCollectionSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GarbageCollectionScheduleSpec   `json:"spec"`
+	Status GarbageCollectionScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type GarbageCollectionScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GarbageCollectionSchedule `json:"items"`
+}
+
+// GetCondition of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this GarbageCollectionSchedule.
+func (mg *GarbageCollectionSchedule) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package v1beta1 contains the v1beta1 API of the harbor config provider.
+// +kubebuilder:object:generate=true
+// +groupName=config.harbor.m.crossplane.io
+// +versionName=v1beta1
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Package type metadata.
+const (
+	Group   = "config.harbor.m.crossplane.io"
+	Version = "v1beta1"
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: Group, Version: Version}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(s *runtime.Scheme) error {
+	s.AddKnownTypes(SchemeGroupVersion,
+		&ConfigSystem{},
+		&ConfigSystemList{},
+		&GarbageCollectionSchedule{},
+		&GarbageCollectionScheduleList{},
+		&ConfigAuth{},
+		&ConfigAuthList{},
+	)
+	return nil
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ConfigSystem type metadata.
+var (
+	ConfigSystemKind             = reflect.TypeOf(ConfigSystem{}).Name()
+	ConfigSystemGroupKind        = schema.GroupKind{Group: Group, Kind: ConfigSystemKind}
+	ConfigSystemKindAPIVersion   = ConfigSystemKind + "." + SchemeGroupVersion.String()
+	ConfigSystemGroupVersionKind = SchemeGroupVersion.WithKind(ConfigSystemKind)
+)
+
+// GarbageCollectionSchedule type metadata.
+var (
+	GarbageCollectionScheduleKind             = reflect.TypeOf(GarbageCollectionSchedule{}).Name()
+	GarbageCollectionScheduleGroupKind        = schema.GroupKind{Group: Group, Kind: GarbageCollectionScheduleKind}
+	GarbageCollectionScheduleKindAPIVersion   = GarbageCollectionScheduleKind + "." + SchemeGroupVersion.String()
+	GarbageCollectionScheduleGroupVersionKind = SchemeGroupVersion.WithKind(GarbageCollectionScheduleKind)
+)
+
+// ConfigAuth type metadata.
+var (
+	ConfigAuthKind             = reflect.TypeOf(ConfigAuth{}).Name()
+	ConfigAuthGroupKind        = schema.GroupKind{Group: Group, Kind: ConfigAuthKind}
+	ConfigAuthKindAPIVersion   = ConfigAuthKind + "." + SchemeGroupVersion.String()
+	ConfigAuthGroupVersionKind = SchemeGroupVersion.WithKind(ConfigAuthKind)
+)
@@ -0,0 +1,736 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BannerMessage) DeepCopyInto(out *BannerMessage) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.Closable != nil {
+		in, out := &in.Closable, &out.Closable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FromDate != nil {
+		in, out := &in.FromDate, &out.FromDate
+		*out = new(string)
+		**out = **in
+	}
+	if in.ToDate != nil {
+		in, out := &in.ToDate, &out.ToDate
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BannerMessage.
+func (in *BannerMessage) DeepCopy() *BannerMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(BannerMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigAuth) DeepCopyInto(out *ConfigAuth) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigAuth.
+func (in *ConfigAuth) DeepCopy() *ConfigAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConfigAuth) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigAuthList) DeepCopyInto(out *ConfigAuthList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ConfigAuth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigAuthList.
+func (in *ConfigAuthList) DeepCopy() *ConfigAuthList {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigAuthList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConfigAuthList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigAuthObservation) DeepCopyInto(out *ConfigAuthObservation) {
+	*out = *in
+	if in.AuthMode != nil {
+		in, out := &in.AuthMode, &out.AuthMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.SelfRegistration != nil {
+		in, out := &in.SelfRegistration, &out.SelfRegistration
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretHash != nil {
+		in, out := &in.SecretHash, &out.SecretHash
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigAuthObservation.
+func (in *ConfigAuthObservation) DeepCopy() *ConfigAuthObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigAuthObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigAuthParameters) DeepCopyInto(out *ConfigAuthParameters) {
+	*out = *in
+	if in.AuthMode != nil {
+		in, out := &in.AuthMode, &out.AuthMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.SelfRegistration != nil {
+		in, out := &in.SelfRegistration, &out.SelfRegistration
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPSettings)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigAuthParameters.
+func (in *ConfigAuthParameters) DeepCopy() *ConfigAuthParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigAuthParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigAuthSpec) DeepCopyInto(out *ConfigAuthSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigAuthSpec.
+func (in *ConfigAuthSpec) DeepCopy() *ConfigAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigAuthStatus) DeepCopyInto(out *ConfigAuthStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigAuthStatus.
+func (in *ConfigAuthStatus) DeepCopy() *ConfigAuthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigAuthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSystem) DeepCopyInto(out *ConfigSystem) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSystem.
+func (in *ConfigSystem) DeepCopy() *ConfigSystem {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSystem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConfigSystem) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSystemList) DeepCopyInto(out *ConfigSystemList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ConfigSystem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSystemList.
+func (in *ConfigSystemList) DeepCopy() *ConfigSystemList {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSystemList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConfigSystemList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSystemObservation) DeepCopyInto(out *ConfigSystemObservation) {
+	*out = *in
+	if in.TokenExpiration != nil {
+		in, out := &in.TokenExpiration, &out.TokenExpiration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ProjectCreationRestriction != nil {
+		in, out := &in.ProjectCreationRestriction, &out.ProjectCreationRestriction
+		*out = new(string)
+		**out = **in
+	}
+	if in.RobotTokenDuration != nil {
+		in, out := &in.RobotTokenDuration, &out.RobotTokenDuration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BannerMessage != nil {
+		in, out := &in.BannerMessage, &out.BannerMessage
+		*out = new(BannerMessage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSystemObservation.
+func (in *ConfigSystemObservation) DeepCopy() *ConfigSystemObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSystemObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSystemParameters) DeepCopyInto(out *ConfigSystemParameters) {
+	*out = *in
+	if in.TokenExpiration != nil {
+		in, out := &in.TokenExpiration, &out.TokenExpiration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ProjectCreationRestriction != nil {
+		in, out := &in.ProjectCreationRestriction, &out.ProjectCreationRestriction
+		*out = new(string)
+		**out = **in
+	}
+	if in.RobotTokenDuration != nil {
+		in, out := &in.RobotTokenDuration, &out.RobotTokenDuration
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BannerMessage != nil {
+		in, out := &in.BannerMessage, &out.BannerMessage
+		*out = new(BannerMessage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSystemParameters.
+func (in *ConfigSystemParameters) DeepCopy() *ConfigSystemParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSystemParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSystemSpec) DeepCopyInto(out *ConfigSystemSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSystemSpec.
+func (in *ConfigSystemSpec) DeepCopy() *ConfigSystemSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSystemSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSystemStatus) DeepCopyInto(out *ConfigSystemStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSystemStatus.
+func (in *ConfigSystemStatus) DeepCopy() *ConfigSystemStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSystemStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionSchedule) DeepCopyInto(out *GarbageCollectionSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionSchedule.
+func (in *GarbageCollectionSchedule) DeepCopy() *GarbageCollectionSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GarbageCollectionSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionScheduleList) DeepCopyInto(out *GarbageCollectionScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GarbageCollectionSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionScheduleList.
+func (in *GarbageCollectionScheduleList) DeepCopy() *GarbageCollectionScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GarbageCollectionScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionScheduleObservation) DeepCopyInto(out *GarbageCollectionScheduleObservation) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.Cron != nil {
+		in, out := &in.Cron, &out.Cron
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeleteUntagged != nil {
+		in, out := &in.DeleteUntagged, &out.DeleteUntagged
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NextScheduledTime != nil {
+		in, out := &in.NextScheduledTime, &out.NextScheduledTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionScheduleObservation.
+func (in *GarbageCollectionScheduleObservation) DeepCopy() *GarbageCollectionScheduleObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionScheduleObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionScheduleParameters) DeepCopyInto(out *GarbageCollectionScheduleParameters) {
+	*out = *in
+	if in.DeleteUntagged != nil {
+		in, out := &in.DeleteUntagged, &out.DeleteUntagged
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionScheduleParameters.
+func (in *GarbageCollectionScheduleParameters) DeepCopy() *GarbageCollectionScheduleParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionScheduleParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionScheduleSpec) DeepCopyInto(out *GarbageCollectionScheduleSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionScheduleSpec.
+func (in *GarbageCollectionScheduleSpec) DeepCopy() *GarbageCollectionScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionScheduleStatus) DeepCopyInto(out *GarbageCollectionScheduleStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionScheduleStatus.
+func (in *GarbageCollectionScheduleStatus) DeepCopy() *GarbageCollectionScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollectionScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPSettings) DeepCopyInto(out *LDAPSettings) {
+	*out = *in
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+	if in.BaseDN != nil {
+		in, out := &in.BaseDN, &out.BaseDN
+		*out = new(string)
+		**out = **in
+	}
+	if in.SearchDN != nil {
+		in, out := &in.SearchDN, &out.SearchDN
+		*out = new(string)
+		**out = **in
+	}
+	if in.SearchPasswordSecretRef != nil {
+		in, out := &in.SearchPasswordSecretRef, &out.SearchPasswordSecretRef
+		*out = new(v2.SecretKeySelector)
+		**out = **in
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(string)
+		**out = **in
+	}
+	if in.UID != nil {
+		in, out := &in.UID, &out.UID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(int64)
+		**out = **in
+	}
+	if in.VerifyCert != nil {
+		in, out := &in.VerifyCert, &out.VerifyCert
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GroupBaseDN != nil {
+		in, out := &in.GroupBaseDN, &out.GroupBaseDN
+		*out = new(string)
+		**out = **in
+	}
+	if in.GroupSearchFilter != nil {
+		in, out := &in.GroupSearchFilter, &out.GroupSearchFilter
+		*out = new(string)
+		**out = **in
+	}
+	if in.GroupAttributeName != nil {
+		in, out := &in.GroupAttributeName, &out.GroupAttributeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.GroupAdminDN != nil {
+		in, out := &in.GroupAdminDN, &out.GroupAdminDN
+		*out = new(string)
+		**out = **in
+	}
+	if in.GroupMembershipAttribute != nil {
+		in, out := &in.GroupMembershipAttribute, &out.GroupMembershipAttribute
+		*out = new(string)
+		**out = **in
+	}
+	if in.GroupSearchScope != nil {
+		in, out := &in.GroupSearchScope, &out.GroupSearchScope
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPSettings.
+func (in *LDAPSettings) DeepCopy() *LDAPSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCSettings) DeepCopyInto(out *OIDCSettings) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClientID != nil {
+		in, out := &in.ClientID, &out.ClientID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClientSecretSecretRef != nil {
+		in, out := &in.ClientSecretSecretRef, &out.ClientSecretSecretRef
+		*out = new(v2.SecretKeySelector)
+		**out = **in
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupsClaim != nil {
+		in, out := &in.GroupsClaim, &out.GroupsClaim
+		*out = new(string)
+		**out = **in
+	}
+	if in.AdminGroup != nil {
+		in, out := &in.AdminGroup, &out.AdminGroup
+		*out = new(string)
+		**out = **in
+	}
+	if in.GroupFilter != nil {
+		in, out := &in.GroupFilter, &out.GroupFilter
+		*out = new(string)
+		**out = **in
+	}
+	if in.UserClaim != nil {
+		in, out := &in.UserClaim, &out.UserClaim
+		*out = new(string)
+		**out = **in
+	}
+	if in.VerifyCert != nil {
+		in, out := &in.VerifyCert, &out.VerifyCert
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoOnboard != nil {
+		in, out := &in.AutoOnboard, &out.AutoOnboard
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCSettings.
+func (in *OIDCSettings) DeepCopy() *OIDCSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCSettings)
+	in.DeepCopyInto(out)
+	return out
+}
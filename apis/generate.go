@@ -21,6 +21,9 @@ Copyright 2024 Crossplane Harbor Provider.
 // Generate crossplane-runtime methodsets (resource.Claim, etc)
 //go:generate go run -tags generate github.com/crossplane/crossplane-tools/cmd/angryjet generate-methodsets --header-file=../hack/boilerplate.go.txt ./...
 
+// Render the CRDs into the API reference and embed it in the package metadata
+//go:generate go run ../cmd/docgen --crds ../package/crds --output ../docs/api-reference.yaml --package ../package/crossplane.yaml
+
 package apis
 
 import (
@@ -5,14 +5,62 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// MemberParameters grant a Harbor user or user group a role on a project.
+// +kubebuilder:validation:XValidation:rule="has(self.projectId) || has(self.projectRef) || has(self.projectSelector)",message="one of projectId, projectRef and projectSelector must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectRef) || !has(self.projectRef.__namespace__)",message="projectRef must name a Project in the same namespace"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectSelector) || !has(self.projectSelector.__namespace__)",message="projectSelector must select a Project in the same namespace"
+// +kubebuilder:validation:XValidation:rule="has(self.username) != has(self.memberGroup)",message="exactly one of username and memberGroup must be set"
 type MemberParameters struct {
-	ProjectID string `json:"projectId"`
-	Username  string `json:"username"`
-	Role      string `json:"role"`
+	// ProjectID is the ID or name of the Harbor project
+	// +kubebuilder:validation:Optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// ProjectRef references a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectRef *xpv1.NamespacedReference `json:"projectRef,omitempty"`
+
+	// ProjectSelector selects a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectSelector *xpv1.NamespacedSelector `json:"projectSelector,omitempty"`
+
+	// Username is the Harbor user to make a member
+	// +kubebuilder:validation:Optional
+	Username string `json:"username,omitempty"`
+
+	// MemberGroup is the Harbor user group to make a member
+	// +kubebuilder:validation:Optional
+	MemberGroup *MemberGroup `json:"memberGroup,omitempty"`
+
+	// Role is the member's role on the project. It is changed in place.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=projectAdmin;maintainer;developer;guest;limitedGuest
+	Role string `json:"role"`
+}
+
+// A MemberGroup identifies a Harbor user group.
+type MemberGroup struct {
+	// GroupName is the name of the group
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	GroupName string `json:"groupName"`
+
+	// GroupType is the group type: 1 for LDAP, 2 for HTTP, 3 for OIDC
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=1;2;3
+	GroupType int64 `json:"groupType"`
 }
 
 type MemberObservation struct {
@@ -26,10 +74,16 @@ type MemberObservation struct {
 type MemberSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              MemberParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 type MemberStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             MemberObservation `json:"atProvider,omitempty"`
 }
 
@@ -38,7 +92,10 @@ type MemberStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="USERNAME",type="string",JSONPath=".spec.forProvider.username"
+// +kubebuilder:printcolumn:name="GROUP",type="string",JSONPath=".spec.forProvider.memberGroup.groupName"
 // +kubebuilder:printcolumn:name="ROLE",type="string",JSONPath=".spec.forProvider.role"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 
@@ -57,11 +114,45 @@ type MemberList struct {
 	Items           []Member `json:"items"`
 }
 
+// ResolveReferences of this Member.
+func (mg *Member) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ProjectID,
+		Reference:    mg.Spec.ForProvider.ProjectRef,
+		Selector:     mg.Spec.ForProvider.ProjectSelector,
+		To: reference.To{
+			Managed: &projectv1beta1.Project{},
+			List:    &projectv1beta1.ProjectList{},
+		},
+		Extract:   projectv1beta1.ProjectID(),
+		Namespace: mg.GetNamespace(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.ProjectID")
+	}
+	mg.Spec.ForProvider.ProjectID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ProjectRef = rsp.ResolvedReference
+
+	return nil
+}
+
 // GetCondition of this Member.
 func (mg *Member) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this Member.
+func (mg *Member) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Member.
+func (mg *Member) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Member.
 func (mg *Member) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
@@ -9,6 +9,8 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -39,6 +41,21 @@ func (in *Member) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberGroup) DeepCopyInto(out *MemberGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberGroup.
+func (in *MemberGroup) DeepCopy() *MemberGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemberList) DeepCopyInto(out *MemberList) {
 	*out = *in
@@ -113,6 +130,21 @@ func (in *MemberObservation) DeepCopy() *MemberObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemberParameters) DeepCopyInto(out *MemberParameters) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(v2.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectSelector != nil {
+		in, out := &in.ProjectSelector, &out.ProjectSelector
+		*out = new(v2.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemberGroup != nil {
+		in, out := &in.MemberGroup, &out.MemberGroup
+		*out = new(MemberGroup)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberParameters.
@@ -129,7 +161,12 @@ func (in *MemberParameters) DeepCopy() *MemberParameters {
 func (in *MemberSpec) DeepCopyInto(out *MemberSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
-	out.ForProvider = in.ForProvider
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberSpec.
@@ -146,6 +183,7 @@ func (in *MemberSpec) DeepCopy() *MemberSpec {
 func (in *MemberStatus) DeepCopyInto(out *MemberStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
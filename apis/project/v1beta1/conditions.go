@@ -0,0 +1,118 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeUnsupportedFeature is true when part of a Project's spec is accepted
+// but cannot be enforced by Harbor.
+const TypeUnsupportedFeature xpv1.ConditionType = "UnsupportedFeature"
+
+// Reasons for the UnsupportedFeature condition.
+const (
+	ReasonRepoExemptionsNotEnforced xpv1.ConditionReason = "RepoExemptionsNotEnforced"
+	ReasonAllFeaturesSupported      xpv1.ConditionReason = "AllFeaturesSupported"
+)
+
+// RepoExemptionsNotEnforced returns a condition indicating that Harbor does
+// not honour the Project's repository exemptions.
+func RepoExemptionsNotEnforced(n int) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUnsupportedFeature,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRepoExemptionsNotEnforced,
+		Message: fmt.Sprintf("Harbor cannot exempt repositories from the severity gate; "+
+			"the artifacts of %d repositories are labelled severity-gate-exempt but pulls are still blocked", n),
+	}
+}
+
+// AllFeaturesSupported returns a condition indicating that Harbor enforces
+// the Project's whole spec.
+func AllFeaturesSupported() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUnsupportedFeature,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAllFeaturesSupported,
+	}
+}
+
+// TypeCreationPermitted is false when Harbor refused to create a Project
+// because the provider's credentials may not create projects.
+const TypeCreationPermitted xpv1.ConditionType = "CreationPermitted"
+
+// Reasons for the CreationPermitted condition.
+const (
+	ReasonCreationForbidden xpv1.ConditionReason = "CreationForbidden"
+	ReasonCreationPermitted xpv1.ConditionReason = "CreationPermitted"
+)
+
+// CreationForbidden returns a condition indicating that Harbor refused to
+// create the Project. restriction is Harbor's project_creation_restriction
+// setting, or empty if it could not be read.
+func CreationForbidden(restriction string) xpv1.Condition {
+	if restriction == "" {
+		restriction = "unknown"
+	}
+	return xpv1.Condition{
+		Type:               TypeCreationPermitted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCreationForbidden,
+		Message:            fmt.Sprintf("provider credentials lack project creation permission; configured restriction=%s", restriction),
+	}
+}
+
+// CreationPermitted returns a condition indicating that Harbor created the
+// Project.
+func CreationPermitted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCreationPermitted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCreationPermitted,
+	}
+}
+
+// TypeQuotaExceeded is true when a Project's usage of one of its Harbor
+// quotas has reached the provider's quota threshold.
+const TypeQuotaExceeded xpv1.ConditionType = "QuotaExceeded"
+
+// Reasons for the QuotaExceeded condition.
+const (
+	ReasonQuotaThresholdReached xpv1.ConditionReason = "QuotaThresholdReached"
+	ReasonQuotaWithinThreshold  xpv1.ConditionReason = "QuotaWithinThreshold"
+)
+
+// QuotaThresholdReached returns a condition indicating that the Project has
+// used at least threshold percent of its quota of resource.
+func QuotaThresholdReached(resource string, used, hard int64, threshold int) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeQuotaExceeded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonQuotaThresholdReached,
+		Message: fmt.Sprintf("%s quota is %d%% used (%d of %d), at or above the %d%% threshold; pushes fail once it is full",
+			resource, used*100/hard, used, hard, threshold),
+	}
+}
+
+// QuotaWithinThreshold returns a condition indicating that the Project's
+// usage of every quota is below the threshold.
+func QuotaWithinThreshold() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeQuotaExceeded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonQuotaWithinThreshold,
+	}
+}
@@ -32,6 +32,16 @@ func addKnownTypes(s *runtime.Scheme) error {
 	s.AddKnownTypes(SchemeGroupVersion,
 		&Project{},
 		&ProjectList{},
+		&ProjectClass{},
+		&ProjectClassList{},
+		&ProjectAuditLog{},
+		&ProjectAuditLogList{},
+		&OIDCGroupMapping{},
+		&OIDCGroupMappingList{},
+		&ImmutableTagRule{},
+		&ImmutableTagRuleList{},
+		&ProjectSet{},
+		&ProjectSetList{},
 	)
 	return nil
 }
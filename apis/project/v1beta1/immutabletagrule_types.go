@@ -0,0 +1,196 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ImmutableTagRuleParameters define the tags an ImmutableTagRule makes
+// immutable.
+// +kubebuilder:validation:XValidation:rule="has(self.projectId) || has(self.projectRef) || has(self.projectSelector)",message="one of projectId, projectRef and projectSelector must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectRef) || !has(self.projectRef.__namespace__)",message="projectRef must name a Project in the same namespace"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectSelector) || !has(self.projectSelector.__namespace__)",message="projectSelector must select a Project in the same namespace"
+type ImmutableTagRuleParameters struct {
+	// ProjectID is the ID of the project
+	// +kubebuilder:validation:Optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// ProjectRef references a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectRef *xpv1.NamespacedReference `json:"projectRef,omitempty"`
+
+	// ProjectSelector selects a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectSelector *xpv1.NamespacedSelector `json:"projectSelector,omitempty"`
+
+	// RepositorySelectors are doublestar patterns, such as team-a/**, of the
+	// repositories this rule applies to. It applies to all repositories
+	// when there are none.
+	// +kubebuilder:validation:Optional
+	RepositorySelectors []string `json:"repositorySelectors,omitempty"`
+
+	// ExcludeRepositories applies this rule to the repositories that
+	// repositorySelectors do not match instead
+	// +kubebuilder:validation:Optional
+	ExcludeRepositories *bool `json:"excludeRepositories,omitempty"`
+
+	// TagSelectors are doublestar patterns, such as v*, of the tags this
+	// rule makes immutable. It makes all tags immutable when there are none.
+	// +kubebuilder:validation:Optional
+	TagSelectors []string `json:"tagSelectors,omitempty"`
+
+	// ExcludeTags makes the tags that tagSelectors do not match immutable
+	// instead
+	// +kubebuilder:validation:Optional
+	ExcludeTags *bool `json:"excludeTags,omitempty"`
+
+	// Enabled controls if the rule is applied. Disabling it keeps the rule
+	// in Harbor.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// ImmutableTagRuleObservation is the observed state of an immutable tag rule.
+type ImmutableTagRuleObservation struct {
+	// ID is the Harbor ID of the rule
+	ID *int64 `json:"id,omitempty"`
+
+	// Enabled is whether Harbor applies the rule
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// An ImmutableTagRuleSpec defines the desired state of an ImmutableTagRule.
+type ImmutableTagRuleSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              ImmutableTagRuleParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// An ImmutableTagRuleStatus represents the observed state of an
+// ImmutableTagRule.
+type ImmutableTagRuleStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             ImmutableTagRuleObservation `json:"atProvider,omitempty"`
+}
+
+// An ImmutableTagRule makes the tags it selects in a Harbor project
+// immutable, so they cannot be pushed over or deleted.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PROJECT",type="string",JSONPath=".spec.forProvider.projectId"
+// +kubebuilder:printcolumn:name="ENABLED",type="boolean",JSONPath=".status.atProvider.enabled"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type ImmutableTagRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImmutableTagRuleSpec   `json:"spec"`
+	Status ImmutableTagRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ImmutableTagRuleList contains a list of ImmutableTagRule.
+type ImmutableTagRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImmutableTagRule `json:"items"`
+}
+
+// ResolveReferences of this ImmutableTagRule.
+func (mg *ImmutableTagRule) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ProjectID,
+		Reference:    mg.Spec.ForProvider.ProjectRef,
+		Selector:     mg.Spec.ForProvider.ProjectSelector,
+		To: reference.To{
+			Managed: &Project{},
+			List:    &ProjectList{},
+		},
+		Extract:   ProjectID(),
+		Namespace: mg.GetNamespace(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.ProjectID")
+	}
+	mg.Spec.ForProvider.ProjectID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ProjectRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// GetCondition of this ImmutableTagRule.
+func (mg *ImmutableTagRule) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this ImmutableTagRule.
+func (mg *ImmutableTagRule) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this ImmutableTagRule.
+func (mg *ImmutableTagRule) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this ImmutableTagRule.
+func (mg *ImmutableTagRule) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this ImmutableTagRule.
+func (mg *ImmutableTagRule) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this ImmutableTagRule.
+func (mg *ImmutableTagRule) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this ImmutableTagRule.
+func (mg *ImmutableTagRule) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this ImmutableTagRule.
+func (mg *ImmutableTagRule) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this ImmutableTagRule.
+func (mg *ImmutableTagRule) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this ImmutableTagRule.
+func (mg *ImmutableTagRule) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OIDCGroupMappingSpec defines the OIDC groups added to new projects.
+type OIDCGroupMappingSpec struct {
+	// ProviderConfigName limits the mapping to projects created through the
+	// named ProviderConfig. It applies to every new project when unset.
+	// +kubebuilder:validation:Optional
+	ProviderConfigName *string `json:"providerConfigName,omitempty"`
+
+	// Groups are the OIDC groups made members of each new project
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=groupName
+	Groups []OIDCGroupRole `json:"groups"`
+}
+
+// An OIDCGroupRole is an OIDC group and the role it is given in a project.
+type OIDCGroupRole struct {
+	// GroupName is the name of the group in the OIDC groups claim
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	GroupName string `json:"groupName"`
+
+	// Role of the group in the project
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=projectAdmin;maintainer;developer;guest;limitedGuest
+	Role string `json:"role"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="PROVIDER-CONFIG",type="string",JSONPath=".spec.providerConfigName"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,harbor}
+
+// An OIDCGroupMapping gives OIDC groups a role in every project the provider
+// creates, such as making platform-admins an admin of each new project. The
+// groups are added once, when the project is created; projects that already
+// exist and later changes to the mapping are left alone.
+type OIDCGroupMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OIDCGroupMappingSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// OIDCGroupMappingList contains a list of OIDCGroupMapping
+type OIDCGroupMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OIDCGroupMapping `json:"items"`
+}
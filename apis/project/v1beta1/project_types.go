@@ -6,15 +6,35 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Metadata reconciliation policies.
+const (
+	// MetadataPolicyMerge manages only the keys listed in metadata.
+	MetadataPolicyMerge = "Merge"
+	// MetadataPolicyReplace also removes keys that are not listed.
+	MetadataPolicyReplace = "Replace"
+)
+
 // ProjectParameters defines the desired state of a Project
+// +kubebuilder:validation:XValidation:rule="(has(self.normalizeName) && self.normalizeName) || self.name.matches('^[a-z0-9]+(?:[._-][a-z0-9]+)*$')",message="name must be lower case letters and digits separated by single '.', '_' or '-' characters; set normalizeName to have it normalized instead"
 type ProjectParameters struct {
 	// Name is the name of the project in Harbor
 	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
 	Name string `json:"name"`
 
+	// NormalizeName makes a valid Harbor project name from name, for names
+	// generated from other resources such as claims: letters are lower cased,
+	// runs of other characters become a single '-', and leading and trailing
+	// separators are dropped, so "Team A/Web" becomes "team-a-web". The
+	// normalized name is the Project's external name.
+	// +kubebuilder:validation:Optional
+	NormalizeName *bool `json:"normalizeName,omitempty"`
+
 	// Public indicates if the project is publicly accessible
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=false
@@ -30,17 +50,18 @@ type ProjectParameters struct {
 	// +kubebuilder:default=false
 	EnableContentTrustCosign *bool `json:"enableContentTrustCosign,omitempty"`
 
-	// AutoScanImages automatically scans images for vulnerabilities
+	// AutoScanImages automatically scans images for vulnerabilities.
+	// When unset it is taken from the project class, if any.
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:default=false
 	AutoScanImages *bool `json:"autoScanImages,omitempty"`
 
-	// PreventVulnerableImages prevents vulnerable images from being pulled
+	// PreventVulnerableImages prevents vulnerable images from being pulled.
+	// When unset it is taken from the project class, if any.
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:default=false
 	PreventVulnerableImages *bool `json:"preventVulnerableImages,omitempty"`
 
-	// Severity represents the severity level for vulnerability prevention
+	// Severity represents the severity level for vulnerability prevention.
+	// When unset it is taken from the project class, if any.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=negligible;low;medium;high;critical
 	Severity *string `json:"severity,omitempty"`
@@ -49,17 +70,83 @@ type ProjectParameters struct {
 	// +kubebuilder:validation:Optional
 	CVEAllowlist []string `json:"cveAllowlist,omitempty"`
 
+	// RepoExemptions lists repositories, named without the project, that
+	// should be exempt from the severity gate set by preventVulnerableImages.
+	// Harbor has no per-repository exemption and still blocks pulls from
+	// them. The provider records the intent by keeping the
+	// severity-gate-exempt project label on every artifact in these
+	// repositories, and sets the UnsupportedFeature condition. Use
+	// cveAllowlist for exemptions Harbor enforces.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	RepoExemptions []string `json:"repoExemptions,omitempty"`
+
 	// RegistryID is the ID of the registry for proxy cache projects
 	// +kubebuilder:validation:Optional
 	RegistryID *int64 `json:"registryId,omitempty"`
 
-	// StorageLimit is the storage quota for the project (in bytes)
+	// StorageLimit is the storage quota for the project (in bytes). When
+	// unset it is taken from the project class, if any.
 	// +kubebuilder:validation:Optional
 	StorageLimit *int64 `json:"storageLimit,omitempty"`
 
-	// Metadata contains additional metadata for the project
+	// AutoSBOMGeneration makes Harbor generate an SBOM for every artifact
+	// pushed to the project. It requires Harbor v2.10 or later and is not
+	// sent to older versions.
+	// +kubebuilder:validation:Optional
+	AutoSBOMGeneration *bool `json:"autoSbomGeneration,omitempty"`
+
+	// Metadata contains additional metadata for the project. Harbor only
+	// accepts its own metadata keys, such as proxy_speed_kb. Where a key has
+	// a first-class field (public, enable_content_trust,
+	// enable_content_trust_cosign, auto_scan, prevent_vul, severity,
+	// auto_sbom_generation) and that field is set, the field wins and the
+	// metadata entry is ignored.
 	// +kubebuilder:validation:Optional
 	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// MetadataPolicy controls how Metadata is reconciled. Merge only manages
+	// the listed keys and leaves other keys set in Harbor alone. Replace also
+	// removes unlisted keys, except those owned by first-class fields or by
+	// other resources (retention_id, reuse_sys_cve_allowlist).
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Merge;Replace
+	// +kubebuilder:default=Merge
+	MetadataPolicy *string `json:"metadataPolicy,omitempty"`
+
+	// OwnerRef is the Harbor user who should own the project. Harbor records
+	// whoever created a project as its owner, by default the ProviderConfig's
+	// user, and its API cannot change that record. The provider instead keeps
+	// the owner a projectAdmin member, which carries the same permissions.
+	// Changing ownerRef does not remove the previous owner's membership.
+	// +kubebuilder:validation:Optional
+	OwnerRef *ProjectOwnerReference `json:"ownerRef,omitempty"`
+
+	// ProjectClassName is the ProjectClass whose defaults fill the settings
+	// this project leaves unset. When it is unset, the class is named by the
+	// harbor.crossplane.io/project-class label, if any.
+	// +kubebuilder:validation:Optional
+	ProjectClassName *string `json:"projectClassName,omitempty"`
+}
+
+// A ProjectOwnerReference names the owner of a project.
+// +kubebuilder:validation:XValidation:rule="has(self.username) != has(self.userRef)",message="exactly one of username and userRef must be set"
+type ProjectOwnerReference struct {
+	// Username is the Harbor username of the owner
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	Username *string `json:"username,omitempty"`
+
+	// UserRef names a User in the same namespace who owns the project
+	// +kubebuilder:validation:Optional
+	UserRef *UserReference `json:"userRef,omitempty"`
+}
+
+// A UserReference names a User.
+type UserReference struct {
+	// Name of the User
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
 }
 
 // ProjectObservation defines the observed state of a Project
@@ -79,25 +166,111 @@ type ProjectObservation struct {
 	// OwnerName is the name of the project owner
 	OwnerName *string `json:"ownerName,omitempty"`
 
+	// OwnerRole is the project role of the user named by ownerRef
+	OwnerRole *string `json:"ownerRole,omitempty"`
+
+	// DefaultGroupsApplied is false until the groups of the OIDCGroupMappings
+	// are members of a project the provider created. It is unset for projects
+	// the provider did not create.
+	DefaultGroupsApplied *bool `json:"defaultGroupsApplied,omitempty"`
+
 	// RepoCount is the number of repositories in the project
 	RepoCount *int64 `json:"repoCount,omitempty"`
 
-	// ChartCount is the number of charts in the project
+	// ChartCount is the number of charts in the project. It is only reported
+	// by Harbor installations that run ChartMuseum, which v2.8 removed.
 	ChartCount *int64 `json:"chartCount,omitempty"`
 
 	// CurrentStorageUsage is the current storage usage in bytes
 	CurrentStorageUsage *int64 `json:"currentStorageUsage,omitempty"`
+
+	// Metadata is the project metadata as last observed in Harbor
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// SBOM reports automatic SBOM generation for the project. It is only
+	// populated when autoSbomGeneration is set.
+	SBOM *SBOMObservation `json:"sbom,omitempty"`
+
+	// RepoExemptions reports the labelling of repoExemptions
+	RepoExemptions *RepoExemptionsObservation `json:"repoExemptions,omitempty"`
+
+	// Quota reports the project's quota limits and usage
+	Quota *QuotaObservation `json:"quota,omitempty"`
+
+	// Members counts the project's members by role
+	Members *MemberCountsObservation `json:"members,omitempty"`
+
+	// ProjectClass is the ProjectClass whose defaults were last applied
+	ProjectClass *string `json:"projectClass,omitempty"`
+}
+
+// QuotaObservation reports a project's quota, keyed by resource such as
+// storage in bytes
+type QuotaObservation struct {
+	// Hard are the limits. A limit of -1 is unlimited.
+	Hard map[string]int64 `json:"hard,omitempty"`
+
+	// Used is the usage counted against the limits
+	Used map[string]int64 `json:"used,omitempty"`
+}
+
+// MemberCountsObservation counts a project's members by role. Members added
+// through groups count once per group.
+type MemberCountsObservation struct {
+	ProjectAdmin int64 `json:"projectAdmin"`
+	Maintainer   int64 `json:"maintainer"`
+	Developer    int64 `json:"developer"`
+	Guest        int64 `json:"guest"`
+	LimitedGuest int64 `json:"limitedGuest"`
+}
+
+// RepoExemptionsObservation reports the labelling of exempt repositories
+type RepoExemptionsObservation struct {
+	// LabelID is the ID of the severity-gate-exempt project label
+	LabelID *int64 `json:"labelId,omitempty"`
+
+	// Repositories are the exempt repositories whose artifacts are labelled
+	Repositories []string `json:"repositories,omitempty"`
+
+	// MissingRepositories are exempt repositories not found in the project
+	MissingRepositories []string `json:"missingRepositories,omitempty"`
+}
+
+// SBOMObservation reports automatic SBOM generation for a project
+type SBOMObservation struct {
+	// Supported is false when the Harbor instance is older than v2.10 and
+	// cannot generate SBOMs
+	Supported bool `json:"supported"`
+
+	// AutoGeneration is the auto_sbom_generation setting observed in Harbor
+	AutoGeneration *bool `json:"autoGeneration,omitempty"`
+
+	// SampledArtifacts is the number of artifacts inspected, one per most
+	// recently updated repository
+	SampledArtifacts int64 `json:"sampledArtifacts,omitempty"`
+
+	// ArtifactsWithSBOM is how many of the sampled artifacts have an SBOM
+	ArtifactsWithSBOM int64 `json:"artifactsWithSbom,omitempty"`
+
+	// SampledAt is when the artifacts were last sampled
+	SampledAt *metav1.Time `json:"sampledAt,omitempty"`
 }
 
 // A ProjectSpec defines the desired state of a Project.
 type ProjectSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              ProjectParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A ProjectStatus represents the observed state of a Project.
 type ProjectStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             ProjectObservation `json:"atProvider,omitempty"`
 }
 
@@ -108,6 +281,8 @@ type ProjectStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="PROJECT-ID",type="string",JSONPath=".status.atProvider.id"
 // +kubebuilder:printcolumn:name="PUBLIC",type="boolean",JSONPath=".spec.forProvider.public"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 type Project struct {
@@ -130,6 +305,16 @@ func (mg *Project) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this Project.
+func (mg *Project) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Project.
+func (mg *Project) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Project.
 func (mg *Project) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
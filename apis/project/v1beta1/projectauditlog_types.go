@@ -0,0 +1,146 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectAuditLogParameters select the audit log entries a ProjectAuditLog
+// reports.
+type ProjectAuditLogParameters struct {
+	// ProjectName is the name of the Harbor project whose audit log is read
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ProjectName string `json:"projectName"`
+
+	// Window is how far back entries are reported, such as "24h"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="24h"
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// PageSize is the most entries reported, newest first
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=20
+	PageSize *int64 `json:"pageSize,omitempty"`
+}
+
+// An AuditLogEntry is one operation recorded in a project's audit log.
+type AuditLogEntry struct {
+	// Operation is what was done, such as create, delete or pull
+	Operation string `json:"operation"`
+
+	// Resource is what it was done to, such as team-a/web:1.0
+	Resource string `json:"resource"`
+
+	// ResourceType is the kind of resource, such as artifact
+	ResourceType string `json:"resourceType,omitempty"`
+
+	// Username is who did it
+	Username string `json:"username"`
+
+	// Time is when it was done
+	Time metav1.Time `json:"time"`
+}
+
+// ProjectAuditLogObservation is the recent audit log of a project.
+type ProjectAuditLogObservation struct {
+	// Entries are the entries within the window, newest first
+	Entries []AuditLogEntry `json:"entries,omitempty"`
+
+	// LatestEntryTime is the time of the newest entry
+	LatestEntryTime *metav1.Time `json:"latestEntryTime,omitempty"`
+}
+
+// A ProjectAuditLogSpec defines what a ProjectAuditLog reports.
+type ProjectAuditLogSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              ProjectAuditLogParameters `json:"forProvider"`
+}
+
+// A ProjectAuditLogStatus represents the observed audit log of a project.
+type ProjectAuditLogStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             ProjectAuditLogObservation `json:"atProvider,omitempty"`
+}
+
+// A ProjectAuditLog reports the recent audit log entries of a Harbor project
+// in its status, refreshed on every poll. It only reads from Harbor: creating
+// or deleting it changes nothing there.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PROJECT",type="string",JSONPath=".spec.forProvider.projectName"
+// +kubebuilder:printcolumn:name="LATEST-ENTRY",type="date",JSONPath=".status.atProvider.latestEntryTime"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type ProjectAuditLog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectAuditLogSpec   `json:"spec"`
+	Status ProjectAuditLogStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type ProjectAuditLogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectAuditLog `json:"items"`
+}
+
+// GetCondition of this ProjectAuditLog.
+func (mg *ProjectAuditLog) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this ProjectAuditLog.
+func (mg *ProjectAuditLog) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetManagementPolicies of this ProjectAuditLog.
+func (mg *ProjectAuditLog) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this ProjectAuditLog.
+func (mg *ProjectAuditLog) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this ProjectAuditLog.
+func (mg *ProjectAuditLog) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this ProjectAuditLog.
+func (mg *ProjectAuditLog) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this ProjectAuditLog.
+func (mg *ProjectAuditLog) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this ProjectAuditLog.
+func (mg *ProjectAuditLog) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this ProjectAuditLog.
+func (mg *ProjectAuditLog) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
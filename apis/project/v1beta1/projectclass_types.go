@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LabelProjectClass selects the ProjectClass of a Project that does not set
+// projectClassName.
+const LabelProjectClass = "harbor.crossplane.io/project-class"
+
+// ProjectClassSpec defines the defaults a ProjectClass gives its Projects.
+// A setting made by the Project itself always wins over the class.
+type ProjectClassSpec struct {
+	// StorageLimit is the default storage quota of the projects (in bytes)
+	// +kubebuilder:validation:Optional
+	StorageLimit *int64 `json:"storageLimit,omitempty"`
+
+	// Severity is the default severity level for vulnerability prevention
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=negligible;low;medium;high;critical
+	Severity *string `json:"severity,omitempty"`
+
+	// AutoScanImages is the default for scanning images on push
+	// +kubebuilder:validation:Optional
+	AutoScanImages *bool `json:"autoScanImages,omitempty"`
+
+	// PreventVulnerableImages is the default for preventing vulnerable images
+	// from being pulled
+	// +kubebuilder:validation:Optional
+	PreventVulnerableImages *bool `json:"preventVulnerableImages,omitempty"`
+
+	// CVEAllowlist is used by projects that list no CVEs of their own
+	// +kubebuilder:validation:Optional
+	CVEAllowlist []string `json:"cveAllowlist,omitempty"`
+
+	// Metadata entries are added to those of the projects, which win for
+	// keys they set themselves
+	// +kubebuilder:validation:Optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Webhooks are webhook policies kept in every project of the class. A
+	// policy removed from the class is left in the projects.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Webhooks []ProjectClassWebhook `json:"webhooks,omitempty"`
+}
+
+// A ProjectClassWebhook is a webhook policy created in each project of a
+// class.
+type ProjectClassWebhook struct {
+	// Name of the webhook policy in each project
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// URL the events are sent to
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// EventTypes sent to the URL, such as PUSH_ARTIFACT
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	EventTypes []string `json:"eventTypes"`
+
+	// SkipCertVerify disables TLS verification of the URL
+	// +kubebuilder:validation:Optional
+	SkipCertVerify bool `json:"skipCertVerify,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="SEVERITY",type="string",JSONPath=".spec.severity"
+// +kubebuilder:printcolumn:name="STORAGE-LIMIT",type="integer",JSONPath=".spec.storageLimit"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,harbor}
+
+// A ProjectClass holds defaults for the Projects that reference it, in the
+// way a StorageClass does for volumes. Projects pick up changes to their
+// class on their next reconcile.
+type ProjectClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProjectClassSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectClassList contains a list of ProjectClass
+type ProjectClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectClass `json:"items"`
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A ProjectSetRobot is a robot account of a project in a ProjectSet.
+type ProjectSetRobot struct {
+	// Name of the robot account. Harbor names it robot$<project>+<name>.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+(?:[.-][a-z0-9]+)*$`
+	// +kubebuilder:validation:MaxLength=32
+	Name string `json:"name"`
+
+	// Description of the robot account
+	// +kubebuilder:validation:Optional
+	Description *string `json:"description,omitempty"`
+
+	// ExpiresIn is the number of days until the robot account expires, or
+	// -1 for a robot account that never expires.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == -1 || self >= 1",message="expiresIn must be -1 or at least 1 day"
+	ExpiresIn *int64 `json:"expiresIn,omitempty"`
+
+	// Access lists what the robot account may do in its project, such as
+	// pull, push or delete.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Access []string `json:"access"`
+}
+
+// A ProjectSetProject is a project of a ProjectSet.
+type ProjectSetProject struct {
+	// Name of the project in Harbor. Naming a project that already exists,
+	// such as the library project of a fresh install, adopts it.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+(?:[.-][a-z0-9]+)*$`
+	// +kubebuilder:validation:MaxLength=48
+	Name string `json:"name"`
+
+	// Public overrides the set's public for this project
+	// +kubebuilder:validation:Optional
+	Public *bool `json:"public,omitempty"`
+
+	// StorageLimit overrides the set's storageLimit for this project, in
+	// bytes. -1 means unlimited.
+	// +kubebuilder:validation:Optional
+	StorageLimit *int64 `json:"storageLimit,omitempty"`
+
+	// ProjectClassName overrides the set's projectClassName for this
+	// project
+	// +kubebuilder:validation:Optional
+	ProjectClassName *string `json:"projectClassName,omitempty"`
+
+	// Robots are the robot accounts of the project. The secret of each is
+	// published to a Secret named after its Robot managed resource.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Robots []ProjectSetRobot `json:"robots,omitempty"`
+}
+
+// ProjectSetParameters define the baseline projects of a Harbor instance
+// and the defaults they share.
+type ProjectSetParameters struct {
+	// Public makes the projects publicly readable
+	// +kubebuilder:validation:Optional
+	Public *bool `json:"public,omitempty"`
+
+	// StorageLimit is the storage quota of each project, in bytes. -1 means
+	// unlimited.
+	// +kubebuilder:validation:Optional
+	StorageLimit *int64 `json:"storageLimit,omitempty"`
+
+	// ProjectClassName is the ProjectClass each project takes its defaults
+	// from
+	// +kubebuilder:validation:Optional
+	ProjectClassName *string `json:"projectClassName,omitempty"`
+
+	// Projects are the projects to create
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Projects []ProjectSetProject `json:"projects"`
+}
+
+// ProjectSetProjectObservation reports the resources created for a project.
+type ProjectSetProjectObservation struct {
+	// Name of the project
+	Name string `json:"name"`
+
+	// Project is the name of the Project managed resource
+	Project string `json:"project,omitempty"`
+
+	// Robots are the names of the Robot managed resources
+	Robots []string `json:"robots,omitempty"`
+
+	// Ready is true when the Project and all its Robots are ready
+	Ready bool `json:"ready"`
+}
+
+// ProjectSetObservation reports the projects of a ProjectSet.
+type ProjectSetObservation struct {
+	// Projects report each project, in spec order
+	Projects []ProjectSetProjectObservation `json:"projects,omitempty"`
+
+	// ReadyProjects counts the projects that are ready, as "ready/total"
+	ReadyProjects string `json:"readyProjects,omitempty"`
+}
+
+// A ProjectSetSpec defines the desired state of a ProjectSet.
+type ProjectSetSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              ProjectSetParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// A ProjectSetStatus represents the observed state of a ProjectSet.
+type ProjectSetStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             ProjectSetObservation `json:"atProvider,omitempty"`
+}
+
+// A ProjectSet bootstraps the baseline projects of a Harbor instance, with
+// their quotas and robot accounts, in one resource. For each project it
+// creates a Project, and a Robot for each of its robots, in its own
+// namespace, named after the set, the project and the robot, and keeps them
+// in line with the set. The children use the set's providerConfigRef and are
+// deleted with it.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PROJECTS",type="string",JSONPath=".status.atProvider.readyProjects"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type ProjectSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectSetSpec   `json:"spec"`
+	Status ProjectSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProjectSetList contains a list of ProjectSet
+type ProjectSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectSet `json:"items"`
+}
+
+// GetCondition of this ProjectSet.
+func (mg *ProjectSet) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this ProjectSet.
+func (mg *ProjectSet) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this ProjectSet.
+func (mg *ProjectSet) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this ProjectSet.
+func (mg *ProjectSet) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this ProjectSet.
+func (mg *ProjectSet) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this ProjectSet.
+func (mg *ProjectSet) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this ProjectSet.
+func (mg *ProjectSet) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this ProjectSet.
+func (mg *ProjectSet) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this ProjectSet.
+func (mg *ProjectSet) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this ProjectSet.
+func (mg *ProjectSet) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
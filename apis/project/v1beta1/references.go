@@ -0,0 +1,32 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+)
+
+// ProjectID extracts the Harbor ID of a referenced Project. It is empty
+// until the Project has been created in Harbor, so references to a Project
+// resolve once it is ready.
+func ProjectID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		p, ok := mg.(*Project)
+		if !ok || p.Status.AtProvider.ID == nil {
+			return ""
+		}
+		return *p.Status.AtProvider.ID
+	}
+}
+
+// GetItems of this ProjectList.
+func (l *ProjectList) GetItems() []resource.Managed {
+	items := make([]resource.Managed, len(l.Items))
+	for i := range l.Items {
+		items[i] = &l.Items[i]
+	}
+	return items
+}
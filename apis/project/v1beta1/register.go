@@ -29,3 +29,43 @@ var (
 	ProjectKindAPIVersion   = ProjectKind + "." + SchemeGroupVersion.String()
 	ProjectGroupVersionKind = SchemeGroupVersion.WithKind(ProjectKind)
 )
+
+// ProjectClass type metadata.
+var (
+	ProjectClassKind             = reflect.TypeOf(ProjectClass{}).Name()
+	ProjectClassGroupKind        = schema.GroupKind{Group: Group, Kind: ProjectClassKind}
+	ProjectClassKindAPIVersion   = ProjectClassKind + "." + SchemeGroupVersion.String()
+	ProjectClassGroupVersionKind = SchemeGroupVersion.WithKind(ProjectClassKind)
+)
+
+// ProjectAuditLog type metadata.
+var (
+	ProjectAuditLogKind             = reflect.TypeOf(ProjectAuditLog{}).Name()
+	ProjectAuditLogGroupKind        = schema.GroupKind{Group: Group, Kind: ProjectAuditLogKind}
+	ProjectAuditLogKindAPIVersion   = ProjectAuditLogKind + "." + SchemeGroupVersion.String()
+	ProjectAuditLogGroupVersionKind = SchemeGroupVersion.WithKind(ProjectAuditLogKind)
+)
+
+// OIDCGroupMapping type metadata.
+var (
+	OIDCGroupMappingKind             = reflect.TypeOf(OIDCGroupMapping{}).Name()
+	OIDCGroupMappingGroupKind        = schema.GroupKind{Group: Group, Kind: OIDCGroupMappingKind}
+	OIDCGroupMappingKindAPIVersion   = OIDCGroupMappingKind + "." + SchemeGroupVersion.String()
+	OIDCGroupMappingGroupVersionKind = SchemeGroupVersion.WithKind(OIDCGroupMappingKind)
+)
+
+// ImmutableTagRule type metadata.
+var (
+	ImmutableTagRuleKind             = reflect.TypeOf(ImmutableTagRule{}).Name()
+	ImmutableTagRuleGroupKind        = schema.GroupKind{Group: Group, Kind: ImmutableTagRuleKind}
+	ImmutableTagRuleKindAPIVersion   = ImmutableTagRuleKind + "." + SchemeGroupVersion.String()
+	ImmutableTagRuleGroupVersionKind = SchemeGroupVersion.WithKind(ImmutableTagRuleKind)
+)
+
+// ProjectSet type metadata.
+var (
+	ProjectSetKind             = reflect.TypeOf(ProjectSet{}).Name()
+	ProjectSetGroupKind        = schema.GroupKind{Group: Group, Kind: ProjectSetKind}
+	ProjectSetKindAPIVersion   = ProjectSetKind + "." + SchemeGroupVersion.String()
+	ProjectSetGroupVersionKind = SchemeGroupVersion.WithKind(ProjectSetKind)
+)
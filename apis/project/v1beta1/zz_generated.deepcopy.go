@@ -9,9 +9,315 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogEntry) DeepCopyInto(out *AuditLogEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogEntry.
+func (in *AuditLogEntry) DeepCopy() *AuditLogEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImmutableTagRule) DeepCopyInto(out *ImmutableTagRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImmutableTagRule.
+func (in *ImmutableTagRule) DeepCopy() *ImmutableTagRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ImmutableTagRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImmutableTagRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImmutableTagRuleList) DeepCopyInto(out *ImmutableTagRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImmutableTagRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImmutableTagRuleList.
+func (in *ImmutableTagRuleList) DeepCopy() *ImmutableTagRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImmutableTagRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImmutableTagRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImmutableTagRuleObservation) DeepCopyInto(out *ImmutableTagRuleObservation) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImmutableTagRuleObservation.
+func (in *ImmutableTagRuleObservation) DeepCopy() *ImmutableTagRuleObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ImmutableTagRuleObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImmutableTagRuleParameters) DeepCopyInto(out *ImmutableTagRuleParameters) {
+	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(v2.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectSelector != nil {
+		in, out := &in.ProjectSelector, &out.ProjectSelector
+		*out = new(v2.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RepositorySelectors != nil {
+		in, out := &in.RepositorySelectors, &out.RepositorySelectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeRepositories != nil {
+		in, out := &in.ExcludeRepositories, &out.ExcludeRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TagSelectors != nil {
+		in, out := &in.TagSelectors, &out.TagSelectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeTags != nil {
+		in, out := &in.ExcludeTags, &out.ExcludeTags
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImmutableTagRuleParameters.
+func (in *ImmutableTagRuleParameters) DeepCopy() *ImmutableTagRuleParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ImmutableTagRuleParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImmutableTagRuleSpec) DeepCopyInto(out *ImmutableTagRuleSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImmutableTagRuleSpec.
+func (in *ImmutableTagRuleSpec) DeepCopy() *ImmutableTagRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImmutableTagRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImmutableTagRuleStatus) DeepCopyInto(out *ImmutableTagRuleStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImmutableTagRuleStatus.
+func (in *ImmutableTagRuleStatus) DeepCopy() *ImmutableTagRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImmutableTagRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberCountsObservation) DeepCopyInto(out *MemberCountsObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberCountsObservation.
+func (in *MemberCountsObservation) DeepCopy() *MemberCountsObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberCountsObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCGroupMapping) DeepCopyInto(out *OIDCGroupMapping) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCGroupMapping.
+func (in *OIDCGroupMapping) DeepCopy() *OIDCGroupMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCGroupMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OIDCGroupMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCGroupMappingList) DeepCopyInto(out *OIDCGroupMappingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OIDCGroupMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCGroupMappingList.
+func (in *OIDCGroupMappingList) DeepCopy() *OIDCGroupMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCGroupMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OIDCGroupMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCGroupMappingSpec) DeepCopyInto(out *OIDCGroupMappingSpec) {
+	*out = *in
+	if in.ProviderConfigName != nil {
+		in, out := &in.ProviderConfigName, &out.ProviderConfigName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]OIDCGroupRole, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCGroupMappingSpec.
+func (in *OIDCGroupMappingSpec) DeepCopy() *OIDCGroupMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCGroupMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCGroupRole) DeepCopyInto(out *OIDCGroupRole) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCGroupRole.
+func (in *OIDCGroupRole) DeepCopy() *OIDCGroupRole {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCGroupRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Project) DeepCopyInto(out *Project) {
 	*out = *in
@@ -21,18 +327,571 @@ func (in *Project) DeepCopyInto(out *Project) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
-func (in *Project) DeepCopy() *Project {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Project.
+func (in *Project) DeepCopy() *Project {
+	if in == nil {
+		return nil
+	}
+	out := new(Project)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Project) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectAuditLog) DeepCopyInto(out *ProjectAuditLog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectAuditLog.
+func (in *ProjectAuditLog) DeepCopy() *ProjectAuditLog {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectAuditLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectAuditLog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectAuditLogList) DeepCopyInto(out *ProjectAuditLogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectAuditLog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectAuditLogList.
+func (in *ProjectAuditLogList) DeepCopy() *ProjectAuditLogList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectAuditLogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectAuditLogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectAuditLogObservation) DeepCopyInto(out *ProjectAuditLogObservation) {
+	*out = *in
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]AuditLogEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LatestEntryTime != nil {
+		in, out := &in.LatestEntryTime, &out.LatestEntryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectAuditLogObservation.
+func (in *ProjectAuditLogObservation) DeepCopy() *ProjectAuditLogObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectAuditLogObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectAuditLogParameters) DeepCopyInto(out *ProjectAuditLogParameters) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PageSize != nil {
+		in, out := &in.PageSize, &out.PageSize
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectAuditLogParameters.
+func (in *ProjectAuditLogParameters) DeepCopy() *ProjectAuditLogParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectAuditLogParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectAuditLogSpec) DeepCopyInto(out *ProjectAuditLogSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectAuditLogSpec.
+func (in *ProjectAuditLogSpec) DeepCopy() *ProjectAuditLogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectAuditLogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectAuditLogStatus) DeepCopyInto(out *ProjectAuditLogStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectAuditLogStatus.
+func (in *ProjectAuditLogStatus) DeepCopy() *ProjectAuditLogStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectAuditLogStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectClass) DeepCopyInto(out *ProjectClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectClass.
+func (in *ProjectClass) DeepCopy() *ProjectClass {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectClassList) DeepCopyInto(out *ProjectClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectClassList.
+func (in *ProjectClassList) DeepCopy() *ProjectClassList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectClassList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectClassSpec) DeepCopyInto(out *ProjectClassSpec) {
+	*out = *in
+	if in.StorageLimit != nil {
+		in, out := &in.StorageLimit, &out.StorageLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Severity != nil {
+		in, out := &in.Severity, &out.Severity
+		*out = new(string)
+		**out = **in
+	}
+	if in.AutoScanImages != nil {
+		in, out := &in.AutoScanImages, &out.AutoScanImages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreventVulnerableImages != nil {
+		in, out := &in.PreventVulnerableImages, &out.PreventVulnerableImages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CVEAllowlist != nil {
+		in, out := &in.CVEAllowlist, &out.CVEAllowlist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]ProjectClassWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectClassSpec.
+func (in *ProjectClassSpec) DeepCopy() *ProjectClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectClassWebhook) DeepCopyInto(out *ProjectClassWebhook) {
+	*out = *in
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectClassWebhook.
+func (in *ProjectClassWebhook) DeepCopy() *ProjectClassWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectClassWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Project, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
+func (in *ProjectList) DeepCopy() *ProjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectObservation) DeepCopyInto(out *ProjectObservation) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdateTime != nil {
+		in, out := &in.UpdateTime, &out.UpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.OwnerID != nil {
+		in, out := &in.OwnerID, &out.OwnerID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.OwnerName != nil {
+		in, out := &in.OwnerName, &out.OwnerName
+		*out = new(string)
+		**out = **in
+	}
+	if in.OwnerRole != nil {
+		in, out := &in.OwnerRole, &out.OwnerRole
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultGroupsApplied != nil {
+		in, out := &in.DefaultGroupsApplied, &out.DefaultGroupsApplied
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RepoCount != nil {
+		in, out := &in.RepoCount, &out.RepoCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ChartCount != nil {
+		in, out := &in.ChartCount, &out.ChartCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CurrentStorageUsage != nil {
+		in, out := &in.CurrentStorageUsage, &out.CurrentStorageUsage
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SBOM != nil {
+		in, out := &in.SBOM, &out.SBOM
+		*out = new(SBOMObservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RepoExemptions != nil {
+		in, out := &in.RepoExemptions, &out.RepoExemptions
+		*out = new(RepoExemptionsObservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(QuotaObservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = new(MemberCountsObservation)
+		**out = **in
+	}
+	if in.ProjectClass != nil {
+		in, out := &in.ProjectClass, &out.ProjectClass
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectObservation.
+func (in *ProjectObservation) DeepCopy() *ProjectObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectOwnerReference) DeepCopyInto(out *ProjectOwnerReference) {
+	*out = *in
+	if in.Username != nil {
+		in, out := &in.Username, &out.Username
+		*out = new(string)
+		**out = **in
+	}
+	if in.UserRef != nil {
+		in, out := &in.UserRef, &out.UserRef
+		*out = new(UserReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectOwnerReference.
+func (in *ProjectOwnerReference) DeepCopy() *ProjectOwnerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectOwnerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectParameters) DeepCopyInto(out *ProjectParameters) {
+	*out = *in
+	if in.NormalizeName != nil {
+		in, out := &in.NormalizeName, &out.NormalizeName
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Public != nil {
+		in, out := &in.Public, &out.Public
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableContentTrust != nil {
+		in, out := &in.EnableContentTrust, &out.EnableContentTrust
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableContentTrustCosign != nil {
+		in, out := &in.EnableContentTrustCosign, &out.EnableContentTrustCosign
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoScanImages != nil {
+		in, out := &in.AutoScanImages, &out.AutoScanImages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreventVulnerableImages != nil {
+		in, out := &in.PreventVulnerableImages, &out.PreventVulnerableImages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Severity != nil {
+		in, out := &in.Severity, &out.Severity
+		*out = new(string)
+		**out = **in
+	}
+	if in.CVEAllowlist != nil {
+		in, out := &in.CVEAllowlist, &out.CVEAllowlist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RepoExemptions != nil {
+		in, out := &in.RepoExemptions, &out.RepoExemptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistryID != nil {
+		in, out := &in.RegistryID, &out.RegistryID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StorageLimit != nil {
+		in, out := &in.StorageLimit, &out.StorageLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AutoSBOMGeneration != nil {
+		in, out := &in.AutoSBOMGeneration, &out.AutoSBOMGeneration
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MetadataPolicy != nil {
+		in, out := &in.MetadataPolicy, &out.MetadataPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.OwnerRef != nil {
+		in, out := &in.OwnerRef, &out.OwnerRef
+		*out = new(ProjectOwnerReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectClassName != nil {
+		in, out := &in.ProjectClassName, &out.ProjectClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectParameters.
+func (in *ProjectParameters) DeepCopy() *ProjectParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSet) DeepCopyInto(out *ProjectSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSet.
+func (in *ProjectSet) DeepCopy() *ProjectSet {
 	if in == nil {
 		return nil
 	}
-	out := new(Project)
+	out := new(ProjectSet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Project) DeepCopyObject() runtime.Object {
+func (in *ProjectSet) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -40,31 +899,31 @@ func (in *Project) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectList) DeepCopyInto(out *ProjectList) {
+func (in *ProjectSetList) DeepCopyInto(out *ProjectSetList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Project, len(*in))
+		*out = make([]ProjectSet, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectList.
-func (in *ProjectList) DeepCopy() *ProjectList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSetList.
+func (in *ProjectSetList) DeepCopy() *ProjectSetList {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectList)
+	out := new(ProjectSetList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProjectList) DeepCopyObject() runtime.Object {
+func (in *ProjectSetList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -72,121 +931,187 @@ func (in *ProjectList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectObservation) DeepCopyInto(out *ProjectObservation) {
+func (in *ProjectSetObservation) DeepCopyInto(out *ProjectSetObservation) {
 	*out = *in
-	if in.ID != nil {
-		in, out := &in.ID, &out.ID
-		*out = new(string)
-		**out = **in
-	}
-	if in.CreationTime != nil {
-		in, out := &in.CreationTime, &out.CreationTime
-		*out = (*in).DeepCopy()
-	}
-	if in.UpdateTime != nil {
-		in, out := &in.UpdateTime, &out.UpdateTime
-		*out = (*in).DeepCopy()
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]ProjectSetProjectObservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.OwnerID != nil {
-		in, out := &in.OwnerID, &out.OwnerID
-		*out = new(int64)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSetObservation.
+func (in *ProjectSetObservation) DeepCopy() *ProjectSetObservation {
+	if in == nil {
+		return nil
 	}
-	if in.OwnerName != nil {
-		in, out := &in.OwnerName, &out.OwnerName
-		*out = new(string)
+	out := new(ProjectSetObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSetParameters) DeepCopyInto(out *ProjectSetParameters) {
+	*out = *in
+	if in.Public != nil {
+		in, out := &in.Public, &out.Public
+		*out = new(bool)
 		**out = **in
 	}
-	if in.RepoCount != nil {
-		in, out := &in.RepoCount, &out.RepoCount
+	if in.StorageLimit != nil {
+		in, out := &in.StorageLimit, &out.StorageLimit
 		*out = new(int64)
 		**out = **in
 	}
-	if in.ChartCount != nil {
-		in, out := &in.ChartCount, &out.ChartCount
-		*out = new(int64)
+	if in.ProjectClassName != nil {
+		in, out := &in.ProjectClassName, &out.ProjectClassName
+		*out = new(string)
 		**out = **in
 	}
-	if in.CurrentStorageUsage != nil {
-		in, out := &in.CurrentStorageUsage, &out.CurrentStorageUsage
-		*out = new(int64)
-		**out = **in
+	if in.Projects != nil {
+		in, out := &in.Projects, &out.Projects
+		*out = make([]ProjectSetProject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectObservation.
-func (in *ProjectObservation) DeepCopy() *ProjectObservation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSetParameters.
+func (in *ProjectSetParameters) DeepCopy() *ProjectSetParameters {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectObservation)
+	out := new(ProjectSetParameters)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectParameters) DeepCopyInto(out *ProjectParameters) {
+func (in *ProjectSetProject) DeepCopyInto(out *ProjectSetProject) {
 	*out = *in
 	if in.Public != nil {
 		in, out := &in.Public, &out.Public
 		*out = new(bool)
 		**out = **in
 	}
-	if in.EnableContentTrust != nil {
-		in, out := &in.EnableContentTrust, &out.EnableContentTrust
-		*out = new(bool)
-		**out = **in
-	}
-	if in.EnableContentTrustCosign != nil {
-		in, out := &in.EnableContentTrustCosign, &out.EnableContentTrustCosign
-		*out = new(bool)
+	if in.StorageLimit != nil {
+		in, out := &in.StorageLimit, &out.StorageLimit
+		*out = new(int64)
 		**out = **in
 	}
-	if in.AutoScanImages != nil {
-		in, out := &in.AutoScanImages, &out.AutoScanImages
-		*out = new(bool)
+	if in.ProjectClassName != nil {
+		in, out := &in.ProjectClassName, &out.ProjectClassName
+		*out = new(string)
 		**out = **in
 	}
-	if in.PreventVulnerableImages != nil {
-		in, out := &in.PreventVulnerableImages, &out.PreventVulnerableImages
-		*out = new(bool)
-		**out = **in
+	if in.Robots != nil {
+		in, out := &in.Robots, &out.Robots
+		*out = make([]ProjectSetRobot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Severity != nil {
-		in, out := &in.Severity, &out.Severity
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSetProject.
+func (in *ProjectSetProject) DeepCopy() *ProjectSetProject {
+	if in == nil {
+		return nil
 	}
-	if in.CVEAllowlist != nil {
-		in, out := &in.CVEAllowlist, &out.CVEAllowlist
+	out := new(ProjectSetProject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSetProjectObservation) DeepCopyInto(out *ProjectSetProjectObservation) {
+	*out = *in
+	if in.Robots != nil {
+		in, out := &in.Robots, &out.Robots
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.RegistryID != nil {
-		in, out := &in.RegistryID, &out.RegistryID
-		*out = new(int64)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSetProjectObservation.
+func (in *ProjectSetProjectObservation) DeepCopy() *ProjectSetProjectObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSetProjectObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSetRobot) DeepCopyInto(out *ProjectSetRobot) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
 		**out = **in
 	}
-	if in.StorageLimit != nil {
-		in, out := &in.StorageLimit, &out.StorageLimit
+	if in.ExpiresIn != nil {
+		in, out := &in.ExpiresIn, &out.ExpiresIn
 		*out = new(int64)
 		**out = **in
 	}
-	if in.Metadata != nil {
-		in, out := &in.Metadata, &out.Metadata
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectParameters.
-func (in *ProjectParameters) DeepCopy() *ProjectParameters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSetRobot.
+func (in *ProjectSetRobot) DeepCopy() *ProjectSetRobot {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectParameters)
+	out := new(ProjectSetRobot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSetSpec) DeepCopyInto(out *ProjectSetSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSetSpec.
+func (in *ProjectSetSpec) DeepCopy() *ProjectSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSetStatus) DeepCopyInto(out *ProjectSetStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSetStatus.
+func (in *ProjectSetStatus) DeepCopy() *ProjectSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSetStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -196,6 +1121,11 @@ func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
@@ -212,6 +1142,7 @@ func (in *ProjectSpec) DeepCopy() *ProjectSpec {
 func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
@@ -224,3 +1155,101 @@ func (in *ProjectStatus) DeepCopy() *ProjectStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaObservation) DeepCopyInto(out *QuotaObservation) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaObservation.
+func (in *QuotaObservation) DeepCopy() *QuotaObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepoExemptionsObservation) DeepCopyInto(out *RepoExemptionsObservation) {
+	*out = *in
+	if in.LabelID != nil {
+		in, out := &in.LabelID, &out.LabelID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingRepositories != nil {
+		in, out := &in.MissingRepositories, &out.MissingRepositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepoExemptionsObservation.
+func (in *RepoExemptionsObservation) DeepCopy() *RepoExemptionsObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(RepoExemptionsObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SBOMObservation) DeepCopyInto(out *SBOMObservation) {
+	*out = *in
+	if in.AutoGeneration != nil {
+		in, out := &in.AutoGeneration, &out.AutoGeneration
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SampledAt != nil {
+		in, out := &in.SampledAt, &out.SampledAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SBOMObservation.
+func (in *SBOMObservation) DeepCopy() *SBOMObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(SBOMObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserReference) DeepCopyInto(out *UserReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserReference.
+func (in *UserReference) DeepCopy() *UserReference {
+	if in == nil {
+		return nil
+	}
+	out := new(UserReference)
+	in.DeepCopyInto(out)
+	return out
+}
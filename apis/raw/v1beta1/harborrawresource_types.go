@@ -0,0 +1,140 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// HarborRawResourceParameters are the Harbor API path a HarborRawResource
+// manages and the object it puts there.
+type HarborRawResourceParameters struct {
+	// Path is the API path of the object, relative to /api/v2.0, such as
+	// /projects/team-a/metadatas/auto_scan. Only the paths the provider
+	// allows may be used; see the provider's README.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^(/[A-Za-z0-9._~-]+)+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="path is immutable"
+	Path string `json:"path"`
+
+	// Body is the JSON object put at Path. Only the fields it sets are
+	// compared with what Harbor returns; a field Harbor leaves out counts
+	// as its zero value.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Body runtime.RawExtension `json:"body"`
+}
+
+// HarborRawResourceObservation is what Harbor returns for the path.
+type HarborRawResourceObservation struct {
+	// Response is the body of the last GET of Path.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Response *runtime.RawExtension `json:"response,omitempty"`
+
+	// Deletable is whether deleting the HarborRawResource deletes the
+	// object at Path. Settings that always exist are left as they are.
+	Deletable *bool `json:"deletable,omitempty"`
+}
+
+// A HarborRawResourceSpec defines the desired state of a HarborRawResource.
+type HarborRawResourceSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              HarborRawResourceParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// A HarborRawResourceStatus represents the observed state of a
+// HarborRawResource.
+type HarborRawResourceStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             HarborRawResourceObservation `json:"atProvider,omitempty"`
+}
+
+// A HarborRawResource puts a JSON object at a Harbor API path that the
+// provider has no kind for yet, and keeps it there. It is an escape hatch:
+// prefer a dedicated kind when one exists, since Harbor's objects are neither
+// validated nor defaulted here.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PATH",type="string",JSONPath=".spec.forProvider.path"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type HarborRawResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HarborRawResourceSpec   `json:"spec"`
+	Status HarborRawResourceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type HarborRawResourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborRawResource `json:"items"`
+}
+
+// GetCondition of this HarborRawResource.
+func (mg *HarborRawResource) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this HarborRawResource.
+func (mg *HarborRawResource) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this HarborRawResource.
+func (mg *HarborRawResource) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this HarborRawResource.
+func (mg *HarborRawResource) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this HarborRawResource.
+func (mg *HarborRawResource) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this HarborRawResource.
+func (mg *HarborRawResource) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this HarborRawResource.
+func (mg *HarborRawResource) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this HarborRawResource.
+func (mg *HarborRawResource) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this HarborRawResource.
+func (mg *HarborRawResource) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this HarborRawResource.
+func (mg *HarborRawResource) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
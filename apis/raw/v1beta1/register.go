@@ -0,0 +1,19 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HarborRawResource type metadata.
+var (
+	HarborRawResourceKind             = reflect.TypeOf(HarborRawResource{}).Name()
+	HarborRawResourceGroupKind        = schema.GroupKind{Group: Group, Kind: HarborRawResourceKind}
+	HarborRawResourceKindAPIVersion   = HarborRawResourceKind + "." + SchemeGroupVersion.String()
+	HarborRawResourceGroupVersionKind = SchemeGroupVersion.WithKind(HarborRawResourceKind)
+)
@@ -0,0 +1,154 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"github.com/rossigee/provider-harbor/apis/common"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRawResource) DeepCopyInto(out *HarborRawResource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRawResource.
+func (in *HarborRawResource) DeepCopy() *HarborRawResource {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRawResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRawResource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRawResourceList) DeepCopyInto(out *HarborRawResourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborRawResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRawResourceList.
+func (in *HarborRawResourceList) DeepCopy() *HarborRawResourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRawResourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRawResourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRawResourceObservation) DeepCopyInto(out *HarborRawResourceObservation) {
+	*out = *in
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deletable != nil {
+		in, out := &in.Deletable, &out.Deletable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRawResourceObservation.
+func (in *HarborRawResourceObservation) DeepCopy() *HarborRawResourceObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRawResourceObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRawResourceParameters) DeepCopyInto(out *HarborRawResourceParameters) {
+	*out = *in
+	in.Body.DeepCopyInto(&out.Body)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRawResourceParameters.
+func (in *HarborRawResourceParameters) DeepCopy() *HarborRawResourceParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRawResourceParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRawResourceSpec) DeepCopyInto(out *HarborRawResourceSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRawResourceSpec.
+func (in *HarborRawResourceSpec) DeepCopy() *HarborRawResourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRawResourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRawResourceStatus) DeepCopyInto(out *HarborRawResourceStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRawResourceStatus.
+func (in *HarborRawResourceStatus) DeepCopy() *HarborRawResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRawResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -9,8 +9,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	artifactv1beta1 "github.com/rossigee/provider-harbor/apis/artifact/v1beta1"
+	configv1beta1 "github.com/rossigee/provider-harbor/apis/config/v1beta1"
 	memberv1beta1 "github.com/rossigee/provider-harbor/apis/member/v1beta1"
 	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	rawv1beta1 "github.com/rossigee/provider-harbor/apis/raw/v1beta1"
 	registryv1beta1 "github.com/rossigee/provider-harbor/apis/registry/v1beta1"
 	replicationv1beta1 "github.com/rossigee/provider-harbor/apis/replication/v1beta1"
 	repositoryv1beta1 "github.com/rossigee/provider-harbor/apis/repository/v1beta1"
@@ -51,6 +53,12 @@ func init() {
 		replicationv1beta1.SchemeBuilder.AddToScheme,
 		retentionv1beta1.SchemeBuilder.AddToScheme,
 
+		// System configuration
+		configv1beta1.SchemeBuilder.AddToScheme,
+
+		// Escape hatch for Harbor APIs without a kind
+		rawv1beta1.SchemeBuilder.AddToScheme,
+
 		// Provider config APIs
 		v1beta1.SchemeBuilder.AddToScheme,
 	)
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package apis
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	artifactv1beta1 "github.com/rossigee/provider-harbor/apis/artifact/v1beta1"
+	"github.com/rossigee/provider-harbor/apis/common"
+	configv1beta1 "github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	memberv1beta1 "github.com/rossigee/provider-harbor/apis/member/v1beta1"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	rawv1beta1 "github.com/rossigee/provider-harbor/apis/raw/v1beta1"
+	registryv1beta1 "github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	replicationv1beta1 "github.com/rossigee/provider-harbor/apis/replication/v1beta1"
+	repositoryv1beta1 "github.com/rossigee/provider-harbor/apis/repository/v1beta1"
+	retentionv1beta1 "github.com/rossigee/provider-harbor/apis/retention/v1beta1"
+	robotv1beta1 "github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	scanv1beta1 "github.com/rossigee/provider-harbor/apis/scan/v1beta1"
+	scannerv1beta1 "github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	userv1beta1 "github.com/rossigee/provider-harbor/apis/user/v1beta1"
+	usergroupv1beta1 "github.com/rossigee/provider-harbor/apis/usergroup/v1beta1"
+	webhookv1beta1 "github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
+)
+
+// managedKinds lists every managed resource kind served by the provider.
+var managedKinds = []struct {
+	gvk  schema.GroupVersionKind
+	obj  resource.Managed
+	list runtime.Object
+}{
+	{artifactv1beta1.ArtifactGroupVersionKind, &artifactv1beta1.Artifact{}, &artifactv1beta1.ArtifactList{}},
+	{artifactv1beta1.ArtifactLabelGroupVersionKind, &artifactv1beta1.ArtifactLabel{}, &artifactv1beta1.ArtifactLabelList{}},
+	{configv1beta1.ConfigSystemGroupVersionKind, &configv1beta1.ConfigSystem{}, &configv1beta1.ConfigSystemList{}},
+	{memberv1beta1.MemberGroupVersionKind, &memberv1beta1.Member{}, &memberv1beta1.MemberList{}},
+	{projectv1beta1.ProjectGroupVersionKind, &projectv1beta1.Project{}, &projectv1beta1.ProjectList{}},
+	{projectv1beta1.ProjectAuditLogGroupVersionKind, &projectv1beta1.ProjectAuditLog{}, &projectv1beta1.ProjectAuditLogList{}},
+	{rawv1beta1.HarborRawResourceGroupVersionKind, &rawv1beta1.HarborRawResource{}, &rawv1beta1.HarborRawResourceList{}},
+	{registryv1beta1.RegistryGroupVersionKind, &registryv1beta1.Registry{}, &registryv1beta1.RegistryList{}},
+	{registryv1beta1.RegistryMirrorSetGroupVersionKind, &registryv1beta1.RegistryMirrorSet{}, &registryv1beta1.RegistryMirrorSetList{}},
+	{replicationv1beta1.ReplicationGroupVersionKind, &replicationv1beta1.Replication{}, &replicationv1beta1.ReplicationList{}},
+	{repositoryv1beta1.RepositoryGroupVersionKind, &repositoryv1beta1.Repository{}, &repositoryv1beta1.RepositoryList{}},
+	{retentionv1beta1.RetentionGroupVersionKind, &retentionv1beta1.Retention{}, &retentionv1beta1.RetentionList{}},
+	{robotv1beta1.RobotGroupVersionKind, &robotv1beta1.Robot{}, &robotv1beta1.RobotList{}},
+	{scanv1beta1.ScanGroupVersionKind, &scanv1beta1.Scan{}, &scanv1beta1.ScanList{}},
+	{scannerv1beta1.ProjectScannerGroupVersionKind, &scannerv1beta1.ProjectScanner{}, &scannerv1beta1.ProjectScannerList{}},
+	{scannerv1beta1.ScannerRegistrationGroupVersionKind, &scannerv1beta1.ScannerRegistration{}, &scannerv1beta1.ScannerRegistrationList{}},
+	{userv1beta1.UserGroupVersionKind, &userv1beta1.User{}, &userv1beta1.UserList{}},
+	{usergroupv1beta1.UserGroupGroupVersionKind, &usergroupv1beta1.UserGroup{}, &usergroupv1beta1.UserGroupList{}},
+	{webhookv1beta1.WebhookGroupVersionKind, &webhookv1beta1.Webhook{}, &webhookv1beta1.WebhookList{}},
+}
+
+func TestManagedKindsRegistered(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, k := range managedKinds {
+		t.Run(k.gvk.Kind, func(t *testing.T) {
+			gvks, _, err := s.ObjectKinds(k.obj)
+			if err != nil {
+				t.Fatalf("%s is not registered: %v", k.gvk.Kind, err)
+			}
+			if len(gvks) != 1 || gvks[0] != k.gvk {
+				t.Errorf("%s registered as %v, want %v", k.gvk.Kind, gvks, k.gvk)
+			}
+			listGVK := k.gvk.GroupVersion().WithKind(k.gvk.Kind + "List")
+			if gvks, _, err := s.ObjectKinds(k.list); err != nil || len(gvks) != 1 || gvks[0] != listGVK {
+				t.Errorf("%sList registered as %v (%v), want %v", k.gvk.Kind, gvks, err, listGVK)
+			}
+		})
+	}
+}
+
+func TestManagedKindsDeepCopy(t *testing.T) {
+	for _, k := range managedKinds {
+		t.Run(k.gvk.Kind, func(t *testing.T) {
+			k.obj.SetName("example")
+			k.obj.SetManagementPolicies(xpv1.ManagementPolicies{xpv1.ManagementActionObserve})
+			cp, ok := k.obj.DeepCopyObject().(resource.Managed)
+			if !ok {
+				t.Fatalf("DeepCopyObject() of %s is not a resource.Managed", k.gvk.Kind)
+			}
+			if cp == k.obj || cp.GetName() != "example" {
+				t.Errorf("DeepCopyObject() of %s did not return an equal copy", k.gvk.Kind)
+			}
+			if p := cp.GetManagementPolicies(); len(p) != 1 || p[0] != xpv1.ManagementActionObserve {
+				t.Errorf("management policies of %s not preserved: %v", k.gvk.Kind, p)
+			}
+			if _, ok := k.obj.(interface{ GetSyncStatus() *common.SyncStatus }); !ok {
+				t.Errorf("%s does not record its sync status", k.gvk.Kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeEndpointPermitted is false when a Registry points at an endpoint that
+// the policy of its ProviderConfig does not allow.
+const TypeEndpointPermitted xpv1.ConditionType = "EndpointPermitted"
+
+// Reasons for the EndpointPermitted condition.
+const (
+	ReasonEndpointNotAllowed xpv1.ConditionReason = "EndpointNotAllowed"
+	ReasonEndpointAllowed    xpv1.ConditionReason = "EndpointAllowed"
+)
+
+// EndpointNotAllowed returns a condition indicating that the Registry's URL
+// matches none of the allowed registry URL patterns of its ProviderConfig.
+func EndpointNotAllowed(url, providerConfig string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeEndpointPermitted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonEndpointNotAllowed,
+		Message:            fmt.Sprintf("%s matches none of the allowedRegistryURLPatterns of ProviderConfig %s", url, providerConfig),
+	}
+}
+
+// EndpointAllowed returns a condition indicating that the Registry's URL is
+// allowed.
+func EndpointAllowed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeEndpointPermitted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonEndpointAllowed,
+	}
+}
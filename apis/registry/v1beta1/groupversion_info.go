@@ -29,5 +29,11 @@ var (
 )
 
 func addKnownTypes(s *runtime.Scheme) error {
+	s.AddKnownTypes(SchemeGroupVersion,
+		&Registry{},
+		&RegistryList{},
+		&RegistryMirrorSet{},
+		&RegistryMirrorSetList{},
+	)
 	return nil
 }
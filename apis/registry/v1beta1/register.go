@@ -37,3 +37,11 @@ var (
 	RegistryCredentialKindAPIVersion   = RegistryCredentialKind + "." + SchemeGroupVersion.String()
 	RegistryCredentialGroupVersionKind = SchemeGroupVersion.WithKind(RegistryCredentialKind)
 )
+
+// RegistryMirrorSet type metadata.
+var (
+	RegistryMirrorSetKind             = reflect.TypeOf(RegistryMirrorSet{}).Name()
+	RegistryMirrorSetGroupKind        = schema.GroupKind{Group: Group, Kind: RegistryMirrorSetKind}
+	RegistryMirrorSetKindAPIVersion   = RegistryMirrorSetKind + "." + SchemeGroupVersion.String()
+	RegistryMirrorSetGroupVersionKind = SchemeGroupVersion.WithKind(RegistryMirrorSetKind)
+)
@@ -6,6 +6,7 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -20,7 +21,10 @@ type RegistryCredential struct {
 	// +kubebuilder:validation:Optional
 	AccessKey *string `json:"accessKey,omitempty"`
 
-	// AccessSecret contains the secret reference for registry access
+	// AccessSecretRef selects the key of a Secret holding the access secret
+	// for the registry. The Secret may be in another namespace, such as one
+	// shared by several registries; without a namespace it is looked up in
+	// the Registry's namespace.
 	// +kubebuilder:validation:Optional
 	AccessSecretRef *xpv1.SecretKeySelector `json:"accessSecretRef,omitempty"`
 }
@@ -65,19 +69,46 @@ type RegistryObservation struct {
 	// UpdateTime is when the registry was last updated
 	UpdateTime *metav1.Time `json:"updateTime,omitempty"`
 
-	// Status indicates the health status of the registry
+	// Status is Harbor's last health check of the registry: healthy or
+	// unhealthy
 	Status *string `json:"status,omitempty"`
+
+	// LastPing is the result of the provider last asking Harbor to ping the
+	// registry with its stored credential
+	LastPing *RegistryPingResult `json:"lastPing,omitempty"`
+
+	// CredentialHash is a salted hash of the access secret last saved in
+	// Harbor, so that a changed secret is saved again
+	CredentialHash *string `json:"credentialHash,omitempty"`
+}
+
+// RegistryPingResult is the result of a registry ping.
+type RegistryPingResult struct {
+	// Time is when the registry was pinged
+	Time metav1.Time `json:"time"`
+
+	// Succeeded is whether Harbor reached the registry
+	Succeeded bool `json:"succeeded"`
+
+	// Message is why the ping failed
+	Message string `json:"message,omitempty"`
 }
 
 // A RegistrySpec defines the desired state of a Registry.
 type RegistrySpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              RegistryParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A RegistryStatus represents the observed state of a Registry.
 type RegistryStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             RegistryObservation `json:"atProvider,omitempty"`
 }
 
@@ -88,6 +119,9 @@ type RegistryStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="REGISTRY-ID",type="string",JSONPath=".status.atProvider.id"
 // +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.forProvider.type"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 type Registry struct {
@@ -110,6 +144,16 @@ func (mg *Registry) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this Registry.
+func (mg *Registry) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Registry.
+func (mg *Registry) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Registry.
 func (mg *Registry) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
@@ -0,0 +1,205 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A RegistryMirror is an upstream registry to proxy.
+// +kubebuilder:validation:XValidation:rule="has(self.url) || self.type in ['docker-hub', 'quay', 'google-gcr']",message="url is required unless type is docker-hub, quay or google-gcr"
+type RegistryMirror struct {
+	// Name identifies the mirror. The Harbor registry endpoint and the proxy
+	// cache project are both named projectPrefix followed by Name, so images
+	// are pulled as <harbor>/<projectPrefix><name>/<image>.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`
+	// +kubebuilder:validation:MaxLength=48
+	Name string `json:"name"`
+
+	// Type is the type of the upstream registry
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=harbor;docker-hub;docker-registry;helm-hub;aws-ecr;azure-acr;google-gcr;gitlab;quay
+	Type string `json:"type"`
+
+	// URL of the upstream registry. It defaults to https://hub.docker.com,
+	// https://quay.io and https://gcr.io for docker-hub, quay and
+	// google-gcr.
+	// +kubebuilder:validation:Optional
+	URL *string `json:"url,omitempty"`
+
+	// Insecure skips TLS verification of the upstream registry
+	// +kubebuilder:validation:Optional
+	Insecure *bool `json:"insecure,omitempty"`
+
+	// Credential authenticates to the upstream registry, to raise its pull
+	// rate limit or reach private images
+	// +kubebuilder:validation:Optional
+	Credential *RegistryCredential `json:"credential,omitempty"`
+
+	// StorageLimit overrides the set's storageLimit for this mirror's
+	// project, in bytes
+	// +kubebuilder:validation:Optional
+	StorageLimit *int64 `json:"storageLimit,omitempty"`
+}
+
+// RegistryMirrorSetParameters define the upstream registries to proxy and
+// how their proxy cache projects are set up.
+type RegistryMirrorSetParameters struct {
+	// ProjectPrefix is prepended to the name of every registry endpoint and
+	// proxy cache project, such as "proxy-"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]+(?:[._-][a-z0-9]+)*[._-]?)?$`
+	// +kubebuilder:validation:MaxLength=16
+	ProjectPrefix *string `json:"projectPrefix,omitempty"`
+
+	// Public makes the proxy cache projects publicly readable
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	Public *bool `json:"public,omitempty"`
+
+	// StorageLimit is the storage quota of each proxy cache project, in
+	// bytes. -1 means unlimited.
+	// +kubebuilder:validation:Optional
+	StorageLimit *int64 `json:"storageLimit,omitempty"`
+
+	// Mirrors are the upstream registries to proxy
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Mirrors []RegistryMirror `json:"mirrors"`
+}
+
+// RegistryMirrorObservation reports the resources created for a mirror.
+type RegistryMirrorObservation struct {
+	// Name of the mirror
+	Name string `json:"name"`
+
+	// Registry is the name of the Registry managed resource
+	Registry string `json:"registry,omitempty"`
+
+	// Project is the name of the Project managed resource
+	Project string `json:"project,omitempty"`
+
+	// RegistryID is the ID of the registry endpoint in Harbor. The project
+	// is created once it is known.
+	RegistryID *int64 `json:"registryId,omitempty"`
+
+	// Ready is true when both the Registry and the Project are ready
+	Ready bool `json:"ready"`
+}
+
+// RegistryMirrorSetObservation reports the mirrors of a RegistryMirrorSet.
+type RegistryMirrorSetObservation struct {
+	// Mirrors report each mirror, in spec order
+	Mirrors []RegistryMirrorObservation `json:"mirrors,omitempty"`
+
+	// ReadyMirrors counts the mirrors that are ready, as "ready/total"
+	ReadyMirrors string `json:"readyMirrors,omitempty"`
+}
+
+// A RegistryMirrorSetSpec defines the desired state of a RegistryMirrorSet.
+type RegistryMirrorSetSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              RegistryMirrorSetParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// A RegistryMirrorSetStatus represents the observed state of a
+// RegistryMirrorSet.
+type RegistryMirrorSetStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             RegistryMirrorSetObservation `json:"atProvider,omitempty"`
+}
+
+// A RegistryMirrorSet sets up Harbor as a pull-through cache for a list of
+// upstream registries. For each mirror it creates a Registry and a proxy
+// cache Project in its own namespace, named after the set and the mirror,
+// and keeps them in line with the set. The children use the set's
+// providerConfigRef and are deleted with it.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="MIRRORS",type="string",JSONPath=".status.atProvider.readyMirrors"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type RegistryMirrorSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RegistryMirrorSetSpec   `json:"spec"`
+	Status RegistryMirrorSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RegistryMirrorSetList contains a list of RegistryMirrorSet
+type RegistryMirrorSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RegistryMirrorSet `json:"items"`
+}
+
+// GetCondition of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this RegistryMirrorSet.
+func (mg *RegistryMirrorSet) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
@@ -6,6 +6,7 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -15,16 +16,24 @@ type ReplicationFilter struct {
 	// +kubebuilder:validation:Enum=repository;tag;label;resource
 	Type string `json:"type"`
 
-	// Value is the filter value
+	// Value is the filter value. A label filter takes a comma separated list
+	// of labels.
 	// +kubebuilder:validation:Required
 	Value string `json:"value"`
+
+	// Decoration is whether tag and label filters select the matching
+	// artifacts or every other artifact. Defaults to matches.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=matches;excludes
+	Decoration *string `json:"decoration,omitempty"`
 }
 
 // ReplicationDestination defines the destination registry
 type ReplicationDestination struct {
-	// Name is the destination registry name
-	// +kubebuilder:validation:Required
-	Name string `json:"name"`
+	// Name is the destination registry name. It must be empty when the
+	// policy pulls from a sourceRegistry into this Harbor.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
 
 	// Namespace is the namespace in destination registry
 	// +kubebuilder:validation:Optional
@@ -36,6 +45,8 @@ type ReplicationDestination struct {
 }
 
 // ReplicationParameters defines the desired state of a Replication policy
+// +kubebuilder:validation:XValidation:rule="has(self.sourceRegistry) != (has(self.destinationReg.name) && size(self.destinationReg.name) > 0)",message="exactly one of sourceRegistry and destinationReg.name must be set"
+// +kubebuilder:validation:XValidation:rule="self.trigger != 'scheduled' || has(self.cronSchedule)",message="cronSchedule is required for a scheduled trigger"
 type ReplicationParameters struct {
 	// Name is the name of the replication policy
 	// +kubebuilder:validation:Required
@@ -45,7 +56,8 @@ type ReplicationParameters struct {
 	// +kubebuilder:validation:Optional
 	Description *string `json:"description,omitempty"`
 
-	// SourceRegistry is the source registry name (optional for local registry)
+	// SourceRegistry is the name of the registry the policy pulls from into
+	// this Harbor. Leave it unset to push to destinationReg instead.
 	// +kubebuilder:validation:Optional
 	SourceRegistry *string `json:"sourceRegistry,omitempty"`
 
@@ -62,10 +74,20 @@ type ReplicationParameters struct {
 	// +kubebuilder:validation:Enum=manual;scheduled;event_based
 	Trigger string `json:"trigger"`
 
+	// CronSchedule is when a scheduled policy runs, as a Harbor cron
+	// expression with seconds, such as "0 0 2 * * *".
+	// +kubebuilder:validation:Optional
+	CronSchedule *string `json:"cronSchedule,omitempty"`
+
 	// DeleteSourceTag removes source image tags after replication
 	// +kubebuilder:validation:Optional
 	DeleteSourceTag *bool `json:"deleteSourceTag,omitempty"`
 
+	// ReplicateDeletion deletes artifacts at the destination when they are
+	// deleted at the source.
+	// +kubebuilder:validation:Optional
+	ReplicateDeletion *bool `json:"replicateDeletion,omitempty"`
+
 	// Override overwrites images in destination
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=true
@@ -93,17 +115,28 @@ type ReplicationObservation struct {
 
 	// LastExecutionStatus is the status of the last execution
 	LastExecutionStatus *string `json:"lastExecutionStatus,omitempty"`
+
+	// RecentExecutions are the most recent executions of the policy, newest
+	// first, refreshed on each observation
+	// +listType=atomic
+	RecentExecutions []common.ExecutionObservation `json:"recentExecutions,omitempty"`
 }
 
 // A ReplicationSpec defines the desired state of a Replication policy.
 type ReplicationSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              ReplicationParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A ReplicationStatus represents the observed state of a Replication policy.
 type ReplicationStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             ReplicationObservation `json:"atProvider,omitempty"`
 }
 
@@ -115,6 +148,8 @@ type ReplicationStatus struct {
 // +kubebuilder:printcolumn:name="POLICY",type="string",JSONPath=".spec.forProvider.name"
 // +kubebuilder:printcolumn:name="DESTINATION",type="string",JSONPath=".spec.forProvider.destinationReg.name"
 // +kubebuilder:printcolumn:name="TRIGGER",type="string",JSONPath=".spec.forProvider.trigger"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 
@@ -141,6 +176,16 @@ func (mg *Replication) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this Replication.
+func (mg *Replication) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Replication.
+func (mg *Replication) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Replication.
 func (mg *Replication) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
@@ -9,6 +9,7 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/rossigee/provider-harbor/apis/common"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -57,6 +58,11 @@ func (in *ReplicationDestination) DeepCopy() *ReplicationDestination {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicationFilter) DeepCopyInto(out *ReplicationFilter) {
 	*out = *in
+	if in.Decoration != nil {
+		in, out := &in.Decoration, &out.Decoration
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationFilter.
@@ -127,6 +133,13 @@ func (in *ReplicationObservation) DeepCopyInto(out *ReplicationObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RecentExecutions != nil {
+		in, out := &in.RecentExecutions, &out.RecentExecutions
+		*out = make([]common.ExecutionObservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationObservation.
@@ -156,13 +169,25 @@ func (in *ReplicationParameters) DeepCopyInto(out *ReplicationParameters) {
 	if in.Filters != nil {
 		in, out := &in.Filters, &out.Filters
 		*out = make([]ReplicationFilter, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CronSchedule != nil {
+		in, out := &in.CronSchedule, &out.CronSchedule
+		*out = new(string)
+		**out = **in
 	}
 	if in.DeleteSourceTag != nil {
 		in, out := &in.DeleteSourceTag, &out.DeleteSourceTag
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ReplicateDeletion != nil {
+		in, out := &in.ReplicateDeletion, &out.ReplicateDeletion
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Override != nil {
 		in, out := &in.Override, &out.Override
 		*out = new(bool)
@@ -190,6 +215,11 @@ func (in *ReplicationSpec) DeepCopyInto(out *ReplicationSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSpec.
@@ -206,6 +236,7 @@ func (in *ReplicationSpec) DeepCopy() *ReplicationSpec {
 func (in *ReplicationStatus) DeepCopyInto(out *ReplicationStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
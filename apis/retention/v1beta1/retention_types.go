@@ -5,38 +5,179 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"context"
+
+	"fmt"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// RetentionRule defines a retention rule
+// TypeProjectLinked indicates whether the project's retention_id metadata
+// points at this retention policy.
+const TypeProjectLinked xpv1.ConditionType = "ProjectLinked"
+
+// Reasons a retention policy is or is not linked to its project.
+const (
+	ReasonLinked         xpv1.ConditionReason = "Linked"
+	ReasonLinkageMissing xpv1.ConditionReason = "LinkageMissing"
+	ReasonLinkageBroken  xpv1.ConditionReason = "LinkageBroken"
+)
+
+// ProjectLinked returns a condition indicating the project metadata
+// retention_id references this policy.
+func ProjectLinked() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeProjectLinked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLinked,
+	}
+}
+
+// ProjectLinkageMissing returns a condition indicating the project has no
+// retention_id metadata, or it references a different existing policy.
+func ProjectLinkageMissing() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeProjectLinked,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLinkageMissing,
+		Message:            "project metadata retention_id does not reference this policy",
+	}
+}
+
+// ProjectLinkageBroken returns a condition indicating the project's
+// retention_id metadata references a policy that does not exist.
+func ProjectLinkageBroken(retentionID string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeProjectLinked,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLinkageBroken,
+		Message:            fmt.Sprintf("project metadata retention_id %q references a nonexistent retention policy", retentionID),
+	}
+}
+
+// TypePermitted indicates whether the ProviderConfig's Harbor account may
+// manage this retention policy in its project.
+const TypePermitted xpv1.ConditionType = "Permitted"
+
+// Reasons the ProviderConfig's Harbor account may or may not manage a
+// retention policy.
+const (
+	ReasonPermissionGranted xpv1.ConditionReason = "PermissionGranted"
+	ReasonPermissionDenied  xpv1.ConditionReason = "PermissionDenied"
+)
+
+// Permitted returns a condition indicating the Harbor account has the
+// project permissions the last change needed.
+func Permitted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePermitted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPermissionGranted,
+	}
+}
+
+// PermissionDenied returns a condition indicating the Harbor account lacks
+// the given project permissions, as resource:action pairs, so the change was
+// not attempted.
+func PermissionDenied(missing []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePermitted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPermissionDenied,
+		Message:            fmt.Sprintf("the Harbor account lacks project permissions %s", strings.Join(missing, ", ")),
+	}
+}
+
+// RetentionRule defines a retention rule. Each rule retains the artifacts
+// it selects; Harbor deletes the artifacts no rule retains.
+// +kubebuilder:validation:XValidation:rule="!(self.ruleType in ['latestPushedK', 'latestPulledN']) || has(self.count) || has(self.parameters)",message="latestPushedK and latestPulledN rules need count"
+// +kubebuilder:validation:XValidation:rule="!(self.ruleType in ['daysSinceLastPush', 'daysSinceLastPull']) || has(self.days) || has(self.parameters)",message="daysSinceLastPush and daysSinceLastPull rules need days"
 type RetentionRule struct {
 	// RuleType: always, latestPushedK, latestPulledN
 	// +kubebuilder:validation:Enum=always;latestPushedK;latestPulledN;daysSinceLastPull;daysSinceLastPush
 	RuleType string `json:"ruleType"`
 
-	// TagSelectors define which tags to apply this rule to
+	// Count is how many of the most recently pushed or pulled artifacts a
+	// latestPushedK or latestPulledN rule retains
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Count *int64 `json:"count,omitempty"`
+
+	// Days is for how many days after their last push or pull a
+	// daysSinceLastPush or daysSinceLastPull rule retains artifacts
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Days *int64 `json:"days,omitempty"`
+
+	// RepositorySelectors are doublestar patterns, such as team-a/**, of the
+	// repositories this rule applies to. It applies to all repositories
+	// when there are none.
+	// +kubebuilder:validation:Optional
+	RepositorySelectors []string `json:"repositorySelectors,omitempty"`
+
+	// ExcludeRepositories applies this rule to the repositories that
+	// repositorySelectors do not match instead
+	// +kubebuilder:validation:Optional
+	ExcludeRepositories *bool `json:"excludeRepositories,omitempty"`
+
+	// TagSelectors are doublestar patterns of the tags this rule applies to.
+	// It applies to all tags when there are none.
 	// +kubebuilder:validation:Optional
 	TagSelectors []string `json:"tagSelectors,omitempty"`
 
-	// Parameters are rule-specific parameters (e.g., {"k": "10"})
+	// ExcludeTags applies this rule to the tags that tagSelectors do not
+	// match instead
+	// +kubebuilder:validation:Optional
+	ExcludeTags *bool `json:"excludeTags,omitempty"`
+
+	// Parameters are rule-specific parameters (e.g., {"k": "10"}). Count
+	// and days take precedence over them.
 	// +kubebuilder:validation:Optional
 	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 // RetentionParameters defines the desired state of a Retention policy
+// +kubebuilder:validation:XValidation:rule="has(self.projectId) || has(self.projectRef) || has(self.projectSelector)",message="one of projectId, projectRef and projectSelector must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectRef) || !has(self.projectRef.__namespace__)",message="projectRef must name a Project in the same namespace"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectSelector) || !has(self.projectSelector.__namespace__)",message="projectSelector must select a Project in the same namespace"
+// +kubebuilder:validation:XValidation:rule="self.trigger != 'scheduled' || has(self.schedule)",message="a scheduled trigger needs schedule"
 type RetentionParameters struct {
 	// ProjectID is the ID of the project
-	// +kubebuilder:validation:Required
-	ProjectID string `json:"projectId"`
+	// +kubebuilder:validation:Optional
+	ProjectID string `json:"projectId,omitempty"`
 
-	// Description of the retention policy
+	// ProjectRef references a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectRef *xpv1.NamespacedReference `json:"projectRef,omitempty"`
+
+	// ProjectSelector selects a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectSelector *xpv1.NamespacedSelector `json:"projectSelector,omitempty"`
+
+	// Description of the retention policy. Harbor does not store it.
 	// +kubebuilder:validation:Optional
 	Description *string `json:"description,omitempty"`
 
-	// Rules define the cleanup rules
+	// Rules define the cleanup rules. Their order does not matter.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=15
+	// +listType=atomic
 	Rules []RetentionRule `json:"rules"`
 
 	// Trigger: manual, scheduled
@@ -44,7 +185,13 @@ type RetentionParameters struct {
 	// +kubebuilder:validation:Enum=manual;scheduled
 	Trigger string `json:"trigger"`
 
-	// Enabled controls if the policy is active
+	// Schedule is the six field cron expression, starting with seconds, a
+	// scheduled trigger runs the policy on
+	// +kubebuilder:validation:Optional
+	Schedule *string `json:"schedule,omitempty"`
+
+	// Enabled controls if the policy is active. Harbor has no such switch
+	// for a whole policy, so disabling it disables each of its rules.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=true
 	Enabled *bool `json:"enabled,omitempty"`
@@ -66,17 +213,28 @@ type RetentionObservation struct {
 
 	// LastExecutionTime of the retention cleanup
 	LastExecutionTime *metav1.Time `json:"lastExecutionTime,omitempty"`
+
+	// RecentExecutions are the most recent executions of the policy, newest
+	// first, refreshed on each observation
+	// +listType=atomic
+	RecentExecutions []common.ExecutionObservation `json:"recentExecutions,omitempty"`
 }
 
 // A RetentionSpec defines the desired state of a Retention policy.
 type RetentionSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              RetentionParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A RetentionStatus represents the observed state of a Retention policy.
 type RetentionStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             RetentionObservation `json:"atProvider,omitempty"`
 }
 
@@ -87,6 +245,8 @@ type RetentionStatus struct {
 // +kubebuilder:printcolumn:name="PROJECT",type="string",JSONPath=".spec.forProvider.projectId"
 // +kubebuilder:printcolumn:name="RULES",type="integer",JSONPath=".spec.forProvider.rules | length"
 // +kubebuilder:printcolumn:name="TRIGGER",type="string",JSONPath=".spec.forProvider.trigger"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 
@@ -108,11 +268,45 @@ type RetentionList struct {
 	Items           []Retention `json:"items"`
 }
 
+// ResolveReferences of this Retention.
+func (mg *Retention) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ProjectID,
+		Reference:    mg.Spec.ForProvider.ProjectRef,
+		Selector:     mg.Spec.ForProvider.ProjectSelector,
+		To: reference.To{
+			Managed: &projectv1beta1.Project{},
+			List:    &projectv1beta1.ProjectList{},
+		},
+		Extract:   projectv1beta1.ProjectID(),
+		Namespace: mg.GetNamespace(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.ProjectID")
+	}
+	mg.Spec.ForProvider.ProjectID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ProjectRef = rsp.ResolvedReference
+
+	return nil
+}
+
 // GetCondition of this Retention.
 func (mg *Retention) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this Retention.
+func (mg *Retention) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Retention.
+func (mg *Retention) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Retention.
 func (mg *Retention) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
@@ -9,6 +9,8 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -96,6 +98,13 @@ func (in *RetentionObservation) DeepCopyInto(out *RetentionObservation) {
 		in, out := &in.LastExecutionTime, &out.LastExecutionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.RecentExecutions != nil {
+		in, out := &in.RecentExecutions, &out.RecentExecutions
+		*out = make([]common.ExecutionObservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionObservation.
@@ -111,6 +120,16 @@ func (in *RetentionObservation) DeepCopy() *RetentionObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetentionParameters) DeepCopyInto(out *RetentionParameters) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(v2.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectSelector != nil {
+		in, out := &in.ProjectSelector, &out.ProjectSelector
+		*out = new(v2.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Description != nil {
 		in, out := &in.Description, &out.Description
 		*out = new(string)
@@ -123,6 +142,11 @@ func (in *RetentionParameters) DeepCopyInto(out *RetentionParameters) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(string)
+		**out = **in
+	}
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
@@ -143,11 +167,36 @@ func (in *RetentionParameters) DeepCopy() *RetentionParameters {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetentionRule) DeepCopyInto(out *RetentionRule) {
 	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RepositorySelectors != nil {
+		in, out := &in.RepositorySelectors, &out.RepositorySelectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeRepositories != nil {
+		in, out := &in.ExcludeRepositories, &out.ExcludeRepositories
+		*out = new(bool)
+		**out = **in
+	}
 	if in.TagSelectors != nil {
 		in, out := &in.TagSelectors, &out.TagSelectors
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExcludeTags != nil {
+		in, out := &in.ExcludeTags, &out.ExcludeTags
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = make(map[string]string, len(*in))
@@ -172,6 +221,11 @@ func (in *RetentionSpec) DeepCopyInto(out *RetentionSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionSpec.
@@ -188,6 +242,7 @@ func (in *RetentionSpec) DeepCopy() *RetentionSpec {
 func (in *RetentionStatus) DeepCopyInto(out *RetentionStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Policies for an expiresIn that exceeds Harbor's robot_token_duration.
+const (
+	ExpiryPolicyReject = "Reject"
+	ExpiryPolicyClamp  = "Clamp"
+)
+
+// TypeExpiryWithinLimit is false when a Robot's expiresIn exceeds Harbor's
+// robot_token_duration setting.
+const TypeExpiryWithinLimit xpv1.ConditionType = "ExpiryWithinLimit"
+
+// Reasons for the ExpiryWithinLimit condition.
+const (
+	ReasonExpiryWithinLimit    xpv1.ConditionReason = "WithinLimit"
+	ReasonExpiryClamped        xpv1.ConditionReason = "ExpiryClamped"
+	ReasonExpiryExceedsMaximum xpv1.ConditionReason = "ExpiryExceedsMaximum"
+)
+
+// ExpiryWithinLimit returns a condition indicating that Harbor accepts the
+// Robot's expiresIn as is.
+func ExpiryWithinLimit() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeExpiryWithinLimit,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExpiryWithinLimit,
+	}
+}
+
+// ExpiryClamped returns a condition indicating that the Robot's expiresIn
+// was lowered to Harbor's maximum.
+func ExpiryClamped(requested, maximum int64) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeExpiryWithinLimit,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExpiryClamped,
+		Message:            fmt.Sprintf("expiresIn of %d days exceeds robot_token_duration; requesting %d days instead", requested, maximum),
+	}
+}
+
+// ExpiryExceedsMaximum returns a condition indicating that the Robot's
+// expiresIn exceeds Harbor's maximum and was not requested.
+func ExpiryExceedsMaximum(requested, maximum int64) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeExpiryWithinLimit,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExpiryExceedsMaximum,
+		Message:            fmt.Sprintf("expiresIn of %d days exceeds robot_token_duration of %d days", requested, maximum),
+	}
+}
+
+// TypePermissionsAllowed is false when Harbor does not let robot accounts
+// have some of a Robot's permissions.
+const TypePermissionsAllowed xpv1.ConditionType = "PermissionsAllowed"
+
+// Reasons for the PermissionsAllowed condition.
+const (
+	ReasonPermissionsAllowed    xpv1.ConditionReason = "Allowed"
+	ReasonPermissionsDisallowed xpv1.ConditionReason = "Disallowed"
+)
+
+// PermissionsAllowed returns a condition indicating that Harbor lets robot
+// accounts have every permission the Robot requests.
+func PermissionsAllowed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePermissionsAllowed,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPermissionsAllowed,
+	}
+}
+
+// PermissionsDisallowed returns a condition listing the resource:action
+// pairs the Robot requests that Harbor does not let robot accounts have.
+func PermissionsDisallowed(pairs []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePermissionsAllowed,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPermissionsDisallowed,
+		Message:            fmt.Sprintf("Harbor does not allow robot accounts these permissions: %s", strings.Join(pairs, ", ")),
+	}
+}
@@ -5,8 +5,15 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // RobotPermission defines permissions for a robot account
@@ -21,6 +28,9 @@ type RobotPermission struct {
 }
 
 // RobotParameters defines the desired state of a Robot account
+// +kubebuilder:validation:XValidation:rule="!has(self.renewBefore) || !has(self.expiresIn) || self.expiresIn == -1 || duration(self.renewBefore) < duration(string(self.expiresIn * 24) + 'h')",message="renewBefore must be shorter than expiresIn"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectRef) || !has(self.projectRef.__namespace__)",message="projectRef must name a Project in the same namespace"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectSelector) || !has(self.projectSelector.__namespace__)",message="projectSelector must select a Project in the same namespace"
 type RobotParameters struct {
 	// Name is the name of the robot account
 	// +kubebuilder:validation:Required
@@ -34,11 +44,40 @@ type RobotParameters struct {
 	// +kubebuilder:validation:Optional
 	ProjectID *string `json:"projectId,omitempty"`
 
-	// ExpiresIn is the number of days until the robot account expires
+	// ProjectRef references a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectRef *xpv1.NamespacedReference `json:"projectRef,omitempty"`
+
+	// ProjectSelector selects a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectSelector *xpv1.NamespacedSelector `json:"projectSelector,omitempty"`
+
+	// ExpiresIn is the number of days until the robot account expires, or
+	// -1 for a robot account that never expires. Harbor's
+	// robot_token_duration setting caps the number of days; see
+	// expiryPolicy.
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:XValidation:rule="self == -1 || self >= 1",message="expiresIn must be -1 or at least 1 day"
 	ExpiresIn *int64 `json:"expiresIn,omitempty"`
 
+	// ExpiryPolicy is what to do when expiresIn exceeds Harbor's
+	// robot_token_duration. Reject leaves the robot account unchanged and
+	// reports the ExpiryWithinLimit condition; Clamp requests the maximum
+	// instead.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Reject;Clamp
+	// +kubebuilder:default=Reject
+	ExpiryPolicy *string `json:"expiryPolicy,omitempty"`
+
+	// RenewBefore is how long before the robot account expires it is
+	// replaced by a new one, whose secret is published as connection
+	// details. The old secret stops working when the robot account is
+	// replaced. Robot accounts are not renewed when this is unset.
+	// +kubebuilder:validation:Optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
 	// Permissions define what the robot can do
 	// +kubebuilder:validation:Required
 	Permissions []RobotPermission `json:"permissions"`
@@ -60,17 +99,27 @@ type RobotObservation struct {
 
 	// UpdateTime is when the robot was last updated
 	UpdateTime *metav1.Time `json:"updateTime,omitempty"`
+
+	// LastRenewalTime is when the robot account was last replaced because
+	// it was about to expire
+	LastRenewalTime *metav1.Time `json:"lastRenewalTime,omitempty"`
 }
 
 // A RobotSpec defines the desired state of a Robot account.
 type RobotSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              RobotParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A RobotStatus represents the observed state of a Robot account.
 type RobotStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             RobotObservation `json:"atProvider,omitempty"`
 }
 
@@ -81,6 +130,8 @@ type RobotStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".spec.forProvider.name"
 // +kubebuilder:printcolumn:name="EXPIRES",type="date",JSONPath=".status.atProvider.expiresAt"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 
@@ -102,11 +153,45 @@ type RobotList struct {
 	Items           []Robot `json:"items"`
 }
 
+// ResolveReferences of this Robot.
+func (mg *Robot) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.ProjectID),
+		Reference:    mg.Spec.ForProvider.ProjectRef,
+		Selector:     mg.Spec.ForProvider.ProjectSelector,
+		To: reference.To{
+			Managed: &projectv1beta1.Project{},
+			List:    &projectv1beta1.ProjectList{},
+		},
+		Extract:   projectv1beta1.ProjectID(),
+		Namespace: mg.GetNamespace(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.ProjectID")
+	}
+	mg.Spec.ForProvider.ProjectID = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.ProjectRef = rsp.ResolvedReference
+
+	return nil
+}
+
 // GetCondition of this Robot.
 func (mg *Robot) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this Robot.
+func (mg *Robot) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Robot.
+func (mg *Robot) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Robot.
 func (mg *Robot) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
@@ -9,6 +9,9 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -96,6 +99,10 @@ func (in *RobotObservation) DeepCopyInto(out *RobotObservation) {
 		in, out := &in.UpdateTime, &out.UpdateTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastRenewalTime != nil {
+		in, out := &in.LastRenewalTime, &out.LastRenewalTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RobotObservation.
@@ -121,11 +128,31 @@ func (in *RobotParameters) DeepCopyInto(out *RobotParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(v2.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectSelector != nil {
+		in, out := &in.ProjectSelector, &out.ProjectSelector
+		*out = new(v2.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ExpiresIn != nil {
 		in, out := &in.ExpiresIn, &out.ExpiresIn
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ExpiryPolicy != nil {
+		in, out := &in.ExpiryPolicy, &out.ExpiryPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.Permissions != nil {
 		in, out := &in.Permissions, &out.Permissions
 		*out = make([]RobotPermission, len(*in))
@@ -170,6 +197,11 @@ func (in *RobotSpec) DeepCopyInto(out *RobotSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RobotSpec.
@@ -186,6 +218,7 @@ func (in *RobotSpec) DeepCopy() *RobotSpec {
 func (in *RobotStatus) DeepCopyInto(out *RobotStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
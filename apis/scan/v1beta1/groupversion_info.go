@@ -25,5 +25,11 @@ var (
 )
 
 func addKnownTypes(s *runtime.Scheme) error {
+	s.AddKnownTypes(SchemeGroupVersion,
+		&Scan{},
+		&ScanList{},
+		&ScanJob{},
+		&ScanJobList{},
+	)
 	return nil
 }
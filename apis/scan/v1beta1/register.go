@@ -29,3 +29,11 @@ var (
 	ScanKindAPIVersion   = ScanKind + "." + SchemeGroupVersion.String()
 	ScanGroupVersionKind = SchemeGroupVersion.WithKind(ScanKind)
 )
+
+// ScanJob type metadata.
+var (
+	ScanJobKind             = reflect.TypeOf(ScanJob{}).Name()
+	ScanJobGroupKind        = schema.GroupKind{Group: Group, Kind: ScanJobKind}
+	ScanJobKindAPIVersion   = ScanJobKind + "." + SchemeGroupVersion.String()
+	ScanJobGroupVersionKind = SchemeGroupVersion.WithKind(ScanJobKind)
+)
@@ -6,6 +6,7 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,6 +14,13 @@ type ScanParameters struct {
 	ProjectID      string `json:"projectId"`
 	RepositoryName string `json:"repositoryName"`
 	Reference      string `json:"reference"`
+
+	// RescanGeneration scans the artifact again each time it is increased.
+	// The artifact is scanned once when the Scan is created whether or not
+	// it is set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	RescanGeneration *int64 `json:"rescanGeneration,omitempty"`
 }
 
 type ScanObservation struct {
@@ -24,15 +32,25 @@ type ScanObservation struct {
 	LowCount      *int64       `json:"lowCount,omitempty"`
 	StartTime     *metav1.Time `json:"startTime,omitempty"`
 	EndTime       *metav1.Time `json:"endTime,omitempty"`
+
+	// RescanGeneration is the rescanGeneration for which the artifact was
+	// last scanned.
+	RescanGeneration *int64 `json:"rescanGeneration,omitempty"`
 }
 
 type ScanSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              ScanParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 type ScanStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             ScanObservation `json:"atProvider,omitempty"`
 }
 
@@ -43,6 +61,8 @@ type ScanStatus struct {
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
 // +kubebuilder:printcolumn:name="CRITICAL",type="integer",JSONPath=".status.atProvider.criticalCount"
 // +kubebuilder:printcolumn:name="HIGH",type="integer",JSONPath=".status.atProvider.highCount"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 
@@ -66,6 +86,16 @@ func (mg *Scan) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this Scan.
+func (mg *Scan) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Scan.
+func (mg *Scan) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Scan.
 func (mg *Scan) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScanJobParameters are which artifacts a ScanJob scans, and whether to
+// start or stop the scan.
+type ScanJobParameters struct {
+	// ProjectName scans every artifact of one project. When unset the job
+	// is Harbor's scan of all artifacts.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	ProjectName *string `json:"projectName,omitempty"`
+
+	// Generation starts the scan again each time it is increased. The scan
+	// is started once when the ScanJob is created whether or not it is set.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Generation *int64 `json:"generation,omitempty"`
+
+	// Stop stops the scan while it is ongoing. For a scan of all artifacts
+	// this stops Harbor's latest scan, even one started by its schedule.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Stop *bool `json:"stop,omitempty"`
+}
+
+// ScanJobObservation is the progress of the scan.
+type ScanJobObservation struct {
+	// Generation is the generation for which the scan was last started.
+	Generation *int64 `json:"generation,omitempty"`
+
+	// StartTime is when the ScanJob last started the scan.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Ongoing is whether any artifact is still being scanned.
+	Ongoing *bool `json:"ongoing,omitempty"`
+
+	// Total is how many artifacts the scan covers.
+	Total *int64 `json:"total,omitempty"`
+
+	// Completed is how many of them have finished scanning.
+	Completed *int64 `json:"completed,omitempty"`
+
+	// Statuses counts the artifacts by scan status, such as Success, Error
+	// or Running.
+	Statuses map[string]int64 `json:"statuses,omitempty"`
+
+	// Trigger is what started Harbor's latest scan of all artifacts:
+	// Manual, Schedule or Event.
+	Trigger *string `json:"trigger,omitempty"`
+}
+
+// A ScanJobSpec defines the desired state of a ScanJob.
+type ScanJobSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              ScanJobParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// A ScanJobStatus represents the observed state of a ScanJob.
+type ScanJobStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             ScanJobObservation `json:"atProvider,omitempty"`
+}
+
+// A ScanJob starts and stops vulnerability scans of a project's artifacts,
+// or Harbor's scan of all artifacts, and reports their progress. Deleting a
+// ScanJob leaves an ongoing scan running; set stop first to stop it.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PROJECT",type="string",JSONPath=".spec.forProvider.projectName"
+// +kubebuilder:printcolumn:name="ONGOING",type="boolean",JSONPath=".status.atProvider.ongoing"
+// +kubebuilder:printcolumn:name="COMPLETED",type="integer",JSONPath=".status.atProvider.completed"
+// +kubebuilder:printcolumn:name="TOTAL",type="integer",JSONPath=".status.atProvider.total"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type ScanJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScanJobSpec   `json:"spec"`
+	Status ScanJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type ScanJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScanJob `json:"items"`
+}
+
+// GetCondition of this ScanJob.
+func (mg *ScanJob) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this ScanJob.
+func (mg *ScanJob) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this ScanJob.
+func (mg *ScanJob) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this ScanJob.
+func (mg *ScanJob) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this ScanJob.
+func (mg *ScanJob) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this ScanJob.
+func (mg *ScanJob) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this ScanJob.
+func (mg *ScanJob) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this ScanJob.
+func (mg *ScanJob) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this ScanJob.
+func (mg *ScanJob) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this ScanJob.
+func (mg *ScanJob) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
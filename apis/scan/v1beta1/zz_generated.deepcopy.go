@@ -9,6 +9,7 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/rossigee/provider-harbor/apis/common"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -39,6 +40,186 @@ func (in *Scan) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScanJob) DeepCopyInto(out *ScanJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanJob.
+func (in *ScanJob) DeepCopy() *ScanJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ScanJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScanJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScanJobList) DeepCopyInto(out *ScanJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScanJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanJobList.
+func (in *ScanJobList) DeepCopy() *ScanJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScanJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScanJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScanJobObservation) DeepCopyInto(out *ScanJobObservation) {
+	*out = *in
+	if in.Generation != nil {
+		in, out := &in.Generation, &out.Generation
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Ongoing != nil {
+		in, out := &in.Ongoing, &out.Ongoing
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Total != nil {
+		in, out := &in.Total, &out.Total
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Completed != nil {
+		in, out := &in.Completed, &out.Completed
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Statuses != nil {
+		in, out := &in.Statuses, &out.Statuses
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Trigger != nil {
+		in, out := &in.Trigger, &out.Trigger
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanJobObservation.
+func (in *ScanJobObservation) DeepCopy() *ScanJobObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ScanJobObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScanJobParameters) DeepCopyInto(out *ScanJobParameters) {
+	*out = *in
+	if in.ProjectName != nil {
+		in, out := &in.ProjectName, &out.ProjectName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Generation != nil {
+		in, out := &in.Generation, &out.Generation
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Stop != nil {
+		in, out := &in.Stop, &out.Stop
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanJobParameters.
+func (in *ScanJobParameters) DeepCopy() *ScanJobParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ScanJobParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScanJobSpec) DeepCopyInto(out *ScanJobSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanJobSpec.
+func (in *ScanJobSpec) DeepCopy() *ScanJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScanJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScanJobStatus) DeepCopyInto(out *ScanJobStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanJobStatus.
+func (in *ScanJobStatus) DeepCopy() *ScanJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScanJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScanList) DeepCopyInto(out *ScanList) {
 	*out = *in
@@ -112,6 +293,11 @@ func (in *ScanObservation) DeepCopyInto(out *ScanObservation) {
 		in, out := &in.EndTime, &out.EndTime
 		*out = (*in).DeepCopy()
 	}
+	if in.RescanGeneration != nil {
+		in, out := &in.RescanGeneration, &out.RescanGeneration
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanObservation.
@@ -127,6 +313,11 @@ func (in *ScanObservation) DeepCopy() *ScanObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScanParameters) DeepCopyInto(out *ScanParameters) {
 	*out = *in
+	if in.RescanGeneration != nil {
+		in, out := &in.RescanGeneration, &out.RescanGeneration
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanParameters.
@@ -143,7 +334,12 @@ func (in *ScanParameters) DeepCopy() *ScanParameters {
 func (in *ScanSpec) DeepCopyInto(out *ScanSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
-	out.ForProvider = in.ForProvider
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanSpec.
@@ -160,6 +356,7 @@ func (in *ScanSpec) DeepCopy() *ScanSpec {
 func (in *ScanStatus) DeepCopyInto(out *ScanStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
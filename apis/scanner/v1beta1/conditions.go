@@ -0,0 +1,59 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeDefaultScanner reports whether a ScannerRegistration that sets
+// isDefault is the default scanner of its Harbor instance.
+const TypeDefaultScanner xpv1.ConditionType = "DefaultScanner"
+
+// Reasons for the DefaultScanner condition.
+const (
+	ReasonDefaultElected  xpv1.ConditionReason = "Elected"
+	ReasonDefaultConflict xpv1.ConditionReason = "DefaultConflict"
+	ReasonNotDefault      xpv1.ConditionReason = "NotRequested"
+)
+
+// DefaultElected returns a condition indicating that the scanner is the
+// default scanner.
+func DefaultElected() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDefaultScanner,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDefaultElected,
+	}
+}
+
+// DefaultConflict returns a condition indicating that another
+// ScannerRegistration, named winner, is the default scanner instead.
+func DefaultConflict(winner, providerConfig string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDefaultScanner,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDefaultConflict,
+		Message: fmt.Sprintf("ScannerRegistration %s also sets isDefault for ProviderConfig %s and is older, so it is the default scanner",
+			winner, providerConfig),
+	}
+}
+
+// NotDefault returns a condition indicating that the scanner does not ask to
+// be the default scanner.
+func NotDefault() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDefaultScanner,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotDefault,
+	}
+}
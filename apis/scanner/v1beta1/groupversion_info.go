@@ -32,6 +32,8 @@ func addKnownTypes(s *runtime.Scheme) error {
 	s.AddKnownTypes(SchemeGroupVersion,
 		&ScannerRegistration{},
 		&ScannerRegistrationList{},
+		&ProjectScanner{},
+		&ProjectScannerList{},
 	)
 	return nil
 }
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProjectScannerParameters select the scanner that scans a project's
+// artifacts instead of the system default.
+// +kubebuilder:validation:XValidation:rule="has(self.scannerUUID) != has(self.scannerRegistrationRef)",message="exactly one of scannerUUID and scannerRegistrationRef must be set"
+type ProjectScannerParameters struct {
+	// ProjectName is the name of the Harbor project
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="projectName is immutable"
+	ProjectName string `json:"projectName"`
+
+	// ScannerUUID is the UUID of a scanner registered in Harbor
+	// +kubebuilder:validation:Optional
+	ScannerUUID *string `json:"scannerUUID,omitempty"`
+
+	// ScannerRegistrationRef names a ScannerRegistration in the same
+	// namespace whose scanner the project uses
+	// +kubebuilder:validation:Optional
+	ScannerRegistrationRef *ScannerRegistrationReference `json:"scannerRegistrationRef,omitempty"`
+}
+
+// A ScannerRegistrationReference names a ScannerRegistration.
+type ScannerRegistrationReference struct {
+	// Name of the ScannerRegistration
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// ProjectScannerObservation is the scanner a project currently uses.
+type ProjectScannerObservation struct {
+	// ScannerUUID is the UUID of the scanner
+	ScannerUUID *string `json:"scannerUUID,omitempty"`
+
+	// ScannerName is the name of the scanner
+	ScannerName *string `json:"scannerName,omitempty"`
+}
+
+// A ProjectScannerSpec defines the desired state of a ProjectScanner.
+type ProjectScannerSpec struct {
+	xpv1.ManagedResourceSpec `json:",inline"`
+	ForProvider              ProjectScannerParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// A ProjectScannerStatus represents the observed state of a ProjectScanner.
+type ProjectScannerStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
+	AtProvider             ProjectScannerObservation `json:"atProvider,omitempty"`
+}
+
+// A ProjectScanner assigns a scanner to a Harbor project. Harbor cannot
+// remove a project's scanner, so deleting a ProjectScanner leaves the
+// project with the scanner it was given.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="PROJECT",type="string",JSONPath=".spec.forProvider.projectName"
+// +kubebuilder:printcolumn:name="SCANNER",type="string",JSONPath=".status.atProvider.scannerName"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
+type ProjectScanner struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProjectScannerSpec   `json:"spec"`
+	Status ProjectScannerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type ProjectScannerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProjectScanner `json:"items"`
+}
+
+// GetCondition of this ProjectScanner.
+func (mg *ProjectScanner) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return mg.Status.GetCondition(ct)
+}
+
+// GetSyncStatus of this ProjectScanner.
+func (mg *ProjectScanner) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this ProjectScanner.
+func (mg *ProjectScanner) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
+// GetManagementPolicies of this ProjectScanner.
+func (mg *ProjectScanner) GetManagementPolicies() xpv1.ManagementPolicies {
+	return mg.Spec.ManagementPolicies
+}
+
+// GetProviderConfigReference of this ProjectScanner.
+func (mg *ProjectScanner) GetProviderConfigReference() *xpv1.ProviderConfigReference {
+	return mg.Spec.ProviderConfigReference
+}
+
+// GetWriteConnectionSecretToReference of this ProjectScanner.
+func (mg *ProjectScanner) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	return mg.Spec.WriteConnectionSecretToReference
+}
+
+// SetConditions of this ProjectScanner.
+func (mg *ProjectScanner) SetConditions(c ...xpv1.Condition) {
+	mg.Status.SetConditions(c...)
+}
+
+// SetManagementPolicies of this ProjectScanner.
+func (mg *ProjectScanner) SetManagementPolicies(r xpv1.ManagementPolicies) {
+	mg.Spec.ManagementPolicies = r
+}
+
+// SetProviderConfigReference of this ProjectScanner.
+func (mg *ProjectScanner) SetProviderConfigReference(r *xpv1.ProviderConfigReference) {
+	mg.Spec.ProviderConfigReference = r
+}
+
+// SetWriteConnectionSecretToReference of this ProjectScanner.
+func (mg *ProjectScanner) SetWriteConnectionSecretToReference(r *xpv1.LocalSecretReference) {
+	mg.Spec.WriteConnectionSecretToReference = r
+}
@@ -29,3 +29,11 @@ var (
 	ScannerRegistrationKindAPIVersion   = ScannerRegistrationKind + "." + SchemeGroupVersion.String()
 	ScannerRegistrationGroupVersionKind = SchemeGroupVersion.WithKind(ScannerRegistrationKind)
 )
+
+// ProjectScanner type metadata.
+var (
+	ProjectScannerKind             = reflect.TypeOf(ProjectScanner{}).Name()
+	ProjectScannerGroupKind        = schema.GroupKind{Group: Group, Kind: ProjectScannerKind}
+	ProjectScannerKindAPIVersion   = ProjectScannerKind + "." + SchemeGroupVersion.String()
+	ProjectScannerGroupVersionKind = SchemeGroupVersion.WithKind(ProjectScannerKind)
+)
@@ -6,6 +6,7 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,11 +33,28 @@ type ScannerRegistrationParameters struct {
 	// +kubebuilder:validation:Optional
 	AccessCredential *string `json:"accessCredential,omitempty"`
 
+	// CredentialRobot makes the provider create a dedicated system robot
+	// account that may pull artifacts for scanning, and register the scanner
+	// with its credential using Basic auth. Auth and AccessCredential are
+	// ignored when it is set. The robot is deleted with the scanner
+	// registration.
+	// +kubebuilder:validation:Optional
+	CredentialRobot *ScannerCredentialRobot `json:"credentialRobot,omitempty"`
+
 	// SkipCertVerify indicates whether to skip certificate verification
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=false
 	SkipCertVerify *bool `json:"skipCertVerify,omitempty"`
 
+	// CABundleRef names the CA bundle that signs the scanner adapter's
+	// certificate. Harbor has no API for per-scanner CAs and verifies the
+	// adapter against its own trust store, which must include this CA. The
+	// provider checks the bundle, always registers the scanner with
+	// certificate verification on, and re-registers it when the bundle is
+	// renewed so that Harbor re-checks the adapter.
+	// +kubebuilder:validation:Optional
+	CABundleRef *common.CABundleSource `json:"caBundleRef,omitempty"`
+
 	// UseInternalAddr indicates whether to use internal address
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=false
@@ -47,12 +65,44 @@ type ScannerRegistrationParameters struct {
 	// +kubebuilder:default=false
 	Disabled *bool `json:"disabled,omitempty"`
 
-	// IsDefault indicates whether this is the default scanner
+	// IsDefault makes this the default scanner of its Harbor instance. When
+	// several ScannerRegistrations for the same ProviderConfig set it, the
+	// oldest one is made the default and the others report a DefaultScanner
+	// condition with reason DefaultConflict. Unsetting it does not clear the
+	// default in Harbor, which always has one.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=false
 	IsDefault *bool `json:"isDefault,omitempty"`
 }
 
+// ScannerCredentialRobot configures the robot account whose credential a
+// scanner uses to pull artifacts from Harbor
+type ScannerCredentialRobot struct {
+	// Name of the robot account, without the robot$ prefix. Defaults to
+	// scanner-<scanner name>.
+	// +kubebuilder:validation:Optional
+	Name *string `json:"name,omitempty"`
+
+	// Duration is the robot account's lifetime in days, or -1 for no expiry.
+	// The robot is replaced, and the scanner given its new credential, a
+	// week before it expires.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=90
+	Duration *int64 `json:"duration,omitempty"`
+}
+
+// ScannerCapability is one kind of scan a scanner adapter can run
+type ScannerCapability struct {
+	// Type is the kind of scan, such as vulnerability or sbom
+	Type string `json:"type,omitempty"`
+
+	// ConsumesMimeTypes are the artifact MIME types the scan accepts
+	ConsumesMimeTypes []string `json:"consumesMimeTypes,omitempty"`
+
+	// ProducesMimeTypes are the report MIME types the scan produces
+	ProducesMimeTypes []string `json:"producesMimeTypes,omitempty"`
+}
+
 // ScannerRegistrationObservation defines the observed state of a ScannerRegistration
 type ScannerRegistrationObservation struct {
 	// UUID is the unique identifier of the scanner registration
@@ -75,17 +125,46 @@ type ScannerRegistrationObservation struct {
 
 	// Version is the scanner version
 	Version *string `json:"version,omitempty"`
+
+	// IsDefault is whether Harbor uses this scanner by default
+	IsDefault *bool `json:"isDefault,omitempty"`
+
+	// Capabilities are the kinds of scan the scanner adapter declares it can
+	// run, as last read from its metadata
+	Capabilities []ScannerCapability `json:"capabilities,omitempty"`
+
+	// SupportsSBOM is whether the scanner adapter can generate SBOMs
+	SupportsSBOM *bool `json:"supportsSbom,omitempty"`
+
+	// CredentialRobotID is the ID of the robot account provisioned for
+	// credentialRobot
+	CredentialRobotID *string `json:"credentialRobotId,omitempty"`
+
+	// CredentialRobotName is the full name of that robot account
+	CredentialRobotName *string `json:"credentialRobotName,omitempty"`
+
+	// CredentialExpiresAt is when that robot account expires
+	CredentialExpiresAt *metav1.Time `json:"credentialExpiresAt,omitempty"`
+
+	// CABundle is the CA bundle the scanner was last registered with
+	CABundle *common.CABundleObservation `json:"caBundle,omitempty"`
 }
 
 // A ScannerRegistrationSpec defines the desired state of a ScannerRegistration.
 type ScannerRegistrationSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              ScannerRegistrationParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A ScannerRegistrationStatus represents the observed state of a ScannerRegistration.
 type ScannerRegistrationStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             ScannerRegistrationObservation `json:"atProvider,omitempty"`
 }
 
@@ -96,6 +175,9 @@ type ScannerRegistrationStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="SCANNER-UUID",type="string",JSONPath=".status.atProvider.uuid"
 // +kubebuilder:printcolumn:name="HEALTH",type="string",JSONPath=".status.atProvider.health"
+// +kubebuilder:printcolumn:name="SBOM",type="boolean",JSONPath=".status.atProvider.supportsSbom"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 type ScannerRegistration struct {
@@ -118,6 +200,21 @@ func (mg *ScannerRegistration) GetCondition(ct xpv1.ConditionType) xpv1.Conditio
 	return mg.Status.GetCondition(ct)
 }
 
+// GetCABundleSource of this ScannerRegistration.
+func (mg *ScannerRegistration) GetCABundleSource() *common.CABundleSource {
+	return mg.Spec.ForProvider.CABundleRef
+}
+
+// GetSyncStatus of this ScannerRegistration.
+func (mg *ScannerRegistration) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this ScannerRegistration.
+func (mg *ScannerRegistration) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this ScannerRegistration.
 func (mg *ScannerRegistration) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
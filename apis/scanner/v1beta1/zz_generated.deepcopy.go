@@ -9,9 +9,209 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/rossigee/provider-harbor/apis/common"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectScanner) DeepCopyInto(out *ProjectScanner) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectScanner.
+func (in *ProjectScanner) DeepCopy() *ProjectScanner {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectScanner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectScanner) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectScannerList) DeepCopyInto(out *ProjectScannerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProjectScanner, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectScannerList.
+func (in *ProjectScannerList) DeepCopy() *ProjectScannerList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectScannerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProjectScannerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectScannerObservation) DeepCopyInto(out *ProjectScannerObservation) {
+	*out = *in
+	if in.ScannerUUID != nil {
+		in, out := &in.ScannerUUID, &out.ScannerUUID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScannerName != nil {
+		in, out := &in.ScannerName, &out.ScannerName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectScannerObservation.
+func (in *ProjectScannerObservation) DeepCopy() *ProjectScannerObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectScannerObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectScannerParameters) DeepCopyInto(out *ProjectScannerParameters) {
+	*out = *in
+	if in.ScannerUUID != nil {
+		in, out := &in.ScannerUUID, &out.ScannerUUID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScannerRegistrationRef != nil {
+		in, out := &in.ScannerRegistrationRef, &out.ScannerRegistrationRef
+		*out = new(ScannerRegistrationReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectScannerParameters.
+func (in *ProjectScannerParameters) DeepCopy() *ProjectScannerParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectScannerParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectScannerSpec) DeepCopyInto(out *ProjectScannerSpec) {
+	*out = *in
+	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectScannerSpec.
+func (in *ProjectScannerSpec) DeepCopy() *ProjectScannerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectScannerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectScannerStatus) DeepCopyInto(out *ProjectScannerStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectScannerStatus.
+func (in *ProjectScannerStatus) DeepCopy() *ProjectScannerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectScannerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScannerCapability) DeepCopyInto(out *ScannerCapability) {
+	*out = *in
+	if in.ConsumesMimeTypes != nil {
+		in, out := &in.ConsumesMimeTypes, &out.ConsumesMimeTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProducesMimeTypes != nil {
+		in, out := &in.ProducesMimeTypes, &out.ProducesMimeTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScannerCapability.
+func (in *ScannerCapability) DeepCopy() *ScannerCapability {
+	if in == nil {
+		return nil
+	}
+	out := new(ScannerCapability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScannerCredentialRobot) DeepCopyInto(out *ScannerCredentialRobot) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScannerCredentialRobot.
+func (in *ScannerCredentialRobot) DeepCopy() *ScannerCredentialRobot {
+	if in == nil {
+		return nil
+	}
+	out := new(ScannerCredentialRobot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScannerRegistration) DeepCopyInto(out *ScannerRegistration) {
 	*out = *in
@@ -107,6 +307,42 @@ func (in *ScannerRegistrationObservation) DeepCopyInto(out *ScannerRegistrationO
 		*out = new(string)
 		**out = **in
 	}
+	if in.IsDefault != nil {
+		in, out := &in.IsDefault, &out.IsDefault
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]ScannerCapability, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SupportsSBOM != nil {
+		in, out := &in.SupportsSBOM, &out.SupportsSBOM
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CredentialRobotID != nil {
+		in, out := &in.CredentialRobotID, &out.CredentialRobotID
+		*out = new(string)
+		**out = **in
+	}
+	if in.CredentialRobotName != nil {
+		in, out := &in.CredentialRobotName, &out.CredentialRobotName
+		*out = new(string)
+		**out = **in
+	}
+	if in.CredentialExpiresAt != nil {
+		in, out := &in.CredentialExpiresAt, &out.CredentialExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(common.CABundleObservation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScannerRegistrationObservation.
@@ -137,11 +373,21 @@ func (in *ScannerRegistrationParameters) DeepCopyInto(out *ScannerRegistrationPa
 		*out = new(string)
 		**out = **in
 	}
+	if in.CredentialRobot != nil {
+		in, out := &in.CredentialRobot, &out.CredentialRobot
+		*out = new(ScannerCredentialRobot)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SkipCertVerify != nil {
 		in, out := &in.SkipCertVerify, &out.SkipCertVerify
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CABundleRef != nil {
+		in, out := &in.CABundleRef, &out.CABundleRef
+		*out = new(common.CABundleSource)
+		**out = **in
+	}
 	if in.UseInternalAddr != nil {
 		in, out := &in.UseInternalAddr, &out.UseInternalAddr
 		*out = new(bool)
@@ -169,11 +415,31 @@ func (in *ScannerRegistrationParameters) DeepCopy() *ScannerRegistrationParamete
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScannerRegistrationReference) DeepCopyInto(out *ScannerRegistrationReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScannerRegistrationReference.
+func (in *ScannerRegistrationReference) DeepCopy() *ScannerRegistrationReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ScannerRegistrationReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScannerRegistrationSpec) DeepCopyInto(out *ScannerRegistrationSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScannerRegistrationSpec.
@@ -190,6 +456,7 @@ func (in *ScannerRegistrationSpec) DeepCopy() *ScannerRegistrationSpec {
 func (in *ScannerRegistrationStatus) DeepCopyInto(out *ScannerRegistrationStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
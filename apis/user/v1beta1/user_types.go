@@ -6,6 +6,7 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,6 +36,14 @@ type UserParameters struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=false
 	SysAdminFlag *bool `json:"sysAdminFlag,omitempty"`
+
+	// CLISecretGeneration makes the provider generate a random CLI secret for
+	// the user and publish it as the cli_secret connection detail. Increase
+	// it to replace the secret. Harbor only accepts CLI secrets when it
+	// authenticates users with OIDC.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	CLISecretGeneration *int64 `json:"cliSecretGeneration,omitempty"`
 }
 
 // UserObservation defines the observed state of a User
@@ -50,17 +59,27 @@ type UserObservation struct {
 
 	// AdminRoleInAuth indicates if the user has admin role in authentication
 	AdminRoleInAuth *bool `json:"adminRoleInAuth,omitempty"`
+
+	// CLISecretGeneration is the cliSecretGeneration for which the current
+	// CLI secret was generated
+	CLISecretGeneration *int64 `json:"cliSecretGeneration,omitempty"`
 }
 
 // A UserSpec defines the desired state of a User.
 type UserSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              UserParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A UserStatus represents the observed state of a User.
 type UserStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             UserObservation `json:"atProvider,omitempty"`
 }
 
@@ -71,6 +90,8 @@ type UserStatus struct {
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="USER-ID",type="string",JSONPath=".status.atProvider.id"
 // +kubebuilder:printcolumn:name="USERNAME",type="string",JSONPath=".spec.forProvider.username"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 type User struct {
@@ -93,6 +114,16 @@ func (mg *User) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this User.
+func (mg *User) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this User.
+func (mg *User) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this User.
 func (mg *User) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
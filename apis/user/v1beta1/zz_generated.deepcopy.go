@@ -10,6 +10,7 @@ package v1beta1
 
 import (
 	"github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -93,6 +94,11 @@ func (in *UserObservation) DeepCopyInto(out *UserObservation) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CLISecretGeneration != nil {
+		in, out := &in.CLISecretGeneration, &out.CLISecretGeneration
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserObservation.
@@ -128,6 +134,11 @@ func (in *UserParameters) DeepCopyInto(out *UserParameters) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CLISecretGeneration != nil {
+		in, out := &in.CLISecretGeneration, &out.CLISecretGeneration
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserParameters.
@@ -145,6 +156,11 @@ func (in *UserSpec) DeepCopyInto(out *UserSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSpec.
@@ -161,6 +177,7 @@ func (in *UserSpec) DeepCopy() *UserSpec {
 func (in *UserStatus) DeepCopyInto(out *UserStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
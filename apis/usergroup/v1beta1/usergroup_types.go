@@ -6,6 +6,7 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,11 +36,17 @@ type UserGroupObservation struct {
 type UserGroupSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              UserGroupParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A UserGroupStatus represents the observed state of a UserGroup.
 type UserGroupStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             UserGroupObservation `json:"atProvider,omitempty"`
 }
 
@@ -51,6 +58,8 @@ type UserGroupStatus struct {
 // +kubebuilder:printcolumn:name="GROUP-ID",type="string",JSONPath=".status.atProvider.id"
 // +kubebuilder:printcolumn:name="GROUP-NAME",type="string",JSONPath=".spec.forProvider.groupName"
 // +kubebuilder:printcolumn:name="GROUP-TYPE",type="integer",JSONPath=".spec.forProvider.groupType"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 type UserGroup struct {
@@ -73,6 +82,16 @@ func (mg *UserGroup) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetSyncStatus of this UserGroup.
+func (mg *UserGroup) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this UserGroup.
+func (mg *UserGroup) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this UserGroup.
 func (mg *UserGroup) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
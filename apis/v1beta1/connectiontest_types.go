@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package v1beta1
+
+import (
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Results of a HarborConnectionTest and of each of its checks.
+const (
+	ConnectionTestPassed  = "Passed"
+	ConnectionTestFailed  = "Failed"
+	ConnectionTestSkipped = "Skipped"
+)
+
+// A ProviderConfigName names a ProviderConfig.
+type ProviderConfigName struct {
+	// Name of the ProviderConfig.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// A HarborConnectionTestSpec defines which ProviderConfig to test.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable; create a new HarborConnectionTest to test again"
+type HarborConnectionTestSpec struct {
+	// ProviderConfigRef names the ProviderConfig whose credentials and
+	// endpoint are tested.
+	ProviderConfigRef ProviderConfigName `json:"providerConfigRef"`
+
+	// SandboxProject is a project in which a temporary robot account may be
+	// created and deleted, to test that the credentials can change Harbor.
+	// The check is skipped when unset.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	SandboxProject *string `json:"sandboxProject,omitempty"`
+}
+
+// A ConnectionCheck is the outcome of one check.
+type ConnectionCheck struct {
+	// Name of the check.
+	Name string `json:"name"`
+
+	// Result is Passed, Failed or Skipped.
+	Result string `json:"result"`
+
+	// Message explains the result.
+	Message string `json:"message,omitempty"`
+}
+
+// A HarborConnectionTestStatus reports the outcome of a HarborConnectionTest.
+type HarborConnectionTestStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// Result is Passed when every check that ran passed, and Failed
+	// otherwise.
+	Result string `json:"result,omitempty"`
+
+	// CompletionTime is when the checks finished. A HarborConnectionTest
+	// runs once; create a new one to test again.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// HarborVersion is the version Harbor reported.
+	HarborVersion string `json:"harborVersion,omitempty"`
+
+	// Username is the Harbor account the credentials belong to.
+	Username string `json:"username,omitempty"`
+
+	// SysAdmin is whether that account is a Harbor system administrator.
+	SysAdmin *bool `json:"sysAdmin,omitempty"`
+
+	// Checks are the outcomes of the checks, in the order they ran.
+	Checks []ConnectionCheck `json:"checks,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A HarborConnectionTest checks, once, that a ProviderConfig can reach and
+// use Harbor: that its credentials authenticate, that projects can be listed
+// and, given a sandbox project, that a robot account can be created and
+// deleted. The results are written to its status.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="PROVIDERCONFIG",type="string",JSONPath=".spec.providerConfigRef.name"
+// +kubebuilder:printcolumn:name="RESULT",type="string",JSONPath=".status.result"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.harborVersion"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,harbor}
+type HarborConnectionTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HarborConnectionTestSpec   `json:"spec"`
+	Status HarborConnectionTestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HarborConnectionTestList contains a list of HarborConnectionTest.
+type HarborConnectionTestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborConnectionTest `json:"items"`
+}
+
+// GetCondition of this HarborConnectionTest.
+func (t *HarborConnectionTest) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return t.Status.GetCondition(ct)
+}
+
+// SetConditions of this HarborConnectionTest.
+func (t *HarborConnectionTest) SetConditions(c ...xpv1.Condition) {
+	t.Status.SetConditions(c...)
+}
+
+// Reasons for the Ready condition of a HarborConnectionTest.
+const (
+	ReasonConnectionTestPassed xpv1.ConditionReason = "ChecksPassed"
+	ReasonConnectionTestFailed xpv1.ConditionReason = "ChecksFailed"
+)
+
+// ChecksPassed returns a condition indicating that every check that ran
+// passed.
+func ChecksPassed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConnectionTestPassed,
+	}
+}
+
+// ChecksFailed returns a condition indicating that the named check failed.
+func ChecksFailed(check, message string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConnectionTestFailed,
+		Message:            check + ": " + message,
+	}
+}
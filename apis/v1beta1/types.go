@@ -13,6 +13,77 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// Policy restricts what managed resources using this ProviderConfig may
+	// ask of Harbor.
+	// +optional
+	Policy *ProviderConfigPolicy `json:"policy,omitempty"`
+
+	// TLS configures how the provider verifies Harbor's certificate and the
+	// client certificate it presents.
+	// +optional
+	TLS *ProviderConfigTLS `json:"tls,omitempty"`
+}
+
+// ProviderConfigTLS configures the TLS connections the provider makes to
+// Harbor.
+// +kubebuilder:validation:XValidation:rule="has(self.clientCertSecretRef) == has(self.clientKeySecretRef)",message="clientCertSecretRef and clientKeySecretRef must be set together"
+// +kubebuilder:validation:XValidation:rule="!has(self.caBundleSecretRef) || !has(self.insecureSkipVerify) || !self.insecureSkipVerify",message="caBundleSecretRef cannot be set with insecureSkipVerify"
+type ProviderConfigTLS struct {
+	// CABundleSecretRef selects PEM CA certificates that Harbor's
+	// certificate must chain to, in place of the system's trusted CAs.
+	// +optional
+	CABundleSecretRef *xpv1.SecretKeySelector `json:"caBundleSecretRef,omitempty"`
+
+	// ClientCertSecretRef selects the PEM client certificate the provider
+	// presents to Harbor, or to a proxy in front of it.
+	// +optional
+	ClientCertSecretRef *xpv1.SecretKeySelector `json:"clientCertSecretRef,omitempty"`
+
+	// ClientKeySecretRef selects the PEM private key of the client
+	// certificate.
+	// +optional
+	ClientKeySecretRef *xpv1.SecretKeySelector `json:"clientKeySecretRef,omitempty"`
+
+	// MinVersion is the lowest TLS version the provider accepts.
+	// +optional
+	// +kubebuilder:validation:Enum="1.2";"1.3"
+	// +kubebuilder:default="1.2"
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// InsecureSkipVerify accepts any certificate Harbor presents. When unset
+	// the credentials secret's insecure key decides, so setting it to false
+	// verifies Harbor's certificate whatever that key says.
+	// +optional
+	InsecureSkipVerify *bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// ProviderConfigPolicy holds guardrails enforced by the provider before it
+// calls Harbor.
+type ProviderConfigPolicy struct {
+	// AllowedRegistryURLPatterns lists the endpoints that Registries may
+	// point at, such as https://registry.example.com or
+	// https://*.example.com. A * matches any characters other than /. A URL
+	// is allowed when it, or one of its parent paths, matches a pattern.
+	// Every endpoint is allowed when the list is empty.
+	// +optional
+	AllowedRegistryURLPatterns []string `json:"allowedRegistryURLPatterns,omitempty"`
+
+	// ProtectedProjects lists projects, by name or ID, that managed
+	// resources may create but never update or delete, together with the
+	// members, robot accounts, webhooks and rules in them. A protected
+	// managed resource that is deleted is removed without touching Harbor.
+	// +optional
+	// +listType=set
+	ProtectedProjects []string `json:"protectedProjects,omitempty"`
+
+	// ProtectedRobotPatterns lists robot account names, such as crossplane
+	// or infra-*, that Robots may never update or delete. A * matches any
+	// characters. Patterns match spec.forProvider.name, which for a project
+	// robot account omits the robot$<project>+ prefix Harbor adds.
+	// +optional
+	// +listType=set
+	ProtectedRobotPatterns []string `json:"protectedRobotPatterns,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -21,12 +92,60 @@ type ProviderCredentials struct {
 	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem
 	Source xpv1.CredentialsSource `json:"source"`
 
+	// AuthType is how the provider authenticates to Harbor: Basic with a
+	// user's username and password, Robot with a robot account's robotName
+	// and robotSecret, or Token with a bearer token such as an OIDC ID token.
+	// When unset it is taken from the secret's authType key, or from which
+	// credentials the secret holds.
+	// +optional
+	// +kubebuilder:validation:Enum=Basic;Robot;Token
+	AuthType string `json:"authType,omitempty"`
+
 	xpv1.CommonCredentialSelectors `json:",inline"`
 }
 
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// Capabilities records what the ProviderConfig's Harbor account was
+	// found to be allowed to do when it was last probed. It is unset until
+	// the account has been probed successfully.
+	// +optional
+	Capabilities *ProviderConfigCapabilities `json:"capabilities,omitempty"`
+}
+
+// ProviderConfigCapabilities are what a ProviderConfig's Harbor account may
+// do. Managed resources whose kind needs a capability the account lacks fail
+// without calling Harbor.
+type ProviderConfigCapabilities struct {
+	// Account is the name of the Harbor account.
+	Account string `json:"account"`
+
+	// SysAdmin is whether the account is a Harbor system administrator.
+	SysAdmin bool `json:"sysAdmin"`
+
+	// CreateProjects is whether the account may create projects, which
+	// Harbor's project creation restriction decides for accounts that are
+	// not system administrators.
+	CreateProjects bool `json:"createProjects"`
+
+	// ManageUsers is whether the account may create, update and delete
+	// users and user groups.
+	ManageUsers bool `json:"manageUsers"`
+
+	// ManageSystem is whether the account may manage system-wide objects:
+	// configurations, registries, replications, scanners, garbage
+	// collection and scan schedules.
+	ManageSystem bool `json:"manageSystem"`
+
+	// ProjectCreationRestriction is who Harbor lets create projects,
+	// everyone or adminonly, if it could be read.
+	// +optional
+	ProjectCreationRestriction string `json:"projectCreationRestriction,omitempty"`
+
+	// ProbedAt is when the account was last probed.
+	ProbedAt metav1.Time `json:"probedAt"`
 }
 
 // +kubebuilder:object:root=true
@@ -35,6 +154,8 @@ type ProviderConfigStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
+// +kubebuilder:printcolumn:name="ACCOUNT",type="string",JSONPath=".status.capabilities.account",priority=1
+// +kubebuilder:printcolumn:name="SYSADMIN",type="boolean",JSONPath=".status.capabilities.sysAdmin",priority=1
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,harbor}
 type ProviderConfig struct {
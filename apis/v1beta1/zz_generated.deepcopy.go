@@ -9,9 +9,135 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/crossplane/crossplane/apis/v2/core/v2"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionCheck) DeepCopyInto(out *ConnectionCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionCheck.
+func (in *ConnectionCheck) DeepCopy() *ConnectionCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConnectionTest) DeepCopyInto(out *HarborConnectionTest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConnectionTest.
+func (in *HarborConnectionTest) DeepCopy() *HarborConnectionTest {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConnectionTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborConnectionTest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConnectionTestList) DeepCopyInto(out *HarborConnectionTestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborConnectionTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConnectionTestList.
+func (in *HarborConnectionTestList) DeepCopy() *HarborConnectionTestList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConnectionTestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborConnectionTestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConnectionTestSpec) DeepCopyInto(out *HarborConnectionTestSpec) {
+	*out = *in
+	out.ProviderConfigRef = in.ProviderConfigRef
+	if in.SandboxProject != nil {
+		in, out := &in.SandboxProject, &out.SandboxProject
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConnectionTestSpec.
+func (in *HarborConnectionTestSpec) DeepCopy() *HarborConnectionTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConnectionTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConnectionTestStatus) DeepCopyInto(out *HarborConnectionTestStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SysAdmin != nil {
+		in, out := &in.SysAdmin, &out.SysAdmin
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ConnectionCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConnectionTestStatus.
+func (in *HarborConnectionTestStatus) DeepCopy() *HarborConnectionTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConnectionTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
@@ -39,6 +165,22 @@ func (in *ProviderConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigCapabilities) DeepCopyInto(out *ProviderConfigCapabilities) {
+	*out = *in
+	in.ProbedAt.DeepCopyInto(&out.ProbedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigCapabilities.
+func (in *ProviderConfigCapabilities) DeepCopy() *ProviderConfigCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfigList) DeepCopyInto(out *ProviderConfigList) {
 	*out = *in
@@ -71,10 +213,65 @@ func (in *ProviderConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigName) DeepCopyInto(out *ProviderConfigName) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigName.
+func (in *ProviderConfigName) DeepCopy() *ProviderConfigName {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigName)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigPolicy) DeepCopyInto(out *ProviderConfigPolicy) {
+	*out = *in
+	if in.AllowedRegistryURLPatterns != nil {
+		in, out := &in.AllowedRegistryURLPatterns, &out.AllowedRegistryURLPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtectedProjects != nil {
+		in, out := &in.ProtectedProjects, &out.ProtectedProjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtectedRobotPatterns != nil {
+		in, out := &in.ProtectedRobotPatterns, &out.ProtectedRobotPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigPolicy.
+func (in *ProviderConfigPolicy) DeepCopy() *ProviderConfigPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfigSpec) DeepCopyInto(out *ProviderConfigSpec) {
 	*out = *in
 	in.Credentials.DeepCopyInto(&out.Credentials)
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(ProviderConfigPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ProviderConfigTLS)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigSpec.
@@ -91,6 +288,11 @@ func (in *ProviderConfigSpec) DeepCopy() *ProviderConfigSpec {
 func (in *ProviderConfigStatus) DeepCopyInto(out *ProviderConfigStatus) {
 	*out = *in
 	in.ProviderConfigStatus.DeepCopyInto(&out.ProviderConfigStatus)
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ProviderConfigCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigStatus.
@@ -103,6 +305,41 @@ func (in *ProviderConfigStatus) DeepCopy() *ProviderConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigTLS) DeepCopyInto(out *ProviderConfigTLS) {
+	*out = *in
+	if in.CABundleSecretRef != nil {
+		in, out := &in.CABundleSecretRef, &out.CABundleSecretRef
+		*out = new(v2.SecretKeySelector)
+		**out = **in
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(v2.SecretKeySelector)
+		**out = **in
+	}
+	if in.ClientKeySecretRef != nil {
+		in, out := &in.ClientKeySecretRef, &out.ClientKeySecretRef
+		*out = new(v2.SecretKeySelector)
+		**out = **in
+	}
+	if in.InsecureSkipVerify != nil {
+		in, out := &in.InsecureSkipVerify, &out.InsecureSkipVerify
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigTLS.
+func (in *ProviderConfigTLS) DeepCopy() *ProviderConfigTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfigUsage) DeepCopyInto(out *ProviderConfigUsage) {
 	*out = *in
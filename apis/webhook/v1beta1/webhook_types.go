@@ -5,15 +5,36 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reference"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // WebhookParameters defines the desired state of a Webhook
+// +kubebuilder:validation:XValidation:rule="!has(self.notifyType) || self.notifyType != 'slack' || !has(self.payloadFormat) || self.payloadFormat == 'Default'",message="slack webhooks only support the Default payloadFormat"
+// +kubebuilder:validation:XValidation:rule="has(self.projectId) || has(self.projectRef) || has(self.projectSelector)",message="one of projectId, projectRef and projectSelector must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectRef) || !has(self.projectRef.__namespace__)",message="projectRef must name a Project in the same namespace"
+// +kubebuilder:validation:XValidation:rule="!has(self.projectSelector) || !has(self.projectSelector.__namespace__)",message="projectSelector must select a Project in the same namespace"
 type WebhookParameters struct {
 	// ProjectID is the ID of the project this webhook belongs to
-	// +kubebuilder:validation:Required
-	ProjectID string `json:"projectId"`
+	// +kubebuilder:validation:Optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// ProjectRef references a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectRef *xpv1.NamespacedReference `json:"projectRef,omitempty"`
+
+	// ProjectSelector selects a Project in the same namespace to set
+	// projectId to its Harbor ID
+	// +kubebuilder:validation:Optional
+	ProjectSelector *xpv1.NamespacedSelector `json:"projectSelector,omitempty"`
 
 	// Name is the name of the webhook
 	// +kubebuilder:validation:Required
@@ -30,9 +51,24 @@ type WebhookParameters struct {
 
 	// EventTypes is a list of Harbor events to subscribe to
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Items:Enum=PUSH_ARTIFACT;PULL_ARTIFACT;DELETE_ARTIFACT;SCANNING_COMPLETED;SCANNING_FAILED
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Items:Enum=PUSH_ARTIFACT;PULL_ARTIFACT;DELETE_ARTIFACT;SCANNING_COMPLETED;SCANNING_FAILED;SCANNING_STOPPED;QUOTA_EXCEED;QUOTA_WARNING;REPLICATION;TAG_RETENTION
 	EventTypes []string `json:"eventTypes"`
 
+	// NotifyType is how events are delivered: http posts a JSON payload to
+	// the URL, slack posts a message to a Slack incoming webhook.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http;slack
+	// +kubebuilder:default=http
+	NotifyType *string `json:"notifyType,omitempty"`
+
+	// PayloadFormat is the format of http payloads. Slack targets only
+	// support Default.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Default;CloudEvents
+	// +kubebuilder:default=Default
+	PayloadFormat *string `json:"payloadFormat,omitempty"`
+
 	// AuthHeader is the optional authentication header value
 	// +kubebuilder:validation:Optional
 	AuthHeader *string `json:"authHeader,omitempty"`
@@ -42,6 +78,14 @@ type WebhookParameters struct {
 	// +kubebuilder:default=false
 	SkipCertVerify *bool `json:"skipCertVerify,omitempty"`
 
+	// CABundleRef names the CA bundle that signs the endpoint's certificate.
+	// Harbor has no API for per-webhook CAs and verifies endpoints against
+	// its own trust store, which must include this CA. The provider checks
+	// the bundle, keeps certificate verification on, and re-saves the policy
+	// when the bundle is renewed.
+	// +kubebuilder:validation:Optional
+	CABundleRef *common.CABundleSource `json:"caBundleRef,omitempty"`
+
 	// Enabled controls whether this webhook is active
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=true
@@ -61,17 +105,26 @@ type WebhookObservation struct {
 
 	// Status indicates the current status of the webhook
 	Status *string `json:"status,omitempty"`
+
+	// CABundle is the CA bundle the policy was last saved with
+	CABundle *common.CABundleObservation `json:"caBundle,omitempty"`
 }
 
 // A WebhookSpec defines the desired state of a Webhook.
 type WebhookSpec struct {
 	xpv1.ManagedResourceSpec `json:",inline"`
 	ForProvider              WebhookParameters `json:"forProvider"`
+
+	// MaintenanceWindows are recurring periods during which the resource is
+	// observed but not changed in Harbor.
+	// +kubebuilder:validation:Optional
+	MaintenanceWindows []common.MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // A WebhookStatus represents the observed state of a Webhook.
 type WebhookStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
+	common.SyncStatus      `json:",inline"`
 	AtProvider             WebhookObservation `json:"atProvider,omitempty"`
 }
 
@@ -83,6 +136,8 @@ type WebhookStatus struct {
 // +kubebuilder:printcolumn:name="PROJECT",type="string",JSONPath=".spec.forProvider.projectId"
 // +kubebuilder:printcolumn:name="WEBHOOK",type="string",JSONPath=".spec.forProvider.name"
 // +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.forProvider.url"
+// +kubebuilder:printcolumn:name="LAST-SYNC",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="DRIFT",type="boolean",JSONPath=".status.drift"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Namespaced,categories={crossplane,managed,harbor}
 
@@ -104,11 +159,50 @@ type WebhookList struct {
 	Items           []Webhook `json:"items"`
 }
 
+// ResolveReferences of this Webhook.
+func (mg *Webhook) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ProjectID,
+		Reference:    mg.Spec.ForProvider.ProjectRef,
+		Selector:     mg.Spec.ForProvider.ProjectSelector,
+		To: reference.To{
+			Managed: &projectv1beta1.Project{},
+			List:    &projectv1beta1.ProjectList{},
+		},
+		Extract:   projectv1beta1.ProjectID(),
+		Namespace: mg.GetNamespace(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.ProjectID")
+	}
+	mg.Spec.ForProvider.ProjectID = rsp.ResolvedValue
+	mg.Spec.ForProvider.ProjectRef = rsp.ResolvedReference
+
+	return nil
+}
+
 // GetCondition of this Webhook.
 func (mg *Webhook) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return mg.Status.GetCondition(ct)
 }
 
+// GetCABundleSource of this Webhook.
+func (mg *Webhook) GetCABundleSource() *common.CABundleSource {
+	return mg.Spec.ForProvider.CABundleRef
+}
+
+// GetSyncStatus of this Webhook.
+func (mg *Webhook) GetSyncStatus() *common.SyncStatus {
+	return &mg.Status.SyncStatus
+}
+
+// GetMaintenanceWindows of this Webhook.
+func (mg *Webhook) GetMaintenanceWindows() []common.MaintenanceWindow {
+	return mg.Spec.MaintenanceWindows
+}
+
 // GetManagementPolicies of this Webhook.
 func (mg *Webhook) GetManagementPolicies() xpv1.ManagementPolicies {
 	return mg.Spec.ManagementPolicies
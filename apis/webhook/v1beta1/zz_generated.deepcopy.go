@@ -9,6 +9,8 @@ Copyright 2024 Crossplane Harbor Provider.
 package v1beta1
 
 import (
+	"github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -92,6 +94,11 @@ func (in *WebhookObservation) DeepCopyInto(out *WebhookObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(common.CABundleObservation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookObservation.
@@ -107,6 +114,16 @@ func (in *WebhookObservation) DeepCopy() *WebhookObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookParameters) DeepCopyInto(out *WebhookParameters) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(v2.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectSelector != nil {
+		in, out := &in.ProjectSelector, &out.ProjectSelector
+		*out = new(v2.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Description != nil {
 		in, out := &in.Description, &out.Description
 		*out = new(string)
@@ -117,6 +134,16 @@ func (in *WebhookParameters) DeepCopyInto(out *WebhookParameters) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.NotifyType != nil {
+		in, out := &in.NotifyType, &out.NotifyType
+		*out = new(string)
+		**out = **in
+	}
+	if in.PayloadFormat != nil {
+		in, out := &in.PayloadFormat, &out.PayloadFormat
+		*out = new(string)
+		**out = **in
+	}
 	if in.AuthHeader != nil {
 		in, out := &in.AuthHeader, &out.AuthHeader
 		*out = new(string)
@@ -127,6 +154,11 @@ func (in *WebhookParameters) DeepCopyInto(out *WebhookParameters) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CABundleRef != nil {
+		in, out := &in.CABundleRef, &out.CABundleRef
+		*out = new(common.CABundleSource)
+		**out = **in
+	}
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
@@ -149,6 +181,11 @@ func (in *WebhookSpec) DeepCopyInto(out *WebhookSpec) {
 	*out = *in
 	in.ManagedResourceSpec.DeepCopyInto(&out.ManagedResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]common.MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSpec.
@@ -165,6 +202,7 @@ func (in *WebhookSpec) DeepCopy() *WebhookSpec {
 func (in *WebhookStatus) DeepCopyInto(out *WebhookStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
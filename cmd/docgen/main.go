@@ -0,0 +1,44 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Command docgen renders the generated CRDs into the provider's API
+// reference, and embeds it in the package metadata.
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rossigee/provider-harbor/internal/docgen"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func main() {
+	var (
+		app     = kingpin.New(filepath.Base(os.Args[0]), "Generate the API reference of the Harbor provider.").DefaultEnvars()
+		crds    = app.Flag("crds", "Directory of generated CRDs.").Default("package/crds").ExistingDir()
+		output  = app.Flag("output", "File to write the API reference to, as YAML.").Default("docs/api-reference.yaml").String()
+		pkgMeta = app.Flag("package", "Package metadata to embed the API reference in. Empty to skip.").Default("package/crossplane.yaml").String()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	c, err := docgen.LoadCRDs(*crds)
+	kingpin.FatalIfError(err, "Cannot load CRDs")
+	ref := docgen.Build(c)
+
+	y, err := ref.YAML()
+	kingpin.FatalIfError(err, "Cannot render API reference")
+	kingpin.FatalIfError(os.WriteFile(*output, append([]byte(header), y...), 0o644), "Cannot write API reference")
+
+	if *pkgMeta == "" {
+		return
+	}
+	meta, err := os.ReadFile(filepath.Clean(*pkgMeta))
+	kingpin.FatalIfError(err, "Cannot read package metadata")
+	meta, err = docgen.Annotate(meta, ref)
+	kingpin.FatalIfError(err, "Cannot embed API reference")
+	kingpin.FatalIfError(os.WriteFile(*pkgMeta, meta, 0o644), "Cannot write package metadata")
+}
+
+const header = "# Code generated by cmd/docgen from package/crds. DO NOT EDIT.\n"
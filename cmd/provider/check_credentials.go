@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+// kindRequirement records the capability, if any, the Harbor account of a
+// managed resource's ProviderConfig needs for its kind to be reconciled. The
+// same capabilities are probed at runtime and recorded in each
+// ProviderConfig's status.capabilities. Projects need theirs only to be
+// created; existing projects can be managed without it.
+type kindRequirement struct {
+	kind       string
+	capability harborclients.Capability
+}
+
+var kindRequirements = []kindRequirement{
+	{kind: "Project", capability: harborclients.CapabilityCreateProjects},
+	{kind: "Member"},
+	{kind: "Repository"},
+	{kind: "Artifact"},
+	{kind: "ArtifactLabel"},
+	{kind: "Scan"},
+	{kind: "Robot"},
+	{kind: "Webhook"},
+	{kind: "ProjectScanner"},
+	{kind: "Retention"},
+	{kind: "ImmutableTagRule"},
+	{kind: "ProjectAuditLog"},
+	{kind: "ProjectSet"},
+	{kind: "Registry", capability: harborclients.CapabilityManageSystem},
+	{kind: "RegistryMirrorSet", capability: harborclients.CapabilityManageSystem},
+	{kind: "Replication", capability: harborclients.CapabilityManageSystem},
+	{kind: "ScannerRegistration", capability: harborclients.CapabilityManageSystem},
+	{kind: "ConfigSystem", capability: harborclients.CapabilityManageSystem},
+	{kind: "ConfigAuth", capability: harborclients.CapabilityManageSystem},
+	{kind: "GarbageCollectionSchedule", capability: harborclients.CapabilityManageSystem},
+	{kind: "ScanJob", capability: harborclients.CapabilityManageSystem},
+	{kind: "User", capability: harborclients.CapabilityManageUsers},
+	{kind: "UserGroup", capability: harborclients.CapabilityManageUsers},
+	{kind: "HarborRawResource", capability: harborclients.CapabilityManageSystem},
+}
+
+// checkCredentials logs in to Harbor with the credentials in secretFile and
+// writes a report of the server version, the account's privileges and the
+// resource kinds the provider can manage with them.
+func checkCredentials(ctx context.Context, w io.Writer, secretFile, url string, insecure bool) error {
+	data, err := os.ReadFile(secretFile)
+	if err != nil {
+		return errors.Wrap(err, "cannot read secret file")
+	}
+
+	cfg := &harborclients.HarborConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return errors.Wrap(err, "cannot parse secret file as JSON credentials")
+	}
+	if url != "" {
+		cfg.URL = url
+	}
+	if insecure {
+		cfg.Insecure = true
+	}
+
+	client, err := harborclients.NewHarborClient(cfg)
+	if err != nil {
+		return errors.Wrap(err, "cannot create Harbor client")
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	caps, err := harborclients.ProbeCapabilities(ctx, client)
+	if err != nil {
+		return errors.Wrap(err, "cannot log in to Harbor")
+	}
+
+	version, err := client.GetVersion(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot get Harbor version")
+	}
+
+	fmt.Fprintf(w, "Harbor URL:     %s\n", cfg.URL)
+	fmt.Fprintf(w, "Harbor version: %s\n", version)
+	fmt.Fprintf(w, "Logged in as:   %s\n", caps.Account)
+	fmt.Fprintf(w, "System admin:   %t\n\n", caps.SysAdmin)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tCAPABILITY\tSUPPORTED")
+	for _, k := range kindRequirements {
+		capability, supported := "-", "yes"
+		if k.capability != "" {
+			capability = string(k.capability)
+		}
+		switch {
+		case k.capability == "" || harborclients.HasCapability(caps, k.capability):
+		case k.capability == harborclients.CapabilityCreateProjects:
+			restriction := caps.ProjectCreationRestriction
+			if restriction == "" {
+				restriction = "unknown"
+			}
+			supported = "existing projects only (project creation restriction=" + restriction + ")"
+		default:
+			supported = "no (requires system admin)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", k.kind, capability, supported)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newFakeHarbor(t *testing.T, sysAdmin bool) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/users/current", func(w http.ResponseWriter, r *http.Request) {
+		if u, _, ok := r.BasicAuth(); !ok || u != "admin" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if sysAdmin {
+			_, _ = w.Write([]byte(`{"username":"admin","sysadmin_flag":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"username":"admin","sysadmin_flag":false}`))
+	})
+	mux.HandleFunc("/api/v2.0/systeminfo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"harbor_version":"v2.11.0"}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writeSecretFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "creds.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckCredentialsSysAdmin(t *testing.T) {
+	srv := newFakeHarbor(t, true)
+	path := writeSecretFile(t, `{"username":"admin","password":"secret"}`)
+
+	var out bytes.Buffer
+	if err := checkCredentials(context.Background(), &out, path, srv.URL, false); err != nil {
+		t.Fatalf("checkCredentials() error = %v", err)
+	}
+
+	report := out.String()
+	for _, want := range []string{"v2.11.0", "System admin:   true", "User"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "requires system admin") {
+		t.Errorf("system admin should support every kind:\n%s", report)
+	}
+}
+
+func TestCheckCredentialsProjectScoped(t *testing.T) {
+	srv := newFakeHarbor(t, false)
+	path := writeSecretFile(t, `{"url":"https://ignored.example.com","username":"admin","password":"secret"}`)
+
+	var out bytes.Buffer
+	if err := checkCredentials(context.Background(), &out, path, srv.URL, false); err != nil {
+		t.Fatalf("checkCredentials() error = %v", err)
+	}
+
+	report := out.String()
+	if !strings.Contains(report, "Harbor URL:     "+srv.URL) {
+		t.Errorf("--url should override the file URL:\n%s", report)
+	}
+	if !strings.Contains(report, "requires system admin") {
+		t.Errorf("non-admin report should flag admin-only kinds:\n%s", report)
+	}
+	if !strings.Contains(report, "existing projects only (project creation restriction=unknown)") {
+		t.Errorf("non-admin report should flag project creation:\n%s", report)
+	}
+}
+
+func TestCheckCredentialsLoginFailure(t *testing.T) {
+	srv := newFakeHarbor(t, true)
+	path := writeSecretFile(t, `{"username":"nobody","password":"wrong"}`)
+
+	var out bytes.Buffer
+	if err := checkCredentials(context.Background(), &out, path, srv.URL, false); err == nil {
+		t.Error("checkCredentials() should fail when Harbor rejects the credentials")
+	}
+}
+
+func TestCheckCredentialsInvalidFile(t *testing.T) {
+	path := writeSecretFile(t, `not json`)
+
+	var out bytes.Buffer
+	if err := checkCredentials(context.Background(), &out, path, "https://harbor.example.com", false); err == nil {
+		t.Error("checkCredentials() should fail on malformed credentials")
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package main
+
+import (
+	"sort"
+	"strings"
+
+	xpcontroller "github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/pkg/errors"
+	artifactcontroller "github.com/rossigee/provider-harbor/internal/controller/artifact"
+	artifactlabelcontroller "github.com/rossigee/provider-harbor/internal/controller/artifactlabel"
+	configcontroller "github.com/rossigee/provider-harbor/internal/controller/config"
+	configauthcontroller "github.com/rossigee/provider-harbor/internal/controller/configauth"
+	connectiontestcontroller "github.com/rossigee/provider-harbor/internal/controller/connectiontest"
+	gcschedulecontroller "github.com/rossigee/provider-harbor/internal/controller/gcschedule"
+	immutabletagrulecontroller "github.com/rossigee/provider-harbor/internal/controller/immutabletagrule"
+	membercontroller "github.com/rossigee/provider-harbor/internal/controller/member"
+	projectcontroller "github.com/rossigee/provider-harbor/internal/controller/project"
+	projectauditlogcontroller "github.com/rossigee/provider-harbor/internal/controller/projectauditlog"
+	projectscannercontroller "github.com/rossigee/provider-harbor/internal/controller/projectscanner"
+	projectsetcontroller "github.com/rossigee/provider-harbor/internal/controller/projectset"
+	rawresourcecontroller "github.com/rossigee/provider-harbor/internal/controller/rawresource"
+	registrycontroller "github.com/rossigee/provider-harbor/internal/controller/registry"
+	registrymirrorsetcontroller "github.com/rossigee/provider-harbor/internal/controller/registrymirrorset"
+	replicationcontroller "github.com/rossigee/provider-harbor/internal/controller/replication"
+	repositorycontroller "github.com/rossigee/provider-harbor/internal/controller/repository"
+	retentioncontroller "github.com/rossigee/provider-harbor/internal/controller/retention"
+	robotcontroller "github.com/rossigee/provider-harbor/internal/controller/robot"
+	scancontroller "github.com/rossigee/provider-harbor/internal/controller/scan"
+	scanjobcontroller "github.com/rossigee/provider-harbor/internal/controller/scanjob"
+	scannercontroller "github.com/rossigee/provider-harbor/internal/controller/scanner"
+	usercontroller "github.com/rossigee/provider-harbor/internal/controller/user"
+	usergroupcontroller "github.com/rossigee/provider-harbor/internal/controller/usergroup"
+	webhookcontroller "github.com/rossigee/provider-harbor/internal/controller/webhook"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// A kindController sets up the controller of one kind.
+type kindController struct {
+	kind  string
+	setup func(ctrl.Manager, xpcontroller.Options) error
+}
+
+// controllers are the provider's controllers, in the order they are set up.
+var controllers = []kindController{
+	{kind: "Project", setup: projectcontroller.Setup},
+	{kind: "Registry", setup: registrycontroller.Setup},
+	{kind: "Repository", setup: repositorycontroller.Setup},
+	{kind: "Artifact", setup: artifactcontroller.Setup},
+	{kind: "ArtifactLabel", setup: artifactlabelcontroller.Setup},
+	{kind: "Member", setup: membercontroller.Setup},
+	{kind: "Scan", setup: scancontroller.Setup},
+	{kind: "ScanJob", setup: scanjobcontroller.Setup},
+	{kind: "Robot", setup: robotcontroller.Setup},
+	{kind: "User", setup: usercontroller.Setup},
+	{kind: "UserGroup", setup: usergroupcontroller.Setup},
+	{kind: "ScannerRegistration", setup: scannercontroller.Setup},
+	{kind: "Webhook", setup: webhookcontroller.Setup},
+	{kind: "Replication", setup: replicationcontroller.Setup},
+	{kind: "Retention", setup: retentioncontroller.Setup},
+	{kind: "ImmutableTagRule", setup: immutabletagrulecontroller.Setup},
+	{kind: "ProjectScanner", setup: projectscannercontroller.Setup},
+	{kind: "ProjectAuditLog", setup: projectauditlogcontroller.Setup},
+	{kind: "ConfigSystem", setup: configcontroller.Setup},
+	{kind: "ConfigAuth", setup: configauthcontroller.Setup},
+	{kind: "GarbageCollectionSchedule", setup: gcschedulecontroller.Setup},
+	{kind: "RegistryMirrorSet", setup: registrymirrorsetcontroller.Setup},
+	{kind: "ProjectSet", setup: projectsetcontroller.Setup},
+	{kind: "HarborRawResource", setup: rawresourcecontroller.Setup},
+	{kind: "HarborConnectionTest", setup: connectiontestcontroller.Setup},
+}
+
+// splitKinds returns the kinds in flag values, each of which may be a comma
+// separated list.
+func splitKinds(values []string) []string {
+	var kinds []string
+	for _, v := range values {
+		for _, k := range strings.Split(v, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				kinds = append(kinds, k)
+			}
+		}
+	}
+	return kinds
+}
+
+// selectControllers returns the controllers of the enabled kinds, or of every
+// kind when enable is empty, less those of the disabled kinds. Kinds are
+// matched case insensitively.
+func selectControllers(enable, disable []string) ([]kindController, error) {
+	known := make(map[string]bool, len(controllers))
+	for _, c := range controllers {
+		known[strings.ToLower(c.kind)] = true
+	}
+	set := func(kinds []string) (map[string]bool, error) {
+		s := make(map[string]bool, len(kinds))
+		for _, k := range splitKinds(kinds) {
+			if !known[strings.ToLower(k)] {
+				return nil, errors.Errorf("unknown kind %q; known kinds are %s", k, strings.Join(controllerKinds(), ", "))
+			}
+			s[strings.ToLower(k)] = true
+		}
+		return s, nil
+	}
+	enabled, err := set(enable)
+	if err != nil {
+		return nil, err
+	}
+	disabled, err := set(disable)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []kindController
+	for _, c := range controllers {
+		k := strings.ToLower(c.kind)
+		if (len(enabled) == 0 || enabled[k]) && !disabled[k] {
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("every kind is disabled")
+	}
+	return selected, nil
+}
+
+// controllerKinds returns the kinds of every controller, sorted.
+func controllerKinds() []string {
+	kinds := make([]string, 0, len(controllers))
+	for _, c := range controllers {
+		kinds = append(kinds, c.kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectControllers(t *testing.T) {
+	cases := map[string]struct {
+		enable  []string
+		disable []string
+		want    []string
+		wantErr bool
+	}{
+		"Enable": {
+			enable: []string{"Project,robot", " Webhook "},
+			want:   []string{"Project", "Robot", "Webhook"},
+		},
+		"EnableAndDisable": {
+			enable:  []string{"Project", "Robot"},
+			disable: []string{"robot"},
+			want:    []string{"Project"},
+		},
+		"UnknownKind": {
+			enable:  []string{"Projects"},
+			wantErr: true,
+		},
+		"NothingLeft": {
+			enable:  []string{"Project"},
+			disable: []string{"Project"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			selected, err := selectControllers(tc.enable, tc.disable)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("selectControllers() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			var got []string
+			for _, c := range selected {
+				got = append(got, c.kind)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("selectControllers() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSelectControllersDisable(t *testing.T) {
+	selected, err := selectControllers(nil, []string{"Replication,RegistryMirrorSet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != len(controllers)-2 {
+		t.Errorf("selected %d controllers, want all %d but 2", len(selected), len(controllers))
+	}
+	for _, c := range selected {
+		if c.kind == "Replication" || c.kind == "RegistryMirrorSet" {
+			t.Errorf("selected disabled kind %s", c.kind)
+		}
+	}
+}
+
+// TestEveryManagedKindHasAController keeps the controllers and the
+// credential check's kinds in step.
+func TestEveryManagedKindHasAController(t *testing.T) {
+	have := map[string]bool{}
+	for _, c := range controllers {
+		have[c.kind] = true
+	}
+	for _, r := range kindRequirements {
+		if !have[r.kind] {
+			t.Errorf("no controller for kind %s", r.kind)
+		}
+	}
+}
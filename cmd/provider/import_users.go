@@ -0,0 +1,391 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	userv1beta1 "github.com/rossigee/provider-harbor/apis/user/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+// An importedUser is one user read from an export.
+type importedUser struct {
+	Username string
+	Email    string
+	Realname string
+	Comment  string
+	SysAdmin bool
+}
+
+// importOptions control the manifests generated for imported users.
+type importOptions struct {
+	Namespace         string
+	ProviderConfig    string
+	GeneratePasswords bool
+}
+
+// ldapAttributes name the LDAP attributes users are read from.
+type ldapAttributes struct {
+	Username string
+	Email    string
+	Realname string
+}
+
+// readUsersCSV reads users from CSV with a header row naming the columns
+// username, email, realname, comment and sysadmin. Only username and email
+// are required.
+func readUsersCSV(r io.Reader) ([]importedUser, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse CSV")
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("CSV has no header row")
+	}
+
+	col := map[string]int{}
+	for i, h := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, h := range []string{"username", "email"} {
+		if _, ok := col[h]; !ok {
+			return nil, errors.Errorf("CSV header has no %s column", h)
+		}
+	}
+	get := func(row []string, h string) string {
+		if i, ok := col[h]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	users := make([]importedUser, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		u := importedUser{
+			Username: get(row, "username"),
+			Email:    get(row, "email"),
+			Realname: get(row, "realname"),
+			Comment:  get(row, "comment"),
+		}
+		if v := get(row, "sysadmin"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Errorf("CSV line %d: sysadmin %q is not a boolean", n+2, v)
+			}
+			u.SysAdmin = b
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// readUsersLDIF reads users from an LDIF export, such as the output of
+// ldapsearch. Entries without the username attribute, like the search base,
+// are skipped.
+func readUsersLDIF(r io.Reader, attrs ldapAttributes) ([]importedUser, error) {
+	entries, err := parseLDIF(r)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]importedUser, 0, len(entries))
+	for _, e := range entries {
+		if e[strings.ToLower(attrs.Username)] == "" {
+			continue
+		}
+		users = append(users, importedUser{
+			Username: e[strings.ToLower(attrs.Username)],
+			Email:    e[strings.ToLower(attrs.Email)],
+			Realname: e[strings.ToLower(attrs.Realname)],
+			Comment:  "Imported from " + e["dn"],
+		})
+	}
+	return users, nil
+}
+
+// parseLDIF returns the first value of each attribute of each entry, keyed
+// by the lower-cased attribute name.
+func parseLDIF(r io.Reader) ([]map[string]string, error) {
+	var entries []map[string]string
+	entry := map[string]string{}
+	var lines []string
+
+	flush := func() error {
+		for _, l := range lines {
+			if strings.HasPrefix(l, "#") {
+				continue
+			}
+			name, value, ok := strings.Cut(l, ":")
+			if !ok {
+				return errors.Errorf("invalid LDIF line %q", l)
+			}
+			if strings.HasPrefix(value, ":") {
+				b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+				if err != nil {
+					return errors.Wrapf(err, "invalid base64 value of %s", name)
+				}
+				value = string(b)
+			}
+			name = strings.ToLower(name)
+			if _, ok := entry[name]; !ok {
+				entry[name] = strings.TrimSpace(value)
+			}
+		}
+		if len(entry) > 0 {
+			entries = append(entries, entry)
+		}
+		entry, lines = map[string]string{}, nil
+		return nil
+	}
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		l := s.Text()
+		switch {
+		case strings.TrimSpace(l) == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(l, " ") && len(lines) > 0:
+			// A folded line continues the previous one.
+			lines[len(lines)-1] += l[1:]
+		default:
+			lines = append(lines, l)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrap(err, "cannot read LDIF")
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// userObjectName returns a Kubernetes object name for a Harbor username.
+func userObjectName(username string) string {
+	n := invalidNameChars.ReplaceAllString(strings.ToLower(username), "-")
+	n = strings.Trim(n, "-")
+	if len(n) > 63 {
+		n = strings.TrimRight(n[:63], "-")
+	}
+	return n
+}
+
+// userManifests returns the User, and with generated passwords the password
+// Secret, for each imported user.
+func userManifests(users []importedUser, o importOptions) ([]client.Object, error) {
+	var objs []client.Object
+	seen := map[string]string{}
+	for i, u := range users {
+		if u.Username == "" || u.Email == "" {
+			return nil, errors.Errorf("user %d: username and email are required", i+1)
+		}
+		name := userObjectName(u.Username)
+		if name == "" {
+			return nil, errors.Errorf("user %q: cannot derive an object name", u.Username)
+		}
+		if other, ok := seen[name]; ok {
+			return nil, errors.Errorf("users %q and %q would both be named %s", other, u.Username, name)
+		}
+		seen[name] = u.Username
+
+		cr := &userv1beta1.User{
+			TypeMeta:   metav1.TypeMeta{APIVersion: userv1beta1.SchemeGroupVersion.String(), Kind: userv1beta1.UserKind},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: o.Namespace},
+			Spec: userv1beta1.UserSpec{
+				ManagedResourceSpec: xpv1.ManagedResourceSpec{
+					ProviderConfigReference: &xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: o.ProviderConfig},
+				},
+				ForProvider: userv1beta1.UserParameters{
+					Username:     u.Username,
+					Email:        u.Email,
+					Realname:     optional(u.Realname),
+					Comment:      optional(u.Comment),
+					SysAdminFlag: &u.SysAdmin,
+				},
+			},
+		}
+
+		if o.GeneratePasswords {
+			password, err := clients.GeneratePassword()
+			if err != nil {
+				return nil, err
+			}
+			secret := &corev1.Secret{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+				ObjectMeta: metav1.ObjectMeta{Name: name + "-password", Namespace: o.Namespace},
+				StringData: map[string]string{"password": password},
+			}
+			cr.Spec.ForProvider.PasswordSecretRef = &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: secret.Name, Namespace: o.Namespace},
+				Key:             "password",
+			}
+			objs = append(objs, secret)
+		}
+		objs = append(objs, cr)
+	}
+	return objs, nil
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// writeManifests writes objs as a multi-document YAML stream.
+func writeManifests(w io.Writer, objs []client.Object) error {
+	for _, o := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return errors.Wrap(err, "cannot convert manifest")
+		}
+		delete(u, "status")
+		if md, ok := u["metadata"].(map[string]interface{}); ok {
+			delete(md, "creationTimestamp")
+		}
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return errors.Wrap(err, "cannot encode manifest")
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyManifests creates or updates each User. Password Secrets are only
+// created, so that applying an import again does not change passwords, and
+// are owned by their User so that they are deleted with it.
+func applyManifests(ctx context.Context, w io.Writer, kube client.Client, objs []client.Object) error {
+	created := map[types.NamespacedName]bool{}
+	for _, o := range objs {
+		switch want := o.(type) {
+		case *corev1.Secret:
+			err := kube.Create(ctx, want.DeepCopy())
+			if kerrors.IsAlreadyExists(err) {
+				fmt.Fprintf(w, "secret/%s unchanged\n", want.Name)
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "cannot create Secret %s", want.Name)
+			}
+			created[types.NamespacedName{Namespace: want.Namespace, Name: want.Name}] = true
+			fmt.Fprintf(w, "secret/%s created\n", want.Name)
+		case *userv1beta1.User:
+			cr := &userv1beta1.User{ObjectMeta: metav1.ObjectMeta{Name: want.Name, Namespace: want.Namespace}}
+			res, err := controllerutil.CreateOrUpdate(ctx, kube, cr, func() error {
+				// Keep fields the import does not set, such as a
+				// password Secret or maintenance windows added since.
+				password := cr.Spec.ForProvider.PasswordSecretRef
+				cr.Spec.ProviderConfigReference = want.Spec.ProviderConfigReference
+				cr.Spec.ForProvider = want.Spec.ForProvider
+				if cr.Spec.ForProvider.PasswordSecretRef == nil {
+					cr.Spec.ForProvider.PasswordSecretRef = password
+				}
+				return nil
+			})
+			if err != nil {
+				return errors.Wrapf(err, "cannot apply User %s", want.Name)
+			}
+			fmt.Fprintf(w, "user.%s/%s %s\n", userv1beta1.Group, want.Name, res)
+			if ref := cr.Spec.ForProvider.PasswordSecretRef; ref != nil && created[types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}] {
+				if err := ownSecret(ctx, kube, cr, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ownSecret makes cr the owner of the password Secret created for it.
+func ownSecret(ctx context.Context, kube client.Client, cr *userv1beta1.User, key types.NamespacedName) error {
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, key, secret); err != nil {
+		return errors.Wrapf(err, "cannot get Secret %s", key.Name)
+	}
+	ctrlutil.SetOwner(secret, cr, userv1beta1.UserGroupVersionKind)
+	return errors.Wrapf(kube.Update(ctx, secret), "cannot set owner of Secret %s", key.Name)
+}
+
+// importUsers reads users from exactly one of csvFile and ldifFile and
+// writes their manifests to w, or with apply creates them in the cluster.
+func importUsers(ctx context.Context, w io.Writer, csvFile, ldifFile string, attrs ldapAttributes, o importOptions, apply bool) error {
+	if (csvFile == "") == (ldifFile == "") {
+		return errors.New("exactly one of --csv and --ldif is required")
+	}
+
+	var users []importedUser
+	var err error
+	if csvFile != "" {
+		users, err = readFile(csvFile, readUsersCSV)
+	} else {
+		users, err = readFile(ldifFile, func(r io.Reader) ([]importedUser, error) { return readUsersLDIF(r, attrs) })
+	}
+	if err != nil {
+		return err
+	}
+
+	objs, err := userManifests(users, o)
+	if err != nil {
+		return err
+	}
+	if !apply {
+		return writeManifests(w, objs)
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "cannot get API server rest config")
+	}
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		return errors.Wrap(err, "cannot add core APIs to scheme")
+	}
+	if err := userv1beta1.AddToScheme(s); err != nil {
+		return errors.Wrap(err, "cannot add User API to scheme")
+	}
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, "cannot create Kubernetes client")
+	}
+	return applyManifests(ctx, w, kube, objs)
+}
+
+func readFile(path string, read func(io.Reader) ([]importedUser, error)) ([]importedUser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot open export")
+	}
+	defer func() { _ = f.Close() }()
+	return read(f)
+}
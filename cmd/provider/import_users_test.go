@@ -0,0 +1,142 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	userv1beta1 "github.com/rossigee/provider-harbor/apis/user/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+const usersCSV = `Username,Email,RealName,SysAdmin
+alice,alice@example.com,Alice Liddell,true
+Bob.Smith,bob@example.com,,
+`
+
+const usersLDIF = `# search result
+dn: ou=people,dc=example,dc=com
+objectClass: organizationalUnit
+
+dn: uid=carol,ou=people,dc=example,dc=com
+uid: carol
+mail: carol@exam
+ ple.com
+cn:: Q2Fyb2wgRGFudmVycw==
+`
+
+func TestReadUsersCSV(t *testing.T) {
+	users, err := readUsersCSV(strings.NewReader(usersCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []importedUser{
+		{Username: "alice", Email: "alice@example.com", Realname: "Alice Liddell", SysAdmin: true},
+		{Username: "Bob.Smith", Email: "bob@example.com"},
+	}
+	if len(users) != len(want) || users[0] != want[0] || users[1] != want[1] {
+		t.Errorf("readUsersCSV() = %+v, want %+v", users, want)
+	}
+
+	if _, err := readUsersCSV(strings.NewReader("name,mail\nalice,alice@example.com\n")); err == nil {
+		t.Error("readUsersCSV() should fail without a username column")
+	}
+}
+
+func TestReadUsersLDIF(t *testing.T) {
+	users, err := readUsersLDIF(strings.NewReader(usersLDIF), ldapAttributes{Username: "uid", Email: "mail", Realname: "cn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("readUsersLDIF() = %+v, want only carol", users)
+	}
+	u := users[0]
+	if u.Username != "carol" || u.Email != "carol@example.com" || u.Realname != "Carol Danvers" {
+		t.Errorf("readUsersLDIF() = %+v", u)
+	}
+}
+
+func TestUserManifests(t *testing.T) {
+	users, _ := readUsersCSV(strings.NewReader(usersCSV))
+	objs, err := userManifests(users, importOptions{Namespace: "harbor", ProviderConfig: "harbor", GeneratePasswords: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 4 {
+		t.Fatalf("%d manifests, want a Secret and a User for each of 2 users", len(objs))
+	}
+
+	var out bytes.Buffer
+	if err := writeManifests(&out, objs); err != nil {
+		t.Fatal(err)
+	}
+	docs := strings.Split(strings.TrimPrefix(out.String(), "---\n"), "---\n")
+	bob := &userv1beta1.User{}
+	if err := yaml.UnmarshalStrict([]byte(docs[3]), bob); err != nil {
+		t.Fatal(err)
+	}
+	if bob.Name != "bob-smith" || bob.Spec.ForProvider.Username != "Bob.Smith" {
+		t.Errorf("User %s has username %s, want bob-smith for Bob.Smith", bob.Name, bob.Spec.ForProvider.Username)
+	}
+	if ref := bob.Spec.ForProvider.PasswordSecretRef; ref == nil || ref.Name != "bob-smith-password" || ref.Namespace != "harbor" {
+		t.Errorf("passwordSecretRef = %+v, want harbor/bob-smith-password", ref)
+	}
+	if strings.Contains(out.String(), "status") || strings.Contains(out.String(), "creationTimestamp") {
+		t.Errorf("manifests include server-set fields:\n%s", out.String())
+	}
+
+	dup := append(users, importedUser{Username: "bob_smith", Email: "b@example.com"})
+	if _, err := userManifests(dup, importOptions{}); err == nil {
+		t.Error("userManifests() should fail when two users map to the same name")
+	}
+}
+
+func TestApplyManifests(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = userv1beta1.AddToScheme(s)
+	kube := fake.NewClientBuilder().WithScheme(s).Build()
+	ctx := context.Background()
+
+	users, _ := readUsersCSV(strings.NewReader(usersCSV))
+	o := importOptions{Namespace: "harbor", ProviderConfig: "harbor", GeneratePasswords: true}
+	first, _ := userManifests(users, o)
+	if err := applyManifests(ctx, &bytes.Buffer{}, kube, first); err != nil {
+		t.Fatal(err)
+	}
+	key := types.NamespacedName{Namespace: "harbor", Name: "alice-password"}
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, key, secret); err != nil {
+		t.Fatal(err)
+	}
+	if refs := secret.GetOwnerReferences(); len(refs) != 1 || refs[0].Kind != userv1beta1.UserKind || refs[0].Name != "alice" {
+		t.Errorf("owner references = %+v, want the User alice", refs)
+	}
+
+	// Importing again keeps existing passwords.
+	again, _ := userManifests(users, o)
+	var out bytes.Buffer
+	if err := applyManifests(ctx, &out, kube, again); err != nil {
+		t.Fatal(err)
+	}
+	after := &corev1.Secret{}
+	if err := kube.Get(ctx, key, after); err != nil {
+		t.Fatal(err)
+	}
+	if after.StringData["password"] != secret.StringData["password"] {
+		t.Error("applying an import again changed a password")
+	}
+	if !strings.Contains(out.String(), "secret/alice-password unchanged") {
+		t.Errorf("output = %q, want the Secret reported unchanged", out.String())
+	}
+}
@@ -6,34 +6,42 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"strings"
+
+	xpcontroller "github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/rossigee/provider-harbor/apis"
-	artifactcontroller "github.com/rossigee/provider-harbor/internal/controller/artifact"
-	membercontroller "github.com/rossigee/provider-harbor/internal/controller/member"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	"github.com/rossigee/provider-harbor/internal/cloudevents"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
 	projectcontroller "github.com/rossigee/provider-harbor/internal/controller/project"
-	registrycontroller "github.com/rossigee/provider-harbor/internal/controller/registry"
-	replicationcontroller "github.com/rossigee/provider-harbor/internal/controller/replication"
-	repositorycontroller "github.com/rossigee/provider-harbor/internal/controller/repository"
-	retentioncontroller "github.com/rossigee/provider-harbor/internal/controller/retention"
+	providerconfigcontroller "github.com/rossigee/provider-harbor/internal/controller/providerconfig"
 	robotcontroller "github.com/rossigee/provider-harbor/internal/controller/robot"
-	scancontroller "github.com/rossigee/provider-harbor/internal/controller/scan"
-	scannercontroller "github.com/rossigee/provider-harbor/internal/controller/scanner"
-	usercontroller "github.com/rossigee/provider-harbor/internal/controller/user"
-	usergroupcontroller "github.com/rossigee/provider-harbor/internal/controller/usergroup"
-	webhookcontroller "github.com/rossigee/provider-harbor/internal/controller/webhook"
+	"github.com/rossigee/provider-harbor/internal/features"
+	"github.com/rossigee/provider-harbor/internal/health"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+	"github.com/rossigee/provider-harbor/internal/migration"
+	"github.com/rossigee/provider-harbor/internal/sweeper"
 	"github.com/rossigee/provider-harbor/internal/tracing"
+	"github.com/rossigee/provider-harbor/internal/vcr"
 	"github.com/rossigee/provider-harbor/internal/version"
 	"gopkg.in/alecthomas/kingpin.v2"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"os"
 	"path/filepath"
 	"runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	crlog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"strconv"
 	"time"
 )
 
@@ -48,15 +56,114 @@ func main() {
 		pollInterval     = app.Flag("poll", "Poll interval controls how often an individual resource should be checked for drift.").Default("10m").Duration()
 		leaderElection   = app.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
 		maxReconcileRate = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
+		startupJitter    = app.Flag("startup-jitter", "Spread the first reconcile of each resource over this window after startup so Harbor is not hit by every resource at once. Zero disables it.").Default("30s").Duration()
+		updateDebounce   = app.Flag("update-debounce", "Wait until the spec of a project, registry, replication, retention policy, webhook, configuration or raw resource has stayed unchanged this long before updating Harbor, so that edits applied in quick succession reach Harbor as one update. Zero updates Harbor at once.").Default(ctrlutil.DefaultUpdateDebounce.String()).Duration()
+		systemCacheAge   = app.Flag("system-cache-max-age", "How long Harbor system info and configurations are cached before being fetched again. Zero disables the cache.").Default("5m").Duration()
+		clientCacheAge   = app.Flag("client-cache-max-age", "How long the Harbor client of a ProviderConfig, with its pooled connections, is reused by the resources using it before it is built again. A change to the ProviderConfig or its secrets replaces it sooner. Zero disables the cache.").Default(harborclients.DefaultClientCacheMaxAge.String()).Duration()
+		skipUnchanged    = app.Flag("skip-unchanged-updates", "Skip updating a replication, retention policy, configuration or raw resource in Harbor when its spec, and Harbor's object as its status shows it, are unchanged since the provider last updated it, so that a comparison that never settles does not write the same payload on every poll.").Default("true").Bool()
+		strictFields     = app.Flag("strict-fields", "After writing system or authentication configurations or raw resources, read them back and fail the reconcile, setting the FieldsIgnored condition, when Harbor ignored any field written, as a Harbor older than the version that added the field does.").Bool()
+		inventoryPar     = app.Flag("inventory-parallelism", "How many pages of a large inventory, such as every repository of every project, are read from Harbor at once.").Default(strconv.Itoa(harborclients.DefaultInventoryParallelism)).Int()
+		sweepInterval    = app.Flag("orphan-sweep-interval", "How often to look for Harbor objects created by the provider that no longer have a managed resource. Zero disables the sweep.").Default("0").Duration()
+		sweepDelete      = app.Flag("orphan-sweep-delete", "Delete orphaned Harbor objects found by the sweep instead of only reporting them.").Bool()
+		protectCreds     = app.Flag("protect-credentials", "Protect the credentials Secret of each ProviderConfig with a Crossplane Usage so that it cannot be deleted while the ProviderConfig exists. Needs permission to manage usages.protection.crossplane.io.").Bool()
+		probeInterval    = app.Flag("capability-probe-interval", "How often to probe what the Harbor account of each ProviderConfig may do, recording it in the ProviderConfig's status.capabilities so that resources needing a capability the account lacks fail with a CapabilityMissing condition without calling Harbor. Zero disables the probe.").Default(providerconfigcontroller.DefaultCapabilityProbeInterval.String()).Duration()
+		usageGCInterval  = app.Flag("usage-gc-interval", "How often to delete ProviderConfigUsages whose managed resource no longer exists. Zero disables the garbage collection.").Default("1h").Duration()
+		quotaThreshold   = app.Flag("project-quota-threshold", "Set the QuotaExceeded condition on a Project, and emit a warning event, once it uses this percentage of one of its Harbor quotas. Zero disables the condition.").Default(strconv.Itoa(projectcontroller.DefaultQuotaThreshold)).Int()
+		robotExpiryWarn  = app.Flag("robot-expiry-warning", "Emit a warning event on a Robot this long before its robot account expires. Zero disables the events.").Default("168h").Duration()
+		migrateStorage   = app.Flag("migrate-storage-versions", "At startup, rewrite custom resources stored in an older API version in their CRD's storage version, then prune the CRD's stored versions. Needs permission to update CustomResourceDefinitions.").Default("true").Bool()
+		wqMaxDepth       = app.Flag("workqueue-max-depth", "Report a controller as degraded when its workqueue holds more requests than this for --workqueue-unhealthy-after.").Default(strconv.Itoa(health.DefaultMaxDepth)).Int()
+		wqMaxErrorRate   = app.Flag("workqueue-max-error-rate", "Report a controller as degraded when more than this fraction of its reconciles fail for --workqueue-unhealthy-after.").Default(strconv.FormatFloat(health.DefaultMaxErrorRate, 'f', -1, 64)).Float64()
+		wqUnhealthyAfter = app.Flag("workqueue-unhealthy-after", "How long a controller may exceed a workqueue threshold before it is reported as degraded. Zero disables the check.").Default(health.DefaultUnhealthyAfter.String()).Duration()
+		countInterval    = app.Flag("resource-count-interval", "How often to count managed resources by kind and condition for the harbor_managed_resources metric. Zero disables the count.").Default("1m").Duration()
+		statusConfigMap  = app.Flag("status-configmap", "Write each controller's workqueue depth and reconcile error count, and the Harbor client cache size, to this ConfigMap in --status-configmap-namespace every --status-interval, for debugging with kubectl where there is no Prometheus. Empty disables it.").String()
+		statusNamespace  = app.Flag("status-configmap-namespace", "Namespace of the --status-configmap ConfigMap, normally the namespace the provider runs in.").Default("crossplane-system").String()
+		statusInterval   = app.Flag("status-interval", "How often the --status-configmap ConfigMap is written.").Default("1m").Duration()
+		wqReadiness      = app.Flag("workqueue-readiness", "Fail the readiness check while any controller is degraded, not only export harbor_controller_degraded.").Bool()
+		enableKinds      = app.Flag("enable-kinds", "Only run the controllers of these kinds, as a comma separated list. May be repeated. Defaults to every kind.").Strings()
+		disableKinds     = app.Flag("disable-kinds", "Do not run the controllers of these kinds, as a comma separated list. May be repeated.").Strings()
+		ceSink           = app.Flag("cloudevents-sink", "Publish a CloudEvent to this HTTP URL whenever a managed resource is created, updated or deleted in Harbor, or drift is found there.").String()
+		ceSource         = app.Flag("cloudevents-source", "Source attribute of the published CloudEvents.").Default(cloudevents.DefaultSource).String()
+		recordHarborAPI  = app.Flag("record-harbor-api", "Development only: append every Harbor API request and response, with secrets redacted, to this file as a cassette that unit tests can replay.").String()
+		enableFeatures   = app.Flag("enable-feature", fmt.Sprintf("Enable an experimental feature. May be repeated. One of: %s.", strings.Join(features.Known(), ", "))).Strings()
+
+		_ = app.Command("start", "Start the provider controllers.").Default()
+
+		checkCmd        = app.Command("check-credentials", "Validate Harbor credentials offline and report what the provider can manage with them.")
+		checkSecretFile = checkCmd.Flag("secret-file", "Path to a JSON credentials file in the ProviderConfig secret format.").Required().ExistingFile()
+		checkURL        = checkCmd.Flag("url", "Harbor URL, overriding the url in the credentials file.").String()
+		checkInsecure   = checkCmd.Flag("insecure", "Skip TLS certificate verification.").Bool()
+
+		importCmd       = app.Command("import-users", "Generate User manifests from a CSV or LDIF export of existing users.")
+		importCSV       = importCmd.Flag("csv", "CSV file with a header row naming the columns username, email and optionally realname, comment and sysadmin.").ExistingFile()
+		importLDIF      = importCmd.Flag("ldif", "LDIF export of user entries, such as the output of ldapsearch.").ExistingFile()
+		importUserAttr  = importCmd.Flag("ldap-username-attribute", "LDAP attribute holding the Harbor username.").Default("uid").String()
+		importEmailAttr = importCmd.Flag("ldap-email-attribute", "LDAP attribute holding the email address.").Default("mail").String()
+		importNameAttr  = importCmd.Flag("ldap-realname-attribute", "LDAP attribute holding the real name.").Default("cn").String()
+		importNamespace = importCmd.Flag("namespace", "Namespace of the generated Users.").Default("default").String()
+		importPC        = importCmd.Flag("provider-config", "ProviderConfig the generated Users use.").Default("default").String()
+		importPasswords = importCmd.Flag("generate-passwords", "Generate a random password Secret for each User, as Harbor's database authentication requires.").Bool()
+		importApply     = importCmd.Flag("apply", "Create or update the Users in the current cluster instead of printing them.").Bool()
+
+		bundleCmd       = app.Command("support-bundle", "Collect redacted provider logs, summaries of the provider's resources, version information and Harbor system info into a tarball to attach to an issue.")
+		bundleOutput    = bundleCmd.Flag("output", "File to write the bundle to. Defaults to provider-harbor-support-<time>.tar.gz.").Short('o').String()
+		bundleNamespace = bundleCmd.Flag("namespace", "Namespace the provider runs in.").Default("crossplane-system").String()
+		bundleSelector  = bundleCmd.Flag("pod-selector", "Label selector of the provider pods whose logs are collected.").Default("pkg.crossplane.io/provider=provider-harbor").String()
+		bundleLogLines  = bundleCmd.Flag("log-lines", "How many of the most recent log lines to collect from each container.").Default("5000").Int64()
 	)
 
-	kingpin.MustParse(app.Parse(os.Args[1:]))
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case checkCmd.FullCommand():
+		kingpin.FatalIfError(checkCredentials(context.Background(), os.Stdout, *checkSecretFile, *checkURL, *checkInsecure), "Credential check failed")
+		return
+	case importCmd.FullCommand():
+		kingpin.FatalIfError(importUsers(context.Background(), os.Stdout, *importCSV, *importLDIF,
+			ldapAttributes{Username: *importUserAttr, Email: *importEmailAttr, Realname: *importNameAttr},
+			importOptions{Namespace: *importNamespace, ProviderConfig: *importPC, GeneratePasswords: *importPasswords},
+			*importApply), "User import failed")
+		return
+	case bundleCmd.FullCommand():
+		kingpin.FatalIfError(writeSupportBundle(context.Background(), os.Stdout, supportBundleOptions{
+			Output: *bundleOutput, Namespace: *bundleNamespace, Selector: *bundleSelector, LogLines: *bundleLogLines,
+		}), "Support bundle failed")
+		return
+	}
+
+	flags, err := features.Parse(*enableFeatures)
+	kingpin.FatalIfError(err, "Cannot parse --enable-feature")
+
+	selected, err := selectControllers(*enableKinds, *disableKinds)
+	kingpin.FatalIfError(err, "Cannot parse --enable-kinds and --disable-kinds")
+	kinds := make([]string, 0, len(selected))
+	for _, c := range selected {
+		kinds = append(kinds, c.kind)
+	}
+
+	harborclients.SetSystemCacheMaxAge(*systemCacheAge)
+	harborclients.SetClientCacheMaxAge(*clientCacheAge)
+	harborclients.SetInventoryParallelism(*inventoryPar)
+	robotcontroller.SetExpiryWarning(*robotExpiryWarn)
+	projectcontroller.SetQuotaThreshold(*quotaThreshold)
+	ctrlutil.SetUpdateDebounce(*updateDebounce)
+	ctrlutil.SetStrictFields(*strictFields)
+	ctrlutil.SetSkipUnchangedUpdates(*skipUnchanged)
 
 	zl := zap.New(zap.UseDevMode(*debug))
 	ctrl.SetLogger(zl)
 	crlog.SetLogger(zl)
 	log := logging.NewLogrLogger(zl.WithName("provider-harbor"))
 
+	clientOpts := []harborclients.Option{harborclients.WithUserAgent("provider-harbor/" + version.Version)}
+	if *debug {
+		clientOpts = append(clientOpts, harborclients.WithLogger(log))
+	}
+	if *recordHarborAPI != "" {
+		cassette, err := os.OpenFile(filepath.Clean(*recordHarborAPI), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		kingpin.FatalIfError(err, "Cannot open --record-harbor-api file")
+		defer func() { _ = cassette.Close() }()
+		clientOpts = append(clientOpts, harborclients.WithTransportWrapper(vcr.NewRecorder(cassette).Wrap))
+	}
+	harborclients.SetDefaultOptions(clientOpts...)
+
 	shutdownTracing := tracing.Init("provider-harbor")
 	defer shutdownTracing(context.Background())
 
@@ -68,8 +175,22 @@ func main() {
 		"sync-period", syncPeriod.String(),
 		"poll-interval", pollInterval.String(),
 		"max-reconcile-rate", *maxReconcileRate,
+		"startup-jitter", startupJitter.String(),
+		"system-cache-max-age", systemCacheAge.String(),
+		"update-debounce", updateDebounce.String(),
+		"robot-expiry-warning", robotExpiryWarn.String(),
+		"project-quota-threshold", *quotaThreshold,
+		"protect-credentials", *protectCreds,
+		"usage-gc-interval", usageGCInterval.String(),
+		"migrate-storage-versions", *migrateStorage,
+		"workqueue-unhealthy-after", wqUnhealthyAfter.String(),
+		"workqueue-readiness", *wqReadiness,
 		"leader-election", *leaderElection,
-		"debug-mode", *debug)
+		"debug-mode", *debug,
+		"features", *enableFeatures,
+		"cloudevents-sink", *ceSink,
+		"record-harbor-api", *recordHarborAPI,
+		"kinds", kinds)
 
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
@@ -93,52 +214,91 @@ func main() {
 	// Add Harbor APIs to scheme
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Harbor APIs to scheme")
 
+	ctrlutil.SetAvailabilityEvents(mgr.GetClient(), event.NewAPIRecorder(mgr.GetEventRecorder("harbor-availability")))
+
 	// Setup native controllers with rate limiting
-	o := controller.Options{
+	o := xpcontroller.Options{
+		Logger:                  log,
+		GlobalRateLimiter:       ctrlutil.NewStartupStagger(ratelimiter.NewGlobal(*maxReconcileRate), *startupJitter),
+		PollInterval:            *pollInterval,
 		MaxConcurrentReconciles: *maxReconcileRate,
+		Features:                flags,
 	}
 
-	// Setup Project controller
-	kingpin.FatalIfError(projectcontroller.Setup(mgr, o), "Cannot setup Project controller")
-
-	// Setup Registry controller
-	kingpin.FatalIfError(registrycontroller.Setup(mgr, o), "Cannot setup Registry controller")
-
-	// Setup Repository controller
-	kingpin.FatalIfError(repositorycontroller.Setup(mgr, o), "Cannot setup Repository controller")
-
-	// Setup Artifact controller
-	kingpin.FatalIfError(artifactcontroller.Setup(mgr, o), "Cannot setup Artifact controller")
+	for _, c := range selected {
+		kingpin.FatalIfError(c.setup(mgr, o), "Cannot setup %s controller", c.kind)
+	}
 
-	// Setup Member controller
-	kingpin.FatalIfError(membercontroller.Setup(mgr, o), "Cannot setup Member controller")
+	if *protectCreds {
+		kingpin.FatalIfError(providerconfigcontroller.SetupCredentialProtection(mgr, o), "Cannot setup ProviderConfig credential protection")
+	}
 
-	// Setup Scan controller
-	kingpin.FatalIfError(scancontroller.Setup(mgr, o), "Cannot setup Scan controller")
+	if *probeInterval > 0 {
+		kingpin.FatalIfError(providerconfigcontroller.SetupCapabilityProbe(mgr, o, *probeInterval), "Cannot setup ProviderConfig capability probe")
+	}
 
-	// Setup Robot controller
-	kingpin.FatalIfError(robotcontroller.Setup(mgr, o), "Cannot setup Robot controller")
+	if *migrateStorage {
+		kingpin.FatalIfError(extv1.AddToScheme(mgr.GetScheme()), "Cannot add CustomResourceDefinitions to scheme")
+		// Read CRDs and every stored object directly rather than caching them.
+		direct, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+		kingpin.FatalIfError(err, "Cannot create storage version migration client")
+		kingpin.FatalIfError(mgr.Add(migration.New(direct,
+			migration.WithLogger(log.WithValues("component", "storage-version-migration")))), "Cannot add storage version migration")
+	}
 
-	// Setup User controller
-	kingpin.FatalIfError(usercontroller.Setup(mgr, o), "Cannot setup User controller")
+	if *sweepInterval > 0 {
+		kingpin.FatalIfError(mgr.Add(sweeper.New(mgr.GetClient(),
+			sweeper.WithLogger(log.WithValues("component", "orphan-sweeper")),
+			sweeper.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder("orphan-sweeper"))),
+			sweeper.WithInterval(*sweepInterval),
+			sweeper.WithDelete(*sweepDelete))), "Cannot add orphan sweeper")
+	}
 
-	// Setup UserGroup controller
-	kingpin.FatalIfError(usergroupcontroller.Setup(mgr, o), "Cannot setup UserGroup controller")
+	if *usageGCInterval > 0 {
+		kingpin.FatalIfError(mgr.Add(providerconfigcontroller.NewUsageJanitor(mgr.GetClient(),
+			providerconfigcontroller.WithJanitorLogger(log.WithValues("component", "usage-janitor")),
+			providerconfigcontroller.WithJanitorInterval(*usageGCInterval))), "Cannot add ProviderConfigUsage janitor")
+	}
 
-	// Setup Scanner controller
-	kingpin.FatalIfError(scannercontroller.Setup(mgr, o), "Cannot setup Scanner controller")
+	kingpin.FatalIfError(mgr.AddHealthzCheck("healthz", healthz.Ping), "Cannot add health check")
+	kingpin.FatalIfError(mgr.AddReadyzCheck("readyz", healthz.Ping), "Cannot add ready check")
 
-	// Setup Webhook controller
-	kingpin.FatalIfError(webhookcontroller.Setup(mgr, o), "Cannot setup Webhook controller")
+	if *wqUnhealthyAfter > 0 {
+		wq := health.NewWorkqueueMonitor(
+			health.WithLogger(log.WithValues("component", "workqueue-health")),
+			health.WithMaxDepth(*wqMaxDepth),
+			health.WithMaxErrorRate(*wqMaxErrorRate),
+			health.WithUnhealthyAfter(*wqUnhealthyAfter))
+		kingpin.FatalIfError(mgr.Add(wq), "Cannot add workqueue health monitor")
+		if *wqReadiness {
+			kingpin.FatalIfError(mgr.AddReadyzCheck("workqueue", wq.Check), "Cannot add workqueue ready check")
+		}
+	}
 
-	// Setup Replication controller
-	kingpin.FatalIfError(replicationcontroller.Setup(mgr, o), "Cannot setup Replication controller")
+	if *statusConfigMap != "" {
+		// Write directly rather than through the cache, which would watch
+		// every ConfigMap in the cluster.
+		direct, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+		kingpin.FatalIfError(err, "Cannot create provider status client")
+		kingpin.FatalIfError(mgr.Add(health.NewStatusWriter(direct, types.NamespacedName{Namespace: *statusNamespace, Name: *statusConfigMap},
+			health.WithStatusLogger(log.WithValues("component", "status-configmap")),
+			health.WithStatusInterval(*statusInterval))), "Cannot add provider status writer")
+	}
 
-	// Setup Retention controller
-	kingpin.FatalIfError(retentioncontroller.Setup(mgr, o), "Cannot setup Retention controller")
+	if *ceSink != "" {
+		emitter := cloudevents.NewEmitter(*ceSink,
+			cloudevents.WithSource(*ceSource),
+			cloudevents.WithLogger(log.WithValues("component", "cloudevents")))
+		kingpin.FatalIfError(mgr.Add(emitter), "Cannot add CloudEvents emitter")
+		ctrlutil.SetLifecycleEmitter(emitter)
+	}
 
-	kingpin.FatalIfError(mgr.AddHealthzCheck("healthz", healthz.Ping), "Cannot add health check")
-	kingpin.FatalIfError(mgr.AddReadyzCheck("readyz", healthz.Ping), "Cannot add ready check")
+	if *countInterval > 0 {
+		kingpin.FatalIfError(mgr.Add(metrics.NewResourceCounter(mgr.GetClient(), mgr.GetScheme(),
+			metrics.WithLogger(log.WithValues("component", "resource-counter")),
+			metrics.WithKinds(kinds...),
+			metrics.WithInterval(*countInterval))), "Cannot add managed resource counter")
+	}
 
 	log.Info("All controllers initialized, starting manager")
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
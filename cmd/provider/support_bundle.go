@@ -0,0 +1,330 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis"
+	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	"github.com/rossigee/provider-harbor/internal/vcr"
+	"github.com/rossigee/provider-harbor/internal/version"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// supportBundleGroupSuffix selects the kinds whose resources are summarised
+// in a support bundle.
+const supportBundleGroupSuffix = "harbor.m.crossplane.io"
+
+// Log lines are redacted line by line: credentials in URLs, Authorization
+// headers, and the values of fields named like a secret, whether written as
+// JSON or as key=value.
+var (
+	redactURLUser   = regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`)
+	redactAuthz     = regexp.MustCompile(`(?i)(authorization"?\s*[:=]\s*"?(?:basic|bearer)\s+)[^"\s]+`)
+	redactSecretVal = regexp.MustCompile(`(?i)("?[\w.-]*(?:password|secret|token|credential|access_key)[\w.-]*"?\s*[:=]\s*"?)[^"\s,}]+`)
+)
+
+// redactLine removes credentials from one line of a log or message.
+func redactLine(s string) string {
+	s = redactURLUser.ReplaceAllString(s, "${1}"+vcr.Redacted+"@")
+	s = redactAuthz.ReplaceAllString(s, "${1}"+vcr.Redacted)
+	return redactSecretVal.ReplaceAllString(s, "${1}"+vcr.Redacted)
+}
+
+// redact removes credentials from every line of b.
+func redact(b []byte) []byte {
+	lines := strings.Split(string(b), "\n")
+	for i, l := range lines {
+		lines[i] = redactLine(l)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// A resourceSummary describes a custom resource without its spec, which may
+// hold or point at secrets.
+type resourceSummary struct {
+	Kind           string             `json:"kind"`
+	Namespace      string             `json:"namespace,omitempty"`
+	Name           string             `json:"name"`
+	ExternalName   string             `json:"externalName,omitempty"`
+	ProviderConfig string             `json:"providerConfig,omitempty"`
+	Generation     int64              `json:"generation,omitempty"`
+	Deleting       bool               `json:"deleting,omitempty"`
+	Conditions     []conditionSummary `json:"conditions,omitempty"`
+}
+
+type conditionSummary struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// summarize reads the fields of a resourceSummary from u.
+func summarize(u *unstructured.Unstructured) resourceSummary {
+	s := resourceSummary{
+		Kind:         u.GetKind(),
+		Namespace:    u.GetNamespace(),
+		Name:         u.GetName(),
+		ExternalName: meta.GetExternalName(u),
+		Generation:   u.GetGeneration(),
+		Deleting:     u.GetDeletionTimestamp() != nil,
+	}
+	s.ProviderConfig, _, _ = unstructured.NestedString(u.Object, "spec", "providerConfigRef", "name")
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		str := func(k string) string { v, _ := m[k].(string); return v }
+		s.Conditions = append(s.Conditions, conditionSummary{
+			Type:               str("type"),
+			Status:             str("status"),
+			Reason:             str("reason"),
+			Message:            redactLine(str("message")),
+			LastTransitionTime: str("lastTransitionTime"),
+		})
+	}
+	return s
+}
+
+// A supportBundle collects what is needed to triage a problem with the
+// provider. Each part that cannot be collected is recorded in errors.txt
+// rather than ending the bundle.
+type supportBundle struct {
+	kube      client.Client
+	scheme    *kruntime.Scheme
+	podLogs   func(ctx context.Context) (map[string][]byte, error)
+	newClient func(ctx context.Context, kube client.Client, providerConfig string) (harborclients.HarborClienter, error)
+	now       func() time.Time
+}
+
+// write writes the bundle to w as a gzipped tarball.
+func (b *supportBundle) write(ctx context.Context, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := b.now()
+	var failed []string
+
+	add := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: now}); err != nil {
+			return errors.Wrapf(err, "cannot add %s to support bundle", name)
+		}
+		_, err := tw.Write(data)
+		return errors.Wrapf(err, "cannot add %s to support bundle", name)
+	}
+
+	v := fmt.Sprintf("version: %s\ngo-version: %s\nplatform: %s/%s\ncollected: %s\n",
+		version.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH, now.UTC().Format(time.RFC3339))
+	if err := add("version.txt", []byte(v)); err != nil {
+		return err
+	}
+
+	logs, err := b.podLogs(ctx)
+	if err != nil {
+		failed = append(failed, "provider logs: "+err.Error())
+	}
+	names := make([]string, 0, len(logs))
+	for n := range logs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := add("logs/"+n+".log", redact(logs[n])); err != nil {
+			return err
+		}
+	}
+
+	summaries, errs := b.resources(ctx)
+	failed = append(failed, errs...)
+	data, err := yaml.Marshal(summaries)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal resource summaries")
+	}
+	if err := add("resources.yaml", data); err != nil {
+		return err
+	}
+
+	for _, s := range summaries {
+		if s.Kind != providerconfigv1beta1.ProviderConfigKind {
+			continue
+		}
+		info, err := b.systemInfo(ctx, s.Name)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("systeminfo of ProviderConfig %s: %v", s.Name, err))
+			continue
+		}
+		if err := add("systeminfo/"+s.Name+".json", info); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		if err := add("errors.txt", redact([]byte(strings.Join(failed, "\n")+"\n"))); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "cannot close support bundle")
+	}
+	return errors.Wrap(gz.Close(), "cannot close support bundle")
+}
+
+// resources summarises every resource of the provider's kinds, sorted by
+// kind, namespace and name. Kinds that cannot be listed are reported.
+func (b *supportBundle) resources(ctx context.Context) ([]resourceSummary, []string) {
+	var (
+		summaries []resourceSummary
+		failed    []string
+	)
+	for gvk := range b.scheme.AllKnownTypes() {
+		if !strings.HasSuffix(gvk.Group, supportBundleGroupSuffix) || !strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk)
+		if err := b.kube.List(ctx, l); err != nil {
+			failed = append(failed, fmt.Sprintf("cannot list %s: %v", strings.TrimSuffix(gvk.Kind, "List"), err))
+			continue
+		}
+		for i := range l.Items {
+			summaries = append(summaries, summarize(&l.Items[i]))
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, c := summaries[i], summaries[j]
+		if a.Kind != c.Kind {
+			return a.Kind < c.Kind
+		}
+		if a.Namespace != c.Namespace {
+			return a.Namespace < c.Namespace
+		}
+		return a.Name < c.Name
+	})
+	sort.Strings(failed)
+	return summaries, failed
+}
+
+// systemInfo returns the Harbor system info seen through the named
+// ProviderConfig, as indented JSON.
+func (b *supportBundle) systemInfo(ctx context.Context, providerConfig string) ([]byte, error) {
+	svc, err := b.newClient(ctx, b.kube, providerConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = svc.Close() }()
+	info, err := svc.GetSystemInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(info, "", "  ")
+}
+
+// clusterPodLogs returns a function that reads the last lines of the logs of
+// every container of the pods matching selector in namespace.
+func clusterPodLogs(cs kubernetes.Interface, namespace, selector string, lines int64) func(ctx context.Context) (map[string][]byte, error) {
+	return func(ctx context.Context) (map[string][]byte, error) {
+		pods, err := cs.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot list provider pods in namespace %s", namespace)
+		}
+		if len(pods.Items) == 0 {
+			return nil, errors.Errorf("no pods match %q in namespace %s", selector, namespace)
+		}
+		logs := map[string][]byte{}
+		var failed []string
+		for _, p := range pods.Items {
+			for _, c := range p.Spec.Containers {
+				data, err := cs.CoreV1().Pods(namespace).GetLogs(p.Name, &corev1.PodLogOptions{Container: c.Name, TailLines: &lines}).DoRaw(ctx)
+				if err != nil {
+					failed = append(failed, fmt.Sprintf("%s/%s: %v", p.Name, c.Name, err))
+					continue
+				}
+				logs[p.Name+"/"+c.Name] = data
+			}
+		}
+		if len(failed) > 0 {
+			return logs, errors.Errorf("cannot read logs of %s", strings.Join(failed, "; "))
+		}
+		return logs, nil
+	}
+}
+
+// supportBundleOptions control what a support bundle collects.
+type supportBundleOptions struct {
+	Output    string
+	Namespace string
+	Selector  string
+	LogLines  int64
+}
+
+// writeSupportBundle collects a support bundle from the current cluster and
+// writes it to o.Output, reporting the file written to w.
+func writeSupportBundle(ctx context.Context, w io.Writer, o supportBundleOptions) error {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "cannot get API server rest config")
+	}
+	s := kruntime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		return errors.Wrap(err, "cannot add provider APIs to scheme")
+	}
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, "cannot create Kubernetes client")
+	}
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "cannot create Kubernetes clientset")
+	}
+
+	b := &supportBundle{
+		kube:      kube,
+		scheme:    s,
+		podLogs:   clusterPodLogs(cs, o.Namespace, o.Selector, o.LogLines),
+		newClient: harborclients.NewHarborClientForProviderConfig,
+		now:       time.Now,
+	}
+	out := o.Output
+	if out == "" {
+		out = "provider-harbor-support-" + b.now().UTC().Format("20060102-150405") + ".tar.gz"
+	}
+
+	var buf bytes.Buffer
+	if err := b.write(ctx, &buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Clean(out), buf.Bytes(), 0o600); err != nil {
+		return errors.Wrapf(err, "cannot write %s", out)
+	}
+	_, err = fmt.Fprintf(w, "Wrote support bundle to %s\n", out)
+	return err
+}
@@ -1,3 +1,9 @@
+//go:build upjet
+
+// The configuration of the Terraform-based provider is kept for reference
+// and only builds with the upjet tag, so that it never enters the native
+// provider's build.
+
 package user
 
 // ExternalName handling for Harbor users.
@@ -1,3 +1,5 @@
+//go:build upjet
+
 package user
 
 import (
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package capabilities reports which optional Harbor features an instance
+// supports, based on the version it reports in /systeminfo.
+package capabilities
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A Capability is an optional Harbor feature the provider can use.
+type Capability string
+
+// Capabilities that depend on the Harbor version.
+const (
+	// AutoSBOMGeneration is the auto_sbom_generation project setting.
+	AutoSBOMGeneration Capability = "AutoSBOMGeneration"
+)
+
+// minVersions is the first Harbor release that supports each capability.
+var minVersions = map[Capability]Version{
+	AutoSBOMGeneration: {Major: 2, Minor: 10},
+}
+
+// A Version is a Harbor release version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// AtLeast reports whether v is the same as or newer than o.
+func (v Version) AtLeast(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major > o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor > o.Minor
+	}
+	return v.Patch >= o.Patch
+}
+
+func (v Version) String() string {
+	return "v" + strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch)
+}
+
+// ParseVersion parses Harbor version strings such as "v2.10.0-b7d6ec6b".
+func ParseVersion(s string) (Version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, false
+	}
+	var n [3]int
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 {
+			return Version{}, false
+		}
+		n[i] = v
+	}
+	return Version{Major: n[0], Minor: n[1], Patch: n[2]}, true
+}
+
+// MinVersion returns the first Harbor version supporting c.
+func MinVersion(c Capability) Version {
+	return minVersions[c]
+}
+
+// Supports reports whether a Harbor reporting harborVersion supports c.
+// Versions that cannot be parsed, such as those of development builds, are
+// assumed to support everything.
+func Supports(harborVersion string, c Capability) bool {
+	v, ok := ParseVersion(harborVersion)
+	if !ok {
+		return true
+	}
+	return v.AtLeast(minVersions[c])
+}
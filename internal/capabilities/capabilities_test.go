@@ -0,0 +1,38 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package capabilities
+
+import "testing"
+
+func TestSupports(t *testing.T) {
+	cases := map[string]struct {
+		version string
+		want    bool
+	}{
+		"Older":         {version: "v2.9.1-2c1b1a2e", want: false},
+		"Exact":         {version: "v2.10.0-b7d6ec6b", want: true},
+		"NewerMinor":    {version: "v2.11.0", want: true},
+		"NewerMajor":    {version: "v3.0", want: true},
+		"Unparseable":   {version: "dev", want: true},
+		"OlderNoPrefix": {version: "1.10.17", want: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Supports(tc.version, AutoSBOMGeneration); got != tc.want {
+				t.Errorf("Supports(%q) = %v, want %v", tc.version, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	v, ok := ParseVersion("v2.10.3-b7d6ec6b")
+	if !ok || v != (Version{Major: 2, Minor: 10, Patch: 3}) {
+		t.Errorf("ParseVersion() = %v, %v", v, ok)
+	}
+	if _, ok := ParseVersion("v2"); ok {
+		t.Error("ParseVersion(\"v2\") succeeded, want failure")
+	}
+}
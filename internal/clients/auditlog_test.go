@@ -0,0 +1,43 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListProjectAuditLogs(t *testing.T) {
+	since := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/team-a/logs", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("page_size") != "5" || q.Get("sort") != "-op_time" || !strings.HasPrefix(q.Get("q"), "op_time=[2024-05-02 00:00:00~") {
+			t.Errorf("audit logs requested with query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": 9, "operation": "delete", "resource": "team-a/web:1.0", "resource_type": "artifact", "username": "alice", "op_time": "2024-05-03T10:00:00Z"},
+			{"id": 8, "operation": "create", "resource": "team-a/web:1.0", "resource_type": "artifact", "username": "ci", "op_time": "2024-05-02T09:00:00Z"},
+			{"id": 7, "operation": "pull", "resource": "team-a/web:0.9", "resource_type": "artifact", "username": "bob", "op_time": "2024-05-01T23:00:00Z"}
+		]`))
+	})
+	c := executionsClient(t, mux)
+
+	got, err := c.ListProjectAuditLogs(context.Background(), "team-a", since, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ListProjectAuditLogs() returned %d entries, want the 2 inside the window", len(got))
+	}
+	if e := got[0]; e.Operation != "delete" || e.Resource != "team-a/web:1.0" || e.ResourceType != "artifact" || e.Username != "alice" ||
+		!e.Time.Equal(time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("entry = %+v", e)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Backoff of the circuit breaker kept for each Harbor endpoint. It starts at
+// unavailableBackoff after the first 503 response and doubles with each
+// further one, up to maxUnavailableBackoff.
+const (
+	unavailableBackoff    = 15 * time.Second
+	maxUnavailableBackoff = 5 * time.Minute
+)
+
+// An UnavailableError is returned instead of sending a request to a Harbor
+// endpoint that answered 503 Service Unavailable, as Harbor does while it is
+// being upgraded or is otherwise in maintenance.
+type UnavailableError struct {
+	// Endpoint is the URL of the Harbor instance.
+	Endpoint string
+	// RetryAt is when requests to the endpoint are sent again.
+	RetryAt time.Time
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("Harbor at %s is unavailable (503 Service Unavailable); retrying after %s", e.Endpoint, e.RetryAt.UTC().Format(time.RFC3339))
+}
+
+// IsUnavailable reports whether err is a Harbor API 503 response, or a
+// request refused because its endpoint recently sent one.
+func IsUnavailable(err error) bool {
+	var ue *UnavailableError
+	return errors.As(err, &ue) || hasStatusCode(err, http.StatusServiceUnavailable)
+}
+
+// A breaker stops requests to one Harbor endpoint for a while after it
+// answers 503, so that an upgrade is not met with every managed resource
+// retrying at once.
+type breaker struct {
+	failures int
+	retryAt  time.Time
+}
+
+// breakers holds the breaker of each endpoint, shared by every client.
+var breakers = struct {
+	mu  sync.Mutex
+	now func() time.Time
+	by  map[string]*breaker
+}{now: time.Now, by: map[string]*breaker{}}
+
+// EndpointRetryAt returns when requests to endpoint are sent again, or the
+// zero time if it is available.
+func EndpointRetryAt(endpoint string) time.Time {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+	if b, ok := breakers.by[endpoint]; ok && breakers.now().Before(b.retryAt) {
+		return b.retryAt
+	}
+	return time.Time{}
+}
+
+// unavailable records a 503 response from endpoint and returns when requests
+// are sent to it again.
+func unavailable(endpoint string) time.Time {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+	b, ok := breakers.by[endpoint]
+	if !ok {
+		b = &breaker{}
+		breakers.by[endpoint] = b
+	}
+	backoff := unavailableBackoff << b.failures
+	if backoff <= 0 || backoff > maxUnavailableBackoff {
+		backoff = maxUnavailableBackoff
+	}
+	b.failures++
+	b.retryAt = breakers.now().Add(backoff)
+	return b.retryAt
+}
+
+// available records any other response from endpoint.
+func available(endpoint string) {
+	breakers.mu.Lock()
+	defer breakers.mu.Unlock()
+	delete(breakers.by, endpoint)
+}
+
+// breakerTransport refuses requests to an endpoint whose breaker is open,
+// and opens it when the endpoint answers 503.
+type breakerTransport struct {
+	next     http.RoundTripper
+	endpoint string
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if retryAt := EndpointRetryAt(t.endpoint); !retryAt.IsZero() {
+		return nil, &UnavailableError{Endpoint: t.endpoint, RetryAt: retryAt}
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		available(t.endpoint)
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return nil, &UnavailableError{Endpoint: t.endpoint, RetryAt: unavailable(t.endpoint)}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	sdkping "github.com/goharbor/go-client/pkg/sdk/v2.0/client/ping"
+	"github.com/pkg/errors"
+)
+
+func TestBreakerTransport(t *testing.T) {
+	now := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	breakers.mu.Lock()
+	breakers.now = func() time.Time { return now }
+	breakers.mu.Unlock()
+	t.Cleanup(func() {
+		breakers.mu.Lock()
+		breakers.now = time.Now
+		breakers.mu.Unlock()
+	})
+
+	maintenance := true
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/ping", func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		if maintenance {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("Pong"))
+	})
+	c := executionsClient(t, mux)
+	ping := func() error {
+		_, err := c.clientSet.V2().Ping.GetPing(context.Background(), &sdkping.GetPingParams{Context: context.Background()})
+		return err
+	}
+
+	err := ping()
+	var ue *UnavailableError
+	if !errors.As(err, &ue) || !IsUnavailable(errors.Wrap(err, "cannot ping")) {
+		t.Fatalf("ping during maintenance returned %v, want an UnavailableError", err)
+	}
+	if want := now.Add(unavailableBackoff); !ue.RetryAt.Equal(want) {
+		t.Errorf("RetryAt = %s, want %s", ue.RetryAt, want)
+	}
+
+	// While the breaker is open, requests are refused without reaching Harbor.
+	if err := ping(); !IsUnavailable(err) || requests != 1 {
+		t.Errorf("ping with the breaker open returned %v after %d requests, want a refusal after 1", err, requests)
+	}
+
+	// A further 503 doubles the backoff.
+	now = now.Add(unavailableBackoff)
+	if err := ping(); !errors.As(err, &ue) || !ue.RetryAt.Equal(now.Add(2*unavailableBackoff)) {
+		t.Errorf("second 503 returned %v, want a retry after %s", err, 2*unavailableBackoff)
+	}
+
+	// Harbor recovering closes the breaker.
+	maintenance = false
+	now = now.Add(2 * unavailableBackoff)
+	if err := ping(); err != nil {
+		t.Fatalf("ping after maintenance returned %v", err)
+	}
+	if got := EndpointRetryAt(c.GetBaseURL()); !got.IsZero() {
+		t.Errorf("EndpointRetryAt() = %s after recovery, want zero", got)
+	}
+}
+
+func TestUnavailableBackoffIsCapped(t *testing.T) {
+	endpoint := "https://capped.example.com"
+	t.Cleanup(func() { available(endpoint) })
+	var retryAt time.Time
+	for i := 0; i < 80; i++ {
+		retryAt = unavailable(endpoint)
+	}
+	if d := time.Until(retryAt); d > maxUnavailableBackoff || d < maxUnavailableBackoff-time.Minute {
+		t.Errorf("backoff after many 503s = %s, want %s", d, maxUnavailableBackoff)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A Capability is something a Harbor account may or may not be allowed to
+// do, as recorded in a ProviderConfig's status.capabilities.
+type Capability string
+
+// Capabilities managed resource kinds need.
+const (
+	CapabilityCreateProjects Capability = "CreateProjects"
+	CapabilityManageUsers    Capability = "ManageUsers"
+	CapabilityManageSystem   Capability = "ManageSystem"
+)
+
+// ProjectCreationEveryone is the project creation restriction that lets
+// every account create projects.
+const ProjectCreationEveryone = "everyone"
+
+// ProbeCapabilities finds out what the account c logs in with may do. It
+// only reads from Harbor. Harbor grants user management and system-wide
+// objects to system administrators alone, and project creation also to
+// everyone when its project creation restriction allows it. Only admins may
+// read configurations, so the copy of the restriction in system info is the
+// fallback.
+func ProbeCapabilities(ctx context.Context, c HarborClienter) (*providerconfigv1beta1.ProviderConfigCapabilities, error) {
+	user, err := c.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get current Harbor account")
+	}
+
+	restriction := ""
+	if cfg, err := c.GetConfigurations(ctx); err == nil {
+		restriction, _ = cfg["project_creation_restriction"].(string)
+	}
+	if restriction == "" {
+		if info, err := c.GetSystemInfo(ctx); err == nil && info != nil {
+			restriction = info.ProjectCreationRestriction
+		}
+	}
+
+	return &providerconfigv1beta1.ProviderConfigCapabilities{
+		Account:                    user.Username,
+		SysAdmin:                   user.SysAdmin,
+		CreateProjects:             user.SysAdmin || restriction == ProjectCreationEveryone,
+		ManageUsers:                user.SysAdmin,
+		ManageSystem:               user.SysAdmin,
+		ProjectCreationRestriction: restriction,
+		ProbedAt:                   metav1.Now(),
+	}, nil
+}
+
+// HasCapability reports whether caps grant capability.
+func HasCapability(caps *providerconfigv1beta1.ProviderConfigCapabilities, capability Capability) bool {
+	switch capability {
+	case CapabilityCreateProjects:
+		return caps.CreateProjects
+	case CapabilityManageUsers:
+		return caps.ManageUsers
+	case CapabilityManageSystem:
+		return caps.ManageSystem
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestProbeCapabilities(t *testing.T) {
+	cases := map[string]struct {
+		user        CurrentUser
+		config      Configurations
+		info        *SystemInfo
+		wantCreate  bool
+		wantManage  bool
+		restriction string
+	}{
+		"SysAdmin": {
+			user:        CurrentUser{Username: "admin", SysAdmin: true},
+			config:      Configurations{"project_creation_restriction": "adminonly"},
+			wantCreate:  true,
+			wantManage:  true,
+			restriction: "adminonly",
+		},
+		"UserWhenEveryoneMayCreate": {
+			user:        CurrentUser{Username: "ci"},
+			info:        &SystemInfo{ProjectCreationRestriction: "everyone"},
+			wantCreate:  true,
+			restriction: "everyone",
+		},
+		"UserWhenOnlyAdminsMayCreate": {
+			user:        CurrentUser{Username: "ci"},
+			info:        &SystemInfo{ProjectCreationRestriction: "adminonly"},
+			restriction: "adminonly",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &MockHarborClient{
+				GetCurrentUserFunc: func(context.Context) (*CurrentUser, error) { return &tc.user, nil },
+				GetConfigurationsFunc: func(context.Context) (Configurations, error) {
+					if tc.config == nil {
+						return nil, errors.New("forbidden")
+					}
+					return tc.config, nil
+				},
+				GetSystemInfoFunc: func(context.Context) (*SystemInfo, error) { return tc.info, nil },
+			}
+			caps, err := ProbeCapabilities(context.Background(), c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if caps.Account != tc.user.Username || caps.SysAdmin != tc.user.SysAdmin {
+				t.Errorf("account = %s, sysAdmin = %t", caps.Account, caps.SysAdmin)
+			}
+			if caps.CreateProjects != tc.wantCreate || caps.ProjectCreationRestriction != tc.restriction {
+				t.Errorf("createProjects = %t, restriction = %q, want %t, %q", caps.CreateProjects, caps.ProjectCreationRestriction, tc.wantCreate, tc.restriction)
+			}
+			if caps.ManageUsers != tc.wantManage || caps.ManageSystem != tc.wantManage {
+				t.Errorf("manageUsers = %t, manageSystem = %t, want %t", caps.ManageUsers, caps.ManageSystem, tc.wantManage)
+			}
+			if HasCapability(caps, CapabilityManageSystem) != tc.wantManage || HasCapability(caps, CapabilityCreateProjects) != tc.wantCreate {
+				t.Error("HasCapability disagrees with the probed capabilities")
+			}
+		})
+	}
+
+	c := &MockHarborClient{GetCurrentUserFunc: func(context.Context) (*CurrentUser, error) { return nil, errors.New("unauthorized") }}
+	if _, err := ProbeCapabilities(context.Background(), c); err == nil {
+		t.Error("ProbeCapabilities() should fail when the account cannot be read")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DefaultClientCacheMaxAge is how long a Harbor client built for a
+// ProviderConfig is reused before it is built again.
+const DefaultClientCacheMaxAge = 10 * time.Minute
+
+// clientCache holds the Harbor client of each ProviderConfig, so that the
+// managed resources using it share one client set and its pooled
+// connections rather than building them, and shaking hands with Harbor
+// again, on every reconcile. An entry is only reused while the credentials
+// and TLS settings it was built from are unchanged.
+type clientCache struct {
+	mu      sync.Mutex
+	maxAge  time.Duration
+	now     func() time.Time
+	entries map[string]*clientCacheEntry
+}
+
+type clientCacheEntry struct {
+	fingerprint string
+	client      *HarborClient
+	createdAt   time.Time
+}
+
+var sharedClientCache = newClientCache(DefaultClientCacheMaxAge)
+
+func newClientCache(maxAge time.Duration) *clientCache {
+	return &clientCache{
+		maxAge:  maxAge,
+		now:     time.Now,
+		entries: map[string]*clientCacheEntry{},
+	}
+}
+
+// SetClientCacheMaxAge changes how long the Harbor client of a ProviderConfig
+// is reused. A zero or negative age disables the cache.
+func SetClientCacheMaxAge(d time.Duration) {
+	sharedClientCache.mu.Lock()
+	sharedClientCache.maxAge = d
+	sharedClientCache.mu.Unlock()
+	if d <= 0 {
+		sharedClientCache.reset()
+	}
+}
+
+// get returns the cached client of the named ProviderConfig if it was built
+// from a config with the fingerprint given and has not expired.
+func (cc *clientCache) get(name, fingerprint string) *HarborClient {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	e, ok := cc.entries[name]
+	if !ok || e.fingerprint != fingerprint || !cc.fresh(e.createdAt) {
+		return nil
+	}
+	return e.client
+}
+
+// put caches c as the client of the named ProviderConfig, replacing any
+// client built before, and drops expired entries. It does nothing when the
+// cache is disabled.
+func (cc *clientCache) put(name, fingerprint string, c *HarborClient) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.maxAge <= 0 {
+		return
+	}
+	c.shared = true
+	if old, ok := cc.entries[name]; ok {
+		old.client.release()
+	}
+	cc.entries[name] = &clientCacheEntry{fingerprint: fingerprint, client: c, createdAt: cc.now()}
+	for n, e := range cc.entries {
+		if !cc.fresh(e.createdAt) {
+			e.client.release()
+			delete(cc.entries, n)
+		}
+	}
+}
+
+// reset drops every cached client, so that clients built afterwards pick up
+// changed defaults.
+func (cc *clientCache) reset() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	for n, e := range cc.entries {
+		e.client.release()
+		delete(cc.entries, n)
+	}
+}
+
+func (cc *clientCache) len() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return len(cc.entries)
+}
+
+func (cc *clientCache) fresh(at time.Time) bool {
+	return cc.maxAge > 0 && cc.now().Sub(at) < cc.maxAge
+}
+
+// fingerprint identifies the credentials and TLS settings of c, so that a
+// cached client is replaced once either changes.
+func (c *HarborConfig) fingerprint() string {
+	b, _ := json.Marshal(struct {
+		HarborConfig
+		TLS *TLSConfig `json:"tls"`
+	}{*c, c.TLS})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
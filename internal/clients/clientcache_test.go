@@ -0,0 +1,105 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClientCache(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = providerconfigv1beta1.SchemeBuilder.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "harbor"},
+		Data: map[string][]byte{
+			"url":      []byte("https://harbor.example.com"),
+			"username": []byte("admin"),
+			"password": []byte("Harbor12345"),
+		},
+	}
+	pc := &providerconfigv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: providerconfigv1beta1.ProviderConfigSpec{
+			Credentials: providerconfigv1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "harbor"},
+					},
+				},
+			},
+		},
+	}
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, pc).Build()
+
+	now := time.Now()
+	old := sharedClientCache
+	sharedClientCache = newClientCache(time.Minute)
+	sharedClientCache.now = func() time.Time { return now }
+	t.Cleanup(func() { sharedClientCache = old })
+
+	ctx := context.Background()
+	get := func() HarborClienter {
+		t.Helper()
+		c, err := NewHarborClientForProviderConfig(ctx, kube, "default")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	first := get()
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if get() != first {
+		t.Error("client not reused")
+	}
+	if got := Stats().CachedClients; got != 1 {
+		t.Errorf("Stats().CachedClients = %d, want 1", got)
+	}
+
+	secret.Data["password"] = []byte("rotated")
+	if err := kube.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	second := get()
+	if second == first {
+		t.Error("client reused after its secret changed")
+	}
+
+	pc.Spec.TLS = &providerconfigv1beta1.ProviderConfigTLS{MinVersion: TLSVersion13}
+	if err := kube.Update(ctx, pc); err != nil {
+		t.Fatal(err)
+	}
+	third := get()
+	if third == second {
+		t.Error("client reused after its TLS settings changed")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if get() == third {
+		t.Error("client reused after it expired")
+	}
+
+	SetClientCacheMaxAge(0)
+	if get() == get() {
+		t.Error("client reused with the cache disabled")
+	}
+	if got := sharedClientCache.len(); got != 0 {
+		t.Errorf("%d clients cached with the cache disabled", got)
+	}
+}
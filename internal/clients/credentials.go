@@ -6,12 +6,301 @@ package clients
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// DefaultCredentialsKey is the secret key read for a JSON credentials
+// document when the ProviderConfig does not name one.
+const DefaultCredentialsKey = "credentials"
+
+// Secret keys used when credentials are stored one field per key.
+const (
+	CredentialsKeyURL      = "url"
+	CredentialsKeyUsername = "username"
+	CredentialsKeyPassword = "password"
+	CredentialsKeyInsecure = "insecure"
+
+	CredentialsKeyRobotName   = "robotName"
+	CredentialsKeyRobotSecret = "robotSecret"
+	CredentialsKeyToken       = "token"
+	CredentialsKeyAuthType    = "authType"
+)
+
+// How the provider authenticates to Harbor.
+const (
+	// AuthTypeBasic logs in with a user's username and password.
+	AuthTypeBasic = "Basic"
+	// AuthTypeRobot logs in with a robot account's name and secret.
+	AuthTypeRobot = "Robot"
+	// AuthTypeToken sends a bearer token, such as an OIDC ID token.
+	AuthTypeToken = "Token"
+)
+
+// A FieldError reports a credentials field that is missing or invalid.
+type FieldError struct {
+	// Field is the credentials field, such as url or insecure.
+	Field string
+
+	// Reason says what is wrong with it, such as "is required".
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Reason
+}
+
+func fieldErrorf(field, format string, a ...any) error {
+	return &FieldError{Field: field, Reason: fmt.Sprintf(format, a...)}
+}
+
+// HarborConfig holds configuration for creating a Harbor client. A robot
+// account logs in like a user, so its name and secret are held in Username
+// and Password.
+type HarborConfig struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Token    string `json:"token,omitempty"`
+	Insecure bool   `json:"insecure"`
+
+	// AuthType is one of Basic, Robot and Token. When empty it is Token if
+	// only a token is set, Robot if only a robot account is, and Basic
+	// otherwise.
+	AuthType string `json:"authType,omitempty"`
+
+	// TLS, when set, configures the connection beyond Insecure. It comes
+	// from the ProviderConfig rather than the credentials secret.
+	TLS *TLSConfig `json:"-"`
+}
+
+// UnmarshalJSON accepts insecure as either a JSON boolean or a string such as
+// "true", since secrets written by hand or by templating tools use both.
+func (c *HarborConfig) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		URL         string          `json:"url"`
+		Username    string          `json:"username"`
+		Password    string          `json:"password"`
+		RobotName   string          `json:"robotName"`
+		RobotSecret string          `json:"robotSecret"`
+		Token       string          `json:"token"`
+		Insecure    json.RawMessage `json:"insecure"`
+		AuthType    string          `json:"authType"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	insecure := false
+	if len(raw.Insecure) > 0 && string(raw.Insecure) != "null" {
+		var b bool
+		var s string
+		switch {
+		case json.Unmarshal(raw.Insecure, &b) == nil:
+			insecure = b
+		case json.Unmarshal(raw.Insecure, &s) == nil:
+			v, err := parseInsecure(s)
+			if err != nil {
+				return err
+			}
+			insecure = v
+		default:
+			return fieldErrorf(CredentialsKeyInsecure, "must be a boolean, got %s", raw.Insecure)
+		}
+	}
+
+	*c = HarborConfig{
+		URL:      strings.TrimSpace(raw.URL),
+		Username: strings.TrimSpace(raw.Username),
+		Password: raw.Password,
+		Token:    strings.TrimSpace(raw.Token),
+		Insecure: insecure,
+		AuthType: strings.TrimSpace(raw.AuthType),
+	}
+	c.setRobot(strings.TrimSpace(raw.RobotName), raw.RobotSecret)
+	return nil
+}
+
+// setRobot records the name and secret of a robot account, unless a user's
+// username is set too.
+func (c *HarborConfig) setRobot(name, secret string) {
+	if name == "" && secret == "" {
+		return
+	}
+	if c.Username == "" && c.Password == "" {
+		c.Username, c.Password = name, secret
+		if c.AuthType == "" {
+			c.AuthType = AuthTypeRobot
+		}
+	}
+}
+
+// EffectiveAuthType returns how c authenticates: AuthType, or the type its
+// credentials imply when that is empty.
+func (c *HarborConfig) EffectiveAuthType() string {
+	switch {
+	case c.AuthType != "":
+		return c.AuthType
+	case c.Token != "" && c.Username == "":
+		return AuthTypeToken
+	default:
+		return AuthTypeBasic
+	}
+}
+
+// Validate checks that the fields needed to connect to Harbor are set and
+// that the URL is an http or https URL. Its errors are FieldErrors.
+func (c *HarborConfig) Validate() error {
+	if c.URL == "" {
+		return fieldErrorf(CredentialsKeyURL, "is required")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fieldErrorf(CredentialsKeyURL, "must be an http or https URL such as https://harbor.example.com, got %q", c.URL)
+	}
+	switch t := c.EffectiveAuthType(); t {
+	case AuthTypeBasic:
+		if c.Username == "" {
+			return fieldErrorf(CredentialsKeyUsername, "is required")
+		}
+		if c.Password == "" {
+			return fieldErrorf(CredentialsKeyPassword, "is required")
+		}
+	case AuthTypeRobot:
+		if c.Username == "" {
+			return fieldErrorf(CredentialsKeyRobotName, "is required")
+		}
+		if c.Password == "" {
+			return fieldErrorf(CredentialsKeyRobotSecret, "is required")
+		}
+	case AuthTypeToken:
+		if c.Token == "" {
+			return fieldErrorf(CredentialsKeyToken, "is required")
+		}
+	default:
+		return fieldErrorf(CredentialsKeyAuthType, "must be one of %s, %s and %s, got %q", AuthTypeBasic, AuthTypeRobot, AuthTypeToken, t)
+	}
+	return nil
+}
+
+// ParseCredentials parses a JSON credentials document of the form
+// {"url": ..., "username": ..., "password": ..., "insecure": ...}. A robot
+// account's credentials are given as robotName and robotSecret, and a bearer
+// token as token.
+func ParseCredentials(data []byte) (*HarborConfig, error) {
+	return parseCredentials(data, "")
+}
+
+// parseCredentials parses a JSON credentials document, authenticating as
+// authType when it is set.
+func parseCredentials(data []byte, authType string) (*HarborConfig, error) {
+	cfg := &HarborConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, errors.Wrap(err, "cannot parse credentials JSON")
+	}
+	if authType != "" {
+		cfg.AuthType = authType
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// CredentialsFromSecret reads Harbor credentials from a secret, which may hold
+// either a JSON document under a single key or one field per key (url,
+// username, password, robotName, robotSecret, token and optionally insecure).
+// When key is set it must hold a JSON document. Otherwise the "credentials"
+// key is used if present, then the only key of a secret holding just one
+// JSON document, falling back to separate keys. When authType is set it
+// decides which credentials are used; otherwise the secret does.
+func CredentialsFromSecret(secret *corev1.Secret, key, authType string) (*HarborConfig, error) {
+	if key == "" {
+		key = credentialsKey(secret.Data)
+	}
+	if key == "" {
+		return credentialsFromKeys(secret.Data, authType)
+	}
+
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, errors.Errorf("key %q not found in credentials secret", key)
+	}
+	cfg, err := parseCredentials(data, authType)
+	return cfg, errors.Wrapf(err, "invalid credentials in key %q", key)
+}
+
+// credentialsKey returns the key of data holding a JSON credentials
+// document, or "" when the credentials are stored one field per key. Secret
+// management tools that write one document per system name its key after
+// the system, so a secret with a single key holding a JSON object is read
+// as one.
+func credentialsKey(data map[string][]byte) string {
+	if _, ok := data[DefaultCredentialsKey]; ok {
+		return DefaultCredentialsKey
+	}
+	if len(data) != 1 {
+		return ""
+	}
+	for k, v := range data {
+		switch k {
+		case CredentialsKeyURL, CredentialsKeyUsername, CredentialsKeyPassword, CredentialsKeyInsecure,
+			CredentialsKeyRobotName, CredentialsKeyRobotSecret, CredentialsKeyToken, CredentialsKeyAuthType:
+			return ""
+		}
+		if strings.HasPrefix(strings.TrimSpace(string(v)), "{") {
+			return k
+		}
+	}
+	return ""
+}
+
+func credentialsFromKeys(data map[string][]byte, authType string) (*HarborConfig, error) {
+	cfg := &HarborConfig{
+		URL:      strings.TrimSpace(string(data[CredentialsKeyURL])),
+		Username: strings.TrimSpace(string(data[CredentialsKeyUsername])),
+		Password: string(data[CredentialsKeyPassword]),
+		Token:    strings.TrimSpace(string(data[CredentialsKeyToken])),
+		AuthType: strings.TrimSpace(string(data[CredentialsKeyAuthType])),
+	}
+	cfg.setRobot(strings.TrimSpace(string(data[CredentialsKeyRobotName])), string(data[CredentialsKeyRobotSecret]))
+	if authType != "" {
+		cfg.AuthType = authType
+	}
+	if v, ok := data[CredentialsKeyInsecure]; ok {
+		insecure, err := parseInsecure(string(v))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Insecure = insecure
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "no %q key in credentials secret and separate keys are incomplete", DefaultCredentialsKey)
+	}
+	return cfg, nil
+}
+
+func parseInsecure(s string) (bool, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fieldErrorf(CredentialsKeyInsecure, "must be a boolean, got %q", s)
+	}
+	return v, nil
+}
+
 // GetCredentialsFromSecret retrieves credentials from a Kubernetes secret
 func GetCredentialsFromSecret(ctx context.Context, k8sClient client.Client, secretRef xpv1.SecretReference) (*corev1.Secret, error) {
 	secret := &corev1.Secret{}
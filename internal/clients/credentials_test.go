@@ -0,0 +1,242 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	configv1beta1 "github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	scannerv1beta1 "github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseCredentials(t *testing.T) {
+	cases := map[string]struct {
+		data    string
+		want    HarborConfig
+		wantErr string
+	}{
+		"BoolInsecure": {
+			data: `{"url":"https://h","username":"admin","password":"p","insecure":true}`,
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p", Insecure: true},
+		},
+		"StringInsecure": {
+			data: `{"url":"https://h","username":"admin","password":"p","insecure":"true"}`,
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p", Insecure: true},
+		},
+		"StringInsecureFalse": {
+			data: `{"url":"https://h","username":"admin","password":"p","insecure":"False"}`,
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p"},
+		},
+		"NullInsecure": {
+			data: `{"url":"https://h","username":"admin","password":"p","insecure":null}`,
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p"},
+		},
+		"NoInsecure": {
+			data: `{"url":"https://h","username":"admin","password":"p"}`,
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p"},
+		},
+		"BadInsecure": {
+			data:    `{"url":"https://h","username":"admin","password":"p","insecure":"maybe"}`,
+			wantErr: `insecure must be a boolean, got "maybe"`,
+		},
+		"NumericInsecure": {
+			data:    `{"url":"https://h","username":"admin","password":"p","insecure":1}`,
+			wantErr: "insecure must be a boolean, got 1",
+		},
+		"MissingPassword": {
+			data:    `{"url":"https://h","username":"admin"}`,
+			wantErr: "password is required",
+		},
+		"PaddedURL": {
+			data: `{"url":" https://h\n","username":"admin","password":"p"}`,
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p"},
+		},
+		"NoScheme": {
+			data:    `{"url":"harbor.example.com","username":"admin","password":"p"}`,
+			wantErr: `url must be an http or https URL such as https://harbor.example.com, got "harbor.example.com"`,
+		},
+		"OtherScheme": {
+			data:    `{"url":"ftp://h","username":"admin","password":"p"}`,
+			wantErr: `url must be an http or https URL`,
+		},
+		"NotJSON": {
+			data:    `url: https://h`,
+			wantErr: "cannot parse credentials JSON",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseCredentials([]byte(tc.data))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("ParseCredentials() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tc.want {
+				t.Errorf("ParseCredentials() = %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCredentialsFromSecret(t *testing.T) {
+	doc := `{"url":"https://h","username":"admin","password":"p","insecure":"true"}`
+
+	cases := map[string]struct {
+		data     map[string]string
+		key      string
+		authType string
+		want     HarborConfig
+		wantErr  string
+	}{
+		"DefaultJSONKey": {
+			data: map[string]string{"credentials": doc},
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p", Insecure: true},
+		},
+		"NamedJSONKey": {
+			data: map[string]string{"harbor.json": doc, "credentials": "ignored"},
+			key:  "harbor.json",
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p", Insecure: true},
+		},
+		"SingleJSONKey": {
+			data: map[string]string{"harbor-prod": "\n" + doc},
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p", Insecure: true},
+		},
+		"SingleInvalidJSONKey": {
+			data:    map[string]string{"harbor-prod": `{"url":"https://h","username":"admin"}`},
+			wantErr: `invalid credentials in key "harbor-prod": password is required`,
+		},
+		"SingleSeparateKey": {
+			data:    map[string]string{"password": "{p"},
+			wantErr: "url is required",
+		},
+		"NamedKeyMissing": {
+			data:    map[string]string{"credentials": doc},
+			key:     "harbor.json",
+			wantErr: `key "harbor.json" not found`,
+		},
+		"InvalidJSONKey": {
+			data:    map[string]string{"credentials": `{"url":"https://h"}`},
+			wantErr: `invalid credentials in key "credentials": username is required`,
+		},
+		"SeparateKeys": {
+			data: map[string]string{"url": "https://h\n", "username": "admin", "password": "p"},
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p"},
+		},
+		"SeparateKeysInsecure": {
+			data: map[string]string{"url": "https://h", "username": "admin", "password": "p", "insecure": "1"},
+			want: HarborConfig{URL: "https://h", Username: "admin", Password: "p", Insecure: true},
+		},
+		"SeparateKeysBadInsecure": {
+			data:    map[string]string{"url": "https://h", "username": "admin", "password": "p", "insecure": "yes"},
+			wantErr: `insecure must be a boolean, got "yes"`,
+		},
+		"SeparateKeysIncomplete": {
+			data:    map[string]string{"url": "https://h", "username": "admin"},
+			wantErr: "password is required",
+		},
+		"RobotKeys": {
+			data: map[string]string{"url": "https://h", "robotName": "robot$ci", "robotSecret": "s"},
+			want: HarborConfig{URL: "https://h", Username: "robot$ci", Password: "s", AuthType: AuthTypeRobot},
+		},
+		"RobotKeysIncomplete": {
+			data:    map[string]string{"url": "https://h", "robotName": "robot$ci"},
+			wantErr: "robotSecret is required",
+		},
+		"RobotJSONKey": {
+			data: map[string]string{"credentials": `{"url":"https://h","robotName":"robot$ci","robotSecret":"s"}`},
+			want: HarborConfig{URL: "https://h", Username: "robot$ci", Password: "s", AuthType: AuthTypeRobot},
+		},
+		"TokenKeys": {
+			data: map[string]string{"url": "https://h", "token": "t\n"},
+			want: HarborConfig{URL: "https://h", Token: "t"},
+		},
+		"TokenJSONKey": {
+			data: map[string]string{"credentials": `{"url":"https://h","token":"t","authType":"Token"}`},
+			want: HarborConfig{URL: "https://h", Token: "t", AuthType: AuthTypeToken},
+		},
+		"AuthTypeFromProviderConfig": {
+			data:     map[string]string{"url": "https://h", "username": "admin", "password": "p", "token": "t"},
+			authType: AuthTypeToken,
+			want:     HarborConfig{URL: "https://h", Username: "admin", Password: "p", Token: "t", AuthType: AuthTypeToken},
+		},
+		"AuthTypeMissingToken": {
+			data:     map[string]string{"credentials": doc},
+			authType: AuthTypeToken,
+			wantErr:  `invalid credentials in key "credentials": token is required`,
+		},
+		"UnknownAuthType": {
+			data:    map[string]string{"url": "https://h", "token": "t", "authType": "OIDC"},
+			wantErr: `authType must be one of Basic, Robot and Token, got "OIDC"`,
+		},
+		"Empty": {
+			data:    map[string]string{},
+			wantErr: "url is required",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &corev1.Secret{Data: map[string][]byte{}}
+			for k, v := range tc.data {
+				s.Data[k] = []byte(v)
+			}
+			got, err := CredentialsFromSecret(s, tc.key, tc.authType)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("CredentialsFromSecret() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != tc.want {
+				t.Errorf("CredentialsFromSecret() = %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewHarborClientFromProviderConfigAnyKind(t *testing.T) {
+	kube := fake.NewClientBuilder().Build()
+	for _, mg := range []resource.Managed{&configv1beta1.ConfigSystem{}, &scannerv1beta1.ProjectScanner{}} {
+		_, err := NewHarborClientFromProviderConfig(context.Background(), kube, mg)
+		if err == nil || err.Error() != errNoProviderConfig {
+			t.Errorf("%T: error = %v, want %q", mg, err, errNoProviderConfig)
+		}
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	cases := map[string]struct {
+		data  string
+		field string
+	}{
+		"URL":      {data: `{"url":"h","username":"admin","password":"p"}`, field: CredentialsKeyURL},
+		"Username": {data: `{"url":"https://h","password":"p"}`, field: CredentialsKeyUsername},
+		"Insecure": {data: `{"url":"https://h","username":"admin","password":"p","insecure":"maybe"}`, field: CredentialsKeyInsecure},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseCredentials([]byte(tc.data))
+			var fe *FieldError
+			if !errors.As(err, &fe) || fe.Field != tc.field {
+				t.Errorf("ParseCredentials() error = %v, want a FieldError for %s", err, tc.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// statusCoder is implemented by the typed error responses generated in the
+// goharbor SDK as well as by the go-openapi runtime APIError.
+type statusCoder interface {
+	IsCode(code int) bool
+}
+
+// IsNotFound reports whether err is a Harbor API 404 response.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsForbidden reports whether err is a Harbor API 403 response.
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+// IsConflict reports whether err is a Harbor API 409 response.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsPreconditionFailed reports whether err is a Harbor API 412 response,
+// which Harbor sends when an object cannot be changed in its current state.
+func IsPreconditionFailed(err error) bool {
+	return hasStatusCode(err, http.StatusPreconditionFailed)
+}
+
+func hasStatusCode(err error, code int) bool {
+	if err == nil {
+		return false
+	}
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		return sc.IsCode(code)
+	}
+	return false
+}
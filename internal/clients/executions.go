@@ -0,0 +1,197 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	sdkreplication "github.com/goharbor/go-client/pkg/sdk/v2.0/client/replication"
+	sdkretention "github.com/goharbor/go-client/pkg/sdk/v2.0/client/retention"
+	"github.com/pkg/errors"
+)
+
+// executionPageSize is how many of the most recent executions are listed.
+const executionPageSize = 10
+
+// retentionTaskPageSize is how many retention tasks are read per request.
+const retentionTaskPageSize = 100
+
+// Harbor job statuses counted as outcomes of a retention task.
+const (
+	taskStatusSuccess = "Success"
+	taskStatusError   = "Error"
+)
+
+// RetentionExecution represents one run of a retention policy
+type RetentionExecution struct {
+	ID        string
+	PolicyID  string
+	Status    string
+	Trigger   string
+	DryRun    bool
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// RetentionTaskCounts counts the tasks of a retention execution, one per
+// repository, by outcome
+type RetentionTaskCounts struct {
+	Succeeded int64
+	Failed    int64
+	Total     int64
+}
+
+// ListReplicationExecutions lists the most recent executions of a
+// replication policy, newest first.
+func (c *HarborClient) ListReplicationExecutions(ctx context.Context, policyID string) ([]*ReplicationExecution, error) {
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	pageSize := int64(executionPageSize)
+	sortBy := "-start_time"
+	resp, err := v2Client.Replication.ListReplicationExecutions(ctx, &sdkreplication.ListReplicationExecutionsParams{
+		PolicyID: &id,
+		PageSize: &pageSize,
+		Sort:     &sortBy,
+		Context:  ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list replication executions")
+	}
+
+	executions := make([]*ReplicationExecution, 0, len(resp.Payload))
+	for _, e := range resp.Payload {
+		executions = append(executions, &ReplicationExecution{
+			ID:           strconv.FormatInt(e.ID, 10),
+			PolicyID:     strconv.FormatInt(e.PolicyID, 10),
+			Status:       e.Status,
+			Trigger:      e.Trigger,
+			StartTime:    time.Time(e.StartTime),
+			EndTime:      time.Time(e.EndTime),
+			SuccessCount: e.Succeed,
+			FailedCount:  e.Failed,
+			TotalCount:   e.Total,
+		})
+	}
+	return executions, nil
+}
+
+// ListRetentionExecutions lists the most recent executions of a retention
+// policy, newest first.
+func (c *HarborClient) ListRetentionExecutions(ctx context.Context, policyID string) ([]*RetentionExecution, error) {
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	pageSize := int64(executionPageSize)
+	resp, err := v2Client.Retention.ListRetentionExecutions(ctx, &sdkretention.ListRetentionExecutionsParams{
+		ID:       id,
+		PageSize: &pageSize,
+		Context:  ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list retention executions")
+	}
+
+	executions := make([]*RetentionExecution, 0, len(resp.Payload))
+	for _, e := range resp.Payload {
+		executions = append(executions, &RetentionExecution{
+			ID:        strconv.FormatInt(e.ID, 10),
+			PolicyID:  strconv.FormatInt(e.PolicyID, 10),
+			Status:    e.Status,
+			Trigger:   e.Trigger,
+			DryRun:    e.DryRun,
+			StartTime: parseExecutionTime(e.StartTime),
+			EndTime:   parseExecutionTime(e.EndTime),
+		})
+	}
+	sort.SliceStable(executions, func(i, j int) bool {
+		return executions[i].StartTime.After(executions[j].StartTime)
+	})
+	return executions, nil
+}
+
+// CountRetentionTasks counts the tasks of a retention execution by outcome.
+// Harbor only reports outcomes per task, so every page of tasks is read.
+func (c *HarborClient) CountRetentionTasks(ctx context.Context, policyID, executionID string) (*RetentionTaskCounts, error) {
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return nil, err
+	}
+	eid, err := strconv.ParseInt(executionID, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("execution ID must be numeric, got %q", executionID)
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	counts := &RetentionTaskCounts{}
+	pageSize := int64(retentionTaskPageSize)
+	for page := int64(1); ; page++ {
+		resp, err := v2Client.Retention.ListRetentionTasks(ctx, &sdkretention.ListRetentionTasksParams{
+			ID:       id,
+			Eid:      eid,
+			Page:     &page,
+			PageSize: &pageSize,
+			Context:  ctx,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list retention tasks")
+		}
+		for _, t := range resp.Payload {
+			switch t.Status {
+			case taskStatusSuccess:
+				counts.Succeeded++
+			case taskStatusError:
+				counts.Failed++
+			}
+		}
+		counts.Total += int64(len(resp.Payload))
+		if len(resp.Payload) < retentionTaskPageSize || (resp.XTotalCount > 0 && counts.Total >= resp.XTotalCount) {
+			return counts, nil
+		}
+	}
+}
+
+func parsePolicyID(policyID string) (int64, error) {
+	if policyID == "" {
+		return 0, errors.New("policy ID is required")
+	}
+	id, err := strconv.ParseInt(policyID, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("policy ID must be numeric, got %q", policyID)
+	}
+	return id, nil
+}
+
+// parseExecutionTime parses the times Harbor reports for retention
+// executions, which are strings rather than date-times. Unparseable times,
+// such as the end of a running execution, are zero.
+func parseExecutionTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func executionsClient(t *testing.T, mux *http.ServeMux) *HarborClient {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestListReplicationExecutions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/replication/executions", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("policy_id") != "7" || q.Get("sort") != "-start_time" {
+			t.Errorf("executions requested with query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": 12, "policy_id": 7, "status": "InProgress", "trigger": "manual", "start_time": "2024-05-03T01:00:00Z", "succeed": 3, "failed": 0, "total": 10},
+			{"id": 11, "policy_id": 7, "status": "Failed", "trigger": "scheduled", "start_time": "2024-05-02T01:00:00Z", "end_time": "2024-05-02T01:05:00Z", "succeed": 8, "failed": 2, "total": 10}
+		]`))
+	})
+	c := executionsClient(t, mux)
+
+	got, err := c.ListReplicationExecutions(context.Background(), "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ListReplicationExecutions() returned %d executions, want 2", len(got))
+	}
+	if e := got[1]; e.ID != "11" || e.Status != "Failed" || e.Trigger != "scheduled" || e.SuccessCount != 8 || e.FailedCount != 2 || e.TotalCount != 10 ||
+		!e.EndTime.Equal(time.Date(2024, 5, 2, 1, 5, 0, 0, time.UTC)) {
+		t.Errorf("execution = %+v", e)
+	}
+	if !got[0].EndTime.IsZero() {
+		t.Errorf("running execution has end time %v", got[0].EndTime)
+	}
+
+	if _, err := c.ListReplicationExecutions(context.Background(), "policy"); err == nil {
+		t.Error("ListReplicationExecutions() should reject a non-numeric policy ID")
+	}
+}
+
+func TestListRetentionExecutions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/retentions/4/executions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": 1, "policy_id": 4, "status": "Success", "trigger": "SCHEDULE", "start_time": "2024-05-01T00:00:00Z", "end_time": "2024-05-01T00:01:00Z"},
+			{"id": 2, "policy_id": 4, "status": "Running", "trigger": "MANUAL", "start_time": "2024-05-02T00:00:00Z"}
+		]`))
+	})
+	c := executionsClient(t, mux)
+
+	got, err := c.ListRetentionExecutions(context.Background(), "4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "2" || got[1].ID != "1" {
+		t.Fatalf("ListRetentionExecutions() = %+v, want newest first", got)
+	}
+	if !got[0].EndTime.IsZero() || !got[1].EndTime.Equal(time.Date(2024, 5, 1, 0, 1, 0, 0, time.UTC)) {
+		t.Errorf("end times = %v, %v", got[0].EndTime, got[1].EndTime)
+	}
+}
+
+func TestCountRetentionTasks(t *testing.T) {
+	const total = 150
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/retentions/4/executions/9/tasks", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		body := "["
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			status := "Success"
+			if i%50 == 0 {
+				status = "Error"
+			}
+			if i > (page-1)*size {
+				body += ","
+			}
+			body += fmt.Sprintf(`{"id": %d, "execution_id": 9, "status": %q}`, i+1, status)
+		}
+		_, _ = w.Write([]byte(body + "]"))
+	})
+	c := executionsClient(t, mux)
+
+	got, err := c.CountRetentionTasks(context.Background(), "4", "9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (RetentionTaskCounts{Succeeded: 147, Failed: 3, Total: total}); *got != want {
+		t.Errorf("CountRetentionTasks() = %+v, want %+v", *got, want)
+	}
+}
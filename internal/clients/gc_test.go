@@ -0,0 +1,90 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sentGCSchedule is the part of a garbage collection schedule request the
+// provider sets.
+type sentGCSchedule struct {
+	Method   string
+	Schedule struct {
+		Type string `json:"type"`
+		Cron string `json:"cron"`
+	} `json:"schedule"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+func TestGCSchedule(t *testing.T) {
+	var sent []sentGCSchedule
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/system/gc/schedule", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{
+				"id": 1,
+				"job_name": "GARBAGE_COLLECTION",
+				"job_parameters": "{\"delete_untagged\":true,\"dry_run\":false,\"workers\":3}",
+				"schedule": {"type": "Custom", "cron": "0 0 2 * * 6", "next_scheduled_time": "2026-10-24T02:00:00Z"}
+			}`))
+		case http.MethodPost, http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			s := sentGCSchedule{Method: r.Method}
+			if err := json.Unmarshal(body, &s); err != nil {
+				t.Error(err)
+			}
+			sent = append(sent, s)
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+			}
+		}
+	})
+	c := executionsClient(t, mux)
+	ctx := context.Background()
+
+	got, err := c.GetGCSchedule(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GCSchedule{
+		Type:              GCScheduleTypeCustom,
+		Cron:              "0 0 2 * * 6",
+		DeleteUntagged:    true,
+		Workers:           3,
+		NextScheduledTime: time.Date(2026, 10, 24, 2, 0, 0, 0, time.UTC),
+	}
+	if !got.NextScheduledTime.Equal(want.NextScheduledTime) {
+		t.Errorf("NextScheduledTime = %v, want %v", got.NextScheduledTime, want.NextScheduledTime)
+	}
+	got.NextScheduledTime = want.NextScheduledTime
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetGCSchedule() = %+v, want %+v", got, want)
+	}
+
+	if err := c.CreateGCSchedule(ctx, &GCSchedule{Type: GCScheduleTypeCustom, Cron: "0 0 2 * * 6", DeleteUntagged: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateGCSchedule(ctx, &GCSchedule{Type: GCScheduleTypeNone}); err != nil {
+		t.Fatal(err)
+	}
+	wantSent := []sentGCSchedule{
+		{Method: http.MethodPost, Parameters: map[string]interface{}{"delete_untagged": true, "dry_run": false}},
+		{Method: http.MethodPut},
+	}
+	wantSent[0].Schedule.Type, wantSent[0].Schedule.Cron = GCScheduleTypeCustom, "0 0 2 * * 6"
+	wantSent[1].Schedule.Type = GCScheduleTypeNone
+	if !reflect.DeepEqual(sent, wantSent) {
+		t.Errorf("sent %v, want %v", sent, wantSent)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+
+	sdkmember "github.com/goharbor/go-client/pkg/sdk/v2.0/client/member"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// OIDCGroupType is Harbor's user group type for groups from an OIDC groups
+// claim.
+const OIDCGroupType int64 = 3
+
+// projectRoleIDs are the IDs Harbor gives the project member roles.
+var projectRoleIDs = map[string]int64{
+	"projectAdmin": 1,
+	"developer":    2,
+	"guest":        3,
+	"maintainer":   4,
+	"limitedGuest": 5,
+}
+
+// AddProjectGroupMember makes a user group a member of a project with the
+// given role. A group that is already a member keeps its role.
+func (c *HarborClient) AddProjectGroupMember(ctx context.Context, projectName, groupName string, groupType int64, role string) error {
+	if projectName == "" {
+		return errors.New("project name is required")
+	}
+	if groupName == "" {
+		return errors.New("group name is required")
+	}
+	roleID, ok := projectRoleIDs[role]
+	if !ok {
+		return errors.Errorf("unknown project role %q", role)
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Adding Harbor project group member", "project", projectName, "group", groupName, "role", role)
+
+	_, err := v2Client.Member.CreateProjectMember(ctx, &sdkmember.CreateProjectMemberParams{
+		ProjectNameOrID: projectName,
+		XIsResourceName: projectIsName(projectName),
+		ProjectMember: &sdkmodels.ProjectMember{
+			RoleID:      roleID,
+			MemberGroup: &sdkmodels.UserGroup{GroupName: groupName, GroupType: groupType},
+		},
+		Context: ctx,
+	})
+	if IsConflict(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "failed to add group %s to project %s", groupName, projectName)
+}
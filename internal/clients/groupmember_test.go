@@ -0,0 +1,50 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestAddProjectGroupMember(t *testing.T) {
+	var got struct {
+		RoleID      int64 `json:"role_id"`
+		MemberGroup struct {
+			GroupName string `json:"group_name"`
+			GroupType int64  `json:"group_type"`
+		} `json:"member_group"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/team-a/members", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("X-Is-Resource-Name") != "true" {
+			t.Errorf("member added with %s, X-Is-Resource-Name %q", r.Method, r.Header.Get("X-Is-Resource-Name"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/api/v2.0/projects/team-b/members", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	c := executionsClient(t, mux)
+
+	if err := c.AddProjectGroupMember(context.Background(), "team-a", "platform-admins", OIDCGroupType, "maintainer"); err != nil {
+		t.Fatal(err)
+	}
+	if got.RoleID != 4 || got.MemberGroup.GroupName != "platform-admins" || got.MemberGroup.GroupType != OIDCGroupType {
+		t.Errorf("Harbor was sent %+v", got)
+	}
+
+	if err := c.AddProjectGroupMember(context.Background(), "team-b", "platform-admins", OIDCGroupType, "guest"); err != nil {
+		t.Errorf("adding a group that is already a member returned %v", err)
+	}
+	if err := c.AddProjectGroupMember(context.Background(), "team-a", "platform-admins", OIDCGroupType, "owner"); err == nil {
+		t.Error("adding a group with an unknown role should fail")
+	}
+}
@@ -18,8 +18,8 @@ package clients
 
 import (
 	"context"
-	"crypto/tls"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/http"
@@ -32,17 +32,15 @@ import (
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 	"github.com/goharbor/go-client/pkg/harbor"
+	sdkproject "github.com/goharbor/go-client/pkg/sdk/v2.0/client/project"
+	sdkprojectmetadata "github.com/goharbor/go-client/pkg/sdk/v2.0/client/project_metadata"
+	sdkreplication "github.com/goharbor/go-client/pkg/sdk/v2.0/client/replication"
 	sdkrobot "github.com/goharbor/go-client/pkg/sdk/v2.0/client/robot"
+	sdkscanner "github.com/goharbor/go-client/pkg/sdk/v2.0/client/scanner"
+	sdkuser "github.com/goharbor/go-client/pkg/sdk/v2.0/client/user"
 	sdkwebhook "github.com/goharbor/go-client/pkg/sdk/v2.0/client/webhook"
 	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
 	"github.com/pkg/errors"
-	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
-	registryv1beta1 "github.com/rossigee/provider-harbor/apis/registry/v1beta1"
-	robotv1beta1 "github.com/rossigee/provider-harbor/apis/robot/v1beta1"
-	scannerv1beta1 "github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
-	userv1beta1 "github.com/rossigee/provider-harbor/apis/user/v1beta1"
-	usergroupv1beta1 "github.com/rossigee/provider-harbor/apis/usergroup/v1beta1"
-	webhookv1beta1 "github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
 	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -55,22 +53,24 @@ const (
 	errGetProviderConfig = "cannot get referenced ProviderConfig"
 	// errExtractCredentials is returned when the credentials cannot be extracted from the provider config.
 	errExtractCredentials = "cannot extract credentials"
+
+	// errGetTLS is returned when the secrets of the provider config's TLS
+	// settings cannot be read.
+	errGetTLS = "cannot get TLS configuration"
 )
 
 // HarborClient provides Harbor API operations using the native Go client
 type HarborClient struct {
-	clientSet  *harbor.ClientSet
-	config     *harbor.ClientSetConfig
-	logger     logging.Logger
-	httpClient *http.Client
-}
+	clientSet   *harbor.ClientSet
+	config      *harbor.ClientSetConfig
+	account     string
+	logger      logging.Logger
+	httpClient  *http.Client
+	systemCache *systemCache
 
-// HarborConfig holds configuration for creating a Harbor client
-type HarborConfig struct {
-	URL      string `json:"url"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Insecure bool   `json:"insecure"`
+	// shared is set once the client is cached for a ProviderConfig, after
+	// which Close leaves its connections open for the next reconcile.
+	shared bool
 }
 
 // ProjectSpec defines the desired state of a Harbor project
@@ -97,8 +97,6 @@ type ProjectStatus struct {
 	UpdatedAt           time.Time `json:"updated_at,omitempty"`
 	OwnerID             int64     `json:"owner_id,omitempty"`
 	OwnerName           string    `json:"owner_name,omitempty"`
-	RepoCount           int64     `json:"repo_count,omitempty"`
-	ChartCount          int64     `json:"chart_count,omitempty"`
 	CurrentStorageUsage int64     `json:"current_storage_usage,omitempty"`
 }
 
@@ -109,6 +107,7 @@ type ScannerSpec struct {
 	URL              string  `json:"url"`
 	Auth             *string `json:"auth,omitempty"`
 	AccessCredential *string `json:"access_credential,omitempty"`
+	SkipCertVerify   *bool   `json:"skip_certVerify,omitempty"`
 }
 
 // ScannerStatus represents the status of a Harbor scanner registration
@@ -119,6 +118,7 @@ type ScannerStatus struct {
 	URL              string    `json:"url"`
 	Auth             *string   `json:"auth,omitempty"`
 	AccessCredential *string   `json:"access_credential,omitempty"`
+	IsDefault        bool      `json:"is_default"`
 	CreateTime       time.Time `json:"create_time"`
 	UpdateTime       time.Time `json:"update_time"`
 }
@@ -156,42 +156,57 @@ type RegistryCredential struct {
 	AccessSecret string `json:"access_secret"`
 }
 
+// maskedCredential returns cred as Harbor reports it, without the secret.
+func maskedCredential(cred *RegistryCredential) *RegistryCredential {
+	if cred == nil {
+		return nil
+	}
+	return &RegistryCredential{Type: cred.Type, AccessKey: cred.AccessKey}
+}
+
 // RegistryStatus represents the status of a Harbor registry
 type RegistryStatus struct {
-	Name        string    `json:"name"`
-	Description *string   `json:"description,omitempty"`
-	Type        string    `json:"type"`
-	URL         string    `json:"url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Type        string  `json:"type"`
+	URL         string  `json:"url"`
+	Insecure    bool    `json:"insecure"`
+	// Credential is nil when the registry has none. Harbor never returns the
+	// access secret, so AccessSecret is always empty.
+	Credential *RegistryCredential `json:"credential,omitempty"`
+	// Status is Harbor's last health check of the registry: healthy or
+	// unhealthy.
+	Status    string    `json:"status,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// NewHarborClient creates a new Harbor client with proper configuration
-func NewHarborClient(config *HarborConfig) (*HarborClient, error) {
+// NewHarborClient creates a new Harbor client with proper configuration.
+// The options set with SetDefaultOptions apply before opts.
+func NewHarborClient(config *HarborConfig, opts ...Option) (*HarborClient, error) {
 	if config == nil {
 		return nil, errors.New("config is required")
 	}
-	if config.URL == "" {
-		return nil, errors.New("harbor URL is required")
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
-	if config.Username == "" {
-		return nil, errors.New("username is required")
-	}
-	if config.Password == "" {
-		return nil, errors.New("password is required")
+
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid TLS configuration")
 	}
 
 	httpClient := &http.Client{
 		Timeout: 30 * time.Second,
 		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
 			DialContext: (&net.Dialer{
 				Timeout:   30 * time.Second,
 				KeepAlive: 30 * time.Second,
 				DualStack: true,
 			}).DialContext,
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: config.Insecure,
-			},
+			TLSClientConfig:       tlsConfig,
 			MaxIdleConns:          100,
 			IdleConnTimeout:       90 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
@@ -204,7 +219,6 @@ func NewHarborClient(config *HarborConfig) (*HarborClient, error) {
 		URL:      config.URL,
 		Username: config.Username,
 		Password: config.Password,
-		Insecure: config.Insecure,
 	}
 
 	clientSet, err := harbor.NewClientSet(csConfig)
@@ -212,51 +226,80 @@ func NewHarborClient(config *HarborConfig) (*HarborClient, error) {
 		return nil, errors.Wrap(err, "failed to create Harbor client set")
 	}
 
-	logger := logging.NewNopLogger().WithValues("client", "harbor")
+	o := buildOptions(opts)
+	logger := o.Logger.WithValues("client", "harbor")
 
-	return &HarborClient{
-		clientSet:  clientSet,
-		config:     csConfig,
-		logger:     logger,
-		httpClient: httpClient,
-	}, nil
+	c := &HarborClient{
+		clientSet:   clientSet,
+		config:      csConfig,
+		account:     config.Username,
+		logger:      logger,
+		httpClient:  httpClient,
+		systemCache: sharedSystemCache,
+	}
+
+	// Without a transport of its own the client set uses one that never
+	// verifies Harbor's certificate, so it is given the one configured here.
+	c.wrapTransport(func(http.RoundTripper) http.RoundTripper {
+		return httpClient.Transport
+	})
+
+	if config.EffectiveAuthType() == AuthTypeToken {
+		// The client set only knows basic auth, which its API clients write
+		// into every request, so the token replaces it on the way out.
+		sum := sha256.Sum256([]byte(config.Token))
+		c.account = "token:" + hex.EncodeToString(sum[:8])
+		c.wrapTransport(func(next http.RoundTripper) http.RoundTripper {
+			return &bearerTransport{next: next, token: config.Token}
+		})
+	}
+
+	c.wrapTransport(func(next http.RoundTripper) http.RoundTripper {
+		return &breakerTransport{next: next, endpoint: config.URL}
+	})
+	if o.UserAgent != "" {
+		c.wrapTransport(func(next http.RoundTripper) http.RoundTripper {
+			return &userAgentTransport{next: next, userAgent: o.UserAgent}
+		})
+	}
+	for _, wrap := range o.TransportWrappers {
+		c.wrapTransport(wrap)
+	}
+
+	transportWrapper.mu.Lock()
+	wrap := transportWrapper.wrap
+	transportWrapper.mu.Unlock()
+	if wrap != nil {
+		c.wrapTransport(wrap)
+	}
+	return c, nil
 }
 
 // NewHarborClientFromProviderConfig creates a Harbor client from a ProviderConfig
 // This maintains compatibility with the existing Crossplane provider pattern
 func NewHarborClientFromProviderConfig(ctx context.Context, k8sClient client.Client, mg resource.Managed) (HarborClienter, error) {
-	// Get provider config reference from the managed resource
-	// In v2, we need to access it through the spec directly
-	var configRef *xpv1.ProviderConfigReference
-
-	// Try to cast to a concrete type that has ProviderConfigReference
-	if project, ok := mg.(*projectv1beta1.Project); ok {
-		configRef = project.Spec.ProviderConfigReference
-	} else if scanner, ok := mg.(*scannerv1beta1.ScannerRegistration); ok {
-		configRef = scanner.Spec.ProviderConfigReference
-	} else if user, ok := mg.(*userv1beta1.User); ok {
-		configRef = user.Spec.ProviderConfigReference
-	} else if registry, ok := mg.(*registryv1beta1.Registry); ok {
-		configRef = registry.Spec.ProviderConfigReference
-	} else if usergroup, ok := mg.(*usergroupv1beta1.UserGroup); ok {
-		configRef = usergroup.Spec.ProviderConfigReference
-	} else if robot, ok := mg.(*robotv1beta1.Robot); ok {
-		configRef = robot.Spec.ProviderConfigReference
-	} else if webhook, ok := mg.(*webhookv1beta1.Webhook); ok {
-		configRef = webhook.Spec.ProviderConfigReference
-	} else {
-		// Fallback: assume the managed resource has ProviderConfigReference
-		// This is a bit of a hack but works for most cases
-		// In a real implementation, you'd handle each type specifically
+	// Every namespaced managed resource carries its ProviderConfig reference
+	// in its spec.
+	pcr, ok := mg.(interface {
+		GetProviderConfigReference() *xpv1.ProviderConfigReference
+	})
+	if !ok {
 		return nil, errors.New("unsupported managed resource type")
 	}
+	configRef := pcr.GetProviderConfigReference()
 
 	if configRef == nil {
 		return nil, errors.New(errNoProviderConfig)
 	}
 
+	return NewHarborClientForProviderConfig(ctx, k8sClient, configRef.Name)
+}
+
+// NewHarborClientForProviderConfig creates a Harbor client from the named
+// ProviderConfig, for callers that are not reconciling a managed resource
+func NewHarborClientForProviderConfig(ctx context.Context, k8sClient client.Client, name string) (HarborClienter, error) {
 	pc := &providerconfigv1beta1.ProviderConfig{}
-	if err := k8sClient.Get(ctx, types.NamespacedName{Name: configRef.Name}, pc); err != nil {
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
 		return nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
@@ -279,50 +322,27 @@ func NewHarborClientFromProviderConfig(ctx context.Context, k8sClient client.Cli
 		return nil, errors.Wrap(err, errExtractCredentials)
 	}
 
-	config := &HarborConfig{}
-
-	// Determine which key contains the credentials
-	credentialKey := pc.Spec.Credentials.SecretRef.Key
-	if credentialKey == "" {
-		credentialKey = "credentials"
-	}
-
-	_, _ = fmt.Fprintf(os.Stderr, "DEBUG: Credentials key: %s\n", credentialKey)
-	_, _ = fmt.Fprintf(os.Stderr, "DEBUG: Secret data keys: %v\n", func() []string {
-		keys := []string{}
-		for k := range secret.Data {
-			keys = append(keys, k)
-		}
-		return keys
-	}())
-
-	// Get the credential data from the secret
-	credentialData, ok := secret.Data[credentialKey]
-	if !ok {
-		_, _ = fmt.Fprintf(os.Stderr, "DEBUG: Key %s not found\n", credentialKey)
-		return nil, errors.Errorf("key %q not found in credentials secret", credentialKey)
+	config, err := CredentialsFromSecret(secret, pc.Spec.Credentials.SecretRef.Key, pc.Spec.Credentials.AuthType)
+	if err != nil {
+		return nil, errors.Wrap(err, errExtractCredentials)
 	}
-
-	_, _ = fmt.Fprintf(os.Stderr, "DEBUG: Credential data length: %d\n", len(credentialData))
-
-	// Parse credentials as JSON (standard Crossplane format)
-	credentialJSON := &HarborConfig{}
-	if err := json.Unmarshal(credentialData, credentialJSON); err != nil {
-		return nil, errors.Wrapf(err, "failed to parse credentials JSON from key %q", credentialKey)
+	if config.TLS, err = TLSFromProviderConfig(ctx, k8sClient, pc.Spec.TLS); err != nil {
+		return nil, errors.Wrap(err, errGetTLS)
 	}
-	config = credentialJSON
 
-	if config.URL == "" {
-		return nil, errors.Errorf("url is required in credentials (key=%s, json-parse-attempted=true, url-from-json=%q)", credentialKey, credentialJSON.URL)
-	}
-	if config.Username == "" {
-		return nil, errors.Errorf("username is required in credentials (key=%s, username=%q)", credentialKey, config.Username)
+	// The ProviderConfig and secrets above are read through the manager's
+	// cache, so reading them on every reconcile is cheap, and a change to
+	// either replaces the cached client.
+	fingerprint := config.fingerprint()
+	if c := sharedClientCache.get(name, fingerprint); c != nil {
+		return c, nil
 	}
-	if config.Password == "" {
-		return nil, errors.Errorf("password is required in credentials (key=%s)", credentialKey)
+	c, err := NewHarborClient(config)
+	if err != nil {
+		return nil, err
 	}
-
-	return NewHarborClient(config)
+	sharedClientCache.put(name, fingerprint, c)
+	return c, nil
 }
 
 // GetBaseURL returns the Harbor base URL
@@ -330,12 +350,20 @@ func (c *HarborClient) GetBaseURL() string {
 	return c.config.URL
 }
 
-// Close closes the client and cleans up resources
+// Close closes the client and cleans up resources. A client cached for a
+// ProviderConfig keeps its connections until the cache replaces it.
 func (c *HarborClient) Close() error {
+	if !c.shared {
+		c.release()
+	}
+	return nil
+}
+
+// release closes the client's idle connections.
+func (c *HarborClient) release() {
 	if c.httpClient != nil {
 		c.httpClient.CloseIdleConnections()
 	}
-	return nil
 }
 
 // TestConnection validates the Harbor connection by checking the API health
@@ -358,6 +386,9 @@ func (c *HarborClient) TestConnection(ctx context.Context) error {
 	return nil
 }
 
+// projectPageSize is how many projects are read per request.
+const projectPageSize = 100
+
 // CreateProject creates a new Harbor project
 func (c *HarborClient) CreateProject(ctx context.Context, spec *ProjectSpec) (*ProjectStatus, error) {
 	if spec == nil {
@@ -381,18 +412,28 @@ func (c *HarborClient) CreateProject(ctx context.Context, spec *ProjectSpec) (*P
 		"storageLimit", spec.StorageLimit,
 	)
 
-	status := &ProjectStatus{
-		ID:        "1",
-		Name:      spec.Name,
-		Public:    spec.Public,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	req := projectReq(spec)
+	req.ProjectName = spec.Name
+	// The registry of a proxy cache project and the storage quota can only
+	// be given when the project is created.
+	req.RegistryID = spec.RegistryID
+	req.StorageLimit = spec.StorageLimit
+	_, err := v2Client.Project.CreateProject(ctx, &sdkproject.CreateProjectParams{
+		Project: req,
+		Context: ctx,
+	})
+	if IsConflict(err) {
+		return nil, errors.Wrapf(err, "project %s already exists", spec.Name)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create project")
 	}
 
-	return status, nil
+	return c.GetProject(ctx, spec.Name)
 }
 
-// GetProject retrieves a Harbor project by name or ID
+// GetProject retrieves a Harbor project by name. An error for which
+// IsNotFound is true is returned when there is no such project.
 func (c *HarborClient) GetProject(ctx context.Context, projectName string) (*ProjectStatus, error) {
 	if projectName == "" {
 		return nil, errors.New("project name is required")
@@ -403,16 +444,17 @@ func (c *HarborClient) GetProject(ctx context.Context, projectName string) (*Pro
 		return nil, errors.New("failed to get Harbor v2 client")
 	}
 
-	c.logger.Info("Retrieving Harbor project", "name", projectName)
-
-	status := &ProjectStatus{
-		ID:        "1",
-		Name:      projectName,
-		Public:    false,
-		CreatedAt: time.Now().Add(-24 * time.Hour),
+	isName := true
+	resp, err := v2Client.Project.GetProject(ctx, &sdkproject.GetProjectParams{
+		ProjectNameOrID: projectName,
+		XIsResourceName: &isName,
+		Context:         ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get project %s", projectName)
 	}
 
-	return status, nil
+	return projectStatus(resp.Payload), nil
 }
 
 // UpdateProject updates an existing Harbor project
@@ -439,18 +481,22 @@ func (c *HarborClient) UpdateProject(ctx context.Context, projectName string, sp
 		"storageLimit", spec.StorageLimit,
 	)
 
-	status := &ProjectStatus{
-		ID:        "1",
-		Name:      projectName,
-		Public:    spec.Public,
-		CreatedAt: time.Now().Add(-24 * time.Hour),
-		UpdatedAt: time.Now(),
+	isName := true
+	_, err := v2Client.Project.UpdateProject(ctx, &sdkproject.UpdateProjectParams{
+		ProjectNameOrID: projectName,
+		XIsResourceName: &isName,
+		Project:         projectReq(spec),
+		Context:         ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update project %s", projectName)
 	}
 
-	return status, nil
+	return c.GetProject(ctx, projectName)
 }
 
-// DeleteProject deletes a Harbor project
+// DeleteProject deletes a Harbor project. Deleting a project that does not
+// exist succeeds.
 func (c *HarborClient) DeleteProject(ctx context.Context, projectName string) error {
 	if projectName == "" {
 		return errors.New("project name is required")
@@ -461,49 +507,130 @@ func (c *HarborClient) DeleteProject(ctx context.Context, projectName string) er
 		return errors.New("failed to get Harbor v2 client")
 	}
 
-	// Log the operation for debugging
 	c.logger.Info("Deleting Harbor project", "name", projectName)
 
-	// In production, this would make actual Harbor API delete calls
-	// For now, we acknowledge the operation was attempted
+	isName := true
+	_, err := v2Client.Project.DeleteProject(ctx, &sdkproject.DeleteProjectParams{
+		ProjectNameOrID: projectName,
+		XIsResourceName: &isName,
+		Context:         ctx,
+	})
+	if err != nil && !IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete project %s", projectName)
+	}
 	return nil
 }
 
-// ListProjects lists Harbor projects
+// ListProjects lists every Harbor project visible to the account
 func (c *HarborClient) ListProjects(ctx context.Context) ([]*ProjectStatus, error) {
 	v2Client := c.clientSet.V2()
 	if v2Client == nil {
 		return nil, errors.New("failed to get Harbor v2 client")
 	}
 
-	// Log the operation for debugging
 	c.logger.Info("Listing Harbor projects")
 
-	// Mock response structure for demonstration
-	// In production, this would query Harbor API and parse the response
-	projects := []*ProjectStatus{
-		{
-			Name:      "library",
-			Public:    true,
-			CreatedAt: time.Now().Add(-7 * 24 * time.Hour),
-		},
-		{
-			Name:      "my-project",
-			Public:    false,
-			CreatedAt: time.Now().Add(-3 * 24 * time.Hour),
-		},
+	var projects []*ProjectStatus
+	pageSize := int64(projectPageSize)
+	for page := int64(1); ; page++ {
+		resp, err := v2Client.Project.ListProjects(ctx, &sdkproject.ListProjectsParams{
+			Page:     &page,
+			PageSize: &pageSize,
+			Context:  ctx,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list projects")
+		}
+		for _, p := range resp.Payload {
+			projects = append(projects, projectStatus(p))
+		}
+		if len(resp.Payload) < projectPageSize || (resp.XTotalCount > 0 && int64(len(projects)) >= resp.XTotalCount) {
+			return projects, nil
+		}
+	}
+}
+
+// projectReq converts the settings of a project that can be changed after
+// it is created to a Harbor project request.
+func projectReq(spec *ProjectSpec) *sdkmodels.ProjectReq {
+	public := spec.Public
+	md := &sdkmodels.ProjectMetadata{
+		Public:                   strconv.FormatBool(spec.Public),
+		EnableContentTrust:       formatBool(spec.EnableContentTrust),
+		EnableContentTrustCosign: formatBool(spec.EnableContentTrustCosign),
+		AutoScan:                 formatBool(spec.AutoScanImages),
+		PreventVul:               formatBool(spec.PreventVulnerableImages),
+		Severity:                 spec.Severity,
+	}
+	req := &sdkmodels.ProjectReq{Public: &public, Metadata: md}
+	if spec.CVEAllowlist != nil {
+		items := make([]*sdkmodels.CVEAllowlistItem, 0, len(spec.CVEAllowlist))
+		for _, id := range spec.CVEAllowlist {
+			items = append(items, &sdkmodels.CVEAllowlistItem{CVEID: id})
+		}
+		req.CVEAllowlist = &sdkmodels.CVEAllowlist{Items: items}
+	}
+	return req
+}
+
+// projectStatus converts a project returned by Harbor.
+func projectStatus(p *sdkmodels.Project) *ProjectStatus {
+	s := &ProjectStatus{
+		ID:        strconv.FormatInt(int64(p.ProjectID), 10),
+		Name:      p.Name,
+		CreatedAt: time.Time(p.CreationTime),
+		UpdatedAt: time.Time(p.UpdateTime),
+		OwnerID:   int64(p.OwnerID),
+		OwnerName: p.OwnerName,
 	}
+	if p.Metadata != nil {
+		s.Public = p.Metadata.Public == "true"
+	}
+	return s
+}
 
-	return projects, nil
+func formatBool(b *bool) *string {
+	if b == nil {
+		return nil
+	}
+	s := strconv.FormatBool(*b)
+	return &s
 }
 
 // GetVersion returns Harbor version information
 func (c *HarborClient) GetVersion(ctx context.Context) (string, error) {
-	// The actual Harbor API call would be implemented here
-	// systeminfo, err := v2Client.Systeminfo.GetSysteminfo(ctx, &systeminfo.GetSysteminfoParams{})
-
 	c.logger.Info("Retrieving Harbor version information")
-	return "Harbor xpv1.x (Go client)", nil
+
+	info, err := c.GetSystemInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return info.HarborVersion, nil
+}
+
+// CurrentUser describes the Harbor account the client is authenticated as
+type CurrentUser struct {
+	Username string
+	SysAdmin bool
+}
+
+// GetCurrentUser returns the account the client's credentials belong to
+func (c *HarborClient) GetCurrentUser(ctx context.Context) (*CurrentUser, error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	resp, err := v2Client.User.GetCurrentUserInfo(ctx, &sdkuser.GetCurrentUserInfoParams{Context: ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current user")
+	}
+
+	return &CurrentUser{
+		Username: resp.Payload.Username,
+		SysAdmin: resp.Payload.SysadminFlag,
+	}, nil
 }
 
 // GetMemoryFootprint returns estimated memory usage for this client
@@ -645,6 +772,28 @@ func (c *HarborClient) DeleteScannerRegistration(ctx context.Context, scannerID
 	return nil
 }
 
+// SetDefaultScanner makes the scanner registration with the given UUID the
+// system default. Harbor has exactly one default, so the previous default
+// loses the flag.
+func (c *HarborClient) SetDefaultScanner(ctx context.Context, scannerID string) error {
+	if scannerID == "" {
+		return errors.New("scanner ID is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	isDefault := true
+	_, err := v2Client.Scanner.SetScannerAsDefault(ctx, &sdkscanner.SetScannerAsDefaultParams{
+		RegistrationID: scannerID,
+		Payload:        &sdkmodels.IsDefault{IsDefault: isDefault},
+		Context:        ctx,
+	})
+	return errors.Wrapf(err, "failed to set scanner %s as default", scannerID)
+}
+
 // ListScannerRegistrations lists Harbor scanner registrations
 func (c *HarborClient) ListScannerRegistrations(ctx context.Context) ([]*ScannerStatus, error) {
 	v2Client := c.clientSet.V2()
@@ -789,137 +938,6 @@ func (c *HarborClient) DeleteUser(ctx context.Context, username string) error {
 	return nil
 }
 
-// CreateRegistry creates a new Harbor registry
-func (c *HarborClient) CreateRegistry(ctx context.Context, spec *RegistrySpec) (*RegistryStatus, error) {
-	if spec == nil {
-		return nil, errors.New("registry spec is required")
-	}
-	if spec.Name == "" {
-		return nil, errors.New("registry name is required")
-	}
-	if spec.URL == "" {
-		return nil, errors.New("registry URL is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Creating Harbor registry", "name", spec.Name, "url", spec.URL, "type", spec.Type)
-
-	// The actual Harbor API call would be implemented here
-	// registryReq := &models.RegistryUpdate{
-	//     Name: spec.Name,
-	//     URL: spec.URL,
-	//     Type: spec.Type,
-	// }
-	// _, err := v2Client.Registry.CreateRegistry(ctx, &registry.CreateRegistryParams{
-	//     Registry: registryReq,
-	// })
-
-	status := &RegistryStatus{
-		Name:        spec.Name,
-		Description: spec.Description,
-		Type:        spec.Type,
-		URL:         spec.URL,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-
-	return status, nil
-}
-
-// GetRegistry retrieves a Harbor registry by name
-func (c *HarborClient) GetRegistry(ctx context.Context, registryName string) (*RegistryStatus, error) {
-	if registryName == "" {
-		return nil, errors.New("registry name is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Retrieving Harbor registry", "name", registryName)
-
-	// The actual Harbor API call would be implemented here
-	// registry, err := v2Client.Registry.GetRegistry(ctx, &registry.GetRegistryParams{
-	//     RegistryID: registryName,
-	// })
-
-	status := &RegistryStatus{
-		Name:        registryName,
-		Description: func() *string { s := "External registry"; return &s }(),
-		Type:        "docker-registry",
-		URL:         "https://registry.example.com",
-		CreatedAt:   time.Now().Add(-24 * time.Hour),
-		UpdatedAt:   time.Now().Add(-24 * time.Hour),
-	}
-
-	return status, nil
-}
-
-// UpdateRegistry updates an existing Harbor registry
-func (c *HarborClient) UpdateRegistry(ctx context.Context, registryName string, spec *RegistrySpec) (*RegistryStatus, error) {
-	if registryName == "" {
-		return nil, errors.New("registry name is required")
-	}
-	if spec == nil {
-		return nil, errors.New("registry spec is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Updating Harbor registry", "name", registryName, "url", spec.URL, "type", spec.Type)
-
-	// The actual Harbor API call would be implemented here
-	// registryReq := &models.RegistryUpdate{
-	//     Name: spec.Name,
-	//     URL: spec.URL,
-	//     Type: spec.Type,
-	// }
-	// err := v2Client.Registry.UpdateRegistry(ctx, &registry.UpdateRegistryParams{
-	//     RegistryID: registryName,
-	//     Registry: registryReq,
-	// })
-
-	status := &RegistryStatus{
-		Name:        registryName,
-		Description: spec.Description,
-		Type:        spec.Type,
-		URL:         spec.URL,
-		CreatedAt:   time.Now().Add(-24 * time.Hour),
-		UpdatedAt:   time.Now(),
-	}
-
-	return status, nil
-}
-
-// DeleteRegistry deletes a Harbor registry
-func (c *HarborClient) DeleteRegistry(ctx context.Context, registryName string) error {
-	if registryName == "" {
-		return errors.New("registry name is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Deleting Harbor registry", "name", registryName)
-
-	// The actual Harbor API call would be implemented here
-	// err := v2Client.Registry.DeleteRegistry(ctx, &registry.DeleteRegistryParams{
-	//     RegistryID: registryName,
-	// })
-
-	return nil
-}
-
 // RepositorySpec defines the desired state of a Harbor repository
 type RepositorySpec struct {
 	ProjectID   string  `json:"projectId"`
@@ -1205,265 +1223,51 @@ func (c *HarborClient) GetArtifactVulnerabilities(ctx context.Context, projectID
 	return status, nil
 }
 
-// MemberStatus represents a Harbor project member
-type MemberStatus struct {
-	ID           string
-	MemberName   string
-	MemberType   string
-	Role         string
-	CreationTime time.Time
-}
-
-// AddProjectMember adds a member to a Harbor project
-func (c *HarborClient) AddProjectMember(ctx context.Context, projectID, username, role string) error {
-	if projectID == "" {
-		return errors.New("project ID is required")
-	}
-	if username == "" {
-		return errors.New("username is required")
-	}
-	if role == "" {
-		return errors.New("role is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Adding Harbor project member", "projectId", projectID, "username", username, "role", role)
-
-	return nil
-}
-
-// ListProjectMembers lists members of a Harbor project
-func (c *HarborClient) ListProjectMembers(ctx context.Context, projectID string) ([]*MemberStatus, error) {
+// ListScans lists scans for an artifact
+func (c *HarborClient) ListScans(ctx context.Context, projectID, repoName string) ([]*ScanStatus, error) {
 	if projectID == "" {
 		return nil, errors.New("project ID is required")
 	}
+	if repoName == "" {
+		return nil, errors.New("repository name is required")
+	}
 
 	v2Client := c.clientSet.V2()
 	if v2Client == nil {
 		return nil, errors.New("failed to get Harbor v2 client")
 	}
 
-	c.logger.Info("Listing Harbor project members", "projectId", projectID)
+	c.logger.Info("Listing Harbor artifact scans", "projectId", projectID, "repo", repoName)
 
-	members := []*MemberStatus{
+	scans := []*ScanStatus{
 		{
-			ID:           "1",
-			MemberName:   "admin",
-			MemberType:   "user",
-			Role:         "master",
-			CreationTime: time.Now().Add(-30 * 24 * time.Hour),
+			ID:            "1",
+			Status:        "completed",
+			CriticalCount: 0,
+			HighCount:     1,
+			MediumCount:   3,
+			LowCount:      5,
+			StartTime:     time.Now().Add(-1 * time.Hour),
+			EndTime:       time.Now(),
 		},
 	}
 
-	return members, nil
+	return scans, nil
 }
 
-// GetProjectMember retrieves a specific project member
-func (c *HarborClient) GetProjectMember(ctx context.Context, projectID, username string) (*MemberStatus, error) {
-	if projectID == "" {
-		return nil, errors.New("project ID is required")
-	}
-	if username == "" {
-		return nil, errors.New("username is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Retrieving Harbor project member", "projectId", projectID, "username", username)
-
-	member := &MemberStatus{
-		ID:           "1",
-		MemberName:   username,
-		MemberType:   "user",
-		Role:         "developer",
-		CreationTime: time.Now().Add(-10 * 24 * time.Hour),
-	}
+// RobotSpec defines the desired state of a Harbor robot account
+type RobotSpec struct {
+	Name        string
+	Description *string
+	ProjectID   *string
+	ExpiresIn   *int64
+	Permissions []RobotPermission
+}
 
-	return member, nil
-}
-
-// UpdateProjectMember updates a project member's role
-func (c *HarborClient) UpdateProjectMember(ctx context.Context, projectID, username, role string) error {
-	if projectID == "" {
-		return errors.New("project ID is required")
-	}
-	if username == "" {
-		return errors.New("username is required")
-	}
-	if role == "" {
-		return errors.New("role is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Updating Harbor project member", "projectId", projectID, "username", username, "role", role)
-
-	return nil
-}
-
-// DeleteProjectMember removes a member from a project
-func (c *HarborClient) DeleteProjectMember(ctx context.Context, projectID, username string) error {
-	if projectID == "" {
-		return errors.New("project ID is required")
-	}
-	if username == "" {
-		return errors.New("username is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Deleting Harbor project member", "projectId", projectID, "username", username)
-
-	return nil
-}
-
-// ScanStatus represents the status of an artifact scan
-type ScanStatus struct {
-	ID            string
-	Status        string
-	CriticalCount int64
-	HighCount     int64
-	MediumCount   int64
-	LowCount      int64
-	StartTime     time.Time
-	EndTime       time.Time
-}
-
-// TriggerScan triggers a vulnerability scan for an artifact
-func (c *HarborClient) TriggerScan(ctx context.Context, projectID, repoName, reference string) error {
-	if projectID == "" {
-		return errors.New("project ID is required")
-	}
-	if repoName == "" {
-		return errors.New("repository name is required")
-	}
-	if reference == "" {
-		return errors.New("reference is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Triggering Harbor artifact scan", "projectId", projectID, "repo", repoName, "reference", reference)
-
-	return nil
-}
-
-// ListScans lists scans for an artifact
-func (c *HarborClient) ListScans(ctx context.Context, projectID, repoName string) ([]*ScanStatus, error) {
-	if projectID == "" {
-		return nil, errors.New("project ID is required")
-	}
-	if repoName == "" {
-		return nil, errors.New("repository name is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Listing Harbor artifact scans", "projectId", projectID, "repo", repoName)
-
-	scans := []*ScanStatus{
-		{
-			ID:            "1",
-			Status:        "completed",
-			CriticalCount: 0,
-			HighCount:     1,
-			MediumCount:   3,
-			LowCount:      5,
-			StartTime:     time.Now().Add(-1 * time.Hour),
-			EndTime:       time.Now(),
-		},
-	}
-
-	return scans, nil
-}
-
-// GetScan retrieves a specific scan result
-func (c *HarborClient) GetScan(ctx context.Context, projectID, repoName, reference string) (*ScanStatus, error) {
-	if projectID == "" {
-		return nil, errors.New("project ID is required")
-	}
-	if repoName == "" {
-		return nil, errors.New("repository name is required")
-	}
-	if reference == "" {
-		return nil, errors.New("reference is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Retrieving Harbor scan", "projectId", projectID, "repo", repoName, "reference", reference)
-
-	scan := &ScanStatus{
-		ID:            "1",
-		Status:        "completed",
-		CriticalCount: 0,
-		HighCount:     1,
-		MediumCount:   3,
-		LowCount:      5,
-		StartTime:     time.Now().Add(-1 * time.Hour),
-		EndTime:       time.Now(),
-	}
-
-	return scan, nil
-}
-
-// StopScan stops a running scan
-func (c *HarborClient) StopScan(ctx context.Context, projectID, repoName, reference string) error {
-	if projectID == "" {
-		return errors.New("project ID is required")
-	}
-	if repoName == "" {
-		return errors.New("repository name is required")
-	}
-	if reference == "" {
-		return errors.New("reference is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Stopping Harbor artifact scan", "projectId", projectID, "repo", repoName, "reference", reference)
-
-	return nil
-}
-
-// RobotSpec defines the desired state of a Harbor robot account
-type RobotSpec struct {
-	Name        string
-	Description *string
-	ProjectID   *string
-	ExpiresIn   *int64
-	Permissions []RobotPermission
-}
-
-// RobotPermission defines permissions for a robot account
-type RobotPermission struct {
-	Namespace string
-	Access    []string
+// RobotPermission defines permissions for a robot account
+type RobotPermission struct {
+	Namespace string
+	Access    []string
 }
 
 // RobotStatus represents the status of a Harbor robot account
@@ -1476,6 +1280,8 @@ type RobotStatus struct {
 	ExpiresAt    *time.Time
 	CreationTime time.Time
 	UpdateTime   time.Time
+	// ManagedByProvider is true when the robot was created by this provider
+	ManagedByProvider bool
 }
 
 // CreateRobot creates a new robot account
@@ -1511,7 +1317,7 @@ func (c *HarborClient) CreateRobot(ctx context.Context, spec *RobotSpec) (*Robot
 		for _, a := range p.Access {
 			accessList = append(accessList, &sdkmodels.Access{
 				Action:   a,
-				Resource: "repository",
+				Resource: robotAccessResource,
 			})
 		}
 		permissions = append(permissions, &sdkmodels.RobotPermission{
@@ -1535,7 +1341,7 @@ func (c *HarborClient) CreateRobot(ctx context.Context, spec *RobotSpec) (*Robot
 	// Create robot account via Harbor API
 	robotCreate := &sdkmodels.RobotCreate{
 		Name:        spec.Name,
-		Description: getStringValue(spec.Description),
+		Description: withManagedByMarker(getStringValue(spec.Description)),
 		Level:       level,
 		Duration:    duration,
 		Permissions: permissions,
@@ -1565,12 +1371,23 @@ func (c *HarborClient) CreateRobot(ctx context.Context, spec *RobotSpec) (*Robot
 		ID:           strconv.FormatInt(createdRobot.ID, 10),
 		Name:         createdRobot.Name,
 		Secret:       createdRobot.Secret,
+		ExpiresAt:    robotExpiry(createdRobot.ExpiresAt),
 		CreationTime: time.Time(createdRobot.CreationTime),
 	}
 
 	return robotStatus, nil
 }
 
+// robotExpiry converts Harbor's expires_at, a Unix timestamp where -1 means
+// never, to a time.
+func robotExpiry(expiresAt int64) *time.Time {
+	if expiresAt <= 0 {
+		return nil
+	}
+	t := time.Unix(expiresAt, 0)
+	return &t
+}
+
 // ListRobots lists all robot accounts
 func (c *HarborClient) ListRobots(ctx context.Context, projectID *string) ([]*RobotStatus, error) {
 	c.logger.Info("ListRobots: starting", "projectId", projectID)
@@ -1599,12 +1416,15 @@ func (c *HarborClient) ListRobots(ctx context.Context, projectID *string) ([]*Ro
 
 	var robots []*RobotStatus
 	for _, r := range resp.Payload {
+		desc, managedByProvider := stripManagedByMarker(r.Description)
 		robot := &RobotStatus{
-			ID:           strconv.FormatInt(r.ID, 10),
-			Name:         r.Name,
-			Description:  &r.Description,
-			CreationTime: time.Time(r.CreationTime),
-			UpdateTime:   time.Time(r.UpdateTime),
+			ID:                strconv.FormatInt(r.ID, 10),
+			Name:              r.Name,
+			Description:       &desc,
+			ExpiresAt:         robotExpiry(r.ExpiresAt),
+			CreationTime:      time.Time(r.CreationTime),
+			UpdateTime:        time.Time(r.UpdateTime),
+			ManagedByProvider: managedByProvider,
 		}
 		robots = append(robots, robot)
 		c.logger.Info("ListRobots: found robot", "id", robot.ID, "name", robot.Name)
@@ -1676,11 +1496,50 @@ func (c *HarborClient) DeleteRobot(ctx context.Context, robotID string) error {
 		return errors.New("failed to get Harbor v2 client")
 	}
 
+	id, err := strconv.ParseInt(robotID, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid robot ID")
+	}
+
 	c.logger.Info("Deleting Harbor robot account", "robotId", robotID)
 
+	if _, err := v2Client.Robot.DeleteRobot(ctx, &sdkrobot.DeleteRobotParams{RobotID: id, Context: ctx}); err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to delete robot account")
+	}
+
 	return nil
 }
 
+// RefreshRobotSecret replaces a robot account's secret with a random one and
+// returns it. The robot's expiry is unchanged.
+func (c *HarborClient) RefreshRobotSecret(ctx context.Context, robotID string) (string, error) {
+	if robotID == "" {
+		return "", errors.New("robot ID is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return "", errors.New("failed to get Harbor v2 client")
+	}
+
+	id, err := strconv.ParseInt(robotID, 10, 64)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid robot ID")
+	}
+
+	c.logger.Info("Refreshing Harbor robot account secret", "robotId", robotID)
+
+	resp, err := v2Client.Robot.RefreshSec(ctx, &sdkrobot.RefreshSecParams{RobotID: id, RobotSec: &sdkmodels.RobotSec{}, Context: ctx})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to refresh robot account secret")
+	}
+
+	return resp.Payload.Secret, nil
+}
+
 // WebhookSpec defines the desired state of a Harbor webhook
 type WebhookSpec struct {
 	ProjectID      string
@@ -1690,18 +1549,29 @@ type WebhookSpec struct {
 	EventTypes     []string
 	AuthHeader     *string
 	SkipCertVerify bool
+	// NotifyType is the target type, http or slack; http when empty
+	NotifyType string
+	// PayloadFormat is the target payload format, Default or CloudEvents;
+	// Harbor's default when empty
+	PayloadFormat string
+	Enabled       bool
 }
 
 // WebhookStatus represents the status of a Harbor webhook
 type WebhookStatus struct {
-	ID           string
-	ProjectID    string
-	Name         string
-	Description  *string
-	URL          string
-	EventTypes   []string
-	CreationTime time.Time
-	UpdateTime   time.Time
+	ID            string
+	ProjectID     string
+	Name          string
+	Description   *string
+	URL           string
+	EventTypes    []string
+	NotifyType    string
+	PayloadFormat string
+	Enabled       bool
+	CreationTime  time.Time
+	UpdateTime    time.Time
+	// ManagedByProvider is true when the policy was created by this provider
+	ManagedByProvider bool
 }
 
 // CreateWebhook creates a new webhook
@@ -1726,25 +1596,7 @@ func (c *HarborClient) CreateWebhook(ctx context.Context, spec *WebhookSpec) (*W
 
 	c.logger.Info("Creating Harbor webhook", "projectId", spec.ProjectID, "name", spec.Name, "url", spec.URL)
 
-	target := &sdkmodels.WebhookTargetObject{
-		Address:        spec.URL,
-		Type:           "http",
-		SkipCertVerify: spec.SkipCertVerify,
-	}
-	if spec.AuthHeader != nil {
-		target.AuthHeader = *spec.AuthHeader
-	}
-
-	policy := &sdkmodels.WebhookPolicy{
-		Name:        spec.Name,
-		Description: "",
-		EventTypes:  spec.EventTypes,
-		Enabled:     true,
-		Targets:     []*sdkmodels.WebhookTargetObject{target},
-	}
-	if spec.Description != nil {
-		policy.Description = *spec.Description
-	}
+	policy := webhookPolicy(spec)
 
 	params := &sdkwebhook.CreateWebhookPolicyOfProjectParams{
 		ProjectNameOrID: spec.ProjectID,
@@ -1780,24 +1632,12 @@ func (c *HarborClient) CreateWebhook(ctx context.Context, spec *WebhookSpec) (*W
 	}
 
 	p := getResp.Payload
-	webhook := &WebhookStatus{
-		ID:        strconv.FormatInt(p.ID, 10),
-		ProjectID: strconv.FormatInt(p.ProjectID, 10),
-		Name:      p.Name,
-	}
-	if p.Description != "" {
-		webhook.Description = &p.Description
-	}
-	if len(p.Targets) > 0 {
-		webhook.URL = p.Targets[0].Address
-	}
-	webhook.EventTypes = p.EventTypes
-	webhook.CreationTime = time.Time(p.CreationTime)
-	webhook.UpdateTime = time.Time(p.UpdateTime)
-
-	return webhook, nil
+	return webhookStatus(p), nil
 }
 
+// webhookPageSize is how many webhook policies are listed per request.
+const webhookPageSize = 100
+
 // ListWebhooks lists webhooks for a project
 func (c *HarborClient) ListWebhooks(ctx context.Context, projectID string) ([]*WebhookStatus, error) {
 	if projectID == "" {
@@ -1811,37 +1651,26 @@ func (c *HarborClient) ListWebhooks(ctx context.Context, projectID string) ([]*W
 
 	c.logger.Info("Listing Harbor webhooks", "projectId", projectID)
 
-	params := &sdkwebhook.ListWebhookPoliciesOfProjectParams{
-		ProjectNameOrID: projectID,
-		Context:         ctx,
-	}
-
-	resp, err := v2Client.Webhook.ListWebhookPoliciesOfProject(ctx, params)
-	if err != nil {
-		c.logger.Info("ListWebhooks: API call failed", "error", err.Error(), "projectId", projectID)
-		return nil, errors.Wrap(err, "failed to list webhooks")
-	}
-
-	webhooks := make([]*WebhookStatus, 0, len(resp.Payload))
-	for _, p := range resp.Payload {
-		webhook := &WebhookStatus{
-			ID:        strconv.FormatInt(p.ID, 10),
-			ProjectID: strconv.FormatInt(p.ProjectID, 10),
-			Name:      p.Name,
+	var webhooks []*WebhookStatus
+	pageSize := int64(webhookPageSize)
+	for page := int64(1); ; page++ {
+		resp, err := v2Client.Webhook.ListWebhookPoliciesOfProject(ctx, &sdkwebhook.ListWebhookPoliciesOfProjectParams{
+			ProjectNameOrID: projectID,
+			Page:            &page,
+			PageSize:        &pageSize,
+			Context:         ctx,
+		})
+		if err != nil {
+			c.logger.Info("ListWebhooks: API call failed", "error", err.Error(), "projectId", projectID)
+			return nil, errors.Wrap(err, "failed to list webhooks")
 		}
-		if p.Description != "" {
-			webhook.Description = &p.Description
+		for _, p := range resp.Payload {
+			webhooks = append(webhooks, webhookStatus(p))
 		}
-		if len(p.Targets) > 0 {
-			webhook.URL = p.Targets[0].Address
+		if len(resp.Payload) < webhookPageSize || (resp.XTotalCount > 0 && int64(len(webhooks)) >= resp.XTotalCount) {
+			return webhooks, nil
 		}
-		webhook.EventTypes = p.EventTypes
-		webhook.CreationTime = time.Time(p.CreationTime)
-		webhook.UpdateTime = time.Time(p.UpdateTime)
-		webhooks = append(webhooks, webhook)
 	}
-
-	return webhooks, nil
 }
 
 // GetWebhook retrieves a specific webhook
@@ -1878,22 +1707,7 @@ func (c *HarborClient) GetWebhook(ctx context.Context, projectID, webhookID stri
 	}
 
 	p := resp.Payload
-	webhook := &WebhookStatus{
-		ID:        strconv.FormatInt(p.ID, 10),
-		ProjectID: strconv.FormatInt(p.ProjectID, 10),
-		Name:      p.Name,
-	}
-	if p.Description != "" {
-		webhook.Description = &p.Description
-	}
-	if len(p.Targets) > 0 {
-		webhook.URL = p.Targets[0].Address
-	}
-	webhook.EventTypes = p.EventTypes
-	webhook.CreationTime = time.Time(p.CreationTime)
-	webhook.UpdateTime = time.Time(p.UpdateTime)
-
-	return webhook, nil
+	return webhookStatus(p), nil
 }
 
 // UpdateWebhook updates a webhook
@@ -1920,25 +1734,7 @@ func (c *HarborClient) UpdateWebhook(ctx context.Context, projectID, webhookID s
 
 	c.logger.Info("Updating Harbor webhook", "projectId", projectID, "webhookId", webhookID, "name", spec.Name)
 
-	target := &sdkmodels.WebhookTargetObject{
-		Address:        spec.URL,
-		Type:           "http",
-		SkipCertVerify: spec.SkipCertVerify,
-	}
-	if spec.AuthHeader != nil {
-		target.AuthHeader = *spec.AuthHeader
-	}
-
-	policy := &sdkmodels.WebhookPolicy{
-		Name:        spec.Name,
-		Description: "",
-		EventTypes:  spec.EventTypes,
-		Enabled:     true,
-		Targets:     []*sdkmodels.WebhookTargetObject{target},
-	}
-	if spec.Description != nil {
-		policy.Description = *spec.Description
-	}
+	policy := webhookPolicy(spec)
 
 	params := &sdkwebhook.UpdateWebhookPolicyOfProjectParams{
 		ProjectNameOrID: projectID,
@@ -1954,19 +1750,71 @@ func (c *HarborClient) UpdateWebhook(ctx context.Context, projectID, webhookID s
 	}
 
 	webhook := &WebhookStatus{
-		ID:           webhookID,
-		ProjectID:    projectID,
-		Name:         spec.Name,
-		Description:  spec.Description,
-		URL:          spec.URL,
-		EventTypes:   spec.EventTypes,
-		CreationTime: time.Now().Add(-7 * 24 * time.Hour),
-		UpdateTime:   time.Now(),
+		ID:            webhookID,
+		ProjectID:     projectID,
+		Name:          spec.Name,
+		Description:   spec.Description,
+		URL:           spec.URL,
+		EventTypes:    spec.EventTypes,
+		NotifyType:    policy.Targets[0].Type,
+		PayloadFormat: string(policy.Targets[0].PayloadFormat),
+		Enabled:       spec.Enabled,
+		CreationTime:  time.Now().Add(-7 * 24 * time.Hour),
+		UpdateTime:    time.Now(),
 	}
 
 	return webhook, nil
 }
 
+// webhookPolicy returns the Harbor policy for spec, with a single target.
+func webhookPolicy(spec *WebhookSpec) *sdkmodels.WebhookPolicy {
+	target := &sdkmodels.WebhookTargetObject{
+		Address:        spec.URL,
+		Type:           spec.NotifyType,
+		PayloadFormat:  sdkmodels.PayloadFormatType(spec.PayloadFormat),
+		SkipCertVerify: spec.SkipCertVerify,
+	}
+	if target.Type == "" {
+		target.Type = "http"
+	}
+	if spec.AuthHeader != nil {
+		target.AuthHeader = *spec.AuthHeader
+	}
+
+	return &sdkmodels.WebhookPolicy{
+		Name:        spec.Name,
+		Description: withManagedByMarker(getStringValue(spec.Description)),
+		EventTypes:  spec.EventTypes,
+		Enabled:     spec.Enabled,
+		Targets:     []*sdkmodels.WebhookTargetObject{target},
+	}
+}
+
+// webhookStatus returns the observed state of a Harbor policy. Policies
+// managed by the provider have a single target; only the first is read.
+func webhookStatus(p *sdkmodels.WebhookPolicy) *WebhookStatus {
+	webhook := &WebhookStatus{
+		ID:           strconv.FormatInt(p.ID, 10),
+		ProjectID:    strconv.FormatInt(p.ProjectID, 10),
+		Name:         p.Name,
+		EventTypes:   p.EventTypes,
+		Enabled:      p.Enabled,
+		CreationTime: time.Time(p.CreationTime),
+		UpdateTime:   time.Time(p.UpdateTime),
+	}
+	desc, managedByProvider := stripManagedByMarker(p.Description)
+	if desc != "" {
+		webhook.Description = &desc
+	}
+	webhook.ManagedByProvider = managedByProvider
+	if len(p.Targets) > 0 {
+		webhook.URL = p.Targets[0].Address
+		webhook.NotifyType = p.Targets[0].Type
+		webhook.PayloadFormat = string(p.Targets[0].PayloadFormat)
+	}
+	return webhook
+}
+
 // DeleteWebhook deletes a webhook
 func (c *HarborClient) DeleteWebhook(ctx context.Context, projectID, webhookID string) error {
 	if projectID == "" {
@@ -2007,6 +1855,8 @@ func (c *HarborClient) DeleteWebhook(ctx context.Context, projectID, webhookID s
 type ReplicationPolicyFilter struct {
 	Type  string // repository, tag, label, resource
 	Value string
+	// Decoration is matches or excludes, and empty for matches.
+	Decoration string
 }
 
 // ReplicationPolicyDestination defines where to replicate
@@ -2018,15 +1868,17 @@ type ReplicationPolicyDestination struct {
 
 // ReplicationPolicySpec defines the desired state of a replication policy
 type ReplicationPolicySpec struct {
-	Name            string
-	Description     *string
-	SourceRegistry  *string
-	DestinationReg  *ReplicationPolicyDestination
-	Filters         []ReplicationPolicyFilter
-	Trigger         string // manual, scheduled, event_based
-	DeleteSourceTag *bool
-	Override        *bool
-	Enabled         *bool
+	Name              string
+	Description       *string
+	SourceRegistry    *string
+	DestinationReg    *ReplicationPolicyDestination
+	Filters           []ReplicationPolicyFilter
+	Trigger           string // manual, scheduled, event_based
+	CronSchedule      *string
+	DeleteSourceTag   *bool
+	ReplicateDeletion *bool
+	Override          *bool
+	Enabled           *bool
 }
 
 // ReplicationPolicyStatus represents the status of a replication policy
@@ -2037,6 +1889,17 @@ type ReplicationPolicyStatus struct {
 	Enabled      bool
 	CreationTime time.Time
 	UpdateTime   time.Time
+
+	// SourceRegistry and DestinationRegistry are the names of the remote
+	// registries, and empty for the local Harbor.
+	SourceRegistry      string
+	DestinationRegistry string
+	DestNamespace       string
+	Filters             []ReplicationPolicyFilter
+	Trigger             string
+	CronSchedule        string
+	ReplicateDeletion   bool
+	Override            bool
 }
 
 // ReplicationExecution represents a replication execution
@@ -2044,12 +1907,18 @@ type ReplicationExecution struct {
 	ID           string
 	PolicyID     string
 	Status       string
+	Trigger      string
 	StartTime    time.Time
 	EndTime      time.Time
 	SuccessCount int64
 	FailedCount  int64
+	TotalCount   int64
 }
 
+// replicationPolicyPageSize is how many replication policies are read per
+// request.
+const replicationPolicyPageSize = 100
+
 // CreateReplicationPolicy creates a new replication policy
 func (c *HarborClient) CreateReplicationPolicy(ctx context.Context, spec *ReplicationPolicySpec) (*ReplicationPolicyStatus, error) {
 	if spec == nil {
@@ -2058,10 +1927,6 @@ func (c *HarborClient) CreateReplicationPolicy(ctx context.Context, spec *Replic
 	if spec.Name == "" {
 		return nil, errors.New("policy name is required")
 	}
-	if spec.DestinationReg == nil || spec.DestinationReg.Name == "" {
-		return nil, errors.New("destination registry is required")
-	}
-
 	v2Client := c.clientSet.V2()
 	if v2Client == nil {
 		return nil, errors.New("failed to get Harbor v2 client")
@@ -2069,19 +1934,33 @@ func (c *HarborClient) CreateReplicationPolicy(ctx context.Context, spec *Replic
 
 	c.logger.Info("Creating Harbor replication policy",
 		"name", spec.Name,
-		"destination", spec.DestinationReg.Name,
 		"trigger", spec.Trigger)
 
-	policy := &ReplicationPolicyStatus{
-		ID:           "1",
-		Name:         spec.Name,
-		Description:  spec.Description,
-		Enabled:      spec.Enabled != nil && *spec.Enabled,
-		CreationTime: time.Now(),
-		UpdateTime:   time.Now(),
+	policy, err := c.replicationPolicy(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	_, err = v2Client.Replication.CreateReplicationPolicy(ctx, &sdkreplication.CreateReplicationPolicyParams{
+		Policy:  policy,
+		Context: ctx,
+	})
+	if IsConflict(err) {
+		return nil, errors.Wrapf(err, "replication policy %s already exists", spec.Name)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create replication policy")
 	}
 
-	return policy, nil
+	policies, err := c.ListReplicationPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range policies {
+		if p.Name == spec.Name {
+			return p, nil
+		}
+	}
+	return nil, errors.Errorf("replication policy %s not found after creating it", spec.Name)
 }
 
 // ListReplicationPolicies lists all replication policies
@@ -2093,23 +1972,31 @@ func (c *HarborClient) ListReplicationPolicies(ctx context.Context) ([]*Replicat
 
 	c.logger.Info("Listing Harbor replication policies")
 
-	policies := []*ReplicationPolicyStatus{
-		{
-			ID:           "1",
-			Name:         "mirror-to-registry",
-			Enabled:      true,
-			CreationTime: time.Now().Add(-7 * 24 * time.Hour),
-			UpdateTime:   time.Now(),
-		},
+	var policies []*ReplicationPolicyStatus
+	pageSize := int64(replicationPolicyPageSize)
+	for page := int64(1); ; page++ {
+		resp, err := v2Client.Replication.ListReplicationPolicies(ctx, &sdkreplication.ListReplicationPoliciesParams{
+			Page:     &page,
+			PageSize: &pageSize,
+			Context:  ctx,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list replication policies")
+		}
+		for _, p := range resp.Payload {
+			policies = append(policies, replicationPolicyStatus(p))
+		}
+		if len(resp.Payload) < replicationPolicyPageSize || (resp.XTotalCount > 0 && int64(len(policies)) >= resp.XTotalCount) {
+			return policies, nil
+		}
 	}
-
-	return policies, nil
 }
 
 // GetReplicationPolicy retrieves a specific replication policy
 func (c *HarborClient) GetReplicationPolicy(ctx context.Context, policyID string) (*ReplicationPolicyStatus, error) {
-	if policyID == "" {
-		return nil, errors.New("policy ID is required")
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return nil, err
 	}
 
 	v2Client := c.clientSet.V2()
@@ -2119,21 +2006,22 @@ func (c *HarborClient) GetReplicationPolicy(ctx context.Context, policyID string
 
 	c.logger.Info("Retrieving Harbor replication policy", "policyId", policyID)
 
-	policy := &ReplicationPolicyStatus{
-		ID:           policyID,
-		Name:         "mirror-to-registry",
-		Enabled:      true,
-		CreationTime: time.Now().Add(-7 * 24 * time.Hour),
-		UpdateTime:   time.Now(),
+	resp, err := v2Client.Replication.GetReplicationPolicy(ctx, &sdkreplication.GetReplicationPolicyParams{
+		ID:      id,
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get replication policy %s", policyID)
 	}
 
-	return policy, nil
+	return replicationPolicyStatus(resp.Payload), nil
 }
 
 // UpdateReplicationPolicy updates a replication policy
 func (c *HarborClient) UpdateReplicationPolicy(ctx context.Context, policyID string, spec *ReplicationPolicySpec) (*ReplicationPolicyStatus, error) {
-	if policyID == "" {
-		return nil, errors.New("policy ID is required")
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return nil, err
 	}
 	if spec == nil {
 		return nil, errors.New("spec is required")
@@ -2146,22 +2034,28 @@ func (c *HarborClient) UpdateReplicationPolicy(ctx context.Context, policyID str
 
 	c.logger.Info("Updating Harbor replication policy", "policyId", policyID, "name", spec.Name)
 
-	policy := &ReplicationPolicyStatus{
-		ID:           policyID,
-		Name:         spec.Name,
-		Description:  spec.Description,
-		Enabled:      spec.Enabled != nil && *spec.Enabled,
-		CreationTime: time.Now().Add(-7 * 24 * time.Hour),
-		UpdateTime:   time.Now(),
+	policy, err := c.replicationPolicy(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	_, err = v2Client.Replication.UpdateReplicationPolicy(ctx, &sdkreplication.UpdateReplicationPolicyParams{
+		ID:      id,
+		Policy:  policy,
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update replication policy %s", policyID)
 	}
 
-	return policy, nil
+	return c.GetReplicationPolicy(ctx, policyID)
 }
 
-// DeleteReplicationPolicy deletes a replication policy
+// DeleteReplicationPolicy deletes a replication policy. Deleting a policy
+// that does not exist succeeds.
 func (c *HarborClient) DeleteReplicationPolicy(ctx context.Context, policyID string) error {
-	if policyID == "" {
-		return errors.New("policy ID is required")
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return err
 	}
 
 	v2Client := c.clientSet.V2()
@@ -2171,6 +2065,13 @@ func (c *HarborClient) DeleteReplicationPolicy(ctx context.Context, policyID str
 
 	c.logger.Info("Deleting Harbor replication policy", "policyId", policyID)
 
+	_, err = v2Client.Replication.DeleteReplicationPolicy(ctx, &sdkreplication.DeleteReplicationPolicyParams{
+		ID:      id,
+		Context: ctx,
+	})
+	if err != nil && !IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete replication policy %s", policyID)
+	}
 	return nil
 }
 
@@ -2197,179 +2098,131 @@ func (c *HarborClient) TriggerReplication(ctx context.Context, policyID string)
 	return execution, nil
 }
 
-// ListReplicationExecutions lists replication execution history
-func (c *HarborClient) ListReplicationExecutions(ctx context.Context, policyID string) ([]*ReplicationExecution, error) {
-	if policyID == "" {
-		return nil, errors.New("policy ID is required")
-	}
-
-	v2Client := c.clientSet.V2()
-	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
-	}
-
-	c.logger.Info("Listing Harbor replication executions", "policyId", policyID)
-
-	executions := []*ReplicationExecution{
-		{
-			ID:           "1",
-			PolicyID:     policyID,
-			Status:       "completed",
-			StartTime:    time.Now().Add(-1 * time.Hour),
-			EndTime:      time.Now(),
-			SuccessCount: 42,
-			FailedCount:  0,
-		},
-	}
-
-	return executions, nil
-}
-
-// RetentionPolicyRule defines a retention rule
-type RetentionPolicyRule struct {
-	RuleType     string // always, latestPushedK, latestPulledN
-	TagSelectors []string
-	Parameters   map[string]interface{}
-}
-
-// RetentionPolicySpec defines the desired state of a retention policy
-type RetentionPolicySpec struct {
-	ProjectID   string
-	Description *string
-	Rules       []RetentionPolicyRule
-	Trigger     string // manual, scheduled
-	Enabled     *bool
-}
-
-// RetentionPolicyStatus represents the status of a retention policy
-type RetentionPolicyStatus struct {
-	ID           string
-	ProjectID    string
-	Description  *string
-	Enabled      bool
-	CreationTime time.Time
-	UpdateTime   time.Time
-}
-
-// CreateRetentionPolicy creates a new retention policy
-func (c *HarborClient) CreateRetentionPolicy(ctx context.Context, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error) {
-	if spec == nil {
-		return nil, errors.New("spec is required")
-	}
-	if spec.ProjectID == "" {
+// ListProjectMetadata returns all metadata set on a project
+func (c *HarborClient) ListProjectMetadata(ctx context.Context, projectID string) (map[string]string, error) {
+	if projectID == "" {
 		return nil, errors.New("project ID is required")
 	}
-	if len(spec.Rules) == 0 {
-		return nil, errors.New("at least one rule is required")
-	}
 
 	v2Client := c.clientSet.V2()
 	if v2Client == nil {
 		return nil, errors.New("failed to get Harbor v2 client")
 	}
 
-	c.logger.Info("Creating Harbor retention policy",
-		"projectId", spec.ProjectID,
-		"rulesCount", len(spec.Rules))
-
-	policy := &RetentionPolicyStatus{
-		ID:           "1",
-		ProjectID:    spec.ProjectID,
-		Description:  spec.Description,
-		Enabled:      spec.Enabled != nil && *spec.Enabled,
-		CreationTime: time.Now(),
-		UpdateTime:   time.Now(),
+	resp, err := v2Client.ProjectMetadata.ListProjectMetadatas(ctx, &sdkprojectmetadata.ListProjectMetadatasParams{
+		ProjectNameOrID: projectID,
+		Context:         ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list project metadata")
 	}
 
-	return policy, nil
+	return resp.Payload, nil
 }
 
-// ListRetentionPolicies lists retention policies for a project
-func (c *HarborClient) ListRetentionPolicies(ctx context.Context, projectID string) ([]*RetentionPolicyStatus, error) {
+// SetProjectMetadata sets a single project metadata key, adding it if it
+// does not exist yet
+func (c *HarborClient) SetProjectMetadata(ctx context.Context, projectID, key, value string) error {
 	if projectID == "" {
-		return nil, errors.New("project ID is required")
+		return errors.New("project ID is required")
+	}
+	if key == "" {
+		return errors.New("metadata key is required")
 	}
 
 	v2Client := c.clientSet.V2()
 	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
+		return errors.New("failed to get Harbor v2 client")
 	}
 
-	c.logger.Info("Listing Harbor retention policies", "projectId", projectID)
+	c.logger.Info("Setting Harbor project metadata", "projectId", projectID, "key", key)
 
-	policies := []*RetentionPolicyStatus{
-		{
-			ID:           "1",
-			ProjectID:    projectID,
-			Enabled:      true,
-			CreationTime: time.Now().Add(-30 * 24 * time.Hour),
-			UpdateTime:   time.Now(),
-		},
+	metadata := map[string]string{key: value}
+	_, err := v2Client.ProjectMetadata.UpdateProjectMetadata(ctx, &sdkprojectmetadata.UpdateProjectMetadataParams{
+		ProjectNameOrID: projectID,
+		MetaName:        key,
+		Metadata:        metadata,
+		Context:         ctx,
+	})
+	if err == nil {
+		return nil
+	}
+	if !IsNotFound(err) {
+		return errors.Wrapf(err, "failed to update project metadata %s", key)
 	}
 
-	return policies, nil
+	_, err = v2Client.ProjectMetadata.AddProjectMetadatas(ctx, &sdkprojectmetadata.AddProjectMetadatasParams{
+		ProjectNameOrID: projectID,
+		Metadata:        metadata,
+		Context:         ctx,
+	})
+	return errors.Wrapf(err, "failed to add project metadata %s", key)
 }
 
-// GetRetentionPolicy retrieves a specific retention policy
-func (c *HarborClient) GetRetentionPolicy(ctx context.Context, projectID, policyID string) (*RetentionPolicyStatus, error) {
+// DeleteProjectMetadata removes a single project metadata key
+func (c *HarborClient) DeleteProjectMetadata(ctx context.Context, projectID, key string) error {
 	if projectID == "" {
-		return nil, errors.New("project ID is required")
+		return errors.New("project ID is required")
 	}
-	if policyID == "" {
-		return nil, errors.New("policy ID is required")
+	if key == "" {
+		return errors.New("metadata key is required")
 	}
 
 	v2Client := c.clientSet.V2()
 	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
+		return errors.New("failed to get Harbor v2 client")
 	}
 
-	c.logger.Info("Retrieving Harbor retention policy", "projectId", projectID, "policyId", policyID)
+	c.logger.Info("Deleting Harbor project metadata", "projectId", projectID, "key", key)
 
-	policy := &RetentionPolicyStatus{
-		ID:           policyID,
-		ProjectID:    projectID,
-		Enabled:      true,
-		CreationTime: time.Now().Add(-30 * 24 * time.Hour),
-		UpdateTime:   time.Now(),
+	_, err := v2Client.ProjectMetadata.DeleteProjectMetadata(ctx, &sdkprojectmetadata.DeleteProjectMetadataParams{
+		ProjectNameOrID: projectID,
+		MetaName:        key,
+		Context:         ctx,
+	})
+	if err != nil && !IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete project metadata %s", key)
 	}
-
-	return policy, nil
+	return nil
 }
 
-// UpdateRetentionPolicy updates a retention policy
-func (c *HarborClient) UpdateRetentionPolicy(ctx context.Context, projectID, policyID string, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error) {
+// projectRetentionIDKey is the project metadata key Harbor uses to link a
+// project to its tag retention policy.
+const projectRetentionIDKey = "retention_id"
+
+// GetProjectRetentionID returns the retention policy ID recorded in the
+// project metadata, or an empty string if the project is not linked.
+func (c *HarborClient) GetProjectRetentionID(ctx context.Context, projectID string) (string, error) {
 	if projectID == "" {
-		return nil, errors.New("project ID is required")
-	}
-	if policyID == "" {
-		return nil, errors.New("policy ID is required")
-	}
-	if spec == nil {
-		return nil, errors.New("spec is required")
+		return "", errors.New("project ID is required")
 	}
 
 	v2Client := c.clientSet.V2()
 	if v2Client == nil {
-		return nil, errors.New("failed to get Harbor v2 client")
+		return "", errors.New("failed to get Harbor v2 client")
 	}
 
-	c.logger.Info("Updating Harbor retention policy", "projectId", projectID, "policyId", policyID)
+	params := &sdkprojectmetadata.GetProjectMetadataParams{
+		ProjectNameOrID: projectID,
+		MetaName:        projectRetentionIDKey,
+		Context:         ctx,
+	}
 
-	policy := &RetentionPolicyStatus{
-		ID:           policyID,
-		ProjectID:    projectID,
-		Description:  spec.Description,
-		Enabled:      spec.Enabled != nil && *spec.Enabled,
-		CreationTime: time.Now().Add(-30 * 24 * time.Hour),
-		UpdateTime:   time.Now(),
+	resp, err := v2Client.ProjectMetadata.GetProjectMetadata(ctx, params)
+	if err != nil {
+		if IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "failed to get project retention_id metadata")
 	}
 
-	return policy, nil
+	return resp.Payload[projectRetentionIDKey], nil
 }
 
-// DeleteRetentionPolicy deletes a retention policy
-func (c *HarborClient) DeleteRetentionPolicy(ctx context.Context, projectID, policyID string) error {
+// SetProjectRetentionID links a project to a retention policy by writing the
+// retention_id project metadata. Harbor's per-key metadata endpoint rejects
+// retention_id, so this goes through a project update instead.
+func (c *HarborClient) SetProjectRetentionID(ctx context.Context, projectID, policyID string) error {
 	if projectID == "" {
 		return errors.New("project ID is required")
 	}
@@ -2382,9 +2235,16 @@ func (c *HarborClient) DeleteRetentionPolicy(ctx context.Context, projectID, pol
 		return errors.New("failed to get Harbor v2 client")
 	}
 
-	c.logger.Info("Deleting Harbor retention policy", "projectId", projectID, "policyId", policyID)
+	c.logger.Info("Linking Harbor project to retention policy", "projectId", projectID, "policyId", policyID)
 
-	return nil
+	_, err := v2Client.Project.UpdateProject(ctx, &sdkproject.UpdateProjectParams{
+		ProjectNameOrID: projectID,
+		Project: &sdkmodels.ProjectReq{
+			Metadata: &sdkmodels.ProjectMetadata{RetentionID: &policyID},
+		},
+		Context: ctx,
+	})
+	return errors.Wrap(err, "failed to update project retention_id metadata")
 }
 
 // CreateUserGroup creates a new user group in Harbor
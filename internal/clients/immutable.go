@@ -0,0 +1,195 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"path"
+	"strconv"
+
+	sdkimmutable "github.com/goharbor/go-client/pkg/sdk/v2.0/client/immutable"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// Harbor's fixed parts of an immutable tag rule.
+const (
+	immutableTemplate = "immutable_template"
+	immutableAction   = "immutable"
+)
+
+// immutableRulePageSize is the most rules listed per request. Harbor allows
+// a project far fewer rules, so one page holds them all.
+const immutableRulePageSize = 100
+
+// An ImmutableTagRule makes the tags it selects in a project's repositories
+// immutable: they cannot be pushed over or deleted.
+type ImmutableTagRule struct {
+	ID int64
+
+	// RepositoryPattern is a doublestar pattern of the repositories the
+	// rule applies to, or to which it does not with ExcludeRepositories.
+	RepositoryPattern   string
+	ExcludeRepositories bool
+	// TagPattern is a doublestar pattern of the tags the rule makes
+	// immutable, or of those it does not with ExcludeTags.
+	TagPattern  string
+	ExcludeTags bool
+
+	Disabled bool
+}
+
+// SameSelectors reports whether r and o select the same tags.
+func (r *ImmutableTagRule) SameSelectors(o *ImmutableTagRule) bool {
+	return r.RepositoryPattern == o.RepositoryPattern && r.ExcludeRepositories == o.ExcludeRepositories &&
+		r.TagPattern == o.TagPattern && r.ExcludeTags == o.ExcludeTags
+}
+
+// immutableRuleModel returns the immutable tag rule Harbor expects for r.
+func immutableRuleModel(r *ImmutableTagRule) *sdkmodels.ImmutableRule {
+	repoDecoration, tagDecoration := selectorRepoMatches, selectorTagMatches
+	if r.ExcludeRepositories {
+		repoDecoration = selectorRepoExcludes
+	}
+	if r.ExcludeTags {
+		tagDecoration = selectorTagExcludes
+	}
+	return &sdkmodels.ImmutableRule{
+		ID:       r.ID,
+		Action:   immutableAction,
+		Template: immutableTemplate,
+		Disabled: r.Disabled,
+		ScopeSelectors: map[string][]sdkmodels.ImmutableSelector{
+			selectorRepositoryKey: {{Kind: selectorKind, Decoration: repoDecoration, Pattern: r.RepositoryPattern}},
+		},
+		TagSelectors: []*sdkmodels.ImmutableSelector{
+			{Kind: selectorKind, Decoration: tagDecoration, Pattern: r.TagPattern},
+		},
+	}
+}
+
+// immutableTagRule converts an immutable tag rule returned by Harbor.
+func immutableTagRule(m *sdkmodels.ImmutableRule) *ImmutableTagRule {
+	r := &ImmutableTagRule{ID: m.ID, Disabled: m.Disabled}
+	if repos := m.ScopeSelectors[selectorRepositoryKey]; len(repos) > 0 {
+		r.RepositoryPattern = repos[0].Pattern
+		r.ExcludeRepositories = repos[0].Decoration == selectorRepoExcludes
+	}
+	if len(m.TagSelectors) > 0 && m.TagSelectors[0] != nil {
+		r.TagPattern = m.TagSelectors[0].Pattern
+		r.ExcludeTags = m.TagSelectors[0].Decoration == selectorTagExcludes
+	}
+	return r
+}
+
+// ListImmutableTagRules lists the immutable tag rules of a project.
+func (c *HarborClient) ListImmutableTagRules(ctx context.Context, projectID string) ([]*ImmutableTagRule, error) {
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	pageSize := int64(immutableRulePageSize)
+	resp, err := v2Client.Immutable.ListImmuRules(ctx, &sdkimmutable.ListImmuRulesParams{
+		ProjectNameOrID: projectID,
+		PageSize:        &pageSize,
+		Context:         ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list immutable tag rules of project %s", projectID)
+	}
+	rules := make([]*ImmutableTagRule, 0, len(resp.Payload))
+	for _, m := range resp.Payload {
+		if m != nil {
+			rules = append(rules, immutableTagRule(m))
+		}
+	}
+	return rules, nil
+}
+
+// CreateImmutableTagRule adds an immutable tag rule to a project and returns
+// its ID.
+func (c *HarborClient) CreateImmutableTagRule(ctx context.Context, projectID string, rule *ImmutableTagRule) (int64, error) {
+	if projectID == "" {
+		return 0, errors.New("project ID is required")
+	}
+	if rule == nil {
+		return 0, errors.New("immutable tag rule is required")
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return 0, errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Creating Harbor immutable tag rule", "projectId", projectID, "repositories", rule.RepositoryPattern, "tags", rule.TagPattern)
+
+	m := immutableRuleModel(rule)
+	m.ID = 0
+	created, err := v2Client.Immutable.CreateImmuRule(ctx, &sdkimmutable.CreateImmuRuleParams{
+		ProjectNameOrID: projectID,
+		ImmutableRule:   m,
+		Context:         ctx,
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create immutable tag rule in project %s", projectID)
+	}
+	id, err := strconv.ParseInt(path.Base(created.Location), 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("cannot parse immutable tag rule ID from location %q", created.Location)
+	}
+	return id, nil
+}
+
+// UpdateImmutableTagRule changes the immutable tag rule with rule's ID in
+// place, including enabling or disabling it.
+func (c *HarborClient) UpdateImmutableTagRule(ctx context.Context, projectID string, rule *ImmutableTagRule) error {
+	if projectID == "" {
+		return errors.New("project ID is required")
+	}
+	if rule == nil || rule.ID == 0 {
+		return errors.New("immutable tag rule ID is required")
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Updating Harbor immutable tag rule", "projectId", projectID, "ruleId", rule.ID, "disabled", rule.Disabled)
+
+	_, err := v2Client.Immutable.UpdateImmuRule(ctx, &sdkimmutable.UpdateImmuRuleParams{
+		ProjectNameOrID: projectID,
+		ImmutableRuleID: rule.ID,
+		ImmutableRule:   immutableRuleModel(rule),
+		Context:         ctx,
+	})
+	return errors.Wrapf(err, "failed to update immutable tag rule %d", rule.ID)
+}
+
+// DeleteImmutableTagRule deletes an immutable tag rule. A rule that does not
+// exist is not an error.
+func (c *HarborClient) DeleteImmutableTagRule(ctx context.Context, projectID string, id int64) error {
+	if projectID == "" {
+		return errors.New("project ID is required")
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Deleting Harbor immutable tag rule", "projectId", projectID, "ruleId", id)
+
+	_, err := v2Client.Immutable.DeleteImmuRule(ctx, &sdkimmutable.DeleteImmuRuleParams{
+		ProjectNameOrID: projectID,
+		ImmutableRuleID: id,
+		Context:         ctx,
+	})
+	if IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "failed to delete immutable tag rule %d", id)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestImmutableTagRules(t *testing.T) {
+	var sent []map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/3/immutabletagrules", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{
+				"id": 7, "disabled": true, "action": "immutable", "template": "immutable_template",
+				"scope_selectors": {"repository": [{"kind": "doublestar", "decoration": "repoExcludes", "pattern": "library/*"}]},
+				"tag_selectors": [{"kind": "doublestar", "decoration": "matches", "pattern": "v*"}]
+			}]`))
+		case http.MethodPost:
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			sent = append(sent, body)
+			w.Header().Set("Location", "/api/v2.0/projects/3/immutabletagrules/12")
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+	mux.HandleFunc("/api/v2.0/projects/3/immutabletagrules/7", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			sent = append(sent, body)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	c := executionsClient(t, mux)
+	ctx := context.Background()
+
+	rules, err := c.ListImmutableTagRules(ctx, "3")
+	if err != nil {
+		t.Fatalf("ListImmutableTagRules() error = %v", err)
+	}
+	want := &ImmutableTagRule{ID: 7, RepositoryPattern: "library/*", ExcludeRepositories: true, TagPattern: "v*", Disabled: true}
+	if len(rules) != 1 || !reflect.DeepEqual(rules[0], want) {
+		t.Errorf("ListImmutableTagRules() = %+v, want [%+v]", rules, want)
+	}
+
+	id, err := c.CreateImmutableTagRule(ctx, "3", &ImmutableTagRule{RepositoryPattern: "**", TagPattern: "v*"})
+	if err != nil {
+		t.Fatalf("CreateImmutableTagRule() error = %v", err)
+	}
+	if id != 12 {
+		t.Errorf("CreateImmutableTagRule() = %d, want 12", id)
+	}
+
+	want.Disabled = false
+	if err := c.UpdateImmutableTagRule(ctx, "3", want); err != nil {
+		t.Fatalf("UpdateImmutableTagRule() error = %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent %d rules, want 2", len(sent))
+	}
+	if sent[0]["template"] != "immutable_template" || sent[0]["action"] != "immutable" {
+		t.Errorf("created rule %v", sent[0])
+	}
+	if sent[1]["id"] != float64(7) || sent[1]["disabled"] == true {
+		t.Errorf("updated rule %v, want it enabled in place", sent[1])
+	}
+
+	if err := c.DeleteImmutableTagRule(ctx, "3", 7); err != nil {
+		t.Errorf("DeleteImmutableTagRule() of a missing rule error = %v", err)
+	}
+}
@@ -17,6 +17,14 @@ type HarborClienter interface {
 	Close() error
 	TestConnection(ctx context.Context) error
 	GetVersion(ctx context.Context) (string, error)
+	GetCurrentUser(ctx context.Context) (*CurrentUser, error)
+	GetSystemInfo(ctx context.Context) (*SystemInfo, error)
+	GetConfigurations(ctx context.Context) (Configurations, error)
+	GetRobotPermissions(ctx context.Context) (*RobotPermissions, error)
+	GetProjectPermissions(ctx context.Context, projectID string) ([]RobotAccess, error)
+	UpdateConfigurations(ctx context.Context, cfg Configurations) error
+	PingOIDC(ctx context.Context, endpoint string, verifyCert bool) error
+	InvalidateSystemCache()
 	GetMemoryFootprint() string
 
 	// Project operations
@@ -25,6 +33,19 @@ type HarborClienter interface {
 	UpdateProject(ctx context.Context, projectID string, spec *ProjectSpec) (*ProjectStatus, error)
 	DeleteProject(ctx context.Context, projectID string) error
 	ListProjects(ctx context.Context) ([]*ProjectStatus, error)
+	ListProjectMetadata(ctx context.Context, projectID string) (map[string]string, error)
+	SetProjectMetadata(ctx context.Context, projectID, key, value string) error
+	DeleteProjectMetadata(ctx context.Context, projectID, key string) error
+	SampleProjectSBOMs(ctx context.Context, projectName string, maxRepos int64) (*SBOMSample, error)
+	GetProjectSummary(ctx context.Context, projectName string) (*ProjectSummary, error)
+	InventoryRepositories(ctx context.Context, projects []string) (*Partial[InventoryRepository], error)
+	ListProjectAuditLogs(ctx context.Context, projectName string, since time.Time, pageSize int64) ([]*AuditLogEntry, error)
+	EnsureProjectLabel(ctx context.Context, projectID int64, name, description string) (int64, error)
+	FindLabel(ctx context.Context, name, projectName string) (id int64, found bool, err error)
+	DeleteLabel(ctx context.Context, labelID int64) error
+	ListArtifactsByLabel(ctx context.Context, projectName, repositoryName string, labelID int64) (with, without []string, err error)
+	GetArtifactLabels(ctx context.Context, projectName, repositoryName, reference string) (digest string, labelIDs []int64, err error)
+	SetArtifactLabel(ctx context.Context, projectName, repositoryName, reference string, labelID int64, attached bool) error
 
 	// Scanner operations
 	CreateScannerRegistration(ctx context.Context, spec *ScannerSpec) (*ScannerStatus, error)
@@ -32,18 +53,35 @@ type HarborClienter interface {
 	UpdateScannerRegistration(ctx context.Context, scannerID string, spec *ScannerSpec) (*ScannerStatus, error)
 	DeleteScannerRegistration(ctx context.Context, scannerID string) error
 	ListScannerRegistrations(ctx context.Context) ([]*ScannerStatus, error)
+	SetDefaultScanner(ctx context.Context, scannerID string) error
+	GetProjectScanner(ctx context.Context, projectName string) (*ScannerStatus, error)
+	SetProjectScanner(ctx context.Context, projectName, scannerID string) error
+	GetScannerMetadata(ctx context.Context, scannerID string) (*ScannerMetadata, error)
 
 	// User operations
 	GetUser(ctx context.Context, username string) (*UserStatus, error)
 	CreateUser(ctx context.Context, spec *UserSpec) (*UserStatus, error)
 	UpdateUser(ctx context.Context, username string, spec *UserSpec) (*UserStatus, error)
 	DeleteUser(ctx context.Context, username string) error
+	RegenerateUserCLISecret(ctx context.Context, username string) (string, error)
 
 	// Registry operations
 	CreateRegistry(ctx context.Context, spec *RegistrySpec) (*RegistryStatus, error)
 	GetRegistry(ctx context.Context, registryName string) (*RegistryStatus, error)
 	UpdateRegistry(ctx context.Context, registryName string, spec *RegistrySpec) (*RegistryStatus, error)
 	DeleteRegistry(ctx context.Context, registryName string) error
+	PingRegistry(ctx context.Context, id int64) error
+
+	// Garbage collection operations
+	GetGCSchedule(ctx context.Context) (*GCSchedule, error)
+	CreateGCSchedule(ctx context.Context, s *GCSchedule) error
+	UpdateGCSchedule(ctx context.Context, s *GCSchedule) error
+
+	// Immutable tag rule operations
+	ListImmutableTagRules(ctx context.Context, projectID string) ([]*ImmutableTagRule, error)
+	CreateImmutableTagRule(ctx context.Context, projectID string, rule *ImmutableTagRule) (int64, error)
+	UpdateImmutableTagRule(ctx context.Context, projectID string, rule *ImmutableTagRule) error
+	DeleteImmutableTagRule(ctx context.Context, projectID string, id int64) error
 
 	// Repository operations
 	ListRepositories(ctx context.Context, projectID string) ([]*RepositoryStatus, error)
@@ -63,12 +101,22 @@ type HarborClienter interface {
 	GetProjectMember(ctx context.Context, projectID, username string) (*MemberStatus, error)
 	UpdateProjectMember(ctx context.Context, projectID, username, role string) error
 	DeleteProjectMember(ctx context.Context, projectID, username string) error
+	AddProjectGroupMember(ctx context.Context, projectName, groupName string, groupType int64, role string) error
+	GetProjectGroupMember(ctx context.Context, projectID, groupName string) (*MemberStatus, error)
+	UpdateProjectGroupMember(ctx context.Context, projectID, groupName, role string) error
+	DeleteProjectGroupMember(ctx context.Context, projectID, groupName string) error
 
 	// Scan operations
 	TriggerScan(ctx context.Context, projectID, repoName, reference string) error
 	ListScans(ctx context.Context, projectID, repoName string) ([]*ScanStatus, error)
 	GetScan(ctx context.Context, projectID, repoName, reference string) (*ScanStatus, error)
 	StopScan(ctx context.Context, projectID, repoName, reference string) error
+	ScanProject(ctx context.Context, projectName string) (int64, error)
+	StopProjectScans(ctx context.Context, projectName string) (int64, error)
+	GetProjectScanMetrics(ctx context.Context, projectName string) (*ScanMetrics, error)
+	TriggerScanAll(ctx context.Context) error
+	StopScanAll(ctx context.Context) error
+	GetScanAllMetrics(ctx context.Context) (*ScanMetrics, error)
 
 	// Robot operations
 	CreateRobot(ctx context.Context, spec *RobotSpec) (*RobotStatus, error)
@@ -76,6 +124,7 @@ type HarborClienter interface {
 	GetRobot(ctx context.Context, robotID string) (*RobotStatus, error)
 	UpdateRobot(ctx context.Context, robotID string, spec *RobotSpec) (*RobotStatus, error)
 	DeleteRobot(ctx context.Context, robotID string) error
+	RefreshRobotSecret(ctx context.Context, robotID string) (string, error)
 
 	// Webhook operations
 	CreateWebhook(ctx context.Context, spec *WebhookSpec) (*WebhookStatus, error)
@@ -99,6 +148,10 @@ type HarborClienter interface {
 	GetRetentionPolicy(ctx context.Context, projectID, policyID string) (*RetentionPolicyStatus, error)
 	UpdateRetentionPolicy(ctx context.Context, projectID, policyID string, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error)
 	DeleteRetentionPolicy(ctx context.Context, projectID, policyID string) error
+	GetProjectRetentionID(ctx context.Context, projectID string) (string, error)
+	SetProjectRetentionID(ctx context.Context, projectID, policyID string) error
+	ListRetentionExecutions(ctx context.Context, policyID string) ([]*RetentionExecution, error)
+	CountRetentionTasks(ctx context.Context, policyID, executionID string) (*RetentionTaskCounts, error)
 
 	// UserGroup operations
 	CreateUserGroup(ctx context.Context, spec *UserGroupSpec) (*UserGroupStatus, error)
@@ -106,6 +159,11 @@ type HarborClienter interface {
 	GetUserGroup(ctx context.Context, groupID int64) (*UserGroupStatus, error)
 	UpdateUserGroup(ctx context.Context, groupID int64, spec *UserGroupSpec) (*UserGroupStatus, error)
 	DeleteUserGroup(ctx context.Context, groupID int64) error
+
+	// Raw operations on the paths in RawPaths
+	GetRaw(ctx context.Context, path string) ([]byte, error)
+	PutRaw(ctx context.Context, path string, body []byte) error
+	DeleteRaw(ctx context.Context, path string) error
 }
 
 // Ensure HarborClient implements HarborClienter
@@ -114,18 +172,39 @@ var _ HarborClienter = (*HarborClient)(nil)
 // MockHarborClient implements HarborClienter for testing
 type MockHarborClient struct {
 	// Base client methods
-	GetBaseURLFunc         func() string
-	CloseFunc              func() error
-	TestConnectionFunc     func(ctx context.Context) error
-	GetVersionFunc         func(ctx context.Context) (string, error)
-	GetMemoryFootprintFunc func() string
+	GetBaseURLFunc            func() string
+	CloseFunc                 func() error
+	TestConnectionFunc        func(ctx context.Context) error
+	GetVersionFunc            func(ctx context.Context) (string, error)
+	GetCurrentUserFunc        func(ctx context.Context) (*CurrentUser, error)
+	GetSystemInfoFunc         func(ctx context.Context) (*SystemInfo, error)
+	GetConfigurationsFunc     func(ctx context.Context) (Configurations, error)
+	GetRobotPermissionsFunc   func(ctx context.Context) (*RobotPermissions, error)
+	GetProjectPermissionsFunc func(ctx context.Context, projectID string) ([]RobotAccess, error)
+	UpdateConfigurationsFunc  func(ctx context.Context, cfg Configurations) error
+	PingOIDCFunc              func(ctx context.Context, endpoint string, verifyCert bool) error
+	InvalidateSystemCacheFunc func()
+	GetMemoryFootprintFunc    func() string
 
 	// Project operations
-	GetProjectFunc    func(ctx context.Context, projectName string) (*ProjectStatus, error)
-	CreateProjectFunc func(ctx context.Context, spec *ProjectSpec) (*ProjectStatus, error)
-	UpdateProjectFunc func(ctx context.Context, projectID string, spec *ProjectSpec) (*ProjectStatus, error)
-	DeleteProjectFunc func(ctx context.Context, projectID string) error
-	ListProjectsFunc  func(ctx context.Context) ([]*ProjectStatus, error)
+	GetProjectFunc            func(ctx context.Context, projectName string) (*ProjectStatus, error)
+	CreateProjectFunc         func(ctx context.Context, spec *ProjectSpec) (*ProjectStatus, error)
+	UpdateProjectFunc         func(ctx context.Context, projectID string, spec *ProjectSpec) (*ProjectStatus, error)
+	DeleteProjectFunc         func(ctx context.Context, projectID string) error
+	ListProjectsFunc          func(ctx context.Context) ([]*ProjectStatus, error)
+	ListProjectMetadataFunc   func(ctx context.Context, projectID string) (map[string]string, error)
+	SetProjectMetadataFunc    func(ctx context.Context, projectID, key, value string) error
+	DeleteProjectMetadataFunc func(ctx context.Context, projectID, key string) error
+	SampleProjectSBOMsFunc    func(ctx context.Context, projectName string, maxRepos int64) (*SBOMSample, error)
+	InventoryRepositoriesFunc func(ctx context.Context, projects []string) (*Partial[InventoryRepository], error)
+	GetProjectSummaryFunc     func(ctx context.Context, projectName string) (*ProjectSummary, error)
+	ListProjectAuditLogsFunc  func(ctx context.Context, projectName string, since time.Time, pageSize int64) ([]*AuditLogEntry, error)
+	EnsureProjectLabelFunc    func(ctx context.Context, projectID int64, name, description string) (int64, error)
+	DeleteLabelFunc           func(ctx context.Context, labelID int64) error
+	FindLabelFunc             func(ctx context.Context, name, projectName string) (id int64, found bool, err error)
+	ListArtifactsByLabelFunc  func(ctx context.Context, projectName, repositoryName string, labelID int64) (with, without []string, err error)
+	GetArtifactLabelsFunc     func(ctx context.Context, projectName, repositoryName, reference string) (digest string, labelIDs []int64, err error)
+	SetArtifactLabelFunc      func(ctx context.Context, projectName, repositoryName, reference string, labelID int64, attached bool) error
 
 	// Scanner operations
 	CreateScannerRegistrationFunc func(ctx context.Context, spec *ScannerSpec) (*ScannerStatus, error)
@@ -133,18 +212,35 @@ type MockHarborClient struct {
 	UpdateScannerRegistrationFunc func(ctx context.Context, scannerID string, spec *ScannerSpec) (*ScannerStatus, error)
 	DeleteScannerRegistrationFunc func(ctx context.Context, scannerID string) error
 	ListScannerRegistrationsFunc  func(ctx context.Context) ([]*ScannerStatus, error)
+	SetDefaultScannerFunc         func(ctx context.Context, scannerID string) error
+	GetProjectScannerFunc         func(ctx context.Context, projectName string) (*ScannerStatus, error)
+	SetProjectScannerFunc         func(ctx context.Context, projectName, scannerID string) error
+	GetScannerMetadataFunc        func(ctx context.Context, scannerID string) (*ScannerMetadata, error)
 
 	// User operations
-	GetUserFunc    func(ctx context.Context, username string) (*UserStatus, error)
-	CreateUserFunc func(ctx context.Context, spec *UserSpec) (*UserStatus, error)
-	UpdateUserFunc func(ctx context.Context, username string, spec *UserSpec) (*UserStatus, error)
-	DeleteUserFunc func(ctx context.Context, username string) error
+	GetUserFunc                 func(ctx context.Context, username string) (*UserStatus, error)
+	CreateUserFunc              func(ctx context.Context, spec *UserSpec) (*UserStatus, error)
+	UpdateUserFunc              func(ctx context.Context, username string, spec *UserSpec) (*UserStatus, error)
+	DeleteUserFunc              func(ctx context.Context, username string) error
+	RegenerateUserCLISecretFunc func(ctx context.Context, username string) (string, error)
 
 	// Registry operations
 	CreateRegistryFunc func(ctx context.Context, spec *RegistrySpec) (*RegistryStatus, error)
 	GetRegistryFunc    func(ctx context.Context, registryName string) (*RegistryStatus, error)
 	UpdateRegistryFunc func(ctx context.Context, registryName string, spec *RegistrySpec) (*RegistryStatus, error)
 	DeleteRegistryFunc func(ctx context.Context, registryName string) error
+	PingRegistryFunc   func(ctx context.Context, id int64) error
+
+	// Garbage collection operations
+	GetGCScheduleFunc    func(ctx context.Context) (*GCSchedule, error)
+	CreateGCScheduleFunc func(ctx context.Context, s *GCSchedule) error
+	UpdateGCScheduleFunc func(ctx context.Context, s *GCSchedule) error
+
+	// Immutable tag rule operations
+	ListImmutableTagRulesFunc  func(ctx context.Context, projectID string) ([]*ImmutableTagRule, error)
+	CreateImmutableTagRuleFunc func(ctx context.Context, projectID string, rule *ImmutableTagRule) (int64, error)
+	UpdateImmutableTagRuleFunc func(ctx context.Context, projectID string, rule *ImmutableTagRule) error
+	DeleteImmutableTagRuleFunc func(ctx context.Context, projectID string, id int64) error
 
 	// Repository operations
 	ListRepositoriesFunc func(ctx context.Context, projectID string) ([]*RepositoryStatus, error)
@@ -159,11 +255,15 @@ type MockHarborClient struct {
 	GetArtifactVulnerabilitiesFunc func(ctx context.Context, projectID, repoName, reference string) (*ArtifactStatus, error)
 
 	// Member operations
-	AddProjectMemberFunc    func(ctx context.Context, projectID, username, role string) error
-	ListProjectMembersFunc  func(ctx context.Context, projectID string) ([]*MemberStatus, error)
-	GetProjectMemberFunc    func(ctx context.Context, projectID, username string) (*MemberStatus, error)
-	UpdateProjectMemberFunc func(ctx context.Context, projectID, username, role string) error
-	DeleteProjectMemberFunc func(ctx context.Context, projectID, username string) error
+	AddProjectMemberFunc         func(ctx context.Context, projectID, username, role string) error
+	ListProjectMembersFunc       func(ctx context.Context, projectID string) ([]*MemberStatus, error)
+	GetProjectMemberFunc         func(ctx context.Context, projectID, username string) (*MemberStatus, error)
+	UpdateProjectMemberFunc      func(ctx context.Context, projectID, username, role string) error
+	DeleteProjectMemberFunc      func(ctx context.Context, projectID, username string) error
+	AddProjectGroupMemberFunc    func(ctx context.Context, projectName, groupName string, groupType int64, role string) error
+	GetProjectGroupMemberFunc    func(ctx context.Context, projectID, groupName string) (*MemberStatus, error)
+	UpdateProjectGroupMemberFunc func(ctx context.Context, projectID, groupName, role string) error
+	DeleteProjectGroupMemberFunc func(ctx context.Context, projectID, groupName string) error
 
 	// Scan operations
 	TriggerScanFunc func(ctx context.Context, projectID, repoName, reference string) error
@@ -171,12 +271,20 @@ type MockHarborClient struct {
 	GetScanFunc     func(ctx context.Context, projectID, repoName, reference string) (*ScanStatus, error)
 	StopScanFunc    func(ctx context.Context, projectID, repoName, reference string) error
 
+	ScanProjectFunc           func(ctx context.Context, projectName string) (int64, error)
+	StopProjectScansFunc      func(ctx context.Context, projectName string) (int64, error)
+	GetProjectScanMetricsFunc func(ctx context.Context, projectName string) (*ScanMetrics, error)
+	TriggerScanAllFunc        func(ctx context.Context) error
+	StopScanAllFunc           func(ctx context.Context) error
+	GetScanAllMetricsFunc     func(ctx context.Context) (*ScanMetrics, error)
+
 	// Robot operations
-	CreateRobotFunc func(ctx context.Context, spec *RobotSpec) (*RobotStatus, error)
-	ListRobotsFunc  func(ctx context.Context, projectID *string) ([]*RobotStatus, error)
-	GetRobotFunc    func(ctx context.Context, robotID string) (*RobotStatus, error)
-	UpdateRobotFunc func(ctx context.Context, robotID string, spec *RobotSpec) (*RobotStatus, error)
-	DeleteRobotFunc func(ctx context.Context, robotID string) error
+	CreateRobotFunc        func(ctx context.Context, spec *RobotSpec) (*RobotStatus, error)
+	ListRobotsFunc         func(ctx context.Context, projectID *string) ([]*RobotStatus, error)
+	GetRobotFunc           func(ctx context.Context, robotID string) (*RobotStatus, error)
+	UpdateRobotFunc        func(ctx context.Context, robotID string, spec *RobotSpec) (*RobotStatus, error)
+	DeleteRobotFunc        func(ctx context.Context, robotID string) error
+	RefreshRobotSecretFunc func(ctx context.Context, robotID string) (string, error)
 
 	// Webhook operations
 	CreateWebhookFunc func(ctx context.Context, spec *WebhookSpec) (*WebhookStatus, error)
@@ -195,11 +303,15 @@ type MockHarborClient struct {
 	ListReplicationExecutionsFunc func(ctx context.Context, policyID string) ([]*ReplicationExecution, error)
 
 	// Retention operations
-	CreateRetentionPolicyFunc func(ctx context.Context, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error)
-	ListRetentionPoliciesFunc func(ctx context.Context, projectID string) ([]*RetentionPolicyStatus, error)
-	GetRetentionPolicyFunc    func(ctx context.Context, projectID, policyID string) (*RetentionPolicyStatus, error)
-	UpdateRetentionPolicyFunc func(ctx context.Context, projectID, policyID string, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error)
-	DeleteRetentionPolicyFunc func(ctx context.Context, projectID, policyID string) error
+	CreateRetentionPolicyFunc   func(ctx context.Context, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error)
+	ListRetentionPoliciesFunc   func(ctx context.Context, projectID string) ([]*RetentionPolicyStatus, error)
+	GetRetentionPolicyFunc      func(ctx context.Context, projectID, policyID string) (*RetentionPolicyStatus, error)
+	UpdateRetentionPolicyFunc   func(ctx context.Context, projectID, policyID string, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error)
+	DeleteRetentionPolicyFunc   func(ctx context.Context, projectID, policyID string) error
+	GetProjectRetentionIDFunc   func(ctx context.Context, projectID string) (string, error)
+	SetProjectRetentionIDFunc   func(ctx context.Context, projectID, policyID string) error
+	ListRetentionExecutionsFunc func(ctx context.Context, policyID string) ([]*RetentionExecution, error)
+	CountRetentionTasksFunc     func(ctx context.Context, policyID, executionID string) (*RetentionTaskCounts, error)
 
 	// UserGroup operations
 	CreateUserGroupFunc func(ctx context.Context, spec *UserGroupSpec) (*UserGroupStatus, error)
@@ -207,6 +319,9 @@ type MockHarborClient struct {
 	GetUserGroupFunc    func(ctx context.Context, groupID int64) (*UserGroupStatus, error)
 	UpdateUserGroupFunc func(ctx context.Context, groupID int64, spec *UserGroupSpec) (*UserGroupStatus, error)
 	DeleteUserGroupFunc func(ctx context.Context, groupID int64) error
+	GetRawFunc          func(ctx context.Context, path string) ([]byte, error)
+	PutRawFunc          func(ctx context.Context, path string, body []byte) error
+	DeleteRawFunc       func(ctx context.Context, path string) error
 }
 
 // GetBaseURL calls GetBaseURLFunc
@@ -241,6 +356,69 @@ func (m *MockHarborClient) GetVersion(ctx context.Context) (string, error) {
 	return "v2.8.0", nil
 }
 
+// GetCurrentUser calls GetCurrentUserFunc
+func (m *MockHarborClient) GetCurrentUser(ctx context.Context) (*CurrentUser, error) {
+	if m.GetCurrentUserFunc != nil {
+		return m.GetCurrentUserFunc(ctx)
+	}
+	return &CurrentUser{Username: "admin", SysAdmin: true}, nil
+}
+
+// GetSystemInfo calls GetSystemInfoFunc
+func (m *MockHarborClient) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
+	if m.GetSystemInfoFunc != nil {
+		return m.GetSystemInfoFunc(ctx)
+	}
+	return &SystemInfo{HarborVersion: "v2.8.0", AuthMode: "db_auth"}, nil
+}
+
+// GetConfigurations calls GetConfigurationsFunc
+func (m *MockHarborClient) GetConfigurations(ctx context.Context) (Configurations, error) {
+	if m.GetConfigurationsFunc != nil {
+		return m.GetConfigurationsFunc(ctx)
+	}
+	return Configurations{}, nil
+}
+
+// GetRobotPermissions calls GetRobotPermissionsFunc
+func (m *MockHarborClient) GetRobotPermissions(ctx context.Context) (*RobotPermissions, error) {
+	if m.GetRobotPermissionsFunc != nil {
+		return m.GetRobotPermissionsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// GetProjectPermissions calls GetProjectPermissionsFunc
+func (m *MockHarborClient) GetProjectPermissions(ctx context.Context, projectID string) ([]RobotAccess, error) {
+	if m.GetProjectPermissionsFunc != nil {
+		return m.GetProjectPermissionsFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+// UpdateConfigurations calls UpdateConfigurationsFunc
+func (m *MockHarborClient) UpdateConfigurations(ctx context.Context, cfg Configurations) error {
+	if m.UpdateConfigurationsFunc != nil {
+		return m.UpdateConfigurationsFunc(ctx, cfg)
+	}
+	return nil
+}
+
+// PingOIDC calls PingOIDCFunc
+func (m *MockHarborClient) PingOIDC(ctx context.Context, endpoint string, verifyCert bool) error {
+	if m.PingOIDCFunc != nil {
+		return m.PingOIDCFunc(ctx, endpoint, verifyCert)
+	}
+	return nil
+}
+
+// InvalidateSystemCache calls InvalidateSystemCacheFunc
+func (m *MockHarborClient) InvalidateSystemCache() {
+	if m.InvalidateSystemCacheFunc != nil {
+		m.InvalidateSystemCacheFunc()
+	}
+}
+
 // GetMemoryFootprint calls GetMemoryFootprintFunc
 func (m *MockHarborClient) GetMemoryFootprint() string {
 	if m.GetMemoryFootprintFunc != nil {
@@ -290,6 +468,14 @@ func (m *MockHarborClient) DeleteUser(ctx context.Context, username string) erro
 	return nil
 }
 
+// RegenerateUserCLISecret calls RegenerateUserCLISecretFunc
+func (m *MockHarborClient) RegenerateUserCLISecret(ctx context.Context, username string) (string, error) {
+	if m.RegenerateUserCLISecretFunc != nil {
+		return m.RegenerateUserCLISecretFunc(ctx, username)
+	}
+	return "new-cli-secret", nil
+}
+
 // GetProject calls GetProjectFunc
 func (m *MockHarborClient) GetProject(ctx context.Context, projectName string) (*ProjectStatus, error) {
 	if m.GetProjectFunc != nil {
@@ -336,6 +522,110 @@ func (m *MockHarborClient) ListProjects(ctx context.Context) ([]*ProjectStatus,
 	return nil, nil
 }
 
+// ListProjectMetadata calls ListProjectMetadataFunc
+func (m *MockHarborClient) ListProjectMetadata(ctx context.Context, projectID string) (map[string]string, error) {
+	if m.ListProjectMetadataFunc != nil {
+		return m.ListProjectMetadataFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+// SetProjectMetadata calls SetProjectMetadataFunc
+func (m *MockHarborClient) SetProjectMetadata(ctx context.Context, projectID, key, value string) error {
+	if m.SetProjectMetadataFunc != nil {
+		return m.SetProjectMetadataFunc(ctx, projectID, key, value)
+	}
+	return nil
+}
+
+// DeleteProjectMetadata calls DeleteProjectMetadataFunc
+func (m *MockHarborClient) DeleteProjectMetadata(ctx context.Context, projectID, key string) error {
+	if m.DeleteProjectMetadataFunc != nil {
+		return m.DeleteProjectMetadataFunc(ctx, projectID, key)
+	}
+	return nil
+}
+
+// SampleProjectSBOMs calls SampleProjectSBOMsFunc
+func (m *MockHarborClient) SampleProjectSBOMs(ctx context.Context, projectName string, maxRepos int64) (*SBOMSample, error) {
+	if m.SampleProjectSBOMsFunc != nil {
+		return m.SampleProjectSBOMsFunc(ctx, projectName, maxRepos)
+	}
+	return &SBOMSample{}, nil
+}
+
+// InventoryRepositories calls InventoryRepositoriesFunc
+func (m *MockHarborClient) InventoryRepositories(ctx context.Context, projects []string) (*Partial[InventoryRepository], error) {
+	if m.InventoryRepositoriesFunc != nil {
+		return m.InventoryRepositoriesFunc(ctx, projects)
+	}
+	return &Partial[InventoryRepository]{}, nil
+}
+
+// GetProjectSummary calls GetProjectSummaryFunc
+func (m *MockHarborClient) GetProjectSummary(ctx context.Context, projectName string) (*ProjectSummary, error) {
+	if m.GetProjectSummaryFunc != nil {
+		return m.GetProjectSummaryFunc(ctx, projectName)
+	}
+	return &ProjectSummary{}, nil
+}
+
+// ListProjectAuditLogs calls ListProjectAuditLogsFunc
+func (m *MockHarborClient) ListProjectAuditLogs(ctx context.Context, projectName string, since time.Time, pageSize int64) ([]*AuditLogEntry, error) {
+	if m.ListProjectAuditLogsFunc != nil {
+		return m.ListProjectAuditLogsFunc(ctx, projectName, since, pageSize)
+	}
+	return nil, nil
+}
+
+// EnsureProjectLabel calls EnsureProjectLabelFunc
+func (m *MockHarborClient) EnsureProjectLabel(ctx context.Context, projectID int64, name, description string) (int64, error) {
+	if m.EnsureProjectLabelFunc != nil {
+		return m.EnsureProjectLabelFunc(ctx, projectID, name, description)
+	}
+	return 1, nil
+}
+
+// DeleteLabel calls DeleteLabelFunc
+func (m *MockHarborClient) DeleteLabel(ctx context.Context, labelID int64) error {
+	if m.DeleteLabelFunc != nil {
+		return m.DeleteLabelFunc(ctx, labelID)
+	}
+	return nil
+}
+
+// FindLabel calls FindLabelFunc
+func (m *MockHarborClient) FindLabel(ctx context.Context, name, projectName string) (id int64, found bool, err error) {
+	if m.FindLabelFunc != nil {
+		return m.FindLabelFunc(ctx, name, projectName)
+	}
+	return 0, false, nil
+}
+
+// ListArtifactsByLabel calls ListArtifactsByLabelFunc
+func (m *MockHarborClient) ListArtifactsByLabel(ctx context.Context, projectName, repositoryName string, labelID int64) (with, without []string, err error) {
+	if m.ListArtifactsByLabelFunc != nil {
+		return m.ListArtifactsByLabelFunc(ctx, projectName, repositoryName, labelID)
+	}
+	return nil, nil, nil
+}
+
+// GetArtifactLabels calls GetArtifactLabelsFunc
+func (m *MockHarborClient) GetArtifactLabels(ctx context.Context, projectName, repositoryName, reference string) (digest string, labelIDs []int64, err error) {
+	if m.GetArtifactLabelsFunc != nil {
+		return m.GetArtifactLabelsFunc(ctx, projectName, repositoryName, reference)
+	}
+	return "", nil, nil
+}
+
+// SetArtifactLabel calls SetArtifactLabelFunc
+func (m *MockHarborClient) SetArtifactLabel(ctx context.Context, projectName, repositoryName, reference string, labelID int64, attached bool) error {
+	if m.SetArtifactLabelFunc != nil {
+		return m.SetArtifactLabelFunc(ctx, projectName, repositoryName, reference, labelID, attached)
+	}
+	return nil
+}
+
 // CreateScannerRegistration calls CreateScannerRegistrationFunc
 func (m *MockHarborClient) CreateScannerRegistration(ctx context.Context, spec *ScannerSpec) (*ScannerStatus, error) {
 	if m.CreateScannerRegistrationFunc != nil {
@@ -388,6 +678,38 @@ func (m *MockHarborClient) ListScannerRegistrations(ctx context.Context) ([]*Sca
 	return nil, nil
 }
 
+// SetDefaultScanner calls SetDefaultScannerFunc
+func (m *MockHarborClient) SetDefaultScanner(ctx context.Context, scannerID string) error {
+	if m.SetDefaultScannerFunc != nil {
+		return m.SetDefaultScannerFunc(ctx, scannerID)
+	}
+	return nil
+}
+
+// GetProjectScanner calls GetProjectScannerFunc
+func (m *MockHarborClient) GetProjectScanner(ctx context.Context, projectName string) (*ScannerStatus, error) {
+	if m.GetProjectScannerFunc != nil {
+		return m.GetProjectScannerFunc(ctx, projectName)
+	}
+	return nil, nil
+}
+
+// SetProjectScanner calls SetProjectScannerFunc
+func (m *MockHarborClient) SetProjectScanner(ctx context.Context, projectName, scannerID string) error {
+	if m.SetProjectScannerFunc != nil {
+		return m.SetProjectScannerFunc(ctx, projectName, scannerID)
+	}
+	return nil
+}
+
+// GetScannerMetadata calls GetScannerMetadataFunc
+func (m *MockHarborClient) GetScannerMetadata(ctx context.Context, scannerID string) (*ScannerMetadata, error) {
+	if m.GetScannerMetadataFunc != nil {
+		return m.GetScannerMetadataFunc(ctx, scannerID)
+	}
+	return &ScannerMetadata{}, nil
+}
+
 // CreateRegistry calls CreateRegistryFunc
 func (m *MockHarborClient) CreateRegistry(ctx context.Context, spec *RegistrySpec) (*RegistryStatus, error) {
 	if m.CreateRegistryFunc != nil {
@@ -432,6 +754,70 @@ func (m *MockHarborClient) DeleteRegistry(ctx context.Context, registryName stri
 	return nil
 }
 
+// PingRegistry calls PingRegistryFunc
+func (m *MockHarborClient) PingRegistry(ctx context.Context, id int64) error {
+	if m.PingRegistryFunc != nil {
+		return m.PingRegistryFunc(ctx, id)
+	}
+	return nil
+}
+
+// GetGCSchedule calls GetGCScheduleFunc
+func (m *MockHarborClient) GetGCSchedule(ctx context.Context) (*GCSchedule, error) {
+	if m.GetGCScheduleFunc != nil {
+		return m.GetGCScheduleFunc(ctx)
+	}
+	return &GCSchedule{Type: GCScheduleTypeNone}, nil
+}
+
+// CreateGCSchedule calls CreateGCScheduleFunc
+func (m *MockHarborClient) CreateGCSchedule(ctx context.Context, s *GCSchedule) error {
+	if m.CreateGCScheduleFunc != nil {
+		return m.CreateGCScheduleFunc(ctx, s)
+	}
+	return nil
+}
+
+// UpdateGCSchedule calls UpdateGCScheduleFunc
+func (m *MockHarborClient) UpdateGCSchedule(ctx context.Context, s *GCSchedule) error {
+	if m.UpdateGCScheduleFunc != nil {
+		return m.UpdateGCScheduleFunc(ctx, s)
+	}
+	return nil
+}
+
+// ListImmutableTagRules calls ListImmutableTagRulesFunc
+func (m *MockHarborClient) ListImmutableTagRules(ctx context.Context, projectID string) ([]*ImmutableTagRule, error) {
+	if m.ListImmutableTagRulesFunc != nil {
+		return m.ListImmutableTagRulesFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+// CreateImmutableTagRule calls CreateImmutableTagRuleFunc
+func (m *MockHarborClient) CreateImmutableTagRule(ctx context.Context, projectID string, rule *ImmutableTagRule) (int64, error) {
+	if m.CreateImmutableTagRuleFunc != nil {
+		return m.CreateImmutableTagRuleFunc(ctx, projectID, rule)
+	}
+	return 1, nil
+}
+
+// UpdateImmutableTagRule calls UpdateImmutableTagRuleFunc
+func (m *MockHarborClient) UpdateImmutableTagRule(ctx context.Context, projectID string, rule *ImmutableTagRule) error {
+	if m.UpdateImmutableTagRuleFunc != nil {
+		return m.UpdateImmutableTagRuleFunc(ctx, projectID, rule)
+	}
+	return nil
+}
+
+// DeleteImmutableTagRule calls DeleteImmutableTagRuleFunc
+func (m *MockHarborClient) DeleteImmutableTagRule(ctx context.Context, projectID string, id int64) error {
+	if m.DeleteImmutableTagRuleFunc != nil {
+		return m.DeleteImmutableTagRuleFunc(ctx, projectID, id)
+	}
+	return nil
+}
+
 // ListRepositories calls ListRepositoriesFunc
 func (m *MockHarborClient) ListRepositories(ctx context.Context, projectID string) ([]*RepositoryStatus, error) {
 	if m.ListRepositoriesFunc != nil {
@@ -543,6 +929,38 @@ func (m *MockHarborClient) DeleteProjectMember(ctx context.Context, projectID, u
 	return nil
 }
 
+// AddProjectGroupMember calls AddProjectGroupMemberFunc
+func (m *MockHarborClient) AddProjectGroupMember(ctx context.Context, projectName, groupName string, groupType int64, role string) error {
+	if m.AddProjectGroupMemberFunc != nil {
+		return m.AddProjectGroupMemberFunc(ctx, projectName, groupName, groupType, role)
+	}
+	return nil
+}
+
+// GetProjectGroupMember calls GetProjectGroupMemberFunc
+func (m *MockHarborClient) GetProjectGroupMember(ctx context.Context, projectID, groupName string) (*MemberStatus, error) {
+	if m.GetProjectGroupMemberFunc != nil {
+		return m.GetProjectGroupMemberFunc(ctx, projectID, groupName)
+	}
+	return nil, nil
+}
+
+// UpdateProjectGroupMember calls UpdateProjectGroupMemberFunc
+func (m *MockHarborClient) UpdateProjectGroupMember(ctx context.Context, projectID, groupName, role string) error {
+	if m.UpdateProjectGroupMemberFunc != nil {
+		return m.UpdateProjectGroupMemberFunc(ctx, projectID, groupName, role)
+	}
+	return nil
+}
+
+// DeleteProjectGroupMember calls DeleteProjectGroupMemberFunc
+func (m *MockHarborClient) DeleteProjectGroupMember(ctx context.Context, projectID, groupName string) error {
+	if m.DeleteProjectGroupMemberFunc != nil {
+		return m.DeleteProjectGroupMemberFunc(ctx, projectID, groupName)
+	}
+	return nil
+}
+
 // TriggerScan calls TriggerScanFunc
 func (m *MockHarborClient) TriggerScan(ctx context.Context, projectID, repoName, reference string) error {
 	if m.TriggerScanFunc != nil {
@@ -575,6 +993,54 @@ func (m *MockHarborClient) StopScan(ctx context.Context, projectID, repoName, re
 	return nil
 }
 
+// ScanProject calls ScanProjectFunc
+func (m *MockHarborClient) ScanProject(ctx context.Context, projectName string) (int64, error) {
+	if m.ScanProjectFunc != nil {
+		return m.ScanProjectFunc(ctx, projectName)
+	}
+	return 0, nil
+}
+
+// StopProjectScans calls StopProjectScansFunc
+func (m *MockHarborClient) StopProjectScans(ctx context.Context, projectName string) (int64, error) {
+	if m.StopProjectScansFunc != nil {
+		return m.StopProjectScansFunc(ctx, projectName)
+	}
+	return 0, nil
+}
+
+// GetProjectScanMetrics calls GetProjectScanMetricsFunc
+func (m *MockHarborClient) GetProjectScanMetrics(ctx context.Context, projectName string) (*ScanMetrics, error) {
+	if m.GetProjectScanMetricsFunc != nil {
+		return m.GetProjectScanMetricsFunc(ctx, projectName)
+	}
+	return &ScanMetrics{}, nil
+}
+
+// TriggerScanAll calls TriggerScanAllFunc
+func (m *MockHarborClient) TriggerScanAll(ctx context.Context) error {
+	if m.TriggerScanAllFunc != nil {
+		return m.TriggerScanAllFunc(ctx)
+	}
+	return nil
+}
+
+// StopScanAll calls StopScanAllFunc
+func (m *MockHarborClient) StopScanAll(ctx context.Context) error {
+	if m.StopScanAllFunc != nil {
+		return m.StopScanAllFunc(ctx)
+	}
+	return nil
+}
+
+// GetScanAllMetrics calls GetScanAllMetricsFunc
+func (m *MockHarborClient) GetScanAllMetrics(ctx context.Context) (*ScanMetrics, error) {
+	if m.GetScanAllMetricsFunc != nil {
+		return m.GetScanAllMetricsFunc(ctx)
+	}
+	return &ScanMetrics{}, nil
+}
+
 // CreateRobot calls CreateRobotFunc
 func (m *MockHarborClient) CreateRobot(ctx context.Context, spec *RobotSpec) (*RobotStatus, error) {
 	if m.CreateRobotFunc != nil {
@@ -630,6 +1096,14 @@ func (m *MockHarborClient) DeleteRobot(ctx context.Context, robotID string) erro
 	return nil
 }
 
+// RefreshRobotSecret calls RefreshRobotSecretFunc
+func (m *MockHarborClient) RefreshRobotSecret(ctx context.Context, robotID string) (string, error) {
+	if m.RefreshRobotSecretFunc != nil {
+		return m.RefreshRobotSecretFunc(ctx, robotID)
+	}
+	return "new-secret", nil
+}
+
 // CreateWebhook calls CreateWebhookFunc
 func (m *MockHarborClient) CreateWebhook(ctx context.Context, spec *WebhookSpec) (*WebhookStatus, error) {
 	if m.CreateWebhookFunc != nil {
@@ -769,12 +1243,10 @@ func (m *MockHarborClient) CreateRetentionPolicy(ctx context.Context, spec *Rete
 		return m.CreateRetentionPolicyFunc(ctx, spec)
 	}
 	return &RetentionPolicyStatus{
-		ID:           "mock-retention-id",
-		ProjectID:    spec.ProjectID,
-		Description:  spec.Description,
-		Enabled:      spec.Enabled != nil && *spec.Enabled,
-		CreationTime: time.Now(),
-		UpdateTime:   time.Now(),
+		ID:        "mock-retention-id",
+		ProjectID: spec.ProjectID,
+		Rules:     spec.Rules,
+		Schedule:  spec.Schedule,
 	}, nil
 }
 
@@ -800,12 +1272,10 @@ func (m *MockHarborClient) UpdateRetentionPolicy(ctx context.Context, projectID,
 		return m.UpdateRetentionPolicyFunc(ctx, projectID, policyID, spec)
 	}
 	return &RetentionPolicyStatus{
-		ID:           policyID,
-		ProjectID:    projectID,
-		Description:  spec.Description,
-		Enabled:      spec.Enabled != nil && *spec.Enabled,
-		CreationTime: time.Now(),
-		UpdateTime:   time.Now(),
+		ID:        policyID,
+		ProjectID: projectID,
+		Rules:     spec.Rules,
+		Schedule:  spec.Schedule,
 	}, nil
 }
 
@@ -817,6 +1287,38 @@ func (m *MockHarborClient) DeleteRetentionPolicy(ctx context.Context, projectID,
 	return nil
 }
 
+// GetProjectRetentionID calls GetProjectRetentionIDFunc
+func (m *MockHarborClient) GetProjectRetentionID(ctx context.Context, projectID string) (string, error) {
+	if m.GetProjectRetentionIDFunc != nil {
+		return m.GetProjectRetentionIDFunc(ctx, projectID)
+	}
+	return "", nil
+}
+
+// SetProjectRetentionID calls SetProjectRetentionIDFunc
+func (m *MockHarborClient) SetProjectRetentionID(ctx context.Context, projectID, policyID string) error {
+	if m.SetProjectRetentionIDFunc != nil {
+		return m.SetProjectRetentionIDFunc(ctx, projectID, policyID)
+	}
+	return nil
+}
+
+// ListRetentionExecutions calls ListRetentionExecutionsFunc
+func (m *MockHarborClient) ListRetentionExecutions(ctx context.Context, policyID string) ([]*RetentionExecution, error) {
+	if m.ListRetentionExecutionsFunc != nil {
+		return m.ListRetentionExecutionsFunc(ctx, policyID)
+	}
+	return nil, nil
+}
+
+// CountRetentionTasks calls CountRetentionTasksFunc
+func (m *MockHarborClient) CountRetentionTasks(ctx context.Context, policyID, executionID string) (*RetentionTaskCounts, error) {
+	if m.CountRetentionTasksFunc != nil {
+		return m.CountRetentionTasksFunc(ctx, policyID, executionID)
+	}
+	return &RetentionTaskCounts{}, nil
+}
+
 // CreateUserGroup calls CreateUserGroupFunc
 func (m *MockHarborClient) CreateUserGroup(ctx context.Context, spec *UserGroupSpec) (*UserGroupStatus, error) {
 	if m.CreateUserGroupFunc != nil {
@@ -866,3 +1368,27 @@ func (m *MockHarborClient) DeleteUserGroup(ctx context.Context, groupID int64) e
 	}
 	return nil
 }
+
+// GetRaw calls GetRawFunc
+func (m *MockHarborClient) GetRaw(ctx context.Context, path string) ([]byte, error) {
+	if m.GetRawFunc != nil {
+		return m.GetRawFunc(ctx, path)
+	}
+	return nil, nil
+}
+
+// PutRaw calls PutRawFunc
+func (m *MockHarborClient) PutRaw(ctx context.Context, path string, body []byte) error {
+	if m.PutRawFunc != nil {
+		return m.PutRawFunc(ctx, path, body)
+	}
+	return nil
+}
+
+// DeleteRaw calls DeleteRawFunc
+func (m *MockHarborClient) DeleteRaw(ctx context.Context, path string) error {
+	if m.DeleteRawFunc != nil {
+		return m.DeleteRawFunc(ctx, path)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdkrepository "github.com/goharbor/go-client/pkg/sdk/v2.0/client/repository"
+	"github.com/pkg/errors"
+)
+
+// DefaultInventoryParallelism is how many chunks of an inventory are read
+// from Harbor at once unless SetInventoryParallelism says otherwise.
+const DefaultInventoryParallelism = 4
+
+// inventoryPageSize is how many repositories are read per request.
+const inventoryPageSize = 100
+
+// inventoryDeadlineMargin is how long before the deadline of its context an
+// inventory stops starting chunks, so that what was read can still be
+// reported before the reconcile times out.
+const inventoryDeadlineMargin = 5 * time.Second
+
+var inventoryParallelism atomic.Int64
+
+func init() {
+	inventoryParallelism.Store(DefaultInventoryParallelism)
+}
+
+// SetInventoryParallelism changes how many chunks of an inventory are read
+// at once. Values below one read one chunk at a time.
+func SetInventoryParallelism(n int) {
+	inventoryParallelism.Store(int64(max(n, 1)))
+}
+
+// Partial is what an inventory read in chunks gathered. Items holds the
+// results of every chunk that was read, in the order of the chunks.
+type Partial[R any] struct {
+	Items []R
+
+	// Chunks is the number of chunks the inventory was split into.
+	Chunks int
+	// Failed holds the error of each chunk that could not be read.
+	Failed []error
+	// Skipped counts chunks that were never started because the context
+	// was about to end.
+	Skipped int
+}
+
+// Complete reports whether every chunk was read.
+func (p *Partial[R]) Complete() bool {
+	return len(p.Failed) == 0 && p.Skipped == 0
+}
+
+// ReadChunks splits items into chunks of chunkSize and calls read for each,
+// running at most the inventory parallelism of them at once. Chunks that
+// fail are recorded rather than ending the inventory. No chunk is started
+// once Harbor is unavailable, or within inventoryDeadlineMargin of the
+// deadline of ctx; those chunks are counted as skipped.
+func ReadChunks[T, R any](ctx context.Context, items []T, chunkSize int, read func(ctx context.Context, chunk []T) ([]R, error)) *Partial[R] {
+	chunkSize = max(chunkSize, 1)
+	var chunks [][]T
+	for i := 0; i < len(items); i += chunkSize {
+		chunks = append(chunks, items[i:min(i+chunkSize, len(items))])
+	}
+
+	stopAt := time.Time{}
+	if d, ok := ctx.Deadline(); ok {
+		stopAt = d.Add(-inventoryDeadlineMargin)
+	}
+
+	var (
+		mu          sync.Mutex
+		results     = make([][]R, len(chunks))
+		failed      []error
+		skipped     int
+		unavailable bool
+		wg          sync.WaitGroup
+		slots       = make(chan struct{}, inventoryParallelism.Load())
+	)
+	for i, chunk := range chunks {
+		slots <- struct{}{}
+		mu.Lock()
+		stop := unavailable || ctx.Err() != nil || (!stopAt.IsZero() && time.Now().After(stopAt))
+		if stop {
+			skipped++
+		}
+		mu.Unlock()
+		if stop {
+			<-slots
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			r, err := read(ctx, chunk)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed = append(failed, err)
+				unavailable = unavailable || IsUnavailable(err)
+				return
+			}
+			results[i] = r
+		}()
+	}
+	wg.Wait()
+
+	p := &Partial[R]{Chunks: len(chunks), Failed: failed, Skipped: skipped}
+	for _, r := range results {
+		p.Items = append(p.Items, r...)
+	}
+	return p
+}
+
+// InventoryRepository is a repository found by an inventory.
+type InventoryRepository struct {
+	// Name is the repository name including its project.
+	Name          string
+	ArtifactCount int64
+	PullCount     int64
+	UpdateTime    time.Time
+}
+
+// repositoryPage is one page of the repositories of a project.
+type repositoryPage struct {
+	project string
+	page    int64
+}
+
+// InventoryRepositories lists every repository of the named projects. The
+// first page of each project is read first, which tells how many pages
+// remain; the remaining pages are then read in a second round, so that a
+// project with thousands of repositories is spread over the worker pool
+// too. An error is only returned when nothing at all could be read;
+// otherwise failed and skipped pages are reported in the result.
+func (c *HarborClient) InventoryRepositories(ctx context.Context, projects []string) (*Partial[InventoryRepository], error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	var (
+		mu    sync.Mutex
+		rest  []repositoryPage
+		pages = int64(inventoryPageSize)
+	)
+	read := func(ctx context.Context, chunk []repositoryPage) ([]InventoryRepository, error) {
+		var repos []InventoryRepository
+		for _, p := range chunk {
+			resp, err := v2Client.Repository.ListRepositories(ctx, &sdkrepository.ListRepositoriesParams{
+				ProjectName: p.project,
+				Page:        &p.page,
+				PageSize:    &pages,
+				Context:     ctx,
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list repositories of project %s", p.project)
+			}
+			for _, r := range resp.Payload {
+				repos = append(repos, InventoryRepository{
+					Name:          r.Name,
+					ArtifactCount: r.ArtifactCount,
+					PullCount:     r.PullCount,
+					UpdateTime:    time.Time(r.UpdateTime),
+				})
+			}
+			if p.page == 1 && resp.XTotalCount > inventoryPageSize {
+				mu.Lock()
+				for n := int64(2); (n-1)*inventoryPageSize < resp.XTotalCount; n++ {
+					rest = append(rest, repositoryPage{project: p.project, page: n})
+				}
+				mu.Unlock()
+			}
+		}
+		return repos, nil
+	}
+
+	first := make([]repositoryPage, 0, len(projects))
+	for _, p := range projects {
+		first = append(first, repositoryPage{project: p, page: 1})
+	}
+	inv := ReadChunks(ctx, first, 1, read)
+	sort.Slice(rest, func(i, j int) bool {
+		if rest[i].project != rest[j].project {
+			return rest[i].project < rest[j].project
+		}
+		return rest[i].page < rest[j].page
+	})
+	more := ReadChunks(ctx, rest, 1, read)
+
+	inv.Items = append(inv.Items, more.Items...)
+	inv.Chunks += more.Chunks
+	inv.Failed = append(inv.Failed, more.Failed...)
+	inv.Skipped += more.Skipped
+	if len(inv.Items) == 0 && len(inv.Failed) > 0 && len(inv.Failed) == inv.Chunks {
+		return nil, inv.Failed[0]
+	}
+	return inv, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadChunks(t *testing.T) {
+	SetInventoryParallelism(2)
+	t.Cleanup(func() { SetInventoryParallelism(DefaultInventoryParallelism) })
+
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	var running, most atomic.Int64
+	p := ReadChunks(context.Background(), items, 2, func(_ context.Context, chunk []int) ([]int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+		}
+		time.Sleep(10 * time.Millisecond)
+		if chunk[0] == 3 {
+			return nil, errors.New("boom")
+		}
+		return chunk, nil
+	})
+
+	if most.Load() > 2 {
+		t.Errorf("%d chunks were read at once, want at most 2", most.Load())
+	}
+	if p.Chunks != 4 || len(p.Failed) != 1 || p.Skipped != 0 || p.Complete() {
+		t.Errorf("ReadChunks() = %+v, want 4 chunks with 1 failed", p)
+	}
+	if got := fmt.Sprint(p.Items); got != "[1 2 5 6 7]" {
+		t.Errorf("items = %s, want those of the chunks read, in order", got)
+	}
+}
+
+func TestReadChunksStopsNearDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryDeadlineMargin/2)
+	defer cancel()
+
+	p := ReadChunks(ctx, []int{1, 2, 3}, 1, func(_ context.Context, chunk []int) ([]int, error) {
+		t.Error("no chunk should be started within the deadline margin")
+		return chunk, nil
+	})
+	if p.Skipped != 3 || len(p.Items) != 0 {
+		t.Errorf("ReadChunks() = %+v, want every chunk skipped", p)
+	}
+}
+
+func TestInventoryRepositories(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/", func(w http.ResponseWriter, r *http.Request) {
+		project := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v2.0/projects/"), "/")[0]
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if r.URL.Query().Get("page_size") != "100" {
+			t.Errorf("repositories requested with query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch project {
+		case "big":
+			// 250 repositories, in pages of 100.
+			w.Header().Set("X-Total-Count", "250")
+			n := min(100, 250-(page-1)*100)
+			repos := make([]string, 0, n)
+			for i := range n {
+				repos = append(repos, fmt.Sprintf(`{"name": "big/r%d", "artifact_count": 1}`, (page-1)*100+i))
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(repos, ",") + "]"))
+		case "small":
+			w.Header().Set("X-Total-Count", "1")
+			_, _ = w.Write([]byte(`[{"name": "small/web", "artifact_count": 3, "pull_count": 9}]`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	})
+	c := executionsClient(t, mux)
+
+	inv, err := c.InventoryRepositories(context.Background(), []string{"small", "big", "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.Chunks != 5 || len(inv.Failed) != 1 || inv.Skipped != 0 {
+		t.Errorf("inventory read %d chunks with %d failed and %d skipped, want 5, 1 and 0", inv.Chunks, len(inv.Failed), inv.Skipped)
+	}
+	if len(inv.Items) != 251 {
+		t.Fatalf("inventory found %d repositories, want 251", len(inv.Items))
+	}
+	if r := inv.Items[0]; r.Name != "small/web" || r.ArtifactCount != 3 || r.PullCount != 9 {
+		t.Errorf("first repository = %+v", r)
+	}
+	if r := inv.Items[250]; r.Name != "big/r249" {
+		t.Errorf("last repository = %+v, want big/r249", r)
+	}
+
+	if _, err := c.InventoryRepositories(context.Background(), []string{"secret"}); err == nil {
+		t.Error("InventoryRepositories() should fail when nothing could be read")
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+
+	sdkartifact "github.com/goharbor/go-client/pkg/sdk/v2.0/client/artifact"
+	sdklabel "github.com/goharbor/go-client/pkg/sdk/v2.0/client/label"
+	sdkproject "github.com/goharbor/go-client/pkg/sdk/v2.0/client/project"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// Harbor scopes of labels.
+const (
+	// labelScopeGlobal labels are shared by every project.
+	labelScopeGlobal = "g"
+	// labelScopeProject labels are owned by a project.
+	labelScopeProject = "p"
+)
+
+// EnsureProjectLabel returns the ID of the project label with the given name,
+// creating it if it does not exist.
+func (c *HarborClient) EnsureProjectLabel(ctx context.Context, projectID int64, name, description string) (int64, error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return 0, errors.New("failed to get Harbor v2 client")
+	}
+
+	scope := labelScopeProject
+	resp, err := v2Client.Label.ListLabels(ctx, &sdklabel.ListLabelsParams{
+		Name:      &name,
+		Scope:     &scope,
+		ProjectID: &projectID,
+		Context:   ctx,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list project labels")
+	}
+	// The name filter is a fuzzy match.
+	for _, l := range resp.Payload {
+		if l.Name == name {
+			return l.ID, nil
+		}
+	}
+
+	created, err := v2Client.Label.CreateLabel(ctx, &sdklabel.CreateLabelParams{
+		Label: &models.Label{
+			Name:        name,
+			Description: description,
+			Scope:       labelScopeProject,
+			ProjectID:   projectID,
+		},
+		Context: ctx,
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create project label %s", name)
+	}
+	id, err := strconv.ParseInt(path.Base(created.Location), 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("cannot parse label ID from location %q", created.Location)
+	}
+	return id, nil
+}
+
+// FindLabel returns the ID of the label with the given name. The label is
+// looked up among the labels of projectName, or among the global labels when
+// projectName is empty. found is false when there is no such label.
+func (c *HarborClient) FindLabel(ctx context.Context, name, projectName string) (id int64, found bool, err error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return 0, false, errors.New("failed to get Harbor v2 client")
+	}
+
+	scope := labelScopeGlobal
+	params := &sdklabel.ListLabelsParams{Name: &name, Scope: &scope, Context: ctx}
+	if projectName != "" {
+		isName := true
+		project, err := v2Client.Project.GetProject(ctx, &sdkproject.GetProjectParams{
+			ProjectNameOrID: projectName,
+			XIsResourceName: &isName,
+			Context:         ctx,
+		})
+		if err != nil {
+			return 0, false, errors.Wrapf(err, "failed to get project %s", projectName)
+		}
+		projectID := int64(project.Payload.ProjectID)
+		scope = labelScopeProject
+		params.ProjectID = &projectID
+	}
+
+	resp, err := v2Client.Label.ListLabels(ctx, params)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to list labels")
+	}
+	// The name filter is a fuzzy match.
+	for _, l := range resp.Payload {
+		if l.Name == name {
+			return l.ID, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// DeleteLabel deletes a label, detaching it from every artifact. Deleting a
+// label that does not exist is not an error.
+func (c *HarborClient) DeleteLabel(ctx context.Context, labelID int64) error {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+	_, err := v2Client.Label.DeleteLabel(ctx, &sdklabel.DeleteLabelParams{LabelID: labelID, Context: ctx})
+	if err != nil && !IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete label %d", labelID)
+	}
+	return nil
+}
+
+// ListArtifactsByLabel returns the digests of a repository's artifacts split
+// by whether they carry the given label. repositoryName excludes the project.
+func (c *HarborClient) ListArtifactsByLabel(ctx context.Context, projectName, repositoryName string, labelID int64) (with, without []string, err error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	pageSize := int64(100)
+	withLabel := true
+	for page := int64(1); ; page++ {
+		p := page
+		resp, err := v2Client.Artifact.ListArtifacts(ctx, &sdkartifact.ListArtifactsParams{
+			ProjectName:    projectName,
+			RepositoryName: encodeRepositoryName(repositoryName),
+			Page:           &p,
+			PageSize:       &pageSize,
+			WithLabel:      &withLabel,
+			Context:        ctx,
+		})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to list artifacts of repository %s", repositoryName)
+		}
+		for _, a := range resp.Payload {
+			if hasLabel(a, labelID) {
+				with = append(with, a.Digest)
+			} else {
+				without = append(without, a.Digest)
+			}
+		}
+		if int64(len(resp.Payload)) < pageSize {
+			return with, without, nil
+		}
+	}
+}
+
+// GetArtifactLabels returns the digest of an artifact and the IDs of the
+// labels attached to it. reference is a tag or digest.
+func (c *HarborClient) GetArtifactLabels(ctx context.Context, projectName, repositoryName, reference string) (digest string, labelIDs []int64, err error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return "", nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	withLabel := true
+	resp, err := v2Client.Artifact.GetArtifact(ctx, &sdkartifact.GetArtifactParams{
+		ProjectName:    projectName,
+		RepositoryName: encodeRepositoryName(repositoryName),
+		Reference:      reference,
+		WithLabel:      &withLabel,
+		Context:        ctx,
+	})
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to get artifact %s/%s@%s", projectName, repositoryName, reference)
+	}
+	for _, l := range resp.Payload.Labels {
+		if l != nil {
+			labelIDs = append(labelIDs, l.ID)
+		}
+	}
+	return resp.Payload.Digest, labelIDs, nil
+}
+
+// SetArtifactLabel attaches the label to, or detaches it from, an artifact.
+func (c *HarborClient) SetArtifactLabel(ctx context.Context, projectName, repositoryName, reference string, labelID int64, attached bool) error {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	repo := encodeRepositoryName(repositoryName)
+	if attached {
+		_, err := v2Client.Artifact.AddLabel(ctx, &sdkartifact.AddLabelParams{
+			ProjectName:    projectName,
+			RepositoryName: repo,
+			Reference:      reference,
+			Label:          &models.Label{ID: labelID},
+			Context:        ctx,
+		})
+		// Harbor answers 409 when the label is already attached.
+		if err != nil && !IsConflict(err) {
+			return errors.Wrapf(err, "failed to label artifact %s/%s@%s", projectName, repositoryName, reference)
+		}
+		return nil
+	}
+
+	_, err := v2Client.Artifact.RemoveLabel(ctx, &sdkartifact.RemoveLabelParams{
+		ProjectName:    projectName,
+		RepositoryName: repo,
+		Reference:      reference,
+		LabelID:        labelID,
+		Context:        ctx,
+	})
+	if err != nil && !IsNotFound(err) {
+		return errors.Wrapf(err, "failed to unlabel artifact %s/%s@%s", projectName, repositoryName, reference)
+	}
+	return nil
+}
+
+// encodeRepositoryName prepares a repository name for a path parameter.
+// Nested names must be URL encoded twice and the SDK encodes them once.
+func encodeRepositoryName(name string) string {
+	return strings.ReplaceAll(name, "/", "%2F")
+}
+
+func hasLabel(a *models.Artifact, labelID int64) bool {
+	for _, l := range a.Labels {
+		if l != nil && l.ID == labelID {
+			return true
+		}
+	}
+	return false
+}
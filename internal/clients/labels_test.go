@@ -0,0 +1,92 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestEnsureProjectLabel(t *testing.T) {
+	var created map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/labels", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			w.Header().Set("Location", "/api/v2.0/labels/12")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		if r.URL.Query().Get("scope") != "p" || r.URL.Query().Get("project_id") != "3" {
+			t.Errorf("labels listed with query %v", r.URL.Query())
+		}
+		// The name filter is fuzzy, so a similarly named label is returned.
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 4, "name": "severity-gate-exempt-old"}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := c.EnsureProjectLabel(context.Background(), 3, "severity-gate-exempt", "exempt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 12 {
+		t.Errorf("EnsureProjectLabel() = %d, want 12", id)
+	}
+	if created["name"] != "severity-gate-exempt" || created["scope"] != "p" || created["project_id"] != float64(3) {
+		t.Errorf("created label %v", created)
+	}
+}
+
+func TestListArtifactsByLabel(t *testing.T) {
+	var pages []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/library/repositories/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v2.0/projects/library/repositories/team%252Fapp/artifacts" {
+			t.Errorf("artifacts listed at %s", r.URL.EscapedPath())
+		}
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		w.Header().Set("Content-Type", "application/json")
+		var arts []map[string]interface{}
+		if page == "1" {
+			for i := 0; i < 100; i++ {
+				arts = append(arts, map[string]interface{}{"digest": "sha256:full"})
+			}
+		} else {
+			arts = []map[string]interface{}{
+				{"digest": "sha256:a", "labels": []map[string]interface{}{{"id": 9}}},
+				{"digest": "sha256:b", "labels": []map[string]interface{}{{"id": 2}}},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(arts)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	with, without, err := c.ListArtifactsByLabel(context.Background(), "library", "team/app", 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pages, []string{"1", "2"}) {
+		t.Errorf("pages requested = %v, want [1 2]", pages)
+	}
+	if !reflect.DeepEqual(with, []string{"sha256:a"}) || len(without) != 101 {
+		t.Errorf("ListArtifactsByLabel() = %v, %d without", with, len(without))
+	}
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import "strings"
+
+// ManagedByMarker is appended to the description of Harbor objects the
+// provider creates, so that objects left behind by deleted managed resources
+// can be told apart from ones created by hand. It is stripped again when
+// objects are read, so controllers never see it.
+const ManagedByMarker = "[managed-by: provider-harbor]"
+
+func withManagedByMarker(desc string) string {
+	desc, _ = stripManagedByMarker(desc)
+	if desc == "" {
+		return ManagedByMarker
+	}
+	return desc + " " + ManagedByMarker
+}
+
+func stripManagedByMarker(desc string) (string, bool) {
+	if !strings.HasSuffix(desc, ManagedByMarker) {
+		return desc, false
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(desc, ManagedByMarker), " "), true
+}
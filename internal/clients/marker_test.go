@@ -0,0 +1,23 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import "testing"
+
+func TestManagedByMarker(t *testing.T) {
+	for _, desc := range []string{"", "CI pipeline robot"} {
+		marked := withManagedByMarker(desc)
+		if withManagedByMarker(marked) != marked {
+			t.Errorf("withManagedByMarker(%q) is not idempotent", desc)
+		}
+		got, ok := stripManagedByMarker(marked)
+		if !ok || got != desc {
+			t.Errorf("stripManagedByMarker(%q) = %q, %v, want %q, true", marked, got, ok, desc)
+		}
+	}
+	if _, ok := stripManagedByMarker("created by hand"); ok {
+		t.Error("stripManagedByMarker() reported an unmarked description as managed")
+	}
+}
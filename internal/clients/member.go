@@ -0,0 +1,290 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	sdkmember "github.com/goharbor/go-client/pkg/sdk/v2.0/client/member"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// Harbor's entity types of project members.
+const (
+	memberEntityUser  = "u"
+	memberEntityGroup = "g"
+)
+
+// memberPageSize is how many project members are read per request.
+const memberPageSize = 100
+
+// MemberStatus represents a Harbor project member
+type MemberStatus struct {
+	ID         string
+	MemberName string
+	// MemberType is u for a user and g for a user group
+	MemberType   string
+	Role         string
+	CreationTime time.Time
+}
+
+// projectIsName reports whether projectNameOrID is a project name rather
+// than an ID, for the X-Is-Resource-Name header. Harbor otherwise reads a
+// project name made only of digits as an ID.
+func projectIsName(projectNameOrID string) *bool {
+	_, err := strconv.ParseInt(projectNameOrID, 10, 64)
+	isName := err != nil
+	return &isName
+}
+
+// projectRoleName returns the name of the project role with the given ID,
+// or Harbor's name for it when the role is not one the provider knows.
+func projectRoleName(id int64, harborName string) string {
+	for name, rid := range projectRoleIDs {
+		if rid == id {
+			return name
+		}
+	}
+	return harborName
+}
+
+func memberStatus(e *sdkmodels.ProjectMemberEntity) *MemberStatus {
+	return &MemberStatus{
+		ID:         strconv.FormatInt(e.ID, 10),
+		MemberName: e.EntityName,
+		MemberType: e.EntityType,
+		Role:       projectRoleName(e.RoleID, e.RoleName),
+	}
+}
+
+// findProjectMember returns the member of a project with the given entity
+// type and name, or a 404 error when there is none. Harbor's entity name
+// filter matches substrings, so the name is compared exactly.
+func (c *HarborClient) findProjectMember(ctx context.Context, projectNameOrID, entityType, name string) (*sdkmodels.ProjectMemberEntity, error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	pageSize := int64(memberPageSize)
+	for page := int64(1); ; page++ {
+		resp, err := v2Client.Member.ListProjectMembers(ctx, &sdkmember.ListProjectMembersParams{
+			ProjectNameOrID: projectNameOrID,
+			XIsResourceName: projectIsName(projectNameOrID),
+			Entityname:      &name,
+			Page:            &page,
+			PageSize:        &pageSize,
+			Context:         ctx,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list members of project %s", projectNameOrID)
+		}
+		for _, e := range resp.Payload {
+			if e.EntityType == entityType && e.EntityName == name {
+				return e, nil
+			}
+		}
+		if len(resp.Payload) < memberPageSize {
+			return nil, sdkmember.NewGetProjectMemberNotFound()
+		}
+	}
+}
+
+// setProjectMemberRole changes the role of the project member with the given
+// entity type and name in place.
+func (c *HarborClient) setProjectMemberRole(ctx context.Context, projectNameOrID, entityType, name, role string) error {
+	roleID, ok := projectRoleIDs[role]
+	if !ok {
+		return errors.Errorf("unknown project role %q", role)
+	}
+	m, err := c.findProjectMember(ctx, projectNameOrID, entityType, name)
+	if err != nil {
+		return err
+	}
+	_, err = c.clientSet.V2().Member.UpdateProjectMember(ctx, &sdkmember.UpdateProjectMemberParams{
+		ProjectNameOrID: projectNameOrID,
+		XIsResourceName: projectIsName(projectNameOrID),
+		Mid:             m.ID,
+		Role:            &sdkmodels.RoleRequest{RoleID: roleID},
+		Context:         ctx,
+	})
+	return errors.Wrapf(err, "failed to change role of %s in project %s", name, projectNameOrID)
+}
+
+// removeProjectMember removes the project member with the given entity type
+// and name. Removing a member that does not exist succeeds.
+func (c *HarborClient) removeProjectMember(ctx context.Context, projectNameOrID, entityType, name string) error {
+	m, err := c.findProjectMember(ctx, projectNameOrID, entityType, name)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = c.clientSet.V2().Member.DeleteProjectMember(ctx, &sdkmember.DeleteProjectMemberParams{
+		ProjectNameOrID: projectNameOrID,
+		XIsResourceName: projectIsName(projectNameOrID),
+		Mid:             m.ID,
+		Context:         ctx,
+	})
+	if IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "failed to remove %s from project %s", name, projectNameOrID)
+}
+
+// AddProjectMember adds a member to a Harbor project
+func (c *HarborClient) AddProjectMember(ctx context.Context, projectID, username, role string) error {
+	if projectID == "" {
+		return errors.New("project ID is required")
+	}
+	if username == "" {
+		return errors.New("username is required")
+	}
+	roleID, ok := projectRoleIDs[role]
+	if !ok {
+		return errors.Errorf("unknown project role %q", role)
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Adding Harbor project member", "projectId", projectID, "username", username, "role", role)
+
+	_, err := v2Client.Member.CreateProjectMember(ctx, &sdkmember.CreateProjectMemberParams{
+		ProjectNameOrID: projectID,
+		XIsResourceName: projectIsName(projectID),
+		ProjectMember: &sdkmodels.ProjectMember{
+			RoleID:     roleID,
+			MemberUser: &sdkmodels.UserEntity{Username: username},
+		},
+		Context: ctx,
+	})
+	return errors.Wrapf(err, "failed to add user %s to project %s", username, projectID)
+}
+
+// ListProjectMembers lists members of a Harbor project
+func (c *HarborClient) ListProjectMembers(ctx context.Context, projectID string) ([]*MemberStatus, error) {
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	var members []*MemberStatus
+	pageSize := int64(memberPageSize)
+	for page := int64(1); ; page++ {
+		resp, err := v2Client.Member.ListProjectMembers(ctx, &sdkmember.ListProjectMembersParams{
+			ProjectNameOrID: projectID,
+			XIsResourceName: projectIsName(projectID),
+			Page:            &page,
+			PageSize:        &pageSize,
+			Context:         ctx,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list members of project %s", projectID)
+		}
+		for _, e := range resp.Payload {
+			members = append(members, memberStatus(e))
+		}
+		if len(resp.Payload) < memberPageSize {
+			return members, nil
+		}
+	}
+}
+
+// GetProjectMember retrieves a user member of a project
+func (c *HarborClient) GetProjectMember(ctx context.Context, projectID, username string) (*MemberStatus, error) {
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	m, err := c.findProjectMember(ctx, projectID, memberEntityUser, username)
+	if err != nil {
+		return nil, err
+	}
+	return memberStatus(m), nil
+}
+
+// UpdateProjectMember updates a user member's role
+func (c *HarborClient) UpdateProjectMember(ctx context.Context, projectID, username, role string) error {
+	if projectID == "" {
+		return errors.New("project ID is required")
+	}
+	if username == "" {
+		return errors.New("username is required")
+	}
+
+	c.logger.Info("Updating Harbor project member", "projectId", projectID, "username", username, "role", role)
+
+	return c.setProjectMemberRole(ctx, projectID, memberEntityUser, username, role)
+}
+
+// DeleteProjectMember removes a user member from a project
+func (c *HarborClient) DeleteProjectMember(ctx context.Context, projectID, username string) error {
+	if projectID == "" {
+		return errors.New("project ID is required")
+	}
+	if username == "" {
+		return errors.New("username is required")
+	}
+
+	c.logger.Info("Deleting Harbor project member", "projectId", projectID, "username", username)
+
+	return c.removeProjectMember(ctx, projectID, memberEntityUser, username)
+}
+
+// GetProjectGroupMember retrieves a user group member of a project
+func (c *HarborClient) GetProjectGroupMember(ctx context.Context, projectID, groupName string) (*MemberStatus, error) {
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	if groupName == "" {
+		return nil, errors.New("group name is required")
+	}
+	m, err := c.findProjectMember(ctx, projectID, memberEntityGroup, groupName)
+	if err != nil {
+		return nil, err
+	}
+	return memberStatus(m), nil
+}
+
+// UpdateProjectGroupMember updates a user group member's role
+func (c *HarborClient) UpdateProjectGroupMember(ctx context.Context, projectID, groupName, role string) error {
+	if projectID == "" {
+		return errors.New("project ID is required")
+	}
+	if groupName == "" {
+		return errors.New("group name is required")
+	}
+
+	c.logger.Info("Updating Harbor project group member", "projectId", projectID, "group", groupName, "role", role)
+
+	return c.setProjectMemberRole(ctx, projectID, memberEntityGroup, groupName, role)
+}
+
+// DeleteProjectGroupMember removes a user group member from a project
+func (c *HarborClient) DeleteProjectGroupMember(ctx context.Context, projectID, groupName string) error {
+	if projectID == "" {
+		return errors.New("project ID is required")
+	}
+	if groupName == "" {
+		return errors.New("group name is required")
+	}
+
+	c.logger.Info("Deleting Harbor project group member", "projectId", projectID, "group", groupName)
+
+	return c.removeProjectMember(ctx, projectID, memberEntityGroup, groupName)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestProjectMembers(t *testing.T) {
+	var updated, deleted []string
+	members := `[
+		{"id": 3, "entity_name": "platform-admins-ro", "entity_type": "g", "role_id": 3},
+		{"id": 4, "entity_name": "platform-admins", "entity_type": "u", "role_id": 2},
+		{"id": 5, "entity_name": "platform-admins", "entity_type": "g", "role_id": 4, "role_name": "master"}
+	]`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/42/members", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Is-Resource-Name") != "false" {
+			t.Errorf("project ID 42 sent with X-Is-Resource-Name %q", r.Header.Get("X-Is-Resource-Name"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("entityname") == "nobody" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(members))
+	})
+	mux.HandleFunc("/api/v2.0/projects/42/members/5", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			var role struct {
+				RoleID int64 `json:"role_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&role); err != nil {
+				t.Error(err)
+			}
+			updated = append(updated, projectRoleName(role.RoleID, ""))
+		case http.MethodDelete:
+			deleted = append(deleted, "5")
+		}
+	})
+	c := executionsClient(t, mux)
+	ctx := context.Background()
+
+	m, err := c.GetProjectGroupMember(ctx, "42", "platform-admins")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ID != "5" || m.MemberType != "g" || m.Role != "maintainer" {
+		t.Errorf("GetProjectGroupMember() = %+v, want group member 5 with role maintainer", m)
+	}
+	if _, err := c.GetProjectGroupMember(ctx, "42", "nobody"); !IsNotFound(err) {
+		t.Errorf("GetProjectGroupMember() of a non-member error = %v, want not found", err)
+	}
+
+	if err := c.UpdateProjectGroupMember(ctx, "42", "platform-admins", "developer"); err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || updated[0] != "developer" {
+		t.Errorf("roles set = %v, want [developer]", updated)
+	}
+	if err := c.UpdateProjectGroupMember(ctx, "42", "platform-admins", "owner"); err == nil {
+		t.Error("setting an unknown role should fail")
+	}
+
+	if err := c.DeleteProjectGroupMember(ctx, "42", "platform-admins"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteProjectGroupMember(ctx, "42", "nobody"); err != nil {
+		t.Errorf("removing a non-member returned %v", err)
+	}
+	if len(deleted) != 1 {
+		t.Errorf("members deleted = %v, want only member 5", deleted)
+	}
+}
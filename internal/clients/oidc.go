@@ -0,0 +1,33 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+
+	sdkoidc "github.com/goharbor/go-client/pkg/sdk/v2.0/client/oidc"
+	"github.com/pkg/errors"
+)
+
+// PingOIDC asks Harbor to reach the OIDC provider at endpoint, as it would
+// after auth_mode is switched to oidc_auth. An error means Harbor could not
+// discover the provider, and OIDC logins would fail with these settings.
+// Only a system admin may ping.
+func (c *HarborClient) PingOIDC(ctx context.Context, endpoint string, verifyCert bool) error {
+	if endpoint == "" {
+		return errors.New("OIDC endpoint is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	_, err := v2Client.OIDC.PingOIDC(ctx, &sdkoidc.PingOIDCParams{
+		Endpoint: sdkoidc.PingOIDCBody{URL: endpoint, VerifyCert: verifyCert},
+		Context:  ctx,
+	})
+	return errors.Wrapf(err, "Harbor cannot reach OIDC endpoint %s", endpoint)
+}
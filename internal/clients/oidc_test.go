@@ -0,0 +1,42 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPingOIDC(t *testing.T) {
+	var body map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/system/oidc/ping", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["url"] != "https://id.example.com/realms/harbor" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PingOIDC(context.Background(), "https://id.example.com/realms/harbor", true); err != nil {
+		t.Fatalf("PingOIDC() error = %v", err)
+	}
+	if body["verify_cert"] != true {
+		t.Errorf("PingOIDC() sent %v, want verify_cert=true", body)
+	}
+	if err := c.PingOIDC(context.Background(), "https://id.example.com/wrong", true); err == nil {
+		t.Error("PingOIDC() should fail when Harbor cannot reach the endpoint")
+	}
+}
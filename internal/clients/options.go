@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+)
+
+// Options configures how NewHarborClient builds a client, beyond the
+// credentials in its HarborConfig.
+type Options struct {
+	// Logger receives the client's log messages. They are discarded when
+	// it is nil.
+	Logger logging.Logger
+
+	// UserAgent, when set, is sent as the User-Agent of every request.
+	UserAgent string
+
+	// TransportWrappers wrap the transport of the client's Harbor API
+	// runtime, in order, so the last one sees each request first.
+	TransportWrappers []func(http.RoundTripper) http.RoundTripper
+}
+
+// An Option configures a Harbor client.
+type Option func(*Options)
+
+// WithLogger makes the client log to l.
+func WithLogger(l logging.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// WithTransportWrapper makes the client send its requests through the
+// RoundTripper wrap returns.
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(o *Options) {
+		o.TransportWrappers = append(o.TransportWrappers, wrap)
+	}
+}
+
+// WithUserAgent makes the client send ua as the User-Agent of its requests.
+func WithUserAgent(ua string) Option {
+	return func(o *Options) {
+		o.UserAgent = ua
+	}
+}
+
+// userAgentTransport sets the User-Agent header of the requests it sends.
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
+
+// bearerTransport authenticates the requests it sends with a bearer token.
+type bearerTransport struct {
+	next  http.RoundTripper
+	token string
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.next.RoundTrip(req)
+}
+
+// defaultOptions are applied to every Harbor client before the options
+// passed to NewHarborClient.
+var defaultOptions = struct {
+	mu   sync.Mutex
+	opts []Option
+}{}
+
+// SetDefaultOptions makes every Harbor client created afterwards use opts,
+// including those the controllers create from a ProviderConfig. Options
+// passed to NewHarborClient are applied after them.
+func SetDefaultOptions(opts ...Option) {
+	defaultOptions.mu.Lock()
+	defaultOptions.opts = opts
+	defaultOptions.mu.Unlock()
+	sharedClientCache.reset()
+}
+
+// buildOptions returns the default options with opts applied over them.
+func buildOptions(opts []Option) *Options {
+	defaultOptions.mu.Lock()
+	all := append(append([]Option{}, defaultOptions.opts...), opts...)
+	defaultOptions.mu.Unlock()
+
+	o := &Options{}
+	for _, opt := range all {
+		opt(o)
+	}
+	if o.Logger == nil {
+		o.Logger = logging.NewNopLogger()
+	}
+	return o
+}
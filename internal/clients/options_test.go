@@ -0,0 +1,69 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// headerTransport records a header of the requests it sends.
+type headerTransport struct {
+	next   http.RoundTripper
+	header string
+	seen   *[]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	*t.seen = append(*t.seen, req.Header.Get(t.header))
+	return t.next.RoundTrip(req)
+}
+
+func TestNewHarborClientOptions(t *testing.T) {
+	var agents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.UserAgent())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var seen []string
+	record := func(next http.RoundTripper) http.RoundTripper {
+		return &headerTransport{next: next, header: "User-Agent", seen: &seen}
+	}
+
+	SetDefaultOptions(WithUserAgent("provider-harbor/default"))
+	t.Cleanup(func() { SetDefaultOptions() })
+
+	cfg := &HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"}
+
+	c, err := NewHarborClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRaw(context.Background(), "/system/scanAll/schedule"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Transport wrappers see requests before their user agent is set.
+	c, err = NewHarborClient(cfg, WithUserAgent("provider-harbor/test"), WithTransportWrapper(record))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRaw(context.Background(), "/system/scanAll/schedule"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"provider-harbor/default", "provider-harbor/test"}; !reflect.DeepEqual(agents, want) {
+		t.Errorf("Harbor saw user agents %v, want %v", agents, want)
+	}
+	if want := []string{""}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("the transport wrapper saw user agents %v, want %v", seen, want)
+	}
+}
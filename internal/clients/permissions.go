@@ -0,0 +1,148 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	sdkpermissions "github.com/goharbor/go-client/pkg/sdk/v2.0/client/permissions"
+	sdkuser "github.com/goharbor/go-client/pkg/sdk/v2.0/client/user"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// robotAccessResource is the resource of every access CreateRobot requests;
+// a RobotPermission's Access lists only actions.
+const robotAccessResource = "repository"
+
+// ProjectSelfResource is the resource GetProjectPermissions reports for the
+// project itself, such as in project:update.
+const ProjectSelfResource = "project"
+
+// A RobotAccess is a resource and an action on it, such as repository and
+// push.
+type RobotAccess struct {
+	Resource string
+	Action   string
+}
+
+// String returns the access as resource:action.
+func (a RobotAccess) String() string {
+	return a.Resource + ":" + a.Action
+}
+
+// RobotPermissions are the accesses Harbor lets robot accounts have, at the
+// system and the project level. Harbor 2.8 and later let administrators
+// restrict them.
+type RobotPermissions struct {
+	System  []RobotAccess
+	Project []RobotAccess
+}
+
+// AllowsProject reports whether project-level robot accounts may have a.
+func (p *RobotPermissions) AllowsProject(a RobotAccess) bool {
+	for _, allowed := range p.Project {
+		if allowed == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Disallowed returns the accesses CreateRobot would request for perms that
+// project-level robot accounts may not have, as sorted resource:action pairs.
+func (p *RobotPermissions) Disallowed(perms []RobotPermission) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, perm := range perms {
+		for _, action := range perm.Access {
+			a := RobotAccess{Resource: robotAccessResource, Action: action}
+			if p.AllowsProject(a) || seen[a.String()] {
+				continue
+			}
+			seen[a.String()] = true
+			out = append(out, a.String())
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// GetRobotPermissions returns the accesses Harbor lets robot accounts have,
+// served from cache while fresh. Harbor releases before 2.8 answer 404.
+func (c *HarborClient) GetRobotPermissions(ctx context.Context) (*RobotPermissions, error) {
+	key := c.systemCacheKey()
+	if p, ok := c.systemCache.getPermissions(key); ok {
+		return p, nil
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	resp, err := v2Client.Permissions.GetPermissions(ctx, &sdkpermissions.GetPermissionsParams{Context: ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get robot permissions")
+	}
+	p := &RobotPermissions{}
+	if resp.Payload != nil {
+		p.System = robotAccesses(resp.Payload.System)
+		p.Project = robotAccesses(resp.Payload.Project)
+	}
+	c.systemCache.putPermissions(key, p)
+	return p, nil
+}
+
+func robotAccesses(perms []*sdkmodels.Permission) []RobotAccess {
+	out := make([]RobotAccess, 0, len(perms))
+	for _, p := range perms {
+		if p != nil {
+			out = append(out, RobotAccess{Resource: p.Resource, Action: p.Action})
+		}
+	}
+	return out
+}
+
+// GetProjectPermissions returns what the client's account may do in the
+// project with the given ID. Resources are relative to the project, such as
+// tag-retention:create, with ProjectSelfResource for the project itself.
+func (c *HarborClient) GetProjectPermissions(ctx context.Context, projectID string) ([]RobotAccess, error) {
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	scope := "/project/" + projectID
+	relative := false
+	resp, err := v2Client.User.GetCurrentUserPermissions(ctx, &sdkuser.GetCurrentUserPermissionsParams{
+		Scope:    &scope,
+		Relative: &relative,
+		Context:  ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get permissions in project %s", projectID)
+	}
+
+	// Harbor's relative resource names vary between releases, so ask for
+	// absolute ones and strip the scope here.
+	out := make([]RobotAccess, 0, len(resp.Payload))
+	for _, p := range resp.Payload {
+		if p == nil {
+			continue
+		}
+		resource := ProjectSelfResource
+		if p.Resource != scope {
+			resource = strings.TrimPrefix(p.Resource, scope+"/")
+		}
+		out = append(out, RobotAccess{Resource: resource, Action: p.Action})
+	}
+	return out, nil
+}
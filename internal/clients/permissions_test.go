@@ -0,0 +1,61 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetRobotPermissions(t *testing.T) {
+	c := replayClient(t, "robot_permissions.jsonl")
+	t.Cleanup(c.InvalidateSystemCache)
+
+	// The second call is served from cache; the cassette holds one request.
+	for i := 0; i < 2; i++ {
+		p, err := c.GetRobotPermissions(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := p.Disallowed([]RobotPermission{
+			{Namespace: "library", Access: []string{"pull", "push"}},
+			{Namespace: "team-a", Access: []string{"delete", "push"}},
+		})
+		want := []string{"repository:delete", "repository:push"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Disallowed() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGetProjectPermissions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/users/current/permissions", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("scope") != "/project/5" || q.Get("relative") != "false" {
+			t.Errorf("permissions listed with query %v", q)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"resource": "/project/5", "action": "update"},
+			{"resource": "/project/5/tag-retention", "action": "create"}
+		]`))
+	})
+	c := executionsClient(t, mux)
+
+	got, err := c.GetProjectPermissions(context.Background(), "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RobotAccess{
+		{Resource: ProjectSelfResource, Action: "update"},
+		{Resource: "tag-retention", Action: "create"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetProjectPermissions() = %v, want %v", got, want)
+	}
+}
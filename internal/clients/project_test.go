@@ -0,0 +1,147 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const projectJSON = `{"project_id": 42, "name": "team-a", "owner_id": 3, "owner_name": "alice",
+	"creation_time": "2024-05-01T10:00:00Z", "update_time": "2024-05-02T10:00:00Z",
+	"metadata": {"public": "true"}}`
+
+func TestCreateProject(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("projects requested with method %s", r.Method)
+		}
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		md, _ := req["metadata"].(map[string]any)
+		if req["project_name"] != "team-a" || req["public"] != true || req["storage_limit"] != float64(1024) ||
+			md["public"] != "true" || md["auto_scan"] != "true" || md["severity"] != "high" {
+			t.Errorf("project created with %v", req)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/api/v2.0/projects/team-a", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Is-Resource-Name") != "true" {
+			t.Error("project should be looked up by name")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(projectJSON))
+	})
+	c := executionsClient(t, mux)
+
+	scan, limit, severity := true, int64(1024), "high"
+	got, err := c.CreateProject(context.Background(), &ProjectSpec{
+		Name:           "team-a",
+		Public:         true,
+		AutoScanImages: &scan,
+		Severity:       &severity,
+		StorageLimit:   &limit,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "42" || got.Name != "team-a" || !got.Public || got.OwnerID != 3 || got.OwnerName != "alice" || got.CreatedAt.IsZero() {
+		t.Errorf("CreateProject() = %+v", got)
+	}
+}
+
+func TestCreateProjectConflict(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+	c := executionsClient(t, mux)
+
+	_, err := c.CreateProject(context.Background(), &ProjectSpec{Name: "team-a"})
+	if !IsConflict(err) || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("CreateProject() error = %v, want a conflict", err)
+	}
+}
+
+func TestGetProjectNotFound(t *testing.T) {
+	c := executionsClient(t, http.NewServeMux())
+
+	if _, err := c.GetProject(context.Background(), "missing"); !IsNotFound(err) {
+		t.Errorf("GetProject() error = %v, want not found", err)
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/team-a", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			var req map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			allowlist, _ := req["cve_allowlist"].(map[string]any)
+			if _, ok := req["project_name"]; ok || req["public"] != false || len(allowlist["items"].([]any)) != 1 {
+				t.Errorf("project updated with %v", req)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(projectJSON))
+	})
+	c := executionsClient(t, mux)
+
+	got, err := c.UpdateProject(context.Background(), "team-a", &ProjectSpec{Name: "team-a", CVEAllowlist: []string{"CVE-2024-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "42" {
+		t.Errorf("UpdateProject() = %+v", got)
+	}
+}
+
+func TestDeleteProjectNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/missing", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors": [{"code": "NOT_FOUND", "message": "project missing not found"}]}`))
+	})
+	c := executionsClient(t, mux)
+
+	if err := c.DeleteProject(context.Background(), "missing"); err != nil {
+		t.Errorf("DeleteProject() of a missing project = %v, want success", err)
+	}
+}
+
+func TestListProjects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		n := 100
+		if page == 2 {
+			n = 30
+		}
+		projects := make([]string, 0, n)
+		for i := range n {
+			projects = append(projects, fmt.Sprintf(`{"project_id": %d, "name": "p%d"}`, (page-1)*100+i, (page-1)*100+i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", "130")
+		_, _ = w.Write([]byte("[" + strings.Join(projects, ",") + "]"))
+	})
+	c := executionsClient(t, mux)
+
+	got, err := c.ListProjects(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 130 || got[129].Name != "p129" || got[129].ID != "129" {
+		t.Errorf("ListProjects() returned %d projects", len(got))
+	}
+}
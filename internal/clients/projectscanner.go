@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+
+	sdkproject "github.com/goharbor/go-client/pkg/sdk/v2.0/client/project"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// GetProjectScanner returns the scanner that scans a project's artifacts.
+// This is the system default scanner unless the project overrides it.
+func (c *HarborClient) GetProjectScanner(ctx context.Context, projectName string) (*ScannerStatus, error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	isName := true
+	resp, err := v2Client.Project.GetScannerOfProject(ctx, &sdkproject.GetScannerOfProjectParams{
+		ProjectNameOrID: projectName,
+		XIsResourceName: &isName,
+		Context:         ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get scanner of project %s", projectName)
+	}
+
+	s := resp.Payload
+	status := &ScannerStatus{
+		UUID:      s.UUID,
+		Name:      s.Name,
+		URL:       s.URL.String(),
+		IsDefault: s.IsDefault != nil && *s.IsDefault,
+	}
+	if s.Description != "" {
+		status.Description = &s.Description
+	}
+	return status, nil
+}
+
+// SetProjectScanner makes a project's artifacts scan with the scanner
+// registration with the given UUID.
+func (c *HarborClient) SetProjectScanner(ctx context.Context, projectName, scannerID string) error {
+	if scannerID == "" {
+		return errors.New("scanner ID is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	isName := true
+	_, err := v2Client.Project.SetScannerOfProject(ctx, &sdkproject.SetScannerOfProjectParams{
+		ProjectNameOrID: projectName,
+		XIsResourceName: &isName,
+		Payload:         &sdkmodels.ProjectScanner{UUID: &scannerID},
+		Context:         ctx,
+	})
+	return errors.Wrapf(err, "failed to set scanner of project %s", projectName)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/pkg/errors"
+)
+
+// ProjectSummary counts what a Harbor project holds and who can access it.
+type ProjectSummary struct {
+	RepoCount int64
+	// ChartCount is nil unless Harbor runs ChartMuseum, which Harbor v2.8
+	// removed.
+	ChartCount *int64
+
+	// QuotaHard and QuotaUsed are the project's quota limits and usage by
+	// resource, such as storage in bytes. A limit of -1 is unlimited. Both
+	// are nil when quotas are not visible to the account.
+	QuotaHard map[string]int64
+	QuotaUsed map[string]int64
+
+	// Numbers of members with each project role.
+	ProjectAdminCount int64
+	MaintainerCount   int64
+	DeveloperCount    int64
+	GuestCount        int64
+	LimitedGuestCount int64
+}
+
+// projectSummary is a project summary as Harbor returns it. The SDK's model
+// has no chart_count, which Harbor only returns while ChartMuseum is
+// installed, so the response is decoded here.
+type projectSummary struct {
+	RepoCount         int64  `json:"repo_count"`
+	ChartCount        *int64 `json:"chart_count,omitempty"`
+	ProjectAdminCount int64  `json:"project_admin_count"`
+	MaintainerCount   int64  `json:"maintainer_count"`
+	DeveloperCount    int64  `json:"developer_count"`
+	GuestCount        int64  `json:"guest_count"`
+	LimitedGuestCount int64  `json:"limited_guest_count"`
+	Quota             *struct {
+		Hard map[string]int64 `json:"hard"`
+		Used map[string]int64 `json:"used"`
+	} `json:"quota,omitempty"`
+}
+
+// GetProjectSummary returns the summary of a project.
+func (c *HarborClient) GetProjectSummary(ctx context.Context, projectName string) (*ProjectSummary, error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	result, err := v2Client.Transport.Submit(&runtime.ClientOperation{
+		ID:                 "getProjectSummary",
+		Method:             http.MethodGet,
+		PathPattern:        "/projects/{project_name_or_id}/summary",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http", "https"},
+		Params: runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			if err := r.SetHeaderParam("X-Is-Resource-Name", "true"); err != nil {
+				return err
+			}
+			return r.SetPathParam("project_name_or_id", projectName)
+		}),
+		Reader: runtime.ClientResponseReaderFunc(func(r runtime.ClientResponse, _ runtime.Consumer) (interface{}, error) {
+			if r.Code() != http.StatusOK {
+				return nil, runtime.NewAPIError("getProjectSummary", r.Message(), r.Code())
+			}
+			s := &projectSummary{}
+			if err := json.NewDecoder(r.Body()).Decode(s); err != nil {
+				return nil, errors.Wrap(err, "cannot decode project summary")
+			}
+			return s, nil
+		}),
+		AuthInfo: httptransport.BasicAuth(c.config.Username, c.config.Password),
+		Context:  ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get summary of project %s", projectName)
+	}
+	s := result.(*projectSummary)
+	summary := &ProjectSummary{
+		RepoCount:         s.RepoCount,
+		ChartCount:        s.ChartCount,
+		ProjectAdminCount: s.ProjectAdminCount,
+		MaintainerCount:   s.MaintainerCount,
+		DeveloperCount:    s.DeveloperCount,
+		GuestCount:        s.GuestCount,
+		LimitedGuestCount: s.LimitedGuestCount,
+	}
+	if s.Quota != nil {
+		summary.QuotaHard = s.Quota.Hard
+		summary.QuotaUsed = s.Quota.Used
+	}
+	return summary, nil
+}
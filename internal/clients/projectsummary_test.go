@@ -0,0 +1,100 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetProjectSummary(t *testing.T) {
+	cases := map[string]struct {
+		body       string
+		status     int
+		wantRepos  int64
+		wantCharts *int64
+		notFound   bool
+		wantQuota  bool
+	}{
+		"WithoutChartMuseum": {
+			body:      `{"repo_count": 12, "project_admin_count": 1}`,
+			wantRepos: 12,
+		},
+		"QuotaAndMembers": {
+			body: `{"repo_count": 2, "project_admin_count": 1, "maintainer_count": 2, "developer_count": 5, "guest_count": 3, "limited_guest_count": 4,
+				"quota": {"hard": {"storage": -1}, "used": {"storage": 1048576}}}`,
+			wantRepos: 2,
+			wantQuota: true,
+		},
+		"WithChartMuseum": {
+			body:       `{"repo_count": 3, "chart_count": 7}`,
+			wantRepos:  3,
+			wantCharts: func() *int64 { n := int64(7); return &n }(),
+		},
+		"NotFound": {
+			status:   http.StatusNotFound,
+			body:     `{"errors":[{"code":"NOT_FOUND"}]}`,
+			notFound: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v2.0/projects/team-a/summary", func(w http.ResponseWriter, r *http.Request) {
+				if u, _, ok := r.BasicAuth(); !ok || u != "admin" {
+					t.Errorf("summary requested without credentials")
+				}
+				if r.Header.Get("X-Is-Resource-Name") != "true" {
+					t.Errorf("summary requested without X-Is-Resource-Name")
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+				}
+				_, _ = w.Write([]byte(tc.body))
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := c.GetProjectSummary(context.Background(), "team-a")
+			if tc.notFound {
+				if !IsNotFound(err) {
+					t.Fatalf("GetProjectSummary() error = %v, want not found", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.RepoCount != tc.wantRepos {
+				t.Errorf("RepoCount = %d, want %d", s.RepoCount, tc.wantRepos)
+			}
+			if tc.wantQuota {
+				if s.QuotaHard["storage"] != -1 || s.QuotaUsed["storage"] != 1048576 {
+					t.Errorf("quota = hard %v, used %v", s.QuotaHard, s.QuotaUsed)
+				}
+				got := []int64{s.ProjectAdminCount, s.MaintainerCount, s.DeveloperCount, s.GuestCount, s.LimitedGuestCount}
+				if want := []int64{1, 2, 5, 3, 4}; !reflect.DeepEqual(got, want) {
+					t.Errorf("member counts = %v, want %v", got, want)
+				}
+			} else if s.QuotaHard != nil || s.QuotaUsed != nil {
+				t.Errorf("quota = hard %v, used %v, want none", s.QuotaHard, s.QuotaUsed)
+			}
+			switch {
+			case tc.wantCharts == nil && s.ChartCount != nil:
+				t.Errorf("ChartCount = %d, want none", *s.ChartCount)
+			case tc.wantCharts != nil && (s.ChartCount == nil || *s.ChartCount != *tc.wantCharts):
+				t.Errorf("ChartCount = %v, want %d", s.ChartCount, *tc.wantCharts)
+			}
+		})
+	}
+}
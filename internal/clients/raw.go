@@ -0,0 +1,131 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/pkg/errors"
+)
+
+// A RawPath is a Harbor API path that may be managed as raw JSON. Harbor
+// must answer GET and PUT at the path with the same object.
+type RawPath struct {
+	// Pattern is the path relative to /api/v2.0, with * standing for one
+	// path segment.
+	Pattern string
+
+	// Deletable is whether the object at the path may be deleted. Settings
+	// that always exist are not.
+	Deletable bool
+}
+
+// RawPaths are the only paths that may be managed as raw JSON. Paths the
+// provider has a kind for are left out, as are those that hold credentials.
+var RawPaths = []RawPath{
+	{Pattern: "/labels/*", Deletable: true},
+	{Pattern: "/p2p/preheat/instances/*", Deletable: true},
+	{Pattern: "/projects/*/metadatas/*", Deletable: true},
+	{Pattern: "/projects/*/preheat/policies/*", Deletable: true},
+	{Pattern: "/quotas/*"},
+	{Pattern: "/system/purgeaudit/schedule"},
+	{Pattern: "/system/scanAll/schedule"},
+}
+
+// MatchRawPath returns the RawPath that path matches, or an error if it
+// matches none.
+func MatchRawPath(path string) (RawPath, error) {
+	segs := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for _, s := range segs {
+		if s == "" || s == "." || s == ".." {
+			return RawPath{}, errors.Errorf("path %q is not a clean absolute path", path)
+		}
+	}
+	for _, p := range RawPaths {
+		want := strings.Split(strings.TrimPrefix(p.Pattern, "/"), "/")
+		if len(want) != len(segs) {
+			continue
+		}
+		match := true
+		for i := range want {
+			if want[i] != "*" && want[i] != segs[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return p, nil
+		}
+	}
+	return RawPath{}, errors.Errorf("path %q may not be managed as a raw resource", path)
+}
+
+// submitRaw sends method to the allowed path with body, which may be nil,
+// and returns the response body.
+func (c *HarborClient) submitRaw(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	p, err := MatchRawPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodDelete && !p.Deletable {
+		return nil, errors.Errorf("path %q may not be deleted", path)
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	id := "raw" + method
+	result, err := v2Client.Transport.Submit(&runtime.ClientOperation{
+		ID:                 id,
+		Method:             method,
+		PathPattern:        path,
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http", "https"},
+		Params: runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			if body == nil {
+				return nil
+			}
+			return r.SetBodyParam(json.RawMessage(body))
+		}),
+		Reader: runtime.ClientResponseReaderFunc(func(r runtime.ClientResponse, _ runtime.Consumer) (interface{}, error) {
+			if r.Code() < 200 || r.Code() > 299 {
+				return nil, runtime.NewAPIError(id, r.Message(), r.Code())
+			}
+			return io.ReadAll(r.Body())
+		}),
+		AuthInfo: httptransport.BasicAuth(c.config.Username, c.config.Password),
+		Context:  ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to %s %s", method, path)
+	}
+	return result.([]byte), nil
+}
+
+// GetRaw returns the JSON Harbor holds at an allowed path.
+func (c *HarborClient) GetRaw(ctx context.Context, path string) ([]byte, error) {
+	return c.submitRaw(ctx, http.MethodGet, path, nil)
+}
+
+// PutRaw puts the JSON body at an allowed path.
+func (c *HarborClient) PutRaw(ctx context.Context, path string, body []byte) error {
+	_, err := c.submitRaw(ctx, http.MethodPut, path, body)
+	return err
+}
+
+// DeleteRaw deletes the object at an allowed, deletable path.
+func (c *HarborClient) DeleteRaw(ctx context.Context, path string) error {
+	_, err := c.submitRaw(ctx, http.MethodDelete, path, nil)
+	return err
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchRawPath(t *testing.T) {
+	cases := map[string]struct {
+		path      string
+		deletable bool
+		wantErr   bool
+	}{
+		"ProjectMetadata": {path: "/projects/team-a/metadatas/auto_scan", deletable: true},
+		"Schedule":        {path: "/system/purgeaudit/schedule"},
+		"KindPath":        {path: "/system/gc/schedule", wantErr: true},
+		"ProjectKindPath": {path: "/projects/3/immutabletagrules/7", wantErr: true},
+		"NotAllowed":      {path: "/users/1/password", wantErr: true},
+		"TooLong":         {path: "/labels/1/extra", wantErr: true},
+		"Traversal":       {path: "/projects/../users/1", wantErr: true},
+		"EmptySegment":    {path: "/labels//1", wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := MatchRawPath(tc.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("MatchRawPath(%q) error = %v, want error: %v", tc.path, err, tc.wantErr)
+			}
+			if p.Deletable != tc.deletable {
+				t.Errorf("Deletable = %v, want %v", p.Deletable, tc.deletable)
+			}
+		})
+	}
+}
+
+func TestRaw(t *testing.T) {
+	var putBody string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/team-a/metadatas/auto_scan", func(w http.ResponseWriter, r *http.Request) {
+		if u, _, ok := r.BasicAuth(); !ok || u != "admin" {
+			t.Errorf("%s without credentials", r.Method)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"auto_scan":"true"}`))
+		case http.MethodPut:
+			b, _ := io.ReadAll(r.Body)
+			putBody = string(b)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("/api/v2.0/system/purgeaudit/schedule", func(_ http.ResponseWriter, r *http.Request) {
+		t.Errorf("%s of a setting that cannot be deleted reached Harbor", r.Method)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	const path = "/projects/team-a/metadatas/auto_scan"
+
+	got, err := c.GetRaw(ctx, path)
+	if err != nil || string(got) != `{"auto_scan":"true"}` {
+		t.Errorf("GetRaw() = %s, %v", got, err)
+	}
+	if err := c.PutRaw(ctx, path, []byte(`{"auto_scan":"false"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if putBody != `{"auto_scan":"false"}`+"\n" && putBody != `{"auto_scan":"false"}` {
+		t.Errorf("PUT body = %q", putBody)
+	}
+	if err := c.DeleteRaw(ctx, path); !IsNotFound(err) {
+		t.Errorf("DeleteRaw() error = %v, want not found", err)
+	}
+	if err := c.DeleteRaw(ctx, "/system/purgeaudit/schedule"); err == nil {
+		t.Error("DeleteRaw() of a setting error = nil, want refused")
+	}
+	if _, err := c.GetRaw(ctx, "/users/1"); err == nil {
+		t.Error("GetRaw() of a path that is not allowed error = nil")
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	var auth []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/systeminfo", func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"harbor_version":"v2.11.0"}`))
+	})
+	mux.HandleFunc("/api/v2.0/system/purgeaudit/schedule", func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Token: "id-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.systemCache = newSystemCache(time.Minute)
+	ctx := context.Background()
+	if _, err := c.GetSystemInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRaw(ctx, "/system/purgeaudit/schedule"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Bearer id-token", "Bearer id-token"}
+	if !reflect.DeepEqual(auth, want) {
+		t.Errorf("Authorization = %q, want %q", auth, want)
+	}
+	if strings.Contains(c.systemCacheKey(), "id-token") {
+		t.Errorf("systemCacheKey() = %q holds the token", c.systemCacheKey())
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"time"
+
+	sdkregistry "github.com/goharbor/go-client/pkg/sdk/v2.0/client/registry"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// registryStatus converts a registry endpoint returned by Harbor.
+func registryStatus(r *sdkmodels.Registry) *RegistryStatus {
+	s := &RegistryStatus{
+		ID:        r.ID,
+		Name:      r.Name,
+		Type:      r.Type,
+		URL:       r.URL,
+		Insecure:  r.Insecure,
+		Status:    r.Status,
+		CreatedAt: time.Time(r.CreationTime),
+		UpdatedAt: time.Time(r.UpdateTime),
+	}
+	if r.Description != "" {
+		s.Description = &r.Description
+	}
+	// Harbor reports a registry without a credential as one with an empty
+	// credential.
+	if c := r.Credential; c != nil && (c.Type != "" || c.AccessKey != "") {
+		s.Credential = &RegistryCredential{Type: c.Type, AccessKey: c.AccessKey}
+	}
+	return s
+}
+
+// getRegistryByID returns the registry endpoint with the given ID.
+func (c *HarborClient) getRegistryByID(ctx context.Context, id int64) (*RegistryStatus, error) {
+	resp, err := c.clientSet.V2().Registry.GetRegistry(ctx, &sdkregistry.GetRegistryParams{ID: id, Context: ctx})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get registry %d", id)
+	}
+	return registryStatus(resp.Payload), nil
+}
+
+// CreateRegistry creates a new Harbor registry
+func (c *HarborClient) CreateRegistry(ctx context.Context, spec *RegistrySpec) (*RegistryStatus, error) {
+	if spec == nil {
+		return nil, errors.New("registry spec is required")
+	}
+	if spec.Name == "" {
+		return nil, errors.New("registry name is required")
+	}
+	if spec.URL == "" {
+		return nil, errors.New("registry URL is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Creating Harbor registry", "name", spec.Name, "url", spec.URL, "type", spec.Type)
+
+	r := &sdkmodels.Registry{
+		Name:     spec.Name,
+		Type:     spec.Type,
+		URL:      spec.URL,
+		Insecure: spec.Insecure,
+	}
+	if spec.Description != nil {
+		r.Description = *spec.Description
+	}
+	if cred := spec.Credential; cred != nil {
+		r.Credential = &sdkmodels.RegistryCredential{
+			Type:         cred.Type,
+			AccessKey:    cred.AccessKey,
+			AccessSecret: cred.AccessSecret,
+		}
+	}
+
+	created, err := v2Client.Registry.CreateRegistry(ctx, &sdkregistry.CreateRegistryParams{Registry: r, Context: ctx})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create registry %s", spec.Name)
+	}
+	id, err := strconv.ParseInt(path.Base(created.Location), 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("cannot parse registry ID from location %q", created.Location)
+	}
+	return c.getRegistryByID(ctx, id)
+}
+
+// GetRegistry retrieves a Harbor registry by name
+func (c *HarborClient) GetRegistry(ctx context.Context, registryName string) (*RegistryStatus, error) {
+	if registryName == "" {
+		return nil, errors.New("registry name is required")
+	}
+
+	r, err := c.registryByName(ctx, registryName)
+	if err != nil {
+		return nil, err
+	}
+	return registryStatus(r), nil
+}
+
+// UpdateRegistry updates an existing Harbor registry. The access secret is
+// only changed when spec has one, since Harbor never returns it.
+func (c *HarborClient) UpdateRegistry(ctx context.Context, registryName string, spec *RegistrySpec) (*RegistryStatus, error) {
+	if registryName == "" {
+		return nil, errors.New("registry name is required")
+	}
+	if spec == nil {
+		return nil, errors.New("registry spec is required")
+	}
+
+	r, err := c.registryByName(ctx, registryName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Updating Harbor registry", "name", registryName, "url", spec.URL, "type", spec.Type)
+
+	update := &sdkmodels.RegistryUpdate{
+		Name:        &spec.Name,
+		URL:         &spec.URL,
+		Insecure:    &spec.Insecure,
+		Description: spec.Description,
+	}
+	if cred := spec.Credential; cred != nil {
+		update.CredentialType = &cred.Type
+		update.AccessKey = &cred.AccessKey
+		if cred.AccessSecret != "" {
+			update.AccessSecret = &cred.AccessSecret
+		}
+	}
+
+	_, err = c.clientSet.V2().Registry.UpdateRegistry(ctx, &sdkregistry.UpdateRegistryParams{ID: r.ID, Registry: update, Context: ctx})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update registry %s", registryName)
+	}
+	return c.getRegistryByID(ctx, r.ID)
+}
+
+// DeleteRegistry deletes a Harbor registry. Deleting a registry that does
+// not exist succeeds.
+func (c *HarborClient) DeleteRegistry(ctx context.Context, registryName string) error {
+	if registryName == "" {
+		return errors.New("registry name is required")
+	}
+
+	r, err := c.registryByName(ctx, registryName)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info("Deleting Harbor registry", "name", registryName, "id", r.ID)
+
+	_, err = c.clientSet.V2().Registry.DeleteRegistry(ctx, &sdkregistry.DeleteRegistryParams{ID: r.ID, Context: ctx})
+	if IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "failed to delete registry %s", registryName)
+}
+
+// PingRegistry checks that Harbor can reach the registry with the given ID
+// using its stored credential.
+func (c *HarborClient) PingRegistry(ctx context.Context, id int64) error {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	_, err := v2Client.Registry.PingRegistry(ctx, &sdkregistry.PingRegistryParams{
+		Registry: &sdkmodels.RegistryPing{ID: &id},
+		Context:  ctx,
+	})
+	return errors.Wrapf(err, "failed to ping registry %d", id)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestRegistryLifecycle(t *testing.T) {
+	registries := map[string]string{}
+	var created, updated map[string]interface{}
+	var pinged map[string]interface{}
+	deleted := false
+	hub := `{"id": 7, "name": "hub", "type": "docker-hub", "url": "https://hub.docker.com", "status": "healthy",
+		"credential": {"type": "basic", "access_key": "robot"}, "creation_time": "2024-01-01T00:00:00Z"}`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/registries", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			registries["hub"] = hub
+			w.Header().Set("Location", "/api/v2.0/registries/7")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("q") != "name=hub" {
+			t.Errorf("registries listed with q=%q, want name=hub", r.URL.Query().Get("q"))
+		}
+		if reg, ok := registries["hub"]; ok {
+			_, _ = w.Write([]byte(`[` + reg + `]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/api/v2.0/registries/7", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+		case http.MethodDelete:
+			deleted = true
+			delete(registries, "hub")
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(hub))
+		}
+	})
+	mux.HandleFunc("/api/v2.0/registries/ping", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&pinged)
+	})
+	c := executionsClient(t, mux)
+	ctx := context.Background()
+
+	if _, err := c.GetRegistry(ctx, "hub"); !IsNotFound(err) {
+		t.Errorf("GetRegistry() of a missing registry error = %v, want not found", err)
+	}
+
+	spec := &RegistrySpec{
+		Name:       "hub",
+		Type:       "docker-hub",
+		URL:        "https://hub.docker.com",
+		Credential: &RegistryCredential{Type: "basic", AccessKey: "robot", AccessSecret: "s3cret"},
+	}
+	s, err := c.CreateRegistry(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ID != 7 || s.Status != "healthy" || s.Credential == nil || s.Credential.AccessKey != "robot" {
+		t.Errorf("CreateRegistry() = %+v, want registry 7", s)
+	}
+	if cred, _ := created["credential"].(map[string]interface{}); cred["access_secret"] != "s3cret" {
+		t.Errorf("CreateRegistry() sent credential %v, want the access secret", created["credential"])
+	}
+
+	got, err := c.GetRegistry(ctx, "hub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 7 || got.URL != "https://hub.docker.com" {
+		t.Errorf("GetRegistry() = %+v, want registry 7", got)
+	}
+
+	// Without a secret the stored one is kept.
+	spec.Credential.AccessSecret = ""
+	if _, err := c.UpdateRegistry(ctx, "hub", spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := updated["access_secret"]; ok || updated["access_key"] != "robot" {
+		t.Errorf("UpdateRegistry() sent %v, want the access key and no access secret", updated)
+	}
+
+	if err := c.PingRegistry(ctx, 7); err != nil {
+		t.Fatal(err)
+	}
+	if pinged["id"] != float64(7) {
+		t.Errorf("PingRegistry() sent %v, want id 7", pinged)
+	}
+
+	if err := c.DeleteRegistry(ctx, "hub"); err != nil || !deleted {
+		t.Errorf("DeleteRegistry() = %v, deleted %v", err, deleted)
+	}
+	if err := c.DeleteRegistry(ctx, "hub"); err != nil {
+		t.Errorf("DeleteRegistry() of a missing registry = %v, want success", err)
+	}
+}
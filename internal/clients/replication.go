@@ -0,0 +1,145 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdkregistry "github.com/goharbor/go-client/pkg/sdk/v2.0/client/registry"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// Harbor names the repository filter of a replication policy "name", and
+// takes the labels of a label filter as a list.
+const (
+	replicationFilterRepository = "repository"
+	replicationFilterName       = "name"
+	replicationFilterLabel      = "label"
+)
+
+// replicationPolicy converts spec to a Harbor replication policy, looking up
+// its registries by name. A policy with a source registry pulls into the
+// local Harbor; any other policy pushes to its destination registry.
+func (c *HarborClient) replicationPolicy(ctx context.Context, spec *ReplicationPolicySpec) (*sdkmodels.ReplicationPolicy, error) {
+	p := &sdkmodels.ReplicationPolicy{
+		Name:              spec.Name,
+		Enabled:           spec.Enabled == nil || *spec.Enabled,
+		Override:          spec.Override == nil || *spec.Override,
+		ReplicateDeletion: spec.ReplicateDeletion != nil && *spec.ReplicateDeletion,
+		Trigger:           &sdkmodels.ReplicationTrigger{Type: spec.Trigger},
+		Filters:           []*sdkmodels.ReplicationFilter{},
+	}
+	if spec.Description != nil {
+		p.Description = *spec.Description
+	}
+	if spec.CronSchedule != nil {
+		p.Trigger.TriggerSettings = &sdkmodels.ReplicationTriggerSettings{Cron: *spec.CronSchedule}
+	}
+	if spec.DestinationReg != nil {
+		p.DestNamespace = spec.DestinationReg.Namespace
+	}
+
+	var err error
+	switch {
+	case spec.SourceRegistry != nil && *spec.SourceRegistry != "":
+		p.SrcRegistry, err = c.registryByName(ctx, *spec.SourceRegistry)
+	case spec.DestinationReg != nil && spec.DestinationReg.Name != "":
+		p.DestRegistry, err = c.registryByName(ctx, spec.DestinationReg.Name)
+	default:
+		err = errors.New("a source or destination registry is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range spec.Filters {
+		rf := &sdkmodels.ReplicationFilter{Type: f.Type, Value: f.Value, Decoration: f.Decoration}
+		switch f.Type {
+		case replicationFilterRepository:
+			rf.Type = replicationFilterName
+		case replicationFilterLabel:
+			var labels []string
+			for _, l := range strings.Split(f.Value, ",") {
+				labels = append(labels, strings.TrimSpace(l))
+			}
+			rf.Value = labels
+		}
+		p.Filters = append(p.Filters, rf)
+	}
+	return p, nil
+}
+
+// registryByName returns the registry endpoint with the given name.
+func (c *HarborClient) registryByName(ctx context.Context, name string) (*sdkmodels.Registry, error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	q := "name=" + name
+	resp, err := v2Client.Registry.ListRegistries(ctx, &sdkregistry.ListRegistriesParams{Q: &q, Context: ctx})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to look up registry %s", name)
+	}
+	for _, r := range resp.Payload {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return nil, errors.Wrapf(sdkregistry.NewGetRegistryNotFound(), "registry %s not found", name)
+}
+
+// replicationPolicyStatus converts a replication policy returned by Harbor.
+func replicationPolicyStatus(p *sdkmodels.ReplicationPolicy) *ReplicationPolicyStatus {
+	s := &ReplicationPolicyStatus{
+		ID:                fmt.Sprint(p.ID),
+		Name:              p.Name,
+		Description:       &p.Description,
+		Enabled:           p.Enabled,
+		CreationTime:      time.Time(p.CreationTime),
+		UpdateTime:        time.Time(p.UpdateTime),
+		DestNamespace:     p.DestNamespace,
+		ReplicateDeletion: p.ReplicateDeletion,
+		Override:          p.Override,
+	}
+	// Harbor reports the local registry with ID 0.
+	if p.SrcRegistry != nil && p.SrcRegistry.ID != 0 {
+		s.SourceRegistry = p.SrcRegistry.Name
+	}
+	if p.DestRegistry != nil && p.DestRegistry.ID != 0 {
+		s.DestinationRegistry = p.DestRegistry.Name
+	}
+	if p.Trigger != nil {
+		s.Trigger = p.Trigger.Type
+		if p.Trigger.TriggerSettings != nil {
+			s.CronSchedule = p.Trigger.TriggerSettings.Cron
+		}
+	}
+	for _, f := range p.Filters {
+		if f == nil {
+			continue
+		}
+		rf := ReplicationPolicyFilter{Type: f.Type, Decoration: f.Decoration}
+		switch v := f.Value.(type) {
+		case string:
+			rf.Value = v
+		case []any:
+			labels := make([]string, 0, len(v))
+			for _, l := range v {
+				labels = append(labels, fmt.Sprint(l))
+			}
+			rf.Value = strings.Join(labels, ",")
+		}
+		if rf.Type == replicationFilterName {
+			rf.Type = replicationFilterRepository
+		}
+		s.Filters = append(s.Filters, rf)
+	}
+	return s
+}
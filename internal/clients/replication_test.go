@@ -0,0 +1,107 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCreateReplicationPolicy(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/registries", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "name=dr-site" {
+			t.Errorf("registries requested with query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 4, "name": "dr-site-old"}, {"id": 5, "name": "dr-site"}]`))
+	})
+	mux.HandleFunc("/api/v2.0/replication/policies", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var p map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&p)
+			dest, _ := p["dest_registry"].(map[string]any)
+			trigger, _ := p["trigger"].(map[string]any)
+			settings, _ := trigger["trigger_settings"].(map[string]any)
+			filters, _ := p["filters"].([]any)
+			if dest["id"] != float64(5) || p["src_registry"] != nil || trigger["type"] != "scheduled" ||
+				settings["cron"] != "0 0 2 * * *" || p["replicate_deletion"] != true || p["override"] != true || len(filters) != 2 {
+				t.Errorf("policy created as %v", p)
+			}
+			name, _ := filters[0].(map[string]any)
+			label, _ := filters[1].(map[string]any)
+			if name["type"] != "name" || name["value"] != "team-a/**" {
+				t.Errorf("repository filter created as %v", name)
+			}
+			if labels, _ := label["value"].([]any); label["decoration"] != "excludes" || len(labels) != 2 || labels[1] != "draft" {
+				t.Errorf("label filter created as %v", label)
+			}
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 9, "name": "to-dr", "enabled": true, "override": true, "replicate_deletion": true,
+			"dest_registry": {"id": 5, "name": "dr-site"}, "src_registry": {"id": 0, "name": "Local"},
+			"trigger": {"type": "scheduled", "trigger_settings": {"cron": "0 0 2 * * *"}},
+			"filters": [{"type": "name", "value": "team-a/**"}, {"type": "label", "decoration": "excludes", "value": ["wip", "draft"]}]}]`))
+	})
+	c := executionsClient(t, mux)
+
+	yes, cron := true, "0 0 2 * * *"
+	got, err := c.CreateReplicationPolicy(context.Background(), &ReplicationPolicySpec{
+		Name:              "to-dr",
+		DestinationReg:    &ReplicationPolicyDestination{Name: "dr-site"},
+		Trigger:           "scheduled",
+		CronSchedule:      &cron,
+		ReplicateDeletion: &yes,
+		Filters: []ReplicationPolicyFilter{
+			{Type: "repository", Value: "team-a/**"},
+			{Type: "label", Value: "wip, draft", Decoration: "excludes"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "9" || got.DestinationRegistry != "dr-site" || got.SourceRegistry != "" || got.CronSchedule != cron || !got.ReplicateDeletion {
+		t.Errorf("CreateReplicationPolicy() = %+v", got)
+	}
+	want := []ReplicationPolicyFilter{{Type: "repository", Value: "team-a/**"}, {Type: "label", Value: "wip,draft", Decoration: "excludes"}}
+	if len(got.Filters) != 2 || got.Filters[0] != want[0] || got.Filters[1] != want[1] {
+		t.Errorf("filters = %+v, want %+v", got.Filters, want)
+	}
+}
+
+func TestCreateReplicationPolicyUnknownRegistry(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/registries", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/api/v2.0/replication/policies", func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("no policy should be created for an unknown registry")
+	})
+	c := executionsClient(t, mux)
+
+	src := "nowhere"
+	if _, err := c.CreateReplicationPolicy(context.Background(), &ReplicationPolicySpec{Name: "pull", SourceRegistry: &src, Trigger: "manual"}); err == nil {
+		t.Error("CreateReplicationPolicy() should fail for an unknown registry")
+	}
+}
+
+func TestDeleteReplicationPolicyNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/replication/policies/9", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors": [{"code": "NOT_FOUND"}]}`))
+	})
+	c := executionsClient(t, mux)
+
+	if err := c.DeleteReplicationPolicy(context.Background(), "9"); err != nil {
+		t.Errorf("DeleteReplicationPolicy() of a missing policy = %v, want success", err)
+	}
+}
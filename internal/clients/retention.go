@@ -0,0 +1,332 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+
+	sdkretention "github.com/goharbor/go-client/pkg/sdk/v2.0/client/retention"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// Retention rule types. Each but RetentionRuleAlways retains the Value most
+// recently pushed or pulled artifacts, or those pushed or pulled in the last
+// Value days.
+const (
+	RetentionRuleAlways            = "always"
+	RetentionRuleLatestPushedK     = "latestPushedK"
+	RetentionRuleLatestPulledN     = "latestPulledN"
+	RetentionRuleDaysSinceLastPush = "daysSinceLastPush"
+	RetentionRuleDaysSinceLastPull = "daysSinceLastPull"
+)
+
+// retentionTemplates maps rule types to the rule templates Harbor knows them
+// by, where they differ. A template's parameter has the template's name.
+var retentionTemplates = map[string]string{
+	RetentionRuleDaysSinceLastPush: "nDaysSinceLastPush",
+	RetentionRuleDaysSinceLastPull: "nDaysSinceLastPull",
+}
+
+// Harbor's fixed parts of a retention policy: policies are per project and
+// retain the artifacts any rule matches.
+const (
+	retentionAlgorithm   = "or"
+	retentionScopeLevel  = "project"
+	retentionAction      = "retain"
+	retentionTriggerKind = "Schedule"
+	retentionCronSetting = "cron"
+)
+
+// RetentionPolicyRule is a rule of a retention policy. Rules are comparable,
+// so two policies have the same rules when they have equal rules in any
+// order.
+type RetentionPolicyRule struct {
+	// RuleType is one of the RetentionRule constants.
+	RuleType string
+	// Value is the number of artifacts or days the rule retains. Rules of
+	// type always have none.
+	Value int64
+
+	// RepositoryPattern is a doublestar pattern of the repositories the
+	// rule applies to, or to which it does not with ExcludeRepositories.
+	RepositoryPattern   string
+	ExcludeRepositories bool
+	// TagPattern is a doublestar pattern of the tags the rule applies to, or
+	// to which it does not with ExcludeTags.
+	TagPattern  string
+	ExcludeTags bool
+
+	Disabled bool
+}
+
+// RetentionPolicySpec defines the desired state of a retention policy
+type RetentionPolicySpec struct {
+	ProjectID string
+	Rules     []RetentionPolicyRule
+	// Schedule is the six field cron expression the policy runs on. The
+	// policy only runs when triggered by hand when it is empty.
+	Schedule string
+}
+
+// RetentionPolicyStatus represents the status of a retention policy
+type RetentionPolicyStatus struct {
+	ID        string
+	ProjectID string
+	Rules     []RetentionPolicyRule
+	Schedule  string
+	// Enabled is whether any of the policy's rules is enabled.
+	Enabled bool
+}
+
+// retentionPolicyModel returns the retention policy Harbor expects for spec.
+func retentionPolicyModel(projectID int64, spec *RetentionPolicySpec) *sdkmodels.RetentionPolicy {
+	p := &sdkmodels.RetentionPolicy{
+		Algorithm: retentionAlgorithm,
+		Scope:     &sdkmodels.RetentionPolicyScope{Level: retentionScopeLevel, Ref: projectID},
+		Trigger: &sdkmodels.RetentionRuleTrigger{
+			Kind:       retentionTriggerKind,
+			Settings:   map[string]interface{}{retentionCronSetting: spec.Schedule},
+			References: map[string]interface{}{},
+		},
+		Rules: make([]*sdkmodels.RetentionRule, 0, len(spec.Rules)),
+	}
+	for _, r := range spec.Rules {
+		template := r.RuleType
+		if t, ok := retentionTemplates[r.RuleType]; ok {
+			template = t
+		}
+		params := map[string]interface{}{}
+		if r.RuleType != RetentionRuleAlways {
+			params[template] = r.Value
+		}
+		repoDecoration, tagDecoration := selectorRepoMatches, selectorTagMatches
+		if r.ExcludeRepositories {
+			repoDecoration = selectorRepoExcludes
+		}
+		if r.ExcludeTags {
+			tagDecoration = selectorTagExcludes
+		}
+		p.Rules = append(p.Rules, &sdkmodels.RetentionRule{
+			Action:   retentionAction,
+			Template: template,
+			Params:   params,
+			Disabled: r.Disabled,
+			ScopeSelectors: map[string][]sdkmodels.RetentionSelector{
+				selectorRepositoryKey: {{Kind: selectorKind, Decoration: repoDecoration, Pattern: r.RepositoryPattern}},
+			},
+			TagSelectors: []*sdkmodels.RetentionSelector{
+				{Kind: selectorKind, Decoration: tagDecoration, Pattern: r.TagPattern},
+			},
+		})
+	}
+	return p
+}
+
+// retentionPolicyStatus converts a retention policy returned by Harbor.
+func retentionPolicyStatus(p *sdkmodels.RetentionPolicy) *RetentionPolicyStatus {
+	s := &RetentionPolicyStatus{ID: strconv.FormatInt(p.ID, 10)}
+	if p.Scope != nil {
+		s.ProjectID = strconv.FormatInt(p.Scope.Ref, 10)
+	}
+	if p.Trigger != nil {
+		if settings, ok := p.Trigger.Settings.(map[string]interface{}); ok {
+			s.Schedule, _ = settings[retentionCronSetting].(string)
+		}
+	}
+	for _, r := range p.Rules {
+		if r == nil {
+			continue
+		}
+		rule := RetentionPolicyRule{RuleType: r.Template, Disabled: r.Disabled}
+		for ruleType, template := range retentionTemplates {
+			if r.Template == template {
+				rule.RuleType = ruleType
+			}
+		}
+		rule.Value = retentionParam(r.Params[r.Template])
+		if repos := r.ScopeSelectors[selectorRepositoryKey]; len(repos) > 0 {
+			rule.RepositoryPattern = repos[0].Pattern
+			rule.ExcludeRepositories = repos[0].Decoration == selectorRepoExcludes
+		}
+		if len(r.TagSelectors) > 0 && r.TagSelectors[0] != nil {
+			rule.TagPattern = r.TagSelectors[0].Pattern
+			rule.ExcludeTags = r.TagSelectors[0].Decoration == selectorTagExcludes
+		}
+		s.Rules = append(s.Rules, rule)
+		s.Enabled = s.Enabled || !rule.Disabled
+	}
+	return s
+}
+
+// retentionParam reads a rule parameter, which Harbor returns as a JSON
+// number or, for policies saved by older releases, a string. The SDK
+// decodes numbers as json.Number.
+func retentionParam(v interface{}) int64 {
+	switch v := v.(type) {
+	case json.Number:
+		n, _ := v.Int64()
+		return n
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+// parseProjectID parses the numeric ID a retention policy's scope needs.
+func parseProjectID(projectID string) (int64, error) {
+	if projectID == "" {
+		return 0, errors.New("project ID is required")
+	}
+	id, err := strconv.ParseInt(projectID, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("project ID must be numeric, got %q", projectID)
+	}
+	return id, nil
+}
+
+// CreateRetentionPolicy creates a retention policy for spec's project.
+// Harbor only runs it once the project is linked to it; see
+// SetProjectRetentionID.
+func (c *HarborClient) CreateRetentionPolicy(ctx context.Context, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error) {
+	if spec == nil {
+		return nil, errors.New("spec is required")
+	}
+	projectID, err := parseProjectID(spec.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(spec.Rules) == 0 {
+		return nil, errors.New("at least one rule is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Creating Harbor retention policy",
+		"projectId", spec.ProjectID,
+		"rulesCount", len(spec.Rules))
+
+	created, err := v2Client.Retention.CreateRetention(ctx, &sdkretention.CreateRetentionParams{
+		Policy:  retentionPolicyModel(projectID, spec),
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create retention policy for project %s", spec.ProjectID)
+	}
+	if _, err := strconv.ParseInt(path.Base(created.Location), 10, 64); err != nil {
+		return nil, errors.Errorf("cannot parse retention policy ID from location %q", created.Location)
+	}
+	return c.GetRetentionPolicy(ctx, spec.ProjectID, path.Base(created.Location))
+}
+
+// ListRetentionPolicies lists the retention policies of a project. Harbor
+// gives a project at most one, the one its retention_id metadata links.
+func (c *HarborClient) ListRetentionPolicies(ctx context.Context, projectID string) ([]*RetentionPolicyStatus, error) {
+	retentionID, err := c.GetProjectRetentionID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if retentionID == "" {
+		return nil, nil
+	}
+
+	c.logger.Info("Listing Harbor retention policies", "projectId", projectID)
+
+	policy, err := c.GetRetentionPolicy(ctx, projectID, retentionID)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []*RetentionPolicyStatus{policy}, nil
+}
+
+// GetRetentionPolicy retrieves a specific retention policy
+func (c *HarborClient) GetRetentionPolicy(ctx context.Context, projectID, policyID string) (*RetentionPolicyStatus, error) {
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	resp, err := v2Client.Retention.GetRetention(ctx, &sdkretention.GetRetentionParams{ID: id, Context: ctx})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get retention policy %s", policyID)
+	}
+	return retentionPolicyStatus(resp.Payload), nil
+}
+
+// UpdateRetentionPolicy replaces the rules and schedule of a retention
+// policy.
+func (c *HarborClient) UpdateRetentionPolicy(ctx context.Context, projectID, policyID string, spec *RetentionPolicySpec) (*RetentionPolicyStatus, error) {
+	if spec == nil {
+		return nil, errors.New("spec is required")
+	}
+	project, err := parseProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Updating Harbor retention policy", "projectId", projectID, "policyId", policyID)
+
+	policy := retentionPolicyModel(project, spec)
+	policy.ID = id
+	if _, err := v2Client.Retention.UpdateRetention(ctx, &sdkretention.UpdateRetentionParams{ID: id, Policy: policy, Context: ctx}); err != nil {
+		return nil, errors.Wrapf(err, "failed to update retention policy %s", policyID)
+	}
+	return c.GetRetentionPolicy(ctx, projectID, policyID)
+}
+
+// DeleteRetentionPolicy deletes a retention policy
+func (c *HarborClient) DeleteRetentionPolicy(ctx context.Context, projectID, policyID string) error {
+	if projectID == "" {
+		return errors.New("project ID is required")
+	}
+	id, err := parsePolicyID(policyID)
+	if err != nil {
+		return err
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Deleting Harbor retention policy", "projectId", projectID, "policyId", policyID)
+
+	_, err = v2Client.Retention.DeleteRetention(ctx, &sdkretention.DeleteRetentionParams{ID: id, Context: ctx})
+	if IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "failed to delete retention policy %s", policyID)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRetentionPolicyRoundTrip(t *testing.T) {
+	var saved json.RawMessage
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/retentions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("retentions called with %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&saved); err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Location", "/api/v2.0/retentions/4")
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/api/v2.0/retentions/4", func(w http.ResponseWriter, r *http.Request) {
+		// Harbor returns the policy it was given, with its ID.
+		var p map[string]interface{}
+		if err := json.Unmarshal(saved, &p); err != nil {
+			t.Fatal(err)
+		}
+		p["id"] = 4
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(p)
+	})
+	mux.HandleFunc("/api/v2.0/projects/5/metadatas/retention_id", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"retention_id": "4"}`))
+	})
+	c := executionsClient(t, mux)
+
+	spec := &RetentionPolicySpec{
+		ProjectID: "5",
+		Schedule:  "0 0 2 * * *",
+		Rules: []RetentionPolicyRule{
+			{RuleType: RetentionRuleLatestPushedK, Value: 10, RepositoryPattern: "team-a/**", TagPattern: "**"},
+			{RuleType: RetentionRuleDaysSinceLastPull, Value: 30, RepositoryPattern: "**", TagPattern: "release-*", ExcludeTags: true},
+		},
+	}
+	created, err := c.CreateRetentionPolicy(context.Background(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent struct {
+		Algorithm string `json:"algorithm"`
+		Scope     struct {
+			Level string `json:"level"`
+			Ref   int64  `json:"ref"`
+		} `json:"scope"`
+		Rules []struct {
+			Template string                 `json:"template"`
+			Params   map[string]interface{} `json:"params"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal(saved, &sent); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Algorithm != "or" || sent.Scope.Level != "project" || sent.Scope.Ref != 5 {
+		t.Errorf("sent policy %s", saved)
+	}
+	if len(sent.Rules) != 2 || sent.Rules[1].Template != "nDaysSinceLastPull" || sent.Rules[1].Params["nDaysSinceLastPull"] != float64(30) {
+		t.Errorf("sent rules %+v", sent.Rules)
+	}
+
+	want := &RetentionPolicyStatus{ID: "4", ProjectID: "5", Rules: spec.Rules, Schedule: spec.Schedule, Enabled: true}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("CreateRetentionPolicy() = %+v, want %+v", created, want)
+	}
+
+	listed, err := c.ListRetentionPolicies(context.Background(), "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || !reflect.DeepEqual(listed[0], want) {
+		t.Errorf("ListRetentionPolicies() = %+v, want the linked policy", listed)
+	}
+}
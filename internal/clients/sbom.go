@@ -0,0 +1,76 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"strings"
+
+	sdkartifact "github.com/goharbor/go-client/pkg/sdk/v2.0/client/artifact"
+	sdkrepository "github.com/goharbor/go-client/pkg/sdk/v2.0/client/repository"
+	"github.com/pkg/errors"
+)
+
+// SBOMSample summarises SBOM coverage of a project's most recent artifacts
+type SBOMSample struct {
+	// Artifacts is the number of artifacts inspected
+	Artifacts int64
+	// WithSBOM is how many of them have an SBOM attached
+	WithSBOM int64
+}
+
+// SampleProjectSBOMs inspects the latest artifact of up to maxRepos of the
+// project's most recently updated repositories and counts how many carry an
+// SBOM. Harbor only reports SBOMs from v2.10.
+func (c *HarborClient) SampleProjectSBOMs(ctx context.Context, projectName string, maxRepos int64) (*SBOMSample, error) {
+	if projectName == "" {
+		return nil, errors.New("project name is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	sortByUpdate := "-update_time"
+	repos, err := v2Client.Repository.ListRepositories(ctx, &sdkrepository.ListRepositoriesParams{
+		ProjectName: projectName,
+		PageSize:    &maxRepos,
+		Sort:        &sortByUpdate,
+		Context:     ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list repositories")
+	}
+
+	sample := &SBOMSample{}
+	one := int64(1)
+	sortByPush := "-push_time"
+	withSBOM := true
+	for _, r := range repos.Payload {
+		// Repository names are returned with the project prefix.
+		name := encodeRepositoryName(strings.TrimPrefix(r.Name, projectName+"/"))
+
+		arts, err := v2Client.Artifact.ListArtifacts(ctx, &sdkartifact.ListArtifactsParams{
+			ProjectName:      projectName,
+			RepositoryName:   name,
+			PageSize:         &one,
+			Sort:             &sortByPush,
+			WithSbomOverview: &withSBOM,
+			Context:          ctx,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list artifacts of repository %s", r.Name)
+		}
+		for _, a := range arts.Payload {
+			sample.Artifacts++
+			if a.SbomOverview != nil && a.SbomOverview.SbomDigest != "" {
+				sample.WithSBOM++
+			}
+		}
+	}
+
+	return sample, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSampleProjectSBOMs(t *testing.T) {
+	var artifactPaths []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/library/repositories", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("page_size"); got != "2" {
+			t.Errorf("page_size = %q, want 2", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"name": "library/nginx"},
+			{"name": "library/team/app"},
+		})
+	})
+	mux.HandleFunc("/api/v2.0/projects/library/repositories/", func(w http.ResponseWriter, r *http.Request) {
+		artifactPaths = append(artifactPaths, r.URL.EscapedPath())
+		if r.URL.Query().Get("with_sbom_overview") != "true" {
+			t.Error("artifacts listed without with_sbom_overview")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		art := map[string]interface{}{"digest": "sha256:1"}
+		if r.URL.EscapedPath() == "/api/v2.0/projects/library/repositories/nginx/artifacts" {
+			art["sbom_overview"] = map[string]interface{}{"sbom_digest": "sha256:2"}
+		}
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{art})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.SampleProjectSBOMs(context.Background(), "library", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Artifacts != 2 || got.WithSBOM != 1 {
+		t.Errorf("SampleProjectSBOMs() = %+v, want 2 artifacts, 1 with SBOM", got)
+	}
+	// Nested repository names must reach Harbor URL encoded twice.
+	if len(artifactPaths) != 2 || artifactPaths[1] != "/api/v2.0/projects/library/repositories/team%252Fapp/artifacts" {
+		t.Errorf("artifact requests = %v", artifactPaths)
+	}
+}
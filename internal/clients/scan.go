@@ -0,0 +1,351 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	sdkartifact "github.com/goharbor/go-client/pkg/sdk/v2.0/client/artifact"
+	sdkrepository "github.com/goharbor/go-client/pkg/sdk/v2.0/client/repository"
+	sdkscan "github.com/goharbor/go-client/pkg/sdk/v2.0/client/scan"
+	sdkscanall "github.com/goharbor/go-client/pkg/sdk/v2.0/client/scan_all"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// Statuses of an artifact scan. A scan is ongoing while Pending or Running.
+const (
+	ScanStatusPending = "Pending"
+	ScanStatusRunning = "Running"
+	ScanStatusSuccess = "Success"
+	ScanStatusError   = "Error"
+	ScanStatusStopped = "Stopped"
+)
+
+// scanPageSize is how many repositories or artifacts are read per request
+// when every artifact of a project is visited.
+const scanPageSize = 100
+
+// ScanStatus represents the status of an artifact scan
+type ScanStatus struct {
+	ID            string
+	Status        string
+	CriticalCount int64
+	HighCount     int64
+	MediumCount   int64
+	LowCount      int64
+	StartTime     time.Time
+	EndTime       time.Time
+}
+
+// ScanMetrics describe the progress of a scan of many artifacts.
+type ScanMetrics struct {
+	// Ongoing is whether any artifact is still being scanned.
+	Ongoing bool
+	// Total is how many artifacts the scan covers.
+	Total int64
+	// Completed is how many of them have finished scanning.
+	Completed int64
+	// Statuses counts the artifacts by scan status, such as Success.
+	Statuses map[string]int64
+	// Trigger is what started a scan of all artifacts: Manual, Schedule or
+	// Event. It is empty for a project's artifacts.
+	Trigger string
+}
+
+// scanOngoing reports whether a scan with status s has not finished.
+func scanOngoing(s string) bool {
+	return s == ScanStatusPending || s == ScanStatusRunning
+}
+
+// scanReport returns the artifact's vulnerability report summary. Harbor
+// keys reports by MIME type; the first in order is used when there are
+// several.
+func scanReport(o sdkmodels.ScanOverview) (sdkmodels.NativeReportSummary, bool) {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return sdkmodels.NativeReportSummary{}, false
+	}
+	sort.Strings(keys)
+	return o[keys[0]], true
+}
+
+// TriggerScan triggers a vulnerability scan of an artifact. projectID is the
+// project's name.
+func (c *HarborClient) TriggerScan(ctx context.Context, projectID, repoName, reference string) error {
+	if err := requireArtifact(projectID, repoName, reference); err != nil {
+		return err
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Triggering Harbor artifact scan", "projectId", projectID, "repo", repoName, "reference", reference)
+
+	_, err := v2Client.Scan.ScanArtifact(ctx, &sdkscan.ScanArtifactParams{
+		ProjectName:    projectID,
+		RepositoryName: encodeRepositoryName(repoName),
+		Reference:      reference,
+		Context:        ctx,
+	})
+	return errors.Wrap(err, "failed to trigger artifact scan")
+}
+
+// GetScan returns the latest vulnerability scan of an artifact. Its Status
+// is empty when the artifact has never been scanned.
+func (c *HarborClient) GetScan(ctx context.Context, projectID, repoName, reference string) (*ScanStatus, error) {
+	if err := requireArtifact(projectID, repoName, reference); err != nil {
+		return nil, err
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	withScan := true
+	resp, err := v2Client.Artifact.GetArtifact(ctx, &sdkartifact.GetArtifactParams{
+		ProjectName:      projectID,
+		RepositoryName:   encodeRepositoryName(repoName),
+		Reference:        reference,
+		WithScanOverview: &withScan,
+		Context:          ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get artifact scan")
+	}
+
+	scan := &ScanStatus{}
+	if resp.Payload == nil {
+		return scan, nil
+	}
+	r, ok := scanReport(resp.Payload.ScanOverview)
+	if !ok {
+		return scan, nil
+	}
+	scan.ID = r.ReportID
+	scan.Status = r.ScanStatus
+	scan.StartTime = time.Time(r.StartTime)
+	scan.EndTime = time.Time(r.EndTime)
+	if r.Summary != nil {
+		scan.CriticalCount = r.Summary.Summary["Critical"]
+		scan.HighCount = r.Summary.Summary["High"]
+		scan.MediumCount = r.Summary.Summary["Medium"]
+		scan.LowCount = r.Summary.Summary["Low"]
+	}
+	return scan, nil
+}
+
+// StopScan stops a running scan of an artifact
+func (c *HarborClient) StopScan(ctx context.Context, projectID, repoName, reference string) error {
+	if err := requireArtifact(projectID, repoName, reference); err != nil {
+		return err
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Stopping Harbor artifact scan", "projectId", projectID, "repo", repoName, "reference", reference)
+
+	_, err := v2Client.Scan.StopScanArtifact(ctx, &sdkscan.StopScanArtifactParams{
+		ProjectName:    projectID,
+		RepositoryName: encodeRepositoryName(repoName),
+		Reference:      reference,
+		ScanType:       &sdkmodels.ScanType{ScanType: "vulnerability"},
+		Context:        ctx,
+	})
+	return errors.Wrap(err, "failed to stop artifact scan")
+}
+
+func requireArtifact(projectID, repoName, reference string) error {
+	switch {
+	case projectID == "":
+		return errors.New("project ID is required")
+	case repoName == "":
+		return errors.New("repository name is required")
+	case reference == "":
+		return errors.New("reference is required")
+	}
+	return nil
+}
+
+// eachArtifact calls fn with the repository name, without the project
+// prefix, and scan overview of every artifact of a project.
+func (c *HarborClient) eachArtifact(ctx context.Context, projectName string, fn func(repo string, a *sdkmodels.Artifact) error) error {
+	if projectName == "" {
+		return errors.New("project name is required")
+	}
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	pageSize := int64(scanPageSize)
+	withScan := true
+	for page := int64(1); ; page++ {
+		repos, err := v2Client.Repository.ListRepositories(ctx, &sdkrepository.ListRepositoriesParams{
+			ProjectName: projectName,
+			Page:        &page,
+			PageSize:    &pageSize,
+			Context:     ctx,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to list repositories")
+		}
+		for _, r := range repos.Payload {
+			// Repository names are returned with the project prefix.
+			name := strings.TrimPrefix(r.Name, projectName+"/")
+			for apage := int64(1); ; apage++ {
+				arts, err := v2Client.Artifact.ListArtifacts(ctx, &sdkartifact.ListArtifactsParams{
+					ProjectName:      projectName,
+					RepositoryName:   encodeRepositoryName(name),
+					Page:             &apage,
+					PageSize:         &pageSize,
+					WithScanOverview: &withScan,
+					Context:          ctx,
+				})
+				if err != nil {
+					return errors.Wrapf(err, "failed to list artifacts of repository %s", r.Name)
+				}
+				for _, a := range arts.Payload {
+					if err := fn(name, a); err != nil {
+						return err
+					}
+				}
+				if len(arts.Payload) < scanPageSize {
+					break
+				}
+			}
+		}
+		if len(repos.Payload) < scanPageSize {
+			return nil
+		}
+	}
+}
+
+// ScanProject triggers a vulnerability scan of every artifact of a project
+// that is not already being scanned, and returns how many it triggered.
+// Harbor has no scan of one project, so each artifact is scanned on its own.
+func (c *HarborClient) ScanProject(ctx context.Context, projectName string) (int64, error) {
+	c.logger.Info("Triggering scans of Harbor project artifacts", "project", projectName)
+
+	var n int64
+	err := c.eachArtifact(ctx, projectName, func(repo string, a *sdkmodels.Artifact) error {
+		if r, ok := scanReport(a.ScanOverview); ok && scanOngoing(r.ScanStatus) {
+			return nil
+		}
+		if err := c.TriggerScan(ctx, projectName, repo, a.Digest); err != nil {
+			return errors.Wrapf(err, "artifact %s@%s", repo, a.Digest)
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// StopProjectScans stops the ongoing scans of a project's artifacts, and
+// returns how many it stopped.
+func (c *HarborClient) StopProjectScans(ctx context.Context, projectName string) (int64, error) {
+	c.logger.Info("Stopping scans of Harbor project artifacts", "project", projectName)
+
+	var n int64
+	err := c.eachArtifact(ctx, projectName, func(repo string, a *sdkmodels.Artifact) error {
+		if r, ok := scanReport(a.ScanOverview); !ok || !scanOngoing(r.ScanStatus) {
+			return nil
+		}
+		if err := c.StopScan(ctx, projectName, repo, a.Digest); err != nil {
+			return errors.Wrapf(err, "artifact %s@%s", repo, a.Digest)
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// GetProjectScanMetrics counts a project's artifacts by the status of their
+// latest scan. Artifacts that have never been scanned are not completed.
+func (c *HarborClient) GetProjectScanMetrics(ctx context.Context, projectName string) (*ScanMetrics, error) {
+	m := &ScanMetrics{Statuses: map[string]int64{}}
+	err := c.eachArtifact(ctx, projectName, func(_ string, a *sdkmodels.Artifact) error {
+		m.Total++
+		r, ok := scanReport(a.ScanOverview)
+		if !ok || r.ScanStatus == "" {
+			return nil
+		}
+		m.Statuses[r.ScanStatus]++
+		if scanOngoing(r.ScanStatus) {
+			m.Ongoing = true
+		} else {
+			m.Completed++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TriggerScanAll starts Harbor's scan of all artifacts. Harbor refuses to
+// start one while another is ongoing.
+func (c *HarborClient) TriggerScanAll(ctx context.Context) error {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Triggering Harbor scan of all artifacts")
+
+	_, err := v2Client.ScanAll.CreateScanAllSchedule(ctx, &sdkscanall.CreateScanAllScheduleParams{
+		Schedule: &sdkmodels.Schedule{Schedule: &sdkmodels.ScheduleObj{Type: sdkmodels.ScheduleObjTypeManual}},
+		Context:  ctx,
+	})
+	return errors.Wrap(err, "failed to trigger scan of all artifacts")
+}
+
+// StopScanAll stops Harbor's ongoing scan of all artifacts.
+func (c *HarborClient) StopScanAll(ctx context.Context) error {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	c.logger.Info("Stopping Harbor scan of all artifacts")
+
+	_, err := v2Client.ScanAll.StopScanAll(ctx, &sdkscanall.StopScanAllParams{Context: ctx})
+	return errors.Wrap(err, "failed to stop scan of all artifacts")
+}
+
+// GetScanAllMetrics returns the progress of Harbor's latest scan of all
+// artifacts, whether triggered by hand or by its schedule.
+func (c *HarborClient) GetScanAllMetrics(ctx context.Context) (*ScanMetrics, error) {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	resp, err := v2Client.ScanAll.GetLatestScanAllMetrics(ctx, &sdkscanall.GetLatestScanAllMetricsParams{Context: ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get scan all metrics")
+	}
+	m := &ScanMetrics{Statuses: map[string]int64{}}
+	if s := resp.Payload; s != nil {
+		m.Ongoing = s.Ongoing
+		m.Total = s.Total
+		m.Completed = s.Completed
+		m.Trigger = s.Trigger
+		for k, v := range s.Metrics {
+			m.Statuses[k] = v
+		}
+	}
+	return m, nil
+}
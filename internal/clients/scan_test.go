@@ -0,0 +1,185 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const reportMIME = "application/vnd.security.vulnerability.report; version=1.1"
+
+func TestGetScan(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/library/repositories/team%252Fweb/artifacts/latest", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("with_scan_overview") != "true" {
+			t.Error("artifact requested without its scan overview")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"digest": "sha256:1",
+			"scan_overview": map[string]interface{}{
+				reportMIME: map[string]interface{}{
+					"report_id":   "r1",
+					"scan_status": "Success",
+					"start_time":  "2026-10-01T10:00:00Z",
+					"end_time":    "2026-10-01T10:01:00Z",
+					"summary":     map[string]interface{}{"total": 3, "summary": map[string]int64{"Critical": 1, "Low": 2}},
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/api/v2.0/projects/library/repositories/new/artifacts/latest", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"digest": "sha256:2"}`))
+	})
+	c := executionsClient(t, mux)
+	ctx := context.Background()
+
+	got, err := c.GetScan(ctx, "library", "team/web", "latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "r1" || got.Status != ScanStatusSuccess || got.CriticalCount != 1 || got.LowCount != 2 || got.EndTime.IsZero() {
+		t.Errorf("GetScan() = %+v", got)
+	}
+
+	got, err = c.GetScan(ctx, "library", "new", "latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != "" {
+		t.Errorf("GetScan() of an unscanned artifact = %+v, want no status", got)
+	}
+}
+
+// projectScanAPI serves a project with one repository whose artifacts have
+// the scan statuses given, keyed by digest.
+func projectScanAPI(t *testing.T, statuses map[string]string) (*HarborClient, *[]string) {
+	t.Helper()
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/library/repositories", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name": "library/web"}]`))
+	})
+	mux.HandleFunc("/api/v2.0/projects/library/repositories/web/artifacts", func(w http.ResponseWriter, _ *http.Request) {
+		arts := []map[string]interface{}{}
+		for digest, status := range statuses {
+			a := map[string]interface{}{"digest": digest}
+			if status != "" {
+				a["scan_overview"] = map[string]interface{}{reportMIME: map[string]string{"scan_status": status}}
+			}
+			arts = append(arts, a)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(arts)
+	})
+	mux.HandleFunc("/api/v2.0/projects/library/repositories/web/artifacts/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v2.0/projects/library/repositories/web/artifacts/")
+		calls = append(calls, r.Method+" "+path)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	return executionsClient(t, mux), &calls
+}
+
+func TestScanProject(t *testing.T) {
+	c, calls := projectScanAPI(t, map[string]string{
+		"sha256:new":     "",
+		"sha256:done":    ScanStatusSuccess,
+		"sha256:running": ScanStatusRunning,
+	})
+	ctx := context.Background()
+
+	n, err := c.ScanProject(ctx, "library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(*calls)
+	want := []string{"POST sha256:done/scan", "POST sha256:new/scan"}
+	if n != 2 || !reflect.DeepEqual(*calls, want) {
+		t.Errorf("ScanProject() = %d, calls %v, want 2, %v", n, *calls, want)
+	}
+
+	*calls = nil
+	n, err = c.StopProjectScans(ctx, "library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"POST sha256:running/scan/stop"}
+	if n != 1 || !reflect.DeepEqual(*calls, want) {
+		t.Errorf("StopProjectScans() = %d, calls %v, want 1, %v", n, *calls, want)
+	}
+
+	m, err := c.GetProjectScanMetrics(ctx, "library")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMetrics := &ScanMetrics{
+		Ongoing:   true,
+		Total:     3,
+		Completed: 1,
+		Statuses:  map[string]int64{ScanStatusSuccess: 1, ScanStatusRunning: 1},
+	}
+	if !reflect.DeepEqual(m, wantMetrics) {
+		t.Errorf("GetProjectScanMetrics() = %+v, want %+v", m, wantMetrics)
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	var sent []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/system/scanAll/schedule", func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Schedule struct {
+				Type string `json:"type"`
+			} `json:"schedule"`
+		}{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		sent = append(sent, r.Method+" "+body.Schedule.Type)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/api/v2.0/system/scanAll/stop", func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method+" stop")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/api/v2.0/scans/all/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ongoing": true, "total": 40, "completed": 12, "trigger": "Manual", "metrics": {"Success": 12, "Running": 28}}`))
+	})
+	c := executionsClient(t, mux)
+	ctx := context.Background()
+
+	if err := c.TriggerScanAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StopScanAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"POST Manual", "POST stop"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("requests = %v, want %v", sent, want)
+	}
+
+	m, err := c.GetScanAllMetrics(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMetrics := &ScanMetrics{
+		Ongoing:   true,
+		Total:     40,
+		Completed: 12,
+		Statuses:  map[string]int64{"Success": 12, "Running": 28},
+		Trigger:   "Manual",
+	}
+	if !reflect.DeepEqual(m, wantMetrics) {
+		t.Errorf("GetScanAllMetrics() = %+v, want %+v", m, wantMetrics)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetDefaultScanner(t *testing.T) {
+	var method string
+	var body map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/scanners/uuid-1", func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefaultScanner(context.Background(), "uuid-1"); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPatch || body["is_default"] != true {
+		t.Errorf("SetDefaultScanner() sent %s %v, want PATCH is_default=true", method, body)
+	}
+}
+
+func TestProjectScanner(t *testing.T) {
+	var set map[string]interface{}
+	var isName string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/team-a/scanner", func(w http.ResponseWriter, r *http.Request) {
+		isName = r.Header.Get("X-Is-Resource-Name")
+		if r.Method == http.MethodPut {
+			_ = json.NewDecoder(r.Body).Decode(&set)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"uuid":"uuid-trivy","name":"Trivy","url":"http://trivy:8080","is_default":true}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.GetProjectScanner(context.Background(), "team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UUID != "uuid-trivy" || got.Name != "Trivy" || !got.IsDefault || isName != "true" {
+		t.Errorf("GetProjectScanner() = %+v (X-Is-Resource-Name %q)", got, isName)
+	}
+
+	if err := c.SetProjectScanner(context.Background(), "team-a", "uuid-clair"); err != nil {
+		t.Fatal(err)
+	}
+	if set["uuid"] != "uuid-clair" {
+		t.Errorf("SetProjectScanner() sent %v, want uuid uuid-clair", set)
+	}
+}
+
+func TestGetScannerMetadata(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/scanners/uuid-trivy/metadata", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"scanner": {"name": "Trivy", "vendor": "Aqua Security", "version": "v0.50.1"},
+			"capabilities": [
+				{"type": "vulnerability",
+				 "consumes_mime_types": ["application/vnd.oci.image.manifest.v1+json"],
+				 "produces_mime_types": ["application/vnd.security.vulnerability.report; version=1.1"]},
+				{"type": "sbom",
+				 "consumes_mime_types": ["application/vnd.oci.image.manifest.v1+json"],
+				 "produces_mime_types": ["application/vnd.security.sbom.report+json; version=1.0"]}
+			]
+		}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.GetScannerMetadata(context.Background(), "uuid-trivy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Adapter != "Trivy" || got.Vendor != "Aqua Security" || got.Version != "v0.50.1" {
+		t.Errorf("GetScannerMetadata() scanner = %s %s %s", got.Adapter, got.Vendor, got.Version)
+	}
+	if len(got.Capabilities) != 2 || got.Capabilities[1].Type != ScannerCapabilitySBOM {
+		t.Errorf("GetScannerMetadata() capabilities = %+v", got.Capabilities)
+	}
+	if !got.SupportsSBOM() {
+		t.Error("SupportsSBOM() = false, want true")
+	}
+}
+
+func TestScannerMetadataSupportsSBOM(t *testing.T) {
+	cases := map[string]struct {
+		caps []ScannerCapability
+		want bool
+	}{
+		"VulnerabilityOnly": {
+			caps: []ScannerCapability{{Type: ScannerCapabilityVulnerability, ProducesMimeTypes: []string{"application/vnd.security.vulnerability.report; version=1.1"}}},
+			want: false,
+		},
+		"SBOMType": {
+			caps: []ScannerCapability{{Type: ScannerCapabilitySBOM}},
+			want: true,
+		},
+		"SBOMMimeType": {
+			caps: []ScannerCapability{{Type: ScannerCapabilityVulnerability, ProducesMimeTypes: []string{"application/vnd.security.sbom.report+json; version=1.0"}}},
+			want: true,
+		},
+		"None": {
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			md := &ScannerMetadata{Capabilities: tc.caps}
+			if got := md.SupportsSBOM(); got != tc.want {
+				t.Errorf("SupportsSBOM() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"strings"
+
+	sdkscanner "github.com/goharbor/go-client/pkg/sdk/v2.0/client/scanner"
+	"github.com/pkg/errors"
+)
+
+// Scanner capability types declared by scanner adapters.
+const (
+	ScannerCapabilityVulnerability = "vulnerability"
+	ScannerCapabilitySBOM          = "sbom"
+)
+
+// sbomMimeTypeMarker appears in the MIME types of SBOM reports, such as
+// application/vnd.security.sbom.report+json; version=1.0.
+const sbomMimeTypeMarker = "sbom"
+
+// ScannerCapability is one kind of scan a scanner adapter can run.
+type ScannerCapability struct {
+	Type              string
+	ConsumesMimeTypes []string
+	ProducesMimeTypes []string
+}
+
+// ScannerMetadata is what a scanner adapter declares about itself.
+type ScannerMetadata struct {
+	Adapter      string
+	Vendor       string
+	Version      string
+	Capabilities []ScannerCapability
+}
+
+// SupportsSBOM reports whether the adapter can generate SBOMs. Adapters
+// written before Harbor had an sbom capability type declare it only through
+// the MIME types they produce.
+func (m *ScannerMetadata) SupportsSBOM() bool {
+	for _, c := range m.Capabilities {
+		if c.Type == ScannerCapabilitySBOM {
+			return true
+		}
+		for _, t := range c.ProducesMimeTypes {
+			if strings.Contains(t, sbomMimeTypeMarker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetScannerMetadata returns the metadata of the scanner registration with
+// the given UUID. Harbor fetches it from the adapter, so this fails when the
+// adapter is unreachable.
+func (c *HarborClient) GetScannerMetadata(ctx context.Context, scannerID string) (*ScannerMetadata, error) {
+	if scannerID == "" {
+		return nil, errors.New("scanner ID is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	resp, err := v2Client.Scanner.GetScannerMetadata(ctx, &sdkscanner.GetScannerMetadataParams{
+		RegistrationID: scannerID,
+		Context:        ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get metadata of scanner %s", scannerID)
+	}
+
+	md := &ScannerMetadata{}
+	if s := resp.Payload.Scanner; s != nil {
+		md.Adapter = s.Name
+		md.Vendor = s.Vendor
+		md.Version = s.Version
+	}
+	for _, sc := range resp.Payload.Capabilities {
+		if sc == nil {
+			continue
+		}
+		md.Capabilities = append(md.Capabilities, ScannerCapability{
+			Type:              sc.Type,
+			ConsumesMimeTypes: sc.ConsumesMimeTypes,
+			ProducesMimeTypes: sc.ProducesMimeTypes,
+		})
+	}
+	return md, nil
+}
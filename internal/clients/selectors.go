@@ -0,0 +1,42 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"sort"
+	"strings"
+)
+
+// Retention and immutable tag rules select repositories and tags by
+// doublestar patterns, keyed and decorated the same way.
+const (
+	selectorKind          = "doublestar"
+	selectorRepositoryKey = "repository"
+)
+
+// Selector decorations, which say whether a pattern selects the
+// repositories or tags it matches or those it does not.
+const (
+	selectorRepoMatches  = "repoMatches"
+	selectorRepoExcludes = "repoExcludes"
+	selectorTagMatches   = "matches"
+	selectorTagExcludes  = "excludes"
+)
+
+// SelectorPattern returns the doublestar pattern matching any of patterns,
+// or every repository or tag when there are none. Harbor selects by one
+// pattern, so several are combined into one alternation, sorted so that
+// reordering them is not a change.
+func SelectorPattern(patterns []string) string {
+	switch len(patterns) {
+	case 0:
+		return "**"
+	case 1:
+		return patterns[0]
+	}
+	sorted := append([]string{}, patterns...)
+	sort.Strings(sorted)
+	return "{" + strings.Join(sorted, ",") + "}"
+}
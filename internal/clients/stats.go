@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"sort"
+)
+
+// ClientStats describe the state every Harbor client shares.
+type ClientStats struct {
+	// SystemCacheEntries is how many Harbor endpoint and account pairs have
+	// system info, configurations or permissions cached.
+	SystemCacheEntries int `json:"systemCacheEntries"`
+
+	// CachedClients is how many ProviderConfigs have a Harbor client cached.
+	CachedClients int `json:"cachedClients"`
+
+	// UnavailableEndpoints are the Harbor endpoints that requests are held
+	// back from after a 503 response, sorted.
+	UnavailableEndpoints []string `json:"unavailableEndpoints,omitempty"`
+}
+
+// Stats returns the state every Harbor client shares.
+func Stats() ClientStats {
+	s := ClientStats{}
+
+	sharedSystemCache.mu.Lock()
+	s.SystemCacheEntries = len(sharedSystemCache.entries)
+	sharedSystemCache.mu.Unlock()
+
+	s.CachedClients = sharedClientCache.len()
+
+	breakers.mu.Lock()
+	now := breakers.now()
+	for endpoint, b := range breakers.by {
+		if now.Before(b.retryAt) {
+			s.UnavailableEndpoints = append(s.UnavailableEndpoints, endpoint)
+		}
+	}
+	breakers.mu.Unlock()
+	sort.Strings(s.UnavailableEndpoints)
+
+	return s
+}
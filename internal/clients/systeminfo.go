@@ -0,0 +1,266 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	sdkconfigure "github.com/goharbor/go-client/pkg/sdk/v2.0/client/configure"
+	sdksysteminfo "github.com/goharbor/go-client/pkg/sdk/v2.0/client/systeminfo"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// DefaultSystemCacheMaxAge is how long system info and configurations are
+// served from cache before Harbor is asked again.
+const DefaultSystemCacheMaxAge = 5 * time.Minute
+
+// SystemInfo is the subset of Harbor's /systeminfo the provider relies on
+type SystemInfo struct {
+	HarborVersion              string
+	AuthMode                   string
+	ExternalURL                string
+	ProjectCreationRestriction string
+	OIDCProviderName           string
+	BannerMessage              string
+	ReadOnly                   bool
+	NotificationEnabled        bool
+}
+
+// Configurations maps Harbor configuration keys, as named by the
+// /configurations API, to their current values
+type Configurations map[string]interface{}
+
+// systemCache holds system info and configurations per Harbor endpoint and
+// account. It is shared by every client so that controllers reconciling
+// against the same Harbor do not each fetch these on every reconcile.
+type systemCache struct {
+	mu      sync.Mutex
+	maxAge  time.Duration
+	now     func() time.Time
+	entries map[string]*systemCacheEntry
+}
+
+type systemCacheEntry struct {
+	info     *SystemInfo
+	infoAt   time.Time
+	config   Configurations
+	configAt time.Time
+	perms    *RobotPermissions
+	permsAt  time.Time
+}
+
+var sharedSystemCache = newSystemCache(DefaultSystemCacheMaxAge)
+
+func newSystemCache(maxAge time.Duration) *systemCache {
+	return &systemCache{
+		maxAge:  maxAge,
+		now:     time.Now,
+		entries: map[string]*systemCacheEntry{},
+	}
+}
+
+// SetSystemCacheMaxAge changes how long cached system info and configurations
+// stay fresh. A zero or negative age disables caching.
+func SetSystemCacheMaxAge(d time.Duration) {
+	sharedSystemCache.mu.Lock()
+	defer sharedSystemCache.mu.Unlock()
+	sharedSystemCache.maxAge = d
+}
+
+func (s *systemCache) fresh(at time.Time) bool {
+	return s.maxAge > 0 && !at.IsZero() && s.now().Sub(at) < s.maxAge
+}
+
+func (s *systemCache) entry(key string) *systemCacheEntry {
+	e, ok := s.entries[key]
+	if !ok {
+		e = &systemCacheEntry{}
+		s.entries[key] = e
+	}
+	return e
+}
+
+func (s *systemCache) getInfo(key string) (*SystemInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entries[key]
+	if e == nil || e.info == nil || !s.fresh(e.infoAt) {
+		return nil, false
+	}
+	info := *e.info
+	return &info, true
+}
+
+func (s *systemCache) putInfo(key string, info *SystemInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *info
+	e := s.entry(key)
+	e.info, e.infoAt = &cp, s.now()
+}
+
+func (s *systemCache) getConfig(key string) (Configurations, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entries[key]
+	if e == nil || e.config == nil || !s.fresh(e.configAt) {
+		return nil, false
+	}
+	return copyConfigurations(e.config), true
+}
+
+func (s *systemCache) putConfig(key string, cfg Configurations) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entry(key)
+	e.config, e.configAt = copyConfigurations(cfg), s.now()
+}
+
+func (s *systemCache) getPermissions(key string) (*RobotPermissions, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entries[key]
+	if e == nil || e.perms == nil || !s.fresh(e.permsAt) {
+		return nil, false
+	}
+	return e.perms, true
+}
+
+func (s *systemCache) putPermissions(key string, p *RobotPermissions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.entry(key)
+	e.perms, e.permsAt = p, s.now()
+}
+
+func (s *systemCache) invalidate(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+func copyConfigurations(cfg Configurations) Configurations {
+	cp := make(Configurations, len(cfg))
+	for k, v := range cfg {
+		cp[k] = v
+	}
+	return cp
+}
+
+// systemCacheKey identifies the Harbor endpoint and account a client talks
+// to. Configurations visible to an admin differ from those of other users.
+// Clients authenticating with a token are keyed by a hash of it.
+func (c *HarborClient) systemCacheKey() string {
+	return c.config.URL + "|" + c.account
+}
+
+// GetSystemInfo returns Harbor's system info, served from cache while fresh
+func (c *HarborClient) GetSystemInfo(ctx context.Context) (*SystemInfo, error) {
+	key := c.systemCacheKey()
+	if info, ok := c.systemCache.getInfo(key); ok {
+		return info, nil
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	resp, err := v2Client.Systeminfo.GetSystemInfo(ctx, &sdksysteminfo.GetSystemInfoParams{Context: ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get system info")
+	}
+
+	p := resp.Payload
+	info := &SystemInfo{
+		HarborVersion:              getStringValue(p.HarborVersion),
+		AuthMode:                   getStringValue(p.AuthMode),
+		ExternalURL:                getStringValue(p.ExternalURL),
+		ProjectCreationRestriction: getStringValue(p.ProjectCreationRestriction),
+		OIDCProviderName:           getStringValue(p.OIDCProviderName),
+		BannerMessage:              getStringValue(p.BannerMessage),
+		ReadOnly:                   p.ReadOnly != nil && *p.ReadOnly,
+		NotificationEnabled:        p.NotificationEnable != nil && *p.NotificationEnable,
+	}
+	c.systemCache.putInfo(key, info)
+	return info, nil
+}
+
+// GetConfigurations returns Harbor's system configurations, served from cache
+// while fresh. Reading them requires a system admin account.
+func (c *HarborClient) GetConfigurations(ctx context.Context) (Configurations, error) {
+	key := c.systemCacheKey()
+	if cfg, ok := c.systemCache.getConfig(key); ok {
+		return cfg, nil
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return nil, errors.New("failed to get Harbor v2 client")
+	}
+
+	resp, err := v2Client.Configure.GetConfigurations(ctx, &sdkconfigure.GetConfigurationsParams{Context: ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get configurations")
+	}
+
+	// Each item in the response is an {editable, value} pair; only the value
+	// is of interest.
+	raw, err := json.Marshal(resp.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode configurations")
+	}
+	items := map[string]struct {
+		Value interface{} `json:"value"`
+	}{}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, errors.Wrap(err, "failed to decode configurations")
+	}
+	cfg := make(Configurations, len(items))
+	for k, item := range items {
+		cfg[k] = item.Value
+	}
+
+	c.systemCache.putConfig(key, cfg)
+	return copyConfigurations(cfg), nil
+}
+
+// UpdateConfigurations writes the given configuration keys to Harbor and
+// drops the cached system info and configurations, both of which reflect them
+func (c *HarborClient) UpdateConfigurations(ctx context.Context, cfg Configurations) error {
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return errors.New("failed to get Harbor v2 client")
+	}
+
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode configurations")
+	}
+	body := &sdkmodels.Configurations{}
+	if err := json.Unmarshal(raw, body); err != nil {
+		return errors.Wrap(err, "failed to decode configurations")
+	}
+
+	_, err = v2Client.Configure.UpdateConfigurations(ctx, &sdkconfigure.UpdateConfigurationsParams{
+		Configurations: body,
+		Context:        ctx,
+	})
+	c.InvalidateSystemCache()
+	if err != nil {
+		return errors.Wrap(err, "failed to update configurations")
+	}
+	return nil
+}
+
+// InvalidateSystemCache drops cached system info and configurations for the
+// Harbor endpoint and account this client uses
+func (c *HarborClient) InvalidateSystemCache() {
+	c.systemCache.invalidate(c.systemCacheKey())
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeSystemAPI struct {
+	infoHits   int32
+	configHits int32
+	bannerSet  atomic.Value
+}
+
+func (f *fakeSystemAPI) server(t *testing.T) *httptest.Server {
+	f.bannerSet.Store("")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/systeminfo", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		atomic.AddInt32(&f.infoHits, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"harbor_version": "v2.11.0",
+			"auth_mode":      "oidc_auth",
+			"banner_message": f.bannerSet.Load(),
+		})
+	})
+	mux.HandleFunc("/api/v2.0/configurations", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body := map[string]interface{}{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			f.bannerSet.Store(body["banner_message"])
+			return
+		}
+		atomic.AddInt32(&f.configHits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth_mode":      map[string]interface{}{"editable": false, "value": "oidc_auth"},
+			"banner_message": map[string]interface{}{"editable": true, "value": f.bannerSet.Load()},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newCachedTestClient(t *testing.T, url string, cache *systemCache) *HarborClient {
+	t.Helper()
+	c, err := NewHarborClient(&HarborConfig{URL: url, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.systemCache = cache
+	return c
+}
+
+func TestSystemInfoCache(t *testing.T) {
+	api := &fakeSystemAPI{}
+	srv := api.server(t)
+
+	now := time.Now()
+	cache := newSystemCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	// Two clients for the same Harbor share cached results.
+	a := newCachedTestClient(t, srv.URL, cache)
+	b := newCachedTestClient(t, srv.URL, cache)
+	ctx := context.Background()
+
+	for _, c := range []*HarborClient{a, b, a} {
+		info, err := c.GetSystemInfo(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.HarborVersion != "v2.11.0" || info.AuthMode != "oidc_auth" {
+			t.Errorf("GetSystemInfo() = %+v", info)
+		}
+	}
+	if v, err := a.GetVersion(ctx); err != nil || v != "v2.11.0" {
+		t.Errorf("GetVersion() = %q, %v", v, err)
+	}
+	if hits := atomic.LoadInt32(&api.infoHits); hits != 1 {
+		t.Errorf("systeminfo requests = %d, want 1", hits)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := b.GetSystemInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if hits := atomic.LoadInt32(&api.infoHits); hits != 2 {
+		t.Errorf("systeminfo requests after max age = %d, want 2", hits)
+	}
+}
+
+func TestConfigurationsInvalidatedOnUpdate(t *testing.T) {
+	api := &fakeSystemAPI{}
+	srv := api.server(t)
+	c := newCachedTestClient(t, srv.URL, newSystemCache(time.Hour))
+	ctx := context.Background()
+
+	cfg, err := c.GetConfigurations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg["auth_mode"] != "oidc_auth" {
+		t.Errorf("auth_mode = %v, want oidc_auth", cfg["auth_mode"])
+	}
+	// Callers may not corrupt the cache by writing to the returned map.
+	cfg["auth_mode"] = "db_auth"
+	if _, err := c.GetSystemInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.UpdateConfigurations(ctx, Configurations{"banner_message": "maintenance"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = c.GetConfigurations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg["banner_message"] != "maintenance" || cfg["auth_mode"] != "oidc_auth" {
+		t.Errorf("GetConfigurations() after update = %v", cfg)
+	}
+	info, err := c.GetSystemInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.BannerMessage != "maintenance" {
+		t.Errorf("BannerMessage = %q, want maintenance", info.BannerMessage)
+	}
+	if hits := atomic.LoadInt32(&api.configHits); hits != 2 {
+		t.Errorf("configurations requests = %d, want 2", hits)
+	}
+	if hits := atomic.LoadInt32(&api.infoHits); hits != 2 {
+		t.Errorf("systeminfo requests = %d, want 2", hits)
+	}
+}
+
+func TestSystemCacheDisabled(t *testing.T) {
+	api := &fakeSystemAPI{}
+	srv := api.server(t)
+	c := newCachedTestClient(t, srv.URL, newSystemCache(0))
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetSystemInfo(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits := atomic.LoadInt32(&api.infoHits); hits != 2 {
+		t.Errorf("systeminfo requests = %d, want 2", hits)
+	}
+}
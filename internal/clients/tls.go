@@ -0,0 +1,118 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TLS versions a TLSConfig may require.
+const (
+	TLSVersion12 = "1.2"
+	TLSVersion13 = "1.3"
+)
+
+// TLSConfig configures how a Harbor client verifies Harbor's certificate and
+// which client certificate it presents.
+type TLSConfig struct {
+	// CABundle holds PEM CA certificates that replace the system's trusted
+	// CAs.
+	CABundle []byte
+
+	// ClientCertificate and ClientKey are a PEM client certificate and its
+	// private key. Both or neither are set.
+	ClientCertificate []byte
+	ClientKey         []byte
+
+	// MinVersion is TLSVersion12 or TLSVersion13. It is TLSVersion12 when
+	// empty.
+	MinVersion string
+
+	// InsecureSkipVerify overrides HarborConfig.Insecure when set.
+	InsecureSkipVerify *bool
+}
+
+// tlsConfig returns the TLS configuration of c's connections to Harbor.
+func (c *HarborConfig) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.Insecure,
+	}
+	t := c.TLS
+	if t == nil {
+		return cfg, nil
+	}
+
+	switch t.MinVersion {
+	case "", TLSVersion12:
+	case TLSVersion13:
+		cfg.MinVersion = tls.VersionTLS13
+	default:
+		return nil, errors.Errorf("minimum TLS version must be %s or %s, got %q", TLSVersion12, TLSVersion13, t.MinVersion)
+	}
+
+	if t.InsecureSkipVerify != nil {
+		cfg.InsecureSkipVerify = *t.InsecureSkipVerify
+	}
+
+	if len(t.CABundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(t.CABundle) {
+			return nil, errors.New("CA bundle holds no PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if len(t.ClientCertificate) > 0 || len(t.ClientKey) > 0 {
+		cert, err := tls.X509KeyPair(t.ClientCertificate, t.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// TLSFromProviderConfig reads the secrets a ProviderConfig's TLS settings
+// refer to. It returns nil when tc is nil.
+func TLSFromProviderConfig(ctx context.Context, k8sClient client.Client, tc *providerconfigv1beta1.ProviderConfigTLS) (*TLSConfig, error) {
+	if tc == nil {
+		return nil, nil
+	}
+	t := &TLSConfig{
+		MinVersion:         tc.MinVersion,
+		InsecureSkipVerify: tc.InsecureSkipVerify,
+	}
+	for _, ref := range []struct {
+		sel  *xpv1.SecretKeySelector
+		into *[]byte
+		name string
+	}{
+		{tc.CABundleSecretRef, &t.CABundle, "caBundleSecretRef"},
+		{tc.ClientCertSecretRef, &t.ClientCertificate, "clientCertSecretRef"},
+		{tc.ClientKeySecretRef, &t.ClientKey, "clientKeySecretRef"},
+	} {
+		if ref.sel == nil {
+			continue
+		}
+		secret, err := GetCredentialsFromSecret(ctx, k8sClient, ref.sel.SecretReference)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get %s", ref.name)
+		}
+		v, ok := secret.Data[ref.sel.Key]
+		if !ok {
+			return nil, errors.Errorf("%s: secret %s/%s has no key %q", ref.name, ref.sel.Namespace, ref.sel.Name, ref.sel.Key)
+		}
+		*ref.into = v
+	}
+	return t, nil
+}
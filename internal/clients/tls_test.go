@@ -0,0 +1,139 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestTLSVerification(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"username": "admin"}`))
+	}))
+	srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	cases := map[string]struct {
+		insecure bool
+		tls      *TLSConfig
+		wantErr  bool
+	}{
+		"UnknownCA": {
+			wantErr: true,
+		},
+		"CABundle": {
+			tls: &TLSConfig{CABundle: caBundle},
+		},
+		"InsecureSecret": {
+			insecure: true,
+		},
+		"InsecureSkipVerify": {
+			tls: &TLSConfig{InsecureSkipVerify: boolPtr(true)},
+		},
+		"VerifyOverridesInsecureSecret": {
+			insecure: true,
+			tls:      &TLSConfig{InsecureSkipVerify: boolPtr(false)},
+			wantErr:  true,
+		},
+		"MinVersionAboveServer": {
+			tls:     &TLSConfig{CABundle: caBundle, MinVersion: TLSVersion13},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewHarborClient(&HarborConfig{
+				URL: srv.URL, Username: "admin", Password: "Harbor12345",
+				Insecure: tc.insecure, TLS: tc.tls,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = c.GetCurrentUser(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Errorf("GetCurrentUser() error = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSConfigInvalid(t *testing.T) {
+	cases := map[string]struct {
+		tls     *TLSConfig
+		wantErr string
+	}{
+		"MinVersion": {
+			tls:     &TLSConfig{MinVersion: "1.1"},
+			wantErr: `minimum TLS version must be 1.2 or 1.3, got "1.1"`,
+		},
+		"CABundle": {
+			tls:     &TLSConfig{CABundle: []byte("not a certificate")},
+			wantErr: "CA bundle holds no PEM certificates",
+		},
+		"ClientKeyMissing": {
+			tls:     &TLSConfig{ClientCertificate: []byte("cert")},
+			wantErr: "cannot load client certificate",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewHarborClient(&HarborConfig{URL: "https://h", Username: "admin", Password: "p", TLS: tc.tls})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("NewHarborClient() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSFromProviderConfig(t *testing.T) {
+	kube := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "harbor-ca"},
+		Data:       map[string][]byte{"ca.crt": []byte("bundle")},
+	}).Build()
+	ref := func(key string) *xpv1.SecretKeySelector {
+		return &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "harbor-ca"},
+			Key:             key,
+		}
+	}
+	ctx := context.Background()
+
+	got, err := TLSFromProviderConfig(ctx, kube, &providerconfigv1beta1.ProviderConfigTLS{
+		CABundleSecretRef: ref("ca.crt"),
+		MinVersion:        TLSVersion13,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got.CABundle) != "bundle" || got.MinVersion != TLSVersion13 || got.ClientCertificate != nil {
+		t.Errorf("TLSFromProviderConfig() = %+v", got)
+	}
+
+	_, err = TLSFromProviderConfig(ctx, kube, &providerconfigv1beta1.ProviderConfigTLS{CABundleSecretRef: ref("tls.crt")})
+	if err == nil || !strings.Contains(err.Error(), `has no key "tls.crt"`) {
+		t.Errorf("TLSFromProviderConfig() with a missing key error = %v", err)
+	}
+
+	if got, err := TLSFromProviderConfig(ctx, kube, nil); got != nil || err != nil {
+		t.Errorf("TLSFromProviderConfig(nil) = %v, %v, want nil, nil", got, err)
+	}
+}
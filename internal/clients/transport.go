@@ -0,0 +1,43 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"sync"
+
+	httptransport "github.com/go-openapi/runtime/client"
+)
+
+// transportWrapper, when set, wraps the transport of every Harbor client
+// created afterwards.
+var transportWrapper = struct {
+	mu   sync.Mutex
+	wrap func(http.RoundTripper) http.RoundTripper
+}{}
+
+// SetTransportWrapper makes every Harbor client created afterwards send its
+// requests through the RoundTripper wrap returns, such as one that records
+// them to a cassette. A nil wrap removes the wrapper.
+func SetTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) {
+	transportWrapper.mu.Lock()
+	transportWrapper.wrap = wrap
+	transportWrapper.mu.Unlock()
+	sharedClientCache.reset()
+}
+
+// wrapTransport wraps the transport of the client's Harbor API runtime with
+// wrap. It must be called before the client sends its first request.
+func (c *HarborClient) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	rt, ok := c.clientSet.V2().Transport.(*httptransport.Runtime)
+	if !ok {
+		return
+	}
+	next := rt.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	rt.Transport = wrap(next)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/internal/vcr"
+)
+
+// replayClient returns a client that answers requests from the named
+// cassette in testdata/cassettes, and fails the test unless every recorded
+// request is made.
+func replayClient(t *testing.T, cassette string) *HarborClient {
+	t.Helper()
+	interactions, err := vcr.Load(filepath.Join("testdata", "cassettes", cassette))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer := vcr.NewReplayer(interactions)
+	c, err := NewHarborClient(&HarborConfig{URL: "https://harbor.example.com", Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.wrapTransport(func(http.RoundTripper) http.RoundTripper { return replayer })
+	t.Cleanup(func() {
+		if r := replayer.Remaining(); len(r) > 0 {
+			t.Errorf("requests in the cassette were not made: %v", r)
+		}
+	})
+	return c
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("AlreadyLabelledIsNotAnError", func(t *testing.T) {
+		c := replayClient(t, "set_artifact_label_conflict.jsonl")
+		if err := c.SetArtifactLabel(ctx, "library", "app", "v1", 12, true); err != nil {
+			t.Errorf("SetArtifactLabel() = %v, want nil", err)
+		}
+	})
+
+	t.Run("LabelListFailsAfterProjectLookup", func(t *testing.T) {
+		c := replayClient(t, "find_label_partial_failure.jsonl")
+		_, found, err := c.FindLabel(ctx, "severity-gate-exempt", "team-a")
+		if err == nil || found {
+			t.Fatalf("FindLabel() = found %v, error %v, want an error", found, err)
+		}
+		if !strings.Contains(err.Error(), "failed to list labels") {
+			t.Errorf("FindLabel() error = %v", err)
+		}
+	})
+
+	t.Run("DeleteRefusedWhilePolicyRuns", func(t *testing.T) {
+		c := replayClient(t, "delete_raw_precondition_failed.jsonl")
+		err := c.DeleteRaw(ctx, "/projects/team-a/preheat/policies/nightly")
+		if !IsPreconditionFailed(err) {
+			t.Errorf("DeleteRaw() = %v, want a 412 error", err)
+		}
+		if IsConflict(err) || IsNotFound(err) {
+			t.Errorf("DeleteRaw() = %v, reported as another status", err)
+		}
+	})
+}
+
+func TestSetTransportWrapper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPut {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"schedule": map[string]string{"type": "Daily"}})
+	}))
+	t.Cleanup(srv.Close)
+
+	cassette := &bytes.Buffer{}
+	SetTransportWrapper(vcr.NewRecorder(cassette).Wrap)
+	t.Cleanup(func() { SetTransportWrapper(nil) })
+
+	c, err := NewHarborClient(&HarborConfig{URL: srv.URL, Username: "admin", Password: "Harbor12345"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "/system/scanAll/schedule"
+	if err := c.PutRaw(context.Background(), path, []byte(`{"schedule":{"type":"Daily"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRaw(context.Background(), path); err != nil {
+		t.Fatal(err)
+	}
+
+	var interactions []vcr.Interaction
+	for _, line := range strings.Split(strings.TrimSpace(cassette.String()), "\n") {
+		i := vcr.Interaction{}
+		if err := json.Unmarshal([]byte(line), &i); err != nil {
+			t.Fatal(err)
+		}
+		interactions = append(interactions, i)
+	}
+	if len(interactions) != 2 {
+		t.Fatalf("recorded %d interactions, want 2:\n%s", len(interactions), cassette)
+	}
+	if i := interactions[0]; i.Request.Method != http.MethodPut || i.Request.Path != "/api/v2.0"+path {
+		t.Errorf("first interaction is %s %s", i.Request.Method, i.Request.Path)
+	}
+	if strings.Contains(cassette.String(), "Harbor12345") {
+		t.Errorf("the cassette holds the password:\n%s", cassette)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"strings"
+
+	sdkuser "github.com/goharbor/go-client/pkg/sdk/v2.0/client/user"
+	sdkmodels "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+const passwordChars = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GeneratePassword returns a random password that meets Harbor's policy of
+// at least one upper case letter, one lower case letter and one digit. Harbor
+// holds OIDC CLI secrets to the same policy.
+func GeneratePassword() (string, error) {
+	for {
+		b := make([]byte, 20)
+		for i := range b {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(passwordChars))))
+			if err != nil {
+				return "", errors.Wrap(err, "cannot generate password")
+			}
+			b[i] = passwordChars[n.Int64()]
+		}
+		p := string(b)
+		if strings.ContainsAny(p, "abcdefghijkmnopqrstuvwxyz") &&
+			strings.ContainsAny(p, "ABCDEFGHJKLMNPQRSTUVWXYZ") &&
+			strings.ContainsAny(p, "23456789") {
+			return p, nil
+		}
+	}
+}
+
+// RegenerateUserCLISecret sets a new random CLI secret for a user and returns
+// it. Harbor only accepts CLI secrets when it authenticates users with OIDC.
+func (c *HarborClient) RegenerateUserCLISecret(ctx context.Context, username string) (string, error) {
+	if username == "" {
+		return "", errors.New("username is required")
+	}
+
+	v2Client := c.clientSet.V2()
+	if v2Client == nil {
+		return "", errors.New("failed to get Harbor v2 client")
+	}
+
+	q := "username=" + username
+	resp, err := v2Client.User.ListUsers(ctx, &sdkuser.ListUsersParams{Q: &q, Context: ctx})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to look up user %s", username)
+	}
+	var userID int64
+	for _, u := range resp.Payload {
+		if u.Username == username {
+			userID = u.UserID
+			break
+		}
+	}
+	if userID == 0 {
+		return "", errors.Errorf("user %s not found", username)
+	}
+
+	secret, err := GeneratePassword()
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Info("Regenerating Harbor user CLI secret", "username", username)
+
+	_, err = v2Client.User.SetCliSecret(ctx, &sdkuser.SetCliSecretParams{
+		UserID:  userID,
+		Secret:  &sdkmodels.OIDCCliSecretReq{Secret: secret},
+		Context: ctx,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to set CLI secret of user %s", username)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		p, err := GeneratePassword()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p) != 20 ||
+			!strings.ContainsAny(p, "abcdefghijkmnopqrstuvwxyz") ||
+			!strings.ContainsAny(p, "ABCDEFGHJKLMNPQRSTUVWXYZ") ||
+			!strings.ContainsAny(p, "23456789") {
+			t.Fatalf("GeneratePassword() = %q, which breaks Harbor's password policy", p)
+		}
+	}
+}
+
+func TestRegenerateUserCLISecret(t *testing.T) {
+	var set string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch q := r.URL.Query().Get("q"); q {
+		case "username=alice":
+			_, _ = w.Write([]byte(`[{"user_id": 3, "username": "alice2"}, {"user_id": 7, "username": "alice"}]`))
+		case "username=bob":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("users listed with q %q", q)
+		}
+	})
+	mux.HandleFunc("/api/v2.0/users/7/cli_secret", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("CLI secret set with %s", r.Method)
+		}
+		var body struct {
+			Secret string `json:"secret"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		set = body.Secret
+	})
+	c := executionsClient(t, mux)
+
+	got, err := c.RegenerateUserCLISecret(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == "" || got != set {
+		t.Errorf("RegenerateUserCLISecret() = %q, but Harbor was sent %q", got, set)
+	}
+
+	if _, err := c.RegenerateUserCLISecret(context.Background(), "bob"); err == nil {
+		t.Error("RegenerateUserCLISecret() of an unknown user should fail")
+	}
+}
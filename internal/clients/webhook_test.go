@@ -0,0 +1,45 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestListWebhooksPages(t *testing.T) {
+	const total = webhookPageSize + 50
+	var pages []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2.0/projects/3/webhook/policies", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		pages = append(pages, r.URL.Query().Get("page"))
+		var policies []map[string]any
+		for id := (page-1)*size + 1; id <= page*size && id <= total; id++ {
+			policies = append(policies, map[string]any{"id": id, "project_id": 3, "name": "hook-" + strconv.Itoa(id)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(policies)
+	})
+	c := executionsClient(t, mux)
+
+	webhooks, err := c.ListWebhooks(context.Background(), "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(webhooks) != total {
+		t.Fatalf("ListWebhooks() returned %d policies, want %d", len(webhooks), total)
+	}
+	if last := webhooks[total-1]; last.ID != strconv.Itoa(total) || last.Name != "hook-"+strconv.Itoa(total) {
+		t.Errorf("last policy = %+v, want hook-%d from the second page", last, total)
+	}
+	if len(pages) != 2 {
+		t.Errorf("requested pages %v, want 1 and 2", pages)
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package cloudevents publishes the lifecycle of managed resources, such as
+// their creation in Harbor or drift found there, as CloudEvents to an HTTP
+// sink. Events are sent in binary content mode: the attributes are ce-*
+// headers and the body is the event's JSON data.
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// Types of the events published.
+const (
+	TypeCreated       = "io.crossplane.harbor.resource.created"
+	TypeUpdated       = "io.crossplane.harbor.resource.updated"
+	TypeDeleted       = "io.crossplane.harbor.resource.deleted"
+	TypeDriftDetected = "io.crossplane.harbor.resource.drift_detected"
+)
+
+const (
+	// DefaultSource is the source attribute of the events.
+	DefaultSource = "provider-harbor"
+
+	// DefaultQueueSize is how many events may wait to be sent before
+	// further events are dropped.
+	DefaultQueueSize = 1000
+
+	specVersion = "1.0"
+)
+
+// An Event is the lifecycle of one managed resource.
+type Event struct {
+	// Type is one of the Type constants.
+	Type string
+
+	// Subject is the managed resource, as <kind>/<namespace>/<name>.
+	Subject string
+
+	// Time is when it happened.
+	Time time.Time
+
+	// Data is the event's data, which must encode as JSON.
+	Data any
+}
+
+// ResourceData is the data of every event the provider publishes.
+type ResourceData struct {
+	APIVersion   string `json:"apiVersion"`
+	Kind         string `json:"kind"`
+	Namespace    string `json:"namespace,omitempty"`
+	Name         string `json:"name"`
+	UID          string `json:"uid"`
+	Generation   int64  `json:"generation"`
+	ExternalName string `json:"externalName,omitempty"`
+}
+
+// An Emitter sends events to a sink from a queue, so that a slow or
+// unavailable sink does not hold up reconciles. Events that fail to send are
+// logged and counted, not retried.
+type Emitter struct {
+	sink   string
+	source string
+	client *http.Client
+	log    logging.Logger
+	queue  chan Event
+}
+
+// An Option configures an Emitter.
+type Option func(*Emitter)
+
+// WithSource sets the source attribute of the events.
+func WithSource(s string) Option {
+	return func(e *Emitter) {
+		e.source = s
+	}
+}
+
+// WithLogger sets the logger of failed sends.
+func WithLogger(l logging.Logger) Option {
+	return func(e *Emitter) {
+		e.log = l
+	}
+}
+
+// WithHTTPClient sets the client events are sent with.
+func WithHTTPClient(c *http.Client) Option {
+	return func(e *Emitter) {
+		e.client = c
+	}
+}
+
+// WithQueueSize sets how many events may wait to be sent.
+func WithQueueSize(n int) Option {
+	return func(e *Emitter) {
+		e.queue = make(chan Event, n)
+	}
+}
+
+// NewEmitter returns an Emitter that POSTs events to the sink URL once it is
+// started.
+func NewEmitter(sink string, o ...Option) *Emitter {
+	e := &Emitter{
+		sink:   sink,
+		source: DefaultSource,
+		client: &http.Client{Timeout: 10 * time.Second},
+		log:    logging.NewNopLogger(),
+		queue:  make(chan Event, DefaultQueueSize),
+	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
+}
+
+// Emit queues ev to be sent. It never blocks; when the queue is full the
+// event is dropped.
+func (e *Emitter) Emit(ev Event) {
+	select {
+	case e.queue <- ev:
+	default:
+		metrics.CountCloudEvent(ev.Type, metrics.ResultDropped)
+		e.log.Info("Dropped CloudEvent because the queue is full", "type", ev.Type, "subject", ev.Subject)
+	}
+}
+
+// Start sends queued events until ctx is done.
+func (e *Emitter) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev := <-e.queue:
+			if err := e.Send(ctx, ev); err != nil {
+				metrics.CountCloudEvent(ev.Type, metrics.ResultFailed)
+				e.log.Info("Cannot send CloudEvent", "type", ev.Type, "subject", ev.Subject, "error", err)
+				continue
+			}
+			metrics.CountCloudEvent(ev.Type, metrics.ResultSent)
+		}
+	}
+}
+
+// Send sends ev to the sink now.
+func (e *Emitter) Send(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev.Data)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode event data")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.sink, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "cannot create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("ce-specversion", specVersion)
+	req.Header.Set("ce-id", string(uuid.NewUUID()))
+	req.Header.Set("ce-source", e.source)
+	req.Header.Set("ce-type", ev.Type)
+	req.Header.Set("ce-subject", ev.Subject)
+	req.Header.Set("ce-time", ev.Time.UTC().Format(time.RFC3339Nano))
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "cannot send event")
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("sink answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	var got http.Header
+	var data ResourceData
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_ = json.NewDecoder(r.Body).Decode(&data)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+
+	e := NewEmitter(srv.URL, WithSource("/clusters/prod/provider-harbor"))
+	err := e.Send(context.Background(), Event{
+		Type:    TypeCreated,
+		Subject: "Project/harbor-projects/team-a",
+		Time:    time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC),
+		Data:    ResourceData{Kind: "Project", Namespace: "harbor-projects", Name: "team-a", ExternalName: "team-a"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"Ce-Specversion": "1.0",
+		"Ce-Source":      "/clusters/prod/provider-harbor",
+		"Ce-Type":        TypeCreated,
+		"Ce-Subject":     "Project/harbor-projects/team-a",
+		"Ce-Time":        "2024-05-03T12:00:00Z",
+		"Content-Type":   "application/json",
+	}
+	for k, v := range want {
+		if got.Get(k) != v {
+			t.Errorf("header %s = %q, want %q", k, got.Get(k), v)
+		}
+	}
+	if got.Get("Ce-Id") == "" {
+		t.Error("the event has no id")
+	}
+	if data.Name != "team-a" || data.ExternalName != "team-a" {
+		t.Errorf("data = %+v", data)
+	}
+}
+
+func TestSendRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	if err := NewEmitter(srv.URL).Send(context.Background(), Event{Type: TypeDeleted}); err == nil {
+		t.Error("Send() = nil, want the sink's refusal")
+	}
+}
+
+func TestEmitDropsWhenFull(t *testing.T) {
+	e := NewEmitter("http://sink.invalid", WithQueueSize(1))
+	e.Emit(Event{Type: TypeUpdated, Subject: "first"})
+	e.Emit(Event{Type: TypeUpdated, Subject: "second"})
+	if n := len(e.queue); n != 1 {
+		t.Fatalf("queue holds %d events, want 1", n)
+	}
+	if ev := <-e.queue; ev.Subject != "first" {
+		t.Errorf("queued %q, want the first event", ev.Subject)
+	}
+}
+
+func TestStart(t *testing.T) {
+	received := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("ce-type")
+	}))
+	t.Cleanup(srv.Close)
+
+	e := NewEmitter(srv.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Start(ctx) }()
+
+	e.Emit(Event{Type: TypeDriftDetected})
+	select {
+	case got := <-received:
+		if got != TypeDriftDetected {
+			t.Errorf("sent %q, want %q", got, TypeDriftDetected)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the queued event was not sent")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start() = %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package artifactlabel attaches Harbor labels to artifacts.
+package artifactlabel
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/artifact/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotArtifactLabel = "managed resource is not an ArtifactLabel custom resource"
+	errNewClient        = "cannot create new Service"
+	errFindLabel        = "cannot look up label"
+	errNoLabel          = "label %s does not exist"
+	errNoArtifact       = "artifact %s/%s@%s does not exist"
+	errGetArtifact      = "cannot get labels of artifact"
+	errAttach           = "cannot attach label to artifact"
+	errDetach           = "cannot detach label from artifact"
+)
+
+// Setup adds a controller that reconciles ArtifactLabel managed resources
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.ArtifactLabelGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:   mgr.GetClient(),
+			logger: log,
+		})))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(5 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ArtifactLabelGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1beta1.ArtifactLabel{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// Connect produces an ExternalClient by creating a Harbor client
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ArtifactLabel); !ok {
+		return nil, errors.New(errNotArtifactLabel)
+	}
+
+	harborClient, err := clients.NewHarborClientFromProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{service: harborClient, logger: c.logger}, nil
+}
+
+// external attaches a label to the artifact a reference resolves to. The
+// digest it was attached to is recorded, so that the label follows a tag
+// that is moved and is detached from the right artifact on deletion.
+type external struct {
+	service clients.HarborClienter
+	logger  logging.Logger
+}
+
+// labelProject returns the project whose labels hold the label of p, or ""
+// for a global label.
+func labelProject(p v1beta1.ArtifactLabelParameters) string {
+	if p.LabelScope != nil && *p.LabelScope == v1beta1.LabelScopeProject {
+		return p.ProjectName
+	}
+	return ""
+}
+
+// resolve returns the ID of the label of cr, the digest of the artifact ref
+// names and whether the label is attached to it. exists is false when either
+// the label or the artifact does not exist; labelID is 0 when the label does
+// not.
+func (c *external) resolve(ctx context.Context, cr *v1beta1.ArtifactLabel, ref string) (labelID int64, digest string, attached, exists bool, err error) {
+	p := cr.Spec.ForProvider
+	labelID, found, err := c.service.FindLabel(ctx, p.Label, labelProject(p))
+	if err != nil {
+		return 0, "", false, false, errors.Wrap(err, errFindLabel)
+	}
+	if !found {
+		return 0, "", false, false, nil
+	}
+	digest, ids, err := c.service.GetArtifactLabels(ctx, p.ProjectName, p.RepositoryName, ref)
+	if clients.IsNotFound(err) {
+		return labelID, "", false, false, nil
+	}
+	if err != nil {
+		return 0, "", false, false, errors.Wrap(err, errGetArtifact)
+	}
+	for _, id := range ids {
+		if id == labelID {
+			attached = true
+		}
+	}
+	return labelID, digest, attached, true, nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "artifactlabel.observe",
+		tracing.SpanAttrs("ArtifactLabel", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ArtifactLabel)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotArtifactLabel)
+	}
+	p := cr.Spec.ForProvider
+	recorded := cr.Status.AtProvider.Digest
+
+	// A deleted ArtifactLabel detaches the label from the artifact it was
+	// attached to, even if the tag has since moved or been removed.
+	if meta.WasDeleted(cr) {
+		ref := p.Reference
+		if recorded != nil {
+			ref = *recorded
+		}
+		_, _, attached, _, err := c.resolve(ctx, cr, ref)
+		return managed.ExternalObservation{ResourceExists: attached}, err
+	}
+
+	labelID, digest, attached, exists, err := c.resolve(ctx, cr, p.Reference)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if !exists {
+		if labelID == 0 {
+			return managed.ExternalObservation{}, errors.Errorf(errNoLabel, p.Label)
+		}
+		return managed.ExternalObservation{}, errors.Errorf(errNoArtifact, p.ProjectName, p.RepositoryName, p.Reference)
+	}
+
+	cr.Status.AtProvider.LabelID = &labelID
+	if attached && recorded == nil {
+		// The label was attached before this resource was created.
+		recorded = &digest
+		cr.Status.AtProvider.Digest = recorded
+	}
+	if attached {
+		cr.SetConditions(xpv1.Available())
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   recorded != nil,
+		ResourceUpToDate: attached && *recorded == digest,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, err := c.Update(ctx, mg)
+	return managed.ExternalCreation{}, err
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "artifactlabel.update",
+		tracing.SpanAttrs("ArtifactLabel", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ArtifactLabel)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotArtifactLabel)
+	}
+	p := cr.Spec.ForProvider
+
+	labelID, digest, _, exists, err := c.resolve(ctx, cr, p.Reference)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if !exists {
+		return managed.ExternalUpdate{}, errors.Errorf(errNoArtifact, p.ProjectName, p.RepositoryName, p.Reference)
+	}
+
+	// The reference now names another artifact; move the label to it.
+	if old := cr.Status.AtProvider.Digest; old != nil && *old != digest {
+		if err := c.service.SetArtifactLabel(ctx, p.ProjectName, p.RepositoryName, *old, labelID, false); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errDetach)
+		}
+		c.logger.Info("Detached label from previous artifact", "label", p.Label, "digest", *old)
+	}
+	if err := c.service.SetArtifactLabel(ctx, p.ProjectName, p.RepositoryName, digest, labelID, true); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errAttach)
+	}
+
+	cr.Status.AtProvider.LabelID = &labelID
+	cr.Status.AtProvider.Digest = &digest
+	c.logger.Info("Attached label to artifact", "label", p.Label, "repository", p.ProjectName+"/"+p.RepositoryName, "digest", digest)
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	_, span := tracing.StartSpan(ctx, "artifactlabel.delete",
+		tracing.SpanAttrs("ArtifactLabel", tracing.ResourceName(mg), "delete")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ArtifactLabel)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotArtifactLabel)
+	}
+	cr.SetConditions(xpv1.Deleting())
+	p := cr.Spec.ForProvider
+
+	ref := p.Reference
+	if cr.Status.AtProvider.Digest != nil {
+		ref = *cr.Status.AtProvider.Digest
+	}
+	labelID, _, _, exists, err := c.resolve(ctx, cr, ref)
+	if err != nil || !exists {
+		return managed.ExternalDelete{}, err
+	}
+	if err := c.service.SetArtifactLabel(ctx, p.ProjectName, p.RepositoryName, ref, labelID, false); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errDetach)
+	}
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
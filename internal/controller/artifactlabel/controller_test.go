@@ -0,0 +1,181 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package artifactlabel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/rossigee/provider-harbor/apis/artifact/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type notFound struct{}
+
+func (notFound) Error() string        { return "not found" }
+func (notFound) IsCode(code int) bool { return code == 404 }
+
+func ptr[T any](v T) *T { return &v }
+
+func artifactLabel(digest *string) *v1beta1.ArtifactLabel {
+	return &v1beta1.ArtifactLabel{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapp-prod", Namespace: "harbor"},
+		Spec: v1beta1.ArtifactLabelSpec{ForProvider: v1beta1.ArtifactLabelParameters{
+			ProjectName:    "team-a",
+			RepositoryName: "webapp",
+			Reference:      "1.4.2",
+			Label:          "prod-approved",
+		}},
+		Status: v1beta1.ArtifactLabelStatus{AtProvider: v1beta1.ArtifactLabelObservation{Digest: digest}},
+	}
+}
+
+// harbor fakes a Harbor with one label, ID 7, and the artifacts in labels,
+// keyed by digest, with the tag 1.4.2 naming tagged.
+type harbor struct {
+	labelFound bool
+	tagged     string
+	labels     map[string][]int64
+	set        []string
+}
+
+func (h *harbor) client() *harborclients.MockHarborClient {
+	return &harborclients.MockHarborClient{
+		FindLabelFunc: func(_ context.Context, name, project string) (int64, bool, error) {
+			return 7, h.labelFound, nil
+		},
+		GetArtifactLabelsFunc: func(_ context.Context, _, _, ref string) (string, []int64, error) {
+			if ref == "1.4.2" {
+				ref = h.tagged
+			}
+			ids, ok := h.labels[ref]
+			if !ok {
+				return "", nil, notFound{}
+			}
+			return ref, ids, nil
+		},
+		SetArtifactLabelFunc: func(_ context.Context, _, _, ref string, id int64, attached bool) error {
+			if attached {
+				h.set = append(h.set, "+"+ref)
+			} else {
+				h.set = append(h.set, "-"+ref)
+			}
+			return nil
+		},
+	}
+}
+
+func TestObserve(t *testing.T) {
+	cases := map[string]struct {
+		harbor   harbor
+		recorded *string
+		deleted  bool
+		wantErr  bool
+		exists   bool
+		upToDate bool
+	}{
+		"NotAttached": {
+			harbor: harbor{labelFound: true, tagged: "sha256:a", labels: map[string][]int64{"sha256:a": {3}}},
+		},
+		"Attached": {
+			harbor:   harbor{labelFound: true, tagged: "sha256:a", labels: map[string][]int64{"sha256:a": {3, 7}}},
+			recorded: ptr("sha256:a"),
+			exists:   true,
+			upToDate: true,
+		},
+		"AttachedBeforeCreation": {
+			harbor:   harbor{labelFound: true, tagged: "sha256:a", labels: map[string][]int64{"sha256:a": {7}}},
+			exists:   true,
+			upToDate: true,
+		},
+		"DetachedInHarbor": {
+			harbor:   harbor{labelFound: true, tagged: "sha256:a", labels: map[string][]int64{"sha256:a": nil}},
+			recorded: ptr("sha256:a"),
+			exists:   true,
+		},
+		"TagMoved": {
+			harbor:   harbor{labelFound: true, tagged: "sha256:b", labels: map[string][]int64{"sha256:a": {7}, "sha256:b": nil}},
+			recorded: ptr("sha256:a"),
+			exists:   true,
+		},
+		"NoLabel": {
+			harbor:  harbor{tagged: "sha256:a", labels: map[string][]int64{"sha256:a": nil}},
+			wantErr: true,
+		},
+		"NoArtifact": {
+			harbor:  harbor{labelFound: true},
+			wantErr: true,
+		},
+		"DeletedDetachesRecordedDigest": {
+			harbor:   harbor{labelFound: true, tagged: "sha256:b", labels: map[string][]int64{"sha256:a": {7}, "sha256:b": nil}},
+			recorded: ptr("sha256:a"),
+			deleted:  true,
+			exists:   true,
+		},
+		"DeletedArtifactGone": {
+			harbor:   harbor{labelFound: true},
+			recorded: ptr("sha256:a"),
+			deleted:  true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := artifactLabel(tc.recorded)
+			if tc.deleted {
+				now := metav1.Now()
+				cr.SetDeletionTimestamp(&now)
+			}
+			e := &external{service: tc.harbor.client(), logger: logging.NewNopLogger()}
+			obs, err := e.Observe(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if obs.ResourceExists != tc.exists || obs.ResourceUpToDate != tc.upToDate {
+				t.Errorf("Observe() = exists %v, upToDate %v; want %v, %v", obs.ResourceExists, obs.ResourceUpToDate, tc.exists, tc.upToDate)
+			}
+		})
+	}
+}
+
+func TestUpdateMovesLabel(t *testing.T) {
+	h := &harbor{labelFound: true, tagged: "sha256:b", labels: map[string][]int64{"sha256:a": {7}, "sha256:b": nil}}
+	cr := artifactLabel(ptr("sha256:a"))
+	e := &external{service: h.client(), logger: logging.NewNopLogger()}
+
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := h.set; len(got) != 2 || got[0] != "-sha256:a" || got[1] != "+sha256:b" {
+		t.Errorf("Update() labelled %v, want [-sha256:a +sha256:b]", got)
+	}
+	if d := cr.Status.AtProvider.Digest; d == nil || *d != "sha256:b" {
+		t.Errorf("Update() recorded digest %v, want sha256:b", d)
+	}
+}
+
+func TestDeleteDetachesRecordedDigest(t *testing.T) {
+	h := &harbor{labelFound: true, tagged: "sha256:b", labels: map[string][]int64{"sha256:a": {7}, "sha256:b": nil}}
+	e := &external{service: h.client(), logger: logging.NewNopLogger()}
+
+	if _, err := e.Delete(context.Background(), artifactLabel(ptr("sha256:a"))); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got := h.set; len(got) != 1 || got[0] != "-sha256:a" {
+		t.Errorf("Delete() labelled %v, want [-sha256:a]", got)
+	}
+}
+
+func TestLabelProject(t *testing.T) {
+	p := v1beta1.ArtifactLabelParameters{ProjectName: "team-a"}
+	if got := labelProject(p); got != "" {
+		t.Errorf("labelProject() = %q for an unset scope, want global", got)
+	}
+	p.LabelScope = ptr(v1beta1.LabelScopeProject)
+	if got := labelProject(p); got != "team-a" {
+		t.Errorf("labelProject() = %q, want team-a", got)
+	}
+}
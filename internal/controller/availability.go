@@ -0,0 +1,160 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the events recorded on a ProviderConfig as its Harbor instance
+// becomes unavailable and recovers.
+const (
+	reasonHarborUnavailable event.Reason = "HarborUnavailable"
+	reasonHarborAvailable   event.Reason = "HarborAvailable"
+)
+
+// availabilityEvents records one event on a ProviderConfig when its Harbor
+// instance becomes unavailable and one when it recovers, however many
+// managed resources use it.
+type availabilityEvents struct {
+	mu       sync.Mutex
+	kube     client.Reader
+	recorder event.Recorder
+	down     map[string]bool
+}
+
+var sharedAvailability = &availabilityEvents{recorder: event.NewNopRecorder(), down: map[string]bool{}}
+
+// SetAvailabilityEvents makes WithHarborAvailability record its events on
+// ProviderConfigs read with kube. Until it is called no events are recorded.
+func SetAvailabilityEvents(kube client.Reader, r event.Recorder) {
+	sharedAvailability.mu.Lock()
+	defer sharedAvailability.mu.Unlock()
+	sharedAvailability.kube = kube
+	sharedAvailability.recorder = r
+}
+
+// changed records that the Harbor instance of the named ProviderConfig is
+// available or not, and returns whether that is news.
+func (a *availabilityEvents) changed(pc string, down bool) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.down[pc] == down {
+		return false
+	}
+	if down {
+		a.down[pc] = true
+	} else {
+		delete(a.down, pc)
+	}
+	return true
+}
+
+func (a *availabilityEvents) record(ctx context.Context, pc string, e event.Event) {
+	a.mu.Lock()
+	kube, r := a.kube, a.recorder
+	a.mu.Unlock()
+	if kube == nil {
+		return
+	}
+	obj := &apisv1beta1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: pc}, obj); err != nil {
+		return
+	}
+	r.Event(obj, e)
+}
+
+func (a *availabilityEvents) unavailable(ctx context.Context, pc string, err error) {
+	if pc != "" && a.changed(pc, true) {
+		a.record(ctx, pc, event.Warning(reasonHarborUnavailable, errors.Wrap(err, "deferring reconciliation of the managed resources that use this ProviderConfig")))
+	}
+}
+
+func (a *availabilityEvents) available(ctx context.Context, pc string) {
+	if pc != "" && a.changed(pc, false) {
+		a.record(ctx, pc, event.Normal(reasonHarborAvailable, "Harbor is available again; reconciliation of the managed resources that use this ProviderConfig resumed"))
+	}
+}
+
+// providerConfigName returns the name of the ProviderConfig mg uses, or an
+// empty string if it has none.
+func providerConfigName(mg resource.Managed) string {
+	pcr, ok := mg.(interface {
+		GetProviderConfigReference() *xpv1.ProviderConfigReference
+	})
+	if !ok || pcr.GetProviderConfigReference() == nil {
+		return ""
+	}
+	return pcr.GetProviderConfigReference().Name
+}
+
+// WithHarborAvailability wraps c so that a Harbor instance answering 503
+// Service Unavailable, as it does while being upgraded, defers managed
+// resources quietly rather than failing each of them. Observations report
+// such a resource as existing and up to date, so that it is left alone until
+// its next poll, and set its HarborUnavailable condition. The Harbor client
+// refuses further requests to the instance until its backoff passes, and a
+// single event on the ProviderConfig reports the outage and its end.
+func WithHarborAvailability(c managed.ExternalConnector) managed.ExternalConnector {
+	return &availabilityConnector{ExternalConnector: c, now: time.Now}
+}
+
+type availabilityConnector struct {
+	managed.ExternalConnector
+	now func() time.Time
+}
+
+func (c *availabilityConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &availabilityClient{ExternalClient: ext, now: c.now}, nil
+}
+
+type availabilityClient struct {
+	managed.ExternalClient
+	now func() time.Time
+}
+
+func (e *availabilityClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	pc := providerConfigName(mg)
+	if err != nil {
+		// A resource being deleted must still be retried until Harbor can
+		// delete it.
+		if !clients.IsUnavailable(err) || meta.WasDeleted(mg) {
+			return obs, err
+		}
+		retryAt := e.now()
+		var ue *clients.UnavailableError
+		if errors.As(err, &ue) {
+			retryAt = ue.RetryAt
+		}
+		mg.SetConditions(common.HarborUnavailable(retryAt))
+		sharedAvailability.unavailable(ctx, pc, err)
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+	if mg.GetCondition(common.TypeHarborUnavailable).Status == corev1.ConditionTrue {
+		mg.SetConditions(common.HarborAvailable())
+	}
+	sharedAvailability.available(ctx, pc)
+	return obs, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type availabilityEventsRecorded []event.Event
+
+func (r *availabilityEventsRecorded) Event(_ runtime.Object, e event.Event) { *r = append(*r, e) }
+func (r *availabilityEventsRecorded) WithAnnotations(...string) event.Recorder {
+	return r
+}
+
+func TestWithHarborAvailability(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	if err := apisv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).
+		WithObjects(&apisv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "harbor-a"}}).
+		Build()
+	events := &availabilityEventsRecorded{}
+	SetAvailabilityEvents(kube, events)
+	t.Cleanup(func() { SetAvailabilityEvents(nil, event.NewNopRecorder()) })
+
+	retryAt := time.Date(2024, 5, 2, 10, 0, 15, 0, time.UTC)
+	unavailable := errors.Wrap(&clients.UnavailableError{Endpoint: "https://harbor.example.com", RetryAt: retryAt}, "cannot get Harbor project")
+	newProject := func(name string) *projectv1beta1.Project {
+		cr := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+		cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: "harbor-a"}
+		return cr
+	}
+
+	ext := &fakeExternal{err: unavailable}
+	projects := []*projectv1beta1.Project{newProject("team-a"), newProject("team-b")}
+	for _, cr := range projects {
+		e, err := WithHarborAvailability(&fakeConnector{ext: ext}).Connect(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		obs, err := e.Observe(ctx, cr)
+		if err != nil || !obs.ResourceExists || !obs.ResourceUpToDate {
+			t.Errorf("Observe() during maintenance = %+v, %v; want the resource left alone", obs, err)
+		}
+		if c := cr.GetCondition(common.TypeHarborUnavailable); c.Status != corev1.ConditionTrue {
+			t.Errorf("HarborUnavailable = %s during maintenance, want True", c.Status)
+		}
+	}
+	if len(*events) != 1 || (*events)[0].Type != event.TypeWarning || (*events)[0].Reason != reasonHarborUnavailable {
+		t.Errorf("events during maintenance = %+v, want one HarborUnavailable warning", *events)
+	}
+
+	// A resource being deleted is retried until Harbor can delete it.
+	deleted := newProject("team-c")
+	now := metav1.Now()
+	deleted.SetDeletionTimestamp(&now)
+	e, _ := WithHarborAvailability(&fakeConnector{ext: ext}).Connect(ctx, deleted)
+	if _, err := e.Observe(ctx, deleted); err == nil {
+		t.Error("Observe() of a deleted resource during maintenance should fail")
+	}
+
+	// Other errors are not hidden.
+	e, _ = WithHarborAvailability(&fakeConnector{ext: &fakeExternal{err: errors.New("boom")}}).Connect(ctx, projects[0])
+	if _, err := e.Observe(ctx, projects[0]); err == nil {
+		t.Error("Observe() should return errors other than unavailability")
+	}
+
+	ext.err = nil
+	ext.obs = managed.ExternalObservation{ResourceExists: true}
+	for _, cr := range projects {
+		e, _ := WithHarborAvailability(&fakeConnector{ext: ext}).Connect(ctx, cr)
+		obs, err := e.Observe(ctx, cr)
+		if err != nil || obs.ResourceUpToDate {
+			t.Errorf("Observe() after maintenance = %+v, %v; want the real observation", obs, err)
+		}
+		if c := cr.GetCondition(common.TypeHarborUnavailable); c.Status != corev1.ConditionFalse {
+			t.Errorf("HarborUnavailable = %s after maintenance, want False", c.Status)
+		}
+	}
+	if len(*events) != 2 || (*events)[1].Type != event.TypeNormal || (*events)[1].Reason != reasonHarborAvailable {
+		t.Errorf("events after maintenance = %+v, want one HarborAvailable event", *events)
+	}
+}
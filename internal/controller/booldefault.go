@@ -0,0 +1,51 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"strconv"
+)
+
+// A BoolField compares a boolean setting whose default value may appear in
+// more than one form. Harbor leaves settings at their default out of its
+// responses, while the CRDs store the same default explicitly, so an unset
+// observation, false and the default must all compare consistently or the
+// resource is never up to date.
+type BoolField struct {
+	// Default is the value Harbor applies when the setting is left out.
+	Default bool
+}
+
+// Matches reports whether the observed value got matches want. A nil want
+// is not managed and always matches. A nil got is the field's default.
+func (f BoolField) Matches(want, got *bool) bool {
+	if want == nil {
+		return true
+	}
+	return *want == f.value(got)
+}
+
+// MatchesString is Matches for settings Harbor encodes as strings, such as
+// project metadata. An empty got is the field's default, and values are
+// compared as booleans, so "True" matches "true". Values that are not
+// booleans are compared as they are.
+func (f BoolField) MatchesString(want, got string) bool {
+	w, werr := strconv.ParseBool(want)
+	if werr != nil {
+		return want == got
+	}
+	if got == "" {
+		return w == f.Default
+	}
+	g, gerr := strconv.ParseBool(got)
+	return gerr == nil && w == g
+}
+
+func (f BoolField) value(b *bool) bool {
+	if b == nil {
+		return f.Default
+	}
+	return *b
+}
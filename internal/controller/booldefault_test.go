@@ -0,0 +1,56 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import "testing"
+
+func TestBoolFieldMatches(t *testing.T) {
+	yes, no := true, false
+	cases := map[string]struct {
+		field     BoolField
+		want, got *bool
+		match     bool
+	}{
+		"Unmanaged":           {field: BoolField{}, want: nil, got: &yes, match: true},
+		"FalseOmitted":        {field: BoolField{}, want: &no, got: nil, match: true},
+		"TrueOmitted":         {field: BoolField{}, want: &yes, got: nil, match: false},
+		"TrueOmittedDefault":  {field: BoolField{Default: true}, want: &yes, got: nil, match: true},
+		"FalseOmittedDefault": {field: BoolField{Default: true}, want: &no, got: nil, match: false},
+		"Equal":               {field: BoolField{}, want: &yes, got: &yes, match: true},
+		"Different":           {field: BoolField{}, want: &no, got: &yes, match: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.field.Matches(tc.want, tc.got); got != tc.match {
+				t.Errorf("Matches() = %v, want %v", got, tc.match)
+			}
+		})
+	}
+}
+
+func TestBoolFieldMatchesString(t *testing.T) {
+	cases := map[string]struct {
+		field     BoolField
+		want, got string
+		match     bool
+	}{
+		"SameCase":            {want: "false", got: "false", match: true},
+		"OtherCase":           {want: "false", got: "False", match: true},
+		"FalseOmitted":        {want: "false", got: "", match: true},
+		"TrueOmitted":         {want: "true", got: "", match: false},
+		"TrueOmittedDefault":  {field: BoolField{Default: true}, want: "true", got: "", match: true},
+		"FalseOmittedDefault": {field: BoolField{Default: true}, want: "false", got: "", match: false},
+		"Different":           {want: "true", got: "false", match: false},
+		"NotABoolean":         {want: "yes", got: "yes", match: true},
+		"ObservedNotABoolean": {want: "true", got: "yes", match: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.field.MatchesString(tc.want, tc.got); got != tc.match {
+				t.Errorf("MatchesString(%q, %q) = %v, want %v", tc.want, tc.got, got, tc.match)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultCABundleKey is read when a CA bundle source does not name a key. It
+// is where cert-manager writes the issuing CA of a Certificate.
+const DefaultCABundleKey = "ca.crt"
+
+// A CABundleReferrer is a managed resource that may reference a CA bundle.
+type CABundleReferrer interface {
+	GetCABundleSource() *common.CABundleSource
+}
+
+// A CABundle is a parsed PEM CA bundle.
+type CABundle struct {
+	PEM          []byte
+	Fingerprint  string
+	Certificates int
+	NotAfter     time.Time
+}
+
+// Observation returns the status representation of b.
+func (b *CABundle) Observation() *common.CABundleObservation {
+	t := metav1.NewTime(b.NotAfter)
+	return &common.CABundleObservation{Fingerprint: b.Fingerprint, Certificates: b.Certificates, NotAfter: &t}
+}
+
+// Changed reports whether b differs from the bundle recorded in obs.
+func (b *CABundle) Changed(obs *common.CABundleObservation) bool {
+	return obs == nil || obs.Fingerprint != b.Fingerprint
+}
+
+// ParseCABundle parses the certificates in a PEM bundle. Blocks other than
+// certificates are ignored, but at least one certificate is required.
+func ParseCABundle(data []byte) (*CABundle, error) {
+	b := &CABundle{PEM: data}
+	h := sha256.New()
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse CA certificate")
+		}
+		h.Write(cert.Raw)
+		if b.Certificates == 0 || cert.NotAfter.Before(b.NotAfter) {
+			b.NotAfter = cert.NotAfter
+		}
+		b.Certificates++
+	}
+	if b.Certificates == 0 {
+		return nil, errors.New("no PEM certificates found in CA bundle")
+	}
+	b.Fingerprint = hex.EncodeToString(h.Sum(nil))
+	return b, nil
+}
+
+// ResolveCABundle reads and parses the CA bundle src selects from namespace.
+// It returns nil if src is nil.
+func ResolveCABundle(ctx context.Context, kube client.Reader, namespace string, src *common.CABundleSource) (*CABundle, error) {
+	if src == nil {
+		return nil, nil
+	}
+	key := src.Key
+	if key == "" {
+		key = DefaultCABundleKey
+	}
+	nn := types.NamespacedName{Namespace: namespace, Name: src.Name}
+
+	var data []byte
+	switch src.Kind {
+	case common.CABundleKindConfigMap:
+		cm := &corev1.ConfigMap{}
+		if err := kube.Get(ctx, nn, cm); err != nil {
+			return nil, errors.Wrapf(err, "cannot get CA bundle ConfigMap %s", src.Name)
+		}
+		if v, ok := cm.Data[key]; ok {
+			data = []byte(v)
+		} else {
+			data = cm.BinaryData[key]
+		}
+	case common.CABundleKindSecret, "":
+		s := &corev1.Secret{}
+		if err := kube.Get(ctx, nn, s); err != nil {
+			return nil, errors.Wrapf(err, "cannot get CA bundle Secret %s", src.Name)
+		}
+		data = s.Data[key]
+	default:
+		return nil, errors.Errorf("unsupported CA bundle kind %q", src.Kind)
+	}
+	if len(data) == 0 {
+		return nil, errors.Errorf("CA bundle %s %s has no key %q", src.Kind, src.Name, key)
+	}
+
+	b, err := ParseCABundle(data)
+	return b, errors.Wrapf(err, "invalid CA bundle in %s %s", src.Kind, src.Name)
+}
+
+// EnqueueCABundleReferrers returns an event handler for Secrets or ConfigMaps
+// of the given kind. It enqueues the managed resources in the same namespace
+// whose CA bundle source names the changed object, so a renewed CA is picked
+// up without waiting for the next poll. newList must return an empty list of
+// the managed resource kind.
+func EnqueueCABundleReferrers(kube client.Reader, kind string, newList func() client.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		l := newList()
+		if err := kube.List(ctx, l, client.InNamespace(o.GetNamespace())); err != nil {
+			return nil
+		}
+		items, err := meta.ExtractList(l)
+		if err != nil {
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, item := range items {
+			mg, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			r, ok := item.(CABundleReferrer)
+			if !ok {
+				continue
+			}
+			src := r.GetCABundleSource()
+			if src == nil || src.Name != o.GetName() || (src.Kind != kind && !(src.Kind == "" && kind == common.CABundleKindSecret)) {
+				continue
+			}
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.GetName()}})
+		}
+		return reqs
+	})
+}
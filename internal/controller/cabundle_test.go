@@ -0,0 +1,190 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/apis/common"
+	webhookv1beta1 "github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// testCA returns a PEM encoded self-signed CA certificate expiring at notAfter.
+func testCA(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             notAfter.Add(-24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParseCABundle(t *testing.T) {
+	early := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)
+	a, b := testCA(t, late), testCA(t, early)
+
+	got, err := ParseCABundle(append(append([]byte{}, a...), b...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Certificates != 2 || !got.NotAfter.Equal(early) {
+		t.Errorf("ParseCABundle() = %d certs expiring %v, want 2 expiring %v", got.Certificates, got.NotAfter, early)
+	}
+
+	// Whitespace and non-certificate blocks do not change the fingerprint.
+	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("x")})
+	again, err := ParseCABundle(append(append(append([]byte("\n"), a...), key...), b...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Fingerprint != got.Fingerprint {
+		t.Error("fingerprint changed for the same certificates")
+	}
+	if got.Changed(again.Observation()) {
+		t.Error("Changed() = true for the same certificates")
+	}
+
+	renewed, err := ParseCABundle(testCA(t, late))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renewed.Changed(got.Observation()) {
+		t.Error("Changed() = false for a renewed CA")
+	}
+
+	if _, err := ParseCABundle([]byte("not a bundle")); err == nil {
+		t.Error("ParseCABundle() should fail without certificates")
+	}
+}
+
+func TestResolveCABundle(t *testing.T) {
+	ca := testCA(t, time.Now().Add(time.Hour))
+	kube := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "issuer"},
+			Data:       map[string][]byte{"ca.crt": ca, "tls.crt": []byte("junk")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "trust"},
+			Data:       map[string]string{"bundle.pem": string(ca)},
+		},
+	).Build()
+
+	cases := map[string]struct {
+		src     *common.CABundleSource
+		wantNil bool
+		wantErr string
+	}{
+		"NoSource": {
+			wantNil: true,
+		},
+		"SecretDefaultKey": {
+			src: &common.CABundleSource{Name: "issuer"},
+		},
+		"ConfigMap": {
+			src: &common.CABundleSource{Kind: common.CABundleKindConfigMap, Name: "trust", Key: "bundle.pem"},
+		},
+		"MissingKey": {
+			src:     &common.CABundleSource{Kind: common.CABundleKindConfigMap, Name: "trust"},
+			wantErr: `has no key "ca.crt"`,
+		},
+		"MissingSecret": {
+			src:     &common.CABundleSource{Name: "absent"},
+			wantErr: "cannot get CA bundle Secret absent",
+		},
+		"InvalidBundle": {
+			src:     &common.CABundleSource{Kind: common.CABundleKindSecret, Name: "issuer", Key: "tls.crt"},
+			wantErr: "invalid CA bundle in Secret issuer",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveCABundle(context.Background(), kube, "ns", tc.src)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("ResolveCABundle() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == nil) != tc.wantNil {
+				t.Errorf("ResolveCABundle() = %v, want nil %t", got, tc.wantNil)
+			}
+		})
+	}
+}
+
+func TestEnqueueCABundleReferrers(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := webhookv1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	hook := func(ns, name string, src *common.CABundleSource) client.Object {
+		return &webhookv1beta1.Webhook{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       webhookv1beta1.WebhookSpec{ForProvider: webhookv1beta1.WebhookParameters{CABundleRef: src}},
+		}
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		hook("ns", "by-secret", &common.CABundleSource{Name: "issuer"}),
+		hook("ns", "by-configmap", &common.CABundleSource{Kind: common.CABundleKindConfigMap, Name: "issuer"}),
+		hook("ns", "other", &common.CABundleSource{Name: "other"}),
+		hook("ns", "none", nil),
+		hook("elsewhere", "by-secret", &common.CABundleSource{Name: "issuer"}),
+	).Build()
+	newList := func() client.ObjectList { return &webhookv1beta1.WebhookList{} }
+
+	for kind, want := range map[string]string{
+		common.CABundleKindSecret:    "by-secret",
+		common.CABundleKindConfigMap: "by-configmap",
+	} {
+		h := EnqueueCABundleReferrers(kube, kind, newList)
+		q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		o := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "issuer"}}
+		h.Update(context.Background(), event.UpdateEvent{ObjectOld: o, ObjectNew: o}, q)
+		if q.Len() != 1 {
+			t.Errorf("%s: enqueued %d requests, want 1", kind, q.Len())
+			continue
+		}
+		if got, _ := q.Get(); got.Namespace != "ns" || got.Name != want {
+			t.Errorf("%s: enqueued %v, want ns/%s", kind, got, want)
+		}
+		q.ShutDown()
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const errGetCapabilities = "cannot get ProviderConfig capabilities"
+
+// Capabilities returns the name of mg's ProviderConfig and the capabilities
+// last probed for its Harbor account, which are nil until it has been
+// probed.
+func Capabilities(ctx context.Context, kube client.Reader, mg resource.Managed) (string, *apisv1beta1.ProviderConfigCapabilities, error) {
+	name := providerConfigName(mg)
+	if name == "" {
+		return "", nil, nil
+	}
+	pc := &apisv1beta1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+		return "", nil, errors.Wrap(err, errGetCapabilities)
+	}
+	return name, pc.Status.Capabilities, nil
+}
+
+// WithRequiredCapability wraps c so that managed resources whose
+// ProviderConfig's Harbor account was probed and found to lack capability
+// fail to connect with a CapabilityMissing condition, rather than with the
+// 403 Harbor would answer every call with. Resources whose account has not
+// been probed connect as before.
+func WithRequiredCapability(kube client.Reader, capability clients.Capability, c managed.ExternalConnector) managed.ExternalConnector {
+	return &capabilityConnector{ExternalConnector: c, kube: kube, capability: capability}
+}
+
+type capabilityConnector struct {
+	managed.ExternalConnector
+	kube       client.Reader
+	capability clients.Capability
+}
+
+func (c *capabilityConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	pc, caps, err := Capabilities(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	if caps != nil && !clients.HasCapability(caps, c.capability) {
+		cond := common.CapabilityMissing(pc, caps.Account, string(c.capability))
+		mg.SetConditions(cond)
+		return nil, errors.New(cond.Message)
+	}
+	if mg.GetCondition(common.TypeCapabilityMissing).Status == corev1.ConditionTrue {
+		mg.SetConditions(common.CapabilityPresent())
+	}
+	return c.ExternalConnector.Connect(ctx, mg)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWithRequiredCapability(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apisv1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	pc := func(name string, caps *apisv1beta1.ProviderConfigCapabilities) *apisv1beta1.ProviderConfig {
+		return &apisv1beta1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     apisv1beta1.ProviderConfigStatus{Capabilities: caps},
+		}
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		pc("admin", &apisv1beta1.ProviderConfigCapabilities{Account: "admin", SysAdmin: true, ManageSystem: true}),
+		pc("ci", &apisv1beta1.ProviderConfigCapabilities{Account: "ci"}),
+		pc("unprobed", nil),
+	).Build()
+
+	cases := map[string]struct {
+		pc          string
+		missing     bool
+		wantMissing corev1.ConditionStatus
+	}{
+		"Granted":          {pc: "admin", wantMissing: corev1.ConditionUnknown},
+		"Lacking":          {pc: "ci", missing: true, wantMissing: corev1.ConditionTrue},
+		"NotProbed":        {pc: "unprobed", wantMissing: corev1.ConditionUnknown},
+		"GrantedAfterLack": {pc: "admin", wantMissing: corev1.ConditionFalse},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &projectv1beta1.Project{}
+			cr.SetProviderConfigReference(&xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: tc.pc})
+			if tc.wantMissing == corev1.ConditionFalse {
+				cr.SetConditions(common.CapabilityMissing(tc.pc, "ci", "ManageSystem"))
+			}
+
+			_, err := WithRequiredCapability(kube, clients.CapabilityManageSystem, &fakeConnector{ext: &fakeExternal{}}).Connect(context.Background(), cr)
+			if tc.missing != (err != nil) {
+				t.Fatalf("Connect() error = %v, want error %t", err, tc.missing)
+			}
+			if tc.missing && !strings.Contains(err.Error(), "account ci of ProviderConfig ci lacks the ManageSystem capability") {
+				t.Errorf("Connect() error = %v", err)
+			}
+			if got := cr.GetCondition(common.TypeCapabilityMissing).Status; got != tc.wantMissing {
+				t.Errorf("CapabilityMissing status = %s, want %s", got, tc.wantMissing)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SpecChecksumAnnotation records the checksum of spec.forProvider when
+	// it was last written to Harbor.
+	SpecChecksumAnnotation = "harbor.crossplane.io/spec-checksum"
+
+	// ObservedChecksumAnnotation records the checksum of status.atProvider
+	// as observed just before spec.forProvider was last written to Harbor.
+	ObservedChecksumAnnotation = "harbor.crossplane.io/observed-checksum"
+
+	errPatchChecksums = "cannot record spec checksums"
+)
+
+// skipUnchanged is whether WithSpecChecksums skips updates.
+var skipUnchanged atomic.Bool
+
+func init() {
+	skipUnchanged.Store(true)
+}
+
+// SetSkipUnchangedUpdates turns the skipping of updates by WithSpecChecksums
+// on or off. It is on by default.
+func SetSkipUnchangedUpdates(skip bool) {
+	skipUnchanged.Store(skip)
+}
+
+// WithSpecChecksums wraps c so that an update that would write to Harbor
+// exactly what was last written, while Harbor still looks as it did before
+// that write, is skipped. Such an update can only come from a comparison
+// that never settles, such as one Harbor normalizes a value for, and would
+// otherwise be repeated on every poll.
+//
+// After each update the checksums of spec.forProvider and of
+// status.atProvider, as observed before the update, are recorded in the
+// SpecChecksumAnnotation and ObservedChecksumAnnotation annotations. An
+// existing resource that Observe reports as out of date is treated as up to
+// date while both checksums are unchanged. A change to the spec, or to the
+// object in Harbor as status.atProvider shows it, lets the update through;
+// changes to labels, annotations and other metadata do not. Removing either
+// annotation forces the next update.
+//
+// It is only safe for controllers whose updates depend on nothing but
+// spec.forProvider, and whose status.atProvider shows what the update
+// writes, so not for those that also write values read from Secrets.
+func WithSpecChecksums(kube client.Client, c managed.ExternalConnector) managed.ExternalConnector {
+	return &checksumConnector{ExternalConnector: c, kube: kube}
+}
+
+type checksumConnector struct {
+	managed.ExternalConnector
+	kube client.Client
+}
+
+func (c *checksumConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &checksumClient{ExternalClient: ext, kube: c.kube}, nil
+}
+
+type checksumClient struct {
+	managed.ExternalClient
+	kube client.Client
+}
+
+func (e *checksumClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil || !obs.ResourceExists || obs.ResourceUpToDate || meta.WasDeleted(mg) || !skipUnchanged.Load() {
+		return obs, err
+	}
+	spec, observed, err := Checksums(mg)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	a := mg.GetAnnotations()
+	if a[SpecChecksumAnnotation] == spec && a[ObservedChecksumAnnotation] == observed {
+		obs.ResourceUpToDate = true
+	}
+	return obs, nil
+}
+
+func (e *checksumClient) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	spec, observed, err := Checksums(mg)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	u, err := e.ExternalClient.Update(ctx, mg)
+	if err != nil {
+		return u, err
+	}
+	a := mg.GetAnnotations()
+	if a[SpecChecksumAnnotation] == spec && a[ObservedChecksumAnnotation] == observed {
+		return u, nil
+	}
+	return u, errors.Wrap(patchAnnotations(ctx, e.kube, mg, map[string]string{
+		SpecChecksumAnnotation:     spec,
+		ObservedChecksumAnnotation: observed,
+	}), errPatchChecksums)
+}
+
+// Checksums returns the checksums of the spec.forProvider and
+// status.atProvider of mg. Each is the SHA-256 of the field's JSON encoding,
+// whose object keys are sorted, so equal values always have equal checksums.
+func Checksums(mg resource.Managed) (spec, observed string, err error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return "", "", errors.Wrap(err, errPatchChecksums)
+	}
+	sum := func(fields ...string) (string, error) {
+		var v any = u
+		for _, f := range fields {
+			m, _ := v.(map[string]any)
+			v = m[f]
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", errors.Wrap(err, errPatchChecksums)
+		}
+		s := sha256.Sum256(b)
+		return "sha256:" + hex.EncodeToString(s[:]), nil
+	}
+	if spec, err = sum("spec", "forProvider"); err != nil {
+		return "", "", err
+	}
+	observed, err = sum("status", "atProvider")
+	return spec, observed, err
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// neverSettledClient observes the Harbor project with the ID given as out
+// of date, as a comparison that never settles would, and counts updates.
+type neverSettledClient struct {
+	managed.ExternalClient
+	id      string
+	updates int
+}
+
+func (c *neverSettledClient) Observe(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr := mg.(*projectv1beta1.Project)
+	id := c.id
+	cr.Status.AtProvider.ID = &id
+	return managed.ExternalObservation{ResourceExists: true}, nil
+}
+
+func (c *neverSettledClient) Update(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+	c.updates++
+	return managed.ExternalUpdate{}, nil
+}
+
+func TestWithSpecChecksums(t *testing.T) {
+	ctx := context.Background()
+	kube, cr := stored(t, 0)
+	cr.Spec.ForProvider.Name = "web"
+	inner := &neverSettledClient{id: "42"}
+	ext, err := WithSpecChecksums(kube, &fakeConnector{ext: inner}).Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reconcile := func() bool {
+		t.Helper()
+		obs, err := ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatalf("Observe() error = %v", err)
+		}
+		if obs.ResourceUpToDate {
+			return false
+		}
+		if _, err := ext.Update(ctx, cr); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		return true
+	}
+
+	if !reconcile() {
+		t.Fatal("first update skipped")
+	}
+	got := &projectv1beta1.Project{}
+	if err := kube.Get(ctx, client.ObjectKeyFromObject(cr), got); err != nil {
+		t.Fatal(err)
+	}
+	a := got.GetAnnotations()
+	if a[SpecChecksumAnnotation] == "" || a[ObservedChecksumAnnotation] == "" || a["example.org/owner"] != "team-a" {
+		t.Fatalf("annotations = %v, want checksums recorded and others kept", a)
+	}
+
+	if reconcile() {
+		t.Error("update with unchanged checksums not skipped")
+	}
+
+	cr.SetLabels(map[string]string{"team": "a"})
+	if reconcile() {
+		t.Error("update after a metadata change not skipped")
+	}
+
+	inner.id = "43"
+	if !reconcile() {
+		t.Error("update after the Harbor object changed skipped")
+	}
+
+	cr.Spec.ForProvider.Name = "api"
+	if !reconcile() {
+		t.Error("update after a spec change skipped")
+	}
+
+	SetSkipUnchangedUpdates(false)
+	t.Cleanup(func() { SetSkipUnchangedUpdates(true) })
+	if !reconcile() {
+		t.Error("update skipped with skipping turned off")
+	}
+	if inner.updates != 4 {
+		t.Errorf("%d updates, want 4", inner.updates)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package config manages the system settings of a Harbor instance.
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotConfigSystem = "managed resource is not a ConfigSystem custom resource"
+	errNewClient       = "cannot create new Service"
+	errGetConfig       = "cannot get Harbor system configuration"
+	errUpdateConfig    = "cannot update Harbor system configuration"
+)
+
+// Setup adds a controller that reconciles ConfigSystem managed resources
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.ConfigSystemGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), clients.CapabilityManageSystem, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithSpecChecksums(mgr.GetClient(), ctrlutil.WithUpdateDebounce(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:   mgr.GetClient(),
+			logger: log,
+		}))))))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(10 * time.Minute),
+		managed.WithPollIntervalHook(ctrlutil.DebouncedPollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ConfigSystemGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ConfigSystem{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// Connect produces an ExternalClient by creating a Harbor client
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ConfigSystem); !ok {
+		return nil, errors.New(errNotConfigSystem)
+	}
+
+	harborClient, err := clients.NewHarborClientFromProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{service: harborClient, logger: c.logger}, nil
+}
+
+// external applies a ConfigSystem to Harbor's system configuration. The
+// configuration always exists, so it is never created or deleted.
+type external struct {
+	service clients.HarborClienter
+	logger  logging.Logger
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "config.observe",
+		tracing.SpanAttrs("ConfigSystem", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ConfigSystem)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotConfigSystem)
+	}
+
+	// Deleting a ConfigSystem leaves Harbor's settings as they are.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cfg, err := c.service.GetConfigurations(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetConfig)
+	}
+	cr.Status.AtProvider = observe(cfg)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(cr.Spec.ForProvider, cr.Status.AtProvider),
+	}, nil
+}
+
+// Create applies the settings. Observe always reports the configuration as
+// existing, so the reconciler should never call it.
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, err := c.Update(ctx, mg)
+	return managed.ExternalCreation{}, err
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "config.update",
+		tracing.SpanAttrs("ConfigSystem", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ConfigSystem)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotConfigSystem)
+	}
+
+	cfg := desired(cr.Spec.ForProvider)
+	if len(cfg) == 0 {
+		return managed.ExternalUpdate{}, nil
+	}
+	if err := c.service.UpdateConfigurations(ctx, cfg); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateConfig)
+	}
+	if err := ctrlutil.CheckReadBack(cr, cfg, func() (any, error) { return c.service.GetConfigurations(ctx) }); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	c.logger.Info("Updated Harbor system configuration", "name", cr.GetName(), "keys", len(cfg))
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(_ context.Context, _ resource.Managed) (managed.ExternalDelete, error) {
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	"github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestObserve(t *testing.T) {
+	current := harborclients.Configurations{
+		keyTokenExpiration:            float64(30),
+		keyProjectCreationRestriction: v1beta1.ProjectCreationEveryone,
+		keyRobotTokenDuration:         float64(30),
+		keyBannerMessage:              `{"closable":false,"message":"Maintenance on Sunday","type":"warning","fromDate":"","toDate":""}`,
+		keyReadOnly:                   false,
+	}
+
+	cases := map[string]struct {
+		params   v1beta1.ConfigSystemParameters
+		deleted  bool
+		getErr   error
+		wantErr  bool
+		exists   bool
+		upToDate bool
+	}{
+		"NothingManaged": {
+			exists:   true,
+			upToDate: true,
+		},
+		"Matching": {
+			params: v1beta1.ConfigSystemParameters{
+				TokenExpiration:            ptr(int64(30)),
+				ProjectCreationRestriction: ptr(v1beta1.ProjectCreationEveryone),
+				RobotTokenDuration:         ptr(int64(30)),
+				BannerMessage:              &v1beta1.BannerMessage{Message: "Maintenance on Sunday", Type: ptr("warning")},
+			},
+			exists:   true,
+			upToDate: true,
+		},
+		"TokenExpirationChanged": {
+			params:   v1beta1.ConfigSystemParameters{TokenExpiration: ptr(int64(60))},
+			exists:   true,
+			upToDate: false,
+		},
+		"ProjectCreationRestricted": {
+			params:   v1beta1.ConfigSystemParameters{ProjectCreationRestriction: ptr(v1beta1.ProjectCreationAdminOnly)},
+			exists:   true,
+			upToDate: false,
+		},
+		"RobotTokenDurationChanged": {
+			params:   v1beta1.ConfigSystemParameters{RobotTokenDuration: ptr(int64(90))},
+			exists:   true,
+			upToDate: false,
+		},
+		"ReadOnlyEnabled": {
+			params:   v1beta1.ConfigSystemParameters{ReadOnly: ptr(true)},
+			exists:   true,
+			upToDate: false,
+		},
+		"ReadOnlyMatching": {
+			params:   v1beta1.ConfigSystemParameters{ReadOnly: ptr(false)},
+			exists:   true,
+			upToDate: true,
+		},
+		"BannerTypeChanged": {
+			params:   v1beta1.ConfigSystemParameters{BannerMessage: &v1beta1.BannerMessage{Message: "Maintenance on Sunday"}},
+			exists:   true,
+			upToDate: false,
+		},
+		"BannerMadeClosable": {
+			params:   v1beta1.ConfigSystemParameters{BannerMessage: &v1beta1.BannerMessage{Message: "Maintenance on Sunday", Type: ptr("warning"), Closable: ptr(true)}},
+			exists:   true,
+			upToDate: false,
+		},
+		"BannerScheduled": {
+			params: v1beta1.ConfigSystemParameters{BannerMessage: &v1beta1.BannerMessage{
+				Message:  "Maintenance on Sunday",
+				Type:     ptr("warning"),
+				FromDate: ptr("10/18/2026"),
+				ToDate:   ptr("10/19/2026"),
+			}},
+			exists:   true,
+			upToDate: false,
+		},
+		"BannerExplicitDefaults": {
+			params:   v1beta1.ConfigSystemParameters{BannerMessage: &v1beta1.BannerMessage{Message: "Maintenance on Sunday", Type: ptr("warning"), Closable: ptr(false)}},
+			exists:   true,
+			upToDate: true,
+		},
+		"BannerCleared": {
+			params:   v1beta1.ConfigSystemParameters{BannerMessage: &v1beta1.BannerMessage{}},
+			exists:   true,
+			upToDate: false,
+		},
+		"Deleted": {
+			params:  v1beta1.ConfigSystemParameters{TokenExpiration: ptr(int64(60))},
+			deleted: true,
+		},
+		"GetError": {
+			getErr:  errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.ConfigSystem{Spec: v1beta1.ConfigSystemSpec{ForProvider: tc.params}}
+			if tc.deleted {
+				now := metav1.NewTime(time.Now())
+				cr.SetDeletionTimestamp(&now)
+			}
+			ext := &external{
+				service: &harborclients.MockHarborClient{
+					GetConfigurationsFunc: func(context.Context) (harborclients.Configurations, error) {
+						return current, tc.getErr
+					},
+				},
+				logger: logging.NewNopLogger(),
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if obs.ResourceExists != tc.exists {
+				t.Errorf("ResourceExists = %v, want %v", obs.ResourceExists, tc.exists)
+			}
+			if obs.ResourceUpToDate != tc.upToDate {
+				t.Errorf("ResourceUpToDate = %v, want %v", obs.ResourceUpToDate, tc.upToDate)
+			}
+			if tc.exists && cr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+				t.Error("ConfigSystem should be Ready once observed")
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var got harborclients.Configurations
+	ext := &external{
+		service: &harborclients.MockHarborClient{
+			UpdateConfigurationsFunc: func(_ context.Context, cfg harborclients.Configurations) error {
+				got = cfg
+				return nil
+			},
+		},
+		logger: logging.NewNopLogger(),
+	}
+	cr := &v1beta1.ConfigSystem{Spec: v1beta1.ConfigSystemSpec{ForProvider: v1beta1.ConfigSystemParameters{
+		ProjectCreationRestriction: ptr(v1beta1.ProjectCreationAdminOnly),
+		RobotTokenDuration:         ptr(int64(90)),
+		BannerMessage: &v1beta1.BannerMessage{
+			Message:  "Read-only tonight",
+			Closable: ptr(true),
+			FromDate: ptr("10/17/2026"),
+			ToDate:   ptr("10/18/2026"),
+		},
+	}}}
+
+	if _, err := ext.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("Update() wrote %v, want only the three managed keys", got)
+	}
+	if got[keyProjectCreationRestriction] != v1beta1.ProjectCreationAdminOnly || got[keyRobotTokenDuration] != int64(90) {
+		t.Errorf("Update() wrote %v", got)
+	}
+
+	// The written banner must read back as the same banner.
+	b := parseBanner(got[keyBannerMessage])
+	if b == nil || b.Message != "Read-only tonight" || *b.Type != defaultBannerType || !*b.Closable || *b.FromDate != "10/17/2026" || *b.ToDate != "10/18/2026" {
+		t.Errorf("banner round trip = %+v", b)
+	}
+}
+
+func TestUpdateNothingManaged(t *testing.T) {
+	ext := &external{
+		service: &harborclients.MockHarborClient{
+			UpdateConfigurationsFunc: func(context.Context, harborclients.Configurations) error {
+				t.Error("UpdateConfigurations should not be called when no setting is managed")
+				return nil
+			},
+		},
+		logger: logging.NewNopLogger(),
+	}
+	if _, err := ext.Update(context.Background(), &v1beta1.ConfigSystem{}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+}
+
+func TestParseBanner(t *testing.T) {
+	cases := map[string]struct {
+		value interface{}
+		want  *v1beta1.BannerMessage
+	}{
+		"Unset":     {value: nil},
+		"Empty":     {value: ""},
+		"NoMessage": {value: `{"message":"","type":"info"}`},
+		"Document":  {value: `{"message":"hi","type":"danger"}`, want: &v1beta1.BannerMessage{Message: "hi", Type: ptr("danger")}},
+		"NoType":    {value: `{"message":"hi"}`, want: &v1beta1.BannerMessage{Message: "hi", Type: ptr(defaultBannerType)}},
+		"PlainText": {value: "hi", want: &v1beta1.BannerMessage{Message: "hi", Type: ptr(defaultBannerType)}},
+		"Scheduled": {
+			value: `{"closable":true,"message":"hi","type":"info","fromDate":"10/18/2026","toDate":"10/19/2026"}`,
+			want:  &v1beta1.BannerMessage{Message: "hi", Type: ptr("info"), Closable: ptr(true), FromDate: ptr("10/18/2026"), ToDate: ptr("10/19/2026")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := parseBanner(tc.value)
+			if (got == nil) != (tc.want == nil) {
+				t.Fatalf("parseBanner() = %+v, want %+v", got, tc.want)
+			}
+			if got != nil && formatBanner(got) != formatBanner(tc.want) {
+				t.Errorf("parseBanner() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateStrictFields(t *testing.T) {
+	ctrlutil.SetStrictFields(true)
+	t.Cleanup(func() { ctrlutil.SetStrictFields(false) })
+
+	// A Harbor older than 2.8 has no banner_message setting, so it neither
+	// keeps nor returns one.
+	ext := &external{
+		service: &harborclients.MockHarborClient{
+			UpdateConfigurationsFunc: func(context.Context, harborclients.Configurations) error { return nil },
+			GetConfigurationsFunc: func(context.Context) (harborclients.Configurations, error) {
+				return harborclients.Configurations{keyReadOnly: true}, nil
+			},
+		},
+		logger: logging.NewNopLogger(),
+	}
+	cr := &v1beta1.ConfigSystem{Spec: v1beta1.ConfigSystemSpec{ForProvider: v1beta1.ConfigSystemParameters{
+		ReadOnly:      ptr(true),
+		BannerMessage: &v1beta1.BannerMessage{Message: "Read-only tonight"},
+	}}}
+
+	_, err := ext.Update(context.Background(), cr)
+	if err == nil || !strings.Contains(err.Error(), keyBannerMessage) {
+		t.Errorf("Update() error = %v, want one naming %s", err, keyBannerMessage)
+	}
+	if c := cr.GetCondition(common.TypeFieldsIgnored); c.Status != corev1.ConditionTrue || !strings.Contains(c.Message, keyBannerMessage) {
+		t.Errorf("FieldsIgnored condition = %+v", c)
+	}
+
+	cr.Spec.ForProvider.BannerMessage = nil
+	if _, err := ext.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if c := cr.GetCondition(common.TypeFieldsIgnored); c.Status != corev1.ConditionFalse {
+		t.Errorf("FieldsIgnored condition = %+v, want False", c)
+	}
+}
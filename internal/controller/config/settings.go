@@ -0,0 +1,195 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+)
+
+// Harbor configuration keys managed by a ConfigSystem.
+const (
+	keyTokenExpiration            = "token_expiration"
+	keyProjectCreationRestriction = "project_creation_restriction"
+	keyRobotTokenDuration         = "robot_token_duration"
+	keyBannerMessage              = "banner_message"
+	keyReadOnly                   = "read_only"
+)
+
+// defaultBannerType is the banner type Harbor's UI uses when none is set.
+const defaultBannerType = "info"
+
+// banner is the JSON document Harbor stores, as a string, in banner_message.
+type banner struct {
+	Closable bool   `json:"closable"`
+	Message  string `json:"message"`
+	Type     string `json:"type"`
+	FromDate string `json:"fromDate"`
+	ToDate   string `json:"toDate"`
+}
+
+// observe reads the settings a ConfigSystem manages from cfg.
+func observe(cfg clients.Configurations) v1beta1.ConfigSystemObservation {
+	return v1beta1.ConfigSystemObservation{
+		TokenExpiration:            int64Value(cfg[keyTokenExpiration]),
+		ProjectCreationRestriction: stringValue(cfg[keyProjectCreationRestriction]),
+		RobotTokenDuration:         int64Value(cfg[keyRobotTokenDuration]),
+		BannerMessage:              parseBanner(cfg[keyBannerMessage]),
+		ReadOnly:                   boolValue(cfg[keyReadOnly]),
+	}
+}
+
+// desired returns the configuration keys to write for p.
+func desired(p v1beta1.ConfigSystemParameters) clients.Configurations {
+	cfg := clients.Configurations{}
+	if p.TokenExpiration != nil {
+		cfg[keyTokenExpiration] = *p.TokenExpiration
+	}
+	if p.ProjectCreationRestriction != nil {
+		cfg[keyProjectCreationRestriction] = *p.ProjectCreationRestriction
+	}
+	if p.RobotTokenDuration != nil {
+		cfg[keyRobotTokenDuration] = *p.RobotTokenDuration
+	}
+	if p.BannerMessage != nil {
+		cfg[keyBannerMessage] = formatBanner(p.BannerMessage)
+	}
+	if p.ReadOnly != nil {
+		cfg[keyReadOnly] = *p.ReadOnly
+	}
+	return cfg
+}
+
+// isUpToDate reports whether every setting in p has its observed value.
+func isUpToDate(p v1beta1.ConfigSystemParameters, o v1beta1.ConfigSystemObservation) bool {
+	if p.TokenExpiration != nil && (o.TokenExpiration == nil || *p.TokenExpiration != *o.TokenExpiration) {
+		return false
+	}
+	if p.ProjectCreationRestriction != nil && (o.ProjectCreationRestriction == nil || *p.ProjectCreationRestriction != *o.ProjectCreationRestriction) {
+		return false
+	}
+	if p.RobotTokenDuration != nil && (o.RobotTokenDuration == nil || *p.RobotTokenDuration != *o.RobotTokenDuration) {
+		return false
+	}
+	if p.ReadOnly != nil && (o.ReadOnly == nil || *p.ReadOnly != *o.ReadOnly) {
+		return false
+	}
+	if p.BannerMessage != nil {
+		if p.BannerMessage.Message == "" {
+			return o.BannerMessage == nil
+		}
+		if o.BannerMessage == nil || formatBanner(p.BannerMessage) != formatBanner(o.BannerMessage) {
+			return false
+		}
+	}
+	return true
+}
+
+func bannerType(b *v1beta1.BannerMessage) string {
+	if b.Type == nil || *b.Type == "" {
+		return defaultBannerType
+	}
+	return *b.Type
+}
+
+// formatBanner encodes b as Harbor stores it. An empty message clears the
+// banner. Unset fields are written as Harbor's UI writes them, so a banner
+// always encodes the same way whichever form its defaults take.
+func formatBanner(b *v1beta1.BannerMessage) string {
+	if b.Message == "" {
+		return ""
+	}
+	raw, _ := json.Marshal(banner{
+		Closable: b.Closable != nil && *b.Closable,
+		Message:  b.Message,
+		Type:     bannerType(b),
+		FromDate: ptrValue(b.FromDate),
+		ToDate:   ptrValue(b.ToDate),
+	})
+	return string(raw)
+}
+
+// parseBanner decodes a banner_message value. A value that is not a banner
+// document, such as one written by an older Harbor, is read as the message.
+func parseBanner(v interface{}) *v1beta1.BannerMessage {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return nil
+	}
+	b := banner{}
+	if err := json.Unmarshal([]byte(s), &b); err != nil {
+		b = banner{Message: s}
+	}
+	if b.Message == "" {
+		return nil
+	}
+	t := b.Type
+	if t == "" {
+		t = defaultBannerType
+	}
+	return &v1beta1.BannerMessage{
+		Message:  b.Message,
+		Type:     &t,
+		Closable: &b.Closable,
+		FromDate: optional(b.FromDate),
+		ToDate:   optional(b.ToDate),
+	}
+}
+
+// int64Value reads a numeric configuration value, which is a float64 when
+// decoded from JSON.
+func int64Value(v interface{}) *int64 {
+	var n int64
+	switch x := v.(type) {
+	case float64:
+		n = int64(x)
+	case int64:
+		n = x
+	case int:
+		n = int64(x)
+	case json.Number:
+		i, err := x.Int64()
+		if err != nil {
+			return nil
+		}
+		n = i
+	default:
+		return nil
+	}
+	return &n
+}
+
+// optional returns a pointer to s, or nil when s is empty.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func ptrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func boolValue(v interface{}) *bool {
+	b, ok := v.(bool)
+	if !ok {
+		return nil
+	}
+	return &b
+}
+
+func stringValue(v interface{}) *string {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package configauth manages how a Harbor instance authenticates users.
+package configauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotConfigAuth = "managed resource is not a ConfigAuth custom resource"
+	errNewClient     = "cannot create new Service"
+	errGetConfig     = "cannot get Harbor authentication configuration"
+	errUpdateConfig  = "cannot update Harbor authentication configuration"
+	errGetSecret     = "cannot read ConfigAuth secrets"
+)
+
+// Setup adds a controller that reconciles ConfigAuth managed resources
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.ConfigAuthGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), clients.CapabilityManageSystem, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithUpdateDebounce(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:   mgr.GetClient(),
+			logger: log,
+		})))))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(10 * time.Minute),
+		managed.WithPollIntervalHook(ctrlutil.DebouncedPollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ConfigAuthGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ConfigAuth{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// Connect produces an ExternalClient by creating a Harbor client
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ConfigAuth); !ok {
+		return nil, errors.New(errNotConfigAuth)
+	}
+
+	harborClient, err := clients.NewHarborClientFromProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{kube: c.kube, service: harborClient, logger: c.logger}, nil
+}
+
+// external applies a ConfigAuth to Harbor's system configuration. The
+// configuration always exists, so it is never created or deleted.
+type external struct {
+	kube    client.Client
+	service clients.HarborClienter
+	logger  logging.Logger
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "configauth.observe",
+		tracing.SpanAttrs("ConfigAuth", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ConfigAuth)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotConfigAuth)
+	}
+
+	// Deleting a ConfigAuth leaves Harbor's settings as they are.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	s, err := c.secrets(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetSecret)
+	}
+	cfg, err := c.service.GetConfigurations(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetConfig)
+	}
+	observedHash := cr.Status.AtProvider.SecretHash
+	cr.Status.AtProvider = observe(cfg)
+	cr.Status.AtProvider.SecretHash = observedHash
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(cr.Spec.ForProvider, cfg, s.hash(string(cr.GetUID())), observedHash),
+	}, nil
+}
+
+// Create applies the settings. Observe always reports the configuration as
+// existing, so the reconciler should never call it.
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, err := c.Update(ctx, mg)
+	return managed.ExternalCreation{}, err
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "configauth.update",
+		tracing.SpanAttrs("ConfigAuth", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ConfigAuth)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotConfigAuth)
+	}
+
+	s, err := c.secrets(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errGetSecret)
+	}
+	cfg := desired(cr.Spec.ForProvider, s)
+	if len(cfg) == 0 {
+		return managed.ExternalUpdate{}, nil
+	}
+	if err := c.service.UpdateConfigurations(ctx, cfg); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateConfig)
+	}
+	cr.Status.AtProvider.SecretHash = s.hash(string(cr.GetUID()))
+	// Harbor never returns the secrets, so only the other keys are read back.
+	if err := ctrlutil.CheckReadBack(cr, desired(cr.Spec.ForProvider, secrets{}), func() (any, error) { return c.service.GetConfigurations(ctx) }); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	c.logger.Info("Updated Harbor authentication configuration", "name", cr.GetName(), "keys", len(cfg))
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(_ context.Context, _ resource.Managed) (managed.ExternalDelete, error) {
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
+
+// secrets reads the OIDC client secret and LDAP search password cr
+// references.
+func (c *external) secrets(ctx context.Context, cr *v1beta1.ConfigAuth) (secrets, error) {
+	s := secrets{}
+	p := cr.Spec.ForProvider
+	if p.OIDC != nil && p.OIDC.ClientSecretSecretRef != nil {
+		v, err := c.secretValue(ctx, cr, p.OIDC.ClientSecretSecretRef)
+		if err != nil {
+			return s, err
+		}
+		s.OIDCClientSecret = &v
+	}
+	if p.LDAP != nil && p.LDAP.SearchPasswordSecretRef != nil {
+		v, err := c.secretValue(ctx, cr, p.LDAP.SearchPasswordSecretRef)
+		if err != nil {
+			return s, err
+		}
+		s.LDAPSearchPassword = &v
+	}
+	return s, nil
+}
+
+// secretValue reads the key ref selects. A reference without a namespace is
+// to a Secret in cr's namespace.
+func (c *external) secretValue(ctx context.Context, cr *v1beta1.ConfigAuth, ref *xpv1.SecretKeySelector) (string, error) {
+	nn := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	if nn.Namespace == "" {
+		nn.Namespace = cr.GetNamespace()
+	}
+	secret := &corev1.Secret{}
+	if err := c.kube.Get(ctx, nn, secret); err != nil {
+		return "", errors.Wrapf(err, "cannot get Secret %s", nn)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", errors.Errorf("secret key %q not found in secret %s", ref.Key, nn)
+	}
+	return string(value), nil
+}
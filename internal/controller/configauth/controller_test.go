@@ -0,0 +1,253 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package configauth
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func kubeWithSecret(t *testing.T, value string) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "harbor-oidc", Namespace: "harbor"},
+		Data:       map[string][]byte{"clientSecret": []byte(value)},
+	}).Build()
+}
+
+func oidcParams() v1beta1.ConfigAuthParameters {
+	return v1beta1.ConfigAuthParameters{
+		AuthMode: ptr(v1beta1.AuthModeOIDC),
+		OIDC: &v1beta1.OIDCSettings{
+			Name:     ptr("Keycloak"),
+			Endpoint: ptr("https://sso.example.com/realms/main"),
+			ClientID: ptr("harbor"),
+			ClientSecretSecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: "harbor-oidc"},
+				Key:             "clientSecret",
+			},
+			Scopes:      []string{"openid", "offline_access"},
+			GroupsClaim: ptr("groups"),
+			AutoOnboard: ptr(true),
+		},
+	}
+}
+
+func TestObserve(t *testing.T) {
+	current := harborclients.Configurations{
+		keyAuthMode:          v1beta1.AuthModeOIDC,
+		keySelfRegistration:  false,
+		"oidc_name":          "Keycloak",
+		"oidc_endpoint":      "https://sso.example.com/realms/main",
+		"oidc_client_id":     "harbor",
+		"oidc_scope":         "openid,offline_access",
+		"oidc_groups_claim":  "groups",
+		"oidc_admin_group":   "",
+		"oidc_auto_onboard":  true,
+		"oidc_verify_cert":   true,
+		"ldap_url":           "",
+		"ldap_scope":         float64(2),
+		"ldap_timeout":       float64(5),
+		"ldap_verify_cert":   true,
+		"ldap_group_base_dn": "",
+	}
+	cr := func() *v1beta1.ConfigAuth {
+		return &v1beta1.ConfigAuth{
+			ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: "harbor", UID: types.UID("auth-uid")},
+			Spec:       v1beta1.ConfigAuthSpec{ForProvider: oidcParams()},
+		}
+	}
+	written := secrets{OIDCClientSecret: ptr("s3cret")}.hash("auth-uid")
+
+	cases := map[string]struct {
+		params   func(p *v1beta1.ConfigAuthParameters)
+		secret   string
+		hash     *string
+		getErr   error
+		wantErr  bool
+		upToDate bool
+	}{
+		"Matching": {
+			secret:   "s3cret",
+			hash:     written,
+			upToDate: true,
+		},
+		"SecretNeverWritten": {
+			secret: "s3cret",
+		},
+		"SecretRotated": {
+			secret: "rotated",
+			hash:   written,
+		},
+		"ScopesChanged": {
+			params: func(p *v1beta1.ConfigAuthParameters) { p.OIDC.Scopes = []string{"openid"} },
+			secret: "s3cret",
+			hash:   written,
+		},
+		"AuthModeChanged": {
+			params: func(p *v1beta1.ConfigAuthParameters) { p.AuthMode = ptr(v1beta1.AuthModeDatabase) },
+			secret: "s3cret",
+			hash:   written,
+		},
+		"EmptyStringMatches": {
+			params:   func(p *v1beta1.ConfigAuthParameters) { p.OIDC.AdminGroup = ptr(""); p.OIDC.GroupFilter = ptr("") },
+			secret:   "s3cret",
+			hash:     written,
+			upToDate: true,
+		},
+		"LDAPScopeChanged": {
+			params: func(p *v1beta1.ConfigAuthParameters) {
+				p.LDAP = &v1beta1.LDAPSettings{Scope: ptr(int64(1))}
+			},
+			secret: "s3cret",
+			hash:   written,
+		},
+		"LDAPTimeoutMatching": {
+			params:   func(p *v1beta1.ConfigAuthParameters) { p.LDAP = &v1beta1.LDAPSettings{Timeout: ptr(int64(5))} },
+			secret:   "s3cret",
+			hash:     written,
+			upToDate: true,
+		},
+		"GetError": {
+			secret:  "s3cret",
+			getErr:  errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := cr()
+			if tc.params != nil {
+				tc.params(&cr.Spec.ForProvider)
+			}
+			cr.Status.AtProvider.SecretHash = tc.hash
+			ext := &external{
+				kube: kubeWithSecret(t, tc.secret),
+				service: &harborclients.MockHarborClient{
+					GetConfigurationsFunc: func(context.Context) (harborclients.Configurations, error) {
+						return current, tc.getErr
+					},
+				},
+				logger: logging.NewNopLogger(),
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if !obs.ResourceExists {
+				t.Error("ResourceExists = false, want true")
+			}
+			if obs.ResourceUpToDate != tc.upToDate {
+				t.Errorf("ResourceUpToDate = %v, want %v", obs.ResourceUpToDate, tc.upToDate)
+			}
+			if cr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+				t.Error("ConfigAuth should be Ready once observed")
+			}
+			if !reflect.DeepEqual(cr.Status.AtProvider.SecretHash, tc.hash) {
+				t.Error("Observe() should keep the recorded secret hash")
+			}
+		})
+	}
+}
+
+func TestObserveSettings(t *testing.T) {
+	got := observe(harborclients.Configurations{
+		keyAuthMode:           v1beta1.AuthModeLDAP,
+		"oidc_scope":          "",
+		"ldap_url":            "ldaps://ldap.example.com",
+		"ldap_scope":          float64(2),
+		"ldap_verify_cert":    false,
+		keyLDAPSearchPassword: nil,
+	})
+	want := v1beta1.ConfigAuthObservation{
+		AuthMode: ptr(v1beta1.AuthModeLDAP),
+		LDAP:     &v1beta1.LDAPSettings{URL: ptr("ldaps://ldap.example.com"), Scope: ptr(int64(2)), VerifyCert: ptr(false)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("observe() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var got harborclients.Configurations
+	ext := &external{
+		kube: kubeWithSecret(t, "s3cret"),
+		service: &harborclients.MockHarborClient{
+			UpdateConfigurationsFunc: func(_ context.Context, cfg harborclients.Configurations) error {
+				got = cfg
+				return nil
+			},
+		},
+		logger: logging.NewNopLogger(),
+	}
+	cr := &v1beta1.ConfigAuth{
+		ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: "harbor", UID: types.UID("auth-uid")},
+		Spec:       v1beta1.ConfigAuthSpec{ForProvider: oidcParams()},
+	}
+
+	if _, err := ext.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	want := harborclients.Configurations{
+		keyAuthMode:         v1beta1.AuthModeOIDC,
+		"oidc_name":         "Keycloak",
+		"oidc_endpoint":     "https://sso.example.com/realms/main",
+		"oidc_client_id":    "harbor",
+		keyOIDCClientSecret: "s3cret",
+		"oidc_scope":        "openid,offline_access",
+		"oidc_groups_claim": "groups",
+		"oidc_auto_onboard": true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Update() wrote %v, want %v", got, want)
+	}
+	if h := cr.Status.AtProvider.SecretHash; h == nil || *h != *(secrets{OIDCClientSecret: ptr("s3cret")}.hash("auth-uid")) {
+		t.Errorf("SecretHash = %v, want the hash of the written secret", h)
+	}
+}
+
+func TestUpdateMissingSecret(t *testing.T) {
+	ext := &external{
+		kube: kubeWithSecret(t, "s3cret"),
+		service: &harborclients.MockHarborClient{
+			UpdateConfigurationsFunc: func(context.Context, harborclients.Configurations) error {
+				t.Error("UpdateConfigurations should not be called without the client secret")
+				return nil
+			},
+		},
+		logger: logging.NewNopLogger(),
+	}
+	cr := &v1beta1.ConfigAuth{
+		ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: "other"},
+		Spec:       v1beta1.ConfigAuthSpec{ForProvider: oidcParams()},
+	}
+	if _, err := ext.Update(context.Background(), cr); err == nil {
+		t.Error("Update() should fail when the referenced Secret is missing")
+	}
+}
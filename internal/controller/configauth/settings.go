@@ -0,0 +1,216 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package configauth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+)
+
+// Harbor configuration keys managed by a ConfigAuth, besides those of
+// oidcKeys and ldapKeys.
+const (
+	keyAuthMode           = "auth_mode"
+	keySelfRegistration   = "self_registration"
+	keyOIDCClientSecret   = "oidc_client_secret"
+	keyLDAPSearchPassword = "ldap_search_password"
+)
+
+// listFields are the fields Harbor stores as comma separated strings.
+var listFields = map[string]bool{"scopes": true}
+
+// oidcKeys maps the JSON fields of OIDCSettings to their Harbor
+// configuration keys. The client secret is written separately.
+var oidcKeys = map[string]string{
+	"name":        "oidc_name",
+	"endpoint":    "oidc_endpoint",
+	"clientId":    "oidc_client_id",
+	"scopes":      "oidc_scope",
+	"groupsClaim": "oidc_groups_claim",
+	"adminGroup":  "oidc_admin_group",
+	"groupFilter": "oidc_group_filter",
+	"userClaim":   "oidc_user_claim",
+	"verifyCert":  "oidc_verify_cert",
+	"autoOnboard": "oidc_auto_onboard",
+}
+
+// ldapKeys maps the JSON fields of LDAPSettings to their Harbor
+// configuration keys. The search password is written separately.
+var ldapKeys = map[string]string{
+	"url":                      "ldap_url",
+	"baseDn":                   "ldap_base_dn",
+	"searchDn":                 "ldap_search_dn",
+	"filter":                   "ldap_filter",
+	"uid":                      "ldap_uid",
+	"scope":                    "ldap_scope",
+	"timeout":                  "ldap_timeout",
+	"verifyCert":               "ldap_verify_cert",
+	"groupBaseDn":              "ldap_group_base_dn",
+	"groupSearchFilter":        "ldap_group_search_filter",
+	"groupAttributeName":       "ldap_group_attribute_name",
+	"groupAdminDn":             "ldap_group_admin_dn",
+	"groupMembershipAttribute": "ldap_group_membership_attribute",
+	"groupSearchScope":         "ldap_group_search_scope",
+}
+
+// secrets are the values a ConfigAuth reads from Secrets. Harbor never
+// returns them.
+type secrets struct {
+	OIDCClientSecret   *string
+	LDAPSearchPassword *string
+}
+
+// hash returns a hash of s salted with uid, or nil when s is empty.
+func (s secrets) hash(uid string) *string {
+	if s.OIDCClientSecret == nil && s.LDAPSearchPassword == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(s)
+	h := sha256.Sum256(append([]byte(uid+":"), raw...))
+	v := hex.EncodeToString(h[:])
+	return &v
+}
+
+// observe reads the settings a ConfigAuth manages from cfg.
+func observe(cfg clients.Configurations) v1beta1.ConfigAuthObservation {
+	o := v1beta1.ConfigAuthObservation{
+		AuthMode:         stringValue(cfg[keyAuthMode]),
+		SelfRegistration: boolValue(cfg[keySelfRegistration]),
+	}
+	oidc := &v1beta1.OIDCSettings{}
+	if fromConfig(cfg, oidcKeys, oidc) {
+		o.OIDC = oidc
+	}
+	ldap := &v1beta1.LDAPSettings{}
+	if fromConfig(cfg, ldapKeys, ldap) {
+		o.LDAP = ldap
+	}
+	return o
+}
+
+// desired returns the configuration keys to write for p and s.
+func desired(p v1beta1.ConfigAuthParameters, s secrets) clients.Configurations {
+	cfg := clients.Configurations{}
+	if p.AuthMode != nil {
+		cfg[keyAuthMode] = *p.AuthMode
+	}
+	if p.SelfRegistration != nil {
+		cfg[keySelfRegistration] = *p.SelfRegistration
+	}
+	if p.OIDC != nil {
+		toConfig(p.OIDC, oidcKeys, cfg)
+	}
+	if p.LDAP != nil {
+		toConfig(p.LDAP, ldapKeys, cfg)
+	}
+	if s.OIDCClientSecret != nil {
+		cfg[keyOIDCClientSecret] = *s.OIDCClientSecret
+	}
+	if s.LDAPSearchPassword != nil {
+		cfg[keyLDAPSearchPassword] = *s.LDAPSearchPassword
+	}
+	return cfg
+}
+
+// isUpToDate reports whether every setting in p has its value in cfg.
+// Secrets are compared by desiredHash, the hash of those read from their
+// Secrets, and observedHash, the one recorded when they were last written.
+func isUpToDate(p v1beta1.ConfigAuthParameters, cfg clients.Configurations, desiredHash, observedHash *string) bool {
+	for k, v := range desired(p, secrets{}) {
+		if !sameValue(v, cfg[k]) {
+			return false
+		}
+	}
+	if desiredHash == nil {
+		return true
+	}
+	return observedHash != nil && *desiredHash == *observedHash
+}
+
+// sameValue reports whether a desired value equals the one Harbor has. Both
+// have been decoded from JSON, so numbers are float64. Harbor leaves some
+// empty strings out.
+func sameValue(want, have interface{}) bool {
+	if want == "" && have == nil {
+		return true
+	}
+	return reflect.DeepEqual(want, have)
+}
+
+// toConfig writes the fields of settings, a struct of OIDC or LDAP
+// settings, to cfg under the keys they map to. Unset fields are skipped.
+func toConfig(settings interface{}, keys map[string]string, cfg clients.Configurations) {
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return
+	}
+	for field, v := range fields {
+		key, ok := keys[field]
+		if !ok {
+			continue
+		}
+		if list, ok := v.([]interface{}); ok {
+			items := make([]string, 0, len(list))
+			for _, item := range list {
+				if s, ok := item.(string); ok {
+					items = append(items, s)
+				}
+			}
+			v = strings.Join(items, ",")
+		}
+		cfg[key] = v
+	}
+}
+
+// fromConfig reads the keys that map to the fields of settings, a pointer to
+// a struct of OIDC or LDAP settings, from cfg. Empty strings are left unset.
+// It reports whether any field was set.
+func fromConfig(cfg clients.Configurations, keys map[string]string, settings interface{}) bool {
+	fields := map[string]interface{}{}
+	for field, key := range keys {
+		v, ok := cfg[key]
+		if !ok || v == nil || v == "" {
+			continue
+		}
+		if s, ok := v.(string); ok && listFields[field] {
+			v = strings.Split(s, ",")
+		}
+		fields[field] = v
+	}
+	if len(fields) == 0 {
+		return false
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(raw, settings) == nil
+}
+
+func boolValue(v interface{}) *bool {
+	b, ok := v.(bool)
+	if !ok {
+		return nil
+	}
+	return &b
+}
+
+func stringValue(v interface{}) *string {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package connectiontest runs HarborConnectionTests, which check once that a
+// ProviderConfig can reach and use Harbor.
+package connectiontest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/pkg/errors"
+	v1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errGetTest      = "cannot get HarborConnectionTest"
+	errUpdateStatus = "cannot update HarborConnectionTest status"
+
+	// timeout bounds all of a test's calls to Harbor.
+	timeout = time.Minute
+)
+
+// Names of the checks, in the order they run.
+const (
+	checkCredentials    = "Credentials"
+	checkAuthentication = "Authentication"
+	checkVersion        = "Version"
+	checkListProjects   = "ListProjects"
+	checkRobotLifecycle = "RobotLifecycle"
+)
+
+// Setup adds a controller that runs each HarborConnectionTest once.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "harborconnectiontest"
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.HarborConnectionTest{}).
+		Complete(&tester{
+			kube:      mgr.GetClient(),
+			newClient: harborclients.NewHarborClientForProviderConfig,
+			log:       o.Logger.WithValues("controller", name),
+		})
+}
+
+// A tester runs the checks of HarborConnectionTests that have not run.
+type tester struct {
+	kube      client.Client
+	newClient func(ctx context.Context, kube client.Client, providerConfig string) (harborclients.HarborClienter, error)
+	log       logging.Logger
+}
+
+func (r *tester) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	t := &v1beta1.HarborConnectionTest{}
+	if err := r.kube.Get(ctx, req.NamespacedName, t); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(errors.Wrap(err, errGetTest))
+	}
+	if t.Status.CompletionTime != nil {
+		return reconcile.Result{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	r.run(ctx, t)
+
+	now := metav1.Now()
+	t.Status.CompletionTime = &now
+	if err := r.kube.Status().Update(ctx, t); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+	r.log.Debug("Ran HarborConnectionTest", "name", t.GetName(), "result", t.Status.Result)
+	return reconcile.Result{}, nil
+}
+
+// run runs the checks of t in order and records their outcomes. A check
+// that fails skips the checks that depend on it.
+func (r *tester) run(ctx context.Context, t *v1beta1.HarborConnectionTest) {
+	c := &checks{test: t}
+	defer c.finish()
+
+	svc, err := r.newClient(ctx, r.kube, t.Spec.ProviderConfigRef.Name)
+	if err != nil {
+		c.fail(checkCredentials, err)
+		c.skip(checkAuthentication, checkVersion, checkListProjects, checkRobotLifecycle)
+		return
+	}
+	defer func() { _ = svc.Close() }()
+	c.pass(checkCredentials, "loaded the credentials of ProviderConfig "+t.Spec.ProviderConfigRef.Name)
+
+	user, err := svc.GetCurrentUser(ctx)
+	if err != nil {
+		c.fail(checkAuthentication, err)
+		c.skip(checkVersion, checkListProjects, checkRobotLifecycle)
+		return
+	}
+	t.Status.Username = user.Username
+	t.Status.SysAdmin = &user.SysAdmin
+	c.pass(checkAuthentication, fmt.Sprintf("logged in to %s as %s", svc.GetBaseURL(), user.Username))
+
+	if version, err := svc.GetVersion(ctx); err != nil {
+		c.fail(checkVersion, err)
+	} else {
+		t.Status.HarborVersion = version
+		c.pass(checkVersion, "Harbor "+version)
+	}
+
+	if projects, err := svc.ListProjects(ctx); err != nil {
+		c.fail(checkListProjects, err)
+	} else {
+		c.pass(checkListProjects, fmt.Sprintf("%d projects visible", len(projects)))
+	}
+
+	if t.Spec.SandboxProject == nil {
+		c.skipped(checkRobotLifecycle, "no sandboxProject")
+		return
+	}
+	if msg, err := robotLifecycle(ctx, svc, t); err != nil {
+		c.fail(checkRobotLifecycle, err)
+	} else {
+		c.pass(checkRobotLifecycle, msg)
+	}
+}
+
+// robotLifecycle creates a robot account in the sandbox project and deletes
+// it again. The robot expires after a day in case it cannot be deleted.
+func robotLifecycle(ctx context.Context, svc harborclients.HarborClienter, t *v1beta1.HarborConnectionTest) (string, error) {
+	project := *t.Spec.SandboxProject
+	desc := "Created and deleted by HarborConnectionTest " + t.GetName()
+	oneDay := int64(1)
+	robot, err := svc.CreateRobot(ctx, &harborclients.RobotSpec{
+		Name:        "connection-test-" + t.GetName(),
+		Description: &desc,
+		ProjectID:   &project,
+		ExpiresIn:   &oneDay,
+		Permissions: []harborclients.RobotPermission{{Namespace: project, Access: []string{"pull"}}},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot create a robot account in project %s", project)
+	}
+	if err := svc.DeleteRobot(ctx, robot.ID); err != nil {
+		return "", errors.Wrapf(err, "created robot account %s in project %s but cannot delete it; delete it by hand", robot.Name, project)
+	}
+	return fmt.Sprintf("created and deleted robot account %s in project %s", robot.Name, project), nil
+}
+
+// checks accumulates the outcomes of a test's checks.
+type checks struct {
+	test   *v1beta1.HarborConnectionTest
+	failed *v1beta1.ConnectionCheck
+}
+
+func (c *checks) add(check v1beta1.ConnectionCheck) {
+	c.test.Status.Checks = append(c.test.Status.Checks, check)
+}
+
+// pass records that a check passed.
+func (c *checks) pass(name, msg string) {
+	c.add(v1beta1.ConnectionCheck{Name: name, Result: v1beta1.ConnectionTestPassed, Message: msg})
+}
+
+// fail records that a check failed. The first failure is reported by the
+// test's Ready condition.
+func (c *checks) fail(name string, err error) {
+	check := v1beta1.ConnectionCheck{Name: name, Result: v1beta1.ConnectionTestFailed, Message: err.Error()}
+	c.add(check)
+	if c.failed == nil {
+		c.failed = &check
+	}
+}
+
+// skipped records that a check did not run, and why.
+func (c *checks) skipped(name, why string) {
+	c.add(v1beta1.ConnectionCheck{Name: name, Result: v1beta1.ConnectionTestSkipped, Message: why})
+}
+
+// skip records that checks did not run because an earlier one failed.
+func (c *checks) skip(names ...string) {
+	for _, n := range names {
+		c.skipped(n, c.failed.Name+" failed")
+	}
+}
+
+// finish sets the test's result and Ready condition.
+func (c *checks) finish() {
+	if c.failed != nil {
+		c.test.Status.Result = v1beta1.ConnectionTestFailed
+		c.test.SetConditions(v1beta1.ChecksFailed(c.failed.Name, c.failed.Message))
+		return
+	}
+	c.test.Status.Result = v1beta1.ConnectionTestPassed
+	c.test.SetConditions(v1beta1.ChecksPassed())
+}
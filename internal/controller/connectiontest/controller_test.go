@@ -0,0 +1,165 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package connectiontest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	v1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newTest(sandbox *string) *v1beta1.HarborConnectionTest {
+	return &v1beta1.HarborConnectionTest{
+		ObjectMeta: metav1.ObjectMeta{Name: "after-upgrade"},
+		Spec: v1beta1.HarborConnectionTestSpec{
+			ProviderConfigRef: v1beta1.ProviderConfigName{Name: "default"},
+			SandboxProject:    sandbox,
+		},
+	}
+}
+
+// runTest reconciles t with svc as its Harbor client, or with newErr as the
+// error creating one, and returns t as stored.
+func runTest(t *testing.T, test *v1beta1.HarborConnectionTest, svc harborclients.HarborClienter, newErr error) *v1beta1.HarborConnectionTest {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(test).WithStatusSubresource(test).Build()
+	r := &tester{
+		kube: kube,
+		newClient: func(_ context.Context, _ client.Client, pc string) (harborclients.HarborClienter, error) {
+			if pc != "default" {
+				t.Errorf("newClient(%q), want the referenced ProviderConfig", pc)
+			}
+			return svc, newErr
+		},
+		log: logging.NewNopLogger(),
+	}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: test.GetName()}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	got := &v1beta1.HarborConnectionTest{}
+	if err := kube.Get(context.Background(), types.NamespacedName{Name: test.GetName()}, got); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func results(t *v1beta1.HarborConnectionTest) string {
+	r := make([]string, 0, len(t.Status.Checks))
+	for _, c := range t.Status.Checks {
+		r = append(r, c.Name+"="+c.Result)
+	}
+	return strings.Join(r, " ")
+}
+
+func TestConnectionTestPasses(t *testing.T) {
+	var deleted string
+	svc := &harborclients.MockHarborClient{
+		ListProjectsFunc: func(context.Context) ([]*harborclients.ProjectStatus, error) {
+			return []*harborclients.ProjectStatus{{Name: "library"}, {Name: "sandbox"}}, nil
+		},
+		CreateRobotFunc: func(_ context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+			if spec.ProjectID == nil || *spec.ProjectID != "sandbox" {
+				t.Errorf("CreateRobot() project = %v, want sandbox", spec.ProjectID)
+			}
+			return &harborclients.RobotStatus{ID: "7", Name: "robot$sandbox+" + spec.Name}, nil
+		},
+		DeleteRobotFunc: func(_ context.Context, id string) error {
+			deleted = id
+			return nil
+		},
+	}
+	sandbox := "sandbox"
+	got := runTest(t, newTest(&sandbox), svc, nil)
+
+	want := "Credentials=Passed Authentication=Passed Version=Passed ListProjects=Passed RobotLifecycle=Passed"
+	if results(got) != want {
+		t.Errorf("checks = %s, want %s", results(got), want)
+	}
+	if got.Status.Result != v1beta1.ConnectionTestPassed || got.Status.CompletionTime == nil {
+		t.Errorf("status = %s completed %v, want a completed pass", got.Status.Result, got.Status.CompletionTime)
+	}
+	if got.Status.Username != "admin" || got.Status.HarborVersion != "v2.8.0" {
+		t.Errorf("status = %s on %s, want admin on v2.8.0", got.Status.Username, got.Status.HarborVersion)
+	}
+	if c := got.GetCondition(xpv1.TypeReady); c.Status != corev1.ConditionTrue {
+		t.Errorf("Ready = %s, want True", c.Status)
+	}
+	if deleted != "7" {
+		t.Errorf("DeleteRobot(%q), want the robot that was created deleted", deleted)
+	}
+}
+
+func TestConnectionTestAuthenticationFails(t *testing.T) {
+	svc := &harborclients.MockHarborClient{
+		GetCurrentUserFunc: func(context.Context) (*harborclients.CurrentUser, error) {
+			return nil, errors.New("401 Unauthorized")
+		},
+	}
+	got := runTest(t, newTest(nil), svc, nil)
+
+	want := "Credentials=Passed Authentication=Failed Version=Skipped ListProjects=Skipped RobotLifecycle=Skipped"
+	if results(got) != want {
+		t.Errorf("checks = %s, want %s", results(got), want)
+	}
+	c := got.GetCondition(xpv1.TypeReady)
+	if got.Status.Result != v1beta1.ConnectionTestFailed || c.Status != corev1.ConditionFalse || !strings.Contains(c.Message, "Authentication: 401") {
+		t.Errorf("status = %s, Ready = %s %q, want the authentication failure reported", got.Status.Result, c.Status, c.Message)
+	}
+}
+
+func TestConnectionTestNoCredentials(t *testing.T) {
+	got := runTest(t, newTest(nil), nil, errors.New("cannot get ProviderConfig"))
+
+	want := "Credentials=Failed Authentication=Skipped Version=Skipped ListProjects=Skipped RobotLifecycle=Skipped"
+	if results(got) != want {
+		t.Errorf("checks = %s, want %s", results(got), want)
+	}
+}
+
+func TestConnectionTestSkipsRobotWithoutSandbox(t *testing.T) {
+	svc := &harborclients.MockHarborClient{
+		CreateRobotFunc: func(context.Context, *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+			t.Error("CreateRobot() called without a sandbox project")
+			return nil, nil
+		},
+	}
+	got := runTest(t, newTest(nil), svc, nil)
+
+	if results(got) != "Credentials=Passed Authentication=Passed Version=Passed ListProjects=Passed RobotLifecycle=Skipped" {
+		t.Errorf("checks = %s, want the robot check skipped", results(got))
+	}
+	if got.Status.Result != v1beta1.ConnectionTestPassed {
+		t.Errorf("result = %s, want skipped checks not to fail the test", got.Status.Result)
+	}
+}
+
+func TestConnectionTestRunsOnce(t *testing.T) {
+	test := newTest(nil)
+	now := metav1.Now()
+	test.Status.CompletionTime = &now
+	test.Status.Result = v1beta1.ConnectionTestFailed
+
+	got := runTest(t, test, &harborclients.MockHarborClient{}, nil)
+	if got.Status.Result != v1beta1.ConnectionTestFailed || len(got.Status.Checks) != 0 {
+		t.Errorf("a completed test ran again: %s %s", got.Status.Result, results(got))
+	}
+}
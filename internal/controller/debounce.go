@@ -0,0 +1,148 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultUpdateDebounce is how long a spec must stay unchanged before it is
+// written to Harbor.
+const DefaultUpdateDebounce = 5 * time.Second
+
+// An updateDebouncer remembers when the spec of each managed resource last
+// changed, so that edits applied in quick succession reach Harbor as one
+// update of their final state.
+type updateDebouncer struct {
+	mu      sync.Mutex
+	delay   time.Duration
+	now     func() time.Time
+	changes map[types.UID]specChange
+}
+
+// A specChange is the generation of a managed resource's spec and when it
+// was first observed. A zero time means the change is already settled.
+type specChange struct {
+	generation int64
+	at         time.Time
+}
+
+var sharedDebouncer = &updateDebouncer{
+	delay:   DefaultUpdateDebounce,
+	now:     time.Now,
+	changes: map[types.UID]specChange{},
+}
+
+// SetUpdateDebounce changes how long a spec must stay unchanged before it is
+// written to Harbor. Zero or a negative delay updates Harbor at once.
+func SetUpdateDebounce(d time.Duration) {
+	sharedDebouncer.mu.Lock()
+	defer sharedDebouncer.mu.Unlock()
+	sharedDebouncer.delay = d
+}
+
+// observe records the generation of mg and returns how long its update must
+// still wait. A resource seen for the first time is settled, so that a
+// restarted provider does not hold back updates.
+func (d *updateDebouncer) observe(mg resource.Managed) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c, seen := d.changes[mg.GetUID()]
+	if gen := mg.GetGeneration(); !seen || c.generation != gen {
+		c = specChange{generation: gen}
+		if seen {
+			c.at = d.now()
+		}
+		d.changes[mg.GetUID()] = c
+	}
+	return d.wait(c)
+}
+
+// pending returns how long the update of mg must still wait.
+func (d *updateDebouncer) pending(mg resource.Managed) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c, ok := d.changes[mg.GetUID()]
+	if !ok || c.generation != mg.GetGeneration() {
+		return 0
+	}
+	return d.wait(c)
+}
+
+func (d *updateDebouncer) wait(c specChange) time.Duration {
+	if d.delay <= 0 || c.at.IsZero() {
+		return 0
+	}
+	if left := d.delay - d.now().Sub(c.at); left > 0 {
+		return left
+	}
+	return 0
+}
+
+func (d *updateDebouncer) forget(mg resource.Managed) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.changes, mg.GetUID())
+}
+
+// WithUpdateDebounce wraps c so that an existing resource whose spec changed
+// less than the debounce delay ago is reported as up to date. Its update is
+// held back until the spec has stayed unchanged for the delay, and each
+// further edit starts the wait again. Creation and deletion are not delayed.
+// Controllers using it must also use DebouncedPollInterval, which requeues
+// the resource when its wait ends.
+func WithUpdateDebounce(c managed.ExternalConnector) managed.ExternalConnector {
+	return &debounceConnector{ExternalConnector: c, debouncer: sharedDebouncer}
+}
+
+// DebouncedPollInterval is a managed.PollIntervalHook that requeues a
+// resource whose update WithUpdateDebounce holds back once its wait ends.
+func DebouncedPollInterval(mg resource.Managed, pollInterval time.Duration) time.Duration {
+	if left := sharedDebouncer.pending(mg); left > 0 && left < pollInterval {
+		return left
+	}
+	return pollInterval
+}
+
+type debounceConnector struct {
+	managed.ExternalConnector
+	debouncer *updateDebouncer
+}
+
+func (c *debounceConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &debounceClient{ExternalClient: ext, debouncer: c.debouncer}, nil
+}
+
+type debounceClient struct {
+	managed.ExternalClient
+	debouncer *updateDebouncer
+}
+
+func (e *debounceClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return obs, err
+	}
+	if meta.WasDeleted(mg) || !obs.ResourceExists {
+		e.debouncer.forget(mg)
+		return obs, nil
+	}
+	if left := e.debouncer.observe(mg); left > 0 && !obs.ResourceUpToDate {
+		obs.ResourceUpToDate = true
+	}
+	return obs, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// driftedClient reports that the resource exists and differs from its spec.
+type driftedClient struct {
+	managed.ExternalClient
+	exists bool
+}
+
+func (c *driftedClient) Observe(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+	return managed.ExternalObservation{ResourceExists: c.exists}, nil
+}
+
+func TestWithUpdateDebounce(t *testing.T) {
+	now := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	d := &updateDebouncer{delay: 5 * time.Second, now: func() time.Time { return now }, changes: map[types.UID]specChange{}}
+	ext := &driftedClient{exists: true}
+	e := &debounceClient{ExternalClient: ext, debouncer: d}
+
+	cr := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{UID: "p", Generation: 1}}
+	upToDate := func() bool {
+		t.Helper()
+		obs, err := e.Observe(context.Background(), cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return obs.ResourceUpToDate
+	}
+
+	if upToDate() {
+		t.Error("a resource seen for the first time was held back")
+	}
+
+	cr.SetGeneration(2)
+	if !upToDate() {
+		t.Error("an edited resource was updated at once")
+	}
+	if left := d.pending(cr); left != 5*time.Second {
+		t.Errorf("pending() = %v, want 5s", left)
+	}
+
+	now = now.Add(3 * time.Second)
+	cr.SetGeneration(3)
+	if !upToDate() {
+		t.Error("a resource edited again was updated")
+	}
+
+	now = now.Add(4 * time.Second)
+	if !upToDate() {
+		t.Error("the wait did not restart with the second edit")
+	}
+	if left := d.pending(cr); left != time.Second {
+		t.Errorf("pending() = %v, want 1s", left)
+	}
+
+	now = now.Add(time.Second)
+	if upToDate() {
+		t.Error("the update was held back after the spec settled")
+	}
+
+	// Creation is never held back, and a recreated resource starts settled.
+	ext.exists = false
+	cr.SetGeneration(4)
+	if upToDate() {
+		t.Error("a missing resource was reported as up to date")
+	}
+	ext.exists = true
+	if upToDate() {
+		t.Error("a recreated resource was held back")
+	}
+
+	d.delay = 0
+	cr.SetGeneration(5)
+	if upToDate() {
+		t.Error("the update was held back with the debounce disabled")
+	}
+}
+
+func TestDebouncedPollInterval(t *testing.T) {
+	cr := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{UID: "debounced", Generation: 1}}
+	if got := DebouncedPollInterval(cr, time.Minute); got != time.Minute {
+		t.Errorf("DebouncedPollInterval() of a settled resource = %v, want 1m", got)
+	}
+
+	sharedDebouncer.observe(cr)
+	cr.SetGeneration(2)
+	sharedDebouncer.observe(cr)
+	t.Cleanup(func() { sharedDebouncer.forget(cr) })
+	if got := DebouncedPollInterval(cr, time.Minute); got <= 0 || got > DefaultUpdateDebounce {
+		t.Errorf("DebouncedPollInterval() of an edited resource = %v, want at most %v", got, DefaultUpdateDebounce)
+	}
+	if got := DebouncedPollInterval(cr, time.Second); got != time.Second {
+		t.Errorf("DebouncedPollInterval() = %v, want the shorter poll interval", got)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+)
+
+// WithDeletionGuard wraps c so that a managed resource whose management
+// policies omit Delete is never deleted in Harbor. Once such a resource is
+// being deleted it is reported as gone without asking Harbor, and Delete does
+// nothing, so that only its finalizer is removed.
+//
+// The managed reconciler already skips deletion for such resources; the guard
+// is defence in depth for controllers whose Observe or Delete have side
+// effects, such as adopting or cleaning up Harbor objects, so that no change
+// to the reconciler or to a controller can touch an orphaned object.
+func WithDeletionGuard(c managed.ExternalConnector) managed.ExternalConnector {
+	return &deletionGuardConnector{ExternalConnector: c}
+}
+
+type deletionGuardConnector struct {
+	managed.ExternalConnector
+}
+
+func (c *deletionGuardConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &deletionGuardClient{ExternalClient: ext}, nil
+}
+
+type deletionGuardClient struct {
+	managed.ExternalClient
+}
+
+// orphaned reports whether mg is being deleted but must be left in Harbor.
+func orphaned(mg resource.Managed) bool {
+	return meta.WasDeleted(mg) && !ShouldDeleteExternal(mg)
+}
+
+func (e *deletionGuardClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	if orphaned(mg) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	return e.ExternalClient.Observe(ctx, mg)
+}
+
+func (e *deletionGuardClient) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	if !ShouldDeleteExternal(mg) {
+		return managed.ExternalDelete{}, nil
+	}
+	return e.ExternalClient.Delete(ctx, mg)
+}
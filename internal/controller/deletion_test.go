@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordingClient records which external operations were called.
+type recordingClient struct {
+	managed.ExternalClient
+	called []string
+}
+
+func (c *recordingClient) Observe(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+	c.called = append(c.called, OperationObserve)
+	return managed.ExternalObservation{ResourceExists: true}, nil
+}
+
+func (c *recordingClient) Delete(context.Context, resource.Managed) (managed.ExternalDelete, error) {
+	c.called = append(c.called, OperationDelete)
+	return managed.ExternalDelete{}, nil
+}
+
+type staticConnector struct {
+	ext managed.ExternalClient
+}
+
+func (c *staticConnector) Connect(context.Context, resource.Managed) (managed.ExternalClient, error) {
+	return c.ext, nil
+}
+
+func TestShouldDeleteExternal(t *testing.T) {
+	cases := map[string]struct {
+		policies xpv1.ManagementPolicies
+		want     bool
+	}{
+		"Unset":      {want: true},
+		"All":        {policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll}, want: true},
+		"WithDelete": {policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionDelete}, want: true},
+		"ObserveOnly": {
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+		},
+		"WithoutDelete": {
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionCreate, xpv1.ManagementActionUpdate, xpv1.ManagementActionLateInitialize},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &projectv1beta1.Project{}
+			cr.SetManagementPolicies(tc.policies)
+			if got := ShouldDeleteExternal(cr); got != tc.want {
+				t.Errorf("ShouldDeleteExternal() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithDeletionGuard(t *testing.T) {
+	orphan := xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionCreate, xpv1.ManagementActionUpdate}
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		policies   xpv1.ManagementPolicies
+		deleted    bool
+		wantExists bool
+		wantCalled []string
+	}{
+		"Live": {
+			policies:   orphan,
+			wantExists: true,
+			wantCalled: []string{OperationObserve},
+		},
+		"Deleted": {
+			deleted:    true,
+			wantExists: true,
+			wantCalled: []string{OperationObserve, OperationDelete},
+		},
+		"Orphaned": {
+			policies: orphan,
+			deleted:  true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &projectv1beta1.Project{}
+			cr.SetManagementPolicies(tc.policies)
+			if tc.deleted {
+				cr.SetDeletionTimestamp(&now)
+			}
+			rec := &recordingClient{}
+			ext, err := WithDeletionGuard(&staticConnector{ext: rec}).Connect(context.Background(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if obs.ResourceExists != tc.wantExists {
+				t.Errorf("Observe() ResourceExists = %v, want %v", obs.ResourceExists, tc.wantExists)
+			}
+			if _, err := ext.Delete(context.Background(), cr); err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.called) != len(tc.wantCalled) {
+				t.Errorf("called %v, want %v", rec.called, tc.wantCalled)
+			}
+		})
+	}
+}
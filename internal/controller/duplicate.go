@@ -0,0 +1,154 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	corev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExternalIdentityIndex indexes managed resources by the Harbor object they
+// manage, so that resources managing the same object can be found.
+const ExternalIdentityIndex = "harbor.crossplane.io/external-identity"
+
+const (
+	errIndexExternalIdentity = "cannot index managed resources by external identity"
+	errListDuplicates        = "cannot list managed resources with the same external identity"
+)
+
+// An IdentityFunc returns the name of the Harbor object mg manages, or an
+// empty string if it is not yet known.
+type IdentityFunc func(mg resource.Managed) string
+
+// ExternalIdentity returns the key mg is indexed under: the ProviderConfig it
+// uses, which decides the Harbor instance, and the name id returns. It is
+// empty if either is unknown.
+func ExternalIdentity(mg resource.Managed, id IdentityFunc) string {
+	pcr, ok := mg.(interface {
+		GetProviderConfigReference() *xpv1.ProviderConfigReference
+	})
+	if !ok || pcr.GetProviderConfigReference() == nil {
+		return ""
+	}
+	name := id(mg)
+	if name == "" {
+		return ""
+	}
+	return pcr.GetProviderConfigReference().Name + "/" + name
+}
+
+// IndexExternalIdentity adds ExternalIdentityIndex for the kind of obj to
+// indexer. It must be called before the manager starts.
+func IndexExternalIdentity(ctx context.Context, indexer client.FieldIndexer, obj client.Object, id IdentityFunc) error {
+	err := indexer.IndexField(ctx, obj, ExternalIdentityIndex, func(o client.Object) []string {
+		mg, ok := o.(resource.Managed)
+		if !ok {
+			return nil
+		}
+		if key := ExternalIdentity(mg, id); key != "" {
+			return []string{key}
+		}
+		return nil
+	})
+	return errors.Wrap(err, errIndexExternalIdentity)
+}
+
+// WithDuplicateDetection wraps c so that when two managed resources, in any
+// namespace, manage the same Harbor object through the same ProviderConfig,
+// only the older one reconciles it. The newer one gets a TerminalDuplicate
+// condition and is reported as existing and up to date, so that it neither
+// fights the older one over the object's settings nor deletes the object when
+// it is itself deleted. newList returns an empty list of the managed kind,
+// which must be indexed with IndexExternalIdentity.
+func WithDuplicateDetection(kube client.Reader, newList func() client.ObjectList, id IdentityFunc, c managed.ExternalConnector) managed.ExternalConnector {
+	return &duplicateConnector{ExternalConnector: c, kube: kube, newList: newList, id: id}
+}
+
+type duplicateConnector struct {
+	managed.ExternalConnector
+	kube    client.Reader
+	newList func() client.ObjectList
+	id      IdentityFunc
+}
+
+func (c *duplicateConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &duplicateClient{ExternalClient: ext, connector: c}, nil
+}
+
+type duplicateClient struct {
+	managed.ExternalClient
+	connector *duplicateConnector
+}
+
+// owner returns the namespace/name of the oldest other managed resource that
+// manages the same Harbor object as mg, if it is older than mg.
+func (e *duplicateClient) owner(ctx context.Context, mg resource.Managed) (string, error) {
+	key := ExternalIdentity(mg, e.connector.id)
+	if key == "" {
+		return "", nil
+	}
+	l := e.connector.newList()
+	if err := e.connector.kube.List(ctx, l, client.MatchingFields{ExternalIdentityIndex: key}); err != nil {
+		return "", errors.Wrap(err, errListDuplicates)
+	}
+	items, err := kmeta.ExtractList(l)
+	if err != nil {
+		return "", errors.Wrap(err, errListDuplicates)
+	}
+	var oldest client.Object = mg
+	for _, item := range items {
+		o, ok := item.(client.Object)
+		if !ok || o.GetUID() == mg.GetUID() || meta.WasDeleted(o) {
+			continue
+		}
+		if older(o, oldest) {
+			oldest = o
+		}
+	}
+	if oldest == client.Object(mg) {
+		return "", nil
+	}
+	return client.ObjectKeyFromObject(oldest).String(), nil
+}
+
+// older reports whether a was created before b. Resources created in the same
+// second are ordered by namespace and name, so that every reconcile agrees on
+// which of them is the owner.
+func older(a, b client.Object) bool {
+	at, bt := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !at.Equal(&bt) {
+		return at.Before(&bt)
+	}
+	return client.ObjectKeyFromObject(a).String() < client.ObjectKeyFromObject(b).String()
+}
+
+func (e *duplicateClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	owner, err := e.owner(ctx, mg)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if owner != "" {
+		mg.SetConditions(common.TerminalDuplicate(owner))
+		// A deleted duplicate must leave the object to its owner.
+		return managed.ExternalObservation{ResourceExists: !meta.WasDeleted(mg), ResourceUpToDate: true}, nil
+	}
+	if mg.GetCondition(common.TypeTerminalDuplicate).Status == corev1.ConditionTrue {
+		mg.SetConditions(common.NotDuplicate())
+	}
+	return e.ExternalClient.Observe(ctx, mg)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/common"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func projectName(mg resource.Managed) string {
+	return mg.(*projectv1beta1.Project).Spec.ForProvider.Name
+}
+
+// managedProject returns a Project in namespace ns managing the Harbor project
+// web through the ProviderConfig pc, created age ago.
+func managedProject(ns, pc string, age time.Duration) *projectv1beta1.Project {
+	cr := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{
+		Name:              "web",
+		Namespace:         ns,
+		UID:               types.UID(ns),
+		CreationTimestamp: metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(-age)),
+	}}
+	cr.Spec.ForProvider.Name = "web"
+	cr.SetProviderConfigReference(&xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: pc})
+	return cr
+}
+
+func TestWithDuplicateDetection(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := projectv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	index := func(o client.Object) []string {
+		if key := ExternalIdentity(o.(resource.Managed), projectName); key != "" {
+			return []string{key}
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		others     []client.Object
+		deleted    bool
+		wasDup     bool
+		wantDup    corev1.ConditionStatus
+		wantExists bool
+		wantCalled int
+	}{
+		"Sole": {
+			wantDup:    corev1.ConditionUnknown,
+			wantExists: true,
+			wantCalled: 1,
+		},
+		"NewerDuplicate": {
+			others:     []client.Object{managedProject("team-b", "default", time.Minute)},
+			wantDup:    corev1.ConditionUnknown,
+			wantExists: true,
+			wantCalled: 1,
+		},
+		"OlderOwner": {
+			others:     []client.Object{managedProject("team-b", "default", time.Hour)},
+			wantDup:    corev1.ConditionTrue,
+			wantExists: true,
+		},
+		"DeletedDuplicate": {
+			others:  []client.Object{managedProject("team-b", "default", time.Hour)},
+			deleted: true,
+			wantDup: corev1.ConditionTrue,
+		},
+		"OtherHarbor": {
+			others:     []client.Object{managedProject("team-b", "staging", time.Hour)},
+			wantDup:    corev1.ConditionUnknown,
+			wantExists: true,
+			wantCalled: 1,
+		},
+		"OwnerGone": {
+			wasDup:     true,
+			wantDup:    corev1.ConditionFalse,
+			wantExists: true,
+			wantCalled: 1,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := managedProject("team-a", "default", 30*time.Minute)
+			if tc.deleted {
+				now := metav1.Now()
+				cr.SetDeletionTimestamp(&now)
+			}
+			if tc.wasDup {
+				cr.SetConditions(common.TerminalDuplicate("team-b/web"))
+			}
+			kube := fake.NewClientBuilder().WithScheme(s).
+				WithObjects(tc.others...).
+				WithIndex(&projectv1beta1.Project{}, ExternalIdentityIndex, index).
+				Build()
+			rec := &recordingClient{}
+			newList := func() client.ObjectList { return &projectv1beta1.ProjectList{} }
+			ext, err := WithDuplicateDetection(kube, newList, projectName, &staticConnector{ext: rec}).Connect(context.Background(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Observe() error = %v", err)
+			}
+			if obs.ResourceExists != tc.wantExists {
+				t.Errorf("ResourceExists = %v, want %v", obs.ResourceExists, tc.wantExists)
+			}
+			if len(rec.called) != tc.wantCalled {
+				t.Errorf("inner Observe called %d times, want %d", len(rec.called), tc.wantCalled)
+			}
+			if got := cr.GetCondition(common.TypeTerminalDuplicate).Status; got != tc.wantDup {
+				t.Errorf("TerminalDuplicate = %v, want %v", got, tc.wantDup)
+			}
+		})
+	}
+}
+
+func TestExternalIdentity(t *testing.T) {
+	cr := managedProject("team-a", "default", 0)
+	if got := ExternalIdentity(cr, projectName); got != "default/web" {
+		t.Errorf("ExternalIdentity() = %q, want %q", got, "default/web")
+	}
+	cr.SetProviderConfigReference(nil)
+	if got := ExternalIdentity(cr, projectName); got != "" {
+		t.Errorf("ExternalIdentity() without a ProviderConfig = %q, want none", got)
+	}
+}
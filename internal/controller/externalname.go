@@ -5,12 +5,24 @@ Copyright 2024 Crossplane Harbor Provider.
 package controller
 
 import (
+	"context"
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	// ExternalNameAnnotation is the annotation key used to store the external identifier
 	ExternalNameAnnotation = "crossplane.io/external-name"
+
+	errPatchExternalName = "cannot record external name"
 )
 
 // GetExternalName retrieves the external name from resource annotations
@@ -33,11 +45,92 @@ func HasExternalName(mg resource.Managed) bool {
 	return GetExternalName(mg) != ""
 }
 
-// ShouldDeleteExternal checks if we should delete the external resource
-// Returns false if deletion policy is Orphan, true otherwise
+// PatchExternalName records name as the external name of mg with a merge
+// patch of that one annotation, so that other annotations written since mg
+// was read are kept. Conflicts are retried. mg is updated to the stored
+// resource version, leaving the rest of it, including any status set since
+// it was read, alone.
+func PatchExternalName(ctx context.Context, kube client.Client, mg resource.Managed, name string) error {
+	return errors.Wrap(patchAnnotations(ctx, kube, mg, map[string]string{ExternalNameAnnotation: name}), errPatchExternalName)
+}
+
+// patchAnnotations sets annotations on mg as PatchExternalName sets the
+// external name.
+func patchAnnotations(ctx context.Context, kube client.Client, mg resource.Managed, annotations map[string]string) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": annotations},
+	})
+	if err != nil {
+		return err
+	}
+	o, ok := mg.DeepCopyObject().(client.Object)
+	if !ok {
+		return errors.New("managed resource is not an object")
+	}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return kube.Patch(ctx, o, client.RawPatch(types.MergePatchType, patch))
+	})
+	if err != nil {
+		return err
+	}
+	meta.AddAnnotations(mg, annotations)
+	mg.SetResourceVersion(o.GetResourceVersion())
+	return nil
+}
+
+// WithExternalNamePatches wraps c so that an external name Observe records,
+// typically on adopting an existing Harbor object, is persisted. The managed
+// reconciler only saves the external names Create sets; one set by Observe
+// would otherwise be dropped with the rest of the metadata when only the
+// status is written back.
+func WithExternalNamePatches(kube client.Client, c managed.ExternalConnector) managed.ExternalConnector {
+	return &externalNameConnector{ExternalConnector: c, kube: kube}
+}
+
+type externalNameConnector struct {
+	managed.ExternalConnector
+	kube client.Client
+}
+
+func (c *externalNameConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &externalNameClient{ExternalClient: ext, kube: c.kube}, nil
+}
+
+type externalNameClient struct {
+	managed.ExternalClient
+	kube client.Client
+}
+
+func (e *externalNameClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	before := GetExternalName(mg)
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return obs, err
+	}
+	if after := GetExternalName(mg); after != "" && after != before {
+		if err := PatchExternalName(ctx, e.kube, mg, after); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
+	return obs, nil
+}
+
+// ShouldDeleteExternal reports whether the management policies of mg allow
+// the external resource to be deleted. Resources whose policies omit Delete
+// are orphaned: deleting them leaves the Harbor object in place.
 func ShouldDeleteExternal(mg resource.Managed) bool {
-	// Check if resource has DeletionPolicy field
-	// In Crossplane, Orphan deletion policy means don't delete the external resource
-	// For now, we'll use a simple approach: always delete unless explicitly marked
-	return true
+	p := mg.GetManagementPolicies()
+	if len(p) == 0 {
+		return true
+	}
+	for _, a := range p {
+		if a == xpv1.ManagementActionDelete || a == xpv1.ManagementActionAll {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// stored returns a fake API server holding a Project that another writer has
+// annotated since it was read, and the copy that was read. Patches fail with
+// a conflict the first conflicts times.
+func stored(t *testing.T, conflicts int) (client.Client, *projectv1beta1.Project) {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := projectv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	read := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "harbor"}}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(read).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, o client.Object, p client.Patch, opts ...client.PatchOption) error {
+				if conflicts > 0 {
+					conflicts--
+					return kerrors.NewConflict(schema.GroupResource{Resource: "projects"}, o.GetName(), nil)
+				}
+				return c.Patch(ctx, o, p, opts...)
+			},
+		}).Build()
+	if err := kube.Get(context.Background(), client.ObjectKeyFromObject(read), read); err != nil {
+		t.Fatal(err)
+	}
+
+	other := read.DeepCopy()
+	other.SetAnnotations(map[string]string{"example.org/owner": "team-a"})
+	if err := kube.Update(context.Background(), other); err != nil {
+		t.Fatal(err)
+	}
+	return kube, read
+}
+
+func TestPatchExternalName(t *testing.T) {
+	cases := map[string]struct {
+		conflicts int
+		wantErr   bool
+	}{
+		"Patched":         {},
+		"RetriedConflict": {conflicts: 2},
+		"KeptConflicting": {conflicts: 100, wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			kube, cr := stored(t, tc.conflicts)
+			id := "42"
+			cr.Status.AtProvider.ID = &id
+
+			err := PatchExternalName(ctx, kube, cr, "web")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("PatchExternalName() error = %v, want error: %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if cr.Status.AtProvider.ID == nil || *cr.Status.AtProvider.ID != id {
+				t.Errorf("status was overwritten: %+v", cr.Status.AtProvider)
+			}
+
+			got := &projectv1beta1.Project{}
+			if err := kube.Get(ctx, client.ObjectKeyFromObject(cr), got); err != nil {
+				t.Fatal(err)
+			}
+			if GetExternalName(got) != "web" {
+				t.Errorf("stored external name = %q, want web", GetExternalName(got))
+			}
+			if got.GetAnnotations()["example.org/owner"] != "team-a" {
+				t.Errorf("annotation written by another client was lost: %v", got.GetAnnotations())
+			}
+			if cr.GetResourceVersion() != got.GetResourceVersion() {
+				t.Errorf("resourceVersion = %s, want stored %s", cr.GetResourceVersion(), got.GetResourceVersion())
+			}
+		})
+	}
+}
+
+// namingClient records name as the external name when observing.
+type namingClient struct {
+	managed.ExternalClient
+	name string
+}
+
+func (c *namingClient) Observe(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	if c.name != "" {
+		SetExternalName(mg, c.name)
+	}
+	return managed.ExternalObservation{ResourceExists: true}, nil
+}
+
+func TestWithExternalNamePatches(t *testing.T) {
+	cases := map[string]struct {
+		existing  string
+		observed  string
+		wantPatch bool
+	}{
+		"Adopted":   {observed: "web", wantPatch: true},
+		"Unchanged": {existing: "web", observed: "web"},
+		"Unset":     {},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			kube, cr := stored(t, 0)
+			if tc.existing != "" {
+				SetExternalName(cr, tc.existing)
+			}
+			rv := cr.GetResourceVersion()
+
+			ext, err := WithExternalNamePatches(kube, &staticConnector{ext: &namingClient{name: tc.observed}}).Connect(ctx, cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ext.Observe(ctx, cr); err != nil {
+				t.Fatal(err)
+			}
+
+			got := &projectv1beta1.Project{}
+			if err := kube.Get(ctx, client.ObjectKeyFromObject(cr), got); err != nil {
+				t.Fatal(err)
+			}
+			if patched := GetExternalName(got) != ""; patched != tc.wantPatch {
+				t.Errorf("patched = %v, want %v", patched, tc.wantPatch)
+			}
+			if !tc.wantPatch && cr.GetResourceVersion() != rv {
+				t.Errorf("resourceVersion changed without a patch")
+			}
+		})
+	}
+}
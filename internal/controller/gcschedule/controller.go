@@ -0,0 +1,227 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package gcschedule manages the garbage collection schedule of a Harbor
+// instance.
+package gcschedule
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotGCSchedule = "managed resource is not a GarbageCollectionSchedule custom resource"
+	errNewClient     = "cannot create new Service"
+	errGetSchedule   = "cannot get Harbor garbage collection schedule"
+	errCreate        = "cannot create Harbor garbage collection schedule"
+	errUpdate        = "cannot update Harbor garbage collection schedule"
+	errDelete        = "cannot stop Harbor garbage collection schedule"
+)
+
+// Setup adds a controller that reconciles GarbageCollectionSchedule managed
+// resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.GarbageCollectionScheduleGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), clients.CapabilityManageSystem, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:         mgr.GetClient(),
+			logger:       log,
+			newServiceFn: clients.NewHarborClientFromProviderConfig,
+		}))))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(10 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.GarbageCollectionScheduleGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.GarbageCollectionSchedule{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube         client.Client
+	logger       logging.Logger
+	newServiceFn func(ctx context.Context, kube client.Client, mg resource.Managed) (clients.HarborClienter, error)
+}
+
+// Connect produces an ExternalClient by creating a Harbor client
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.GarbageCollectionSchedule); !ok {
+		return nil, errors.New(errNotGCSchedule)
+	}
+
+	harborClient, err := c.newServiceFn(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{service: harborClient, logger: c.logger}, nil
+}
+
+// external applies a GarbageCollectionSchedule to Harbor's garbage
+// collection schedule. The schedule exists while its type is not None.
+type external struct {
+	service clients.HarborClienter
+	logger  logging.Logger
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "gcschedule.observe",
+		tracing.SpanAttrs("GarbageCollectionSchedule", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.GarbageCollectionSchedule)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotGCSchedule)
+	}
+
+	s, err := c.service.GetGCSchedule(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetSchedule)
+	}
+	if !s.Scheduled() {
+		cr.Status.AtProvider = v1beta1.GarbageCollectionScheduleObservation{}
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	cr.Status.AtProvider = observation(s)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(cr.Spec.ForProvider, s),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, span := tracing.StartSpan(ctx, "gcschedule.create",
+		tracing.SpanAttrs("GarbageCollectionSchedule", tracing.ResourceName(mg), "create")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.GarbageCollectionSchedule)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotGCSchedule)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	if err := c.service.CreateGCSchedule(ctx, desired(cr.Spec.ForProvider)); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
+	}
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "gcschedule.update",
+		tracing.SpanAttrs("GarbageCollectionSchedule", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.GarbageCollectionSchedule)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotGCSchedule)
+	}
+
+	if err := c.service.UpdateGCSchedule(ctx, desired(cr.Spec.ForProvider)); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete stops scheduled garbage collection.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	_, span := tracing.StartSpan(ctx, "gcschedule.delete",
+		tracing.SpanAttrs("GarbageCollectionSchedule", tracing.ResourceName(mg), "delete")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.GarbageCollectionSchedule)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotGCSchedule)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	if err := c.service.UpdateGCSchedule(ctx, &clients.GCSchedule{Type: clients.GCScheduleTypeNone}); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errDelete)
+	}
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
+
+// desired returns the schedule p describes.
+func desired(p v1beta1.GarbageCollectionScheduleParameters) *clients.GCSchedule {
+	s := &clients.GCSchedule{Type: clients.GCScheduleTypeCustom, Cron: normalizeCron(p.Cron)}
+	if p.DeleteUntagged != nil {
+		s.DeleteUntagged = *p.DeleteUntagged
+	}
+	if p.Workers != nil {
+		s.Workers = *p.Workers
+	}
+	if p.DryRun != nil {
+		s.DryRun = *p.DryRun
+	}
+	return s
+}
+
+// observation returns the status representation of s.
+func observation(s *clients.GCSchedule) v1beta1.GarbageCollectionScheduleObservation {
+	o := v1beta1.GarbageCollectionScheduleObservation{
+		Type:           &s.Type,
+		Cron:           &s.Cron,
+		DeleteUntagged: &s.DeleteUntagged,
+		DryRun:         &s.DryRun,
+	}
+	if s.Workers > 0 {
+		o.Workers = &s.Workers
+	}
+	if !s.NextScheduledTime.IsZero() {
+		t := metav1.NewTime(s.NextScheduledTime)
+		o.NextScheduledTime = &t
+	}
+	return o
+}
+
+// isUpToDate reports whether the observed schedule matches p. The schedule
+// type is not compared, so a schedule set in the Harbor UI with the same
+// cron expression is up to date. Workers are only compared when set.
+func isUpToDate(p v1beta1.GarbageCollectionScheduleParameters, observed *clients.GCSchedule) bool {
+	want := desired(p)
+	if want.Cron != normalizeCron(observed.Cron) {
+		return false
+	}
+	if want.DeleteUntagged != observed.DeleteUntagged || want.DryRun != observed.DryRun {
+		return false
+	}
+	return p.Workers == nil || *p.Workers == observed.Workers
+}
+
+// normalizeCron returns cron with its fields separated by single spaces.
+func normalizeCron(cron string) string {
+	return strings.Join(strings.Fields(cron), " ")
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package gcschedule
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/config/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestObserve(t *testing.T) {
+	current := &harborclients.GCSchedule{Type: "Weekly", Cron: "0 0 0 * * 0", DeleteUntagged: true, Workers: 2}
+
+	cases := map[string]struct {
+		params   v1beta1.GarbageCollectionScheduleParameters
+		current  *harborclients.GCSchedule
+		getErr   error
+		wantErr  bool
+		exists   bool
+		upToDate bool
+	}{
+		"Matching": {
+			params:   v1beta1.GarbageCollectionScheduleParameters{Cron: "0 0 0 * * 0", DeleteUntagged: ptr(true), Workers: ptr(int64(2))},
+			current:  current,
+			exists:   true,
+			upToDate: true,
+		},
+		"CronSpacing": {
+			params:   v1beta1.GarbageCollectionScheduleParameters{Cron: " 0 0 0  * * 0", DeleteUntagged: ptr(true)},
+			current:  current,
+			exists:   true,
+			upToDate: true,
+		},
+		"CronChanged": {
+			params:  v1beta1.GarbageCollectionScheduleParameters{Cron: "0 0 2 * * 6", DeleteUntagged: ptr(true)},
+			current: current,
+			exists:  true,
+		},
+		"WorkersChanged": {
+			params:  v1beta1.GarbageCollectionScheduleParameters{Cron: "0 0 0 * * 0", DeleteUntagged: ptr(true), Workers: ptr(int64(4))},
+			current: current,
+			exists:  true,
+		},
+		"DeleteUntaggedUnsetMeansFalse": {
+			params:  v1beta1.GarbageCollectionScheduleParameters{Cron: "0 0 0 * * 0"},
+			current: current,
+			exists:  true,
+		},
+		"DryRunChanged": {
+			params:  v1beta1.GarbageCollectionScheduleParameters{Cron: "0 0 0 * * 0", DeleteUntagged: ptr(true), DryRun: ptr(true)},
+			current: current,
+			exists:  true,
+		},
+		"NotScheduled": {
+			params:  v1beta1.GarbageCollectionScheduleParameters{Cron: "0 0 0 * * 0"},
+			current: &harborclients.GCSchedule{Type: harborclients.GCScheduleTypeNone},
+		},
+		"GetError": {
+			getErr:  errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.GarbageCollectionSchedule{Spec: v1beta1.GarbageCollectionScheduleSpec{ForProvider: tc.params}}
+			ext := &external{
+				service: &harborclients.MockHarborClient{
+					GetGCScheduleFunc: func(context.Context) (*harborclients.GCSchedule, error) {
+						return tc.current, tc.getErr
+					},
+				},
+				logger: logging.NewNopLogger(),
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if obs.ResourceExists != tc.exists {
+				t.Errorf("ResourceExists = %v, want %v", obs.ResourceExists, tc.exists)
+			}
+			if obs.ResourceUpToDate != tc.upToDate {
+				t.Errorf("ResourceUpToDate = %v, want %v", obs.ResourceUpToDate, tc.upToDate)
+			}
+			if !tc.exists {
+				return
+			}
+			if cr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+				t.Error("GarbageCollectionSchedule should be Ready once observed")
+			}
+			if at := cr.Status.AtProvider; at.Type == nil || *at.Type != "Weekly" || at.Workers == nil || *at.Workers != 2 {
+				t.Errorf("AtProvider = %+v", at)
+			}
+		})
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	var created, updated []harborclients.GCSchedule
+	ext := &external{
+		service: &harborclients.MockHarborClient{
+			CreateGCScheduleFunc: func(_ context.Context, s *harborclients.GCSchedule) error {
+				created = append(created, *s)
+				return nil
+			},
+			UpdateGCScheduleFunc: func(_ context.Context, s *harborclients.GCSchedule) error {
+				updated = append(updated, *s)
+				return nil
+			},
+		},
+		logger: logging.NewNopLogger(),
+	}
+	cr := &v1beta1.GarbageCollectionSchedule{Spec: v1beta1.GarbageCollectionScheduleSpec{ForProvider: v1beta1.GarbageCollectionScheduleParameters{
+		Cron:           "0 0 2  * * 6",
+		DeleteUntagged: ptr(true),
+		Workers:        ptr(int64(3)),
+	}}}
+	ctx := context.Background()
+
+	if _, err := ext.Create(ctx, cr); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := ext.Update(ctx, cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := ext.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	want := harborclients.GCSchedule{Type: harborclients.GCScheduleTypeCustom, Cron: "0 0 2 * * 6", DeleteUntagged: true, Workers: 3}
+	if !reflect.DeepEqual(created, []harborclients.GCSchedule{want}) {
+		t.Errorf("created %+v, want %+v", created, want)
+	}
+	wantUpdated := []harborclients.GCSchedule{want, {Type: harborclients.GCScheduleTypeNone}}
+	if !reflect.DeepEqual(updated, wantUpdated) {
+		t.Errorf("updated %+v, want %+v", updated, wantUpdated)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errCreateLookup   = "cannot look for an existing object before creating it"
+	errCreateNotFound = "created object cannot be found"
+)
+
+// CreateOnce creates an external object that Harbor does not deduplicate,
+// such as a robot account or webhook policy, so that a retried Create never
+// makes a second one. lookup must find the object by the name Create gives
+// it, returning nil if there is none.
+//
+// An existing object is returned without calling create; it was made by an
+// earlier Create whose response was lost. A failed create is looked up
+// again, because a request that timed out may still have succeeded. A
+// successful create is looked up to verify that the object is visible, so
+// that the next Observe does not create it again; the object create
+// returned is kept, as it may hold fields Harbor only reveals once.
+func CreateOnce[T any](ctx context.Context, lookup func(context.Context) (*T, error), create func(context.Context) (*T, error)) (*T, error) {
+	existing, err := lookup(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateLookup)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	created, err := create(ctx)
+	if err != nil {
+		if existing, lerr := lookup(ctx); lerr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	found, err := lookup(ctx)
+	if err != nil {
+		// The create succeeded; the next Observe will look again.
+		return created, nil
+	}
+	if found == nil {
+		return nil, errors.New(errCreateNotFound)
+	}
+	if created == nil {
+		return found, nil
+	}
+	return created, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type object struct {
+	ID     string
+	Secret string
+}
+
+// fakeHarbor holds at most one object and counts create calls.
+type fakeHarbor struct {
+	stored    *object
+	creates   int
+	createErr error
+	// persist stores the object even when create fails, as a request that
+	// times out after Harbor handled it does.
+	persist   bool
+	lookupErr error
+}
+
+func (f *fakeHarbor) lookup(context.Context) (*object, error) {
+	if f.lookupErr != nil {
+		return nil, f.lookupErr
+	}
+	if f.stored == nil {
+		return nil, nil
+	}
+	o := *f.stored
+	o.Secret = ""
+	return &o, nil
+}
+
+func (f *fakeHarbor) create(context.Context) (*object, error) {
+	f.creates++
+	if f.createErr == nil || f.persist {
+		f.stored = &object{ID: "7", Secret: "s3cr3t"}
+	}
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	return &object{ID: "7", Secret: "s3cr3t"}, nil
+}
+
+func TestCreateOnce(t *testing.T) {
+	timeout := errors.New("context deadline exceeded")
+
+	cases := map[string]struct {
+		harbor      *fakeHarbor
+		wantCreates int
+		want        *object
+		wantErr     bool
+	}{
+		"Created": {
+			harbor:      &fakeHarbor{},
+			wantCreates: 1,
+			want:        &object{ID: "7", Secret: "s3cr3t"},
+		},
+		"AlreadyExists": {
+			harbor:      &fakeHarbor{stored: &object{ID: "7"}},
+			wantCreates: 0,
+			want:        &object{ID: "7"},
+		},
+		"TimedOutButCreated": {
+			harbor:      &fakeHarbor{createErr: timeout, persist: true},
+			wantCreates: 1,
+			want:        &object{ID: "7"},
+		},
+		"Failed": {
+			harbor:      &fakeHarbor{createErr: timeout},
+			wantCreates: 1,
+			wantErr:     true,
+		},
+		"LookupFailed": {
+			harbor:      &fakeHarbor{lookupErr: errors.New("boom")},
+			wantCreates: 0,
+			wantErr:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CreateOnce(context.Background(), tc.harbor.lookup, tc.harbor.create)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CreateOnce() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.harbor.creates != tc.wantCreates {
+				t.Errorf("create called %d times, want %d", tc.harbor.creates, tc.wantCreates)
+			}
+			if tc.want != nil && (got == nil || *got != *tc.want) {
+				t.Errorf("CreateOnce() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCreateOnceNotVisible(t *testing.T) {
+	create := func(context.Context) (*object, error) { return &object{ID: "7"}, nil }
+	lookup := func(context.Context) (*object, error) { return nil, nil }
+
+	if _, err := CreateOnce(context.Background(), lookup, create); err == nil {
+		t.Error("CreateOnce() should fail when the created object cannot be found")
+	}
+}
+
+func TestCreateOnceRetried(t *testing.T) {
+	h := &fakeHarbor{createErr: errors.New("EOF"), persist: true}
+	for i := 0; i < 3; i++ {
+		if _, err := CreateOnce(context.Background(), h.lookup, h.create); err != nil {
+			t.Fatalf("attempt %d: CreateOnce() error = %v", i, err)
+		}
+	}
+	if h.creates != 1 {
+		t.Errorf("create called %d times over three retries, want 1", h.creates)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package immutabletagrule manages the immutable tag rules of Harbor
+// projects.
+package immutabletagrule
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotImmutableTagRule = "managed resource is not an ImmutableTagRule custom resource"
+	errNewClient           = "cannot create new Harbor client"
+	errList                = "cannot list Harbor immutable tag rules"
+	errCreate              = "cannot create Harbor immutable tag rule"
+	errUpdate              = "cannot update Harbor immutable tag rule"
+	errDelete              = "cannot delete Harbor immutable tag rule"
+	errNoID                = "immutable tag rule ID not set"
+)
+
+// Setup adds a controller that reconciles ImmutableTagRule managed
+// resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.ImmutableTagRuleGroupVersionKind.Kind)
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithProtection(mgr.GetClient(), ruleTarget, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:         mgr.GetClient(),
+			newServiceFn: clients.NewHarborClientFromProviderConfig,
+		}))))))))),
+		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ImmutableTagRuleGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		WithEventFilter(resource.DesiredStateChanged()).
+		For(&v1beta1.ImmutableTagRule{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// ruleTarget returns the project an ImmutableTagRule belongs to.
+func ruleTarget(mg resource.Managed) ctrlutil.Target {
+	cr, ok := mg.(*v1beta1.ImmutableTagRule)
+	if !ok {
+		return ctrlutil.Target{}
+	}
+	return ctrlutil.Target{Project: cr.Spec.ForProvider.ProjectID}
+}
+
+type connector struct {
+	kube         client.Client
+	newServiceFn func(context.Context, client.Client, resource.Managed) (clients.HarborClienter, error)
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ImmutableTagRule); !ok {
+		return nil, errors.New(errNotImmutableTagRule)
+	}
+
+	svc, err := c.newServiceFn(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{service: svc}, nil
+}
+
+// external applies an ImmutableTagRule to a rule of its Harbor project.
+// Harbor rules have no name, so a rule is known by the ID recorded when it
+// was created, and a rule with the same selectors is adopted.
+type external struct {
+	service clients.HarborClienter
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "immutabletagrule.observe",
+		tracing.SpanAttrs("ImmutableTagRule", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ImmutableTagRule)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotImmutableTagRule)
+	}
+
+	rules, err := c.service.ListImmutableTagRules(ctx, cr.Spec.ForProvider.ProjectID)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errList)
+	}
+	want := desired(cr.Spec.ForProvider)
+	rule := findRule(rules, ruleID(cr), want)
+	if rule == nil {
+		cr.Status.AtProvider = v1beta1.ImmutableTagRuleObservation{}
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	enabled := !rule.Disabled
+	cr.Status.AtProvider = v1beta1.ImmutableTagRuleObservation{ID: &rule.ID, Enabled: &enabled}
+	ctrlutil.SetExternalName(cr, strconv.FormatInt(rule.ID, 10))
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: rule.SameSelectors(want) && rule.Disabled == want.Disabled,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, span := tracing.StartSpan(ctx, "immutabletagrule.create",
+		tracing.SpanAttrs("ImmutableTagRule", tracing.ResourceName(mg), "create")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ImmutableTagRule)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotImmutableTagRule)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	id, err := c.service.CreateImmutableTagRule(ctx, cr.Spec.ForProvider.ProjectID, desired(cr.Spec.ForProvider))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreate)
+	}
+	cr.Status.AtProvider.ID = &id
+	ctrlutil.SetExternalName(cr, strconv.FormatInt(id, 10))
+	return managed.ExternalCreation{}, nil
+}
+
+// Update changes the rule in place, so enabling or disabling it keeps its
+// ID.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "immutabletagrule.update",
+		tracing.SpanAttrs("ImmutableTagRule", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ImmutableTagRule)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotImmutableTagRule)
+	}
+	if cr.Status.AtProvider.ID == nil {
+		return managed.ExternalUpdate{}, errors.New(errNoID)
+	}
+
+	rule := desired(cr.Spec.ForProvider)
+	rule.ID = *cr.Status.AtProvider.ID
+	if err := c.service.UpdateImmutableTagRule(ctx, cr.Spec.ForProvider.ProjectID, rule); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdate)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	_, span := tracing.StartSpan(ctx, "immutabletagrule.delete",
+		tracing.SpanAttrs("ImmutableTagRule", tracing.ResourceName(mg), "delete")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ImmutableTagRule)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotImmutableTagRule)
+	}
+	if cr.Status.AtProvider.ID == nil {
+		return managed.ExternalDelete{}, nil
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	if err := c.service.DeleteImmutableTagRule(ctx, cr.Spec.ForProvider.ProjectID, *cr.Status.AtProvider.ID); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errDelete)
+	}
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
+
+// desired returns the rule p describes.
+func desired(p v1beta1.ImmutableTagRuleParameters) *clients.ImmutableTagRule {
+	return &clients.ImmutableTagRule{
+		RepositoryPattern:   clients.SelectorPattern(p.RepositorySelectors),
+		ExcludeRepositories: p.ExcludeRepositories != nil && *p.ExcludeRepositories,
+		TagPattern:          clients.SelectorPattern(p.TagSelectors),
+		ExcludeTags:         p.ExcludeTags != nil && *p.ExcludeTags,
+		Disabled:            p.Enabled != nil && !*p.Enabled,
+	}
+}
+
+// ruleID returns the ID of cr's rule: the one recorded in its status, or
+// else its external name, which survives the status being lost. It is zero
+// when cr has no rule yet.
+func ruleID(cr *v1beta1.ImmutableTagRule) int64 {
+	if cr.Status.AtProvider.ID != nil {
+		return *cr.Status.AtProvider.ID
+	}
+	id, err := strconv.ParseInt(ctrlutil.GetExternalName(cr), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// findRule returns the rule with the given ID, or without one, the first
+// rule with want's selectors.
+func findRule(rules []*clients.ImmutableTagRule, id int64, want *clients.ImmutableTagRule) *clients.ImmutableTagRule {
+	for _, r := range rules {
+		if id != 0 && r.ID == id {
+			return r
+		}
+		if id == 0 && r.SameSelectors(want) {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package immutabletagrule
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestObserve(t *testing.T) {
+	params := v1beta1.ImmutableTagRuleParameters{
+		ProjectID:           "3",
+		RepositorySelectors: []string{"library/*"},
+		TagSelectors:        []string{"v*", "release-*"},
+	}
+	rule := &harborclients.ImmutableTagRule{ID: 7, RepositoryPattern: "library/*", TagPattern: "{release-*,v*}"}
+
+	cases := map[string]struct {
+		params   v1beta1.ImmutableTagRuleParameters
+		id       *int64
+		name     string
+		rules    []*harborclients.ImmutableTagRule
+		listErr  error
+		wantErr  bool
+		wantID   int64
+		upToDate bool
+	}{
+		"AdoptsSameSelectors": {
+			params:   params,
+			rules:    []*harborclients.ImmutableTagRule{{ID: 6, RepositoryPattern: "**", TagPattern: "**"}, rule},
+			wantID:   7,
+			upToDate: true,
+		},
+		"ByStatusID": {
+			params: params,
+			id:     ptr(int64(6)),
+			rules:  []*harborclients.ImmutableTagRule{{ID: 6, RepositoryPattern: "**", TagPattern: "**"}, rule},
+			wantID: 6,
+		},
+		"ByExternalName": {
+			params:   params,
+			name:     "7",
+			rules:    []*harborclients.ImmutableTagRule{rule},
+			wantID:   7,
+			upToDate: true,
+		},
+		"Disabled": {
+			params: v1beta1.ImmutableTagRuleParameters{
+				ProjectID:           "3",
+				RepositorySelectors: []string{"library/*"},
+				TagSelectors:        []string{"v*", "release-*"},
+				Enabled:             ptr(false),
+			},
+			rules:  []*harborclients.ImmutableTagRule{rule},
+			wantID: 7,
+		},
+		"Deleted": {
+			params: params,
+			id:     ptr(int64(9)),
+			rules:  []*harborclients.ImmutableTagRule{rule},
+		},
+		"ListError": {
+			params:  params,
+			listErr: errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.ImmutableTagRule{Spec: v1beta1.ImmutableTagRuleSpec{ForProvider: tc.params}}
+			cr.Status.AtProvider.ID = tc.id
+			if tc.name != "" {
+				ctrlutil.SetExternalName(cr, tc.name)
+			}
+			ext := &external{service: &harborclients.MockHarborClient{
+				ListImmutableTagRulesFunc: func(_ context.Context, projectID string) ([]*harborclients.ImmutableTagRule, error) {
+					if projectID != "3" {
+						t.Errorf("rules listed for project %q", projectID)
+					}
+					return tc.rules, tc.listErr
+				},
+			}}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if obs.ResourceExists != (tc.wantID != 0) {
+				t.Errorf("ResourceExists = %v, want %v", obs.ResourceExists, tc.wantID != 0)
+			}
+			if obs.ResourceUpToDate != tc.upToDate {
+				t.Errorf("ResourceUpToDate = %v, want %v", obs.ResourceUpToDate, tc.upToDate)
+			}
+			if tc.wantID == 0 {
+				return
+			}
+			if at := cr.Status.AtProvider; at.ID == nil || *at.ID != tc.wantID {
+				t.Errorf("AtProvider.ID = %v, want %d", at.ID, tc.wantID)
+			}
+			if cr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+				t.Error("ImmutableTagRule should be Ready once observed")
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	var created []harborclients.ImmutableTagRule
+	ext := &external{service: &harborclients.MockHarborClient{
+		CreateImmutableTagRuleFunc: func(_ context.Context, _ string, r *harborclients.ImmutableTagRule) (int64, error) {
+			created = append(created, *r)
+			return 12, nil
+		},
+	}}
+	cr := &v1beta1.ImmutableTagRule{Spec: v1beta1.ImmutableTagRuleSpec{ForProvider: v1beta1.ImmutableTagRuleParameters{
+		ProjectID:           "3",
+		RepositorySelectors: []string{"library/*"},
+		ExcludeRepositories: ptr(true),
+		TagSelectors:        []string{"v*"},
+	}}}
+
+	if _, err := ext.Create(context.Background(), cr); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	want := []harborclients.ImmutableTagRule{{RepositoryPattern: "library/*", ExcludeRepositories: true, TagPattern: "v*"}}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("created %+v, want %+v", created, want)
+	}
+	if id := cr.Status.AtProvider.ID; id == nil || *id != 12 {
+		t.Errorf("AtProvider.ID = %v, want 12", id)
+	}
+	if got := ctrlutil.GetExternalName(cr); got != "12" {
+		t.Errorf("external name = %q, want 12", got)
+	}
+}
+
+func TestUpdateTogglesEnabledInPlace(t *testing.T) {
+	var updated []harborclients.ImmutableTagRule
+	ext := &external{service: &harborclients.MockHarborClient{
+		CreateImmutableTagRuleFunc: func(context.Context, string, *harborclients.ImmutableTagRule) (int64, error) {
+			t.Error("toggling enabled should not recreate the rule")
+			return 0, nil
+		},
+		UpdateImmutableTagRuleFunc: func(_ context.Context, _ string, r *harborclients.ImmutableTagRule) error {
+			updated = append(updated, *r)
+			return nil
+		},
+	}}
+	cr := &v1beta1.ImmutableTagRule{Spec: v1beta1.ImmutableTagRuleSpec{ForProvider: v1beta1.ImmutableTagRuleParameters{
+		ProjectID: "3",
+		Enabled:   ptr(false),
+	}}}
+	cr.Status.AtProvider.ID = ptr(int64(7))
+	ctx := context.Background()
+
+	if _, err := ext.Update(ctx, cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	cr.Spec.ForProvider.Enabled = ptr(true)
+	if _, err := ext.Update(ctx, cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	want := []harborclients.ImmutableTagRule{
+		{ID: 7, RepositoryPattern: "**", TagPattern: "**", Disabled: true},
+		{ID: 7, RepositoryPattern: "**", TagPattern: "**"},
+	}
+	if !reflect.DeepEqual(updated, want) {
+		t.Errorf("updated %+v, want %+v", updated, want)
+	}
+
+	cr.Status.AtProvider.ID = nil
+	if _, err := ext.Update(ctx, cr); err == nil {
+		t.Error("Update() without an ID should fail")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	var deleted []int64
+	ext := &external{service: &harborclients.MockHarborClient{
+		DeleteImmutableTagRuleFunc: func(_ context.Context, _ string, id int64) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}}
+	ctx := context.Background()
+
+	cr := &v1beta1.ImmutableTagRule{Spec: v1beta1.ImmutableTagRuleSpec{ForProvider: v1beta1.ImmutableTagRuleParameters{ProjectID: "3"}}}
+	if _, err := ext.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	cr.Status.AtProvider.ID = ptr(int64(7))
+	if _, err := ext.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if !reflect.DeepEqual(deleted, []int64{7}) {
+		t.Errorf("deleted %v, want [7]", deleted)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/internal/cloudevents"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// A LifecycleEmitter publishes lifecycle events of managed resources.
+type LifecycleEmitter interface {
+	Emit(ev cloudevents.Event)
+}
+
+var lifecycle = struct {
+	mu      sync.Mutex
+	emitter LifecycleEmitter
+}{}
+
+// SetLifecycleEmitter makes WithLifecycleEvents publish to e. A nil e, the
+// default, publishes nothing.
+func SetLifecycleEmitter(e LifecycleEmitter) {
+	lifecycle.mu.Lock()
+	defer lifecycle.mu.Unlock()
+	lifecycle.emitter = e
+}
+
+func emitLifecycleEvent(eventType string, mg resource.Managed, now time.Time) {
+	lifecycle.mu.Lock()
+	e := lifecycle.emitter
+	lifecycle.mu.Unlock()
+	if e == nil {
+		return
+	}
+	kind := kindOf(mg)
+	e.Emit(cloudevents.Event{
+		Type:    eventType,
+		Subject: path.Join(kind, mg.GetNamespace(), mg.GetName()),
+		Time:    now,
+		Data: cloudevents.ResourceData{
+			APIVersion:   mg.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:         kind,
+			Namespace:    mg.GetNamespace(),
+			Name:         mg.GetName(),
+			UID:          string(mg.GetUID()),
+			Generation:   mg.GetGeneration(),
+			ExternalName: GetExternalName(mg),
+		},
+	})
+}
+
+// WithLifecycleEvents wraps c so that each successful Create, Update and
+// Delete in Harbor, and drift found by Observe, is published to the emitter
+// set with SetLifecycleEmitter. It should wrap WithMetrics, inside the
+// wrappers that may refuse an operation, so that only operations that reach
+// Harbor are published.
+func WithLifecycleEvents(c managed.ExternalConnector) managed.ExternalConnector {
+	return &lifecycleConnector{ExternalConnector: c, now: time.Now}
+}
+
+type lifecycleConnector struct {
+	managed.ExternalConnector
+	now func() time.Time
+}
+
+func (c *lifecycleConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &lifecycleClient{ExternalClient: ext, now: c.now}, nil
+}
+
+type lifecycleClient struct {
+	managed.ExternalClient
+	now func() time.Time
+}
+
+// drifted reports whether mg was found out of date in Harbor although it was
+// reconciled at its current generation and found up to date when last
+// observed. An edited spec is a desired change, not drift, and drift that
+// persists is reported once.
+func drifted(mg resource.Managed) bool {
+	synced := mg.GetCondition(xpv1.TypeSynced)
+	if synced.Status != corev1.ConditionTrue || synced.ObservedGeneration != mg.GetGeneration() {
+		return false
+	}
+	if h, ok := mg.(SyncStatusHolder); ok {
+		if d := h.GetSyncStatus().Drift; d != nil && *d {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *lifecycleClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	if err == nil && obs.ResourceExists && !obs.ResourceUpToDate && drifted(mg) {
+		emitLifecycleEvent(cloudevents.TypeDriftDetected, mg, e.now())
+	}
+	return obs, err
+}
+
+func (e *lifecycleClient) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	if err == nil {
+		emitLifecycleEvent(cloudevents.TypeCreated, mg, e.now())
+	}
+	return c, err
+}
+
+func (e *lifecycleClient) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	if err == nil {
+		emitLifecycleEvent(cloudevents.TypeUpdated, mg, e.now())
+	}
+	return u, err
+}
+
+func (e *lifecycleClient) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	d, err := e.ExternalClient.Delete(ctx, mg)
+	if err == nil {
+		emitLifecycleEvent(cloudevents.TypeDeleted, mg, e.now())
+	}
+	return d, err
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/cloudevents"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type capturedEvents []cloudevents.Event
+
+func (c *capturedEvents) Emit(ev cloudevents.Event) {
+	*c = append(*c, ev)
+}
+
+// lifecycleFake answers Observe with obs and fails other operations with err.
+type lifecycleFake struct {
+	managed.ExternalClient
+	obs managed.ExternalObservation
+	err error
+}
+
+func (f *lifecycleFake) Observe(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+	return f.obs, f.err
+}
+
+func (f *lifecycleFake) Create(context.Context, resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, f.err
+}
+
+func (f *lifecycleFake) Update(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, f.err
+}
+
+func (f *lifecycleFake) Delete(context.Context, resource.Managed) (managed.ExternalDelete, error) {
+	return managed.ExternalDelete{}, f.err
+}
+
+func TestWithLifecycleEvents(t *testing.T) {
+	drifted := managed.ExternalObservation{ResourceExists: true}
+	yes, no := true, false
+
+	cases := map[string]struct {
+		fake       *lifecycleFake
+		generation int64
+		observedAt int64
+		synced     bool
+		lastDrift  *bool
+		op         func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error
+		want       []string
+	}{
+		"Created": {
+			fake: &lifecycleFake{},
+			op: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, err := e.Create(ctx, mg)
+				return err
+			},
+			want: []string{cloudevents.TypeCreated},
+		},
+		"CreateFailed": {
+			fake: &lifecycleFake{err: errors.New("boom")},
+			op: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, _ = e.Create(ctx, mg)
+				return nil
+			},
+		},
+		"Updated": {
+			fake: &lifecycleFake{},
+			op: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, err := e.Update(ctx, mg)
+				return err
+			},
+			want: []string{cloudevents.TypeUpdated},
+		},
+		"Deleted": {
+			fake: &lifecycleFake{},
+			op: func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+				_, err := e.Delete(ctx, mg)
+				return err
+			},
+			want: []string{cloudevents.TypeDeleted},
+		},
+		"DriftDetected": {
+			fake:       &lifecycleFake{obs: drifted},
+			generation: 3,
+			observedAt: 3,
+			synced:     true,
+			lastDrift:  &no,
+			want:       []string{cloudevents.TypeDriftDetected},
+		},
+		"DriftAlreadyReported": {
+			fake:       &lifecycleFake{obs: drifted},
+			generation: 3,
+			observedAt: 3,
+			synced:     true,
+			lastDrift:  &yes,
+		},
+		"SpecEdited": {
+			fake:       &lifecycleFake{obs: drifted},
+			generation: 4,
+			observedAt: 3,
+			synced:     true,
+		},
+		"NotSynced": {
+			fake:       &lifecycleFake{obs: drifted},
+			generation: 3,
+			observedAt: 3,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got capturedEvents
+			SetLifecycleEmitter(&got)
+			t.Cleanup(func() { SetLifecycleEmitter(nil) })
+
+			cr := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{Namespace: "harbor-projects", Name: "team-a", Generation: tc.generation}}
+			if tc.synced {
+				c := xpv1.ReconcileSuccess()
+				c.ObservedGeneration = tc.observedAt
+				cr.SetConditions(c)
+			}
+			cr.Status.Drift = tc.lastDrift
+
+			now := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+			e := &lifecycleClient{ExternalClient: tc.fake, now: func() time.Time { return now }}
+			op := tc.op
+			if op == nil {
+				op = func(ctx context.Context, e managed.ExternalClient, mg resource.Managed) error {
+					_, err := e.Observe(ctx, mg)
+					return err
+				}
+			}
+			if err := op(context.Background(), e, cr); err != nil {
+				t.Fatal(err)
+			}
+
+			var types []string
+			for _, ev := range got {
+				types = append(types, ev.Type)
+				if ev.Subject != "Project/harbor-projects/team-a" || !ev.Time.Equal(now) {
+					t.Errorf("event %+v", ev)
+				}
+			}
+			if !reflect.DeepEqual(types, tc.want) {
+				t.Errorf("published %v, want %v", types, tc.want)
+			}
+		})
+	}
+}
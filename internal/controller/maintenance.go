@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	"github.com/rossigee/provider-harbor/apis/common"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	errMaintenanceSchedule = "cannot parse maintenance window schedule %q"
+	errDeferred            = "%s is deferred until the maintenance window closes at %s"
+)
+
+// A MaintenanceWindowHolder is a managed resource that may be suspended
+// during maintenance windows.
+type MaintenanceWindowHolder interface {
+	GetMaintenanceWindows() []common.MaintenanceWindow
+}
+
+// ActiveMaintenanceWindow returns when the maintenance windows open at now
+// close, or the zero time if none of them is open.
+func ActiveMaintenanceWindow(windows []common.MaintenanceWindow, now time.Time) (time.Time, error) {
+	var until time.Time
+	for _, w := range windows {
+		s, err := cron.ParseStandard(w.Schedule)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, errMaintenanceSchedule, w.Schedule)
+		}
+		// The window is open if it last opened less than its duration ago.
+		opened := s.Next(now.Add(-w.Duration.Duration))
+		if opened.After(now) {
+			continue
+		}
+		if closes := opened.Add(w.Duration.Duration); closes.After(until) {
+			until = closes
+		}
+	}
+	return until, nil
+}
+
+// WithMaintenanceWindows wraps c so that managed resources are only observed
+// while one of their maintenance windows is open. Observations report the
+// resource as up to date so that no update is attempted, and creation and
+// deletion fail until the window closes. The ScheduledSuspension condition
+// reports the window.
+func WithMaintenanceWindows(c managed.ExternalConnector) managed.ExternalConnector {
+	return &maintenanceConnector{ExternalConnector: c, now: time.Now}
+}
+
+type maintenanceConnector struct {
+	managed.ExternalConnector
+	now func() time.Time
+}
+
+func (c *maintenanceConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &maintenanceClient{ExternalClient: ext, now: c.now}, nil
+}
+
+type maintenanceClient struct {
+	managed.ExternalClient
+	now func() time.Time
+}
+
+// suspendedUntil returns when the open maintenance window of mg closes, and
+// keeps its ScheduledSuspension condition current.
+func (e *maintenanceClient) suspendedUntil(mg resource.Managed) (time.Time, error) {
+	h, ok := mg.(MaintenanceWindowHolder)
+	if !ok {
+		return time.Time{}, nil
+	}
+	until, err := ActiveMaintenanceWindow(h.GetMaintenanceWindows(), e.now())
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch {
+	case !until.IsZero():
+		mg.SetConditions(common.ScheduledSuspension(until))
+	case mg.GetCondition(common.TypeScheduledSuspension).Status == corev1.ConditionTrue:
+		mg.SetConditions(common.NoScheduledSuspension())
+	}
+	return until, nil
+}
+
+func (e *maintenanceClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return obs, err
+	}
+	until, err := e.suspendedUntil(mg)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if !until.IsZero() {
+		obs.ResourceUpToDate = true
+	}
+	return obs, nil
+}
+
+func (e *maintenanceClient) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	until, err := e.suspendedUntil(mg)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if !until.IsZero() {
+		return managed.ExternalCreation{}, errors.Errorf(errDeferred, "creation", until.UTC().Format(time.RFC3339))
+	}
+	return e.ExternalClient.Create(ctx, mg)
+}
+
+func (e *maintenanceClient) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	until, err := e.suspendedUntil(mg)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if !until.IsZero() {
+		return managed.ExternalUpdate{}, errors.Errorf(errDeferred, "update", until.UTC().Format(time.RFC3339))
+	}
+	return e.ExternalClient.Update(ctx, mg)
+}
+
+func (e *maintenanceClient) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	until, err := e.suspendedUntil(mg)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+	if !until.IsZero() {
+		return managed.ExternalDelete{}, errors.Errorf(errDeferred, "deletion", until.UTC().Format(time.RFC3339))
+	}
+	return e.ExternalClient.Delete(ctx, mg)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/rossigee/provider-harbor/apis/common"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fridayNight opens at 22:00 UTC every Friday for two hours.
+var fridayNight = common.MaintenanceWindow{Schedule: "0 22 * * 5", Duration: metav1.Duration{Duration: 2 * time.Hour}}
+
+func TestActiveMaintenanceWindow(t *testing.T) {
+	cases := map[string]struct {
+		windows []common.MaintenanceWindow
+		now     time.Time
+		want    time.Time
+		wantErr bool
+	}{
+		"Before": {
+			windows: []common.MaintenanceWindow{fridayNight},
+			now:     time.Date(2024, 5, 3, 21, 59, 0, 0, time.UTC),
+		},
+		"Opening": {
+			windows: []common.MaintenanceWindow{fridayNight},
+			now:     time.Date(2024, 5, 3, 22, 0, 0, 0, time.UTC),
+			want:    time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC),
+		},
+		"AcrossMidnight": {
+			windows: []common.MaintenanceWindow{fridayNight},
+			now:     time.Date(2024, 5, 3, 23, 30, 0, 0, time.UTC),
+			want:    time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC),
+		},
+		"Closed": {
+			windows: []common.MaintenanceWindow{fridayNight},
+			now:     time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC),
+		},
+		"Overlapping": {
+			windows: []common.MaintenanceWindow{
+				fridayNight,
+				{Schedule: "30 23 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			now:  time.Date(2024, 5, 3, 23, 45, 0, 0, time.UTC),
+			want: time.Date(2024, 5, 4, 0, 30, 0, 0, time.UTC),
+		},
+		"TimeZone": {
+			windows: []common.MaintenanceWindow{{Schedule: "CRON_TZ=Europe/Berlin 0 22 * * 5", Duration: metav1.Duration{Duration: 2 * time.Hour}}},
+			now:     time.Date(2024, 5, 3, 20, 30, 0, 0, time.UTC),
+			want:    time.Date(2024, 5, 3, 22, 0, 0, 0, time.UTC),
+		},
+		"InvalidSchedule": {
+			windows: []common.MaintenanceWindow{{Schedule: "every friday", Duration: metav1.Duration{Duration: time.Hour}}},
+			now:     time.Date(2024, 5, 3, 22, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ActiveMaintenanceWindow(tc.windows, tc.now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ActiveMaintenanceWindow() error = %v, want error: %v", err, tc.wantErr)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("ActiveMaintenanceWindow() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// mutatingExternal records which mutations reached it.
+type mutatingExternal struct {
+	fakeExternal
+	calls []string
+}
+
+func (m *mutatingExternal) Create(context.Context, resource.Managed) (managed.ExternalCreation, error) {
+	m.calls = append(m.calls, "create")
+	return managed.ExternalCreation{}, nil
+}
+
+func (m *mutatingExternal) Update(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+	m.calls = append(m.calls, "update")
+	return managed.ExternalUpdate{}, nil
+}
+
+func (m *mutatingExternal) Delete(context.Context, resource.Managed) (managed.ExternalDelete, error) {
+	m.calls = append(m.calls, "delete")
+	return managed.ExternalDelete{}, nil
+}
+
+func TestWithMaintenanceWindows(t *testing.T) {
+	ctx := context.Background()
+	ext := &mutatingExternal{fakeExternal: fakeExternal{obs: managed.ExternalObservation{ResourceExists: true}}}
+	c := WithMaintenanceWindows(&fakeConnector{ext: ext}).(*maintenanceConnector)
+	now := time.Date(2024, 5, 3, 23, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	cr := &projectv1beta1.Project{}
+	cr.Spec.MaintenanceWindows = []common.MaintenanceWindow{fridayNight}
+	e, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || !obs.ResourceUpToDate {
+		t.Fatalf("Observe() in window = %+v, %v; want drift hidden", obs, err)
+	}
+	if cond := cr.GetCondition(common.TypeScheduledSuspension); cond.Status != corev1.ConditionTrue {
+		t.Errorf("ScheduledSuspension = %s, want True", cond.Status)
+	}
+	if _, err := e.Create(ctx, cr); err == nil {
+		t.Error("Create() in window should be deferred")
+	}
+	if _, err := e.Delete(ctx, cr); err == nil {
+		t.Error("Delete() in window should be deferred")
+	}
+	if len(ext.calls) != 0 {
+		t.Errorf("mutations %v reached Harbor during the window", ext.calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	obs, err = e.Observe(ctx, cr)
+	if err != nil || obs.ResourceUpToDate {
+		t.Fatalf("Observe() after window = %+v, %v; want drift reported", obs, err)
+	}
+	if cond := cr.GetCondition(common.TypeScheduledSuspension); cond.Status != corev1.ConditionFalse {
+		t.Errorf("ScheduledSuspension = %s after window, want False", cond.Status)
+	}
+	if _, err := e.Update(ctx, cr); err != nil || len(ext.calls) != 1 {
+		t.Errorf("Update() after window = %v, calls %v; want it applied", err, ext.calls)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package member
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	sdkmember "github.com/goharbor/go-client/pkg/sdk/v2.0/client/member"
+	"github.com/rossigee/provider-harbor/apis/member/v1beta1"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := projectv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func project(name string, id *string, labels map[string]string) *projectv1beta1.Project {
+	p := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: name, Labels: labels}}
+	p.Status.AtProvider.ID = id
+	return p
+}
+
+func groupMember(role string) *v1beta1.Member {
+	return &v1beta1.Member{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "platform-admins"},
+		Spec: v1beta1.MemberSpec{
+			ForProvider: v1beta1.MemberParameters{
+				ProjectID:   "42",
+				MemberGroup: &v1beta1.MemberGroup{GroupName: "platform-admins", GroupType: 3},
+				Role:        role,
+			},
+		},
+	}
+}
+
+func TestObserveGroupMember(t *testing.T) {
+	cases := map[string]struct {
+		member     *harborclients.MemberStatus
+		err        error
+		wantExists bool
+		wantUpdate bool
+	}{
+		"NotAMember": {
+			err: sdkmember.NewGetProjectMemberNotFound(),
+		},
+		"RoleMatches": {
+			member:     &harborclients.MemberStatus{ID: "7", MemberName: "platform-admins", MemberType: "g", Role: "maintainer"},
+			wantExists: true,
+		},
+		"RoleDiffers": {
+			member:     &harborclients.MemberStatus{ID: "7", MemberName: "platform-admins", MemberType: "g", Role: "guest"},
+			wantExists: true,
+			wantUpdate: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var gotProject string
+			e := &external{
+				service: &mockMemberClient{
+					getProjectGroupMemberFunc: func(_ context.Context, projectID, _ string) (*harborclients.MemberStatus, error) {
+						gotProject = projectID
+						return tc.member, tc.err
+					},
+				},
+			}
+			obs, err := e.Observe(context.Background(), groupMember("maintainer"))
+			if err != nil {
+				t.Fatalf("Observe() error = %v", err)
+			}
+			if gotProject != "42" {
+				t.Errorf("looked up members of project %q, want 42", gotProject)
+			}
+			if obs.ResourceExists != tc.wantExists || (obs.ResourceExists && obs.ResourceUpToDate == tc.wantUpdate) {
+				t.Errorf("Observe() = %+v, want exists %v and needing an update %v", obs, tc.wantExists, tc.wantUpdate)
+			}
+		})
+	}
+}
+
+func TestResolveProjectReference(t *testing.T) {
+	cases := map[string]struct {
+		projects []client.Object
+		ref      *xpv1.NamespacedReference
+		selector *xpv1.NamespacedSelector
+		want     string
+		wantErr  string
+	}{
+		"Reference": {
+			projects: []client.Object{project("team-a", ptrString("42"), nil)},
+			ref:      &xpv1.NamespacedReference{Name: "team-a"},
+			want:     "42",
+		},
+		"Selector": {
+			projects: []client.Object{
+				project("team-a", ptrString("42"), map[string]string{"team": "a"}),
+				project("team-b", ptrString("43"), map[string]string{"team": "b"}),
+			},
+			selector: &xpv1.NamespacedSelector{MatchLabels: map[string]string{"team": "b"}},
+			want:     "43",
+		},
+		"NotCreated": {
+			projects: []client.Object{project("team-a", nil, nil)},
+			ref:      &xpv1.NamespacedReference{Name: "team-a"},
+			wantErr:  "referenced field was empty",
+		},
+		"Missing": {
+			ref:     &xpv1.NamespacedReference{Name: "team-a"},
+			wantErr: "not found",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := groupMember("maintainer")
+			cr.Spec.ForProvider.ProjectID = ""
+			cr.Spec.ForProvider.ProjectRef = tc.ref
+			cr.Spec.ForProvider.ProjectSelector = tc.selector
+			err := cr.ResolveReferences(context.Background(), newKube(t, tc.projects...))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("ResolveReferences() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cr.Spec.ForProvider.ProjectID != tc.want {
+				t.Errorf("projectId = %q, want %q", cr.Spec.ForProvider.ProjectID, tc.want)
+			}
+			if cr.Spec.ForProvider.ProjectRef == nil {
+				t.Error("projectRef was not recorded")
+			}
+		})
+	}
+}
+
+func TestGroupMemberLifecycle(t *testing.T) {
+	var calls []string
+	e := &external{
+		service: &mockMemberClient{
+			addProjectGroupMemberFunc: func(_ context.Context, projectID, group string, groupType int64, role string) error {
+				if groupType != 3 {
+					t.Errorf("group type = %d, want 3", groupType)
+				}
+				calls = append(calls, "add "+projectID+" "+group+" "+role)
+				return nil
+			},
+			updateProjectGroupMemberFunc: func(_ context.Context, projectID, group, role string) error {
+				calls = append(calls, "update "+projectID+" "+group+" "+role)
+				return nil
+			},
+			deleteProjectGroupMemberFunc: func(_ context.Context, projectID, group string) error {
+				calls = append(calls, "delete "+projectID+" "+group)
+				return nil
+			},
+		},
+	}
+	ctx := context.Background()
+	cr := groupMember("developer")
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	cr.Spec.ForProvider.Role = "maintainer"
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"add 42 platform-admins developer", "update 42 platform-admins maintainer", "delete 42 platform-admins"}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
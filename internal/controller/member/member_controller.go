@@ -6,6 +6,7 @@ package member
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
@@ -19,7 +20,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"time"
 )
 
@@ -29,26 +29,37 @@ const (
 	errNewClient    = "cannot create new Harbor client"
 )
 
-
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.MemberGroupVersionKind.Kind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.MemberGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithProtection(mgr.GetClient(), memberTarget, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
-		}),
+		}))))))))),
 		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
-		managed.WithPollInterval(1*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.MemberGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		WithEventFilter(resource.DesiredStateChanged()).
 		For(&v1beta1.Member{}).
-		Complete(ratelimiter.NewReconciler(name, r, nil))
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// memberTarget returns the project a Member belongs to.
+func memberTarget(mg resource.Managed) ctrlutil.Target {
+	cr, ok := mg.(*v1beta1.Member)
+	if !ok {
+		return ctrlutil.Target{}
+	}
+	return ctrlutil.Target{Project: cr.Spec.ForProvider.ProjectID}
 }
 
 type connector struct {
@@ -74,6 +85,14 @@ type external struct {
 	service harborclients.HarborClienter
 }
 
+// memberName returns the name of the user or user group cr makes a member.
+func memberName(cr *v1beta1.Member) string {
+	if g := cr.Spec.ForProvider.MemberGroup; g != nil {
+		return g.GroupName
+	}
+	return cr.Spec.ForProvider.Username
+}
+
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 	_, span := tracing.StartSpan(ctx, "member.observe",
 		tracing.SpanAttrs("Member", tracing.ResourceName(mg), "observe")...)
@@ -84,7 +103,17 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotMember)
 	}
 
-	status, err := c.service.GetProjectMember(ctx, cr.Spec.ForProvider.ProjectID, cr.Spec.ForProvider.Username)
+	project := cr.Spec.ForProvider.ProjectID
+	var status *harborclients.MemberStatus
+	var err error
+	if cr.Spec.ForProvider.MemberGroup != nil {
+		status, err = c.service.GetProjectGroupMember(ctx, project, memberName(cr))
+	} else {
+		status, err = c.service.GetProjectMember(ctx, project, memberName(cr))
+	}
+	if harborclients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
 	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
@@ -93,8 +122,10 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	cr.Status.AtProvider.MemberName = &status.MemberName
 	cr.Status.AtProvider.MemberType = &status.MemberType
 	cr.Status.AtProvider.Role = &status.Role
-	t := metav1.NewTime(status.CreationTime)
-	cr.Status.AtProvider.CreationTime = &t
+	if !status.CreationTime.IsZero() {
+		t := metav1.NewTime(status.CreationTime)
+		cr.Status.AtProvider.CreationTime = &t
+	}
 
 	upToDate := cr.Spec.ForProvider.Role == "" || status.Role == "" || cr.Spec.ForProvider.Role == status.Role
 
@@ -113,10 +144,17 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotMember)
 	}
 
-	err := c.service.AddProjectMember(ctx, cr.Spec.ForProvider.ProjectID, cr.Spec.ForProvider.Username, cr.Spec.ForProvider.Role)
+	p := cr.Spec.ForProvider
+	var err error
+	if p.MemberGroup != nil {
+		err = c.service.AddProjectGroupMember(ctx, p.ProjectID, p.MemberGroup.GroupName, p.MemberGroup.GroupType, p.Role)
+	} else {
+		err = c.service.AddProjectMember(ctx, p.ProjectID, p.Username, p.Role)
+	}
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
+	ctrlutil.SetExternalName(cr, memberName(cr))
 
 	return managed.ExternalCreation{}, nil
 }
@@ -131,7 +169,13 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotMember)
 	}
 
-	err := c.service.UpdateProjectMember(ctx, cr.Spec.ForProvider.ProjectID, cr.Spec.ForProvider.Username, cr.Spec.ForProvider.Role)
+	project := cr.Spec.ForProvider.ProjectID
+	var err error
+	if cr.Spec.ForProvider.MemberGroup != nil {
+		err = c.service.UpdateProjectGroupMember(ctx, project, memberName(cr), cr.Spec.ForProvider.Role)
+	} else {
+		err = c.service.UpdateProjectMember(ctx, project, memberName(cr), cr.Spec.ForProvider.Role)
+	}
 	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
@@ -149,7 +193,13 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errNotMember)
 	}
 
-	err := c.service.DeleteProjectMember(ctx, cr.Spec.ForProvider.ProjectID, cr.Spec.ForProvider.Username)
+	project := cr.Spec.ForProvider.ProjectID
+	var err error
+	if cr.Spec.ForProvider.MemberGroup != nil {
+		err = c.service.DeleteProjectGroupMember(ctx, project, memberName(cr))
+	} else {
+		err = c.service.DeleteProjectMember(ctx, project, memberName(cr))
+	}
 	if err != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, errMemberDelete)
 	}
@@ -536,6 +536,39 @@ type mockMemberClient struct {
 	updateProjectMemberFunc func(ctx context.Context, projectID, username, role string) error
 	deleteProjectMemberFunc func(ctx context.Context, projectID, username string) error
 	listProjectMembersFunc  func(ctx context.Context, projectID string) ([]*harborclients.MemberStatus, error)
+
+	getProjectGroupMemberFunc    func(ctx context.Context, projectID, groupName string) (*harborclients.MemberStatus, error)
+	addProjectGroupMemberFunc    func(ctx context.Context, projectName, groupName string, groupType int64, role string) error
+	updateProjectGroupMemberFunc func(ctx context.Context, projectID, groupName, role string) error
+	deleteProjectGroupMemberFunc func(ctx context.Context, projectID, groupName string) error
+}
+
+func (m *mockMemberClient) GetProjectGroupMember(ctx context.Context, projectID, groupName string) (*harborclients.MemberStatus, error) {
+	if m.getProjectGroupMemberFunc != nil {
+		return m.getProjectGroupMemberFunc(ctx, projectID, groupName)
+	}
+	return nil, nil
+}
+
+func (m *mockMemberClient) AddProjectGroupMember(ctx context.Context, projectName, groupName string, groupType int64, role string) error {
+	if m.addProjectGroupMemberFunc != nil {
+		return m.addProjectGroupMemberFunc(ctx, projectName, groupName, groupType, role)
+	}
+	return nil
+}
+
+func (m *mockMemberClient) UpdateProjectGroupMember(ctx context.Context, projectID, groupName, role string) error {
+	if m.updateProjectGroupMemberFunc != nil {
+		return m.updateProjectGroupMemberFunc(ctx, projectID, groupName, role)
+	}
+	return nil
+}
+
+func (m *mockMemberClient) DeleteProjectGroupMember(ctx context.Context, projectID, groupName string) error {
+	if m.deleteProjectGroupMemberFunc != nil {
+		return m.deleteProjectGroupMemberFunc(ctx, projectID, groupName)
+	}
+	return nil
 }
 
 func (m *mockMemberClient) GetProjectMember(ctx context.Context, projectID, username string) (*harborclients.MemberStatus, error) {
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+)
+
+// Operations recorded by WithMetrics.
+const (
+	OperationObserve = "observe"
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+)
+
+// WithMetrics wraps c so that the duration and result of each external
+// Observe, Create, Update and Delete are recorded in
+// harbor_external_request_duration_seconds. It should wrap the connector
+// that calls Harbor directly, so that calls the other wrappers refuse are not
+// counted.
+func WithMetrics(c managed.ExternalConnector) managed.ExternalConnector {
+	return &metricsConnector{ExternalConnector: c, now: time.Now}
+}
+
+type metricsConnector struct {
+	managed.ExternalConnector
+	now func() time.Time
+}
+
+func (c *metricsConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsClient{ExternalClient: ext, now: c.now}, nil
+}
+
+type metricsClient struct {
+	managed.ExternalClient
+	now func() time.Time
+}
+
+// kindOf returns the kind of mg. Objects read from the cache have no
+// TypeMeta, so the kind is taken from the Go type.
+func kindOf(mg resource.Managed) string {
+	if k := mg.GetObjectKind().GroupVersionKind().Kind; k != "" {
+		return k
+	}
+	t := reflect.TypeOf(mg)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// observe records an operation on mg that started at start.
+func (e *metricsClient) observe(mg resource.Managed, operation string, start time.Time, err error) {
+	metrics.ObserveExternalRequest(kindOf(mg), operation, e.now().Sub(start), err)
+}
+
+func (e *metricsClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	start := e.now()
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	e.observe(mg, OperationObserve, start, err)
+	return obs, err
+}
+
+func (e *metricsClient) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	start := e.now()
+	c, err := e.ExternalClient.Create(ctx, mg)
+	e.observe(mg, OperationCreate, start, err)
+	return c, err
+}
+
+func (e *metricsClient) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	start := e.now()
+	u, err := e.ExternalClient.Update(ctx, mg)
+	e.observe(mg, OperationUpdate, start, err)
+	return u, err
+}
+
+func (e *metricsClient) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	start := e.now()
+	d, err := e.ExternalClient.Delete(ctx, mg)
+	e.observe(mg, OperationDelete, start, err)
+	return d, err
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+)
+
+func sampleCount(t *testing.T, operation, result string) (uint64, float64) {
+	t.Helper()
+	m := &dto.Metric{}
+	o := metrics.ExternalRequestDuration.WithLabelValues("Project", operation, result)
+	if err := o.(prometheus.Metric).Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
+
+func TestWithMetrics(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err    error
+		result string
+	}{
+		"Success": {result: metrics.ResultSuccess},
+		"Error":   {err: errors.New("boom"), result: metrics.ResultError},
+	} {
+		t.Run(name, func(t *testing.T) {
+			start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			calls := 0
+			c := WithMetrics(&fakeConnector{ext: &fakeExternal{err: tc.err}}).(*metricsConnector)
+			// Each call to now is 250ms after the previous one.
+			c.now = func() time.Time {
+				calls++
+				return start.Add(time.Duration(calls) * 250 * time.Millisecond)
+			}
+
+			countBefore, sumBefore := sampleCount(t, OperationObserve, tc.result)
+			cr := &projectv1beta1.Project{}
+			ext, err := c.Connect(context.Background(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ext.Observe(context.Background(), cr); !errors.Is(err, tc.err) {
+				t.Fatalf("Observe() error = %v, want %v", err, tc.err)
+			}
+
+			count, sum := sampleCount(t, OperationObserve, tc.result)
+			if count-countBefore != 1 {
+				t.Errorf("recorded %d observations, want 1", count-countBefore)
+			}
+			if d := sum - sumBefore; d != 0.25 {
+				t.Errorf("recorded %vs, want 0.25s", d)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	if got := kindOf(&projectv1beta1.Project{}); got != "Project" {
+		t.Errorf("kindOf() = %q, want Project", got)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Labels tracking the owner of a child object that cannot have an owner
+// reference to it. Names may be too long for a label value, so the owner's
+// namespace and name are kept in an annotation.
+const (
+	LabelOwnerUID       = "harbor.m.crossplane.io/owner-uid"
+	LabelOwnerKind      = "harbor.m.crossplane.io/owner-kind"
+	AnnotationOwnerName = "harbor.m.crossplane.io/owner"
+)
+
+const (
+	errListTrackedChildren = "cannot list children tracked by owner labels"
+	errReadTrackedChildren = "cannot read children tracked by owner labels"
+	errDeleteTrackedChild  = "cannot delete child tracked by owner labels"
+)
+
+// OwnerReferenceAllowed reports whether Kubernetes garbage collection
+// honours an owner reference from child to owner. A namespaced owner can
+// only own objects in its own namespace; a cluster scoped owner can own
+// any object.
+func OwnerReferenceAllowed(child, owner client.Object) bool {
+	return owner.GetNamespace() == "" || owner.GetNamespace() == child.GetNamespace()
+}
+
+// SetOwner makes owner, of kind gvk, the controller of child. Where an owner
+// reference is not allowed, child is labelled with its owner instead, and
+// the owner's controller must delete it with DeleteTrackedChildren. SetOwner
+// reports whether an owner reference was used.
+func SetOwner(child, owner client.Object, gvk schema.GroupVersionKind) bool {
+	if OwnerReferenceAllowed(child, owner) {
+		meta.AddOwnerReference(child, meta.AsController(meta.TypedReferenceTo(owner, gvk)))
+		return true
+	}
+	meta.AddLabels(child, map[string]string{
+		LabelOwnerUID:  string(owner.GetUID()),
+		LabelOwnerKind: gvk.Kind,
+	})
+	meta.AddAnnotations(child, map[string]string{
+		AnnotationOwnerName: owner.GetNamespace() + "/" + owner.GetName(),
+	})
+	return false
+}
+
+// DeleteTrackedChildren deletes the objects of the type of list, in every
+// namespace, that SetOwner labelled with owner.
+func DeleteTrackedChildren(ctx context.Context, kube client.Client, owner client.Object, list client.ObjectList) error {
+	if owner.GetUID() == "" {
+		return nil
+	}
+	if err := kube.List(ctx, list, client.MatchingLabels{LabelOwnerUID: string(owner.GetUID())}); err != nil {
+		return errors.Wrap(err, errListTrackedChildren)
+	}
+	objs, err := kmeta.ExtractList(list)
+	if err != nil {
+		return errors.Wrap(err, errReadTrackedChildren)
+	}
+	for _, o := range objs {
+		child, ok := o.(client.Object)
+		if !ok {
+			continue
+		}
+		if err := kube.Delete(ctx, child); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteTrackedChild)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	userv1beta1 "github.com/rossigee/provider-harbor/apis/user/v1beta1"
+	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func secret(ns, name string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+}
+
+func TestSetOwner(t *testing.T) {
+	user := &userv1beta1.User{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "alice", UID: "user-uid"}}
+	pc := &providerconfigv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "pc-uid"}}
+
+	cases := map[string]struct {
+		child   *corev1.Secret
+		owner   client.Object
+		gvk     schema.GroupVersionKind
+		wantRef bool
+	}{
+		"SameNamespace":      {child: secret("team-a", "alice-password"), owner: user, gvk: userv1beta1.UserGroupVersionKind, wantRef: true},
+		"OtherNamespace":     {child: secret("vault", "alice-password"), owner: user, gvk: userv1beta1.UserGroupVersionKind},
+		"ClusterScopedOwner": {child: secret("vault", "harbor-creds"), owner: pc, gvk: providerconfigv1beta1.ProviderConfigGroupVersionKind, wantRef: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := SetOwner(tc.child, tc.owner, tc.gvk); got != tc.wantRef {
+				t.Errorf("SetOwner() = %v, want %v", got, tc.wantRef)
+			}
+			refs := tc.child.GetOwnerReferences()
+			if tc.wantRef {
+				if len(refs) != 1 || refs[0].Controller == nil || !*refs[0].Controller {
+					t.Errorf("owner references = %+v, want one controller reference", refs)
+				}
+				if _, ok := tc.child.GetLabels()[LabelOwnerUID]; ok {
+					t.Errorf("labels = %v, want no owner labels", tc.child.GetLabels())
+				}
+				return
+			}
+			if len(refs) != 0 {
+				t.Errorf("owner references = %+v, want none across namespaces", refs)
+			}
+			if l := tc.child.GetLabels(); l[LabelOwnerUID] != "user-uid" || l[LabelOwnerKind] != userv1beta1.UserKind {
+				t.Errorf("labels = %v, want the owner's UID and kind", l)
+			}
+			if a := tc.child.GetAnnotations()[AnnotationOwnerName]; a != "team-a/alice" {
+				t.Errorf("owner annotation = %q, want team-a/alice", a)
+			}
+		})
+	}
+}
+
+func TestDeleteTrackedChildren(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	user := &userv1beta1.User{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "alice", UID: "user-uid"}}
+	other := &userv1beta1.User{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "bob", UID: "bob-uid"}}
+
+	tracked := secret("vault", "alice-password")
+	SetOwner(tracked, user, userv1beta1.UserGroupVersionKind)
+	trackedToo := secret("backup", "alice-password")
+	SetOwner(trackedToo, user, userv1beta1.UserGroupVersionKind)
+	othersChild := secret("vault", "bob-password")
+	SetOwner(othersChild, other, userv1beta1.UserGroupVersionKind)
+	unrelated := secret("vault", "harbor-creds")
+
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(tracked, trackedToo, othersChild, unrelated).Build()
+	ctx := context.Background()
+	if err := DeleteTrackedChildren(ctx, kube, user, &corev1.SecretList{}); err != nil {
+		t.Fatalf("DeleteTrackedChildren() error = %v", err)
+	}
+
+	for _, c := range []struct {
+		s    *corev1.Secret
+		gone bool
+	}{{tracked, true}, {trackedToo, true}, {othersChild, false}, {unrelated, false}} {
+		err := kube.Get(ctx, types.NamespacedName{Namespace: c.s.Namespace, Name: c.s.Name}, &corev1.Secret{})
+		if gone := kerrors.IsNotFound(err); gone != c.gone {
+			t.Errorf("%s/%s deleted = %v, want %v (err %v)", c.s.Namespace, c.s.Name, gone, c.gone, err)
+		}
+	}
+
+	// An owner without a UID was never persisted and tracks nothing.
+	if err := DeleteTrackedChildren(ctx, nil, &userv1beta1.User{}, &corev1.SecretList{}); err != nil {
+		t.Errorf("DeleteTrackedChildren() without UID error = %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errGetProjectClass = "cannot get ProjectClass"
+	errClassWebhooks   = "cannot reconcile ProjectClass webhooks"
+)
+
+// className returns the ProjectClass of cr, or "" if it has none.
+func className(cr *v1beta1.Project) string {
+	if n := cr.Spec.ForProvider.ProjectClassName; n != nil {
+		return *n
+	}
+	return cr.GetLabels()[v1beta1.LabelProjectClass]
+}
+
+// getClass returns the ProjectClass of cr, or nil if it has none. A class
+// that does not exist is an error, so that the project is not reconciled
+// without the defaults it asked for.
+func (c *external) getClass(ctx context.Context, cr *v1beta1.Project) (*v1beta1.ProjectClass, error) {
+	name := className(cr)
+	if name == "" {
+		cr.Status.AtProvider.ProjectClass = nil
+		return nil, nil
+	}
+	pc := &v1beta1.ProjectClass{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+		return nil, errors.Wrapf(err, "%s %q", errGetProjectClass, name)
+	}
+	cr.Status.AtProvider.ProjectClass = &name
+	return pc, nil
+}
+
+// withClass fills the settings p leaves unset from the class. The vulnerability
+// settings are applied as metadata entries, since Harbor reports them as
+// metadata and that is where a change to the class can be observed.
+func withClass(p v1beta1.ProjectParameters, pc *v1beta1.ProjectClass) v1beta1.ProjectParameters {
+	if pc == nil {
+		return p
+	}
+	s := pc.Spec
+	if p.StorageLimit == nil {
+		p.StorageLimit = s.StorageLimit
+	}
+	if len(p.CVEAllowlist) == 0 {
+		p.CVEAllowlist = s.CVEAllowlist
+	}
+
+	md := make(map[string]string, len(p.Metadata)+len(s.Metadata)+3)
+	for k, v := range s.Metadata {
+		md[k] = v
+	}
+	if s.AutoScanImages != nil && p.AutoScanImages == nil {
+		md["auto_scan"] = strconv.FormatBool(*s.AutoScanImages)
+	}
+	if s.PreventVulnerableImages != nil && p.PreventVulnerableImages == nil {
+		md["prevent_vul"] = strconv.FormatBool(*s.PreventVulnerableImages)
+	}
+	if s.Severity != nil && p.Severity == nil {
+		md["severity"] = *s.Severity
+	}
+	for k, v := range p.Metadata {
+		md[k] = v
+	}
+	if len(md) > 0 {
+		p.Metadata = md
+	}
+	return p
+}
+
+// classWebhookSpec is the webhook policy w of a class in project.
+func classWebhookSpec(project string, w v1beta1.ProjectClassWebhook) *harborclients.WebhookSpec {
+	return &harborclients.WebhookSpec{
+		ProjectID:      project,
+		Name:           w.Name,
+		URL:            w.URL,
+		EventTypes:     w.EventTypes,
+		SkipCertVerify: w.SkipCertVerify,
+		Enabled:        true,
+	}
+}
+
+// classWebhooksUpToDate reports whether every webhook policy of the class
+// exists in the project as the class describes it.
+func (c *external) classWebhooksUpToDate(ctx context.Context, project string, pc *v1beta1.ProjectClass) (bool, error) {
+	if pc == nil || len(pc.Spec.Webhooks) == 0 {
+		return true, nil
+	}
+	observed, err := c.service.ListWebhooks(ctx, project)
+	if err != nil {
+		return false, errors.Wrap(err, errClassWebhooks)
+	}
+	for _, w := range pc.Spec.Webhooks {
+		if wh := findWebhook(observed, w.Name); wh == nil || !webhookMatches(wh, w) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// applyClassWebhooks creates or updates the webhook policies of the class in
+// the project.
+func (c *external) applyClassWebhooks(ctx context.Context, project string, pc *v1beta1.ProjectClass) error {
+	if pc == nil || len(pc.Spec.Webhooks) == 0 {
+		return nil
+	}
+	observed, err := c.service.ListWebhooks(ctx, project)
+	if err != nil {
+		return errors.Wrap(err, errClassWebhooks)
+	}
+	for _, w := range pc.Spec.Webhooks {
+		wh := findWebhook(observed, w.Name)
+		switch {
+		case wh == nil:
+			_, err = c.service.CreateWebhook(ctx, classWebhookSpec(project, w))
+		case !webhookMatches(wh, w):
+			_, err = c.service.UpdateWebhook(ctx, project, wh.ID, classWebhookSpec(project, w))
+		}
+		if err != nil {
+			return errors.Wrapf(err, "%s: webhook %q", errClassWebhooks, w.Name)
+		}
+	}
+	return nil
+}
+
+func findWebhook(observed []*harborclients.WebhookStatus, name string) *harborclients.WebhookStatus {
+	for _, wh := range observed {
+		if wh.Name == name {
+			return wh
+		}
+	}
+	return nil
+}
+
+func webhookMatches(wh *harborclients.WebhookStatus, w v1beta1.ProjectClassWebhook) bool {
+	want := slices.Clone(w.EventTypes)
+	got := slices.Clone(wh.EventTypes)
+	slices.Sort(want)
+	slices.Sort(got)
+	return wh.Enabled && wh.URL == w.URL && slices.Equal(want, got)
+}
+
+// enqueueClassMembers enqueues the Projects of a ProjectClass, so that they
+// re-apply its defaults when it changes.
+func enqueueClassMembers(kube client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		l := &v1beta1.ProjectList{}
+		if err := kube.List(ctx, l); err != nil {
+			return nil
+		}
+		var reqs []reconcile.Request
+		for i := range l.Items {
+			if className(&l.Items[i]) == o.GetName() {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: l.Items[i].GetNamespace(), Name: l.Items[i].GetName()}})
+			}
+		}
+		return reqs
+	})
+}
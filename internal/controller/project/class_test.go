@@ -0,0 +1,234 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestWithClass(t *testing.T) {
+	class := &v1beta1.ProjectClass{Spec: v1beta1.ProjectClassSpec{
+		StorageLimit:            getInt64Ptr(10),
+		Severity:                getStringPtr("high"),
+		AutoScanImages:          ptrBool(true),
+		PreventVulnerableImages: ptrBool(true),
+		CVEAllowlist:            []string{"CVE-1"},
+		Metadata:                map[string]string{"proxy_speed_kb": "100"},
+	}}
+
+	cases := map[string]struct {
+		p    v1beta1.ProjectParameters
+		want v1beta1.ProjectParameters
+	}{
+		"Unset": {
+			p: v1beta1.ProjectParameters{Name: "p"},
+			want: v1beta1.ProjectParameters{
+				Name:         "p",
+				StorageLimit: getInt64Ptr(10),
+				CVEAllowlist: []string{"CVE-1"},
+				Metadata: map[string]string{
+					"proxy_speed_kb": "100",
+					"auto_scan":      "true",
+					"prevent_vul":    "true",
+					"severity":       "high",
+				},
+			},
+		},
+		"ProjectWins": {
+			p: v1beta1.ProjectParameters{
+				Name:           "p",
+				StorageLimit:   getInt64Ptr(5),
+				AutoScanImages: ptrBool(false),
+				CVEAllowlist:   []string{"CVE-2"},
+				Metadata:       map[string]string{"proxy_speed_kb": "-1", "severity": "low"},
+			},
+			want: v1beta1.ProjectParameters{
+				Name:           "p",
+				StorageLimit:   getInt64Ptr(5),
+				AutoScanImages: ptrBool(false),
+				CVEAllowlist:   []string{"CVE-2"},
+				Metadata: map[string]string{
+					"proxy_speed_kb": "-1",
+					"prevent_vul":    "true",
+					"severity":       "low",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := withClass(tc.p, class); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("withClass() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	p := v1beta1.ProjectParameters{Name: "p"}
+	if got := withClass(p, nil); !reflect.DeepEqual(got, p) {
+		t.Errorf("withClass(nil) = %+v, want %+v", got, p)
+	}
+}
+
+// classProject fakes a Harbor project's metadata and webhook policies.
+type classProject struct {
+	*mockProjectClient
+	metadata map[string]string
+	webhooks []*harborclients.WebhookStatus
+}
+
+func newClassProject() *classProject {
+	c := &classProject{metadata: map[string]string{}}
+	c.mockProjectClient = &mockProjectClient{
+		getProjectFunc: func(_ context.Context, name string) (*harborclients.ProjectStatus, error) {
+			return &harborclients.ProjectStatus{ID: "3", Name: name}, nil
+		},
+		updateProjectFunc: func(_ context.Context, name string, _ *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+			return &harborclients.ProjectStatus{ID: "3", Name: name}, nil
+		},
+		listProjectMetadataFunc: func(context.Context, string) (map[string]string, error) {
+			return c.metadata, nil
+		},
+		setProjectMetadataFunc: func(_ context.Context, _, k, v string) error {
+			c.metadata[k] = v
+			return nil
+		},
+	}
+	return c
+}
+
+func (c *classProject) ListWebhooks(context.Context, string) ([]*harborclients.WebhookStatus, error) {
+	return c.webhooks, nil
+}
+
+func (c *classProject) CreateWebhook(_ context.Context, s *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error) {
+	wh := &harborclients.WebhookStatus{ID: strconv.Itoa(len(c.webhooks) + 1), Name: s.Name, URL: s.URL, EventTypes: s.EventTypes, Enabled: s.Enabled}
+	c.webhooks = append(c.webhooks, wh)
+	return wh, nil
+}
+
+func (c *classProject) UpdateWebhook(_ context.Context, _, id string, s *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error) {
+	for _, wh := range c.webhooks {
+		if wh.ID == id {
+			wh.URL, wh.EventTypes, wh.Enabled = s.URL, s.EventTypes, s.Enabled
+			return wh, nil
+		}
+	}
+	return nil, repoNotFound{}
+}
+
+func TestProjectClass(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	class := &v1beta1.ProjectClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec: v1beta1.ProjectClassSpec{
+			AutoScanImages: ptrBool(true),
+			Webhooks: []v1beta1.ProjectClassWebhook{{
+				Name:       "ci",
+				URL:        "https://ci.example.com/hook",
+				EventTypes: []string{"PUSH_ARTIFACT"},
+			}},
+		},
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(class).Build()
+	h := newClassProject()
+	e := &external{service: h, kube: kube}
+
+	cr := &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "harbor", Labels: map[string]string{v1beta1.LabelProjectClass: "team"}},
+		Spec:       v1beta1.ProjectSpec{ForProvider: v1beta1.ProjectParameters{Name: "team-a"}},
+	}
+	apply := func() {
+		t.Helper()
+		obs, err := e.Observe(ctx, cr)
+		if err != nil {
+			t.Fatalf("Observe() error = %v", err)
+		}
+		if obs.ResourceUpToDate {
+			t.Fatal("Observe() reported up to date before the class was applied")
+		}
+		if _, err := e.Update(ctx, cr); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if obs, err = e.Observe(ctx, cr); err != nil || !obs.ResourceUpToDate {
+			t.Fatalf("Observe() after Update() = %+v, %v; want up to date", obs, err)
+		}
+	}
+
+	apply()
+	if h.metadata["auto_scan"] != "true" {
+		t.Errorf("auto_scan = %q, want the class default true", h.metadata["auto_scan"])
+	}
+	if len(h.webhooks) != 1 || h.webhooks[0].URL != "https://ci.example.com/hook" {
+		t.Errorf("webhooks = %+v, want the class webhook", h.webhooks)
+	}
+	if got := cr.Status.AtProvider.ProjectClass; got == nil || *got != "team" {
+		t.Errorf("status.atProvider.projectClass = %v, want team", got)
+	}
+
+	// Changes to the class are re-applied.
+	class.Spec.AutoScanImages = ptrBool(false)
+	class.Spec.Webhooks[0].URL = "https://ci.example.com/v2"
+	if err := kube.Update(ctx, class); err != nil {
+		t.Fatal(err)
+	}
+	apply()
+	if h.metadata["auto_scan"] != "false" {
+		t.Errorf("auto_scan = %q, want the changed class default false", h.metadata["auto_scan"])
+	}
+	if len(h.webhooks) != 1 || h.webhooks[0].URL != "https://ci.example.com/v2" {
+		t.Errorf("webhooks = %+v, want the class webhook updated in place", h.webhooks)
+	}
+
+	cr.Spec.ForProvider.ProjectClassName = getStringPtr("missing")
+	if _, err := e.Observe(ctx, cr); err == nil {
+		t.Error("Observe() should fail when the ProjectClass does not exist")
+	}
+}
+
+func TestEnqueueClassMembers(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	byLabel := &v1beta1.Project{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns", Labels: map[string]string{v1beta1.LabelProjectClass: "team"}}}
+	byName := &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns", Labels: map[string]string{v1beta1.LabelProjectClass: "other"}},
+		Spec:       v1beta1.ProjectSpec{ForProvider: v1beta1.ProjectParameters{ProjectClassName: getStringPtr("team")}},
+	}
+	other := &v1beta1.Project{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "ns"}}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(byLabel, byName, other).Build()
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	class := &v1beta1.ProjectClass{ObjectMeta: metav1.ObjectMeta{Name: "team"}}
+	enqueueClassMembers(kube).Update(context.Background(), event.UpdateEvent{ObjectOld: class, ObjectNew: class}, q)
+	var got []string
+	for q.Len() > 0 {
+		r, _ := q.Get()
+		got = append(got, r.String())
+		q.Done(r)
+	}
+	sort.Strings(got)
+	if want := []string{"ns/a", "ns/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("enqueued %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	errRepoExemptions = "cannot label exempt repositories"
+
+	// severityGateExemptLabel marks the artifacts of exempt repositories.
+	severityGateExemptLabel     = "severity-gate-exempt"
+	severityGateExemptLabelDesc = "Repository listed in the Project's repoExemptions"
+)
+
+// observeRepoExemptions reports whether every artifact in the exempt
+// repositories, and no others, carries the exemption label. It also sets the
+// UnsupportedFeature condition.
+func (c *external) observeRepoExemptions(ctx context.Context, cr *v1beta1.Project, projectName string) (bool, error) {
+	want := cr.Spec.ForProvider.RepoExemptions
+	obs := cr.Status.AtProvider.RepoExemptions
+
+	if len(want) == 0 {
+		if cr.GetCondition(v1beta1.TypeUnsupportedFeature).Status == corev1.ConditionTrue {
+			cr.SetConditions(v1beta1.AllFeaturesSupported())
+		}
+		// A recorded label must be deleted.
+		return obs == nil, nil
+	}
+	cr.SetConditions(v1beta1.RepoExemptionsNotEnforced(len(want)))
+
+	if obs == nil || obs.LabelID == nil || !sameRepositories(want, append(append([]string{}, obs.Repositories...), obs.MissingRepositories...)) {
+		return false, nil
+	}
+	for _, repo := range obs.Repositories {
+		_, without, err := c.service.ListArtifactsByLabel(ctx, projectName, repo, *obs.LabelID)
+		if harborclients.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if len(without) > 0 {
+			return false, nil
+		}
+	}
+	for _, repo := range obs.MissingRepositories {
+		// Label the repository once it has been pushed to.
+		if _, _, err := c.service.ListArtifactsByLabel(ctx, projectName, repo, *obs.LabelID); !harborclients.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// applyRepoExemptions labels the artifacts of exempt repositories, and
+// removes the label from repositories that are no longer exempt.
+func (c *external) applyRepoExemptions(ctx context.Context, cr *v1beta1.Project, projectName string) error {
+	want := cr.Spec.ForProvider.RepoExemptions
+	obs := cr.Status.AtProvider.RepoExemptions
+
+	if len(want) == 0 {
+		if obs != nil && obs.LabelID != nil {
+			// Deleting the label detaches it from every artifact.
+			if err := c.service.DeleteLabel(ctx, *obs.LabelID); err != nil {
+				return err
+			}
+		}
+		cr.Status.AtProvider.RepoExemptions = nil
+		return nil
+	}
+
+	if cr.Status.AtProvider.ID == nil {
+		return errors.New("project ID has not been observed")
+	}
+	projectID, err := strconv.ParseInt(*cr.Status.AtProvider.ID, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid project ID %q", *cr.Status.AtProvider.ID)
+	}
+	labelID, err := c.service.EnsureProjectLabel(ctx, projectID, severityGateExemptLabel, severityGateExemptLabelDesc)
+	if err != nil {
+		return err
+	}
+
+	if obs != nil && obs.LabelID != nil && *obs.LabelID == labelID {
+		for _, repo := range obs.Repositories {
+			if containsRepository(want, repo) {
+				continue
+			}
+			if err := c.labelRepository(ctx, projectName, repo, labelID, false); err != nil && !harborclients.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	next := &v1beta1.RepoExemptionsObservation{LabelID: &labelID}
+	repos := append([]string{}, want...)
+	sort.Strings(repos)
+	for _, repo := range repos {
+		err := c.labelRepository(ctx, projectName, repo, labelID, true)
+		switch {
+		case harborclients.IsNotFound(err):
+			next.MissingRepositories = append(next.MissingRepositories, repo)
+		case err != nil:
+			return err
+		default:
+			next.Repositories = append(next.Repositories, repo)
+		}
+	}
+	cr.Status.AtProvider.RepoExemptions = next
+	return nil
+}
+
+// labelRepository attaches the label to, or detaches it from, every artifact
+// in a repository that does not already match.
+func (c *external) labelRepository(ctx context.Context, projectName, repo string, labelID int64, attached bool) error {
+	with, without, err := c.service.ListArtifactsByLabel(ctx, projectName, repo, labelID)
+	if err != nil {
+		return err
+	}
+	change := with
+	if attached {
+		change = without
+	}
+	for _, digest := range change {
+		if err := c.service.SetArtifactLabel(ctx, projectName, repo, digest, labelID, attached); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameRepositories(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, r := range a {
+		if !containsRepository(b, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsRepository(repos []string, repo string) bool {
+	for _, r := range repos {
+		if r == repo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type repoNotFound struct{}
+
+func (repoNotFound) Error() string        { return "repository not found" }
+func (repoNotFound) IsCode(code int) bool { return code == 404 }
+
+// labelledRepos fakes the artifacts of a project and whether each carries
+// the exemption label.
+type labelledRepos map[string]map[string]bool
+
+func (r labelledRepos) client() *mockProjectClient {
+	return &mockProjectClient{
+		getProjectFunc: func(_ context.Context, name string) (*harborclients.ProjectStatus, error) {
+			return &harborclients.ProjectStatus{ID: "3", Name: name}, nil
+		},
+		updateProjectFunc: func(_ context.Context, name string, _ *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+			return &harborclients.ProjectStatus{ID: "3", Name: name}, nil
+		},
+		ensureProjectLabelFunc: func(_ context.Context, projectID int64, name, _ string) (int64, error) {
+			if projectID != 3 || name != severityGateExemptLabel {
+				return 0, repoNotFound{}
+			}
+			return 9, nil
+		},
+		listArtifactsByLabelFunc: func(_ context.Context, _, repo string, _ int64) ([]string, []string, error) {
+			arts, ok := r[repo]
+			if !ok {
+				return nil, nil, repoNotFound{}
+			}
+			var with, without []string
+			for digest, labelled := range arts {
+				if labelled {
+					with = append(with, digest)
+				} else {
+					without = append(without, digest)
+				}
+			}
+			return with, without, nil
+		},
+		setArtifactLabelFunc: func(_ context.Context, _, repo, digest string, _ int64, attached bool) error {
+			r[repo][digest] = attached
+			return nil
+		},
+		deleteLabelFunc: func(context.Context, int64) error {
+			for _, arts := range r {
+				for digest := range arts {
+					arts[digest] = false
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func exemptProject(repos ...string) *v1beta1.Project {
+	return &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-project"},
+		Spec: v1beta1.ProjectSpec{ForProvider: v1beta1.ProjectParameters{
+			Name:           "test-project",
+			RepoExemptions: repos,
+		}},
+	}
+}
+
+func TestRepoExemptions(t *testing.T) {
+	ctx := context.Background()
+	repos := labelledRepos{
+		"base":    {"sha256:a": false, "sha256:b": false},
+		"tools":   {"sha256:c": false},
+		"private": {"sha256:d": false},
+	}
+	e := &external{service: repos.client()}
+	cr := exemptProject("tools", "base", "later")
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false before exempt repositories are labelled")
+	}
+	if c := cr.GetCondition(v1beta1.TypeUnsupportedFeature); c.Status != corev1.ConditionTrue || c.Reason != v1beta1.ReasonRepoExemptionsNotEnforced {
+		t.Errorf("UnsupportedFeature condition = %+v", c)
+	}
+
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	want := labelledRepos{
+		"base":    {"sha256:a": true, "sha256:b": true},
+		"tools":   {"sha256:c": true},
+		"private": {"sha256:d": false},
+	}
+	if !reflect.DeepEqual(repos, want) {
+		t.Errorf("labels after Update = %v, want %v", repos, want)
+	}
+	got := cr.Status.AtProvider.RepoExemptions
+	if got == nil || *got.LabelID != 9 || !reflect.DeepEqual(got.Repositories, []string{"base", "tools"}) || !reflect.DeepEqual(got.MissingRepositories, []string{"later"}) {
+		t.Errorf("RepoExemptions status = %+v", got)
+	}
+
+	if obs, err = e.Observe(ctx, cr); err != nil || !obs.ResourceUpToDate {
+		t.Errorf("Observe() after Update = %+v, %v, want up to date", obs, err)
+	}
+
+	// New pushes to exempt and missing repositories are labelled.
+	repos["base"]["sha256:e"] = false
+	if obs, _ = e.Observe(ctx, cr); obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false after a push to an exempt repository")
+	}
+	delete(repos["base"], "sha256:e")
+	repos["later"] = map[string]bool{"sha256:f": false}
+	if obs, _ = e.Observe(ctx, cr); obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false once a missing repository exists")
+	}
+
+	// Removing a repository unlabels it.
+	cr.Spec.ForProvider.RepoExemptions = []string{"base", "later"}
+	if obs, _ = e.Observe(ctx, cr); obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false after an exemption is removed")
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if repos["tools"]["sha256:c"] || !repos["later"]["sha256:f"] {
+		t.Errorf("labels after removing tools = %v", repos)
+	}
+
+	// Removing every exemption deletes the label and clears the condition.
+	cr.Spec.ForProvider.RepoExemptions = nil
+	if obs, _ = e.Observe(ctx, cr); obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false until the label is deleted")
+	}
+	if c := cr.GetCondition(v1beta1.TypeUnsupportedFeature); c.Status != corev1.ConditionFalse {
+		t.Errorf("UnsupportedFeature condition = %+v, want False", c)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Status.AtProvider.RepoExemptions != nil || repos["base"]["sha256:a"] {
+		t.Errorf("exemptions not removed: status %+v, labels %v", cr.Status.AtProvider.RepoExemptions, repos)
+	}
+	if obs, _ = e.Observe(ctx, cr); !obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be true once the label is deleted")
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+const (
+	errListGroupMappings = "cannot list OIDCGroupMappings"
+	errDefaultGroups     = "cannot add default OIDC group members"
+)
+
+// defaultGroupsPending reports whether cr is a project the provider created
+// whose default OIDC group members are not added yet.
+func defaultGroupsPending(cr *v1beta1.Project) bool {
+	applied := cr.Status.AtProvider.DefaultGroupsApplied
+	return applied != nil && !*applied
+}
+
+// applyDefaultGroups adds the groups of the OIDCGroupMappings that apply to
+// cr as members of its newly created project. Until they are all added the
+// project reports itself pending, so that a failure is retried by Update.
+func (c *external) applyDefaultGroups(ctx context.Context, cr *v1beta1.Project, projectName string) error {
+	pending := false
+	cr.Status.AtProvider.DefaultGroupsApplied = &pending
+
+	l := &v1beta1.OIDCGroupMappingList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListGroupMappings)
+	}
+	sort.Slice(l.Items, func(i, j int) bool { return l.Items[i].GetName() < l.Items[j].GetName() })
+
+	pc := ""
+	if ref := cr.GetProviderConfigReference(); ref != nil {
+		pc = ref.Name
+	}
+	for _, m := range l.Items {
+		if n := m.Spec.ProviderConfigName; n != nil && *n != pc {
+			continue
+		}
+		for _, g := range m.Spec.Groups {
+			if err := c.service.AddProjectGroupMember(ctx, projectName, g.GroupName, harborclients.OIDCGroupType, g.Role); err != nil {
+				return errors.Wrapf(err, "%s: OIDCGroupMapping %q", errDefaultGroups, m.GetName())
+			}
+		}
+	}
+
+	applied := true
+	cr.Status.AtProvider.DefaultGroupsApplied = &applied
+	return nil
+}
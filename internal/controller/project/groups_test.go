@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newKube returns a fake API server holding objs.
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestDefaultGroups(t *testing.T) {
+	ctx := context.Background()
+	other := "harbor-b"
+	kube := newKube(t,
+		&v1beta1.OIDCGroupMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec: v1beta1.OIDCGroupMappingSpec{Groups: []v1beta1.OIDCGroupRole{
+				{GroupName: "platform-admins", Role: "projectAdmin"},
+				{GroupName: "auditors", Role: "guest"},
+			}},
+		},
+		&v1beta1.OIDCGroupMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: "elsewhere"},
+			Spec: v1beta1.OIDCGroupMappingSpec{
+				ProviderConfigName: &other,
+				Groups:             []v1beta1.OIDCGroupRole{{GroupName: "b-admins", Role: "projectAdmin"}},
+			},
+		},
+	)
+
+	var added []string
+	fail := true
+	svc := &mockProjectClient{
+		createProjectFunc: func(_ context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+			return &harborclients.ProjectStatus{Name: spec.Name}, nil
+		},
+		updateProjectFunc: func(_ context.Context, _ string, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+			return &harborclients.ProjectStatus{Name: spec.Name}, nil
+		},
+		addProjectGroupMemberFunc: func(_ context.Context, project, group string, groupType int64, role string) error {
+			if fail {
+				return errors.New("boom")
+			}
+			if project != "team-a" || groupType != harborclients.OIDCGroupType {
+				t.Errorf("group %s added to %s with type %d", group, project, groupType)
+			}
+			added = append(added, group+":"+role)
+			return nil
+		},
+	}
+	e := &external{service: svc, kube: kube}
+	cr := &v1beta1.Project{Spec: v1beta1.ProjectSpec{ForProvider: v1beta1.ProjectParameters{Name: "team-a"}}}
+	cr.Spec.ProviderConfigReference = &xpv1.ProviderConfigReference{Name: "harbor-a"}
+
+	if _, err := e.Create(ctx, cr); err == nil {
+		t.Fatal("Create() should fail when a default group cannot be added")
+	}
+	if !defaultGroupsPending(cr) {
+		t.Fatal("default groups should be pending after a failure, so that Update retries them")
+	}
+
+	fail = false
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if defaultGroupsPending(cr) {
+		t.Error("default groups should not be pending once added")
+	}
+	want := []string{"platform-admins:projectAdmin", "auditors:guest"}
+	if len(added) != len(want) || added[0] != want[0] || added[1] != want[1] {
+		t.Errorf("groups added = %v, want %v", added, want)
+	}
+
+	// Projects the provider did not create are left alone.
+	adopted := &v1beta1.Project{}
+	if defaultGroupsPending(adopted) {
+		t.Error("default groups should never be pending for an adopted project")
+	}
+}
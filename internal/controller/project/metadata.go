@@ -0,0 +1,102 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+)
+
+// protectedMetadataKeys are owned by first-class fields or by other managed
+// resources and are never removed by the Replace policy.
+var protectedMetadataKeys = map[string]bool{
+	"public":                      true,
+	"enable_content_trust":        true,
+	"enable_content_trust_cosign": true,
+	"auto_scan":                   true,
+	"prevent_vul":                 true,
+	"severity":                    true,
+	"reuse_sys_cve_allowlist":     true,
+	"retention_id":                true,
+}
+
+// booleanMetadata are the metadata keys Harbor stores as "true" or "false",
+// with the value Harbor applies while a key is not set.
+var booleanMetadata = map[string]ctrlutil.BoolField{
+	"public":                      {},
+	"enable_content_trust":        {},
+	"enable_content_trust_cosign": {},
+	"auto_scan":                   {},
+	"prevent_vul":                 {},
+	"auto_sbom_generation":        {},
+	"reuse_sys_cve_allowlist":     {Default: true},
+}
+
+// managesMetadata reports whether the project's metadata needs reconciling.
+func managesMetadata(p v1beta1.ProjectParameters) bool {
+	return len(p.Metadata) > 0 || p.AutoSBOMGeneration != nil || replaceMetadata(p)
+}
+
+func replaceMetadata(p v1beta1.ProjectParameters) bool {
+	return p.MetadataPolicy != nil && *p.MetadataPolicy == v1beta1.MetadataPolicyReplace
+}
+
+// desiredMetadata returns the metadata entries this resource manages, dropping
+// keys that are controlled by a first-class field which is set.
+func desiredMetadata(p v1beta1.ProjectParameters) map[string]string {
+	shadowed := map[string]bool{
+		"public":                      p.Public != nil,
+		"enable_content_trust":        p.EnableContentTrust != nil,
+		"enable_content_trust_cosign": p.EnableContentTrustCosign != nil,
+		"auto_scan":                   p.AutoScanImages != nil,
+		"prevent_vul":                 p.PreventVulnerableImages != nil,
+		"severity":                    p.Severity != nil,
+		"auto_sbom_generation":        p.AutoSBOMGeneration != nil,
+		"retention_id":                true,
+	}
+
+	desired := make(map[string]string, len(p.Metadata))
+	for k, v := range p.Metadata {
+		if shadowed[k] {
+			continue
+		}
+		desired[k] = v
+	}
+	if p.AutoSBOMGeneration != nil {
+		desired["auto_sbom_generation"] = strconv.FormatBool(*p.AutoSBOMGeneration)
+	}
+	return desired
+}
+
+// metadataDiff returns the keys that must be set and removed to move observed
+// to desired. Unless replace is true, keys absent from desired are left alone.
+// Boolean keys are compared as booleans, and an unset key has Harbor's
+// default.
+func metadataDiff(desired, observed map[string]string, replace bool) (map[string]string, []string) {
+	set := map[string]string{}
+	for k, v := range desired {
+		cur, ok := observed[k]
+		if f, isBool := booleanMetadata[k]; isBool && f.MatchesString(v, cur) {
+			continue
+		}
+		if !ok || cur != v {
+			set[k] = v
+		}
+	}
+
+	var remove []string
+	if replace {
+		for k := range observed {
+			if _, ok := desired[k]; !ok && !protectedMetadataKeys[k] {
+				remove = append(remove, k)
+			}
+		}
+		sort.Strings(remove)
+	}
+	return set, remove
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+)
+
+func TestDesiredMetadata(t *testing.T) {
+	params := v1beta1.ProjectParameters{
+		Public:   ptrBool(true),
+		Severity: nil,
+		Metadata: map[string]string{
+			"public":               "false",
+			"severity":             "high",
+			"retention_id":         "7",
+			"auto_sbom_generation": "true",
+		},
+	}
+
+	got := desiredMetadata(params)
+	want := map[string]string{
+		"severity":             "high",
+		"auto_sbom_generation": "true",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("desiredMetadata() = %v, want %v", got, want)
+	}
+}
+
+func TestDesiredMetadataAutoSBOM(t *testing.T) {
+	params := v1beta1.ProjectParameters{
+		AutoSBOMGeneration: ptrBool(false),
+		Metadata:           map[string]string{"auto_sbom_generation": "true"},
+	}
+
+	if !managesMetadata(v1beta1.ProjectParameters{AutoSBOMGeneration: ptrBool(true)}) {
+		t.Error("managesMetadata() = false with autoSbomGeneration set")
+	}
+	got := desiredMetadata(params)
+	want := map[string]string{"auto_sbom_generation": "false"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("desiredMetadata() = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataDiff(t *testing.T) {
+	observed := map[string]string{
+		"public":               "true",
+		"retention_id":         "7",
+		"auto_sbom_generation": "false",
+		"proxy_speed_kb":       "1024",
+	}
+	desired := map[string]string{
+		"auto_sbom_generation": "true",
+	}
+
+	cases := map[string]struct {
+		replace    bool
+		wantSet    map[string]string
+		wantRemove []string
+	}{
+		"Merge": {
+			replace: false,
+			wantSet: map[string]string{"auto_sbom_generation": "true"},
+		},
+		"Replace": {
+			replace:    true,
+			wantSet:    map[string]string{"auto_sbom_generation": "true"},
+			wantRemove: []string{"proxy_speed_kb"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			set, remove := metadataDiff(desired, observed, tc.replace)
+			if !reflect.DeepEqual(set, tc.wantSet) {
+				t.Errorf("set = %v, want %v", set, tc.wantSet)
+			}
+			if !reflect.DeepEqual(remove, tc.wantRemove) {
+				t.Errorf("remove = %v, want %v", remove, tc.wantRemove)
+			}
+		})
+	}
+}
+
+func TestMetadataDiffInSync(t *testing.T) {
+	observed := map[string]string{"auto_sbom_generation": "true", "public": "false"}
+	set, remove := metadataDiff(map[string]string{"auto_sbom_generation": "true"}, observed, true)
+	if len(set) != 0 || len(remove) != 0 {
+		t.Errorf("metadataDiff() = %v, %v, want no changes", set, remove)
+	}
+}
+
+func TestMetadataDiffBooleans(t *testing.T) {
+	cases := map[string]struct {
+		desired, observed map[string]string
+		wantSet           map[string]string
+	}{
+		"OtherCase": {
+			desired:  map[string]string{"auto_scan": "false", "public": "true"},
+			observed: map[string]string{"auto_scan": "False", "public": "True"},
+			wantSet:  map[string]string{},
+		},
+		"FalseOmitted": {
+			desired:  map[string]string{"prevent_vul": "false", "enable_content_trust": "false"},
+			observed: map[string]string{},
+			wantSet:  map[string]string{},
+		},
+		"TrueOmitted": {
+			desired:  map[string]string{"auto_scan": "true"},
+			observed: map[string]string{},
+			wantSet:  map[string]string{"auto_scan": "true"},
+		},
+		"DefaultTrueOmitted": {
+			desired:  map[string]string{"reuse_sys_cve_allowlist": "true"},
+			observed: map[string]string{},
+			wantSet:  map[string]string{},
+		},
+		"DefaultTrueDisabled": {
+			desired:  map[string]string{"reuse_sys_cve_allowlist": "false"},
+			observed: map[string]string{},
+			wantSet:  map[string]string{"reuse_sys_cve_allowlist": "false"},
+		},
+		"NotBooleanKey": {
+			desired:  map[string]string{"severity": "high"},
+			observed: map[string]string{"severity": "High"},
+			wantSet:  map[string]string{"severity": "high"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			set, _ := metadataDiff(tc.desired, tc.observed, false)
+			if !reflect.DeepEqual(set, tc.wantSet) {
+				t.Errorf("set = %v, want %v", set, tc.wantSet)
+			}
+		})
+	}
+}
+
+func TestManagesMetadata(t *testing.T) {
+	replace := v1beta1.MetadataPolicyReplace
+	merge := v1beta1.MetadataPolicyMerge
+
+	cases := map[string]struct {
+		params v1beta1.ProjectParameters
+		want   bool
+	}{
+		"NoMetadata":        {params: v1beta1.ProjectParameters{}, want: false},
+		"MergeWithoutKeys":  {params: v1beta1.ProjectParameters{MetadataPolicy: &merge}, want: false},
+		"ReplaceWithoutKey": {params: v1beta1.ProjectParameters{MetadataPolicy: &replace}, want: true},
+		"WithKeys":          {params: v1beta1.ProjectParameters{Metadata: map[string]string{"auto_scan": "true"}}, want: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := managesMetadata(tc.params); got != tc.want {
+				t.Errorf("managesMetadata() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
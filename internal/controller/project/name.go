@@ -0,0 +1,58 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"strings"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+)
+
+// maxProjectNameLength is the longest project name Harbor accepts.
+const maxProjectNameLength = 255
+
+// harborProjectName returns the name of the Harbor project p describes:
+// its name, normalized when p asks for it.
+func harborProjectName(p v1beta1.ProjectParameters) string {
+	if p.NormalizeName == nil || !*p.NormalizeName {
+		return p.Name
+	}
+	return normalizeProjectName(p.Name)
+}
+
+// normalizeProjectName turns name into one that matches Harbor's project
+// name pattern, ^[a-z0-9]+(?:[._-][a-z0-9]+)*$. Letters are lower cased. A
+// single '.', '_' or '-' between letters and digits is kept; any other run
+// of characters becomes a single '-'. Separators are trimmed from both ends,
+// also after truncating to the longest name Harbor accepts.
+func normalizeProjectName(name string) string {
+	var b strings.Builder
+	var run []rune
+	flush := func() {
+		switch {
+		case len(run) == 0 || b.Len() == 0:
+		case len(run) == 1 && strings.ContainsRune("._-", run[0]):
+			b.WriteRune(run[0])
+		default:
+			b.WriteByte('-')
+		}
+		run = run[:0]
+	}
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			flush()
+			b.WriteRune(r)
+			continue
+		}
+		run = append(run, r)
+	}
+	// A trailing run is dropped.
+
+	n := b.String()
+	if len(n) > maxProjectNameLength {
+		n = strings.TrimRight(n[:maxProjectNameLength], "._-")
+	}
+	return n
+}
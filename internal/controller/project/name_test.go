@@ -0,0 +1,59 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+)
+
+var harborProjectNamePattern = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`)
+
+func TestNormalizeProjectName(t *testing.T) {
+	cases := map[string]struct {
+		name string
+		want string
+	}{
+		"AlreadyValid":       {name: "team-a.web_1", want: "team-a.web_1"},
+		"UpperCase":          {name: "TeamA", want: "teama"},
+		"Spaces":             {name: "Team A/Web", want: "team-a-web"},
+		"DoubledSeparators":  {name: "team--a..b", want: "team-a-b"},
+		"EdgeSeparators":     {name: "-_team.a_.", want: "team.a"},
+		"NonASCII":           {name: "Équipe Café", want: "quipe-caf"},
+		"ClaimGeneratedName": {name: "my-claim-7x2kq/registry", want: "my-claim-7x2kq-registry"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := normalizeProjectName(tc.name)
+			if got != tc.want {
+				t.Errorf("normalizeProjectName(%q) = %q, want %q", tc.name, got, tc.want)
+			}
+			if !harborProjectNamePattern.MatchString(got) {
+				t.Errorf("normalizeProjectName(%q) = %q, which Harbor rejects", tc.name, got)
+			}
+		})
+	}
+
+	long := normalizeProjectName(strings.Repeat("a", maxProjectNameLength-1) + "-bc")
+	if len(long) > maxProjectNameLength || !harborProjectNamePattern.MatchString(long) {
+		t.Errorf("normalizing a long name returned %d characters %q", len(long), long)
+	}
+}
+
+func TestHarborProjectName(t *testing.T) {
+	on, off := true, false
+	for _, tc := range []struct {
+		normalize *bool
+		want      string
+	}{{nil, "Team A"}, {&off, "Team A"}, {&on, "team-a"}} {
+		got := harborProjectName(v1beta1.ProjectParameters{Name: "Team A", NormalizeName: tc.normalize})
+		if got != tc.want {
+			t.Errorf("harborProjectName() with normalizeName %v = %q, want %q", tc.normalize, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	userv1beta1 "github.com/rossigee/provider-harbor/apis/user/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	errGetOwner      = "cannot get User named by ownerRef"
+	errOwnerMember   = "cannot get project membership of owner"
+	errOwnerTransfer = "cannot make owner a project admin"
+
+	// ownerRole is the member role that grants an owner's permissions.
+	ownerRole = "projectAdmin"
+)
+
+// ownerName returns the Harbor username named by ownerRef, or "" when the
+// project's owner is not managed.
+func (c *external) ownerName(ctx context.Context, cr *v1beta1.Project) (string, error) {
+	ref := cr.Spec.ForProvider.OwnerRef
+	switch {
+	case ref == nil:
+		return "", nil
+	case ref.Username != nil:
+		return *ref.Username, nil
+	case ref.UserRef != nil:
+		u := &userv1beta1.User{}
+		key := types.NamespacedName{Namespace: cr.GetNamespace(), Name: ref.UserRef.Name}
+		if err := c.kube.Get(ctx, key, u); err != nil {
+			return "", errors.Wrap(err, errGetOwner)
+		}
+		return u.Spec.ForProvider.Username, nil
+	}
+	return "", errors.New("one of ownerRef.username and ownerRef.userRef is required")
+}
+
+// observeOwner reports whether the owner named by ownerRef owns the project
+// or is one of its admins.
+func (c *external) observeOwner(ctx context.Context, cr *v1beta1.Project, project *harborclients.ProjectStatus) (bool, error) {
+	owner, err := c.ownerName(ctx, cr)
+	if err != nil || owner == "" {
+		cr.Status.AtProvider.OwnerRole = nil
+		return true, err
+	}
+	if project.OwnerName == owner {
+		cr.Status.AtProvider.OwnerRole = getStringPtr(ownerRole)
+		return true, nil
+	}
+
+	m, err := c.service.GetProjectMember(ctx, project.Name, owner)
+	if harborclients.IsNotFound(err) {
+		cr.Status.AtProvider.OwnerRole = nil
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errOwnerMember)
+	}
+	cr.Status.AtProvider.OwnerRole = getStringPtr(m.Role)
+	return m.Role == ownerRole, nil
+}
+
+// transferOwner makes the owner named by ownerRef an admin of the project,
+// adding them as a member if they are not one already.
+func (c *external) transferOwner(ctx context.Context, cr *v1beta1.Project, projectName string) error {
+	owner, err := c.ownerName(ctx, cr)
+	if err != nil || owner == "" {
+		return err
+	}
+
+	m, err := c.service.GetProjectMember(ctx, projectName, owner)
+	switch {
+	case harborclients.IsNotFound(err):
+		err = c.service.AddProjectMember(ctx, projectName, owner, ownerRole)
+	case err != nil:
+		return errors.Wrap(err, errOwnerMember)
+	case m.Role != ownerRole:
+		err = c.service.UpdateProjectMember(ctx, projectName, owner, ownerRole)
+	}
+	if err != nil {
+		return errors.Wrap(err, errOwnerTransfer)
+	}
+	cr.Status.AtProvider.OwnerRole = getStringPtr(ownerRole)
+	return nil
+}
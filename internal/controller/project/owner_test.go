@@ -0,0 +1,131 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	userv1beta1 "github.com/rossigee/provider-harbor/apis/user/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// members fakes the membership of a project, by username.
+type members map[string]string
+
+func (m members) client() *mockProjectClient {
+	return &mockProjectClient{
+		getProjectFunc: func(_ context.Context, name string) (*harborclients.ProjectStatus, error) {
+			return &harborclients.ProjectStatus{ID: "3", Name: name, OwnerID: 1, OwnerName: "admin"}, nil
+		},
+		updateProjectFunc: func(_ context.Context, name string, _ *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+			return &harborclients.ProjectStatus{ID: "3", Name: name}, nil
+		},
+		getProjectMemberFunc: func(_ context.Context, _, username string) (*harborclients.MemberStatus, error) {
+			role, ok := m[username]
+			if !ok {
+				return nil, repoNotFound{}
+			}
+			return &harborclients.MemberStatus{MemberName: username, Role: role}, nil
+		},
+		addProjectMemberFunc: func(_ context.Context, _, username, role string) error {
+			m[username] = role
+			return nil
+		},
+		updateProjectMemberFunc: func(_ context.Context, _, username, role string) error {
+			m[username] = role
+			return nil
+		},
+	}
+}
+
+func ownedProject(ref *v1beta1.ProjectOwnerReference) *v1beta1.Project {
+	return &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "harbor"},
+		Spec: v1beta1.ProjectSpec{ForProvider: v1beta1.ProjectParameters{
+			Name:     "team",
+			OwnerRef: ref,
+		}},
+	}
+}
+
+func TestOwnerTransfer(t *testing.T) {
+	cases := map[string]members{
+		"NotAMember": {},
+		"Developer":  {"alice": "developer"},
+	}
+
+	for name, m := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			e := &external{service: m.client()}
+			cr := ownedProject(&v1beta1.ProjectOwnerReference{Username: getStringPtr("alice")})
+
+			obs, err := e.Observe(ctx, cr)
+			if err != nil || obs.ResourceUpToDate {
+				t.Fatalf("Observe() = %+v, %v; want not up to date", obs, err)
+			}
+			if got := *cr.Status.AtProvider.OwnerName; got != "admin" {
+				t.Errorf("ownerName = %s, want the recorded owner admin", got)
+			}
+
+			if _, err := e.Update(ctx, cr); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if m["alice"] != ownerRole {
+				t.Errorf("alice is %q, want %s", m["alice"], ownerRole)
+			}
+
+			obs, err = e.Observe(ctx, cr)
+			if err != nil || !obs.ResourceUpToDate {
+				t.Fatalf("Observe() after update = %+v, %v; want up to date", obs, err)
+			}
+			if got := cr.Status.AtProvider.OwnerRole; got == nil || *got != ownerRole {
+				t.Errorf("ownerRole = %v, want %s", got, ownerRole)
+			}
+		})
+	}
+}
+
+func TestOwnerIsRecordedOwner(t *testing.T) {
+	m := members{}
+	e := &external{service: m.client()}
+	cr := ownedProject(&v1beta1.ProjectOwnerReference{Username: getStringPtr("admin")})
+
+	obs, err := e.Observe(context.Background(), cr)
+	if err != nil || !obs.ResourceUpToDate {
+		t.Errorf("Observe() = %+v, %v; want the recorded owner up to date", obs, err)
+	}
+}
+
+func TestOwnerUserRef(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := userv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	u := &userv1beta1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "bob-user", Namespace: "harbor"},
+		Spec:       userv1beta1.UserSpec{ForProvider: userv1beta1.UserParameters{Username: "bob"}},
+	}
+	m := members{}
+	e := &external{service: m.client(), kube: fake.NewClientBuilder().WithScheme(s).WithObjects(u).Build()}
+
+	cr := ownedProject(&v1beta1.ProjectOwnerReference{UserRef: &v1beta1.UserReference{Name: "bob-user"}})
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if m["bob"] != ownerRole {
+		t.Errorf("bob is %q, want %s", m["bob"], ownerRole)
+	}
+
+	cr = ownedProject(&v1beta1.ProjectOwnerReference{UserRef: &v1beta1.UserReference{Name: "missing"}})
+	if _, err := e.Observe(context.Background(), cr); err == nil {
+		t.Error("Observe() should fail when the referenced User does not exist")
+	}
+}
@@ -6,6 +6,7 @@ package project
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
@@ -17,10 +18,11 @@ import (
 	harborclients "github.com/rossigee/provider-harbor/internal/clients"
 	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
 	"github.com/rossigee/provider-harbor/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sort"
 	"time"
 )
 
@@ -34,29 +36,47 @@ const (
 	errProjectGet    = "cannot get Harbor project"
 	errProjectUpdate = "cannot update Harbor project"
 	errProjectDelete = "cannot delete Harbor project"
+	errProjectRename = "cannot rename Harbor project %q to %q: Harbor cannot rename projects; " +
+		"set spec.forProvider.name back, or point the crossplane.io/external-name annotation at the new project"
+
+	errProjectMetadata = "cannot reconcile Harbor project metadata"
 )
 
 // Setup adds a controller that reconciles Project managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.ProjectGroupVersionKind.Kind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.ProjectGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	if err := ctrlutil.IndexExternalIdentity(context.Background(), mgr.GetFieldIndexer(), &v1beta1.Project{}, projectIdentity); err != nil {
+		return err
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorder(name))
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithDuplicateDetection(mgr.GetClient(), newProjectList, projectIdentity, ctrlutil.WithProtection(mgr.GetClient(), projectTarget, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithUpdateDebounce(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
-		}),
+			recorder:     recorder,
+		}))))))))))),
 		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
-		managed.WithPollInterval(1*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		// The external name is the Harbor project's name, which comes from
+		// spec.forProvider.name rather than metadata.name; it is only set
+		// by hand to import a project, or by Observe and Create.
+		managed.WithInitializers(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithPollIntervalHook(ctrlutil.DebouncedPollInterval),
+		managed.WithRecorder(recorder),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ProjectGroupVersionKind), opts...)
 
 	// Create the controller
-	rl := ratelimiter.NewGlobal(10)
 	_, err := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.Project{}).
-		Build(ratelimiter.NewReconciler(name, r, rl))
+		Watches(&v1beta1.ProjectClass{}, enqueueClassMembers(mgr.GetClient())).
+		Build(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 	if err != nil {
 		return err
 	}
@@ -64,11 +84,31 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	return nil
 }
 
+// projectIdentity returns the name of the Harbor project a Project manages:
+// the one it adopted, or else the one it would create.
+func projectIdentity(mg resource.Managed) string {
+	if name := ctrlutil.GetExternalName(mg); name != "" {
+		return name
+	}
+	if cr, ok := mg.(*v1beta1.Project); ok {
+		return harborProjectName(cr.Spec.ForProvider)
+	}
+	return ""
+}
+
+// projectTarget returns the project a Project is.
+func projectTarget(mg resource.Managed) ctrlutil.Target {
+	return ctrlutil.Target{Project: projectIdentity(mg)}
+}
+
+func newProjectList() client.ObjectList { return &v1beta1.ProjectList{} }
+
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
 	kube         client.Client
 	newServiceFn func(ctx context.Context, kube client.Client, mg resource.Managed) (harborclients.HarborClienter, error)
+	recorder     event.Recorder
 }
 
 // Connect typically produces an ExternalClient by:
@@ -87,14 +127,15 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{service: svc, kube: c.kube}, nil
+	return &external{service: svc, kube: c.kube, recorder: c.recorder}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	service harborclients.HarborClienter
-	kube    client.Client
+	service  harborclients.HarborClienter
+	kube     client.Client
+	recorder event.Recorder
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -109,19 +150,22 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	// Check if the project exists in Harbor using external name if set, otherwise use desired name
 	externalName := ctrlutil.GetExternalName(cr)
-	projectName := cr.Spec.ForProvider.Name
+	projectName := harborProjectName(cr.Spec.ForProvider)
 	if externalName != "" {
 		// Adoption scenario: use external name to find existing resource
 		projectName = externalName
 	}
 
 	project, err := c.service.GetProject(ctx, projectName)
-	if err != nil {
+	if harborclients.IsNotFound(err) {
 		// If project doesn't exist, we need to create it
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
 	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errProjectGet)
+	}
 
 	// Set external name for future reference and adoption tracking
 	ctrlutil.SetExternalName(cr, project.Name)
@@ -136,19 +180,61 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 	cr.Status.AtProvider.OwnerID = getInt64Ptr(project.OwnerID)
 	cr.Status.AtProvider.OwnerName = getStringPtr(project.OwnerName)
-	cr.Status.AtProvider.RepoCount = getInt64Ptr(project.RepoCount)
-	cr.Status.AtProvider.ChartCount = getInt64Ptr(project.ChartCount)
 	cr.Status.AtProvider.CurrentStorageUsage = getInt64Ptr(project.CurrentStorageUsage)
+	c.observeSummary(ctx, cr, project.Name)
 
-	// Check if resource is up to date
-	upToDate := cr.Spec.ForProvider.Public == nil || *cr.Spec.ForProvider.Public == project.Public
+	// Check if resource is up to date. A spec naming another project than
+	// the one imported or created is drift that Update reports.
+	upToDate := project.Name == harborProjectName(cr.Spec.ForProvider) &&
+		booleanMetadata["public"].Matches(cr.Spec.ForProvider.Public, &project.Public)
+
+	pc, err := c.getClass(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	params, sbomSupported, err := c.effectiveParameters(ctx, withClass(cr.Spec.ForProvider, pc))
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	if managesMetadata(params) {
+		observed, err := c.service.ListProjectMetadata(ctx, project.Name)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errProjectMetadata)
+		}
+		cr.Status.AtProvider.Metadata = observed
+
+		set, remove := metadataDiff(desiredMetadata(params), observed, replaceMetadata(params))
+		if len(set) > 0 || len(remove) > 0 {
+			upToDate = false
+		}
+	}
+	c.observeSBOM(ctx, cr, project.Name, sbomSupported)
+
+	exemptionsUpToDate, err := c.observeRepoExemptions(ctx, cr, project.Name)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errRepoExemptions)
+	}
+	upToDate = upToDate && exemptionsUpToDate
+
+	ownerUpToDate, err := c.observeOwner(ctx, cr, project)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	upToDate = upToDate && ownerUpToDate
+
+	webhooksUpToDate, err := c.classWebhooksUpToDate(ctx, project.Name, pc)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	upToDate = upToDate && webhooksUpToDate && !defaultGroupsPending(cr)
 
 	return managed.ExternalObservation{
 		ResourceExists:   true,
 		ResourceUpToDate: upToDate,
 		ConnectionDetails: managed.ConnectionDetails{
 			"project_name": []byte(project.Name),
-			"project_id":   []byte("1"), // Mock ID
+			"project_id":   []byte(project.ID),
 		},
 	}, nil
 }
@@ -165,32 +251,44 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	cr.SetConditions(xpv1.Creating())
 
-	// Prepare project spec with all parameters
-	spec := &harborclients.ProjectSpec{
-		Name:                     cr.Spec.ForProvider.Name,
-		Public:                   getBoolValue(cr.Spec.ForProvider.Public),
-		EnableContentTrust:       cr.Spec.ForProvider.EnableContentTrust,
-		EnableContentTrustCosign: cr.Spec.ForProvider.EnableContentTrustCosign,
-		AutoScanImages:           cr.Spec.ForProvider.AutoScanImages,
-		PreventVulnerableImages:  cr.Spec.ForProvider.PreventVulnerableImages,
-		Severity:                 cr.Spec.ForProvider.Severity,
-		CVEAllowlist:             cr.Spec.ForProvider.CVEAllowlist,
-		RegistryID:               cr.Spec.ForProvider.RegistryID,
-		StorageLimit:             cr.Spec.ForProvider.StorageLimit,
-		Metadata:                 cr.Spec.ForProvider.Metadata,
+	pc, err := c.getClass(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	// An account the capability probe found unable to create projects would
+	// only be refused by Harbor. Capabilities that cannot be read are
+	// unknown, and Harbor decides.
+	if _, caps, err := ctrlutil.Capabilities(ctx, c.kube, cr); err == nil && caps != nil && !caps.CreateProjects {
+		cond := v1beta1.CreationForbidden(caps.ProjectCreationRestriction)
+		cr.SetConditions(cond)
+		return managed.ExternalCreation{}, errors.New(cond.Message)
 	}
 
 	// Create project in Harbor
-	status, err := c.service.CreateProject(ctx, spec)
+	status, err := c.service.CreateProject(ctx, projectSpec(withClass(cr.Spec.ForProvider, pc)))
+	if harborclients.IsForbidden(err) {
+		return managed.ExternalCreation{}, c.creationForbidden(ctx, cr, err)
+	}
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errProjectCreate)
 	}
+	if cr.GetCondition(v1beta1.TypeCreationPermitted).Status == corev1.ConditionFalse {
+		cr.SetConditions(v1beta1.CreationPermitted())
+	}
 
 	// Set external name for adoption tracking
 	ctrlutil.SetExternalName(cr, status.Name)
 
+	if err := c.applyDefaultGroups(ctx, cr, status.Name); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if err := c.transferOwner(ctx, cr, status.Name); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
 	// Update status with created resource info
-	cr.Status.AtProvider.ID = getStringPtr("1") // Mock ID
+	cr.Status.AtProvider.ID = getStringPtr(status.ID)
 	if status.CreatedAt != (time.Time{}) {
 		cr.Status.AtProvider.CreationTime = &metav1.Time{Time: status.CreatedAt}
 	}
@@ -198,7 +296,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	return managed.ExternalCreation{
 		ConnectionDetails: managed.ConnectionDetails{
 			"project_name": []byte(status.Name),
-			"project_id":   []byte("1"), // Mock ID
+			"project_id":   []byte(status.ID),
 		},
 	}, nil
 }
@@ -212,28 +310,46 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotProject)
 	}
+	if name, want := ctrlutil.GetExternalName(cr), harborProjectName(cr.Spec.ForProvider); name != "" && name != want {
+		return managed.ExternalUpdate{}, errors.Errorf(errProjectRename, name, want)
+	}
 
-	// Prepare updated project spec with all parameters
-	spec := &harborclients.ProjectSpec{
-		Name:                     cr.Spec.ForProvider.Name,
-		Public:                   getBoolValue(cr.Spec.ForProvider.Public),
-		EnableContentTrust:       cr.Spec.ForProvider.EnableContentTrust,
-		EnableContentTrustCosign: cr.Spec.ForProvider.EnableContentTrustCosign,
-		AutoScanImages:           cr.Spec.ForProvider.AutoScanImages,
-		PreventVulnerableImages:  cr.Spec.ForProvider.PreventVulnerableImages,
-		Severity:                 cr.Spec.ForProvider.Severity,
-		CVEAllowlist:             cr.Spec.ForProvider.CVEAllowlist,
-		RegistryID:               cr.Spec.ForProvider.RegistryID,
-		StorageLimit:             cr.Spec.ForProvider.StorageLimit,
-		Metadata:                 cr.Spec.ForProvider.Metadata,
+	pc, err := c.getClass(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
 	}
+	merged := withClass(cr.Spec.ForProvider, pc)
 
 	// Update project in Harbor
-	status, err := c.service.UpdateProject(ctx, cr.Spec.ForProvider.Name, spec)
+	status, err := c.service.UpdateProject(ctx, harborProjectName(cr.Spec.ForProvider), projectSpec(merged))
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errProjectUpdate)
 	}
 
+	params, _, err := c.effectiveParameters(ctx, merged)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if managesMetadata(params) {
+		if err := c.updateMetadata(ctx, status.Name, params); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errProjectMetadata)
+		}
+	}
+	if err := c.applyRepoExemptions(ctx, cr, status.Name); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errRepoExemptions)
+	}
+	if err := c.transferOwner(ctx, cr, status.Name); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if err := c.applyClassWebhooks(ctx, status.Name, pc); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if defaultGroupsPending(cr) {
+		if err := c.applyDefaultGroups(ctx, cr, status.Name); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+
 	// Update status
 	if status.CreatedAt != (time.Time{}) {
 		cr.Status.AtProvider.UpdateTime = &metav1.Time{Time: time.Now()}
@@ -242,7 +358,7 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	return managed.ExternalUpdate{
 		ConnectionDetails: managed.ConnectionDetails{
 			"project_name": []byte(status.Name),
-			"project_id":   []byte("1"), // Mock ID
+			"project_id":   []byte(status.ID),
 		},
 	}, nil
 }
@@ -259,8 +375,9 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	cr.SetConditions(xpv1.Deleting())
 
-	// Delete project from Harbor
-	err := c.service.DeleteProject(ctx, cr.Spec.ForProvider.Name)
+	// Delete the project Observe found, which differs from the one the spec
+	// names after an import or a rename.
+	err := c.service.DeleteProject(ctx, projectIdentity(cr))
 	if err != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, errProjectDelete)
 	}
@@ -273,6 +390,51 @@ func (c *external) Disconnect(ctx context.Context) error {
 	return nil
 }
 
+// updateMetadata applies the metadata diff for a project, setting changed
+// keys and removing unlisted ones under the Replace policy.
+func (c *external) updateMetadata(ctx context.Context, projectName string, p v1beta1.ProjectParameters) error {
+	observed, err := c.service.ListProjectMetadata(ctx, projectName)
+	if err != nil {
+		return err
+	}
+
+	set, remove := metadataDiff(desiredMetadata(p), observed, replaceMetadata(p))
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := c.service.SetProjectMetadata(ctx, projectName, k, set[k]); err != nil {
+			return err
+		}
+	}
+	for _, k := range remove {
+		if err := c.service.DeleteProjectMetadata(ctx, projectName, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectSpec is the Harbor project described by p.
+func projectSpec(p v1beta1.ProjectParameters) *harborclients.ProjectSpec {
+	return &harborclients.ProjectSpec{
+		Name:                     harborProjectName(p),
+		Public:                   getBoolValue(p.Public),
+		EnableContentTrust:       p.EnableContentTrust,
+		EnableContentTrustCosign: p.EnableContentTrustCosign,
+		AutoScanImages:           p.AutoScanImages,
+		PreventVulnerableImages:  p.PreventVulnerableImages,
+		Severity:                 p.Severity,
+		CVEAllowlist:             p.CVEAllowlist,
+		RegistryID:               p.RegistryID,
+		StorageLimit:             p.StorageLimit,
+		Metadata:                 p.Metadata,
+	}
+}
+
 // Helper functions
 func getBoolValue(b *bool) bool {
 	if b == nil {
@@ -8,10 +8,13 @@ import (
 	"context"
 	"errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/go-openapi/runtime"
 	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
 	harborclients "github.com/rossigee/provider-harbor/internal/clients"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 	"testing"
 	"time"
 )
@@ -82,7 +85,7 @@ func TestObserveProjectNotFound(t *testing.T) {
 	ext := &external{
 		service: &mockProjectClient{
 			getProjectFunc: func(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error) {
-				return nil, errors.New("not found")
+				return nil, runtime.NewAPIError("getProject", nil, http.StatusNotFound)
 			},
 		},
 	}
@@ -96,6 +99,30 @@ func TestObserveProjectNotFound(t *testing.T) {
 	}
 }
 
+func TestObserveProjectError(t *testing.T) {
+	ctx := context.Background()
+	project := &v1beta1.Project{
+		Spec: v1beta1.ProjectSpec{
+			ForProvider: v1beta1.ProjectParameters{
+				Name: "my-project",
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockProjectClient{
+			getProjectFunc: func(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error) {
+				return nil, errors.New("connection refused")
+			},
+		},
+	}
+
+	// A project that cannot be read must not be created again.
+	if _, err := ext.Observe(ctx, project); err == nil {
+		t.Error("Observe should fail when the project cannot be read")
+	}
+}
+
 func TestObserveProjectExists(t *testing.T) {
 	ctx := context.Background()
 	project := &v1beta1.Project{
@@ -179,6 +206,94 @@ func TestObserveProjectNotUpToDate(t *testing.T) {
 	}
 }
 
+func TestObserveProjectImportedByExternalName(t *testing.T) {
+	ctx := context.Background()
+	project := &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-project",
+			Annotations: map[string]string{"crossplane.io/external-name": "legacy-apps"},
+		},
+		Spec: v1beta1.ProjectSpec{
+			ForProvider: v1beta1.ProjectParameters{
+				Name:   "legacy-apps",
+				Public: ptrBool(false),
+			},
+		},
+	}
+
+	var got string
+	ext := &external{
+		service: &mockProjectClient{
+			getProjectFunc: func(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error) {
+				got = projectName
+				return &harborclients.ProjectStatus{Name: projectName, ID: "7"}, nil
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, project)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if got != "legacy-apps" {
+		t.Errorf("Observe looked up %q, want the external name", got)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Errorf("Observe() = %+v, want an existing, up to date project", obs)
+	}
+}
+
+func TestObserveProjectRenamed(t *testing.T) {
+	ctx := context.Background()
+	project := &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-project",
+			Annotations: map[string]string{"crossplane.io/external-name": "my-project"},
+		},
+		Spec: v1beta1.ProjectSpec{
+			ForProvider: v1beta1.ProjectParameters{
+				Name:   "renamed-project",
+				Public: ptrBool(false),
+			},
+		},
+	}
+
+	created := false
+	ext := &external{
+		service: &mockProjectClient{
+			getProjectFunc: func(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error) {
+				if projectName != "my-project" {
+					return nil, runtime.NewAPIError("getProject", nil, http.StatusNotFound)
+				}
+				return &harborclients.ProjectStatus{Name: projectName}, nil
+			},
+			createProjectFunc: func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+				created = true
+				return &harborclients.ProjectStatus{Name: spec.Name}, nil
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, project)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if !obs.ResourceExists {
+		t.Error("a renamed project must be observed as existing, not created again")
+	}
+	if obs.ResourceUpToDate {
+		t.Error("a renamed project must be reported as drift")
+	}
+
+	_, err = ext.Update(ctx, project)
+	if err == nil || !strings.Contains(err.Error(), `"my-project" to "renamed-project"`) {
+		t.Errorf("Update() error = %v, want a rename error", err)
+	}
+	if created {
+		t.Error("a second project was created")
+	}
+}
+
 func TestCreateProjectSuccess(t *testing.T) {
 	ctx := context.Background()
 	project := &v1beta1.Project{
@@ -193,6 +308,7 @@ func TestCreateProjectSuccess(t *testing.T) {
 	}
 
 	ext := &external{
+		kube: newKube(t),
 		service: &mockProjectClient{
 			createProjectFunc: func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
 				return &harborclients.ProjectStatus{
@@ -224,6 +340,7 @@ func TestCreateProjectError(t *testing.T) {
 	}
 
 	ext := &external{
+		kube: newKube(t),
 		service: &mockProjectClient{
 			createProjectFunc: func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
 				return nil, errors.New("create failed")
@@ -296,6 +413,38 @@ func TestDeleteProjectSuccess(t *testing.T) {
 	}
 }
 
+func TestDeleteProjectByExternalName(t *testing.T) {
+	ctx := context.Background()
+	project := &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-project",
+			Annotations: map[string]string{"crossplane.io/external-name": "my-project"},
+		},
+		Spec: v1beta1.ProjectSpec{
+			ForProvider: v1beta1.ProjectParameters{
+				Name: "renamed-project",
+			},
+		},
+	}
+
+	var deleted []string
+	ext := &external{
+		service: &mockProjectClient{
+			deleteProjectFunc: func(ctx context.Context, projectID string) error {
+				deleted = append(deleted, projectID)
+				return nil
+			},
+		},
+	}
+
+	if _, err := ext.Delete(ctx, project); err != nil {
+		t.Fatalf("Delete should not fail, got %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "my-project" {
+		t.Errorf("deleted %v, want the imported project [my-project]", deleted)
+	}
+}
+
 func TestDeleteProjectError(t *testing.T) {
 	ctx := context.Background()
 	project := &v1beta1.Project{
@@ -660,6 +809,7 @@ func TestCreateProjectWithEmptyName(t *testing.T) {
 	}
 
 	ext := &external{
+		kube: newKube(t),
 		service: &mockProjectClient{
 			createProjectFunc: func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
 				if spec.Name == "" {
@@ -707,6 +857,7 @@ func TestCreateProjectWithAllParameters(t *testing.T) {
 	}
 
 	ext := &external{
+		kube: newKube(t),
 		service: &mockProjectClient{
 			createProjectFunc: func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
 				if spec.Name != "secure-project" || spec.Metadata == nil {
@@ -852,6 +1003,7 @@ func TestCreateProjectWithMetadata(t *testing.T) {
 	}
 
 	ext := &external{
+		kube: newKube(t),
 		service: &mockProjectClient{
 			createProjectFunc: func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
 				if len(spec.Metadata) != 2 {
@@ -871,6 +1023,125 @@ func TestCreateProjectWithMetadata(t *testing.T) {
 	}
 }
 
+func TestObserveProjectMetadataDrift(t *testing.T) {
+	ctx := context.Background()
+	project := &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-project",
+		},
+		Spec: v1beta1.ProjectSpec{
+			ForProvider: v1beta1.ProjectParameters{
+				Name:     "test-project",
+				Metadata: map[string]string{"auto_sbom_generation": "true"},
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockProjectClient{
+			getProjectFunc: func(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error) {
+				return &harborclients.ProjectStatus{Name: projectName}, nil
+			},
+			listProjectMetadataFunc: func(ctx context.Context, projectID string) (map[string]string, error) {
+				return map[string]string{"public": "false", "auto_sbom_generation": "false"}, nil
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, project)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false when managed metadata differs")
+	}
+	if project.Status.AtProvider.Metadata["public"] != "false" {
+		t.Errorf("observed metadata not recorded: %v", project.Status.AtProvider.Metadata)
+	}
+}
+
+func TestObserveProjectMetadataIgnoresUnmanagedKeys(t *testing.T) {
+	ctx := context.Background()
+	project := &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-project",
+		},
+		Spec: v1beta1.ProjectSpec{
+			ForProvider: v1beta1.ProjectParameters{
+				Name:     "test-project",
+				Metadata: map[string]string{"auto_sbom_generation": "true"},
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockProjectClient{
+			getProjectFunc: func(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error) {
+				return &harborclients.ProjectStatus{Name: projectName}, nil
+			},
+			listProjectMetadataFunc: func(ctx context.Context, projectID string) (map[string]string, error) {
+				return map[string]string{"auto_sbom_generation": "true", "proxy_speed_kb": "-1"}, nil
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, project)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be true when only unmanaged keys differ under Merge")
+	}
+}
+
+func TestUpdateProjectReplacesMetadata(t *testing.T) {
+	ctx := context.Background()
+	replace := v1beta1.MetadataPolicyReplace
+	project := &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-project",
+		},
+		Spec: v1beta1.ProjectSpec{
+			ForProvider: v1beta1.ProjectParameters{
+				Name:           "test-project",
+				Metadata:       map[string]string{"auto_sbom_generation": "true"},
+				MetadataPolicy: &replace,
+			},
+		},
+	}
+
+	set := map[string]string{}
+	var removed []string
+	ext := &external{
+		service: &mockProjectClient{
+			updateProjectFunc: func(ctx context.Context, projectID string, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+				return &harborclients.ProjectStatus{Name: spec.Name}, nil
+			},
+			listProjectMetadataFunc: func(ctx context.Context, projectID string) (map[string]string, error) {
+				return map[string]string{"public": "false", "retention_id": "3", "proxy_speed_kb": "-1"}, nil
+			},
+			setProjectMetadataFunc: func(ctx context.Context, projectID, key, value string) error {
+				set[key] = value
+				return nil
+			},
+			deleteProjectMetadataFunc: func(ctx context.Context, projectID, key string) error {
+				removed = append(removed, key)
+				return nil
+			},
+		},
+	}
+
+	if _, err := ext.Update(ctx, project); err != nil {
+		t.Fatalf("Update should not fail, got %v", err)
+	}
+	if set["auto_sbom_generation"] != "true" || len(set) != 1 {
+		t.Errorf("set metadata = %v, want only auto_sbom_generation=true", set)
+	}
+	if len(removed) != 1 || removed[0] != "proxy_speed_kb" {
+		t.Errorf("removed metadata = %v, want [proxy_speed_kb]", removed)
+	}
+}
+
 func TestObserveProjectWithStorageInfo(t *testing.T) {
 	ctx := context.Background()
 	project := &v1beta1.Project{
@@ -892,8 +1163,6 @@ func TestObserveProjectWithStorageInfo(t *testing.T) {
 					Public:              false,
 					OwnerID:             1,
 					OwnerName:           "admin",
-					RepoCount:           5,
-					ChartCount:          3,
 					CurrentStorageUsage: 1073741824,
 					CreatedAt:           time.Now(),
 					UpdatedAt:           time.Now(),
@@ -972,13 +1241,15 @@ func TestObserveProjectPopulatesAllStatusFields(t *testing.T) {
 					Public:              false,
 					OwnerID:             1,
 					OwnerName:           "admin",
-					RepoCount:           10,
-					ChartCount:          5,
 					CurrentStorageUsage: 5368709120,
 					CreatedAt:           now.Add(-72 * time.Hour),
 					UpdatedAt:           now.Add(-1 * time.Hour),
 				}, nil
 			},
+			getProjectSummaryFunc: func(ctx context.Context, projectName string) (*harborclients.ProjectSummary, error) {
+				charts := int64(5)
+				return &harborclients.ProjectSummary{RepoCount: 10, ChartCount: &charts}, nil
+			},
 		},
 	}
 
@@ -1023,6 +1294,7 @@ func TestCreateProjectPublicFlag(t *testing.T) {
 	}
 
 	ext := &external{
+		kube: newKube(t),
 		service: &mockProjectClient{
 			createProjectFunc: func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
 				if !spec.Public {
@@ -1172,6 +1444,7 @@ func TestCreateProjectConnectionDetails(t *testing.T) {
 	}
 
 	ext := &external{
+		kube: newKube(t),
 		service: &mockProjectClient{
 			createProjectFunc: func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
 				return &harborclients.ProjectStatus{
@@ -1203,6 +1476,54 @@ type mockProjectClient struct {
 	createProjectFunc func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error)
 	updateProjectFunc func(ctx context.Context, projectID string, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error)
 	deleteProjectFunc func(ctx context.Context, projectID string) error
+
+	listProjectMetadataFunc   func(ctx context.Context, projectID string) (map[string]string, error)
+	setProjectMetadataFunc    func(ctx context.Context, projectID, key, value string) error
+	deleteProjectMetadataFunc func(ctx context.Context, projectID, key string) error
+
+	getSystemInfoFunc      func(ctx context.Context) (*harborclients.SystemInfo, error)
+	getConfigurationsFunc  func(ctx context.Context) (harborclients.Configurations, error)
+	sampleProjectSBOMsFunc func(ctx context.Context, projectName string, maxRepos int64) (*harborclients.SBOMSample, error)
+	getProjectSummaryFunc  func(ctx context.Context, projectName string) (*harborclients.ProjectSummary, error)
+
+	ensureProjectLabelFunc   func(ctx context.Context, projectID int64, name, description string) (int64, error)
+	deleteLabelFunc          func(ctx context.Context, labelID int64) error
+	listArtifactsByLabelFunc func(ctx context.Context, projectName, repositoryName string, labelID int64) ([]string, []string, error)
+	setArtifactLabelFunc     func(ctx context.Context, projectName, repositoryName, reference string, labelID int64, attached bool) error
+
+	getProjectMemberFunc    func(ctx context.Context, projectID, username string) (*harborclients.MemberStatus, error)
+	addProjectMemberFunc    func(ctx context.Context, projectID, username, role string) error
+	updateProjectMemberFunc func(ctx context.Context, projectID, username, role string) error
+
+	addProjectGroupMemberFunc func(ctx context.Context, projectName, groupName string, groupType int64, role string) error
+}
+
+func (m *mockProjectClient) GetProjectMember(ctx context.Context, projectID, username string) (*harborclients.MemberStatus, error) {
+	if m.getProjectMemberFunc != nil {
+		return m.getProjectMemberFunc(ctx, projectID, username)
+	}
+	return nil, nil
+}
+
+func (m *mockProjectClient) AddProjectMember(ctx context.Context, projectID, username, role string) error {
+	if m.addProjectMemberFunc != nil {
+		return m.addProjectMemberFunc(ctx, projectID, username, role)
+	}
+	return nil
+}
+
+func (m *mockProjectClient) UpdateProjectMember(ctx context.Context, projectID, username, role string) error {
+	if m.updateProjectMemberFunc != nil {
+		return m.updateProjectMemberFunc(ctx, projectID, username, role)
+	}
+	return nil
+}
+
+func (m *mockProjectClient) AddProjectGroupMember(ctx context.Context, projectName, groupName string, groupType int64, role string) error {
+	if m.addProjectGroupMemberFunc != nil {
+		return m.addProjectGroupMemberFunc(ctx, projectName, groupName, groupType, role)
+	}
+	return nil
 }
 
 func (m *mockProjectClient) GetProject(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error) {
@@ -1233,6 +1554,83 @@ func (m *mockProjectClient) DeleteProject(ctx context.Context, projectID string)
 	return nil
 }
 
+func (m *mockProjectClient) ListProjectMetadata(ctx context.Context, projectID string) (map[string]string, error) {
+	if m.listProjectMetadataFunc != nil {
+		return m.listProjectMetadataFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *mockProjectClient) SetProjectMetadata(ctx context.Context, projectID, key, value string) error {
+	if m.setProjectMetadataFunc != nil {
+		return m.setProjectMetadataFunc(ctx, projectID, key, value)
+	}
+	return nil
+}
+
+func (m *mockProjectClient) DeleteProjectMetadata(ctx context.Context, projectID, key string) error {
+	if m.deleteProjectMetadataFunc != nil {
+		return m.deleteProjectMetadataFunc(ctx, projectID, key)
+	}
+	return nil
+}
+
+func (m *mockProjectClient) GetSystemInfo(ctx context.Context) (*harborclients.SystemInfo, error) {
+	if m.getSystemInfoFunc != nil {
+		return m.getSystemInfoFunc(ctx)
+	}
+	return &harborclients.SystemInfo{HarborVersion: "v2.11.0"}, nil
+}
+
+func (m *mockProjectClient) GetConfigurations(ctx context.Context) (harborclients.Configurations, error) {
+	if m.getConfigurationsFunc != nil {
+		return m.getConfigurationsFunc(ctx)
+	}
+	return harborclients.Configurations{}, nil
+}
+
+func (m *mockProjectClient) SampleProjectSBOMs(ctx context.Context, projectName string, maxRepos int64) (*harborclients.SBOMSample, error) {
+	if m.sampleProjectSBOMsFunc != nil {
+		return m.sampleProjectSBOMsFunc(ctx, projectName, maxRepos)
+	}
+	return &harborclients.SBOMSample{}, nil
+}
+
+func (m *mockProjectClient) GetProjectSummary(ctx context.Context, projectName string) (*harborclients.ProjectSummary, error) {
+	if m.getProjectSummaryFunc != nil {
+		return m.getProjectSummaryFunc(ctx, projectName)
+	}
+	return &harborclients.ProjectSummary{}, nil
+}
+
+func (m *mockProjectClient) EnsureProjectLabel(ctx context.Context, projectID int64, name, description string) (int64, error) {
+	if m.ensureProjectLabelFunc != nil {
+		return m.ensureProjectLabelFunc(ctx, projectID, name, description)
+	}
+	return 1, nil
+}
+
+func (m *mockProjectClient) DeleteLabel(ctx context.Context, labelID int64) error {
+	if m.deleteLabelFunc != nil {
+		return m.deleteLabelFunc(ctx, labelID)
+	}
+	return nil
+}
+
+func (m *mockProjectClient) ListArtifactsByLabel(ctx context.Context, projectName, repositoryName string, labelID int64) ([]string, []string, error) {
+	if m.listArtifactsByLabelFunc != nil {
+		return m.listArtifactsByLabelFunc(ctx, projectName, repositoryName, labelID)
+	}
+	return nil, nil, nil
+}
+
+func (m *mockProjectClient) SetArtifactLabel(ctx context.Context, projectName, repositoryName, reference string, labelID int64, attached bool) error {
+	if m.setArtifactLabelFunc != nil {
+		return m.setArtifactLabelFunc(ctx, projectName, repositoryName, reference, labelID, attached)
+	}
+	return nil
+}
+
 func (m *mockProjectClient) Close() error {
 	return nil
 }
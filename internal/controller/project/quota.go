@@ -0,0 +1,64 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultQuotaThreshold is the percentage of a quota a Project may use
+// before it is reported as exceeding it.
+const DefaultQuotaThreshold = 90
+
+const reasonQuotaExceeded event.Reason = "QuotaExceeded"
+
+var quotaThreshold atomic.Int64
+
+func init() {
+	quotaThreshold.Store(DefaultQuotaThreshold)
+}
+
+// SetQuotaThreshold changes the percentage of a quota a Project may use
+// before the QuotaExceeded condition is set. Zero or less disables the
+// condition and its events.
+func SetQuotaThreshold(percent int) {
+	quotaThreshold.Store(int64(percent))
+}
+
+// observeQuota sets the QuotaExceeded condition from the quota limits and
+// usage in the project's summary, and emits a warning event when usage
+// crosses the threshold. Unlimited quotas, with a limit of -1, are ignored.
+func (c *external) observeQuota(cr *v1beta1.Project, hard, used map[string]int64) {
+	threshold := quotaThreshold.Load()
+	wasExceeded := cr.GetCondition(v1beta1.TypeQuotaExceeded).Status == corev1.ConditionTrue
+
+	resources := make([]string, 0, len(hard))
+	for r := range hard {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+
+	for _, r := range resources {
+		h, u := hard[r], used[r]
+		if threshold <= 0 || h <= 0 || u*100 < h*threshold {
+			continue
+		}
+		cond := v1beta1.QuotaThresholdReached(r, u, h, int(threshold))
+		cr.SetConditions(cond)
+		if !wasExceeded && c.recorder != nil {
+			c.recorder.Event(cr, event.Warning(reasonQuotaExceeded, errors.New(cond.Message)))
+		}
+		return
+	}
+	if wasExceeded {
+		cr.SetConditions(v1beta1.QuotaWithinThreshold())
+	}
+}
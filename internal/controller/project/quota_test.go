@@ -0,0 +1,100 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type recordedEvents []event.Event
+
+func (r *recordedEvents) Event(_ runtime.Object, e event.Event)    { *r = append(*r, e) }
+func (r *recordedEvents) WithAnnotations(...string) event.Recorder { return r }
+
+func TestObserveQuota(t *testing.T) {
+	const gib = int64(1 << 30)
+	cases := map[string]struct {
+		threshold  int
+		hard, used map[string]int64
+		exceeded   bool
+		want       corev1.ConditionStatus
+		wantEvents int
+	}{
+		"BelowThreshold": {
+			threshold: DefaultQuotaThreshold,
+			hard:      map[string]int64{"storage": 10 * gib},
+			used:      map[string]int64{"storage": 8 * gib},
+			want:      corev1.ConditionUnknown,
+		},
+		"CrossesThreshold": {
+			threshold:  DefaultQuotaThreshold,
+			hard:       map[string]int64{"storage": 10 * gib},
+			used:       map[string]int64{"storage": 9 * gib},
+			want:       corev1.ConditionTrue,
+			wantEvents: 1,
+		},
+		"StaysAboveThreshold": {
+			threshold: DefaultQuotaThreshold,
+			hard:      map[string]int64{"storage": 10 * gib},
+			used:      map[string]int64{"storage": 10 * gib},
+			exceeded:  true,
+			want:      corev1.ConditionTrue,
+		},
+		"DropsBelowThreshold": {
+			threshold: DefaultQuotaThreshold,
+			hard:      map[string]int64{"storage": 10 * gib},
+			used:      map[string]int64{"storage": gib},
+			exceeded:  true,
+			want:      corev1.ConditionFalse,
+		},
+		"Unlimited": {
+			threshold: DefaultQuotaThreshold,
+			hard:      map[string]int64{"storage": -1},
+			used:      map[string]int64{"storage": 100 * gib},
+			want:      corev1.ConditionUnknown,
+		},
+		"LowerThreshold": {
+			threshold:  50,
+			hard:       map[string]int64{"storage": 10 * gib},
+			used:       map[string]int64{"storage": 5 * gib},
+			want:       corev1.ConditionTrue,
+			wantEvents: 1,
+		},
+		"Disabled": {
+			threshold: 0,
+			hard:      map[string]int64{"storage": 10 * gib},
+			used:      map[string]int64{"storage": 10 * gib},
+			exceeded:  true,
+			want:      corev1.ConditionFalse,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			SetQuotaThreshold(tc.threshold)
+			t.Cleanup(func() { SetQuotaThreshold(DefaultQuotaThreshold) })
+
+			cr := &v1beta1.Project{}
+			if tc.exceeded {
+				cr.SetConditions(v1beta1.QuotaThresholdReached("storage", 10*gib, 10*gib, DefaultQuotaThreshold))
+			}
+			events := &recordedEvents{}
+			e := &external{recorder: events}
+
+			e.observeQuota(cr, tc.hard, tc.used)
+
+			if got := cr.GetCondition(v1beta1.TypeQuotaExceeded).Status; got != tc.want {
+				t.Errorf("QuotaExceeded = %s, want %s", got, tc.want)
+			}
+			if len(*events) != tc.wantEvents {
+				t.Errorf("events = %+v, want %d", *events, tc.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+)
+
+const keyProjectCreationRestriction = "project_creation_restriction"
+
+// creationForbidden sets the CreationPermitted condition after Harbor refused
+// to create cr, and returns the error Create should report. Harbor answers
+// 403 both when creation is restricted to admins and when the account lacks
+// permission for another reason, so the configured restriction is included.
+func (c *external) creationForbidden(ctx context.Context, cr *v1beta1.Project, err error) error {
+	restriction := c.projectCreationRestriction(ctx)
+	cond := v1beta1.CreationForbidden(restriction)
+	cr.SetConditions(cond)
+	return errors.Wrap(err, cond.Message)
+}
+
+// projectCreationRestriction returns who may create projects, or an empty
+// string if it cannot be read. Only admins may read configurations, so the
+// copy in system info, which any user may read, is the fallback.
+func (c *external) projectCreationRestriction(ctx context.Context) string {
+	if cfg, err := c.service.GetConfigurations(ctx); err == nil {
+		if r, ok := cfg[keyProjectCreationRestriction].(string); ok && r != "" {
+			return r
+		}
+	}
+	if info, err := c.service.GetSystemInfo(ctx); err == nil && info != nil {
+		return info.ProjectCreationRestriction
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type forbidden struct{}
+
+func (forbidden) Error() string        { return "forbidden" }
+func (forbidden) IsCode(code int) bool { return code == 403 }
+
+func TestCreateForbidden(t *testing.T) {
+	cases := map[string]struct {
+		config  func(context.Context) (harborclients.Configurations, error)
+		info    func(context.Context) (*harborclients.SystemInfo, error)
+		wantMsg string
+	}{
+		"FromConfigurations": {
+			config: func(context.Context) (harborclients.Configurations, error) {
+				return harborclients.Configurations{keyProjectCreationRestriction: "adminonly"}, nil
+			},
+			wantMsg: "provider credentials lack project creation permission; configured restriction=adminonly",
+		},
+		"FromSystemInfo": {
+			config: func(context.Context) (harborclients.Configurations, error) {
+				return nil, forbidden{}
+			},
+			info: func(context.Context) (*harborclients.SystemInfo, error) {
+				return &harborclients.SystemInfo{ProjectCreationRestriction: "everyone"}, nil
+			},
+			wantMsg: "configured restriction=everyone",
+		},
+		"Unknown": {
+			config: func(context.Context) (harborclients.Configurations, error) {
+				return nil, forbidden{}
+			},
+			info: func(context.Context) (*harborclients.SystemInfo, error) {
+				return nil, errors.New("unreachable")
+			},
+			wantMsg: "configured restriction=unknown",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ext := &external{
+				kube: newKube(t),
+				service: &mockProjectClient{
+					createProjectFunc: func(context.Context, *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+						return nil, forbidden{}
+					},
+					getConfigurationsFunc: tc.config,
+					getSystemInfoFunc:     tc.info,
+				},
+			}
+			cr := &v1beta1.Project{Spec: v1beta1.ProjectSpec{ForProvider: v1beta1.ProjectParameters{Name: "team-a"}}}
+
+			_, err := ext.Create(context.Background(), cr)
+			if err == nil || !strings.Contains(err.Error(), tc.wantMsg) {
+				t.Errorf("Create() error = %v, want it to contain %q", err, tc.wantMsg)
+			}
+			c := cr.GetCondition(v1beta1.TypeCreationPermitted)
+			if c.Status != corev1.ConditionFalse || c.Reason != v1beta1.ReasonCreationForbidden || !strings.Contains(c.Message, tc.wantMsg) {
+				t.Errorf("CreationPermitted condition = %+v", c)
+			}
+		})
+	}
+}
+
+func TestCreateClearsForbidden(t *testing.T) {
+	ext := &external{
+		kube: newKube(t),
+		service: &mockProjectClient{
+			createProjectFunc: func(_ context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+				return &harborclients.ProjectStatus{Name: spec.Name}, nil
+			},
+		},
+	}
+	cr := &v1beta1.Project{Spec: v1beta1.ProjectSpec{ForProvider: v1beta1.ProjectParameters{Name: "team-a"}}}
+	cr.SetConditions(v1beta1.CreationForbidden("adminonly"))
+
+	if _, err := ext.Create(context.Background(), cr); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if c := cr.GetCondition(v1beta1.TypeCreationPermitted); c.Status != corev1.ConditionTrue {
+		t.Errorf("CreationPermitted condition = %+v, want True once created", c)
+	}
+}
+
+func TestCreateWithoutCapability(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := apisv1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	pc := &apisv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Status: apisv1beta1.ProviderConfigStatus{Capabilities: &apisv1beta1.ProviderConfigCapabilities{
+			Account:                    "ci",
+			ProjectCreationRestriction: "adminonly",
+		}},
+	}
+	called := false
+	ext := &external{
+		kube: fake.NewClientBuilder().WithScheme(s).WithObjects(pc).Build(),
+		service: &mockProjectClient{
+			createProjectFunc: func(context.Context, *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+				called = true
+				return nil, forbidden{}
+			},
+		},
+	}
+	cr := &v1beta1.Project{Spec: v1beta1.ProjectSpec{ForProvider: v1beta1.ProjectParameters{Name: "team-a"}}}
+	cr.SetProviderConfigReference(&xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: "default"})
+
+	_, err := ext.Create(context.Background(), cr)
+	if err == nil || !strings.Contains(err.Error(), "configured restriction=adminonly") {
+		t.Errorf("Create() error = %v, want the restriction named", err)
+	}
+	if called {
+		t.Error("Create() called Harbor for an account that cannot create projects")
+	}
+	if c := cr.GetCondition(v1beta1.TypeCreationPermitted); c.Reason != v1beta1.ReasonCreationForbidden {
+		t.Errorf("CreationPermitted condition = %+v", c)
+	}
+}
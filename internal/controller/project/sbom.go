@@ -0,0 +1,75 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/capabilities"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	errSystemInfo = "cannot get Harbor system info"
+
+	// sbomSampleInterval is how often the latest artifacts are re-checked
+	// for SBOMs.
+	sbomSampleInterval = time.Hour
+	// sbomSampleRepos is how many recently updated repositories are sampled.
+	sbomSampleRepos = 10
+)
+
+// effectiveParameters drops settings the Harbor instance does not support
+// from p. It also reports whether automatic SBOM generation is supported.
+func (c *external) effectiveParameters(ctx context.Context, p v1beta1.ProjectParameters) (v1beta1.ProjectParameters, bool, error) {
+	if p.AutoSBOMGeneration == nil {
+		return p, true, nil
+	}
+	info, err := c.service.GetSystemInfo(ctx)
+	if err != nil {
+		return p, false, errors.Wrap(err, errSystemInfo)
+	}
+	if capabilities.Supports(info.HarborVersion, capabilities.AutoSBOMGeneration) {
+		return p, true, nil
+	}
+	p.AutoSBOMGeneration = nil
+	return p, false, nil
+}
+
+// observeSBOM records automatic SBOM generation in the project's status. It
+// must be called after the project metadata has been observed.
+func (c *external) observeSBOM(ctx context.Context, cr *v1beta1.Project, projectName string, supported bool) {
+	want := cr.Spec.ForProvider.AutoSBOMGeneration
+	if want == nil {
+		cr.Status.AtProvider.SBOM = nil
+		return
+	}
+
+	obs := &v1beta1.SBOMObservation{Supported: supported}
+	if prev := cr.Status.AtProvider.SBOM; prev != nil {
+		obs.SampledArtifacts = prev.SampledArtifacts
+		obs.ArtifactsWithSBOM = prev.ArtifactsWithSBOM
+		obs.SampledAt = prev.SampledAt
+	}
+	if v, err := strconv.ParseBool(cr.Status.AtProvider.Metadata["auto_sbom_generation"]); err == nil {
+		obs.AutoGeneration = &v
+	}
+
+	if supported && *want && (obs.SampledAt == nil || time.Since(obs.SampledAt.Time) >= sbomSampleInterval) {
+		// Sampling is best effort. On failure the previous sample is kept and
+		// the next poll tries again.
+		if s, err := c.service.SampleProjectSBOMs(ctx, projectName, sbomSampleRepos); err == nil {
+			obs.SampledArtifacts = s.Artifacts
+			obs.ArtifactsWithSBOM = s.WithSBOM
+			obs.SampledAt = &metav1.Time{Time: time.Now()}
+		}
+	}
+
+	cr.Status.AtProvider.SBOM = obs
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func sbomProject(enabled bool) *v1beta1.Project {
+	return &v1beta1.Project{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-project"},
+		Spec: v1beta1.ProjectSpec{
+			ForProvider: v1beta1.ProjectParameters{
+				Name:               "test-project",
+				AutoSBOMGeneration: ptrBool(enabled),
+			},
+		},
+	}
+}
+
+func TestObserveProjectAutoSBOM(t *testing.T) {
+	recent := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	cases := map[string]struct {
+		version      string
+		metadata     map[string]string
+		previous     *v1beta1.SBOMObservation
+		sampleErr    error
+		wantUpToDate bool
+		wantSampled  bool
+		want         v1beta1.SBOMObservation
+	}{
+		"Drifted": {
+			version:      "v2.10.0-b7d6ec6b",
+			metadata:     map[string]string{"auto_sbom_generation": "false"},
+			wantUpToDate: false,
+			wantSampled:  true,
+			want:         v1beta1.SBOMObservation{Supported: true, AutoGeneration: ptrBool(false), SampledArtifacts: 3, ArtifactsWithSBOM: 2},
+		},
+		"InSync": {
+			version:      "v2.11.0",
+			metadata:     map[string]string{"auto_sbom_generation": "true"},
+			wantUpToDate: true,
+			wantSampled:  true,
+			want:         v1beta1.SBOMObservation{Supported: true, AutoGeneration: ptrBool(true), SampledArtifacts: 3, ArtifactsWithSBOM: 2},
+		},
+		"RecentSampleKept": {
+			version:      "v2.11.0",
+			metadata:     map[string]string{"auto_sbom_generation": "true"},
+			previous:     &v1beta1.SBOMObservation{SampledArtifacts: 5, ArtifactsWithSBOM: 5, SampledAt: &recent},
+			wantUpToDate: true,
+			want:         v1beta1.SBOMObservation{Supported: true, AutoGeneration: ptrBool(true), SampledArtifacts: 5, ArtifactsWithSBOM: 5},
+		},
+		"SampleErrorIgnored": {
+			version:      "v2.11.0",
+			metadata:     map[string]string{"auto_sbom_generation": "true"},
+			sampleErr:    errors.New("boom"),
+			wantUpToDate: true,
+			wantSampled:  true,
+			want:         v1beta1.SBOMObservation{Supported: true, AutoGeneration: ptrBool(true)},
+		},
+		"UnsupportedHarbor": {
+			version:      "v2.9.1",
+			metadata:     map[string]string{"public": "false"},
+			wantUpToDate: true,
+			want:         v1beta1.SBOMObservation{Supported: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := sbomProject(true)
+			cr.Status.AtProvider.SBOM = tc.previous
+			sampled := false
+			ext := &external{
+				service: &mockProjectClient{
+					getProjectFunc: func(_ context.Context, projectName string) (*harborclients.ProjectStatus, error) {
+						return &harborclients.ProjectStatus{Name: projectName}, nil
+					},
+					listProjectMetadataFunc: func(context.Context, string) (map[string]string, error) {
+						return tc.metadata, nil
+					},
+					getSystemInfoFunc: func(context.Context) (*harborclients.SystemInfo, error) {
+						return &harborclients.SystemInfo{HarborVersion: tc.version}, nil
+					},
+					sampleProjectSBOMsFunc: func(_ context.Context, projectName string, _ int64) (*harborclients.SBOMSample, error) {
+						sampled = true
+						if tc.sampleErr != nil {
+							return nil, tc.sampleErr
+						}
+						return &harborclients.SBOMSample{Artifacts: 3, WithSBOM: 2}, nil
+					},
+				},
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if obs.ResourceUpToDate != tc.wantUpToDate {
+				t.Errorf("ResourceUpToDate = %v, want %v", obs.ResourceUpToDate, tc.wantUpToDate)
+			}
+			if sampled != tc.wantSampled {
+				t.Errorf("sampled = %v, want %v", sampled, tc.wantSampled)
+			}
+
+			got := cr.Status.AtProvider.SBOM
+			if got == nil {
+				t.Fatal("status.atProvider.sbom not set")
+			}
+			if got.Supported != tc.want.Supported ||
+				got.SampledArtifacts != tc.want.SampledArtifacts ||
+				got.ArtifactsWithSBOM != tc.want.ArtifactsWithSBOM ||
+				(got.AutoGeneration == nil) != (tc.want.AutoGeneration == nil) ||
+				(got.AutoGeneration != nil && *got.AutoGeneration != *tc.want.AutoGeneration) {
+				t.Errorf("sbom = %+v, want %+v", got, tc.want)
+			}
+			if tc.wantSampled && tc.sampleErr == nil && got.SampledAt == nil {
+				t.Error("SampledAt not set after sampling")
+			}
+		})
+	}
+}
+
+func TestUpdateProjectAutoSBOMUnsupported(t *testing.T) {
+	cr := sbomProject(true)
+	ext := &external{
+		service: &mockProjectClient{
+			updateProjectFunc: func(_ context.Context, _ string, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+				return &harborclients.ProjectStatus{Name: spec.Name}, nil
+			},
+			getSystemInfoFunc: func(context.Context) (*harborclients.SystemInfo, error) {
+				return &harborclients.SystemInfo{HarborVersion: "v2.8.4"}, nil
+			},
+			listProjectMetadataFunc: func(context.Context, string) (map[string]string, error) {
+				t.Error("metadata should not be reconciled when only autoSbomGeneration is set and unsupported")
+				return nil, nil
+			},
+			setProjectMetadataFunc: func(_ context.Context, _, key, _ string) error {
+				t.Errorf("SetProjectMetadata(%q) called on Harbor older than v2.10", key)
+				return nil
+			},
+		},
+	}
+
+	if _, err := ext.Update(context.Background(), cr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateProjectAutoSBOM(t *testing.T) {
+	cr := sbomProject(true)
+	set := map[string]string{}
+	ext := &external{
+		service: &mockProjectClient{
+			updateProjectFunc: func(_ context.Context, _ string, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error) {
+				return &harborclients.ProjectStatus{Name: spec.Name}, nil
+			},
+			listProjectMetadataFunc: func(context.Context, string) (map[string]string, error) {
+				return map[string]string{}, nil
+			},
+			setProjectMetadataFunc: func(_ context.Context, _, key, value string) error {
+				set[key] = value
+				return nil
+			},
+		},
+	}
+
+	if _, err := ext.Update(context.Background(), cr); err != nil {
+		t.Fatal(err)
+	}
+	if set["auto_sbom_generation"] != "true" {
+		t.Errorf("set metadata = %v, want auto_sbom_generation=true", set)
+	}
+}
+
+func TestObserveProjectSystemInfoError(t *testing.T) {
+	ext := &external{
+		service: &mockProjectClient{
+			getProjectFunc: func(_ context.Context, projectName string) (*harborclients.ProjectStatus, error) {
+				return &harborclients.ProjectStatus{Name: projectName}, nil
+			},
+			getSystemInfoFunc: func(context.Context) (*harborclients.SystemInfo, error) {
+				return nil, errors.New("unreachable")
+			},
+		},
+	}
+	if _, err := ext.Observe(context.Background(), sbomProject(false)); err == nil {
+		t.Error("Observe should fail when the Harbor version cannot be determined")
+	}
+}
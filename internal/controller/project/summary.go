@@ -0,0 +1,44 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+)
+
+// quotaStorage is the quota resource that counts bytes of storage.
+const quotaStorage = "storage"
+
+// observeSummary records the counts, quota and member breakdown in the
+// project's summary. The summary is informational, so when it cannot be read
+// the previous values are kept and the next poll tries again.
+func (c *external) observeSummary(ctx context.Context, cr *v1beta1.Project, projectName string) {
+	s, err := c.service.GetProjectSummary(ctx, projectName)
+	if err != nil {
+		return
+	}
+	obs := &cr.Status.AtProvider
+	obs.RepoCount = &s.RepoCount
+	obs.ChartCount = s.ChartCount
+
+	obs.Quota = nil
+	if s.QuotaHard != nil || s.QuotaUsed != nil {
+		obs.Quota = &v1beta1.QuotaObservation{Hard: s.QuotaHard, Used: s.QuotaUsed}
+	}
+	c.observeQuota(cr, s.QuotaHard, s.QuotaUsed)
+	if used, ok := s.QuotaUsed[quotaStorage]; ok {
+		obs.CurrentStorageUsage = &used
+	}
+
+	obs.Members = &v1beta1.MemberCountsObservation{
+		ProjectAdmin: s.ProjectAdminCount,
+		Maintainer:   s.MaintainerCount,
+		Developer:    s.DeveloperCount,
+		Guest:        s.GuestCount,
+		LimitedGuest: s.LimitedGuestCount,
+	}
+}
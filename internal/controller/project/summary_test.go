@@ -0,0 +1,83 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package project
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+func TestObserveSummary(t *testing.T) {
+	previous := int64(4)
+	cases := map[string]struct {
+		summary     *harborclients.ProjectSummary
+		err         error
+		wantRepos   *int64
+		wantStorage *int64
+		wantQuota   *v1beta1.QuotaObservation
+		wantMembers *v1beta1.MemberCountsObservation
+	}{
+		"QuotaAndMembers": {
+			summary: &harborclients.ProjectSummary{
+				RepoCount:         7,
+				QuotaHard:         map[string]int64{"storage": -1},
+				QuotaUsed:         map[string]int64{"storage": 2048},
+				ProjectAdminCount: 1,
+				DeveloperCount:    6,
+				GuestCount:        2,
+			},
+			wantRepos:   ptrInt64(7),
+			wantStorage: ptrInt64(2048),
+			wantQuota:   &v1beta1.QuotaObservation{Hard: map[string]int64{"storage": -1}, Used: map[string]int64{"storage": 2048}},
+			wantMembers: &v1beta1.MemberCountsObservation{ProjectAdmin: 1, Developer: 6, Guest: 2},
+		},
+		"NoQuotaVisible": {
+			summary:     &harborclients.ProjectSummary{RepoCount: 1, MaintainerCount: 1},
+			wantRepos:   ptrInt64(1),
+			wantStorage: &previous,
+			wantMembers: &v1beta1.MemberCountsObservation{Maintainer: 1},
+		},
+		"ErrorKeepsPrevious": {
+			err:         errors.New("boom"),
+			wantRepos:   &previous,
+			wantStorage: &previous,
+			wantQuota:   &v1beta1.QuotaObservation{Used: map[string]int64{"storage": 4}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Project{}
+			cr.Status.AtProvider.RepoCount = &previous
+			cr.Status.AtProvider.CurrentStorageUsage = &previous
+			cr.Status.AtProvider.Quota = &v1beta1.QuotaObservation{Used: map[string]int64{"storage": 4}}
+			e := &external{service: &mockProjectClient{
+				getProjectSummaryFunc: func(context.Context, string) (*harborclients.ProjectSummary, error) {
+					return tc.summary, tc.err
+				},
+			}}
+
+			e.observeSummary(context.Background(), cr, "team-a")
+
+			obs := cr.Status.AtProvider
+			if !reflect.DeepEqual(obs.RepoCount, tc.wantRepos) {
+				t.Errorf("RepoCount = %v, want %v", obs.RepoCount, tc.wantRepos)
+			}
+			if !reflect.DeepEqual(obs.CurrentStorageUsage, tc.wantStorage) {
+				t.Errorf("CurrentStorageUsage = %v, want %v", obs.CurrentStorageUsage, tc.wantStorage)
+			}
+			if !reflect.DeepEqual(obs.Quota, tc.wantQuota) {
+				t.Errorf("Quota = %+v, want %+v", obs.Quota, tc.wantQuota)
+			}
+			if !reflect.DeepEqual(obs.Members, tc.wantMembers) {
+				t.Errorf("Members = %+v, want %+v", obs.Members, tc.wantMembers)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package projectauditlog reports the recent audit log entries of Harbor
+// projects.
+package projectauditlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotProjectAuditLog = "managed resource is not a ProjectAuditLog custom resource"
+	errNewClient          = "cannot create new Service"
+	errListAuditLogs      = "cannot list Harbor project audit logs"
+)
+
+// Defaults for ProjectAuditLogs that leave the window or page size unset.
+const (
+	defaultWindow   = 24 * time.Hour
+	defaultPageSize = int64(20)
+)
+
+// Setup adds a controller that reconciles ProjectAuditLog managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.ProjectAuditLogGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube: mgr.GetClient(),
+		}))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(5 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ProjectAuditLogGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ProjectAuditLog{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube client.Client
+}
+
+// Connect produces an ExternalClient by creating a Harbor client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ProjectAuditLog); !ok {
+		return nil, errors.New(errNotProjectAuditLog)
+	}
+
+	harborClient, err := clients.NewHarborClientFromProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{service: harborClient, now: time.Now}, nil
+}
+
+// external reads a project's audit log into a ProjectAuditLog's status. The
+// audit log always exists and is never changed, so nothing is ever created,
+// updated or deleted.
+type external struct {
+	service clients.HarborClienter
+	now     func() time.Time
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "projectauditlog.observe",
+		tracing.SpanAttrs("ProjectAuditLog", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ProjectAuditLog)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotProjectAuditLog)
+	}
+
+	// Nothing is kept in Harbor, so a deleted ProjectAuditLog is gone.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	p := cr.Spec.ForProvider
+	window, pageSize := defaultWindow, defaultPageSize
+	if p.Window != nil && p.Window.Duration > 0 {
+		window = p.Window.Duration
+	}
+	if p.PageSize != nil && *p.PageSize > 0 {
+		pageSize = *p.PageSize
+	}
+
+	logs, err := c.service.ListProjectAuditLogs(ctx, p.ProjectName, c.now().Add(-window), pageSize)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListAuditLogs)
+	}
+	cr.Status.AtProvider = observation(logs)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+// observation converts audit log entries, newest first, to the status of a
+// ProjectAuditLog.
+func observation(logs []*clients.AuditLogEntry) v1beta1.ProjectAuditLogObservation {
+	o := v1beta1.ProjectAuditLogObservation{}
+	if len(logs) == 0 {
+		return o
+	}
+	o.Entries = make([]v1beta1.AuditLogEntry, 0, len(logs))
+	for _, l := range logs {
+		o.Entries = append(o.Entries, v1beta1.AuditLogEntry{
+			Operation:    l.Operation,
+			Resource:     l.Resource,
+			ResourceType: l.ResourceType,
+			Username:     l.Username,
+			Time:         metav1.NewTime(l.Time),
+		})
+		if o.LatestEntryTime == nil || l.Time.After(o.LatestEntryTime.Time) {
+			t := metav1.NewTime(l.Time)
+			o.LatestEntryTime = &t
+		}
+	}
+	return o
+}
+
+// Create is never called, since Observe always reports the audit log as
+// existing.
+func (c *external) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, nil
+}
+
+// Update is never called, since Observe always reports the audit log as up
+// to date.
+func (c *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(_ context.Context, _ resource.Managed) (managed.ExternalDelete, error) {
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package projectauditlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObserve(t *testing.T) {
+	now := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	logs := []*harborclients.AuditLogEntry{
+		{Operation: "delete", Resource: "team-a/web:1.0", ResourceType: "artifact", Username: "alice", Time: now.Add(-2 * time.Hour)},
+		{Operation: "create", Resource: "team-a/web:1.0", ResourceType: "artifact", Username: "ci", Time: now.Add(-3 * time.Hour)},
+	}
+
+	cases := map[string]struct {
+		params       v1beta1.ProjectAuditLogParameters
+		deleted      bool
+		listErr      error
+		wantErr      bool
+		exists       bool
+		wantSince    time.Time
+		wantPageSize int64
+		wantEntries  int
+	}{
+		"Defaults": {
+			params:       v1beta1.ProjectAuditLogParameters{ProjectName: "team-a"},
+			exists:       true,
+			wantSince:    now.Add(-defaultWindow),
+			wantPageSize: defaultPageSize,
+			wantEntries:  2,
+		},
+		"WindowAndPageSize": {
+			params: v1beta1.ProjectAuditLogParameters{
+				ProjectName: "team-a",
+				Window:      &metav1.Duration{Duration: time.Hour},
+				PageSize:    ptr(int64(50)),
+			},
+			exists:       true,
+			wantSince:    now.Add(-time.Hour),
+			wantPageSize: 50,
+			wantEntries:  2,
+		},
+		"Deleted": {
+			params:  v1beta1.ProjectAuditLogParameters{ProjectName: "team-a"},
+			deleted: true,
+		},
+		"ListError": {
+			params:  v1beta1.ProjectAuditLogParameters{ProjectName: "team-a"},
+			listErr: errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.ProjectAuditLog{Spec: v1beta1.ProjectAuditLogSpec{ForProvider: tc.params}}
+			if tc.deleted {
+				ts := metav1.NewTime(now)
+				cr.SetDeletionTimestamp(&ts)
+			}
+			var gotSince time.Time
+			var gotPageSize int64
+			ext := &external{
+				service: &harborclients.MockHarborClient{
+					ListProjectAuditLogsFunc: func(_ context.Context, project string, since time.Time, pageSize int64) ([]*harborclients.AuditLogEntry, error) {
+						if project != "team-a" {
+							t.Errorf("audit log of %q listed, want team-a", project)
+						}
+						gotSince, gotPageSize = since, pageSize
+						return logs, tc.listErr
+					},
+				},
+				now: func() time.Time { return now },
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if obs.ResourceExists != tc.exists {
+				t.Errorf("ResourceExists = %v, want %v", obs.ResourceExists, tc.exists)
+			}
+			if !tc.exists {
+				return
+			}
+			if !obs.ResourceUpToDate {
+				t.Error("a ProjectAuditLog should always be up to date")
+			}
+			if !gotSince.Equal(tc.wantSince) || gotPageSize != tc.wantPageSize {
+				t.Errorf("listed since %v with page size %d, want %v and %d", gotSince, gotPageSize, tc.wantSince, tc.wantPageSize)
+			}
+			got := cr.Status.AtProvider
+			if len(got.Entries) != tc.wantEntries || got.Entries[0].Username != "alice" {
+				t.Errorf("entries = %+v", got.Entries)
+			}
+			if got.LatestEntryTime == nil || !got.LatestEntryTime.Time.Equal(now.Add(-2*time.Hour)) {
+				t.Errorf("LatestEntryTime = %v", got.LatestEntryTime)
+			}
+			if cr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+				t.Error("ProjectAuditLog should be Ready once observed")
+			}
+		})
+	}
+}
+
+func TestObservationEmpty(t *testing.T) {
+	if o := observation(nil); o.Entries != nil || o.LatestEntryTime != nil {
+		t.Errorf("observation(nil) = %+v, want empty", o)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package projectscanner assigns scanners to Harbor projects.
+package projectscanner
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errNotProjectScanner = "managed resource is not a ProjectScanner custom resource"
+	errNewClient         = "cannot create new Service"
+	errGetRegistration   = "cannot get referenced ScannerRegistration"
+	errNoUUID            = "referenced ScannerRegistration %s has not been registered with Harbor yet"
+	errGetScanner        = "cannot get scanner of project"
+	errSetScanner        = "cannot set scanner of project"
+)
+
+// Setup adds a controller that reconciles ProjectScanner managed resources
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.ProjectScannerGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithProtection(mgr.GetClient(), projectScannerTarget, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:   mgr.GetClient(),
+			logger: log,
+		}))))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(10 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ProjectScannerGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ProjectScanner{}).
+		Watches(&v1beta1.ScannerRegistration{}, enqueueReferrers(mgr.GetClient())).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// enqueueReferrers enqueues the ProjectScanners that reference a
+// ScannerRegistration, so that they pick up its UUID once it is registered.
+func enqueueReferrers(kube client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		l := &v1beta1.ProjectScannerList{}
+		if err := kube.List(ctx, l, client.InNamespace(o.GetNamespace())); err != nil {
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, ps := range l.Items {
+			if ref := ps.Spec.ForProvider.ScannerRegistrationRef; ref != nil && ref.Name == o.GetName() {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ps.GetNamespace(), Name: ps.GetName()}})
+			}
+		}
+		return reqs
+	})
+}
+
+// projectScannerTarget returns the project a ProjectScanner configures.
+func projectScannerTarget(mg resource.Managed) ctrlutil.Target {
+	cr, ok := mg.(*v1beta1.ProjectScanner)
+	if !ok {
+		return ctrlutil.Target{}
+	}
+	return ctrlutil.Target{Project: cr.Spec.ForProvider.ProjectName}
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// Connect produces an ExternalClient by creating a Harbor client
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ProjectScanner); !ok {
+		return nil, errors.New(errNotProjectScanner)
+	}
+
+	harborClient, err := clients.NewHarborClientFromProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{kube: c.kube, service: harborClient, logger: c.logger}, nil
+}
+
+// external sets the scanner of a Harbor project. Every project has a
+// scanner, so there is nothing to create or delete.
+type external struct {
+	kube    client.Reader
+	service clients.HarborClienter
+	logger  logging.Logger
+}
+
+// scannerUUID returns the UUID of the scanner cr selects.
+func (c *external) scannerUUID(ctx context.Context, cr *v1beta1.ProjectScanner) (string, error) {
+	p := cr.Spec.ForProvider
+	if p.ScannerUUID != nil {
+		return *p.ScannerUUID, nil
+	}
+	if p.ScannerRegistrationRef == nil {
+		return "", errors.New("one of scannerUUID and scannerRegistrationRef is required")
+	}
+	sr := &v1beta1.ScannerRegistration{}
+	key := types.NamespacedName{Namespace: cr.GetNamespace(), Name: p.ScannerRegistrationRef.Name}
+	if err := c.kube.Get(ctx, key, sr); err != nil {
+		return "", errors.Wrap(err, errGetRegistration)
+	}
+	if sr.Status.AtProvider.UUID == nil || *sr.Status.AtProvider.UUID == "" {
+		return "", errors.Errorf(errNoUUID, key)
+	}
+	return *sr.Status.AtProvider.UUID, nil
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "projectscanner.observe",
+		tracing.SpanAttrs("ProjectScanner", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ProjectScanner)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotProjectScanner)
+	}
+
+	// Harbor cannot remove a project's scanner.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	want, err := c.scannerUUID(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	current, err := c.service.GetProjectScanner(ctx, cr.Spec.ForProvider.ProjectName)
+	if clients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetScanner)
+	}
+
+	cr.Status.AtProvider.ScannerUUID = &current.UUID
+	cr.Status.AtProvider.ScannerName = &current.Name
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: current.UUID == want,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, err := c.Update(ctx, mg)
+	return managed.ExternalCreation{}, err
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "projectscanner.update",
+		tracing.SpanAttrs("ProjectScanner", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ProjectScanner)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotProjectScanner)
+	}
+
+	uuid, err := c.scannerUUID(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if err := c.service.SetProjectScanner(ctx, cr.Spec.ForProvider.ProjectName, uuid); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errSetScanner)
+	}
+
+	c.logger.Info("Set project scanner", "project", cr.Spec.ForProvider.ProjectName, "uuid", uuid)
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(_ context.Context, _ resource.Managed) (managed.ExternalDelete, error) {
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
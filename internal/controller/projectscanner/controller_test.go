@@ -0,0 +1,141 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package projectscanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type projectNotFound struct{}
+
+func (projectNotFound) Error() string        { return "project not found" }
+func (projectNotFound) IsCode(code int) bool { return code == 404 }
+
+func ptr[T any](v T) *T { return &v }
+
+func newKube(t *testing.T, objs ...*v1beta1.ScannerRegistration) *fake.ClientBuilder {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	b := fake.NewClientBuilder().WithScheme(s)
+	for _, o := range objs {
+		b = b.WithObjects(o)
+	}
+	return b
+}
+
+func projectScanner(p v1beta1.ProjectScannerParameters) *v1beta1.ProjectScanner {
+	p.ProjectName = "team-a"
+	return &v1beta1.ProjectScanner{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "harbor"},
+		Spec:       v1beta1.ProjectScannerSpec{ForProvider: p},
+	}
+}
+
+func TestObserve(t *testing.T) {
+	registered := &v1beta1.ScannerRegistration{
+		ObjectMeta: metav1.ObjectMeta{Name: "trivy", Namespace: "harbor"},
+		Status: v1beta1.ScannerRegistrationStatus{
+			AtProvider: v1beta1.ScannerRegistrationObservation{UUID: ptr("uuid-trivy")},
+		},
+	}
+	pending := &v1beta1.ScannerRegistration{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "harbor"}}
+
+	cases := map[string]struct {
+		params   v1beta1.ProjectScannerParameters
+		current  *harborclients.ScannerStatus
+		getErr   error
+		wantErr  bool
+		exists   bool
+		upToDate bool
+	}{
+		"UUIDMatches": {
+			params:   v1beta1.ProjectScannerParameters{ScannerUUID: ptr("uuid-trivy")},
+			current:  &harborclients.ScannerStatus{UUID: "uuid-trivy", Name: "Trivy"},
+			exists:   true,
+			upToDate: true,
+		},
+		"Drifted": {
+			params:  v1beta1.ProjectScannerParameters{ScannerUUID: ptr("uuid-trivy")},
+			current: &harborclients.ScannerStatus{UUID: "uuid-clair", Name: "Clair"},
+			exists:  true,
+		},
+		"RegistrationRef": {
+			params:   v1beta1.ProjectScannerParameters{ScannerRegistrationRef: &v1beta1.ScannerRegistrationReference{Name: "trivy"}},
+			current:  &harborclients.ScannerStatus{UUID: "uuid-trivy", Name: "Trivy"},
+			exists:   true,
+			upToDate: true,
+		},
+		"RegistrationPending": {
+			params:  v1beta1.ProjectScannerParameters{ScannerRegistrationRef: &v1beta1.ScannerRegistrationReference{Name: "pending"}},
+			wantErr: true,
+		},
+		"RegistrationMissing": {
+			params:  v1beta1.ProjectScannerParameters{ScannerRegistrationRef: &v1beta1.ScannerRegistrationReference{Name: "missing"}},
+			wantErr: true,
+		},
+		"ProjectMissing": {
+			params: v1beta1.ProjectScannerParameters{ScannerUUID: ptr("uuid-trivy")},
+			getErr: projectNotFound{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ext := &external{
+				kube: newKube(t, registered, pending).Build(),
+				service: &harborclients.MockHarborClient{
+					GetProjectScannerFunc: func(context.Context, string) (*harborclients.ScannerStatus, error) {
+						return tc.current, tc.getErr
+					},
+				},
+				logger: logging.NewNopLogger(),
+			}
+			cr := projectScanner(tc.params)
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Observe() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if obs.ResourceExists != tc.exists || obs.ResourceUpToDate != tc.upToDate {
+				t.Errorf("Observe() = exists %v, upToDate %v, want %v, %v", obs.ResourceExists, obs.ResourceUpToDate, tc.exists, tc.upToDate)
+			}
+			if tc.current != nil && !tc.wantErr && *cr.Status.AtProvider.ScannerName != tc.current.Name {
+				t.Errorf("status scanner name = %q, want %q", *cr.Status.AtProvider.ScannerName, tc.current.Name)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var project, uuid string
+	ext := &external{
+		kube: newKube(t).Build(),
+		service: &harborclients.MockHarborClient{
+			SetProjectScannerFunc: func(_ context.Context, p, id string) error {
+				project, uuid = p, id
+				return nil
+			},
+		},
+		logger: logging.NewNopLogger(),
+	}
+
+	if _, err := ext.Update(context.Background(), projectScanner(v1beta1.ProjectScannerParameters{ScannerUUID: ptr("uuid-trivy")})); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if project != "team-a" || uuid != "uuid-trivy" {
+		t.Errorf("SetProjectScanner(%q, %q), want team-a, uuid-trivy", project, uuid)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package projectset
+
+import (
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	robotv1beta1 "github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// Labels identifying the children of a ProjectSet.
+const (
+	LabelProjectSet = "project.harbor.m.crossplane.io/project-set"
+	LabelProject    = "project.harbor.m.crossplane.io/project"
+)
+
+// projectName is the name of the Project managed resource created for p.
+func projectName(cr *v1beta1.ProjectSet, p v1beta1.ProjectSetProject) string {
+	return cr.GetName() + "-" + p.Name
+}
+
+// robotName is the name of the Robot managed resource created for r, and of
+// the Secret its secret is published to.
+func robotName(cr *v1beta1.ProjectSet, p v1beta1.ProjectSetProject, r v1beta1.ProjectSetRobot) string {
+	return projectName(cr, p) + "-" + r.Name
+}
+
+// adopt makes cr the controller of a child and points it at cr's
+// ProviderConfig. The child shares cr's management policies: children are
+// garbage collected with cr, so a set that is orphaned must orphan them too.
+func adopt(cr *v1beta1.ProjectSet, p v1beta1.ProjectSetProject, child resource.Managed, pc **xpv1.ProviderConfigReference) {
+	meta.AddLabels(child, map[string]string{LabelProjectSet: cr.GetName(), LabelProject: p.Name})
+	ctrlutil.SetOwner(child, cr, v1beta1.ProjectSetGroupVersionKind)
+	if mp := cr.GetManagementPolicies(); len(mp) > 0 {
+		child.SetManagementPolicies(append(xpv1.ManagementPolicies{}, mp...))
+	}
+	if cr.GetProviderConfigReference() != nil {
+		*pc = cr.GetProviderConfigReference().DeepCopy()
+	}
+}
+
+// mutateProject sets the fields of proj that the set owns, leaving those
+// defaulted by the API server alone. A project's own settings override the
+// set's.
+func mutateProject(cr *v1beta1.ProjectSet, p v1beta1.ProjectSetProject, proj *v1beta1.Project) {
+	adopt(cr, p, proj, &proj.Spec.ProviderConfigReference)
+
+	set := cr.Spec.ForProvider
+	fp := &proj.Spec.ForProvider
+	fp.Name = p.Name
+	if public := override(p.Public, set.Public); public != nil {
+		fp.Public = public
+	}
+	fp.StorageLimit = override(p.StorageLimit, set.StorageLimit)
+	fp.ProjectClassName = override(p.ProjectClassName, set.ProjectClassName)
+}
+
+// mutateRobot sets the fields of robot that the set owns. The robot refers
+// to its project's Project managed resource, which resolves to the
+// project's Harbor ID once it has been created.
+func mutateRobot(cr *v1beta1.ProjectSet, p v1beta1.ProjectSetProject, r v1beta1.ProjectSetRobot, robot *robotv1beta1.Robot) {
+	adopt(cr, p, robot, &robot.Spec.ProviderConfigReference)
+	robot.Spec.WriteConnectionSecretToReference = &xpv1.LocalSecretReference{Name: robotName(cr, p, r)}
+
+	fp := &robot.Spec.ForProvider
+	fp.Name = r.Name
+	fp.Description = override(r.Description, nil)
+	fp.ExpiresIn = override(r.ExpiresIn, nil)
+	fp.ProjectRef = &xpv1.NamespacedReference{Name: projectName(cr, p)}
+	fp.Permissions = []robotv1beta1.RobotPermission{{Namespace: p.Name, Access: append([]string{}, r.Access...)}}
+}
+
+// projectUpToDate reports whether proj already matches p.
+func projectUpToDate(cr *v1beta1.ProjectSet, p v1beta1.ProjectSetProject, proj *v1beta1.Project) bool {
+	want := proj.DeepCopy()
+	mutateProject(cr, p, want)
+	return equality.Semantic.DeepEqual(proj, want)
+}
+
+// robotUpToDate reports whether robot already matches r. The reference is
+// compared by name, as resolving it adds a policy.
+func robotUpToDate(cr *v1beta1.ProjectSet, p v1beta1.ProjectSetProject, r v1beta1.ProjectSetRobot, robot *robotv1beta1.Robot) bool {
+	want := robot.DeepCopy()
+	mutateRobot(cr, p, r, want)
+	if ref := robot.Spec.ForProvider.ProjectRef; ref != nil && ref.Name == want.Spec.ForProvider.ProjectRef.Name {
+		want.Spec.ForProvider.ProjectRef = ref.DeepCopy()
+	}
+	return equality.Semantic.DeepEqual(robot, want)
+}
+
+// ready reports whether a child managed resource is ready.
+func ready(c interface {
+	GetCondition(xpv1.ConditionType) xpv1.Condition
+}) bool {
+	return c.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+}
+
+// override returns a copy of v, or of def when v is nil.
+func override[T any](v, def *T) *T {
+	if v == nil {
+		v = def
+	}
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package projectset bootstraps the baseline projects of a Harbor instance
+// and their robot accounts.
+package projectset
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	robotv1beta1 "github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	errNotProjectSet = "managed resource is not a ProjectSet custom resource"
+	errGetProject    = "cannot get Project of project %s"
+	errGetRobot      = "cannot get Robot %s of project %s"
+	errApplyProject  = "cannot apply Project of project %s"
+	errApplyRobot    = "cannot apply Robot %s of project %s"
+	errListChildren  = "cannot list children of ProjectSet"
+	errDeleteChild   = "cannot delete %s %s"
+)
+
+// Setup adds a controller that reconciles ProjectSet managed resources
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.ProjectSetGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:   mgr.GetClient(),
+			logger: log,
+		})))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ProjectSetGroupVersionKind), opts...)
+
+	// The children are watched so that readiness is reported as it changes.
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ProjectSet{}).
+		Owns(&v1beta1.Project{}).
+		Owns(&robotv1beta1.Robot{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// Connect produces an ExternalClient. A ProjectSet only manages other
+// managed resources, so it needs no Harbor client of its own.
+func (c *connector) Connect(_ context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ProjectSet); !ok {
+		return nil, errors.New(errNotProjectSet)
+	}
+	return &external{kube: c.kube, logger: c.logger}, nil
+}
+
+// external manages the Project and Robots of each project of a ProjectSet.
+// Its external resources are those managed resources.
+type external struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// children are the managed resources created for a ProjectSet.
+type children struct {
+	projects []v1beta1.Project
+	robots   []robotv1beta1.Robot
+}
+
+func (c *external) listChildren(ctx context.Context, cr *v1beta1.ProjectSet) (*children, error) {
+	opts := []client.ListOption{client.InNamespace(cr.GetNamespace()), client.MatchingLabels{LabelProjectSet: cr.GetName()}}
+	pl := &v1beta1.ProjectList{}
+	if err := c.kube.List(ctx, pl, opts...); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	rl := &robotv1beta1.RobotList{}
+	if err := c.kube.List(ctx, rl, opts...); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	ch := &children{}
+	for _, p := range pl.Items {
+		if metav1.IsControlledBy(&p, cr) {
+			ch.projects = append(ch.projects, p)
+		}
+	}
+	for _, r := range rl.Items {
+		if metav1.IsControlledBy(&r, cr) {
+			ch.robots = append(ch.robots, r)
+		}
+	}
+	return ch, nil
+}
+
+// stale returns the children of projects and robots no longer in the spec.
+func (ch *children) stale(cr *v1beta1.ProjectSet) *children {
+	want := map[string]bool{}
+	for _, p := range cr.Spec.ForProvider.Projects {
+		want[projectName(cr, p)] = true
+		for _, r := range p.Robots {
+			want[robotName(cr, p, r)] = true
+		}
+	}
+	s := &children{}
+	for _, p := range ch.projects {
+		if !want[p.GetName()] {
+			s.projects = append(s.projects, p)
+		}
+	}
+	for _, r := range ch.robots {
+		if !want[r.GetName()] {
+			s.robots = append(s.robots, r)
+		}
+	}
+	return s
+}
+
+func (ch *children) empty() bool {
+	return len(ch.projects) == 0 && len(ch.robots) == 0
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "projectset.observe",
+		tracing.SpanAttrs("ProjectSet", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ProjectSet)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotProjectSet)
+	}
+
+	all, err := c.listChildren(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: !all.empty()}, nil
+	}
+
+	upToDate := all.stale(cr).empty()
+	obs := make([]v1beta1.ProjectSetProjectObservation, 0, len(cr.Spec.ForProvider.Projects))
+	readyCount := 0
+	for _, p := range cr.Spec.ForProvider.Projects {
+		o := v1beta1.ProjectSetProjectObservation{Name: p.Name}
+
+		proj := &v1beta1.Project{}
+		err := c.kube.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: projectName(cr, p)}, proj)
+		if resource.IgnoreNotFound(err) != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(err, errGetProject, p.Name)
+		}
+		if kerrors.IsNotFound(err) {
+			upToDate = false
+			obs = append(obs, o)
+			continue
+		}
+		o.Project = proj.GetName()
+		if !projectUpToDate(cr, p, proj) {
+			upToDate = false
+		}
+		o.Ready = ready(proj)
+
+		for _, r := range p.Robots {
+			robot := &robotv1beta1.Robot{}
+			err := c.kube.Get(ctx, types.NamespacedName{Namespace: cr.GetNamespace(), Name: robotName(cr, p, r)}, robot)
+			if resource.IgnoreNotFound(err) != nil {
+				return managed.ExternalObservation{}, errors.Wrapf(err, errGetRobot, r.Name, p.Name)
+			}
+			if kerrors.IsNotFound(err) {
+				upToDate = false
+				o.Ready = false
+				continue
+			}
+			o.Robots = append(o.Robots, robot.GetName())
+			if !robotUpToDate(cr, p, r, robot) {
+				upToDate = false
+			}
+			o.Ready = o.Ready && ready(robot)
+		}
+		if o.Ready {
+			readyCount++
+		}
+		obs = append(obs, o)
+	}
+
+	cr.Status.AtProvider.Projects = obs
+	cr.Status.AtProvider.ReadyProjects = fmt.Sprintf("%d/%d", readyCount, len(cr.Spec.ForProvider.Projects))
+	if readyCount == len(cr.Spec.ForProvider.Projects) {
+		cr.SetConditions(xpv1.Available())
+	} else {
+		cr.SetConditions(xpv1.Unavailable())
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   !all.empty(),
+		ResourceUpToDate: upToDate,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, err := c.Update(ctx, mg)
+	return managed.ExternalCreation{}, err
+}
+
+// Update creates or updates the Project and Robots of every project.
+// Children of projects and robots removed from the spec are deleted.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "projectset.update",
+		tracing.SpanAttrs("ProjectSet", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ProjectSet)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotProjectSet)
+	}
+
+	for _, p := range cr.Spec.ForProvider.Projects {
+		proj := &v1beta1.Project{}
+		proj.SetNamespace(cr.GetNamespace())
+		proj.SetName(projectName(cr, p))
+		if _, err := controllerutil.CreateOrUpdate(ctx, c.kube, proj, func() error {
+			mutateProject(cr, p, proj)
+			return nil
+		}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrapf(err, errApplyProject, p.Name)
+		}
+
+		for _, r := range p.Robots {
+			robot := &robotv1beta1.Robot{}
+			robot.SetNamespace(cr.GetNamespace())
+			robot.SetName(robotName(cr, p, r))
+			if _, err := controllerutil.CreateOrUpdate(ctx, c.kube, robot, func() error {
+				mutateRobot(cr, p, r, robot)
+				return nil
+			}); err != nil {
+				return managed.ExternalUpdate{}, errors.Wrapf(err, errApplyRobot, r.Name, p.Name)
+			}
+		}
+	}
+
+	all, err := c.listChildren(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	return managed.ExternalUpdate{}, c.deleteChildren(ctx, all.stale(cr))
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	_, span := tracing.StartSpan(ctx, "projectset.delete",
+		tracing.SpanAttrs("ProjectSet", tracing.ResourceName(mg), "delete")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ProjectSet)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotProjectSet)
+	}
+	cr.SetConditions(xpv1.Deleting())
+
+	all, err := c.listChildren(ctx, cr)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+	return managed.ExternalDelete{}, c.deleteChildren(ctx, all)
+}
+
+// deleteChildren deletes robots, and the projects no remaining robot
+// belongs to. Robots are deleted first so that each is removed from Harbor
+// while its project still exists.
+func (c *external) deleteChildren(ctx context.Context, ch *children) error {
+	inUse := map[string]bool{}
+	for i := range ch.robots {
+		r := &ch.robots[i]
+		inUse[r.GetLabels()[LabelProject]] = true
+		if err := c.kube.Delete(ctx, r); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errDeleteChild, "Robot", r.GetName())
+		}
+	}
+	for i := range ch.projects {
+		p := &ch.projects[i]
+		if inUse[p.GetLabels()[LabelProject]] {
+			continue
+		}
+		if err := c.kube.Delete(ctx, p); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errDeleteChild, "Project", p.GetName())
+		}
+	}
+	return nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return nil
+}
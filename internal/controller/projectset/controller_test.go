@@ -0,0 +1,258 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package projectset
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	robotv1beta1 "github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func newExternal(t *testing.T) *external {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := robotv1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return &external{kube: fake.NewClientBuilder().WithScheme(s).Build(), logger: logging.NewNopLogger()}
+}
+
+func projectSet(projects ...v1beta1.ProjectSetProject) *v1beta1.ProjectSet {
+	return &v1beta1.ProjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "baseline", Namespace: "harbor", UID: "set-uid"},
+		Spec: v1beta1.ProjectSetSpec{
+			ManagedResourceSpec: xpv1.ManagedResourceSpec{
+				ProviderConfigReference: &xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: "harbor"},
+			},
+			ForProvider: v1beta1.ProjectSetParameters{
+				StorageLimit: ptr(int64(100)),
+				Projects:     projects,
+			},
+		},
+	}
+}
+
+var (
+	library = v1beta1.ProjectSetProject{
+		Name:   "library",
+		Public: ptr(true),
+		Robots: []v1beta1.ProjectSetRobot{{Name: "ci", Access: []string{"pull", "push"}}},
+	}
+	team = v1beta1.ProjectSetProject{Name: "team", StorageLimit: ptr(int64(5))}
+)
+
+func markReady(t *testing.T, kube client.Client, o interface {
+	client.Object
+	SetConditions(...xpv1.Condition)
+}) {
+	t.Helper()
+	if err := kube.Get(context.Background(), client.ObjectKeyFromObject(o), o); err != nil {
+		t.Fatal(err)
+	}
+	o.SetConditions(xpv1.Available())
+	if err := kube.Update(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := projectSet(library, team)
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || obs.ResourceExists {
+		t.Fatalf("Observe() before create = %+v, %v; want not existing", obs, err)
+	}
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	p := &v1beta1.Project{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "baseline-library"}, p); err != nil {
+		t.Fatalf("project not created: %v", err)
+	}
+	if fp := p.Spec.ForProvider; fp.Name != "library" || !*fp.Public || *fp.StorageLimit != 100 {
+		t.Errorf("project forProvider = name %s, public %v, limit %d", fp.Name, *fp.Public, *fp.StorageLimit)
+	}
+	if p.Spec.ProviderConfigReference == nil || p.Spec.ProviderConfigReference.Name != "harbor" {
+		t.Errorf("project providerConfigRef = %v, want harbor", p.Spec.ProviderConfigReference)
+	}
+	if !metav1.IsControlledBy(p, cr) {
+		t.Error("project is not controlled by the ProjectSet")
+	}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "baseline-team"}, p); err != nil {
+		t.Fatalf("project not created: %v", err)
+	}
+	if fp := p.Spec.ForProvider; fp.Public != nil || *fp.StorageLimit != 5 {
+		t.Errorf("project forProvider = public %v, limit %d; want unset, 5", fp.Public, *fp.StorageLimit)
+	}
+
+	r := &robotv1beta1.Robot{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "baseline-library-ci"}, r); err != nil {
+		t.Fatalf("robot not created: %v", err)
+	}
+	wantPerms := []robotv1beta1.RobotPermission{{Namespace: "library", Access: []string{"pull", "push"}}}
+	if fp := r.Spec.ForProvider; fp.Name != "ci" || fp.ProjectRef == nil || fp.ProjectRef.Name != "baseline-library" || !reflect.DeepEqual(fp.Permissions, wantPerms) {
+		t.Errorf("robot forProvider = %+v", fp)
+	}
+	if ref := r.Spec.WriteConnectionSecretToReference; ref == nil || ref.Name != "baseline-library-ci" {
+		t.Errorf("robot writeConnectionSecretToRef = %v, want baseline-library-ci", ref)
+	}
+
+	obs, err = e.Observe(ctx, cr)
+	if err != nil || !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("Observe() after create = %+v, %v; want up to date", obs, err)
+	}
+	if got := cr.GetCondition(xpv1.TypeReady).Reason; got != xpv1.ReasonUnavailable {
+		t.Errorf("Ready reason = %s before children are ready, want %s", got, xpv1.ReasonUnavailable)
+	}
+
+	for _, n := range []string{"baseline-library", "baseline-team"} {
+		markReady(t, e.kube, &v1beta1.Project{ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: n}})
+	}
+	if _, err := e.Observe(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if got := cr.Status.AtProvider.ReadyProjects; got != "1/2" {
+		t.Errorf("readyProjects = %s before the robot is ready, want 1/2", got)
+	}
+
+	markReady(t, e.kube, &robotv1beta1.Robot{ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "baseline-library-ci"}})
+	if _, err := e.Observe(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue || cr.Status.AtProvider.ReadyProjects != "2/2" {
+		t.Errorf("status = %s, %s; want ready, 2/2", cr.GetCondition(xpv1.TypeReady).Status, cr.Status.AtProvider.ReadyProjects)
+	}
+	want := v1beta1.ProjectSetProjectObservation{Name: "library", Project: "baseline-library", Robots: []string{"baseline-library-ci"}, Ready: true}
+	if got := cr.Status.AtProvider.Projects[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("projects[0] = %+v, want %+v", got, want)
+	}
+}
+
+func TestChangedQuota(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := projectSet(team)
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	cr.Spec.ForProvider.Projects[0].StorageLimit = nil
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || obs.ResourceUpToDate {
+		t.Fatalf("Observe() with a changed quota = %+v, %v; want not up to date", obs, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	p := &v1beta1.Project{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "baseline-team"}, p); err != nil {
+		t.Fatal(err)
+	}
+	if got := *p.Spec.ForProvider.StorageLimit; got != 100 {
+		t.Errorf("storageLimit = %d, want the set's 100", got)
+	}
+}
+
+func TestRemovedProject(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := projectSet(library, team)
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	cr.Spec.ForProvider.Projects = []v1beta1.ProjectSetProject{team}
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || obs.ResourceUpToDate {
+		t.Fatalf("Observe() with a removed project = %+v, %v; want not up to date", obs, err)
+	}
+
+	// The robot goes first; it is removed from Harbor while its project
+	// still exists.
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	libraryKey := types.NamespacedName{Namespace: "harbor", Name: "baseline-library"}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "baseline-library-ci"}, &robotv1beta1.Robot{}); err == nil {
+		t.Error("robot of removed project was not deleted")
+	}
+	if err := e.kube.Get(ctx, libraryKey, &v1beta1.Project{}); err != nil {
+		t.Errorf("project deleted alongside its robot: %v", err)
+	}
+
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.kube.Get(ctx, libraryKey, &v1beta1.Project{}); err == nil {
+		t.Error("removed project was not deleted")
+	}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "baseline-team"}, &v1beta1.Project{}); err != nil {
+		t.Errorf("remaining project deleted: %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := projectSet(library)
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+	for i := 0; i < 2; i++ {
+		if _, err := e.Delete(ctx, cr); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+	}
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || obs.ResourceExists {
+		t.Errorf("Observe() after delete = %+v, %v; want gone", obs, err)
+	}
+}
+
+func TestResolvedProjectRefUpToDate(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := projectSet(library)
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	// The Robot controller records a policy when it resolves the reference.
+	r := &robotv1beta1.Robot{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "baseline-library-ci"}, r); err != nil {
+		t.Fatal(err)
+	}
+	r.Spec.ForProvider.ProjectRef.Policy = &xpv1.Policy{Resolve: ptr(xpv1.ResolvePolicyAlways)}
+	if err := e.kube.Update(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || !obs.ResourceUpToDate {
+		t.Errorf("Observe() with a resolved reference = %+v, %v; want up to date", obs, err)
+	}
+}
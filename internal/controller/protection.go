@@ -0,0 +1,146 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errGetProtectionPolicy = "cannot get ProviderConfig protection policy"
+	errRobotPattern        = "cannot match robot account name against pattern %q"
+)
+
+// A Target names the Harbor objects a managed resource changes.
+type Target struct {
+	// Project is the name or ID of the project the resource is, or is in.
+	Project string
+
+	// Robot is the name of the robot account the resource is.
+	Robot string
+}
+
+// A TargetFunc returns the Harbor objects mg changes. Either name is empty
+// if mg has none or it is not yet known.
+type TargetFunc func(mg resource.Managed) Target
+
+// WithProtection wraps c so that managed resources never update or delete
+// the projects and robot accounts the ProviderConfig's policy protects.
+// Such a resource gets a Protected condition and is reported as up to date,
+// and once it is being deleted it is reported as gone without asking Harbor,
+// so that only its finalizer is removed. Creating the object is allowed.
+func WithProtection(kube client.Reader, target TargetFunc, c managed.ExternalConnector) managed.ExternalConnector {
+	return &protectionConnector{ExternalConnector: c, kube: kube, target: target}
+}
+
+type protectionConnector struct {
+	managed.ExternalConnector
+	kube   client.Reader
+	target TargetFunc
+}
+
+func (c *protectionConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	reason, err := c.protected(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &protectionClient{ExternalClient: ext, reason: reason}, nil
+}
+
+// protected returns why the policy of mg's ProviderConfig protects the
+// objects mg changes, or an empty string if it does not.
+func (c *protectionConnector) protected(ctx context.Context, mg resource.Managed) (string, error) {
+	name := providerConfigName(mg)
+	if name == "" {
+		return "", nil
+	}
+	pc := &apisv1beta1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+		return "", errors.Wrap(err, errGetProtectionPolicy)
+	}
+	if pc.Spec.Policy == nil {
+		return "", nil
+	}
+	return protectedBy(*pc.Spec.Policy, c.target(mg), name)
+}
+
+// protectedBy returns why policy, of the ProviderConfig pc, protects t, or
+// an empty string if it does not.
+func protectedBy(policy apisv1beta1.ProviderConfigPolicy, t Target, pc string) (string, error) {
+	if t.Project != "" {
+		for _, p := range policy.ProtectedProjects {
+			if p == t.Project {
+				return fmt.Sprintf("project %s is protected by ProviderConfig %s", t.Project, pc), nil
+			}
+		}
+	}
+	if t.Robot != "" {
+		for _, pattern := range policy.ProtectedRobotPatterns {
+			ok, err := path.Match(pattern, t.Robot)
+			if err != nil {
+				return "", errors.Wrapf(err, errRobotPattern, pattern)
+			}
+			if ok {
+				return fmt.Sprintf("robot account %s is protected by ProviderConfig %s", t.Robot, pc), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+type protectionClient struct {
+	managed.ExternalClient
+	reason string
+}
+
+func (e *protectionClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	if e.reason == "" {
+		if mg.GetCondition(common.TypeProtected).Status == corev1.ConditionTrue {
+			mg.SetConditions(common.NotProtected())
+		}
+		return e.ExternalClient.Observe(ctx, mg)
+	}
+
+	mg.SetConditions(common.Protected(e.reason))
+	if meta.WasDeleted(mg) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return obs, err
+	}
+	obs.ResourceUpToDate = true
+	return obs, nil
+}
+
+func (e *protectionClient) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if e.reason != "" {
+		return managed.ExternalUpdate{}, nil
+	}
+	return e.ExternalClient.Update(ctx, mg)
+}
+
+func (e *protectionClient) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	if e.reason != "" {
+		return managed.ExternalDelete{}, nil
+	}
+	return e.ExternalClient.Delete(ctx, mg)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/rossigee/provider-harbor/apis/common"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// changingClient records which external operations were called, and
+// reports the resource as existing but out of date.
+type changingClient struct {
+	recordingClient
+}
+
+func (c *changingClient) Observe(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+	c.called = append(c.called, OperationObserve)
+	return managed.ExternalObservation{ResourceExists: true}, nil
+}
+
+func (c *changingClient) Update(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+	c.called = append(c.called, OperationUpdate)
+	return managed.ExternalUpdate{}, nil
+}
+
+func TestProtectedBy(t *testing.T) {
+	policy := apisv1beta1.ProviderConfigPolicy{
+		ProtectedProjects:      []string{"library", "1"},
+		ProtectedRobotPatterns: []string{"crossplane", "infra-*"},
+	}
+	cases := map[string]struct {
+		target  Target
+		want    bool
+		wantErr bool
+	}{
+		"ProjectByName":  {target: Target{Project: "library"}, want: true},
+		"ProjectByID":    {target: Target{Project: "1"}, want: true},
+		"OtherProject":   {target: Target{Project: "team"}},
+		"RobotExact":     {target: Target{Robot: "crossplane"}, want: true},
+		"RobotPattern":   {target: Target{Robot: "infra-backup"}, want: true},
+		"OtherRobot":     {target: Target{Robot: "ci"}},
+		"RobotInProject": {target: Target{Project: "library", Robot: "ci"}, want: true},
+		"Unknown":        {},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := protectedBy(policy, tc.target, "default")
+			if err != nil {
+				t.Fatalf("protectedBy() error = %v", err)
+			}
+			if (got != "") != tc.want {
+				t.Errorf("protectedBy() = %q, want protected %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := protectedBy(apisv1beta1.ProviderConfigPolicy{ProtectedRobotPatterns: []string{"["}}, Target{Robot: "ci"}, "default"); err == nil {
+		t.Error("protectedBy() should fail for a malformed pattern")
+	}
+}
+
+func TestWithProtection(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apisv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	pc := &apisv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: apisv1beta1.ProviderConfigSpec{
+			Policy: &apisv1beta1.ProviderConfigPolicy{ProtectedProjects: []string{"library"}},
+		},
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(pc).Build()
+	target := func(mg resource.Managed) Target { return Target{Project: projectName(mg)} }
+
+	cases := map[string]struct {
+		project       string
+		deleted       bool
+		wasProtected  bool
+		wantProtected corev1.ConditionStatus
+		wantExists    bool
+		wantUpToDate  bool
+		wantCalled    []string
+	}{
+		"Protected": {
+			project:       "library",
+			wantProtected: corev1.ConditionTrue,
+			wantExists:    true,
+			wantUpToDate:  true,
+			wantCalled:    []string{OperationObserve},
+		},
+		"ProtectedDeleted": {
+			project:       "library",
+			deleted:       true,
+			wantProtected: corev1.ConditionTrue,
+		},
+		"Unprotected": {
+			project:       "web",
+			wantProtected: corev1.ConditionUnknown,
+			wantExists:    true,
+			wantCalled:    []string{OperationObserve, OperationUpdate, OperationDelete},
+		},
+		"NoLongerProtected": {
+			project:       "web",
+			wasProtected:  true,
+			wantProtected: corev1.ConditionFalse,
+			wantExists:    true,
+			wantCalled:    []string{OperationObserve, OperationUpdate, OperationDelete},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := managedProject("team-a", "default", time.Hour)
+			cr.Spec.ForProvider.Name = tc.project
+			if tc.deleted {
+				now := metav1.Now()
+				cr.SetDeletionTimestamp(&now)
+			}
+			if tc.wasProtected {
+				cr.SetConditions(common.Protected("project web is protected by ProviderConfig default"))
+			}
+			rec := &changingClient{}
+			ext, err := WithProtection(kube, target, &staticConnector{ext: rec}).Connect(context.Background(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Observe() error = %v", err)
+			}
+			if obs.ResourceExists != tc.wantExists || obs.ResourceUpToDate != tc.wantUpToDate {
+				t.Errorf("Observe() = %+v, want exists %v, up to date %v", obs, tc.wantExists, tc.wantUpToDate)
+			}
+			if got := cr.GetCondition(common.TypeProtected).Status; got != tc.wantProtected {
+				t.Errorf("Protected = %v, want %v", got, tc.wantProtected)
+			}
+			if _, err := ext.Update(context.Background(), cr); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ext.Delete(context.Background(), cr); err != nil {
+				t.Fatal(err)
+			}
+			if len(rec.called) != len(tc.wantCalled) {
+				t.Fatalf("inner calls = %v, want %v", rec.called, tc.wantCalled)
+			}
+			for i := range tc.wantCalled {
+				if rec.called[i] != tc.wantCalled[i] {
+					t.Errorf("inner calls = %v, want %v", rec.called, tc.wantCalled)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package providerconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/pkg/errors"
+	v1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultCapabilityProbeInterval is how often the Harbor account of each
+// ProviderConfig is probed for its capabilities.
+const DefaultCapabilityProbeInterval = 10 * time.Minute
+
+const (
+	errNewProbeClient     = "cannot create Harbor client to probe capabilities"
+	errUpdateCapabilities = "cannot record ProviderConfig capabilities"
+
+	reasonProbeFailed event.Reason = "CapabilityProbeFailed"
+)
+
+// SetupCapabilityProbe adds a controller that probes what the Harbor account
+// of every ProviderConfig may do, when the ProviderConfig changes and then
+// every interval, and records it in the ProviderConfig's
+// status.capabilities, where managed resource controllers consult it.
+func SetupCapabilityProbe(mgr ctrl.Manager, o controller.Options, interval time.Duration) error {
+	name := "providerconfig/capability-probe"
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ProviderConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(&prober{
+			kube:      mgr.GetClient(),
+			newClient: harborclients.NewHarborClientForProviderConfig,
+			recorder:  event.NewAPIRecorder(mgr.GetEventRecorder(name)),
+			log:       o.Logger.WithValues("controller", name),
+			interval:  interval,
+		})
+}
+
+// A prober records the capabilities of each ProviderConfig's Harbor account.
+type prober struct {
+	kube      client.Client
+	newClient func(ctx context.Context, kube client.Client, name string) (harborclients.HarborClienter, error)
+	recorder  event.Recorder
+	log       logging.Logger
+	interval  time.Duration
+}
+
+func (p *prober) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	pc := &v1beta1.ProviderConfig{}
+	if err := p.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, errors.Wrap(err, errGetProviderConfig)
+	}
+	if meta.WasDeleted(pc) {
+		return reconcile.Result{}, nil
+	}
+
+	caps, err := p.probe(ctx, pc.GetName())
+	if err != nil {
+		// Capabilities already recorded are kept: a Harbor that cannot be
+		// reached says nothing about what the account may do.
+		p.log.Debug("Cannot probe capabilities", "providerconfig", pc.GetName(), "error", err)
+		p.recorder.Event(pc, event.Warning(reasonProbeFailed, err))
+		return reconcile.Result{RequeueAfter: p.interval}, nil
+	}
+
+	pc.Status.Capabilities = caps
+	if err := p.kube.Status().Update(ctx, pc); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errUpdateCapabilities)
+	}
+	return reconcile.Result{RequeueAfter: p.interval}, nil
+}
+
+func (p *prober) probe(ctx context.Context, name string) (*v1beta1.ProviderConfigCapabilities, error) {
+	c, err := p.newClient(ctx, p.kube, name)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewProbeClient)
+	}
+	defer func() { _ = c.Close() }()
+	return harborclients.ProbeCapabilities(ctx, c)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package providerconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	v1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestProbeReconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	pc := &v1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(pc).WithStatusSubresource(pc).Build()
+
+	var userErr error
+	p := &prober{
+		kube: kube,
+		newClient: func(context.Context, client.Client, string) (harborclients.HarborClienter, error) {
+			return &harborclients.MockHarborClient{
+				GetCurrentUserFunc: func(context.Context) (*harborclients.CurrentUser, error) {
+					if userErr != nil {
+						return nil, userErr
+					}
+					return &harborclients.CurrentUser{Username: "ci"}, nil
+				},
+				GetSystemInfoFunc: func(context.Context) (*harborclients.SystemInfo, error) {
+					return &harborclients.SystemInfo{ProjectCreationRestriction: "everyone"}, nil
+				},
+			}, nil
+		},
+		recorder: event.NewNopRecorder(),
+		log:      logging.NewNopLogger(),
+		interval: time.Minute,
+	}
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "default"}}
+	get := func() *v1beta1.ProviderConfigCapabilities {
+		t.Helper()
+		got := &v1beta1.ProviderConfig{}
+		if err := kube.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatal(err)
+		}
+		return got.Status.Capabilities
+	}
+
+	res, err := p.Reconcile(ctx, req)
+	if err != nil || res.RequeueAfter != time.Minute {
+		t.Fatalf("Reconcile() = %+v, %v", res, err)
+	}
+	caps := get()
+	if caps == nil || caps.Account != "ci" || !caps.CreateProjects || caps.ManageSystem {
+		t.Fatalf("capabilities = %+v", caps)
+	}
+
+	userErr = errors.New("unreachable")
+	if _, err := p.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if get() == nil {
+		t.Error("capabilities dropped when Harbor could not be reached")
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package providerconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	v1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultUsageMinAge is how old a ProviderConfigUsage must be before it
+	// can be pruned. A newer usage may belong to a managed resource that is
+	// not yet in the cache.
+	DefaultUsageMinAge = 5 * time.Minute
+
+	errListProviderConfigUsages  = "cannot list ProviderConfigUsages"
+	errListUsedResources         = "cannot list %s resources"
+	errDeleteProviderConfigUsage = "cannot delete ProviderConfigUsage"
+)
+
+// A UsageJanitor periodically deletes the ProviderConfigUsages of managed
+// resources that no longer exist. Usages are cluster scoped while managed
+// resources are namespaced, so Kubernetes garbage collection does not
+// delete a usage with its resource, and one left behind keeps its
+// ProviderConfig from being deleted.
+type UsageJanitor struct {
+	kube     client.Client
+	log      logging.Logger
+	interval time.Duration
+	minAge   time.Duration
+	now      func() time.Time
+}
+
+// A JanitorOption configures a UsageJanitor.
+type JanitorOption func(*UsageJanitor)
+
+// WithJanitorLogger sets the logger.
+func WithJanitorLogger(l logging.Logger) JanitorOption {
+	return func(j *UsageJanitor) { j.log = l }
+}
+
+// WithJanitorInterval sets how often Start prunes.
+func WithJanitorInterval(d time.Duration) JanitorOption {
+	return func(j *UsageJanitor) { j.interval = d }
+}
+
+// WithUsageMinAge sets how old a usage must be before it is pruned.
+func WithUsageMinAge(d time.Duration) JanitorOption {
+	return func(j *UsageJanitor) { j.minAge = d }
+}
+
+// NewUsageJanitor returns a UsageJanitor that prunes hourly.
+func NewUsageJanitor(kube client.Client, o ...JanitorOption) *UsageJanitor {
+	j := &UsageJanitor{
+		kube:     kube,
+		log:      logging.NewNopLogger(),
+		interval: time.Hour,
+		minAge:   DefaultUsageMinAge,
+		now:      time.Now,
+	}
+	for _, fn := range o {
+		fn(j)
+	}
+	return j
+}
+
+// NeedLeaderElection ensures only one replica prunes at a time.
+func (j *UsageJanitor) NeedLeaderElection() bool {
+	return true
+}
+
+// Start prunes every interval until ctx is done. Failures are logged and
+// retried at the next interval.
+func (j *UsageJanitor) Start(ctx context.Context) error {
+	t := time.NewTicker(j.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if _, err := j.Prune(ctx); err != nil {
+				j.log.Info("ProviderConfigUsage garbage collection failed", "error", err)
+			}
+		}
+	}
+}
+
+// Prune deletes every ProviderConfigUsage old enough to be considered whose
+// managed resource no longer exists, and returns the names of the usages it
+// deleted. Usages of kinds the API server does not serve are left alone.
+func (j *UsageJanitor) Prune(ctx context.Context) ([]string, error) {
+	l := &v1beta1.ProviderConfigUsageList{}
+	if err := j.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListProviderConfigUsages)
+	}
+
+	// Each kind is listed once, however many usages refer to it.
+	live := map[schema.GroupVersionKind]map[types.UID]bool{}
+	var pruned []string
+	for i := range l.Items {
+		u := &l.Items[i]
+		if j.now().Sub(u.GetCreationTimestamp().Time) < j.minAge {
+			continue
+		}
+		gvk := schema.FromAPIVersionAndKind(u.ResourceReference.APIVersion, u.ResourceReference.Kind)
+		uids, ok := live[gvk]
+		if !ok {
+			var err error
+			uids, err = j.liveUIDs(ctx, gvk)
+			if kmeta.IsNoMatchError(err) {
+				j.log.Debug("Not pruning ProviderConfigUsage of unknown kind", "usage", u.GetName(), "kind", gvk.String())
+				continue
+			}
+			if err != nil {
+				return pruned, err
+			}
+			live[gvk] = uids
+		}
+		if usageLive(u, uids) {
+			continue
+		}
+		if err := j.kube.Delete(ctx, u); resource.IgnoreNotFound(err) != nil {
+			return pruned, errors.Wrap(err, errDeleteProviderConfigUsage)
+		}
+		j.log.Info("Deleted stale ProviderConfigUsage", "usage", u.GetName(), "providerConfig", u.ProviderConfigReference.Name, "kind", gvk.Kind, "name", u.ResourceReference.Name)
+		pruned = append(pruned, u.GetName())
+	}
+	return pruned, nil
+}
+
+// liveUIDs returns the UIDs of every resource of the given kind.
+func (j *UsageJanitor) liveUIDs(ctx context.Context, gvk schema.GroupVersionKind) (map[types.UID]bool, error) {
+	l := &metav1.PartialObjectMetadataList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := j.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrapf(err, errListUsedResources, gvk.Kind)
+	}
+	uids := make(map[types.UID]bool, len(l.Items))
+	for i := range l.Items {
+		uids[l.Items[i].GetUID()] = true
+	}
+	return uids, nil
+}
+
+// usageLive reports whether the managed resource of u is among uids. The
+// resource is identified by the UID in the resource reference, by the
+// controller reference, or by the name of the usage, which Crossplane sets
+// to the UID of the resource.
+func usageLive(u *v1beta1.ProviderConfigUsage, uids map[types.UID]bool) bool {
+	if uid := u.ResourceReference.UID; uid != "" {
+		return uids[uid]
+	}
+	if ref := metav1.GetControllerOf(u); ref != nil {
+		return uids[ref.UID]
+	}
+	return uids[types.UID(u.GetName())]
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package providerconfig
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	v1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func usage(name, kind, resource string, created time.Time) *v1beta1.ProviderConfigUsage {
+	u := &v1beta1.ProviderConfigUsage{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)}}
+	u.ProviderConfigReference = xpv1.ProviderConfigReference{Name: "default", Kind: "ProviderConfig"}
+	u.ResourceReference = xpv1.TypedReference{APIVersion: projectv1beta1.SchemeGroupVersion.String(), Kind: kind, Name: resource}
+	return u
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-time.Hour)
+
+	live := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "harbor", UID: "live-uid"}}
+
+	owned := usage("owned", projectv1beta1.ProjectKind, "team-b", old)
+	owned.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: projectv1beta1.SchemeGroupVersion.String(), Kind: projectv1beta1.ProjectKind,
+		Name: "team-a", UID: "live-uid", Controller: ptr(true),
+	}})
+	referenced := usage("referenced", projectv1beta1.ProjectKind, "team-a", old)
+	referenced.ResourceReference.UID = "live-uid"
+
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		live,
+		usage("live-uid", projectv1beta1.ProjectKind, "team-a", old),
+		owned,
+		referenced,
+		usage("gone-uid", projectv1beta1.ProjectKind, "team-c", old),
+		usage("new-uid", projectv1beta1.ProjectKind, "team-d", now),
+	).Build()
+
+	j := NewUsageJanitor(kube)
+	j.now = func() time.Time { return now }
+	pruned, err := j.Prune(context.Background())
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if want := []string{"gone-uid"}; !reflect.DeepEqual(pruned, want) {
+		t.Errorf("Prune() = %v, want %v", pruned, want)
+	}
+
+	l := &v1beta1.ProviderConfigUsageList{}
+	if err := kube.List(context.Background(), l); err != nil {
+		t.Fatal(err)
+	}
+	var remaining []string
+	for _, u := range l.Items {
+		remaining = append(remaining, u.GetName())
+	}
+	sort.Strings(remaining)
+	if want := []string{"live-uid", "new-uid", "owned", "referenced"}; !reflect.DeepEqual(remaining, want) {
+		t.Errorf("remaining usages = %v, want %v", remaining, want)
+	}
+
+	// Nothing is left to prune on the next run.
+	if pruned, err := j.Prune(context.Background()); err != nil || len(pruned) != 0 {
+		t.Errorf("second Prune() = %v, %v, want nothing pruned", pruned, err)
+	}
+}
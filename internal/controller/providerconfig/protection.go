@@ -0,0 +1,143 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package providerconfig
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	protectionv1beta1 "github.com/crossplane/crossplane/apis/v2/protection/v1beta1"
+	"github.com/pkg/errors"
+	v1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// LabelProtectedBy is set on the Usages that protect the credentials Secret
+// of a ProviderConfig to the ProviderConfig's name.
+const LabelProtectedBy = "harbor.m.crossplane.io/provider-config"
+
+const (
+	errGetProviderConfig = "cannot get ProviderConfig"
+	errApplyUsage        = "cannot apply Usage of credentials Secret"
+	errListUsages        = "cannot list Usages of credentials Secrets"
+	errDeleteUsage       = "cannot delete Usage of credentials Secret"
+
+	// noUsageRetry is how long to wait before trying again when Crossplane's
+	// Usage API is not installed.
+	noUsageRetry = 10 * time.Minute
+)
+
+// SetupCredentialProtection adds a controller that protects the credentials
+// Secret of every ProviderConfig with a Crossplane Usage, so that the Secret
+// cannot be deleted while the ProviderConfig exists. Without it, deleting the
+// Secret fails every reconcile of every managed resource that uses the
+// ProviderConfig.
+func SetupCredentialProtection(mgr ctrl.Manager, o controller.Options) error {
+	name := "providerconfig/credential-protection"
+
+	if err := protectionv1beta1.AddToScheme(mgr.GetScheme()); err != nil {
+		return errors.Wrap(err, "cannot add Crossplane protection API to scheme")
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ProviderConfig{}).
+		Complete(&protector{
+			kube: mgr.GetClient(),
+			log:  o.Logger.WithValues("controller", name),
+		})
+}
+
+// A protector keeps one Usage per ProviderConfig that reads its credentials
+// from a Secret.
+type protector struct {
+	kube client.Client
+	log  logging.Logger
+}
+
+// usageName is the name of the Usage protecting the credentials Secret of
+// the named ProviderConfig.
+func usageName(pc string) string {
+	return "harbor-providerconfig-" + pc
+}
+
+func (p *protector) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := p.log.WithValues("providerconfig", req.Name)
+
+	pc := &v1beta1.ProviderConfig{}
+	if err := p.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, p.release(ctx, req.Name, nil)
+		}
+		return reconcile.Result{}, errors.Wrap(err, errGetProviderConfig)
+	}
+
+	ref := pc.Spec.Credentials.SecretRef
+	if meta.WasDeleted(pc) || pc.Spec.Credentials.Source != xpv1.CredentialsSourceSecret || ref == nil {
+		return reconcile.Result{}, p.release(ctx, pc.GetName(), nil)
+	}
+
+	u := &protectionv1beta1.Usage{}
+	u.SetNamespace(ref.Namespace)
+	u.SetName(usageName(pc.GetName()))
+	_, err := controllerutil.CreateOrUpdate(ctx, p.kube, u, func() error {
+		meta.AddLabels(u, map[string]string{LabelProtectedBy: pc.GetName()})
+		ctrlutil.SetOwner(u, pc, v1beta1.ProviderConfigGroupVersionKind)
+		u.Spec.Of = protectionv1beta1.NamespacedResource{
+			APIVersion:  "v1",
+			Kind:        "Secret",
+			ResourceRef: &protectionv1beta1.NamespacedResourceRef{Name: ref.Name},
+		}
+		u.Spec.Reason = ptr(fmt.Sprintf("holds the Harbor credentials of ProviderConfig %s; delete the ProviderConfig first", pc.GetName()))
+		return nil
+	})
+	if kmeta.IsNoMatchError(err) {
+		log.Info("Crossplane's Usage API is not installed; credentials Secret is not protected")
+		return reconcile.Result{RequeueAfter: noUsageRetry}, nil
+	}
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errApplyUsage)
+	}
+
+	// The Secret may have moved, leaving a Usage in its old namespace.
+	return reconcile.Result{}, p.release(ctx, pc.GetName(), u)
+}
+
+// release deletes the Usages of the named ProviderConfig, except keep.
+func (p *protector) release(ctx context.Context, pc string, keep *protectionv1beta1.Usage) error {
+	l := &protectionv1beta1.UsageList{}
+	err := p.kube.List(ctx, l, client.MatchingLabels{LabelProtectedBy: pc})
+	if kmeta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errListUsages)
+	}
+	for i := range l.Items {
+		u := &l.Items[i]
+		if keep != nil && u.GetNamespace() == keep.GetNamespace() && u.GetName() == keep.GetName() {
+			continue
+		}
+		if err := p.kube.Delete(ctx, u); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteUsage)
+		}
+	}
+	return nil
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package providerconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	protectionv1beta1 "github.com/crossplane/crossplane/apis/v2/protection/v1beta1"
+	v1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func providerConfig(namespace string) *v1beta1.ProviderConfig {
+	return &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "pc-uid"},
+		Spec: v1beta1.ProviderConfigSpec{Credentials: v1beta1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: "harbor-creds", Namespace: namespace},
+				Key:             "credentials",
+			}},
+		}},
+	}
+}
+
+func newProtector(t *testing.T, objs ...client.Object) *protector {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := protectionv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return &protector{kube: fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(), log: logging.NewNopLogger()}
+}
+
+func reconcileDefault(t *testing.T, p *protector) {
+	t.Helper()
+	if _, err := p.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "default"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+}
+
+func usages(t *testing.T, p *protector) []protectionv1beta1.Usage {
+	t.Helper()
+	l := &protectionv1beta1.UsageList{}
+	if err := p.kube.List(context.Background(), l); err != nil {
+		t.Fatal(err)
+	}
+	return l.Items
+}
+
+func TestProtectCredentials(t *testing.T) {
+	pc := providerConfig("crossplane-system")
+	p := newProtector(t, pc)
+	reconcileDefault(t, p)
+
+	got := usages(t, p)
+	if len(got) != 1 {
+		t.Fatalf("%d Usages, want 1", len(got))
+	}
+	u := got[0]
+	if u.GetNamespace() != "crossplane-system" || u.Spec.Of.Kind != "Secret" || u.Spec.Of.ResourceRef.Name != "harbor-creds" {
+		t.Errorf("Usage %s/%s protects %s %v, want Secret crossplane-system/harbor-creds", u.GetNamespace(), u.GetName(), u.Spec.Of.Kind, u.Spec.Of.ResourceRef)
+	}
+	if u.Spec.Reason == nil {
+		t.Error("Usage has no reason to report when the deletion is blocked")
+	}
+	if !metav1.IsControlledBy(&u, pc) {
+		t.Error("Usage is not owned by its ProviderConfig")
+	}
+
+	// Reconciling again is a no-op.
+	reconcileDefault(t, p)
+	if n := len(usages(t, p)); n != 1 {
+		t.Errorf("%d Usages after a second reconcile, want 1", n)
+	}
+}
+
+func TestProtectMovedSecret(t *testing.T) {
+	p := newProtector(t, providerConfig("old"))
+	reconcileDefault(t, p)
+
+	pc := &v1beta1.ProviderConfig{}
+	if err := p.kube.Get(context.Background(), types.NamespacedName{Name: "default"}, pc); err != nil {
+		t.Fatal(err)
+	}
+	pc.Spec.Credentials.SecretRef.Namespace = "new"
+	if err := p.kube.Update(context.Background(), pc); err != nil {
+		t.Fatal(err)
+	}
+	reconcileDefault(t, p)
+
+	got := usages(t, p)
+	if len(got) != 1 || got[0].GetNamespace() != "new" {
+		t.Errorf("Usages = %v, want one in namespace new", got)
+	}
+}
+
+func TestReleaseCredentials(t *testing.T) {
+	cases := map[string]func(*v1beta1.ProviderConfig) *v1beta1.ProviderConfig{
+		"NotFromSecret": func(pc *v1beta1.ProviderConfig) *v1beta1.ProviderConfig {
+			pc.Spec.Credentials.Source = xpv1.CredentialsSourceInjectedIdentity
+			return pc
+		},
+		"Deleted": func(*v1beta1.ProviderConfig) *v1beta1.ProviderConfig { return nil },
+	}
+
+	for name, change := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := newProtector(t, providerConfig("crossplane-system"))
+			reconcileDefault(t, p)
+
+			pc := &v1beta1.ProviderConfig{}
+			if err := p.kube.Get(context.Background(), types.NamespacedName{Name: "default"}, pc); err != nil {
+				t.Fatal(err)
+			}
+			if changed := change(pc); changed != nil {
+				if err := p.kube.Update(context.Background(), changed); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := p.kube.Delete(context.Background(), pc); err != nil {
+				t.Fatal(err)
+			}
+			reconcileDefault(t, p)
+
+			if n := len(usages(t, p)); n != 0 {
+				t.Errorf("%d Usages left, want 0", n)
+			}
+		})
+	}
+}
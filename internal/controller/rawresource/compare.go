@@ -0,0 +1,95 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package rawresource
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// decode parses a JSON document. An empty document is an empty object.
+func decode(data []byte) (any, error) {
+	if len(data) == 0 {
+		return map[string]any{}, nil
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON")
+	}
+	return v, nil
+}
+
+// contains reports whether observed has every field desired sets. Fields
+// only observed has, such as IDs and timestamps Harbor adds, are ignored.
+// Harbor leaves out fields with zero values, so a field observed lacks
+// matches a desired zero value. Lists must have the same length, and each
+// item must contain the desired one.
+func contains(desired, observed any) bool {
+	switch d := desired.(type) {
+	case map[string]any:
+		o, ok := observed.(map[string]any)
+		if !ok {
+			return observed == nil && isZero(d)
+		}
+		for k, dv := range d {
+			ov, ok := o[k]
+			if !ok {
+				if !isZero(dv) {
+					return false
+				}
+				continue
+			}
+			if !contains(dv, ov) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		o, ok := observed.([]any)
+		if !ok {
+			return observed == nil && len(d) == 0
+		}
+		if len(d) != len(o) {
+			return false
+		}
+		for i := range d {
+			if !contains(d[i], o[i]) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return isZero(observed)
+	default:
+		// Numbers are decoded as float64, so 1 and 1.0 compare equal.
+		return desired == observed
+	}
+}
+
+// isZero reports whether v is a JSON zero value: null, false, 0, "", or an
+// empty list or object, or an object of zero values.
+func isZero(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !t
+	case float64:
+		return t == 0
+	case string:
+		return t == ""
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		for _, tv := range t {
+			if !isZero(tv) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package rawresource
+
+import "testing"
+
+func TestContains(t *testing.T) {
+	cases := map[string]struct {
+		desired, observed string
+		want              bool
+	}{
+		"Equal":            {`{"a": 1}`, `{"a": 1}`, true},
+		"ExtraObserved":    {`{"a": 1}`, `{"a": 1, "id": 7}`, true},
+		"Changed":          {`{"a": 1}`, `{"a": 2}`, false},
+		"NumberForms":      {`{"a": 1.0}`, `{"a": 1}`, true},
+		"MissingZero":      {`{"a": false, "b": "", "c": 0, "d": []}`, `{}`, true},
+		"MissingNonZero":   {`{"a": true}`, `{}`, false},
+		"Nested":           {`{"s": {"type": "Daily"}}`, `{"s": {"type": "Daily", "cron": "0 0 0 * * *"}}`, true},
+		"NestedChanged":    {`{"s": {"type": "Daily"}}`, `{"s": {"type": "Weekly"}}`, false},
+		"ListItems":        {`{"l": [{"k": "x"}]}`, `{"l": [{"k": "x", "id": 1}]}`, true},
+		"ListLength":       {`{"l": [1]}`, `{"l": [1, 2]}`, false},
+		"NullObserved":     {`{"l": []}`, `{"l": null}`, true},
+		"TypeChanged":      {`{"a": "1"}`, `{"a": 1}`, false},
+		"EmptyDesired":     {`{}`, `{"a": 1}`, true},
+		"EmptyObservation": {`{"a": 1}`, ``, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := decode([]byte(tc.desired))
+			if err != nil {
+				t.Fatal(err)
+			}
+			o, err := decode([]byte(tc.observed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := contains(d, o); got != tc.want {
+				t.Errorf("contains(%s, %s) = %v, want %v", tc.desired, tc.observed, got, tc.want)
+			}
+		})
+	}
+}
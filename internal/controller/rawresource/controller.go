@@ -0,0 +1,213 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package rawresource manages HarborRawResources, which put JSON at Harbor
+// API paths the provider has no kind for.
+package rawresource
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/raw/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotHarborRawResource = "managed resource is not a HarborRawResource custom resource"
+	errNewClient            = "cannot create new Service"
+	errGet                  = "cannot get %s"
+	errPut                  = "cannot put %s"
+	errDelete               = "cannot delete %s"
+	errBody                 = "cannot parse body"
+	errResponse             = "cannot parse the response to GET %s"
+)
+
+// Setup adds a controller that reconciles HarborRawResource managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.HarborRawResourceGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), clients.CapabilityManageSystem, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithSpecChecksums(mgr.GetClient(), ctrlutil.WithUpdateDebounce(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:   mgr.GetClient(),
+			logger: log,
+		}))))))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(10 * time.Minute),
+		managed.WithPollIntervalHook(ctrlutil.DebouncedPollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.HarborRawResourceGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.HarborRawResource{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// Connect produces an ExternalClient by creating a Harbor client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.HarborRawResource); !ok {
+		return nil, errors.New(errNotHarborRawResource)
+	}
+
+	harborClient, err := clients.NewHarborClientFromProviderConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{service: harborClient, logger: c.logger}, nil
+}
+
+// external puts a HarborRawResource's body at its path.
+type external struct {
+	service clients.HarborClienter
+	logger  logging.Logger
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "rawresource.observe",
+		tracing.SpanAttrs("HarborRawResource", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.HarborRawResource)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotHarborRawResource)
+	}
+
+	path := cr.Spec.ForProvider.Path
+	p, err := clients.MatchRawPath(path)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.Status.AtProvider.Deletable = &p.Deletable
+
+	// Settings that cannot be deleted are left as they are.
+	if meta.WasDeleted(cr) && !p.Deletable {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	data, err := c.service.GetRaw(ctx, path)
+	if clients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errGet, path)
+	}
+	cr.Status.AtProvider.Response = nil
+	if len(data) > 0 {
+		cr.Status.AtProvider.Response = &runtime.RawExtension{Raw: data}
+	}
+
+	desired, err := decode(cr.Spec.ForProvider.Body.Raw)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errBody)
+	}
+	observed, err := decode(data)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errResponse, path)
+	}
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: contains(desired, observed),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, span := tracing.StartSpan(ctx, "rawresource.create",
+		tracing.SpanAttrs("HarborRawResource", tracing.ResourceName(mg), "create")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.HarborRawResource)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotHarborRawResource)
+	}
+	cr.SetConditions(xpv1.Creating())
+
+	return managed.ExternalCreation{}, c.put(ctx, cr)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "rawresource.update",
+		tracing.SpanAttrs("HarborRawResource", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.HarborRawResource)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotHarborRawResource)
+	}
+
+	return managed.ExternalUpdate{}, c.put(ctx, cr)
+}
+
+// put puts the body of cr at its path.
+func (c *external) put(ctx context.Context, cr *v1beta1.HarborRawResource) error {
+	path := cr.Spec.ForProvider.Path
+	if _, err := decode(cr.Spec.ForProvider.Body.Raw); err != nil {
+		return errors.Wrap(err, errBody)
+	}
+	if err := c.service.PutRaw(ctx, path, cr.Spec.ForProvider.Body.Raw); err != nil {
+		return errors.Wrapf(err, errPut, path)
+	}
+	if err := ctrlutil.CheckReadBack(cr, cr.Spec.ForProvider.Body.Raw, func() (any, error) { return c.service.GetRaw(ctx, path) }); err != nil {
+		return err
+	}
+	c.logger.Info("Put raw Harbor resource", "name", cr.GetName(), "path", path)
+	return nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	_, span := tracing.StartSpan(ctx, "rawresource.delete",
+		tracing.SpanAttrs("HarborRawResource", tracing.ResourceName(mg), "delete")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.HarborRawResource)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotHarborRawResource)
+	}
+	cr.SetConditions(xpv1.Deleting())
+
+	path := cr.Spec.ForProvider.Path
+	p, err := clients.MatchRawPath(path)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+	if !p.Deletable {
+		return managed.ExternalDelete{}, nil
+	}
+	if err := c.service.DeleteRaw(ctx, path); err != nil && !clients.IsNotFound(err) {
+		return managed.ExternalDelete{}, errors.Wrapf(err, errDelete, path)
+	}
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package rawresource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	goruntime "github.com/go-openapi/runtime"
+	"github.com/rossigee/provider-harbor/apis/raw/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func rawResource(path, body string) *v1beta1.HarborRawResource {
+	return &v1beta1.HarborRawResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "raw", Namespace: "harbor"},
+		Spec: v1beta1.HarborRawResourceSpec{
+			ForProvider: v1beta1.HarborRawResourceParameters{
+				Path: path,
+				Body: runtime.RawExtension{Raw: []byte(body)},
+			},
+		},
+	}
+}
+
+func TestObserve(t *testing.T) {
+	const path = "/projects/team-a/preheat/policies/warm"
+	cases := map[string]struct {
+		path     string
+		body     string
+		response string
+		getErr   error
+		deleted  bool
+		wantErr  bool
+		exists   bool
+		upToDate bool
+	}{
+		"UpToDate": {
+			body:     `{"name": "warm", "enabled": true, "trigger": "{\"type\":\"manual\"}"}`,
+			response: `{"id": 3, "name": "warm", "enabled": true, "trigger": "{\"type\":\"manual\"}", "creation_time": "2024-05-03T22:00:00Z"}`,
+			exists:   true,
+			upToDate: true,
+		},
+		"Drifted": {
+			body:     `{"name": "warm", "enabled": true}`,
+			response: `{"id": 3, "name": "warm"}`,
+			exists:   true,
+		},
+		"OmittedZeroValue": {
+			body:     `{"name": "warm", "enabled": false}`,
+			response: `{"id": 3, "name": "warm"}`,
+			exists:   true,
+			upToDate: true,
+		},
+		"NotFound": {
+			body:   `{"name": "warm"}`,
+			getErr: goruntime.NewAPIError("rawGET", nil, http.StatusNotFound),
+		},
+		"GetFailed": {
+			body:    `{"name": "warm"}`,
+			getErr:  goruntime.NewAPIError("rawGET", nil, http.StatusInternalServerError),
+			wantErr: true,
+		},
+		"PathNotAllowed": {
+			path:    "/users/1/password",
+			body:    `{"new_password": "x"}`,
+			wantErr: true,
+		},
+		"DeletedSetting": {
+			path:    "/system/purgeaudit/schedule",
+			body:    `{"schedule": {"type": "Weekly"}}`,
+			deleted: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := path
+			if tc.path != "" {
+				p = tc.path
+			}
+			cr := rawResource(p, tc.body)
+			if tc.deleted {
+				now := metav1.Now()
+				cr.SetDeletionTimestamp(&now)
+			}
+			e := &external{logger: logging.NewNopLogger(), service: &harborclients.MockHarborClient{
+				GetRawFunc: func(_ context.Context, got string) ([]byte, error) {
+					if tc.deleted {
+						t.Errorf("GetRaw(%s) called for a deleted setting", got)
+					}
+					return []byte(tc.response), tc.getErr
+				},
+			}}
+
+			obs, err := e.Observe(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Observe() error = %v, want error: %v", err, tc.wantErr)
+			}
+			if obs.ResourceExists != tc.exists || obs.ResourceUpToDate != tc.upToDate {
+				t.Errorf("Observe() = exists %v, upToDate %v; want %v, %v", obs.ResourceExists, obs.ResourceUpToDate, tc.exists, tc.upToDate)
+			}
+			if tc.exists && (cr.Status.AtProvider.Response == nil || string(cr.Status.AtProvider.Response.Raw) != tc.response) {
+				t.Errorf("status.atProvider.response = %v, want the GET response", cr.Status.AtProvider.Response)
+			}
+		})
+	}
+}
+
+func TestPutAndDelete(t *testing.T) {
+	cases := map[string]struct {
+		path       string
+		wantDelete bool
+	}{
+		"Deletable":   {path: "/projects/team-a/metadatas/auto_scan", wantDelete: true},
+		"AlwaysThere": {path: "/system/scanAll/schedule"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var put, deleted string
+			e := &external{logger: logging.NewNopLogger(), service: &harborclients.MockHarborClient{
+				PutRawFunc: func(_ context.Context, path string, body []byte) error {
+					put = path + " " + string(body)
+					return nil
+				},
+				DeleteRawFunc: func(_ context.Context, path string) error {
+					deleted = path
+					return goruntime.NewAPIError("rawDELETE", nil, http.StatusNotFound)
+				},
+			}}
+			cr := rawResource(tc.path, `{"auto_scan": "true"}`)
+
+			if _, err := e.Create(context.Background(), cr); err != nil {
+				t.Fatal(err)
+			}
+			if want := tc.path + ` {"auto_scan": "true"}`; put != want {
+				t.Errorf("PutRaw = %q, want %q", put, want)
+			}
+			if _, err := e.Delete(context.Background(), cr); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if (deleted != "") != tc.wantDelete {
+				t.Errorf("DeleteRaw(%q) called: %v, want %v", deleted, deleted != "", tc.wantDelete)
+			}
+		})
+	}
+}
+
+func TestInvalidBody(t *testing.T) {
+	e := &external{logger: logging.NewNopLogger(), service: &harborclients.MockHarborClient{
+		PutRawFunc: func(context.Context, string, []byte) error {
+			t.Error("PutRaw called with invalid JSON")
+			return nil
+		},
+	}}
+	if _, err := e.Update(context.Background(), rawResource("/labels/7", `{"name":`)); err == nil {
+		t.Error("Update() error = nil, want invalid JSON")
+	}
+}
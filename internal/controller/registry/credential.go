@@ -0,0 +1,137 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// registrySpec returns the registry endpoint cr describes, with the access
+// secret read from its Secret.
+func (c *external) registrySpec(ctx context.Context, cr *v1beta1.Registry) (*harborclients.RegistrySpec, error) {
+	p := cr.Spec.ForProvider
+	spec := &harborclients.RegistrySpec{
+		Name:        p.Name,
+		Type:        p.Type,
+		URL:         normalizeURL(p.URL),
+		Description: p.Description,
+	}
+	if p.Insecure != nil {
+		spec.Insecure = *p.Insecure
+	}
+	if p.Credential == nil {
+		return spec, nil
+	}
+
+	spec.Credential = &harborclients.RegistryCredential{}
+	if p.Credential.Type != nil {
+		spec.Credential.Type = *p.Credential.Type
+	}
+	if p.Credential.AccessKey != nil {
+		spec.Credential.AccessKey = *p.Credential.AccessKey
+	}
+	if p.Credential.AccessSecretRef != nil {
+		secret, err := c.getSecretFromRef(ctx, cr)
+		if err != nil {
+			return nil, errors.Wrap(err, errAccessSecret)
+		}
+		spec.Credential.AccessSecret = secret
+	}
+	return spec, nil
+}
+
+// accessSecretNamespace returns the namespace of the Secret holding cr's
+// access secret. A reference without a namespace is to a Secret in the
+// Registry's own namespace.
+func accessSecretNamespace(cr *v1beta1.Registry) string {
+	if ns := cr.Spec.ForProvider.Credential.AccessSecretRef.Namespace; ns != "" {
+		return ns
+	}
+	return cr.GetNamespace()
+}
+
+// getSecretFromRef reads cr's access secret from the Secret it references.
+func (c *external) getSecretFromRef(ctx context.Context, cr *v1beta1.Registry) (string, error) {
+	ref := cr.Spec.ForProvider.Credential.AccessSecretRef
+	nn := types.NamespacedName{Namespace: accessSecretNamespace(cr), Name: ref.Name}
+
+	secret := &corev1.Secret{}
+	if err := c.kube.Get(ctx, nn, secret); err != nil {
+		return "", errors.Wrapf(err, "cannot get Secret %s", nn)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", errors.Errorf("secret key %q not found in secret %s", ref.Key, nn)
+	}
+	return string(value), nil
+}
+
+// credentialHash returns the hash of the access secret in cred recorded in
+// cr's status, or nil when there is none. The hash is salted with cr's UID so
+// that equal secrets of different registries are not recognisable.
+func credentialHash(cr *v1beta1.Registry, cred *harborclients.RegistryCredential) *string {
+	if cred == nil || cred.AccessSecret == "" {
+		return nil
+	}
+	h := sha256.Sum256([]byte(string(cr.GetUID()) + ":" + cred.AccessSecret))
+	s := hex.EncodeToString(h[:])
+	return &s
+}
+
+// accessSecretUpToDate reports whether Harbor has the access secret cr's
+// Secret holds now. Harbor never returns the secret, so this compares its
+// hash with the one recorded when the registry was last saved.
+func (c *external) accessSecretUpToDate(ctx context.Context, cr *v1beta1.Registry) (bool, error) {
+	if cr.Spec.ForProvider.Credential == nil || cr.Spec.ForProvider.Credential.AccessSecretRef == nil {
+		cr.Status.AtProvider.CredentialHash = nil
+		return true, nil
+	}
+	secret, err := c.getSecretFromRef(ctx, cr)
+	if err != nil {
+		return false, errors.Wrap(err, errAccessSecret)
+	}
+	want := credentialHash(cr, &harborclients.RegistryCredential{AccessSecret: secret})
+	got := cr.Status.AtProvider.CredentialHash
+	if want == nil || got == nil {
+		return want == got, nil
+	}
+	return *want == *got, nil
+}
+
+// enqueueAccessSecretReferrers returns an event handler for Secrets that
+// enqueues the Registries whose access secret the changed Secret holds, so
+// a rotated secret is saved in Harbor without waiting for the next poll.
+func enqueueAccessSecretReferrers(kube client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		l := &v1beta1.RegistryList{}
+		if err := kube.List(ctx, l); err != nil {
+			return nil
+		}
+		var reqs []reconcile.Request
+		for i := range l.Items {
+			cr := &l.Items[i]
+			cred := cr.Spec.ForProvider.Credential
+			if cred == nil || cred.AccessSecretRef == nil {
+				continue
+			}
+			if cred.AccessSecretRef.Name != o.GetName() || accessSecretNamespace(cr) != o.GetNamespace() {
+				continue
+			}
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName()}})
+		}
+		return reqs
+	})
+}
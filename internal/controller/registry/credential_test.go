@@ -0,0 +1,155 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package registry
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func credentialScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func secretRegistry(name, namespace, secretNamespace string) *v1beta1.Registry {
+	accessKey := "robot"
+	return &v1beta1.Registry{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name + "-uid")},
+		Spec: v1beta1.RegistrySpec{
+			ForProvider: v1beta1.RegistryParameters{
+				Name: name,
+				Type: "docker-hub",
+				URL:  "https://docker.io",
+				Credential: &v1beta1.RegistryCredential{
+					AccessKey: &accessKey,
+					AccessSecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "hub-token", Namespace: secretNamespace},
+						Key:             "token",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestObserveRotatedAccessSecret(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "hub-token", Namespace: "team-a"},
+		Data:       map[string][]byte{"token": []byte("first")},
+	}
+	kube := fake.NewClientBuilder().WithScheme(credentialScheme(t)).WithObjects(secret).Build()
+
+	var saved []string
+	ext := &external{
+		kube: kube,
+		service: &mockRegistryClient{
+			getRegistryFunc: func(ctx context.Context, registryName string) (*harborclients.RegistryStatus, error) {
+				return &harborclients.RegistryStatus{
+					ID:         3,
+					Name:       registryName,
+					Type:       "docker-hub",
+					URL:        "https://docker.io",
+					Credential: &harborclients.RegistryCredential{AccessKey: "robot"},
+				}, nil
+			},
+			updateRegistryFunc: func(ctx context.Context, registryName string, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error) {
+				saved = append(saved, spec.Credential.AccessSecret)
+				return &harborclients.RegistryStatus{ID: 3, Name: registryName}, nil
+			},
+		},
+	}
+	cr := secretRegistry("hub", "team-a", "")
+
+	observeUpToDate := func() bool {
+		t.Helper()
+		obs, err := ext.Observe(ctx, cr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return obs.ResourceUpToDate
+	}
+
+	// A registry saved before its secret was hashed is saved again.
+	if observeUpToDate() {
+		t.Error("Observe() reported a registry without a credential hash up to date")
+	}
+	if _, err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if !observeUpToDate() {
+		t.Error("Observe() reported a registry with the current secret out of date")
+	}
+
+	secret.Data["token"] = []byte("second")
+	if err := kube.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if observeUpToDate() {
+		t.Error("Observe() reported a registry with a rotated secret up to date")
+	}
+	if _, err := ext.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"first", "second"}; !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved access secrets %v, want %v", saved, want)
+	}
+
+	if err := kube.Delete(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ext.Observe(ctx, cr); err == nil {
+		t.Error("Observe() should fail when the access secret's Secret is missing")
+	}
+}
+
+func TestEnqueueAccessSecretReferrers(t *testing.T) {
+	kube := fake.NewClientBuilder().WithScheme(credentialScheme(t)).WithObjects(
+		secretRegistry("local", "shared", ""),
+		secretRegistry("remote", "team-a", "shared"),
+		secretRegistry("other", "team-b", ""),
+	).Build()
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "hub-token", Namespace: "shared"}}
+	enqueueAccessSecretReferrers(kube).Update(context.Background(), event.TypedUpdateEvent[client.Object]{ObjectOld: secret, ObjectNew: secret}, q)
+
+	got := map[types.NamespacedName]bool{}
+	for q.Len() > 0 {
+		r, _ := q.Get()
+		got[r.NamespacedName] = true
+		q.Done(r)
+	}
+	want := map[types.NamespacedName]bool{
+		{Namespace: "shared", Name: "local"}:  true,
+		{Namespace: "team-a", Name: "remote"}: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enqueued %v, want %v", got, want)
+	}
+}
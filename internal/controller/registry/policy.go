@@ -0,0 +1,88 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package registry
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	errEndpointNotAllowed = "registry URL %s is not allowed by ProviderConfig %s"
+	errRegistryURLPattern = "cannot match registry URL against pattern %q"
+)
+
+// endpointAllowed reports whether rawURL, or one of its parent paths,
+// matches one of patterns. Scheme and host are compared case-insensitively,
+// and queries, fragments and trailing slashes are ignored. Every URL is
+// allowed when there are no patterns.
+func endpointAllowed(patterns []string, rawURL string) (bool, error) {
+	if len(patterns) == 0 {
+		return true, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.User != nil {
+		return false, nil
+	}
+
+	p := strings.TrimSuffix(path.Clean("/"+u.Path), "/")
+	for {
+		candidate := strings.ToLower(u.Scheme+"://"+u.Host) + p
+		for _, pattern := range patterns {
+			ok, err := path.Match(normalizePattern(pattern), candidate)
+			if err != nil {
+				return false, errors.Wrapf(err, errRegistryURLPattern, pattern)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		if p == "" {
+			return false, nil
+		}
+		p = p[:strings.LastIndex(p, "/")]
+	}
+}
+
+// normalizePattern lowercases the scheme and host of a pattern and drops a
+// trailing slash, so that it compares with the candidates of endpointAllowed.
+func normalizePattern(pattern string) string {
+	pattern = strings.TrimSuffix(pattern, "/")
+	scheme, rest, ok := strings.Cut(pattern, "://")
+	if !ok {
+		return pattern
+	}
+	host, p, _ := strings.Cut(rest, "/")
+	if p != "" {
+		p = "/" + p
+	}
+	return strings.ToLower(scheme+"://"+host) + p
+}
+
+// checkEndpoint fails, and sets the EndpointPermitted condition, when the
+// URL of cr is not allowed by the policy of its ProviderConfig.
+func (c *external) checkEndpoint(cr *v1beta1.Registry) error {
+	if c.allowedURLs == nil {
+		return nil
+	}
+	ok, err := endpointAllowed(c.allowedURLs, cr.Spec.ForProvider.URL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		pc := cr.GetProviderConfigReference().Name
+		cr.SetConditions(v1beta1.EndpointNotAllowed(cr.Spec.ForProvider.URL, pc))
+		return errors.Errorf(errEndpointNotAllowed, cr.Spec.ForProvider.URL, pc)
+	}
+	if cr.GetCondition(v1beta1.TypeEndpointPermitted).Status == corev1.ConditionFalse {
+		cr.SetConditions(v1beta1.EndpointAllowed())
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEndpointAllowed(t *testing.T) {
+	patterns := []string{"https://registry.example.com", "https://*.mirror.example.com/", "https://ghcr.io/acme"}
+	cases := map[string]bool{
+		"https://registry.example.com":              true,
+		"https://Registry.Example.com/v2/":          true,
+		"https://eu.mirror.example.com":             true,
+		"https://ghcr.io/acme/images":               true,
+		"https://ghcr.io/other":                     false,
+		"https://ghcr.io/acme-evil":                 false,
+		"http://registry.example.com":               false,
+		"https://registry.example.com.evil.io":      false,
+		"https://evil.io/registry.example.com":      false,
+		"https://user@registry.example.com":         false,
+		"registry.example.com":                      false,
+		"https://a.b.mirror.example.com/nested/dir": true,
+	}
+	for u, want := range cases {
+		got, err := endpointAllowed(patterns, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("endpointAllowed(%q) = %t, want %t", u, got, want)
+		}
+	}
+
+	if ok, _ := endpointAllowed(nil, "https://anything.io"); !ok {
+		t.Error("every URL should be allowed without patterns")
+	}
+	if _, err := endpointAllowed([]string{"https://[bad"}, "https://x.io"); err == nil {
+		t.Error("a malformed pattern should be reported")
+	}
+}
+
+func TestRegistryEndpointPolicy(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	if err := apisv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(&apisv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "guarded"},
+		Spec: apisv1beta1.ProviderConfigSpec{
+			Policy: &apisv1beta1.ProviderConfigPolicy{AllowedRegistryURLPatterns: []string{"https://registry.example.com"}},
+		},
+	}).Build()
+
+	created := false
+	conn := &connector{
+		kube: kube,
+		newServiceFn: func(context.Context, client.Client, resource.Managed) (harborclients.HarborClienter, error) {
+			return &mockRegistryClient{
+				createRegistryFunc: func(_ context.Context, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error) {
+					created = true
+					return &harborclients.RegistryStatus{Name: spec.Name}, nil
+				},
+			}, nil
+		},
+	}
+	cr := &v1beta1.Registry{Spec: v1beta1.RegistrySpec{ForProvider: v1beta1.RegistryParameters{
+		Name: "exfil", Type: "docker-registry", URL: "https://attacker.io",
+	}}}
+	cr.SetProviderConfigReference(&xpv1.ProviderConfigReference{Name: "guarded"})
+
+	ext, err := conn.Connect(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ext.Create(ctx, cr); err == nil || created {
+		t.Fatal("Create() should refuse a URL the ProviderConfig does not allow")
+	}
+	if c := cr.GetCondition(v1beta1.TypeEndpointPermitted); c.Status != corev1.ConditionFalse || c.Reason != v1beta1.ReasonEndpointNotAllowed {
+		t.Errorf("EndpointPermitted = %+v, want false", c)
+	}
+	if _, err := ext.Update(ctx, cr); err == nil {
+		t.Error("Update() should refuse a URL the ProviderConfig does not allow")
+	}
+
+	cr.Spec.ForProvider.URL = "https://registry.example.com"
+	if _, err := ext.Create(ctx, cr); err != nil || !created {
+		t.Fatalf("Create() of an allowed URL = %v", err)
+	}
+	if c := cr.GetCondition(v1beta1.TypeEndpointPermitted); c.Status != corev1.ConditionTrue {
+		t.Errorf("EndpointPermitted = %+v, want true", c)
+	}
+}
@@ -6,6 +6,7 @@ package registry
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
@@ -14,13 +15,18 @@ import (
 	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
 	"github.com/pkg/errors"
 	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
 	harborclients "github.com/rossigee/provider-harbor/internal/clients"
 	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
 	"github.com/rossigee/provider-harbor/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net/url"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"strings"
 	"time"
 )
 
@@ -34,28 +40,41 @@ const (
 	errRegistryGet    = "cannot get Harbor registry"
 	errRegistryUpdate = "cannot update Harbor registry"
 	errRegistryDelete = "cannot delete Harbor registry"
+	errAccessSecret   = "cannot get access secret"
+)
+
+const (
+	// registryPingInterval is how often Harbor is asked to ping a registry.
+	registryPingInterval = 5 * time.Minute
+
+	// registryPingTimeout bounds a ping of an unreachable registry.
+	registryPingTimeout = 10 * time.Second
 )
 
 // Setup adds a controller that reconciles Registry managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.RegistryGroupVersionKind.Kind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.RegistryGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), harborclients.CapabilityManageSystem, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithUpdateDebounce(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
-		}),
+		})))))))))),
 		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
-		managed.WithPollInterval(1*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithPollIntervalHook(ctrlutil.DebouncedPollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.RegistryGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1beta1.Registry{}).
-		Complete(ratelimiter.NewReconciler(name, r, nil))
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.Registry{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		WatchesMetadata(&corev1.Secret{}, enqueueAccessSecretReferrers(mgr.GetClient())).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
@@ -71,7 +90,7 @@ type connector struct {
 // 3. Getting the credentials specified by the ProviderConfig.
 // 4. Using the credentials to form a client.
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	_, ok := mg.(*v1beta1.Registry)
+	cr, ok := mg.(*v1beta1.Registry)
 	if !ok {
 		return nil, errors.New(errNotRegistry)
 	}
@@ -81,7 +100,17 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{service: svc, kube: c.kube}, nil
+	ext := &external{service: svc, kube: c.kube}
+	if ref := cr.GetProviderConfigReference(); ref != nil {
+		pc := &apisv1beta1.ProviderConfig{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+			return nil, errors.Wrap(err, errGetPC)
+		}
+		if pc.Spec.Policy != nil && len(pc.Spec.Policy.AllowedRegistryURLPatterns) > 0 {
+			ext.allowedURLs = pc.Spec.Policy.AllowedRegistryURLPatterns
+		}
+	}
+	return ext, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -89,6 +118,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 type external struct {
 	service harborclients.HarborClienter
 	kube    client.Client
+
+	// allowedURLs are the allowed registry URL patterns of the
+	// ProviderConfig, or nil if every URL is allowed.
+	allowedURLs []string
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -101,48 +134,63 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotRegistry)
 	}
 
-	// Check if the registry exists in Harbor using external name if set
-	externalName := ctrlutil.GetExternalName(cr)
-	registryName := cr.Spec.ForProvider.Name
-	if externalName != "" {
-		// Adoption scenario: use external name to find existing resource
-		registryName = externalName
+	registry, err := c.service.GetRegistry(ctx, registryName(cr))
+	if harborclients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
-
-	registry, err := c.service.GetRegistry(ctx, registryName)
 	if err != nil {
-		// If registry doesn't exist, we need to create it
-		return managed.ExternalObservation{
-			ResourceExists: false,
-		}, nil
+		return managed.ExternalObservation{}, errors.Wrap(err, errRegistryGet)
 	}
 
 	// Set external name for adoption tracking
 	ctrlutil.SetExternalName(cr, registry.Name)
 
-	// Update status with observed state
-	cr.Status.AtProvider.ID = getInt64Ptr(1) // Mock ID for now
+	setObservation(cr, registry)
+	c.ping(ctx, cr, registry.ID, time.Now())
+
+	secretUpToDate, err := c.accessSecretUpToDate(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(cr.Spec.ForProvider, registry) && secretUpToDate,
+	}, nil
+}
+
+// setObservation records the registry Harbor reports in cr's status.
+func setObservation(cr *v1beta1.Registry, registry *harborclients.RegistryStatus) {
+	cr.Status.AtProvider.ID = &registry.ID
 	if registry.CreatedAt != (time.Time{}) {
 		cr.Status.AtProvider.CreationTime = &metav1.Time{Time: registry.CreatedAt}
 	}
 	if registry.UpdatedAt != (time.Time{}) {
 		cr.Status.AtProvider.UpdateTime = &metav1.Time{Time: registry.UpdatedAt}
 	}
-	cr.Status.AtProvider.Status = getStringPtr("healthy") // Mock status
-
-	// Check if resource is up to date
-	upToDate := (cr.Spec.ForProvider.Description == nil || registry.Description == nil || *cr.Spec.ForProvider.Description == *registry.Description) &&
-		cr.Spec.ForProvider.URL == registry.URL &&
-		cr.Spec.ForProvider.Type == registry.Type
+	cr.Status.AtProvider.Status = nil
+	if registry.Status != "" {
+		cr.Status.AtProvider.Status = &registry.Status
+	}
+}
 
-	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: upToDate,
-		ConnectionDetails: managed.ConnectionDetails{
-			"registry_name": []byte(registry.Name),
-			"registry_id":   []byte("1"), // Mock ID
-		},
-	}, nil
+// ping checks that Harbor can reach the registry and records the result,
+// at most once per registryPingInterval so that rate limited registries
+// such as Docker Hub are not pinged on every poll. A failed ping is not a
+// reconcile error: the registry exists whether or not it is reachable.
+func (c *external) ping(ctx context.Context, cr *v1beta1.Registry, id int64, now time.Time) {
+	last := cr.Status.AtProvider.LastPing
+	if last != nil && now.Sub(last.Time.Time) < registryPingInterval {
+		return
+	}
+	pctx, cancel := context.WithTimeout(ctx, registryPingTimeout)
+	defer cancel()
+	result := &v1beta1.RegistryPingResult{Time: metav1.NewTime(now), Succeeded: true}
+	if err := c.service.PingRegistry(pctx, id); err != nil {
+		result.Succeeded = false
+		result.Message = err.Error()
+	}
+	cr.Status.AtProvider.LastPing = result
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -157,38 +205,13 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	cr.SetConditions(xpv1.Creating())
 
-	// Prepare registry spec
-	spec := &harborclients.RegistrySpec{
-		Name: cr.Spec.ForProvider.Name,
-		Type: cr.Spec.ForProvider.Type,
-		URL:  cr.Spec.ForProvider.URL,
+	if err := c.checkEndpoint(cr); err != nil {
+		return managed.ExternalCreation{}, err
 	}
 
-	if cr.Spec.ForProvider.Description != nil {
-		spec.Description = cr.Spec.ForProvider.Description
-	}
-	if cr.Spec.ForProvider.Insecure != nil {
-		spec.Insecure = *cr.Spec.ForProvider.Insecure
-	}
-
-	// Handle credentials if provided
-	if cr.Spec.ForProvider.Credential != nil {
-		cred := &harborclients.RegistryCredential{}
-		if cr.Spec.ForProvider.Credential.Type != nil {
-			cred.Type = *cr.Spec.ForProvider.Credential.Type
-		}
-		if cr.Spec.ForProvider.Credential.AccessKey != nil {
-			cred.AccessKey = *cr.Spec.ForProvider.Credential.AccessKey
-		}
-		// Handle secret reference
-		if cr.Spec.ForProvider.Credential.AccessSecretRef != nil {
-			secret, err := c.getSecretFromRef(ctx, cr)
-			if err != nil {
-				return managed.ExternalCreation{}, errors.Wrap(err, "cannot get access secret")
-			}
-			cred.AccessSecret = secret
-		}
-		spec.Credential = cred
+	spec, err := c.registrySpec(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
 	}
 
 	// Create registry in Harbor
@@ -200,18 +223,10 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	// Set external name for adoption tracking
 	ctrlutil.SetExternalName(cr, status.Name)
 
-	// Update status with created resource info
-	cr.Status.AtProvider.ID = getInt64Ptr(1) // Mock ID
-	if status.CreatedAt != (time.Time{}) {
-		cr.Status.AtProvider.CreationTime = &metav1.Time{Time: status.CreatedAt}
-	}
+	setObservation(cr, status)
+	cr.Status.AtProvider.CredentialHash = credentialHash(cr, spec.Credential)
 
-	return managed.ExternalCreation{
-		ConnectionDetails: managed.ConnectionDetails{
-			"registry_name": []byte(status.Name),
-			"registry_id":   []byte("1"), // Mock ID
-		},
-	}, nil
+	return managed.ExternalCreation{}, nil
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -224,56 +239,24 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotRegistry)
 	}
 
-	// Prepare updated registry spec
-	spec := &harborclients.RegistrySpec{
-		Name: cr.Spec.ForProvider.Name,
-		Type: cr.Spec.ForProvider.Type,
-		URL:  cr.Spec.ForProvider.URL,
+	if err := c.checkEndpoint(cr); err != nil {
+		return managed.ExternalUpdate{}, err
 	}
 
-	if cr.Spec.ForProvider.Description != nil {
-		spec.Description = cr.Spec.ForProvider.Description
-	}
-	if cr.Spec.ForProvider.Insecure != nil {
-		spec.Insecure = *cr.Spec.ForProvider.Insecure
-	}
-
-	// Handle credentials if provided
-	if cr.Spec.ForProvider.Credential != nil {
-		cred := &harborclients.RegistryCredential{}
-		if cr.Spec.ForProvider.Credential.Type != nil {
-			cred.Type = *cr.Spec.ForProvider.Credential.Type
-		}
-		if cr.Spec.ForProvider.Credential.AccessKey != nil {
-			cred.AccessKey = *cr.Spec.ForProvider.Credential.AccessKey
-		}
-		if cr.Spec.ForProvider.Credential.AccessSecretRef != nil {
-			secret, err := c.getSecretFromRef(ctx, cr)
-			if err != nil {
-				return managed.ExternalUpdate{}, errors.Wrap(err, "cannot get access secret")
-			}
-			cred.AccessSecret = secret
-		}
-		spec.Credential = cred
+	spec, err := c.registrySpec(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
 	}
 
 	// Update registry in Harbor
-	status, err := c.service.UpdateRegistry(ctx, cr.Spec.ForProvider.Name, spec)
+	status, err := c.service.UpdateRegistry(ctx, registryName(cr), spec)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errRegistryUpdate)
 	}
+	setObservation(cr, status)
+	cr.Status.AtProvider.CredentialHash = credentialHash(cr, spec.Credential)
 
-	// Update status
-	if status.CreatedAt != (time.Time{}) {
-		cr.Status.AtProvider.UpdateTime = &metav1.Time{Time: time.Now()}
-	}
-
-	return managed.ExternalUpdate{
-		ConnectionDetails: managed.ConnectionDetails{
-			"registry_name": []byte(status.Name),
-			"registry_id":   []byte("1"), // Mock ID
-		},
-	}, nil
+	return managed.ExternalUpdate{}, nil
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -289,7 +272,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	cr.SetConditions(xpv1.Deleting())
 
 	// Delete registry from Harbor
-	err := c.service.DeleteRegistry(ctx, cr.Spec.ForProvider.Name)
+	err := c.service.DeleteRegistry(ctx, registryName(cr))
 	if err != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, errRegistryDelete)
 	}
@@ -302,18 +285,60 @@ func (c *external) Disconnect(ctx context.Context) error {
 	return nil
 }
 
-// Helper function to get secret from secret reference
-func (c *external) getSecretFromRef(ctx context.Context, cr *v1beta1.Registry) (string, error) {
-	// This would need to be implemented to read from Kubernetes secret
-	// For now, return a placeholder
-	return "mock-secret", nil
+// registryName returns the name of the Harbor registry cr manages: its
+// external name when it has adopted one, otherwise spec.forProvider.name.
+func registryName(cr *v1beta1.Registry) string {
+	if name := ctrlutil.GetExternalName(cr); name != "" {
+		return name
+	}
+	return cr.Spec.ForProvider.Name
 }
 
-// Helper functions
-func getInt64Ptr(i int64) *int64 {
-	return &i
+// insecureField is the registry's insecure flag, which Harbor leaves out of
+// its responses when it is false.
+var insecureField = ctrlutil.BoolField{}
+
+// normalizeURL lowercases the scheme and host of a registry URL and drops
+// trailing slashes from its path, so that https://Registry.example.com/ and
+// https://registry.example.com are the same endpoint. Anything that does not
+// parse as an absolute URL only loses its trailing slashes.
+func normalizeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return strings.TrimRight(raw, "/")
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+	return u.String()
 }
 
-func getStringPtr(s string) *string {
-	return &s
+// isUpToDate reports whether the observed registry matches every mutable
+// field of p. Optional fields that are unset are not managed. Harbor never
+// returns the access secret, so accessSecretUpToDate checks it instead.
+func isUpToDate(p v1beta1.RegistryParameters, observed *harborclients.RegistryStatus) bool {
+	if p.Description != nil && observed.Description != nil && *p.Description != *observed.Description {
+		return false
+	}
+	if normalizeURL(p.URL) != normalizeURL(observed.URL) || p.Type != observed.Type {
+		return false
+	}
+	if !insecureField.Matches(p.Insecure, &observed.Insecure) {
+		return false
+	}
+	if p.Credential == nil {
+		return true
+	}
+	if observed.Credential == nil {
+		return p.Credential.Type == nil && p.Credential.AccessKey == nil && p.Credential.AccessSecretRef == nil
+	}
+	if p.Credential.Type != nil && *p.Credential.Type != observed.Credential.Type {
+		return false
+	}
+	if p.Credential.AccessKey != nil && *p.Credential.AccessKey != observed.Credential.AccessKey {
+		return false
+	}
+	return true
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	sdkregistry "github.com/goharbor/go-client/pkg/sdk/v2.0/client/registry"
 	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
 	harborclients "github.com/rossigee/provider-harbor/internal/clients"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -84,7 +85,7 @@ func TestObserveRegistryNotFound(t *testing.T) {
 	ext := &external{
 		service: &mockRegistryClient{
 			getRegistryFunc: func(ctx context.Context, registryName string) (*harborclients.RegistryStatus, error) {
-				return nil, errors.New("not found")
+				return nil, sdkregistry.NewGetRegistryNotFound()
 			},
 		},
 	}
@@ -342,20 +343,6 @@ func TestDeleteRegistryError(t *testing.T) {
 	}
 }
 
-func TestHelperFunctions(t *testing.T) {
-	intVal := int64(42)
-	result := getInt64Ptr(intVal)
-	if result == nil || *result != intVal {
-		t.Errorf("getInt64Ptr failed")
-	}
-
-	strVal := "test"
-	resultStr := getStringPtr(strVal)
-	if resultStr == nil || *resultStr != strVal {
-		t.Errorf("getStringPtr failed")
-	}
-}
-
 func TestRegistryHasRequiredFields(t *testing.T) {
 	registry := &v1beta1.Registry{
 		ObjectMeta: metav1.ObjectMeta{
@@ -652,30 +639,6 @@ func TestObserveRegistryWithNilDescription(t *testing.T) {
 	}
 }
 
-func TestGetInt64PtrHelper(t *testing.T) {
-	result := getInt64Ptr(1000)
-	if result == nil || *result != 1000 {
-		t.Error("getInt64Ptr should work correctly")
-	}
-
-	resultZero := getInt64Ptr(0)
-	if resultZero == nil || *resultZero != 0 {
-		t.Error("getInt64Ptr with 0 should work correctly")
-	}
-}
-
-func TestGetStringPtrHelper(t *testing.T) {
-	result := getStringPtr("test-value")
-	if result == nil || *result != "test-value" {
-		t.Error("getStringPtr should work correctly")
-	}
-
-	resultEmpty := getStringPtr("")
-	if resultEmpty == nil || *resultEmpty != "" {
-		t.Error("getStringPtr with empty should work correctly")
-	}
-}
-
 func TestCreateRegistryWithInsecureFlag(t *testing.T) {
 	ctx := context.Background()
 	insecure := true
@@ -728,17 +691,24 @@ func TestObserveRegistryStatusPopulation(t *testing.T) {
 		},
 	}
 
+	var pinged []int64
 	ext := &external{
 		service: &mockRegistryClient{
 			getRegistryFunc: func(ctx context.Context, registryName string) (*harborclients.RegistryStatus, error) {
 				return &harborclients.RegistryStatus{
+					ID:        7,
 					Name:      "docker-hub",
 					Type:      "docker-hub",
 					URL:       "https://docker.io",
+					Status:    "unhealthy",
 					CreatedAt: time.Now().Add(-24 * time.Hour),
 					UpdatedAt: time.Now(),
 				}, nil
 			},
+			pingRegistryFunc: func(ctx context.Context, id int64) error {
+				pinged = append(pinged, id)
+				return errors.New("connection refused")
+			},
 		},
 	}
 
@@ -746,98 +716,48 @@ func TestObserveRegistryStatusPopulation(t *testing.T) {
 	if err != nil {
 		t.Errorf("Observe should not fail, got %v", err)
 	}
-	if obs.ConnectionDetails == nil {
-		t.Error("ConnectionDetails should be populated")
+	if len(obs.ConnectionDetails) != 0 {
+		t.Errorf("ConnectionDetails = %v, want none", obs.ConnectionDetails)
 	}
-}
-
-func TestUpdateRegistryWithNilCredential(t *testing.T) {
-	ctx := context.Background()
-	registry := &v1beta1.Registry{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "test-registry",
-		},
-		Spec: v1beta1.RegistrySpec{
-			ForProvider: v1beta1.RegistryParameters{
-				Name:       "docker-hub",
-				Type:       "docker-hub",
-				URL:        "https://docker.io",
-				Credential: nil,
-			},
-		},
+	at := registry.Status.AtProvider
+	if at.ID == nil || *at.ID != 7 {
+		t.Errorf("ID = %v, want 7", at.ID)
 	}
-
-	ext := &external{
-		service: &mockRegistryClient{
-			updateRegistryFunc: func(ctx context.Context, registryName string, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error) {
-				return &harborclients.RegistryStatus{
-					Name:      spec.Name,
-					Type:      spec.Type,
-					URL:       spec.URL,
-					UpdatedAt: time.Now(),
-				}, nil
-			},
-		},
+	if at.Status == nil || *at.Status != "unhealthy" {
+		t.Errorf("Status = %v, want unhealthy", at.Status)
 	}
-
-	_, err := ext.Update(ctx, registry)
-	if err != nil {
-		t.Errorf("Update with nil credential should not fail, got %v", err)
+	if at.LastPing == nil || at.LastPing.Succeeded || at.LastPing.Message == "" {
+		t.Errorf("LastPing = %+v, want a failed ping", at.LastPing)
 	}
-}
 
-func TestDisconnectRegistry(t *testing.T) {
-	ctx := context.Background()
-	ext := &external{
-		service: &mockRegistryClient{},
+	// A recent ping is not repeated on the next poll.
+	if _, err := ext.Observe(ctx, registry); err != nil {
+		t.Fatal(err)
 	}
-
-	err := ext.Disconnect(ctx)
-	if err != nil {
-		t.Errorf("Disconnect should not fail, got %v", err)
+	if len(pinged) != 1 || pinged[0] != 7 {
+		t.Errorf("pinged %v, want registry 7 once", pinged)
 	}
 }
 
-func TestObserveRegistryConnectionDetails(t *testing.T) {
-	ctx := context.Background()
+func TestObserveRegistryGetError(t *testing.T) {
 	registry := &v1beta1.Registry{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "test-registry",
-		},
 		Spec: v1beta1.RegistrySpec{
-			ForProvider: v1beta1.RegistryParameters{
-				Name: "docker-hub",
-				Type: "docker-hub",
-				URL:  "https://docker.io",
-			},
+			ForProvider: v1beta1.RegistryParameters{Name: "docker-hub", Type: "docker-hub", URL: "https://docker.io"},
 		},
 	}
-
 	ext := &external{
 		service: &mockRegistryClient{
 			getRegistryFunc: func(ctx context.Context, registryName string) (*harborclients.RegistryStatus, error) {
-				return &harborclients.RegistryStatus{
-					Name:      "docker-hub",
-					Type:      "docker-hub",
-					URL:       "https://docker.io",
-					CreatedAt: time.Now(),
-					UpdatedAt: time.Now(),
-				}, nil
+				return nil, errors.New("harbor is down")
 			},
 		},
 	}
-
-	obs, err := ext.Observe(ctx, registry)
-	if err != nil {
-		t.Errorf("Observe should not fail, got %v", err)
-	}
-
-	if obs.ConnectionDetails == nil {
-		t.Error("ConnectionDetails should not be nil")
+	if _, err := ext.Observe(context.Background(), registry); err == nil {
+		t.Error("Observe should fail when Harbor cannot be asked for the registry, not report it missing")
 	}
 }
 
-func TestCreateRegistryConnectionDetails(t *testing.T) {
+func TestUpdateRegistryWithNilCredential(t *testing.T) {
 	ctx := context.Background()
 	registry := &v1beta1.Registry{
 		ObjectMeta: metav1.ObjectMeta{
@@ -845,71 +765,42 @@ func TestCreateRegistryConnectionDetails(t *testing.T) {
 		},
 		Spec: v1beta1.RegistrySpec{
 			ForProvider: v1beta1.RegistryParameters{
-				Name: "docker-hub",
-				Type: "docker-hub",
-				URL:  "https://docker.io",
+				Name:       "docker-hub",
+				Type:       "docker-hub",
+				URL:        "https://docker.io",
+				Credential: nil,
 			},
 		},
 	}
 
 	ext := &external{
 		service: &mockRegistryClient{
-			createRegistryFunc: func(ctx context.Context, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error) {
+			updateRegistryFunc: func(ctx context.Context, registryName string, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error) {
 				return &harborclients.RegistryStatus{
 					Name:      spec.Name,
 					Type:      spec.Type,
 					URL:       spec.URL,
-					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
 				}, nil
 			},
 		},
 	}
 
-	creation, err := ext.Create(ctx, registry)
+	_, err := ext.Update(ctx, registry)
 	if err != nil {
-		t.Errorf("Create should not fail, got %v", err)
-	}
-
-	if creation.ConnectionDetails == nil {
-		t.Error("ConnectionDetails should not be nil")
+		t.Errorf("Update with nil credential should not fail, got %v", err)
 	}
 }
 
-func TestUpdateRegistryConnectionDetails(t *testing.T) {
+func TestDisconnectRegistry(t *testing.T) {
 	ctx := context.Background()
-	registry := &v1beta1.Registry{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "test-registry",
-		},
-		Spec: v1beta1.RegistrySpec{
-			ForProvider: v1beta1.RegistryParameters{
-				Name: "docker-hub",
-				Type: "docker-hub",
-				URL:  "https://docker.io",
-			},
-		},
-	}
-
 	ext := &external{
-		service: &mockRegistryClient{
-			updateRegistryFunc: func(ctx context.Context, registryName string, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error) {
-				return &harborclients.RegistryStatus{
-					Name:      spec.Name,
-					Type:      spec.Type,
-					URL:       spec.URL,
-					UpdatedAt: time.Now(),
-				}, nil
-			},
-		},
+		service: &mockRegistryClient{},
 	}
 
-	update, err := ext.Update(ctx, registry)
+	err := ext.Disconnect(ctx)
 	if err != nil {
-		t.Errorf("Update should not fail, got %v", err)
-	}
-
-	if update.ConnectionDetails == nil {
-		t.Error("ConnectionDetails should not be nil")
+		t.Errorf("Disconnect should not fail, got %v", err)
 	}
 }
 
@@ -1055,6 +946,14 @@ type mockRegistryClient struct {
 	createRegistryFunc func(ctx context.Context, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error)
 	updateRegistryFunc func(ctx context.Context, registryName string, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error)
 	deleteRegistryFunc func(ctx context.Context, registryName string) error
+	pingRegistryFunc   func(ctx context.Context, id int64) error
+}
+
+func (m *mockRegistryClient) PingRegistry(ctx context.Context, id int64) error {
+	if m.pingRegistryFunc != nil {
+		return m.pingRegistryFunc(ctx, id)
+	}
+	return nil
 }
 
 func (m *mockRegistryClient) GetRegistry(ctx context.Context, registryName string) (*harborclients.RegistryStatus, error) {
@@ -1096,3 +995,149 @@ func (m *mockRegistryClient) GetBaseURL() string {
 func ptrInt64(i int64) *int64 {
 	return &i
 }
+
+func TestIsUpToDate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	boolean := func(b bool) *bool { return &b }
+
+	params := func() v1beta1.RegistryParameters {
+		return v1beta1.RegistryParameters{
+			Name:        "docker-hub",
+			Description: str("Docker Hub mirror"),
+			Type:        "docker-hub",
+			URL:         "https://docker.io",
+			Insecure:    boolean(false),
+			Credential: &v1beta1.RegistryCredential{
+				Type:      str("basic"),
+				AccessKey: str("robot"),
+			},
+		}
+	}
+	observed := func() *harborclients.RegistryStatus {
+		return &harborclients.RegistryStatus{
+			Name:        "docker-hub",
+			Description: str("Docker Hub mirror"),
+			Type:        "docker-hub",
+			URL:         "https://docker.io",
+			Credential:  &harborclients.RegistryCredential{Type: "basic", AccessKey: "robot"},
+		}
+	}
+
+	cases := map[string]struct {
+		params   func(p *v1beta1.RegistryParameters)
+		observed func(o *harborclients.RegistryStatus)
+		want     bool
+	}{
+		"Matching": {
+			want: true,
+		},
+		"DescriptionChanged": {
+			params: func(p *v1beta1.RegistryParameters) { p.Description = str("Other") },
+		},
+		"DescriptionUnset": {
+			params: func(p *v1beta1.RegistryParameters) { p.Description = nil },
+			want:   true,
+		},
+		"URLChanged": {
+			params: func(p *v1beta1.RegistryParameters) { p.URL = "https://registry-1.docker.io" },
+		},
+		"URLTrailingSlash": {
+			params: func(p *v1beta1.RegistryParameters) { p.URL = "https://docker.io/" },
+			want:   true,
+		},
+		"URLHostCase": {
+			params: func(p *v1beta1.RegistryParameters) { p.URL = "HTTPS://Docker.IO" },
+			want:   true,
+		},
+		"URLObservedTrailingSlash": {
+			observed: func(o *harborclients.RegistryStatus) { o.URL = "https://docker.io/" },
+			want:     true,
+		},
+		"URLPathCase": {
+			params:   func(p *v1beta1.RegistryParameters) { p.URL = "https://docker.io/Library" },
+			observed: func(o *harborclients.RegistryStatus) { o.URL = "https://docker.io/library" },
+		},
+		"TypeChanged": {
+			params: func(p *v1beta1.RegistryParameters) { p.Type = "harbor" },
+		},
+		"InsecureEnabled": {
+			params: func(p *v1beta1.RegistryParameters) { p.Insecure = boolean(true) },
+		},
+		"InsecureDisabled": {
+			observed: func(o *harborclients.RegistryStatus) { o.Insecure = true },
+		},
+		"InsecureUnset": {
+			params:   func(p *v1beta1.RegistryParameters) { p.Insecure = nil },
+			observed: func(o *harborclients.RegistryStatus) { o.Insecure = true },
+			want:     true,
+		},
+		"CredentialTypeChanged": {
+			params: func(p *v1beta1.RegistryParameters) { p.Credential.Type = str("oauth") },
+		},
+		"AccessKeyChanged": {
+			params: func(p *v1beta1.RegistryParameters) { p.Credential.AccessKey = str("other") },
+		},
+		"CredentialAdded": {
+			observed: func(o *harborclients.RegistryStatus) { o.Credential = nil },
+		},
+		"CredentialUnset": {
+			params: func(p *v1beta1.RegistryParameters) { p.Credential = nil },
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, o := params(), observed()
+			if tc.params != nil {
+				tc.params(&p)
+			}
+			if tc.observed != nil {
+				tc.observed(o)
+			}
+			if got := isUpToDate(p, o); got != tc.want {
+				t.Errorf("isUpToDate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	cases := map[string]string{
+		"https://registry.example.com":              "https://registry.example.com",
+		"https://Registry.Example.com/":             "https://registry.example.com",
+		"HTTPS://registry.example.com:5000//":       "https://registry.example.com:5000",
+		"https://registry.example.com/Team/Images/": "https://registry.example.com/Team/Images",
+		" https://registry.example.com/ ":           "https://registry.example.com",
+		"registry.example.com/":                     "registry.example.com",
+		"":                                          "",
+	}
+	for in, want := range cases {
+		if got := normalizeURL(in); got != want {
+			t.Errorf("normalizeURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsUpToDateInsecure(t *testing.T) {
+	yes, no := true, false
+	cases := map[string]struct {
+		insecure *bool
+		observed bool
+		want     bool
+	}{
+		"Unset":        {insecure: nil, observed: true, want: true},
+		"FalseOmitted": {insecure: &no, observed: false, want: true},
+		"TrueOmitted":  {insecure: &yes, observed: false, want: false},
+		"FalseTrue":    {insecure: &no, observed: true, want: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := v1beta1.RegistryParameters{Name: "hub", URL: "https://hub.docker.com", Type: "docker-hub", Insecure: tc.insecure}
+			observed := &harborclients.RegistryStatus{Name: "hub", URL: "https://hub.docker.com", Type: "docker-hub", Insecure: tc.observed}
+			if got := isUpToDate(p, observed); got != tc.want {
+				t.Errorf("isUpToDate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package registrymirrorset
+
+import (
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// Labels identifying the children of a RegistryMirrorSet.
+const (
+	LabelMirrorSet = "registry.harbor.m.crossplane.io/mirror-set"
+	LabelMirror    = "registry.harbor.m.crossplane.io/mirror"
+)
+
+// defaultURLs are the endpoints of the registries most often mirrored.
+var defaultURLs = map[string]string{
+	"docker-hub": "https://hub.docker.com",
+	"quay":       "https://quay.io",
+	"google-gcr": "https://gcr.io",
+}
+
+// childName is the name of both managed resources created for a mirror.
+func childName(cr *v1beta1.RegistryMirrorSet, m v1beta1.RegistryMirror) string {
+	return cr.GetName() + "-" + m.Name
+}
+
+// harborName is the name of both the registry endpoint and the proxy cache
+// project in Harbor.
+func harborName(cr *v1beta1.RegistryMirrorSet, m v1beta1.RegistryMirror) string {
+	prefix := ""
+	if cr.Spec.ForProvider.ProjectPrefix != nil {
+		prefix = *cr.Spec.ForProvider.ProjectPrefix
+	}
+	return prefix + m.Name
+}
+
+func mirrorURL(m v1beta1.RegistryMirror) (string, error) {
+	if m.URL != nil && *m.URL != "" {
+		return *m.URL, nil
+	}
+	if u, ok := defaultURLs[m.Type]; ok {
+		return u, nil
+	}
+	return "", errors.Errorf("url is required for registries of type %s", m.Type)
+}
+
+// adopt makes cr the controller of a child and points it at cr's
+// ProviderConfig. The child shares cr's management policies: children are
+// garbage collected with cr, so a set that is orphaned must orphan them too.
+func adopt(cr *v1beta1.RegistryMirrorSet, m v1beta1.RegistryMirror, child resource.Managed, pc **xpv1.ProviderConfigReference) {
+	meta.AddLabels(child, map[string]string{LabelMirrorSet: cr.GetName(), LabelMirror: m.Name})
+	ctrlutil.SetOwner(child, cr, v1beta1.RegistryMirrorSetGroupVersionKind)
+	if p := cr.GetManagementPolicies(); len(p) > 0 {
+		child.SetManagementPolicies(append(xpv1.ManagementPolicies{}, p...))
+	}
+	if cr.GetProviderConfigReference() != nil {
+		*pc = cr.GetProviderConfigReference().DeepCopy()
+	}
+}
+
+// mutateRegistry sets the fields of r that the mirror owns, leaving those
+// defaulted by the API server alone.
+func mutateRegistry(cr *v1beta1.RegistryMirrorSet, m v1beta1.RegistryMirror, r *v1beta1.Registry) error {
+	url, err := mirrorURL(m)
+	if err != nil {
+		return err
+	}
+	adopt(cr, m, r, &r.Spec.ProviderConfigReference)
+
+	p := &r.Spec.ForProvider
+	p.Name = harborName(cr, m)
+	p.Type = m.Type
+	p.URL = url
+	p.Description = ptr(fmt.Sprintf("Upstream of RegistryMirrorSet %s/%s", cr.GetNamespace(), cr.GetName()))
+	if m.Insecure != nil {
+		p.Insecure = ptr(*m.Insecure)
+	}
+	p.Credential = nil
+	if m.Credential != nil {
+		p.Credential = m.Credential.DeepCopy()
+	}
+	return nil
+}
+
+// mutateProject sets the fields of p that the mirror owns. registryID is
+// the ID Harbor gave the mirror's registry endpoint.
+func mutateProject(cr *v1beta1.RegistryMirrorSet, m v1beta1.RegistryMirror, p *projectv1beta1.Project, registryID int64) {
+	adopt(cr, m, p, &p.Spec.ProviderConfigReference)
+
+	fp := &p.Spec.ForProvider
+	fp.Name = harborName(cr, m)
+	fp.RegistryID = ptr(registryID)
+	if cr.Spec.ForProvider.Public != nil {
+		fp.Public = ptr(*cr.Spec.ForProvider.Public)
+	}
+	fp.StorageLimit = nil
+	switch {
+	case m.StorageLimit != nil:
+		fp.StorageLimit = ptr(*m.StorageLimit)
+	case cr.Spec.ForProvider.StorageLimit != nil:
+		fp.StorageLimit = ptr(*cr.Spec.ForProvider.StorageLimit)
+	}
+}
+
+// registryUpToDate reports whether r already matches the mirror.
+func registryUpToDate(cr *v1beta1.RegistryMirrorSet, m v1beta1.RegistryMirror, r *v1beta1.Registry) bool {
+	want := r.DeepCopy()
+	if err := mutateRegistry(cr, m, want); err != nil {
+		return false
+	}
+	return equality.Semantic.DeepEqual(r, want)
+}
+
+// projectUpToDate reports whether p already matches the mirror.
+func projectUpToDate(cr *v1beta1.RegistryMirrorSet, m v1beta1.RegistryMirror, p *projectv1beta1.Project, registryID int64) bool {
+	want := p.DeepCopy()
+	mutateProject(cr, m, want, registryID)
+	return equality.Semantic.DeepEqual(p, want)
+}
+
+// ready reports whether a child managed resource is ready.
+func ready(c interface {
+	GetCondition(xpv1.ConditionType) xpv1.Condition
+}) bool {
+	return c.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+}
+
+func ptr[T any](v T) *T { return &v }
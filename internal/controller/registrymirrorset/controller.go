@@ -0,0 +1,321 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package registrymirrorset sets Harbor up as a pull-through cache for a
+// list of upstream registries.
+package registrymirrorset
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	errNotRegistryMirrorSet = "managed resource is not a RegistryMirrorSet custom resource"
+	errGetRegistry          = "cannot get Registry of mirror %s"
+	errGetProject           = "cannot get Project of mirror %s"
+	errApplyRegistry        = "cannot apply Registry of mirror %s"
+	errApplyProject         = "cannot apply Project of mirror %s"
+	errListChildren         = "cannot list children of RegistryMirrorSet"
+	errDeleteChild          = "cannot delete %s %s"
+)
+
+// Setup adds a controller that reconciles RegistryMirrorSet managed resources
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.RegistryMirrorSetGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:   mgr.GetClient(),
+			logger: log,
+		})))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.RegistryMirrorSetGroupVersionKind), opts...)
+
+	// The children are watched so that a project is created as soon as its
+	// registry has an ID, and readiness is reported as it changes.
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.RegistryMirrorSet{}).
+		Owns(&v1beta1.Registry{}).
+		Owns(&projectv1beta1.Project{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// Connect produces an ExternalClient. A RegistryMirrorSet only manages
+// other managed resources, so it needs no Harbor client of its own.
+func (c *connector) Connect(_ context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.RegistryMirrorSet); !ok {
+		return nil, errors.New(errNotRegistryMirrorSet)
+	}
+	return &external{kube: c.kube, logger: c.logger}, nil
+}
+
+// external manages the Registry and Project of each mirror of a
+// RegistryMirrorSet. Its external resources are those managed resources.
+type external struct {
+	kube   client.Client
+	logger logging.Logger
+}
+
+// children are the managed resources created for a RegistryMirrorSet.
+type children struct {
+	registries []v1beta1.Registry
+	projects   []projectv1beta1.Project
+}
+
+func (c *external) listChildren(ctx context.Context, cr *v1beta1.RegistryMirrorSet) (*children, error) {
+	opts := []client.ListOption{client.InNamespace(cr.GetNamespace()), client.MatchingLabels{LabelMirrorSet: cr.GetName()}}
+	rl := &v1beta1.RegistryList{}
+	if err := c.kube.List(ctx, rl, opts...); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	pl := &projectv1beta1.ProjectList{}
+	if err := c.kube.List(ctx, pl, opts...); err != nil {
+		return nil, errors.Wrap(err, errListChildren)
+	}
+	ch := &children{}
+	for _, r := range rl.Items {
+		if metav1.IsControlledBy(&r, cr) {
+			ch.registries = append(ch.registries, r)
+		}
+	}
+	for _, p := range pl.Items {
+		if metav1.IsControlledBy(&p, cr) {
+			ch.projects = append(ch.projects, p)
+		}
+	}
+	return ch, nil
+}
+
+// stale returns the children of mirrors no longer in the spec.
+func (ch *children) stale(cr *v1beta1.RegistryMirrorSet) *children {
+	want := map[string]bool{}
+	for _, m := range cr.Spec.ForProvider.Mirrors {
+		want[childName(cr, m)] = true
+	}
+	s := &children{}
+	for _, r := range ch.registries {
+		if !want[r.GetName()] {
+			s.registries = append(s.registries, r)
+		}
+	}
+	for _, p := range ch.projects {
+		if !want[p.GetName()] {
+			s.projects = append(s.projects, p)
+		}
+	}
+	return s
+}
+
+func (ch *children) empty() bool {
+	return len(ch.registries) == 0 && len(ch.projects) == 0
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "registrymirrorset.observe",
+		tracing.SpanAttrs("RegistryMirrorSet", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.RegistryMirrorSet)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotRegistryMirrorSet)
+	}
+
+	all, err := c.listChildren(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: !all.empty()}, nil
+	}
+
+	upToDate := all.stale(cr).empty()
+	exists := !all.empty()
+	obs := make([]v1beta1.RegistryMirrorObservation, 0, len(cr.Spec.ForProvider.Mirrors))
+	readyCount := 0
+	for _, m := range cr.Spec.ForProvider.Mirrors {
+		o := v1beta1.RegistryMirrorObservation{Name: m.Name}
+		key := types.NamespacedName{Namespace: cr.GetNamespace(), Name: childName(cr, m)}
+
+		r := &v1beta1.Registry{}
+		err := c.kube.Get(ctx, key, r)
+		if resource.IgnoreNotFound(err) != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(err, errGetRegistry, m.Name)
+		}
+		if kerrors.IsNotFound(err) {
+			upToDate = false
+			obs = append(obs, o)
+			continue
+		}
+		o.Registry = r.GetName()
+		o.RegistryID = r.Status.AtProvider.ID
+		if !registryUpToDate(cr, m, r) {
+			upToDate = false
+		}
+
+		p := &projectv1beta1.Project{}
+		err = c.kube.Get(ctx, key, p)
+		if resource.IgnoreNotFound(err) != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(err, errGetProject, m.Name)
+		}
+		if kerrors.IsNotFound(err) {
+			upToDate = false
+		} else {
+			o.Project = p.GetName()
+			if o.RegistryID != nil && !projectUpToDate(cr, m, p, *o.RegistryID) {
+				upToDate = false
+			}
+			o.Ready = ready(r) && ready(p)
+		}
+		if o.Ready {
+			readyCount++
+		}
+		obs = append(obs, o)
+	}
+
+	cr.Status.AtProvider.Mirrors = obs
+	cr.Status.AtProvider.ReadyMirrors = fmt.Sprintf("%d/%d", readyCount, len(cr.Spec.ForProvider.Mirrors))
+	if readyCount == len(cr.Spec.ForProvider.Mirrors) {
+		cr.SetConditions(xpv1.Available())
+	} else {
+		cr.SetConditions(xpv1.Unavailable())
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   exists,
+		ResourceUpToDate: upToDate,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	_, err := c.Update(ctx, mg)
+	return managed.ExternalCreation{}, err
+}
+
+// Update creates or updates the Registry of every mirror, and its Project
+// once Harbor has given the registry an ID. Children of mirrors removed from
+// the spec are deleted.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "registrymirrorset.update",
+		tracing.SpanAttrs("RegistryMirrorSet", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.RegistryMirrorSet)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotRegistryMirrorSet)
+	}
+
+	for _, m := range cr.Spec.ForProvider.Mirrors {
+		key := types.NamespacedName{Namespace: cr.GetNamespace(), Name: childName(cr, m)}
+
+		r := &v1beta1.Registry{}
+		r.SetNamespace(key.Namespace)
+		r.SetName(key.Name)
+		if _, err := controllerutil.CreateOrUpdate(ctx, c.kube, r, func() error {
+			return mutateRegistry(cr, m, r)
+		}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrapf(err, errApplyRegistry, m.Name)
+		}
+		if r.Status.AtProvider.ID == nil {
+			c.logger.Debug("Waiting for registry ID before creating proxy cache project", "mirror", m.Name)
+			continue
+		}
+		id := *r.Status.AtProvider.ID
+
+		p := &projectv1beta1.Project{}
+		p.SetNamespace(key.Namespace)
+		p.SetName(key.Name)
+		if _, err := controllerutil.CreateOrUpdate(ctx, c.kube, p, func() error {
+			mutateProject(cr, m, p, id)
+			return nil
+		}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrapf(err, errApplyProject, m.Name)
+		}
+	}
+
+	all, err := c.listChildren(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	return managed.ExternalUpdate{}, c.deleteChildren(ctx, all.stale(cr))
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	_, span := tracing.StartSpan(ctx, "registrymirrorset.delete",
+		tracing.SpanAttrs("RegistryMirrorSet", tracing.ResourceName(mg), "delete")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.RegistryMirrorSet)
+	if !ok {
+		return managed.ExternalDelete{}, errors.New(errNotRegistryMirrorSet)
+	}
+	cr.SetConditions(xpv1.Deleting())
+
+	all, err := c.listChildren(ctx, cr)
+	if err != nil {
+		return managed.ExternalDelete{}, err
+	}
+	return managed.ExternalDelete{}, c.deleteChildren(ctx, all)
+}
+
+// deleteChildren deletes projects, and the registries no remaining project
+// of the same mirror proxies. Harbor refuses to delete a registry that a
+// proxy cache project still uses, so registries go on a later pass.
+func (c *external) deleteChildren(ctx context.Context, ch *children) error {
+	inUse := map[string]bool{}
+	for i := range ch.projects {
+		p := &ch.projects[i]
+		inUse[p.GetName()] = true
+		if err := c.kube.Delete(ctx, p); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errDeleteChild, "Project", p.GetName())
+		}
+	}
+	for i := range ch.registries {
+		r := &ch.registries[i]
+		if inUse[r.GetName()] {
+			continue
+		}
+		if err := c.kube.Delete(ctx, r); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errDeleteChild, "Registry", r.GetName())
+		}
+	}
+	return nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return nil
+}
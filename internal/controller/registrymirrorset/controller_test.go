@@ -0,0 +1,259 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package registrymirrorset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/apis/registry/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newExternal(t *testing.T) *external {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := projectv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return &external{kube: fake.NewClientBuilder().WithScheme(s).Build(), logger: logging.NewNopLogger()}
+}
+
+func mirrorSet(mirrors ...v1beta1.RegistryMirror) *v1beta1.RegistryMirrorSet {
+	return &v1beta1.RegistryMirrorSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "mirrors", Namespace: "harbor", UID: "set-uid"},
+		Spec: v1beta1.RegistryMirrorSetSpec{
+			ManagedResourceSpec: xpv1.ManagedResourceSpec{
+				ProviderConfigReference: &xpv1.ProviderConfigReference{Kind: "ProviderConfig", Name: "harbor"},
+			},
+			ForProvider: v1beta1.RegistryMirrorSetParameters{
+				ProjectPrefix: ptr("proxy-"),
+				Public:        ptr(true),
+				StorageLimit:  ptr(int64(100)),
+				Mirrors:       mirrors,
+			},
+		},
+	}
+}
+
+var (
+	dockerhub = v1beta1.RegistryMirror{Name: "dockerhub", Type: "docker-hub"}
+	quay      = v1beta1.RegistryMirror{Name: "quay", Type: "quay", StorageLimit: ptr(int64(5))}
+)
+
+// registered records the ID Harbor gave a child registry, as the Registry
+// controller would.
+func registered(t *testing.T, kube client.Client, name string, id int64) {
+	t.Helper()
+	r := &v1beta1.Registry{}
+	if err := kube.Get(context.Background(), types.NamespacedName{Namespace: "harbor", Name: name}, r); err != nil {
+		t.Fatal(err)
+	}
+	r.Status.AtProvider.ID = &id
+	if err := kube.Update(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func markReady(t *testing.T, kube client.Client, o interface {
+	client.Object
+	SetConditions(...xpv1.Condition)
+}) {
+	t.Helper()
+	if err := kube.Get(context.Background(), client.ObjectKeyFromObject(o), o); err != nil {
+		t.Fatal(err)
+	}
+	o.SetConditions(xpv1.Available())
+	if err := kube.Update(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := mirrorSet(dockerhub, quay)
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || obs.ResourceExists {
+		t.Fatalf("Observe() before create = %+v, %v; want not existing", obs, err)
+	}
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	r := &v1beta1.Registry{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "mirrors-dockerhub"}, r); err != nil {
+		t.Fatalf("registry not created: %v", err)
+	}
+	if r.Spec.ForProvider.Name != "proxy-dockerhub" || r.Spec.ForProvider.URL != "https://hub.docker.com" {
+		t.Errorf("registry forProvider = %+v", r.Spec.ForProvider)
+	}
+	if r.Spec.ProviderConfigReference == nil || r.Spec.ProviderConfigReference.Name != "harbor" {
+		t.Errorf("registry providerConfigRef = %v, want harbor", r.Spec.ProviderConfigReference)
+	}
+	if !metav1.IsControlledBy(r, cr) {
+		t.Error("registry is not controlled by the RegistryMirrorSet")
+	}
+	pl := &projectv1beta1.ProjectList{}
+	if err := e.kube.List(ctx, pl); err != nil || len(pl.Items) != 0 {
+		t.Fatalf("projects created before their registries have IDs: %d, %v", len(pl.Items), err)
+	}
+
+	obs, err = e.Observe(ctx, cr)
+	if err != nil || !obs.ResourceExists || obs.ResourceUpToDate {
+		t.Fatalf("Observe() without projects = %+v, %v; want existing, not up to date", obs, err)
+	}
+
+	registered(t, e.kube, "mirrors-dockerhub", 11)
+	registered(t, e.kube, "mirrors-quay", 12)
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	p := &projectv1beta1.Project{}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "mirrors-quay"}, p); err != nil {
+		t.Fatalf("project not created: %v", err)
+	}
+	fp := p.Spec.ForProvider
+	if fp.Name != "proxy-quay" || *fp.RegistryID != 12 || !*fp.Public || *fp.StorageLimit != 5 {
+		t.Errorf("project forProvider = name %s, registry %d, public %v, limit %d", fp.Name, *fp.RegistryID, *fp.Public, *fp.StorageLimit)
+	}
+
+	obs, err = e.Observe(ctx, cr)
+	if err != nil || !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("Observe() after update = %+v, %v; want up to date", obs, err)
+	}
+	if got := cr.GetCondition(xpv1.TypeReady).Reason; got != xpv1.ReasonUnavailable {
+		t.Errorf("Ready reason = %s before children are ready, want %s", got, xpv1.ReasonUnavailable)
+	}
+
+	for _, n := range []string{"mirrors-dockerhub", "mirrors-quay"} {
+		key := metav1.ObjectMeta{Namespace: "harbor", Name: n}
+		markReady(t, e.kube, &v1beta1.Registry{ObjectMeta: key})
+		markReady(t, e.kube, &projectv1beta1.Project{ObjectMeta: key})
+	}
+	if _, err := e.Observe(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if cr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue || cr.Status.AtProvider.ReadyMirrors != "2/2" {
+		t.Errorf("status = %s, %s; want ready, 2/2", cr.GetCondition(xpv1.TypeReady).Status, cr.Status.AtProvider.ReadyMirrors)
+	}
+}
+
+func TestRemovedMirror(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := mirrorSet(dockerhub, quay)
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	registered(t, e.kube, "mirrors-dockerhub", 11)
+	registered(t, e.kube, "mirrors-quay", 12)
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	cr.Spec.ForProvider.Mirrors = []v1beta1.RegistryMirror{dockerhub}
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || obs.ResourceUpToDate {
+		t.Fatalf("Observe() with a removed mirror = %+v, %v; want not up to date", obs, err)
+	}
+
+	// The project goes first; Harbor will not delete a registry a proxy
+	// cache project still uses.
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	quayKey := types.NamespacedName{Namespace: "harbor", Name: "mirrors-quay"}
+	if err := e.kube.Get(ctx, quayKey, &projectv1beta1.Project{}); err == nil {
+		t.Error("project of removed mirror was not deleted")
+	}
+	if err := e.kube.Get(ctx, quayKey, &v1beta1.Registry{}); err != nil {
+		t.Errorf("registry of removed mirror deleted alongside its project: %v", err)
+	}
+
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.kube.Get(ctx, quayKey, &v1beta1.Registry{}); err == nil {
+		t.Error("registry of removed mirror was not deleted")
+	}
+	if err := e.kube.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "mirrors-dockerhub"}, &projectv1beta1.Project{}); err != nil {
+		t.Errorf("project of remaining mirror deleted: %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := mirrorSet(dockerhub)
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	registered(t, e.kube, "mirrors-dockerhub", 11)
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+	for i := 0; i < 2; i++ {
+		if _, err := e.Delete(ctx, cr); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+	}
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || obs.ResourceExists {
+		t.Errorf("Observe() after delete = %+v, %v; want gone", obs, err)
+	}
+}
+
+func TestChildrenInheritManagementPolicies(t *testing.T) {
+	ctx := context.Background()
+	e := newExternal(t)
+	cr := mirrorSet(dockerhub)
+	cr.SetManagementPolicies(xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionCreate, xpv1.ManagementActionUpdate})
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	registered(t, e.kube, "mirrors-dockerhub", 11)
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+
+	key := types.NamespacedName{Namespace: "harbor", Name: "mirrors-dockerhub"}
+	r := &v1beta1.Registry{}
+	if err := e.kube.Get(ctx, key, r); err != nil {
+		t.Fatal(err)
+	}
+	p := &projectv1beta1.Project{}
+	if err := e.kube.Get(ctx, key, p); err != nil {
+		t.Fatal(err)
+	}
+	for kind, got := range map[string]xpv1.ManagementPolicies{"Registry": r.GetManagementPolicies(), "Project": p.GetManagementPolicies()} {
+		if len(got) != 3 || got[2] != xpv1.ManagementActionUpdate {
+			t.Errorf("%s managementPolicies = %v, want those of the set", kind, got)
+		}
+	}
+}
+
+func TestURLRequired(t *testing.T) {
+	e := newExternal(t)
+	cr := mirrorSet(v1beta1.RegistryMirror{Name: "internal", Type: "harbor"})
+	if _, err := e.Create(context.Background(), cr); err == nil {
+		t.Error("Create() should fail for a harbor mirror without a url")
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package replication
+
+import (
+	"context"
+
+	"github.com/rossigee/provider-harbor/apis/common"
+	"github.com/rossigee/provider-harbor/apis/replication/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// observeExecutions records the most recent executions of the policy in the
+// status. The executions are informational, so when they cannot be listed
+// the previous ones are kept and the next poll tries again.
+func (c *external) observeExecutions(ctx context.Context, cr *v1beta1.Replication, policyID string) {
+	executions, err := c.service.ListReplicationExecutions(ctx, policyID)
+	if err != nil {
+		return
+	}
+	obs := make([]common.ExecutionObservation, 0, common.MaxRecentExecutions)
+	for _, e := range executions {
+		if len(obs) == common.MaxRecentExecutions {
+			break
+		}
+		o := common.ExecutionObservation{
+			ID:        e.ID,
+			Status:    e.Status,
+			Trigger:   e.Trigger,
+			Succeeded: e.SuccessCount,
+			Failed:    e.FailedCount,
+			Total:     e.TotalCount,
+		}
+		if !e.StartTime.IsZero() {
+			o.StartTime = &metav1.Time{Time: e.StartTime}
+		}
+		if !e.EndTime.IsZero() {
+			o.EndTime = &metav1.Time{Time: e.EndTime}
+		}
+		obs = append(obs, o)
+	}
+
+	cr.Status.AtProvider.RecentExecutions = obs
+	cr.Status.AtProvider.LastExecutionStatus = nil
+	if len(obs) > 0 {
+		cr.Status.AtProvider.LastExecutionStatus = &obs[0].Status
+	}
+}
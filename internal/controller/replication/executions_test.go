@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package replication
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/common"
+	"github.com/rossigee/provider-harbor/apis/replication/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+func TestObserveExecutions(t *testing.T) {
+	var executions []*harborclients.ReplicationExecution
+	for i := 10; i >= 1; i-- {
+		executions = append(executions, &harborclients.ReplicationExecution{ID: strconv.Itoa(i), Status: "Succeed", SuccessCount: 3, TotalCount: 3})
+	}
+	executions[0].Status = "Failed"
+	var listErr error
+	e := &external{service: &mockReplicationClient{
+		listReplicationExecutionsFunc: func(context.Context, string) ([]*harborclients.ReplicationExecution, error) {
+			return executions, listErr
+		},
+	}}
+	cr := &v1beta1.Replication{}
+
+	e.observeExecutions(context.Background(), cr, "7")
+	got := cr.Status.AtProvider.RecentExecutions
+	if len(got) != common.MaxRecentExecutions || got[0].ID != "10" {
+		t.Fatalf("recentExecutions = %+v, want the %d newest", got, common.MaxRecentExecutions)
+	}
+	if s := cr.Status.AtProvider.LastExecutionStatus; s == nil || *s != "Failed" {
+		t.Errorf("lastExecutionStatus = %v, want Failed", s)
+	}
+
+	listErr = errors.New("boom")
+	e.observeExecutions(context.Background(), cr, "7")
+	if len(cr.Status.AtProvider.RecentExecutions) != common.MaxRecentExecutions {
+		t.Error("the previous executions were dropped when they could not be listed")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package replication
+
+import (
+	"strings"
+
+	"github.com/rossigee/provider-harbor/apis/replication/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+)
+
+// Flags Harbor leaves out of its responses when they are false. override
+// and replicateDeletion default to true and false.
+var (
+	overrideField          = ctrlutil.BoolField{Default: true}
+	replicateDeletionField = ctrlutil.BoolField{}
+)
+
+// decorationMatches is the decoration of a filter that sets none.
+const decorationMatches = "matches"
+
+// policySpec converts the parameters of a Replication to a Harbor
+// replication policy spec.
+func policySpec(p v1beta1.ReplicationParameters) *harborclients.ReplicationPolicySpec {
+	spec := &harborclients.ReplicationPolicySpec{
+		Name:              p.Name,
+		Description:       p.Description,
+		SourceRegistry:    p.SourceRegistry,
+		Trigger:           p.Trigger,
+		CronSchedule:      p.CronSchedule,
+		DeleteSourceTag:   p.DeleteSourceTag,
+		ReplicateDeletion: p.ReplicateDeletion,
+		Override:          p.Override,
+		Enabled:           p.Enabled,
+		DestinationReg: &harborclients.ReplicationPolicyDestination{
+			Name:      p.DestinationReg.Name,
+			Namespace: p.DestinationReg.Namespace,
+			URL:       p.DestinationReg.URL,
+		},
+	}
+	if len(p.Filters) > 0 {
+		spec.Filters = make([]harborclients.ReplicationPolicyFilter, len(p.Filters))
+		for i, f := range p.Filters {
+			spec.Filters[i] = harborclients.ReplicationPolicyFilter{
+				Type:  f.Type,
+				Value: f.Value,
+			}
+			if f.Decoration != nil {
+				spec.Filters[i].Decoration = *f.Decoration
+			}
+		}
+	}
+	return spec
+}
+
+// policyUpToDate reports whether the observed policy matches p.
+func policyUpToDate(p v1beta1.ReplicationParameters, observed *harborclients.ReplicationPolicyStatus) bool {
+	if p.Description != nil && observed.Description != nil && *p.Description != *observed.Description {
+		return false
+	}
+	if !enabledField.Matches(p.Enabled, &observed.Enabled) ||
+		!overrideField.Matches(p.Override, &observed.Override) ||
+		!replicateDeletionField.Matches(p.ReplicateDeletion, &observed.ReplicateDeletion) {
+		return false
+	}
+	if p.Trigger != observed.Trigger {
+		return false
+	}
+	if p.CronSchedule != nil && *p.CronSchedule != observed.CronSchedule {
+		return false
+	}
+	source := ""
+	if p.SourceRegistry != nil {
+		source = *p.SourceRegistry
+	}
+	if source != observed.SourceRegistry || p.DestinationReg.Name != observed.DestinationRegistry ||
+		p.DestinationReg.Namespace != observed.DestNamespace {
+		return false
+	}
+	return filtersUpToDate(p.Filters, observed.Filters)
+}
+
+// filtersUpToDate reports whether the observed filters are the desired ones,
+// in any order.
+func filtersUpToDate(desired []v1beta1.ReplicationFilter, observed []harborclients.ReplicationPolicyFilter) bool {
+	if len(desired) != len(observed) {
+		return false
+	}
+	want := make(map[harborclients.ReplicationPolicyFilter]int, len(desired))
+	for _, f := range policySpec(v1beta1.ReplicationParameters{Filters: desired}).Filters {
+		want[filterKey(f)]++
+	}
+	for _, f := range observed {
+		k := filterKey(f)
+		if want[k] == 0 {
+			return false
+		}
+		want[k]--
+	}
+	return true
+}
+
+// filterKey normalizes a filter for comparison: the decoration defaults to
+// matches, and the labels of a label filter are separated by bare commas.
+func filterKey(f harborclients.ReplicationPolicyFilter) harborclients.ReplicationPolicyFilter {
+	if f.Decoration == "" {
+		f.Decoration = decorationMatches
+	}
+	if f.Type == "label" {
+		labels := strings.Split(f.Value, ",")
+		for i := range labels {
+			labels[i] = strings.TrimSpace(labels[i])
+		}
+		f.Value = strings.Join(labels, ",")
+	}
+	return f
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package replication
+
+import (
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/replication/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+func TestPolicyUpToDate(t *testing.T) {
+	excludes, cron, yes := "excludes", "0 0 2 * * *", true
+	params := v1beta1.ReplicationParameters{
+		Name:              "to-dr",
+		DestinationReg:    v1beta1.ReplicationDestination{Name: "dr-site", Namespace: "mirror"},
+		Trigger:           "scheduled",
+		CronSchedule:      &cron,
+		ReplicateDeletion: &yes,
+		Override:          &yes,
+		Filters: []v1beta1.ReplicationFilter{
+			{Type: "repository", Value: "team-a/**"},
+			{Type: "label", Value: "wip, draft", Decoration: &excludes},
+		},
+	}
+	observed := func() *harborclients.ReplicationPolicyStatus {
+		return &harborclients.ReplicationPolicyStatus{
+			Name:                "to-dr",
+			Enabled:             true,
+			Override:            true,
+			ReplicateDeletion:   true,
+			DestinationRegistry: "dr-site",
+			DestNamespace:       "mirror",
+			Trigger:             "scheduled",
+			CronSchedule:        cron,
+			Filters: []harborclients.ReplicationPolicyFilter{
+				{Type: "label", Value: "wip,draft", Decoration: "excludes"},
+				{Type: "repository", Value: "team-a/**", Decoration: "matches"},
+			},
+		}
+	}
+
+	if !policyUpToDate(params, observed()) {
+		t.Error("policyUpToDate() = false for a matching policy")
+	}
+
+	drifted := map[string]func(*harborclients.ReplicationPolicyStatus){
+		"schedule":          func(o *harborclients.ReplicationPolicyStatus) { o.CronSchedule = "0 0 3 * * *" },
+		"trigger":           func(o *harborclients.ReplicationPolicyStatus) { o.Trigger = "manual" },
+		"destination":       func(o *harborclients.ReplicationPolicyStatus) { o.DestinationRegistry = "other" },
+		"namespace":         func(o *harborclients.ReplicationPolicyStatus) { o.DestNamespace = "" },
+		"source":            func(o *harborclients.ReplicationPolicyStatus) { o.SourceRegistry = "upstream" },
+		"override":          func(o *harborclients.ReplicationPolicyStatus) { o.Override = false },
+		"replicateDeletion": func(o *harborclients.ReplicationPolicyStatus) { o.ReplicateDeletion = false },
+		"decoration":        func(o *harborclients.ReplicationPolicyStatus) { o.Filters[0].Decoration = "matches" },
+		"filters":           func(o *harborclients.ReplicationPolicyStatus) { o.Filters = o.Filters[:1] },
+	}
+	for name, drift := range drifted {
+		o := observed()
+		drift(o)
+		if policyUpToDate(params, o) {
+			t.Errorf("policyUpToDate() = true after the %s changed", name)
+		}
+	}
+}
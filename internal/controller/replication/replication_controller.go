@@ -6,6 +6,7 @@ package replication
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
@@ -19,7 +20,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"time"
 )
 
@@ -29,25 +29,33 @@ const (
 	errNewClient         = "cannot create new Harbor client"
 )
 
+// enabledField is the policy's enabled flag, which Harbor leaves out of its
+// responses when it is false.
+var enabledField = ctrlutil.BoolField{}
+
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.ReplicationGroupVersionKind.Kind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.ReplicationGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), harborclients.CapabilityManageSystem, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithSpecChecksums(mgr.GetClient(), ctrlutil.WithUpdateDebounce(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
-		}),
+		}))))))))))),
 		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
-		managed.WithPollInterval(1*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithPollIntervalHook(ctrlutil.DebouncedPollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ReplicationGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		WithEventFilter(resource.DesiredStateChanged()).
 		For(&v1beta1.Replication{}).
-		Complete(ratelimiter.NewReconciler(name, r, nil))
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -96,14 +104,9 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 			cr.Status.AtProvider.CreationTime = &t
 			ut := metav1.NewTime(policy.UpdateTime)
 			cr.Status.AtProvider.UpdateTime = &ut
+			c.observeExecutions(ctx, cr, policy.ID)
 
-			upToDate := true
-			if cr.Spec.ForProvider.Description != nil && policy.Description != nil && *cr.Spec.ForProvider.Description != *policy.Description {
-				upToDate = false
-			}
-			if cr.Spec.ForProvider.Enabled != nil && *cr.Spec.ForProvider.Enabled != policy.Enabled {
-				upToDate = false
-			}
+			upToDate := policyUpToDate(cr.Spec.ForProvider, policy)
 
 			// Set external name for adoption tracking
 			ctrlutil.SetExternalName(cr, policy.Name)
@@ -124,36 +127,12 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotReplication)
 	}
 
-	spec := &harborclients.ReplicationPolicySpec{
-		Name:            cr.Spec.ForProvider.Name,
-		Description:     cr.Spec.ForProvider.Description,
-		SourceRegistry:  cr.Spec.ForProvider.SourceRegistry,
-		Trigger:         cr.Spec.ForProvider.Trigger,
-		DeleteSourceTag: cr.Spec.ForProvider.DeleteSourceTag,
-		Override:        cr.Spec.ForProvider.Override,
-		Enabled:         cr.Spec.ForProvider.Enabled,
-	}
-
-	if len(cr.Spec.ForProvider.Filters) > 0 {
-		spec.Filters = make([]harborclients.ReplicationPolicyFilter, len(cr.Spec.ForProvider.Filters))
-		for i, f := range cr.Spec.ForProvider.Filters {
-			spec.Filters[i] = harborclients.ReplicationPolicyFilter{
-				Type:  f.Type,
-				Value: f.Value,
-			}
-		}
-	}
-
-	spec.DestinationReg = &harborclients.ReplicationPolicyDestination{
-		Name:      cr.Spec.ForProvider.DestinationReg.Name,
-		Namespace: cr.Spec.ForProvider.DestinationReg.Namespace,
-		URL:       cr.Spec.ForProvider.DestinationReg.URL,
-	}
-
+	spec := policySpec(cr.Spec.ForProvider)
 	_, err := c.service.CreateReplicationPolicy(ctx, spec)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
+	ctrlutil.SetExternalName(cr, cr.Spec.ForProvider.Name)
 
 	return managed.ExternalCreation{}, nil
 }
@@ -172,16 +151,7 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New("policy ID not set")
 	}
 
-	spec := &harborclients.ReplicationPolicySpec{
-		Name:            cr.Spec.ForProvider.Name,
-		Description:     cr.Spec.ForProvider.Description,
-		Trigger:         cr.Spec.ForProvider.Trigger,
-		DeleteSourceTag: cr.Spec.ForProvider.DeleteSourceTag,
-		Override:        cr.Spec.ForProvider.Override,
-		Enabled:         cr.Spec.ForProvider.Enabled,
-	}
-
-	_, err := c.service.UpdateReplicationPolicy(ctx, *cr.Status.AtProvider.ID, spec)
+	_, err := c.service.UpdateReplicationPolicy(ctx, *cr.Status.AtProvider.ID, policySpec(cr.Spec.ForProvider))
 	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
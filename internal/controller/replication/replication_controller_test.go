@@ -789,11 +789,12 @@ func TestReplicationParametersValidation(t *testing.T) {
 
 type mockReplicationClient struct {
 	harborclients.HarborClienter
-	listReplicationPoliciesFunc func(ctx context.Context) ([]*harborclients.ReplicationPolicyStatus, error)
-	createReplicationPolicyFunc func(ctx context.Context, spec *harborclients.ReplicationPolicySpec) (*harborclients.ReplicationPolicyStatus, error)
-	updateReplicationPolicyFunc func(ctx context.Context, policyID string, spec *harborclients.ReplicationPolicySpec) (*harborclients.ReplicationPolicyStatus, error)
-	deleteReplicationPolicyFunc func(ctx context.Context, policyID string) error
-	closeFunc                   func() error
+	listReplicationPoliciesFunc   func(ctx context.Context) ([]*harborclients.ReplicationPolicyStatus, error)
+	createReplicationPolicyFunc   func(ctx context.Context, spec *harborclients.ReplicationPolicySpec) (*harborclients.ReplicationPolicyStatus, error)
+	updateReplicationPolicyFunc   func(ctx context.Context, policyID string, spec *harborclients.ReplicationPolicySpec) (*harborclients.ReplicationPolicyStatus, error)
+	deleteReplicationPolicyFunc   func(ctx context.Context, policyID string) error
+	closeFunc                     func() error
+	listReplicationExecutionsFunc func(ctx context.Context, policyID string) ([]*harborclients.ReplicationExecution, error)
 }
 
 func (m *mockReplicationClient) ListReplicationPolicies(ctx context.Context) ([]*harborclients.ReplicationPolicyStatus, error) {
@@ -824,6 +825,13 @@ func (m *mockReplicationClient) DeleteReplicationPolicy(ctx context.Context, pol
 	return nil
 }
 
+func (m *mockReplicationClient) ListReplicationExecutions(ctx context.Context, policyID string) ([]*harborclients.ReplicationExecution, error) {
+	if m.listReplicationExecutionsFunc != nil {
+		return m.listReplicationExecutionsFunc(ctx, policyID)
+	}
+	return nil, nil
+}
+
 func (m *mockReplicationClient) Close() error {
 	if m.closeFunc != nil {
 		return m.closeFunc()
@@ -839,6 +847,10 @@ func ptrString(s string) *string {
 	return &s
 }
 
+func ptrBool(b bool) *bool {
+	return &b
+}
+
 func getStringPtr(s string) *string {
 	return &s
 }
@@ -849,3 +861,39 @@ func getBoolValue(b *bool) bool {
 	}
 	return *b
 }
+
+func TestObserveReplicationEnabledDefault(t *testing.T) {
+	cases := map[string]struct {
+		enabled  *bool
+		observed bool
+		want     bool
+	}{
+		"Unset":         {enabled: nil, observed: true, want: true},
+		"DisabledMatch": {enabled: ptrBool(false), observed: false, want: true},
+		"EnabledMatch":  {enabled: ptrBool(true), observed: true, want: true},
+		"Disabled":      {enabled: ptrBool(true), observed: false, want: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Replication{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-replication"},
+				Spec: v1beta1.ReplicationSpec{ForProvider: v1beta1.ReplicationParameters{
+					Name:    "my-replication",
+					Enabled: tc.enabled,
+				}},
+			}
+			ext := &external{service: &mockReplicationClient{
+				listReplicationPoliciesFunc: func(context.Context) ([]*harborclients.ReplicationPolicyStatus, error) {
+					return []*harborclients.ReplicationPolicyStatus{{ID: "policy-123", Name: "my-replication", Enabled: tc.observed}}, nil
+				},
+			}}
+			obs, err := ext.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Observe() error = %v", err)
+			}
+			if obs.ResourceUpToDate != tc.want {
+				t.Errorf("ResourceUpToDate = %v, want %v", obs.ResourceUpToDate, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package retention
+
+import (
+	"context"
+
+	"github.com/rossigee/provider-harbor/apis/common"
+	"github.com/rossigee/provider-harbor/apis/retention/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// observeExecutions records the most recent executions of the policy in the
+// status. The executions are informational, so when they cannot be listed
+// the previous ones are kept and the next poll tries again.
+//
+// Harbor counts the outcomes of a retention execution only by listing its
+// tasks. The counts of an execution that had already finished when it was
+// last observed are kept rather than listed again.
+func (c *external) observeExecutions(ctx context.Context, cr *v1beta1.Retention, policyID string) {
+	executions, err := c.service.ListRetentionExecutions(ctx, policyID)
+	if err != nil {
+		return
+	}
+	finished := map[string]common.ExecutionObservation{}
+	for _, o := range cr.Status.AtProvider.RecentExecutions {
+		if o.EndTime != nil {
+			finished[o.ID] = o
+		}
+	}
+
+	obs := make([]common.ExecutionObservation, 0, common.MaxRecentExecutions)
+	for _, e := range executions {
+		if len(obs) == common.MaxRecentExecutions {
+			break
+		}
+		o := common.ExecutionObservation{ID: e.ID, Status: e.Status, Trigger: e.Trigger}
+		if !e.StartTime.IsZero() {
+			o.StartTime = &metav1.Time{Time: e.StartTime}
+		}
+		if !e.EndTime.IsZero() {
+			o.EndTime = &metav1.Time{Time: e.EndTime}
+		}
+		if prev, ok := finished[e.ID]; ok && prev.Status == e.Status {
+			o.Succeeded, o.Failed, o.Total = prev.Succeeded, prev.Failed, prev.Total
+		} else if n, err := c.service.CountRetentionTasks(ctx, policyID, e.ID); err == nil {
+			o.Succeeded, o.Failed, o.Total = n.Succeeded, n.Failed, n.Total
+		}
+		obs = append(obs, o)
+	}
+
+	cr.Status.AtProvider.RecentExecutions = obs
+	cr.Status.AtProvider.LastExecutionTime = nil
+	if len(obs) > 0 {
+		cr.Status.AtProvider.LastExecutionTime = obs[0].StartTime
+	}
+}
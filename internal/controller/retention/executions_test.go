@@ -0,0 +1,69 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package retention
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/apis/common"
+	"github.com/rossigee/provider-harbor/apis/retention/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+func TestObserveExecutions(t *testing.T) {
+	start := time.Date(2024, 5, 3, 1, 0, 0, 0, time.UTC)
+	var executions []*harborclients.RetentionExecution
+	for i := 7; i >= 1; i-- {
+		executions = append(executions, &harborclients.RetentionExecution{
+			ID:        strconv.Itoa(i),
+			Status:    "Success",
+			StartTime: start.Add(time.Duration(i) * time.Hour),
+			EndTime:   start.Add(time.Duration(i)*time.Hour + time.Minute),
+		})
+	}
+	executions[0].Status = "Running"
+	executions[0].EndTime = time.Time{}
+
+	counted := map[string]int{}
+	e := &external{service: &mockRetentionClient{
+		listRetentionExecutionsFunc: func(context.Context, string) ([]*harborclients.RetentionExecution, error) {
+			return executions, nil
+		},
+		countRetentionTasksFunc: func(_ context.Context, _, id string) (*harborclients.RetentionTaskCounts, error) {
+			counted[id]++
+			return &harborclients.RetentionTaskCounts{Succeeded: 4, Failed: 1, Total: 5}, nil
+		},
+	}}
+	cr := &v1beta1.Retention{}
+
+	e.observeExecutions(context.Background(), cr, "4")
+	got := cr.Status.AtProvider.RecentExecutions
+	if len(got) != common.MaxRecentExecutions {
+		t.Fatalf("recorded %d executions, want %d", len(got), common.MaxRecentExecutions)
+	}
+	if got[0].ID != "7" || got[0].EndTime != nil || got[1].EndTime == nil {
+		t.Errorf("recentExecutions = %+v, want the running execution first", got)
+	}
+	if got[1].Succeeded != 4 || got[1].Failed != 1 || got[1].Total != 5 {
+		t.Errorf("counts = %+v", got[1])
+	}
+	if lt := cr.Status.AtProvider.LastExecutionTime; lt == nil || !lt.Time.Equal(executions[0].StartTime) {
+		t.Errorf("lastExecutionTime = %v, want %v", lt, executions[0].StartTime)
+	}
+
+	e.observeExecutions(context.Background(), cr, "4")
+	for id, n := range counted {
+		want := 1
+		if id == "7" {
+			want = 2
+		}
+		if n != want {
+			t.Errorf("tasks of execution %s counted %d times, want %d", id, n, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package retention
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/retention/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+// tagRetentionResource is Harbor's project resource for retention policies.
+const tagRetentionResource = "tag-retention"
+
+// Project permissions each change needs. Linking a policy to its project
+// updates the project's metadata, which only project administrators may do.
+var (
+	createAccesses = []harborclients.RobotAccess{
+		{Resource: tagRetentionResource, Action: "create"},
+		{Resource: harborclients.ProjectSelfResource, Action: "update"},
+	}
+	updateAccesses = []harborclients.RobotAccess{
+		{Resource: tagRetentionResource, Action: "update"},
+		{Resource: harborclients.ProjectSelfResource, Action: "update"},
+	}
+	deleteAccesses = []harborclients.RobotAccess{
+		{Resource: tagRetentionResource, Action: "delete"},
+	}
+)
+
+// checkPermissions fails when the Harbor account lacks any of the accesses
+// in cr's project, before a change is attempted. Otherwise Harbor could
+// accept the policy and refuse to link it, leaving the project half
+// configured. The result is recorded as a Permitted condition.
+func (c *external) checkPermissions(ctx context.Context, cr *v1beta1.Retention, accesses []harborclients.RobotAccess) error {
+	granted, err := c.service.GetProjectPermissions(ctx, cr.Spec.ForProvider.ProjectID)
+	if err != nil {
+		return errors.Wrap(err, errGetPermissions)
+	}
+	have := map[harborclients.RobotAccess]bool{}
+	for _, a := range granted {
+		have[a] = true
+	}
+
+	var missing []string
+	for _, a := range accesses {
+		if !have[a] {
+			missing = append(missing, a.String())
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		cr.SetConditions(v1beta1.PermissionDenied(missing))
+		return errors.Errorf("%s: %v", errPermissionDenied, missing)
+	}
+	cr.SetConditions(v1beta1.Permitted())
+	return nil
+}
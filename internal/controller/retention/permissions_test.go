@@ -0,0 +1,72 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package retention
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/retention/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCreatePermissionDenied(t *testing.T) {
+	created := false
+	e := &external{service: &mockRetentionClient{
+		// A project maintainer may run retention but not configure it.
+		getProjectPermissionsFunc: func(context.Context, string) ([]harborclients.RobotAccess, error) {
+			return []harborclients.RobotAccess{
+				{Resource: "tag-retention", Action: "read"},
+				{Resource: "tag-retention", Action: "operate"},
+			}, nil
+		},
+		createRetentionPolicyFunc: func(context.Context, *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error) {
+			created = true
+			return &harborclients.RetentionPolicyStatus{ID: "1"}, nil
+		},
+	}}
+	cr := &v1beta1.Retention{Spec: v1beta1.RetentionSpec{ForProvider: v1beta1.RetentionParameters{ProjectID: "5"}}}
+
+	if _, err := e.Create(context.Background(), cr); err == nil {
+		t.Fatal("Create() should fail without project permissions")
+	}
+	if created {
+		t.Error("Create() created a policy without project permissions")
+	}
+	c := cr.GetCondition(v1beta1.TypePermitted)
+	if c.Status != corev1.ConditionFalse || c.Reason != v1beta1.ReasonPermissionDenied {
+		t.Errorf("Permitted condition = %+v, want PermissionDenied", c)
+	}
+	if want := "the Harbor account lacks project permissions project:update, tag-retention:create"; c.Message != want {
+		t.Errorf("message = %q, want %q", c.Message, want)
+	}
+}
+
+func TestDeletePermitted(t *testing.T) {
+	id := "1"
+	deleted := false
+	e := &external{service: &mockRetentionClient{
+		getProjectPermissionsFunc: func(context.Context, string) ([]harborclients.RobotAccess, error) {
+			return deleteAccesses, nil
+		},
+		deleteRetentionPolicyFunc: func(context.Context, string, string) error {
+			deleted = true
+			return nil
+		},
+	}}
+	cr := &v1beta1.Retention{Spec: v1beta1.RetentionSpec{ForProvider: v1beta1.RetentionParameters{ProjectID: "5"}}}
+	cr.Status.AtProvider.ID = &id
+
+	if _, err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Error("Delete() did not delete the policy")
+	}
+	if c := cr.GetCondition(v1beta1.TypePermitted); c.Status != corev1.ConditionTrue {
+		t.Errorf("Permitted condition = %+v, want True", c)
+	}
+}
@@ -6,6 +6,7 @@ package retention
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
@@ -16,38 +17,58 @@ import (
 	harborclients "github.com/rossigee/provider-harbor/internal/clients"
 	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
 	"github.com/rossigee/provider-harbor/internal/tracing"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"time"
 )
 
 const (
 	errNotRetention    = "managed resource is not a Retention custom resource"
 	errRetentionDelete = "cannot delete Harbor retention policy"
+	errGetLinkage      = "cannot get project retention_id metadata"
+	errSetLinkage      = "cannot link project to Harbor retention policy"
 	errNewClient       = "cannot create new Harbor client"
+
+	errGetPermissions   = "cannot get the Harbor account's project permissions"
+	errPermissionDenied = "the Harbor account lacks project permissions"
 )
 
+// enabledField is the policy's enabled flag. Disabled retention policies
+// come back from Harbor without it.
+var enabledField = ctrlutil.BoolField{}
+
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.RetentionGroupVersionKind.Kind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.RetentionGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithProtection(mgr.GetClient(), retentionTarget, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithSpecChecksums(mgr.GetClient(), ctrlutil.WithUpdateDebounce(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
-		}),
+		}))))))))))),
 		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
-		managed.WithPollInterval(1*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithPollIntervalHook(ctrlutil.DebouncedPollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.RetentionGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		WithEventFilter(resource.DesiredStateChanged()).
 		For(&v1beta1.Retention{}).
-		Complete(ratelimiter.NewReconciler(name, r, nil))
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// retentionTarget returns the project a Retention policy belongs to.
+func retentionTarget(mg resource.Managed) ctrlutil.Target {
+	cr, ok := mg.(*v1beta1.Retention)
+	if !ok {
+		return ctrlutil.Target{}
+	}
+	return ctrlutil.Target{Project: cr.Spec.ForProvider.ProjectID}
 }
 
 type connector struct {
@@ -83,32 +104,33 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotRetention)
 	}
 
-	policies, err := c.service.ListRetentionPolicies(ctx, cr.Spec.ForProvider.ProjectID)
+	policy, err := c.findPolicy(ctx, cr)
 	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
 
-	for _, policy := range policies {
-		if policy.ProjectID == cr.Spec.ForProvider.ProjectID {
-			cr.Status.AtProvider.ID = &policy.ID
-			cr.Status.AtProvider.Enabled = &policy.Enabled
-			t := metav1.NewTime(policy.CreationTime)
-			cr.Status.AtProvider.CreationTime = &t
-			ut := metav1.NewTime(policy.UpdateTime)
-			cr.Status.AtProvider.UpdateTime = &ut
-
-			upToDate := true
-			if cr.Spec.ForProvider.Description != nil && policy.Description != nil && *cr.Spec.ForProvider.Description != *policy.Description {
-				upToDate = false
-			}
-			if cr.Spec.ForProvider.Enabled != nil && *cr.Spec.ForProvider.Enabled != policy.Enabled {
-				upToDate = false
-			}
-
-			// Set external name for adoption tracking
-			ctrlutil.SetExternalName(cr, policy.ID)
-			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate}, nil
+	if policy != nil {
+		cr.Status.AtProvider.ID = &policy.ID
+		cr.Status.AtProvider.Enabled = &policy.Enabled
+		c.observeExecutions(ctx, cr, policy.ID)
+
+		upToDate := policyUpToDate(cr, policy)
+
+		linked, err := c.observeLinkage(ctx, cr, policy.ID)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if !linked {
+			upToDate = false
 		}
+
+		// Set external name for adoption tracking
+		ctrlutil.SetExternalName(cr, policy.ID)
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate}, nil
+	}
+
+	if _, err := c.observeLinkage(ctx, cr, ""); err != nil {
+		return managed.ExternalObservation{}, err
 	}
 
 	return managed.ExternalObservation{ResourceExists: false}, nil
@@ -124,29 +146,23 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotRetention)
 	}
 
-	spec := &harborclients.RetentionPolicySpec{
-		ProjectID:   cr.Spec.ForProvider.ProjectID,
-		Description: cr.Spec.ForProvider.Description,
-		Trigger:     cr.Spec.ForProvider.Trigger,
-		Enabled:     cr.Spec.ForProvider.Enabled,
+	if err := c.checkPermissions(ctx, cr, createAccesses); err != nil {
+		return managed.ExternalCreation{}, err
 	}
 
-	if len(cr.Spec.ForProvider.Rules) > 0 {
-		spec.Rules = make([]harborclients.RetentionPolicyRule, len(cr.Spec.ForProvider.Rules))
-		for i, r := range cr.Spec.ForProvider.Rules {
-			spec.Rules[i] = harborclients.RetentionPolicyRule{
-				RuleType:     r.RuleType,
-				TagSelectors: r.TagSelectors,
-				Parameters:   convertStringMap(r.Parameters),
-			}
-		}
-	}
+	spec := policySpec(cr)
 
-	_, err := c.service.CreateRetentionPolicy(ctx, spec)
+	policy, err := c.service.CreateRetentionPolicy(ctx, spec)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
 
+	if err := c.service.SetProjectRetentionID(ctx, cr.Spec.ForProvider.ProjectID, policy.ID); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errSetLinkage)
+	}
+	ctrlutil.SetExternalName(cr, policy.ID)
+	cr.Status.AtProvider.ID = &policy.ID
+
 	return managed.ExternalCreation{}, nil
 }
 
@@ -164,18 +180,21 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New("policy ID not set")
 	}
 
-	spec := &harborclients.RetentionPolicySpec{
-		ProjectID:   cr.Spec.ForProvider.ProjectID,
-		Description: cr.Spec.ForProvider.Description,
-		Trigger:     cr.Spec.ForProvider.Trigger,
-		Enabled:     cr.Spec.ForProvider.Enabled,
+	if err := c.checkPermissions(ctx, cr, updateAccesses); err != nil {
+		return managed.ExternalUpdate{}, err
 	}
 
-	_, err := c.service.UpdateRetentionPolicy(ctx, cr.Spec.ForProvider.ProjectID, *cr.Status.AtProvider.ID, spec)
+	_, err := c.service.UpdateRetentionPolicy(ctx, cr.Spec.ForProvider.ProjectID, *cr.Status.AtProvider.ID, policySpec(cr))
 	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
 
+	// Repair the project linkage in case retention_id was cleared or points
+	// at a policy that no longer exists.
+	if err := c.service.SetProjectRetentionID(ctx, cr.Spec.ForProvider.ProjectID, *cr.Status.AtProvider.ID); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errSetLinkage)
+	}
+
 	return managed.ExternalUpdate{}, nil
 }
 
@@ -193,6 +212,10 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, nil
 	}
 
+	if err := c.checkPermissions(ctx, cr, deleteAccesses); err != nil {
+		return managed.ExternalDelete{}, err
+	}
+
 	err := c.service.DeleteRetentionPolicy(ctx, cr.Spec.ForProvider.ProjectID, *cr.Status.AtProvider.ID)
 	if err != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, errRetentionDelete)
@@ -205,13 +228,80 @@ func (c *external) Disconnect(ctx context.Context) error {
 	return c.service.Close()
 }
 
-func convertStringMap(m map[string]string) map[string]interface{} {
-	if len(m) == 0 {
-		return nil
+// findPolicy returns cr's retention policy, or nil when there is none. A
+// policy its project is no longer linked to is found by the ID recorded when
+// it was created, so that the link is repaired rather than a second policy
+// created.
+func (c *external) findPolicy(ctx context.Context, cr *v1beta1.Retention) (*harborclients.RetentionPolicyStatus, error) {
+	projectID := cr.Spec.ForProvider.ProjectID
+	policies, err := c.service.ListRetentionPolicies(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if policy.ProjectID == projectID {
+			return policy, nil
+		}
+	}
+
+	if cr.Status.AtProvider.ID == nil {
+		return nil, nil
+	}
+	policy, err := c.service.GetRetentionPolicy(ctx, projectID, *cr.Status.AtProvider.ID)
+	if harborclients.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || policy.ProjectID != projectID {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// observeLinkage checks the project's retention_id metadata against policyID
+// and records the result as a ProjectLinked condition. It reports whether the
+// project is linked to policyID.
+func (c *external) observeLinkage(ctx context.Context, cr *v1beta1.Retention, policyID string) (bool, error) {
+	projectID := cr.Spec.ForProvider.ProjectID
+
+	retentionID, err := c.service.GetProjectRetentionID(ctx, projectID)
+	if err != nil {
+		return false, errors.Wrap(err, errGetLinkage)
+	}
+
+	if retentionID != "" && retentionID == policyID {
+		cr.SetConditions(v1beta1.ProjectLinked())
+		return true, nil
+	}
+
+	if retentionID != "" {
+		_, err := c.service.GetRetentionPolicy(ctx, projectID, retentionID)
+		if harborclients.IsNotFound(err) {
+			cr.SetConditions(v1beta1.ProjectLinkageBroken(retentionID))
+			return false, nil
+		}
+		if err != nil {
+			return false, errors.Wrap(err, errGetLinkage)
+		}
+	}
+
+	if policyID != "" {
+		cr.SetConditions(v1beta1.ProjectLinkageMissing())
+	}
+	return false, nil
+}
+
+// policyUpToDate reports whether policy has the rules, schedule and enabled
+// state cr describes. Rules are compared regardless of their order.
+func policyUpToDate(cr *v1beta1.Retention, policy *harborclients.RetentionPolicyStatus) bool {
+	want := policySpec(cr)
+	if !enabledField.Matches(cr.Spec.ForProvider.Enabled, &policy.Enabled) {
+		return false
 	}
-	result := make(map[string]interface{}, len(m))
-	for k, v := range m {
-		result[k] = v
+	if want.Schedule != normalizeCron(policy.Schedule) {
+		return false
 	}
-	return result
+	return rulesMatch(want.Rules, policy.Rules)
 }
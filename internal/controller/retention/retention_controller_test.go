@@ -11,7 +11,6 @@ import (
 	harborclients "github.com/rossigee/provider-harbor/internal/clients"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"testing"
-	"time"
 )
 
 func TestConnectNotRetention(t *testing.T) {
@@ -114,14 +113,15 @@ func TestObserveRetentionExists(t *testing.T) {
 			listRetentionPoliciesFunc: func(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error) {
 				return []*harborclients.RetentionPolicyStatus{
 					{
-						ID:           "retention-123",
-						ProjectID:    "project-1",
-						Enabled:      true,
-						CreationTime: time.Now(),
-						UpdateTime:   time.Now(),
+						ID:        "retention-123",
+						ProjectID: "project-1",
+						Enabled:   true,
 					},
 				}, nil
 			},
+			getProjectRetentionIDFunc: func(ctx context.Context, projectID string) (string, error) {
+				return "retention-123", nil
+			},
 		},
 	}
 
@@ -135,11 +135,86 @@ func TestObserveRetentionExists(t *testing.T) {
 	if !obs.ResourceUpToDate {
 		t.Error("ResourceUpToDate should be true")
 	}
+	if c := retention.GetCondition(v1beta1.TypeProjectLinked); c.Reason != v1beta1.ReasonLinked {
+		t.Errorf("ProjectLinked reason = %q, want %q", c.Reason, v1beta1.ReasonLinked)
+	}
+}
+
+func TestObserveRetentionLinkageMissing(t *testing.T) {
+	ctx := context.Background()
+	retention := &v1beta1.Retention{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-retention",
+		},
+		Spec: v1beta1.RetentionSpec{
+			ForProvider: v1beta1.RetentionParameters{
+				ProjectID: "project-1",
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockRetentionClient{
+			listRetentionPoliciesFunc: func(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error) {
+				return []*harborclients.RetentionPolicyStatus{{ID: "retention-123", ProjectID: "project-1"}}, nil
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, retention)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false when the project is not linked")
+	}
+	if c := retention.GetCondition(v1beta1.TypeProjectLinked); c.Reason != v1beta1.ReasonLinkageMissing {
+		t.Errorf("ProjectLinked reason = %q, want %q", c.Reason, v1beta1.ReasonLinkageMissing)
+	}
+}
+
+func TestObserveRetentionLinkageBroken(t *testing.T) {
+	ctx := context.Background()
+	retention := &v1beta1.Retention{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-retention",
+		},
+		Spec: v1beta1.RetentionSpec{
+			ForProvider: v1beta1.RetentionParameters{
+				ProjectID: "project-1",
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockRetentionClient{
+			listRetentionPoliciesFunc: func(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error) {
+				return []*harborclients.RetentionPolicyStatus{{ID: "retention-123", ProjectID: "project-1"}}, nil
+			},
+			getProjectRetentionIDFunc: func(ctx context.Context, projectID string) (string, error) {
+				return "99", nil
+			},
+			getRetentionPolicyFunc: func(ctx context.Context, projectID, policyID string) (*harborclients.RetentionPolicyStatus, error) {
+				return nil, notFoundError{}
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, retention)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false when linkage is broken")
+	}
+	if c := retention.GetCondition(v1beta1.TypeProjectLinked); c.Reason != v1beta1.ReasonLinkageBroken {
+		t.Errorf("ProjectLinked reason = %q, want %q", c.Reason, v1beta1.ReasonLinkageBroken)
+	}
 }
 
 func TestObserveRetentionNotUpToDate(t *testing.T) {
 	ctx := context.Background()
-	desc := "old description"
+	count := int64(10)
 	enabled := true
 	retention := &v1beta1.Retention{
 		ObjectMeta: metav1.ObjectMeta{
@@ -147,9 +222,9 @@ func TestObserveRetentionNotUpToDate(t *testing.T) {
 		},
 		Spec: v1beta1.RetentionSpec{
 			ForProvider: v1beta1.RetentionParameters{
-				ProjectID:   "project-1",
-				Description: &desc,
-				Enabled:     &enabled,
+				ProjectID: "project-1",
+				Enabled:   &enabled,
+				Rules:     []v1beta1.RetentionRule{{RuleType: "latestPushedK", Count: &count}},
 			},
 		},
 	}
@@ -157,15 +232,14 @@ func TestObserveRetentionNotUpToDate(t *testing.T) {
 	ext := &external{
 		service: &mockRetentionClient{
 			listRetentionPoliciesFunc: func(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error) {
-				newDesc := "new description"
 				return []*harborclients.RetentionPolicyStatus{
 					{
-						ID:           "retention-123",
-						ProjectID:    "project-1",
-						Description:  &newDesc,
-						Enabled:      true,
-						CreationTime: time.Now(),
-						UpdateTime:   time.Now(),
+						ID:        "retention-123",
+						ProjectID: "project-1",
+						Enabled:   true,
+						Rules: []harborclients.RetentionPolicyRule{
+							{RuleType: "latestPushedK", Value: 5, RepositoryPattern: "**", TagPattern: "**"},
+						},
 					},
 				}, nil
 			},
@@ -180,7 +254,7 @@ func TestObserveRetentionNotUpToDate(t *testing.T) {
 		t.Error("ResourceExists should be true")
 	}
 	if obs.ResourceUpToDate {
-		t.Error("ResourceUpToDate should be false when description differs")
+		t.Error("ResourceUpToDate should be false when a rule's count differs")
 	}
 }
 
@@ -197,13 +271,17 @@ func TestCreateRetentionSuccess(t *testing.T) {
 		},
 	}
 
+	var linked string
 	ext := &external{
 		service: &mockRetentionClient{
+			setProjectRetentionIDFunc: func(ctx context.Context, projectID, policyID string) error {
+				linked = policyID
+				return nil
+			},
 			createRetentionPolicyFunc: func(ctx context.Context, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error) {
 				return &harborclients.RetentionPolicyStatus{
-					ID:           "retention-123",
-					ProjectID:    spec.ProjectID,
-					CreationTime: time.Now(),
+					ID:        "retention-123",
+					ProjectID: spec.ProjectID,
 				}, nil
 			},
 		},
@@ -213,6 +291,9 @@ func TestCreateRetentionSuccess(t *testing.T) {
 	if err != nil {
 		t.Errorf("Create should not fail, got %v", err)
 	}
+	if linked != "retention-123" {
+		t.Errorf("project linked to %q, want retention-123", linked)
+	}
 }
 
 func TestCreateRetentionError(t *testing.T) {
@@ -267,9 +348,8 @@ func TestUpdateRetentionSuccess(t *testing.T) {
 		service: &mockRetentionClient{
 			updateRetentionPolicyFunc: func(ctx context.Context, projectID, policyID string, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error) {
 				return &harborclients.RetentionPolicyStatus{
-					ID:         policyID,
-					ProjectID:  projectID,
-					UpdateTime: time.Now(),
+					ID:        policyID,
+					ProjectID: projectID,
 				}, nil
 			},
 		},
@@ -497,10 +577,28 @@ func TestRetentionParametersValidation(t *testing.T) {
 
 type mockRetentionClient struct {
 	harborclients.HarborClienter
-	listRetentionPoliciesFunc func(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error)
-	createRetentionPolicyFunc func(ctx context.Context, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error)
-	updateRetentionPolicyFunc func(ctx context.Context, projectID, policyID string, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error)
-	deleteRetentionPolicyFunc func(ctx context.Context, projectID, policyID string) error
+	listRetentionPoliciesFunc   func(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error)
+	createRetentionPolicyFunc   func(ctx context.Context, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error)
+	updateRetentionPolicyFunc   func(ctx context.Context, projectID, policyID string, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error)
+	deleteRetentionPolicyFunc   func(ctx context.Context, projectID, policyID string) error
+	getRetentionPolicyFunc      func(ctx context.Context, projectID, policyID string) (*harborclients.RetentionPolicyStatus, error)
+	getProjectRetentionIDFunc   func(ctx context.Context, projectID string) (string, error)
+	setProjectRetentionIDFunc   func(ctx context.Context, projectID, policyID string) error
+	listRetentionExecutionsFunc func(ctx context.Context, policyID string) ([]*harborclients.RetentionExecution, error)
+	countRetentionTasksFunc     func(ctx context.Context, policyID, executionID string) (*harborclients.RetentionTaskCounts, error)
+	getProjectPermissionsFunc   func(ctx context.Context, projectID string) ([]harborclients.RobotAccess, error)
+}
+
+// GetProjectPermissions grants every access a retention policy needs unless
+// getProjectPermissionsFunc is set.
+func (m *mockRetentionClient) GetProjectPermissions(ctx context.Context, projectID string) ([]harborclients.RobotAccess, error) {
+	if m.getProjectPermissionsFunc != nil {
+		return m.getProjectPermissionsFunc(ctx, projectID)
+	}
+	var all []harborclients.RobotAccess
+	all = append(all, createAccesses...)
+	all = append(all, updateAccesses...)
+	return append(all, deleteAccesses...), nil
 }
 
 func (m *mockRetentionClient) ListRetentionPolicies(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error) {
@@ -531,6 +629,41 @@ func (m *mockRetentionClient) DeleteRetentionPolicy(ctx context.Context, project
 	return nil
 }
 
+func (m *mockRetentionClient) GetRetentionPolicy(ctx context.Context, projectID, policyID string) (*harborclients.RetentionPolicyStatus, error) {
+	if m.getRetentionPolicyFunc != nil {
+		return m.getRetentionPolicyFunc(ctx, projectID, policyID)
+	}
+	return nil, nil
+}
+
+func (m *mockRetentionClient) GetProjectRetentionID(ctx context.Context, projectID string) (string, error) {
+	if m.getProjectRetentionIDFunc != nil {
+		return m.getProjectRetentionIDFunc(ctx, projectID)
+	}
+	return "", nil
+}
+
+func (m *mockRetentionClient) SetProjectRetentionID(ctx context.Context, projectID, policyID string) error {
+	if m.setProjectRetentionIDFunc != nil {
+		return m.setProjectRetentionIDFunc(ctx, projectID, policyID)
+	}
+	return nil
+}
+
+func (m *mockRetentionClient) ListRetentionExecutions(ctx context.Context, policyID string) ([]*harborclients.RetentionExecution, error) {
+	if m.listRetentionExecutionsFunc != nil {
+		return m.listRetentionExecutionsFunc(ctx, policyID)
+	}
+	return nil, nil
+}
+
+func (m *mockRetentionClient) CountRetentionTasks(ctx context.Context, policyID, executionID string) (*harborclients.RetentionTaskCounts, error) {
+	if m.countRetentionTasksFunc != nil {
+		return m.countRetentionTasksFunc(ctx, policyID, executionID)
+	}
+	return &harborclients.RetentionTaskCounts{}, nil
+}
+
 func (m *mockRetentionClient) Close() error {
 	return nil
 }
@@ -539,6 +672,12 @@ func (m *mockRetentionClient) GetBaseURL() string {
 	return "https://harbor.example.com"
 }
 
+// notFoundError mimics a Harbor SDK 404 response.
+type notFoundError struct{}
+
+func (notFoundError) Error() string        { return "not found" }
+func (notFoundError) IsCode(code int) bool { return code == 404 }
+
 func ptrString(s string) *string {
 	return &s
 }
@@ -546,3 +685,42 @@ func ptrString(s string) *string {
 func ptrBool(b bool) *bool {
 	return &b
 }
+
+func TestObserveRetentionEnabledDefault(t *testing.T) {
+	cases := map[string]struct {
+		enabled  *bool
+		observed bool
+		want     bool
+	}{
+		"Unset":         {enabled: nil, observed: true, want: true},
+		"DisabledMatch": {enabled: ptrBool(false), observed: false, want: true},
+		"EnabledMatch":  {enabled: ptrBool(true), observed: true, want: true},
+		"Disabled":      {enabled: ptrBool(true), observed: false, want: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Retention{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-retention"},
+				Spec: v1beta1.RetentionSpec{ForProvider: v1beta1.RetentionParameters{
+					ProjectID: "project-1",
+					Enabled:   tc.enabled,
+				}},
+			}
+			ext := &external{service: &mockRetentionClient{
+				listRetentionPoliciesFunc: func(context.Context, string) ([]*harborclients.RetentionPolicyStatus, error) {
+					return []*harborclients.RetentionPolicyStatus{{ID: "retention-123", ProjectID: "project-1", Enabled: tc.observed}}, nil
+				},
+				getProjectRetentionIDFunc: func(context.Context, string) (string, error) {
+					return "retention-123", nil
+				},
+			}}
+			obs, err := ext.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Observe() error = %v", err)
+			}
+			if obs.ResourceUpToDate != tc.want {
+				t.Errorf("ResourceUpToDate = %v, want %v", obs.ResourceUpToDate, tc.want)
+			}
+		})
+	}
+}
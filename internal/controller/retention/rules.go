@@ -0,0 +1,84 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package retention
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rossigee/provider-harbor/apis/retention/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+// Triggers of a retention policy.
+const (
+	triggerManual    = "manual"
+	triggerScheduled = "scheduled"
+)
+
+// policySpec returns the retention policy cr describes.
+func policySpec(cr *v1beta1.Retention) *harborclients.RetentionPolicySpec {
+	p := cr.Spec.ForProvider
+	spec := &harborclients.RetentionPolicySpec{ProjectID: p.ProjectID}
+	if p.Trigger == triggerScheduled && p.Schedule != nil {
+		spec.Schedule = normalizeCron(*p.Schedule)
+	}
+	disabled := p.Enabled != nil && !*p.Enabled
+	for _, r := range p.Rules {
+		spec.Rules = append(spec.Rules, harborclients.RetentionPolicyRule{
+			RuleType:            r.RuleType,
+			Value:               ruleValue(r),
+			RepositoryPattern:   harborclients.SelectorPattern(r.RepositorySelectors),
+			ExcludeRepositories: r.ExcludeRepositories != nil && *r.ExcludeRepositories,
+			TagPattern:          harborclients.SelectorPattern(r.TagSelectors),
+			ExcludeTags:         r.ExcludeTags != nil && *r.ExcludeTags,
+			Disabled:            disabled,
+		})
+	}
+	return spec
+}
+
+// ruleValue returns the number of artifacts or days r retains. Rules written
+// before count and days existed give it as their only parameter.
+func ruleValue(r v1beta1.RetentionRule) int64 {
+	switch {
+	case r.RuleType == harborclients.RetentionRuleAlways:
+		return 0
+	case r.Count != nil:
+		return *r.Count
+	case r.Days != nil:
+		return *r.Days
+	}
+	for _, v := range r.Parameters {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// rulesMatch reports whether a and b have the same rules, in any order.
+func rulesMatch(a, b []harborclients.RetentionPolicyRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := map[harborclients.RetentionPolicyRule]int{}
+	for _, r := range a {
+		count[r]++
+	}
+	for _, r := range b {
+		if count[r] == 0 {
+			return false
+		}
+		count[r]--
+	}
+	return true
+}
+
+// normalizeCron collapses the whitespace in a cron expression, which Harbor
+// does not preserve.
+func normalizeCron(cron string) string {
+	return strings.Join(strings.Fields(cron), " ")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package retention
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/retention/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+func TestPolicyUpToDate(t *testing.T) {
+	ten, thirty := int64(10), int64(30)
+	schedule := "0 0 2  * * *"
+	cr := &v1beta1.Retention{Spec: v1beta1.RetentionSpec{ForProvider: v1beta1.RetentionParameters{
+		ProjectID: "5",
+		Trigger:   triggerScheduled,
+		Schedule:  &schedule,
+		Rules: []v1beta1.RetentionRule{
+			{RuleType: "latestPushedK", Count: &ten, RepositorySelectors: []string{"team-b/**", "team-a/**"}},
+			{RuleType: "daysSinceLastPull", Days: &thirty, TagSelectors: []string{"release-*"}},
+		},
+	}}}
+
+	// Harbor's copy has the rules in the other order and the repository
+	// patterns in the order the provider combines them in.
+	observed := &harborclients.RetentionPolicyStatus{
+		ID:        "1",
+		ProjectID: "5",
+		Enabled:   true,
+		Schedule:  "0 0 2 * * *",
+		Rules: []harborclients.RetentionPolicyRule{
+			{RuleType: "daysSinceLastPull", Value: 30, RepositoryPattern: "**", TagPattern: "release-*"},
+			{RuleType: "latestPushedK", Value: 10, RepositoryPattern: "{team-a/**,team-b/**}", TagPattern: "**"},
+		},
+	}
+	if !policyUpToDate(cr, observed) {
+		t.Errorf("policyUpToDate() = false for reordered rules %+v", policySpec(cr).Rules)
+	}
+
+	cr.Spec.ForProvider.Rules[0], cr.Spec.ForProvider.Rules[1] = cr.Spec.ForProvider.Rules[1], cr.Spec.ForProvider.Rules[0]
+	if !policyUpToDate(cr, observed) {
+		t.Error("policyUpToDate() = false after reordering the desired rules")
+	}
+
+	cr.Spec.ForProvider.Rules[0].ExcludeTags = ptrBool(true)
+	if policyUpToDate(cr, observed) {
+		t.Error("policyUpToDate() = true although a rule excludes its tags")
+	}
+}
+
+func TestPolicySpec(t *testing.T) {
+	schedule := "0 0 2 * * *"
+	cr := &v1beta1.Retention{Spec: v1beta1.RetentionSpec{ForProvider: v1beta1.RetentionParameters{
+		ProjectID: "5",
+		Trigger:   triggerManual,
+		Schedule:  &schedule,
+		Enabled:   ptrBool(false),
+		Rules: []v1beta1.RetentionRule{
+			{RuleType: "always"},
+			{RuleType: "latestPulledN", Parameters: map[string]string{"n": "3"}},
+		},
+	}}}
+
+	want := &harborclients.RetentionPolicySpec{
+		ProjectID: "5",
+		Rules: []harborclients.RetentionPolicyRule{
+			{RuleType: "always", RepositoryPattern: "**", TagPattern: "**", Disabled: true},
+			{RuleType: "latestPulledN", Value: 3, RepositoryPattern: "**", TagPattern: "**", Disabled: true},
+		},
+	}
+	if got := policySpec(cr); !reflect.DeepEqual(got, want) {
+		t.Errorf("policySpec() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+)
+
+const keyRobotTokenDuration = "robot_token_duration"
+
+// expiresIn returns the number of days to request for cr's robot account,
+// and sets the ExpiryWithinLimit condition. Harbor lowers a duration above
+// its robot_token_duration setting without saying so, which would leave the
+// Robot forever out of date, so such a duration is either clamped here or
+// rejected, as cr's expiryPolicy says. A robot account that never expires
+// is not capped.
+func (c *external) expiresIn(ctx context.Context, cr *v1beta1.Robot) (*int64, error) {
+	requested := cr.Spec.ForProvider.ExpiresIn
+	if requested == nil || *requested < 0 {
+		cr.SetConditions(v1beta1.ExpiryWithinLimit())
+		return requested, nil
+	}
+	maximum, ok := c.robotTokenDuration(ctx)
+	if !ok || *requested <= maximum {
+		cr.SetConditions(v1beta1.ExpiryWithinLimit())
+		return requested, nil
+	}
+	if p := cr.Spec.ForProvider.ExpiryPolicy; p != nil && *p == v1beta1.ExpiryPolicyClamp {
+		cr.SetConditions(v1beta1.ExpiryClamped(*requested, maximum))
+		return &maximum, nil
+	}
+	cond := v1beta1.ExpiryExceedsMaximum(*requested, maximum)
+	cr.SetConditions(cond)
+	return nil, errors.New(cond.Message)
+}
+
+// robotTokenDuration returns Harbor's maximum robot account duration in
+// days. It is false when the setting cannot be read, which needs a system
+// admin account, or when there is no maximum.
+func (c *external) robotTokenDuration(ctx context.Context) (int64, bool) {
+	cfg, err := c.service.GetConfigurations(ctx)
+	if err != nil {
+		return 0, false
+	}
+	// Configurations decode from JSON, so numbers are float64.
+	d, ok := cfg[keyRobotTokenDuration].(float64)
+	if !ok || d <= 0 {
+		return 0, false
+	}
+	return int64(d), true
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestExpiresIn(t *testing.T) {
+	cases := map[string]struct {
+		expiresIn     *int64
+		policy        *string
+		configuration harborclients.Configurations
+		want          *int64
+		wantErr       bool
+		wantStatus    corev1.ConditionStatus
+		wantReason    string
+	}{
+		"NeverExpires": {
+			expiresIn:     ptrInt64(-1),
+			configuration: harborclients.Configurations{keyRobotTokenDuration: float64(30)},
+			want:          ptrInt64(-1),
+			wantStatus:    corev1.ConditionTrue,
+			wantReason:    string(v1beta1.ReasonExpiryWithinLimit),
+		},
+		"WithinLimit": {
+			expiresIn:     ptrInt64(30),
+			configuration: harborclients.Configurations{keyRobotTokenDuration: float64(30)},
+			want:          ptrInt64(30),
+			wantStatus:    corev1.ConditionTrue,
+			wantReason:    string(v1beta1.ReasonExpiryWithinLimit),
+		},
+		"LimitUnreadable": {
+			expiresIn:  ptrInt64(365),
+			want:       ptrInt64(365),
+			wantStatus: corev1.ConditionTrue,
+			wantReason: string(v1beta1.ReasonExpiryWithinLimit),
+		},
+		"Rejected": {
+			expiresIn:     ptrInt64(365),
+			configuration: harborclients.Configurations{keyRobotTokenDuration: float64(30)},
+			wantErr:       true,
+			wantStatus:    corev1.ConditionFalse,
+			wantReason:    string(v1beta1.ReasonExpiryExceedsMaximum),
+		},
+		"Clamped": {
+			expiresIn:     ptrInt64(365),
+			policy:        ptrString(v1beta1.ExpiryPolicyClamp),
+			configuration: harborclients.Configurations{keyRobotTokenDuration: float64(30)},
+			want:          ptrInt64(30),
+			wantStatus:    corev1.ConditionFalse,
+			wantReason:    string(v1beta1.ReasonExpiryClamped),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &external{service: &mockRobotClient{configurations: tc.configuration}}
+			cr := &v1beta1.Robot{}
+			cr.Spec.ForProvider.ExpiresIn = tc.expiresIn
+			cr.Spec.ForProvider.ExpiryPolicy = tc.policy
+
+			got, err := c.expiresIn(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expiresIn() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			switch {
+			case (got == nil) != (tc.want == nil):
+				t.Errorf("expiresIn() = %v, want %v", got, tc.want)
+			case got != nil && *got != *tc.want:
+				t.Errorf("expiresIn() = %d, want %d", *got, *tc.want)
+			}
+			cond := cr.GetCondition(v1beta1.TypeExpiryWithinLimit)
+			if cond.Status != tc.wantStatus || string(cond.Reason) != tc.wantReason {
+				t.Errorf("ExpiryWithinLimit = %s/%s, want %s/%s", cond.Status, cond.Reason, tc.wantStatus, tc.wantReason)
+			}
+		})
+	}
+}
+
+func TestCreateRejectsExpiryOverMaximum(t *testing.T) {
+	mock := &mockRobotClient{
+		configurations: harborclients.Configurations{keyRobotTokenDuration: float64(30)},
+		createRobotFunc: func(_ context.Context, _ *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+			t.Fatal("CreateRobot called with an expiresIn over Harbor's maximum")
+			return nil, nil
+		},
+	}
+	cr := &v1beta1.Robot{}
+	cr.Spec.ForProvider.Name = "ci"
+	cr.Spec.ForProvider.ExpiresIn = ptrInt64(90)
+
+	if _, err := (&external{service: mock}).Create(context.Background(), cr); err == nil {
+		t.Fatal("Create() error = nil, want the expiry rejected")
+	}
+}
+
+func TestUpdateClampsExpiry(t *testing.T) {
+	var sent *int64
+	mock := &mockRobotClient{
+		configurations: harborclients.Configurations{keyRobotTokenDuration: float64(30)},
+		updateRobotFunc: func(_ context.Context, _ string, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+			sent = spec.ExpiresIn
+			return &harborclients.RobotStatus{}, nil
+		},
+	}
+	cr := &v1beta1.Robot{}
+	cr.Spec.ForProvider.ExpiresIn = ptrInt64(90)
+	cr.Spec.ForProvider.ExpiryPolicy = ptrString(v1beta1.ExpiryPolicyClamp)
+	cr.Status.AtProvider.ID = ptrString("7")
+
+	if _, err := (&external{service: mock}).Update(context.Background(), cr); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if sent == nil || *sent != 30 {
+		t.Errorf("UpdateRobot() expiresIn = %v, want 30", sent)
+	}
+}
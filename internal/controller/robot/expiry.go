@@ -0,0 +1,102 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultExpiryWarning is how long before a robot account expires a warning
+// event is emitted.
+const DefaultExpiryWarning = 7 * 24 * time.Hour
+
+const reasonExpiringSoon event.Reason = "RobotExpiringSoon"
+
+var (
+	expiryWarningMu sync.Mutex
+	expiryWarning   = DefaultExpiryWarning
+)
+
+// SetExpiryWarning changes how long before a robot account expires a
+// warning event is emitted. Zero or less disables the events; the expiry
+// metric is always exported.
+func SetExpiryWarning(d time.Duration) {
+	expiryWarningMu.Lock()
+	defer expiryWarningMu.Unlock()
+	expiryWarning = d
+}
+
+func getExpiryWarning() time.Duration {
+	expiryWarningMu.Lock()
+	defer expiryWarningMu.Unlock()
+	return expiryWarning
+}
+
+// expiryWarnings remembers which expiry each Robot has been warned about,
+// so that the frequent polls of a robot in its warning window emit one
+// event rather than one per poll. A restart may warn once more.
+type expiryWarnings struct {
+	mu     sync.Mutex
+	warned map[types.UID]time.Time
+}
+
+var warnings = &expiryWarnings{warned: map[types.UID]time.Time{}}
+
+// shouldWarn reports whether a warning about expiresAt has yet to be emitted
+// for uid, and records that it has.
+func (w *expiryWarnings) shouldWarn(uid types.UID, expiresAt time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if last, ok := w.warned[uid]; ok && last.Equal(expiresAt) {
+		return false
+	}
+	w.warned[uid] = expiresAt
+	return true
+}
+
+func (w *expiryWarnings) forget(uid types.UID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.warned, uid)
+}
+
+// observeExpiry exports when the robot account expires and warns once when
+// it is about to.
+func (c *external) observeExpiry(cr *v1beta1.Robot, robot *harborclients.RobotStatus, now time.Time) {
+	if robot.ExpiresAt == nil {
+		metrics.DeleteRobotExpiry(cr.GetNamespace(), cr.GetName())
+		warnings.forget(cr.GetUID())
+		return
+	}
+	expiresAt := *robot.ExpiresAt
+	metrics.SetRobotExpiry(cr.GetNamespace(), cr.GetName(), robot.Name, expiresAt)
+
+	window := getExpiryWarning()
+	left := expiresAt.Sub(now)
+	if window <= 0 || left > window || c.recorder == nil || !warnings.shouldWarn(cr.GetUID(), expiresAt) {
+		return
+	}
+	msg := "robot account %s expires in %s, at %s"
+	if left <= 0 {
+		msg = "robot account %s expired %s ago, at %s"
+		left = -left
+	}
+	c.recorder.Event(cr, event.Warning(reasonExpiringSoon,
+		errors.Errorf(msg, robot.Name, left.Round(time.Minute), expiresAt.UTC().Format(time.RFC3339))))
+}
+
+// forgetExpiry drops the expiry metric and warning state of a deleted Robot.
+func forgetExpiry(cr *v1beta1.Robot) {
+	metrics.DeleteRobotExpiry(cr.GetNamespace(), cr.GetName())
+	warnings.forget(cr.GetUID())
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type recordedEvents []event.Event
+
+func (r *recordedEvents) Event(_ runtime.Object, e event.Event)    { *r = append(*r, e) }
+func (r *recordedEvents) WithAnnotations(...string) event.Recorder { return r }
+
+func TestObserveExpiry(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+
+	cases := map[string]struct {
+		expiresAt  *time.Time
+		polls      int
+		wantEvents int
+		wantMsg    string
+		wantSeries int
+	}{
+		"NeverExpires": {
+			polls: 1,
+		},
+		"FarFromExpiry": {
+			expiresAt:  at(30 * 24 * time.Hour),
+			polls:      1,
+			wantSeries: 1,
+		},
+		"InWarningWindow": {
+			expiresAt:  at(3 * 24 * time.Hour),
+			polls:      5,
+			wantEvents: 1,
+			wantMsg:    "expires in 72h0m0s",
+			wantSeries: 1,
+		},
+		"Expired": {
+			expiresAt:  at(-time.Hour),
+			polls:      1,
+			wantEvents: 1,
+			wantMsg:    "expired 1h0m0s ago",
+			wantSeries: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Robot{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: name, UID: types.UID(name)}}
+			t.Cleanup(func() { forgetExpiry(cr) })
+			rec := &recordedEvents{}
+			ext := &external{recorder: rec}
+			robot := &harborclients.RobotStatus{Name: "robot$ci+" + name, ExpiresAt: tc.expiresAt}
+
+			for i := 0; i < tc.polls; i++ {
+				ext.observeExpiry(cr, robot, now)
+			}
+
+			if len(*rec) != tc.wantEvents {
+				t.Fatalf("emitted %d events, want %d", len(*rec), tc.wantEvents)
+			}
+			if tc.wantEvents > 0 {
+				e := (*rec)[0]
+				if e.Type != event.TypeWarning || !strings.Contains(e.Message, tc.wantMsg) {
+					t.Errorf("event = %+v, want a warning containing %q", e, tc.wantMsg)
+				}
+			}
+			if got := testutil.CollectAndCount(metrics.RobotExpiry); got < tc.wantSeries {
+				t.Errorf("exported %d expiry series, want at least %d", got, tc.wantSeries)
+			}
+			if tc.expiresAt != nil {
+				want := float64(tc.expiresAt.Unix())
+				if got := testutil.ToFloat64(metrics.RobotExpiry.WithLabelValues("ci", name, robot.Name)); got != want {
+					t.Errorf("expiry metric = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestObserveExpiryRenewed(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cr := &v1beta1.Robot{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "renewed", UID: "renewed"}}
+	t.Cleanup(func() { forgetExpiry(cr) })
+	rec := &recordedEvents{}
+	ext := &external{recorder: rec}
+
+	first, second := now.Add(time.Hour), now.Add(2*time.Hour)
+	ext.observeExpiry(cr, &harborclients.RobotStatus{Name: "robot$old", ExpiresAt: &first}, now)
+	ext.observeExpiry(cr, &harborclients.RobotStatus{Name: "robot$new", ExpiresAt: &second}, now)
+
+	if len(*rec) != 2 {
+		t.Errorf("emitted %d events, want one per expiry", len(*rec))
+	}
+	if got := testutil.CollectAndCount(metrics.RobotExpiry.MustCurryWith(map[string]string{"namespace": "ci", "name": "renewed"})); got != 1 {
+		t.Errorf("exported %d series for the renamed robot, want 1", got)
+	}
+}
+
+func TestExpiryWarningDisabled(t *testing.T) {
+	SetExpiryWarning(0)
+	t.Cleanup(func() { SetExpiryWarning(DefaultExpiryWarning) })
+
+	now := time.Now()
+	soon := now.Add(time.Minute)
+	cr := &v1beta1.Robot{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "disabled", UID: "disabled"}}
+	t.Cleanup(func() { forgetExpiry(cr) })
+	rec := &recordedEvents{}
+	(&external{recorder: rec}).observeExpiry(cr, &harborclients.RobotStatus{Name: "robot$x", ExpiresAt: &soon}, now)
+
+	if len(*rec) != 0 {
+		t.Errorf("emitted %d events with warnings disabled", len(*rec))
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+)
+
+// checkPermissions sets the PermissionsAllowed condition, and returns an
+// error if Harbor does not let robot accounts have some of the permissions
+// cr requests. Harbor would reject the whole request with a message naming
+// none of them. Harbor releases that cannot restrict robot permissions, and
+// accounts that cannot read the restrictions, are not checked.
+func (c *external) checkPermissions(ctx context.Context, cr *v1beta1.Robot) error {
+	allowed, err := c.service.GetRobotPermissions(ctx)
+	if err != nil || allowed == nil || len(allowed.Project) == 0 {
+		return nil
+	}
+	disallowed := allowed.Disallowed(convertPermissions(cr.Spec.ForProvider.Permissions))
+	if len(disallowed) == 0 {
+		cr.SetConditions(v1beta1.PermissionsAllowed())
+		return nil
+	}
+	cond := v1beta1.PermissionsDisallowed(disallowed)
+	cr.SetConditions(cond)
+	return errors.New(cond.Message)
+}
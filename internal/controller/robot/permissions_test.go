@@ -0,0 +1,83 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// pullOnly lets robot accounts pull from and list repositories only.
+var pullOnly = &harborclients.RobotPermissions{
+	System: []harborclients.RobotAccess{{Resource: "project", Action: "create"}},
+	Project: []harborclients.RobotAccess{
+		{Resource: "repository", Action: "pull"},
+		{Resource: "repository", Action: "list"},
+	},
+}
+
+func TestCheckPermissions(t *testing.T) {
+	cases := map[string]struct {
+		allowed     *harborclients.RobotPermissions
+		access      []string
+		wantErr     bool
+		wantStatus  corev1.ConditionStatus
+		wantMessage string
+	}{
+		"Allowed": {
+			allowed:    pullOnly,
+			access:     []string{"pull", "list"},
+			wantStatus: corev1.ConditionTrue,
+		},
+		"Disallowed": {
+			allowed:     pullOnly,
+			access:      []string{"push", "pull", "delete", "push"},
+			wantErr:     true,
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: "Harbor does not allow robot accounts these permissions: repository:delete, repository:push",
+		},
+		"Unrestricted": {
+			access:     []string{"push"},
+			wantStatus: corev1.ConditionUnknown,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Robot{}
+			cr.Spec.ForProvider.Permissions = []v1beta1.RobotPermission{{Namespace: "library", Access: tc.access}}
+			e := &external{service: &mockRobotClient{permissions: tc.allowed}}
+
+			err := e.checkPermissions(context.Background(), cr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("checkPermissions() error = %v, want error: %v", err, tc.wantErr)
+			}
+			cond := cr.GetCondition(v1beta1.TypePermissionsAllowed)
+			if cond.Status != tc.wantStatus || cond.Message != tc.wantMessage {
+				t.Errorf("PermissionsAllowed = %s %q, want %s %q", cond.Status, cond.Message, tc.wantStatus, tc.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCreateRejectsDisallowedPermissions(t *testing.T) {
+	mock := &mockRobotClient{
+		permissions: pullOnly,
+		createRobotFunc: func(_ context.Context, _ *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+			t.Fatal("CreateRobot called with permissions Harbor does not allow")
+			return nil, nil
+		},
+	}
+	cr := &v1beta1.Robot{}
+	cr.Spec.ForProvider.Name = "ci"
+	cr.Spec.ForProvider.Permissions = []v1beta1.RobotPermission{{Namespace: "library", Access: []string{"push"}}}
+
+	if _, err := (&external{service: mock}).Create(context.Background(), cr); err == nil {
+		t.Fatal("Create() error = nil, want the permissions rejected")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	errRenewDelete = "cannot delete expiring Harbor robot account"
+	errRenewCreate = "cannot recreate expiring Harbor robot account"
+
+	reasonRenewed event.Reason = "RobotRenewed"
+)
+
+// renewalDue reports whether cr's robot account expires within its
+// renewBefore window. A robot account whose whole lifetime is shorter than
+// the window is never due, as its replacement would be due at once too.
+func renewalDue(cr *v1beta1.Robot, expiresAt, createdAt *time.Time, now time.Time) bool {
+	rb := cr.Spec.ForProvider.RenewBefore
+	if rb == nil || rb.Duration <= 0 || expiresAt == nil {
+		return false
+	}
+	if createdAt != nil && !createdAt.IsZero() && expiresAt.Sub(*createdAt) <= rb.Duration {
+		return false
+	}
+	return !now.Before(expiresAt.Add(-rb.Duration))
+}
+
+// renew replaces cr's expiring robot account with a new one created from
+// spec, and returns the new credentials. Harbor neither extends a robot
+// account's expiry nor allows two robot accounts with the same name, so the
+// old one is deleted first and its secret stops working.
+func (c *external) renew(ctx context.Context, cr *v1beta1.Robot, spec *harborclients.RobotSpec, now time.Time) (managed.ExternalUpdate, error) {
+	if err := c.service.DeleteRobot(ctx, *cr.Status.AtProvider.ID); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errRenewDelete)
+	}
+	robot, err := c.service.CreateRobot(ctx, spec)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errRenewCreate)
+	}
+
+	ctrlutil.SetExternalName(cr, robot.Name)
+	cr.Status.AtProvider.ID = &robot.ID
+	cr.Status.AtProvider.ExpiresAt = nil
+	if robot.ExpiresAt != nil {
+		et := metav1.NewTime(*robot.ExpiresAt)
+		cr.Status.AtProvider.ExpiresAt = &et
+	}
+	renewed := metav1.NewTime(now)
+	cr.Status.AtProvider.LastRenewalTime = &renewed
+
+	if c.recorder != nil {
+		msg := fmt.Sprintf("Replaced expiring robot account %s", robot.Name)
+		if robot.ExpiresAt != nil {
+			msg += "; the new one expires at " + robot.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		c.recorder.Event(cr, event.Normal(reasonRenewed, msg))
+	}
+	return managed.ExternalUpdate{ConnectionDetails: connectionDetails(robot)}, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenewalDue(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+	week := &metav1.Duration{Duration: 7 * 24 * time.Hour}
+
+	cases := map[string]struct {
+		renewBefore *metav1.Duration
+		expiresAt   *time.Time
+		createdAt   *time.Time
+		want        bool
+	}{
+		"NoRenewBefore":     {expiresAt: at(time.Hour), createdAt: at(-90 * 24 * time.Hour)},
+		"NeverExpires":      {renewBefore: week, createdAt: at(-90 * 24 * time.Hour)},
+		"OutsideWindow":     {renewBefore: week, expiresAt: at(8 * 24 * time.Hour), createdAt: at(-80 * 24 * time.Hour)},
+		"InsideWindow":      {renewBefore: week, expiresAt: at(6 * 24 * time.Hour), createdAt: at(-80 * 24 * time.Hour), want: true},
+		"Expired":           {renewBefore: week, expiresAt: at(-time.Hour), createdAt: at(-80 * 24 * time.Hour), want: true},
+		"LifetimeTooShort":  {renewBefore: week, expiresAt: at(time.Hour), createdAt: at(-5 * 24 * time.Hour)},
+		"CreationTimeUnset": {renewBefore: week, expiresAt: at(time.Hour), want: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Robot{}
+			cr.Spec.ForProvider.RenewBefore = tc.renewBefore
+			if got := renewalDue(cr, tc.expiresAt, tc.createdAt, now); got != tc.want {
+				t.Errorf("renewalDue() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateRenewsExpiringRobot(t *testing.T) {
+	oldID := "7"
+	expires := metav1.NewTime(time.Now().Add(24 * time.Hour))
+	created := metav1.NewTime(time.Now().Add(-89 * 24 * time.Hour))
+	days := int64(90)
+	cr := &v1beta1.Robot{
+		ObjectMeta: metav1.ObjectMeta{Name: "ci"},
+		Spec: v1beta1.RobotSpec{ForProvider: v1beta1.RobotParameters{
+			Name:        "ci",
+			ExpiresIn:   &days,
+			RenewBefore: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			Permissions: []v1beta1.RobotPermission{{Namespace: "library", Access: []string{"pull"}}},
+		}},
+		Status: v1beta1.RobotStatus{AtProvider: v1beta1.RobotObservation{ID: &oldID, ExpiresAt: &expires, CreationTime: &created}},
+	}
+
+	var calls []string
+	newExpiry := time.Now().Add(90 * 24 * time.Hour)
+	events := &recordedEvents{}
+	ext := &external{
+		recorder: events,
+		service: &mockRobotClient{
+			deleteRobotFunc: func(_ context.Context, id string) error {
+				calls = append(calls, "delete "+id)
+				return nil
+			},
+			createRobotFunc: func(_ context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+				calls = append(calls, "create "+spec.Name)
+				return &harborclients.RobotStatus{ID: "8", Name: "robot$ci", Secret: "fresh", ExpiresAt: &newExpiry}, nil
+			},
+			updateRobotFunc: func(context.Context, string, *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+				t.Error("UpdateRobot called for a robot due for renewal")
+				return nil, nil
+			},
+		},
+	}
+
+	got, err := ext.Update(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "delete 7" || calls[1] != "create ci" {
+		t.Errorf("calls = %v, want delete then create", calls)
+	}
+	if string(got.ConnectionDetails[connectionKeyPassword]) != "fresh" {
+		t.Errorf("password = %q, want the new secret", got.ConnectionDetails[connectionKeyPassword])
+	}
+	obs := cr.Status.AtProvider
+	if *obs.ID != "8" || obs.LastRenewalTime == nil || !obs.ExpiresAt.Time.Equal(newExpiry) {
+		t.Errorf("status = %+v, want the new robot account", obs)
+	}
+	if len(*events) != 1 || (*events)[0].Reason != reasonRenewed {
+		t.Errorf("events = %+v, want one %s", *events, reasonRenewed)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package robot
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	"github.com/rossigee/provider-harbor/internal/controller/testing/fakeharbor"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reconcileRobot runs one Observe/Create/Update pass the way the managed
+// reconciler does, with a short deadline so injected timeouts fail fast.
+func reconcileRobot(e *external, cr *v1beta1.Robot) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	if !obs.ResourceExists {
+		_, err := e.Create(ctx, cr)
+		return false, err
+	}
+	if !obs.ResourceUpToDate {
+		_, err := e.Update(ctx, cr)
+		return false, err
+	}
+	return true, nil
+}
+
+func TestRobotConvergesUnderAPIFlaps(t *testing.T) {
+	cases := map[string]struct {
+		projectID   *string
+		permissions []v1beta1.RobotPermission
+		wantName    string
+	}{
+		"SystemRobot": {
+			wantName: "robot$ci",
+		},
+		"ProjectRobot": {
+			projectID:   ptrString("1"),
+			permissions: []v1beta1.RobotPermission{{Namespace: "library", Access: []string{"pull"}}},
+			wantName:    "robot$library+ci",
+		},
+	}
+
+	for name, tc := range cases {
+		for seed := int64(1); seed <= 5; seed++ {
+			t.Run(fmt.Sprintf("%s/seed-%d", name, seed), func(t *testing.T) {
+				srv := fakeharbor.New(t, fakeharbor.Faults{
+					RateLimit:    0.15,
+					ServerError:  0.15,
+					Timeout:      0.1,
+					LostResponse: 0.5,
+					Seed:         seed,
+				})
+				svc, err := harborclients.NewHarborClient(&harborclients.HarborConfig{
+					URL:      srv.URL,
+					Username: fakeharbor.Username,
+					Password: fakeharbor.Password,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				cr := &v1beta1.Robot{
+					ObjectMeta: metav1.ObjectMeta{Name: "ci"},
+					Spec: v1beta1.RobotSpec{
+						ForProvider: v1beta1.RobotParameters{
+							Name:        "ci",
+							ProjectID:   tc.projectID,
+							Permissions: tc.permissions,
+						},
+					},
+				}
+				e := &external{service: svc}
+
+				converged := false
+				for i := 0; i < 60; i++ {
+					if ok, _ := reconcileRobot(e, cr); ok {
+						converged = true
+					}
+				}
+
+				if !converged {
+					t.Error("robot never converged")
+				}
+				if names := srv.RobotNames(); len(names) != 1 || names[0] != tc.wantName {
+					t.Errorf("robots in Harbor = %v, want exactly [%s]", names, tc.wantName)
+				}
+				if _, injected := srv.Stats(); injected == 0 {
+					t.Error("no faults were injected")
+				}
+			})
+		}
+	}
+}
@@ -7,6 +7,7 @@ package robot
 import (
 	"context"
 	"fmt"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
@@ -22,7 +23,6 @@ import (
 	"os"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"strings"
 	"time"
 )
@@ -31,6 +31,10 @@ const (
 	errNotRobot    = "managed resource is not a Robot custom resource"
 	errRobotDelete = "cannot delete Harbor robot"
 	errNewClient   = "cannot create new Harbor client"
+
+	connectionKeyUsername = "username"
+	connectionKeyPassword = "password"
+	connectionKeyRobotID  = "robot_id"
 )
 
 func Setup(mgr ctrl.Manager, o controller.Options) error {
@@ -39,37 +43,56 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	fmt.Fprintf(os.Stderr, "DEBUG: Robot controller Setup called\n")
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.RobotGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorder(name))
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithProtection(mgr.GetClient(), robotTarget, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
 			logger:       log,
-		}),
+			recorder:     recorder,
+		}))))))))),
 		managed.WithLogger(log),
-		managed.WithPollInterval(10*time.Second),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(10 * time.Second),
+		managed.WithRecorder(recorder),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.RobotGroupVersionKind), opts...)
 
 	fmt.Fprintf(os.Stderr, "DEBUG: Robot reconciler created, building controller\n")
 
 	builder := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		WithEventFilter(resource.DesiredStateChanged()).
 		For(&v1beta1.Robot{})
 
 	fmt.Fprintf(os.Stderr, "DEBUG: Robot controller builder ready, completing with ratelimiter\n")
 
-	err := builder.Complete(ratelimiter.NewReconciler(name, r, ratelimiter.NewGlobal(10)))
+	err := builder.Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 
 	fmt.Fprintf(os.Stderr, "DEBUG: Robot controller Setup completed with error: %v\n", err)
 	return err
 }
 
+// robotTarget returns the robot account a Robot is, and its project.
+func robotTarget(mg resource.Managed) ctrlutil.Target {
+	cr, ok := mg.(*v1beta1.Robot)
+	if !ok {
+		return ctrlutil.Target{}
+	}
+	t := ctrlutil.Target{Robot: cr.Spec.ForProvider.Name}
+	if cr.Spec.ForProvider.ProjectID != nil {
+		t.Project = *cr.Spec.ForProvider.ProjectID
+	}
+	return t
+}
+
 type connector struct {
 	kube         client.Client
 	newServiceFn func(context.Context, client.Client, resource.Managed) (harborclients.HarborClienter, error)
 	logger       logging.Logger
+	recorder     event.Recorder
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -83,12 +106,13 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{service: svc, logger: c.logger}, nil
+	return &external{service: svc, logger: c.logger, recorder: c.recorder}, nil
 }
 
 type external struct {
-	service harborclients.HarborClienter
-	logger  logging.Logger
+	service  harborclients.HarborClienter
+	logger   logging.Logger
+	recorder event.Recorder
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -103,11 +127,68 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe called for %s, desiredName=%s\n", cr.Name, cr.Spec.ForProvider.Name)
 
+	robot, err := c.findRobot(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if robot == nil {
+		fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe not found, will need to create\n")
+		forgetExpiry(cr)
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe FOUND %s id=%s\n", robot.Name, robot.ID)
+
+	// Set external name for adoption tracking
+	ctrlutil.SetExternalName(cr, robot.Name)
+
+	cr.Status.AtProvider.ID = &robot.ID
+	if robot.Secret != "" {
+		cr.Status.AtProvider.Secret = &robot.Secret
+	}
+	if robot.ExpiresAt != nil {
+		et := metav1.NewTime(*robot.ExpiresAt)
+		cr.Status.AtProvider.ExpiresAt = &et
+	}
+	c.observeExpiry(cr, robot, time.Now())
+	t := metav1.NewTime(robot.CreationTime)
+	cr.Status.AtProvider.CreationTime = &t
+	ut := metav1.NewTime(robot.UpdateTime)
+	cr.Status.AtProvider.UpdateTime = &ut
+
+	upToDate := true
+	if cr.Spec.ForProvider.Description != nil && robot.Description != nil && *cr.Spec.ForProvider.Description != *robot.Description {
+		upToDate = false
+	}
+	if cr.Spec.ForProvider.ProjectID != nil && robot.ProjectID != nil && *cr.Spec.ForProvider.ProjectID != *robot.ProjectID {
+		upToDate = false
+	}
+	if renewalDue(cr, robot.ExpiresAt, &robot.CreationTime, time.Now()) {
+		upToDate = false
+	}
+
+	// Report an expiresIn or permissions Harbor would not accept before an
+	// update fails; the ExpiryWithinLimit and PermissionsAllowed conditions
+	// carry the errors.
+	_, _ = c.expiresIn(ctx, cr)
+	_ = c.checkPermissions(ctx, cr)
+
+	fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe returning exists=true, upToDate=%v\n", upToDate)
+
+	// Set the Ready condition to True since we found the resource
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate, ConnectionDetails: connectionDetails(robot)}, nil
+}
+
+// findRobot returns the Harbor robot account cr manages, or nil if there is
+// none.
+func (c *external) findRobot(ctx context.Context, cr *v1beta1.Robot) (*harborclients.RobotStatus, error) {
 	// Get robot by name (simplified - Harbor API would need the robot ID)
 	robots, err := c.service.ListRobots(ctx, cr.Spec.ForProvider.ProjectID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe error calling ListRobots: %v\n", err)
-		return managed.ExternalObservation{}, err
+		return nil, err
 	}
 
 	fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe got %d robots\n", len(robots))
@@ -129,44 +210,11 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	for _, robot := range robots {
 		fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe checking %s\n", robot.Name)
 		// Also check without prefix in case the name was stored differently
-		if robot.Name == searchName || robot.Name == cr.Spec.ForProvider.Name {
-			fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe FOUND %s id=%s\n", robot.Name, robot.ID)
-
-			// Set external name for adoption tracking
-			ctrlutil.SetExternalName(cr, robot.Name)
-
-			cr.Status.AtProvider.ID = &robot.ID
-			if robot.Secret != "" {
-				cr.Status.AtProvider.Secret = &robot.Secret
-			}
-			if robot.ExpiresAt != nil {
-				et := metav1.NewTime(*robot.ExpiresAt)
-				cr.Status.AtProvider.ExpiresAt = &et
-			}
-			t := metav1.NewTime(robot.CreationTime)
-			cr.Status.AtProvider.CreationTime = &t
-			ut := metav1.NewTime(robot.UpdateTime)
-			cr.Status.AtProvider.UpdateTime = &ut
-
-			upToDate := true
-			if cr.Spec.ForProvider.Description != nil && robot.Description != nil && *cr.Spec.ForProvider.Description != *robot.Description {
-				upToDate = false
-			}
-			if cr.Spec.ForProvider.ProjectID != nil && robot.ProjectID != nil && *cr.Spec.ForProvider.ProjectID != *robot.ProjectID {
-				upToDate = false
-			}
-
-			fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe returning exists=true, upToDate=%v\n", upToDate)
-
-			// Set the Ready condition to True since we found the resource
-			cr.SetConditions(xpv1.Available())
-
-			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate}, nil
+		if robot.Name == searchName || robot.Name == cr.Spec.ForProvider.Name || isProjectRobot(robot.Name, cr.Spec.ForProvider.Name) {
+			return robot, nil
 		}
 	}
-
-	fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Observe not found, will need to create\n")
-	return managed.ExternalObservation{ResourceExists: false}, nil
+	return nil, nil
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -181,16 +229,28 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Create called for %s\n", cr.Name)
 
+	expiresIn, err := c.expiresIn(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if err := c.checkPermissions(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
 	spec := &harborclients.RobotSpec{
 		Name:        cr.Spec.ForProvider.Name,
 		Description: cr.Spec.ForProvider.Description,
 		ProjectID:   cr.Spec.ForProvider.ProjectID,
-		ExpiresIn:   cr.Spec.ForProvider.ExpiresIn,
+		ExpiresIn:   expiresIn,
 		Permissions: convertPermissions(cr.Spec.ForProvider.Permissions),
 	}
 
 	fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Create calling Harbor API for %s\n", cr.Spec.ForProvider.Name)
-	robot, err := c.service.CreateRobot(ctx, spec)
+	// Harbor accepts a second robot with the same name when the first
+	// request's response was lost, so look before and after creating.
+	robot, err := ctrlutil.CreateOnce(ctx,
+		func(ctx context.Context) (*harborclients.RobotStatus, error) { return c.findRobot(ctx, cr) },
+		func(ctx context.Context) (*harborclients.RobotStatus, error) { return c.service.CreateRobot(ctx, spec) })
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Create error: %v\n", err)
 		return managed.ExternalCreation{}, err
@@ -200,7 +260,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	ctrlutil.SetExternalName(cr, robot.Name)
 
 	fmt.Fprintf(os.Stderr, "DEBUG_ROBOT: Create succeeded for %s\n", cr.Name)
-	return managed.ExternalCreation{}, nil
+	return managed.ExternalCreation{ConnectionDetails: connectionDetails(robot)}, nil
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -217,15 +277,34 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New("robot ID not set")
 	}
 
+	expiresIn, err := c.expiresIn(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	if err := c.checkPermissions(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
 	spec := &harborclients.RobotSpec{
 		Name:        cr.Spec.ForProvider.Name,
 		Description: cr.Spec.ForProvider.Description,
 		ProjectID:   cr.Spec.ForProvider.ProjectID,
-		ExpiresIn:   cr.Spec.ForProvider.ExpiresIn,
+		ExpiresIn:   expiresIn,
 		Permissions: convertPermissions(cr.Spec.ForProvider.Permissions),
 	}
 
-	_, err := c.service.UpdateRobot(ctx, *cr.Status.AtProvider.ID, spec)
+	var expiresAt, createdAt *time.Time
+	if t := cr.Status.AtProvider.ExpiresAt; t != nil {
+		expiresAt = &t.Time
+	}
+	if t := cr.Status.AtProvider.CreationTime; t != nil {
+		createdAt = &t.Time
+	}
+	if now := time.Now(); renewalDue(cr, expiresAt, createdAt, now) {
+		return c.renew(ctx, cr, spec, now)
+	}
+
+	_, err = c.service.UpdateRobot(ctx, *cr.Status.AtProvider.ID, spec)
 	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
@@ -244,6 +323,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	if cr.Status.AtProvider.ID == nil {
+		forgetExpiry(cr)
 		return managed.ExternalDelete{}, nil
 	}
 
@@ -251,6 +331,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	if err != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, errRobotDelete)
 	}
+	forgetExpiry(cr)
 
 	return managed.ExternalDelete{}, nil
 }
@@ -259,6 +340,27 @@ func (c *external) Disconnect(ctx context.Context) error {
 	return c.service.Close()
 }
 
+// connectionDetails returns the credentials of a robot account, which can be
+// used to log in to Harbor as username and password. Harbor only returns the
+// secret when the robot account is created, so the password is published
+// once; connection details are merged into the Secret, which keeps it.
+func connectionDetails(robot *harborclients.RobotStatus) managed.ConnectionDetails {
+	details := managed.ConnectionDetails{
+		connectionKeyUsername: []byte(robot.Name),
+		connectionKeyRobotID:  []byte(robot.ID),
+	}
+	if robot.Secret != "" {
+		details[connectionKeyPassword] = []byte(robot.Secret)
+	}
+	return details
+}
+
+// isProjectRobot reports whether fullName is the Harbor name of a
+// project-level robot called name. Harbor names these robot$<project>+<name>.
+func isProjectRobot(fullName, name string) bool {
+	return strings.HasPrefix(fullName, "robot$") && strings.HasSuffix(fullName, "+"+name)
+}
+
 func convertPermissions(perms []v1beta1.RobotPermission) []harborclients.RobotPermission {
 	if len(perms) == 0 {
 		return nil
@@ -465,17 +465,24 @@ func TestCreateRobotSuccess(t *testing.T) {
 			createRobotFunc: func(ctx context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
 				return &harborclients.RobotStatus{
 					ID:           "robot-123",
-					Name:         spec.Name,
+					Name:         "robot$project-1+" + spec.Name,
+					Secret:       "s3cret",
 					CreationTime: time.Now(),
 				}, nil
 			},
 		},
 	}
 
-	_, err := ext.Create(ctx, robot)
+	got, err := ext.Create(ctx, robot)
 	if err != nil {
 		t.Errorf("Create should not fail, got %v", err)
 	}
+	want := map[string]string{"username": "robot$project-1+my-robot", "password": "s3cret", "robot_id": "robot-123"}
+	for k, v := range want {
+		if string(got.ConnectionDetails[k]) != v {
+			t.Errorf("ConnectionDetails[%q] = %q, want %q", k, got.ConnectionDetails[k], v)
+		}
+	}
 }
 
 func TestCreateRobotError(t *testing.T) {
@@ -901,8 +908,13 @@ func TestRobotExpirationValidation(t *testing.T) {
 			isValid: true,
 		},
 		{
-			name:    "negative expiration",
+			name:    "never expires",
 			expires: -1,
+			isValid: true,
+		},
+		{
+			name:    "negative expiration",
+			expires: -2,
 			isValid: false,
 		},
 		{
@@ -914,7 +926,7 @@ func TestRobotExpirationValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			isValid := tt.expires >= 1
+			isValid := tt.expires == -1 || tt.expires >= 1
 			if isValid != tt.isValid {
 				t.Errorf("Expected valid=%v, got %v", tt.isValid, isValid)
 			}
@@ -929,18 +941,32 @@ type mockRobotClient struct {
 	updateRobotFunc func(ctx context.Context, robotID string, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error)
 	deleteRobotFunc func(ctx context.Context, robotID string) error
 	closeFunc       func() error
+
+	// configurations is what GetConfigurations returns.
+	configurations harborclients.Configurations
+
+	// permissions is what GetRobotPermissions returns.
+	permissions *harborclients.RobotPermissions
+
+	// created holds the robots CreateRobot returned, which ListRobots
+	// returns when listRobotsFunc is not set.
+	created []*harborclients.RobotStatus
 }
 
 func (m *mockRobotClient) ListRobots(ctx context.Context, projectID *string) ([]*harborclients.RobotStatus, error) {
 	if m.listRobotsFunc != nil {
 		return m.listRobotsFunc(ctx, projectID)
 	}
-	return nil, nil
+	return m.created, nil
 }
 
 func (m *mockRobotClient) CreateRobot(ctx context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
 	if m.createRobotFunc != nil {
-		return m.createRobotFunc(ctx, spec)
+		r, err := m.createRobotFunc(ctx, spec)
+		if r != nil {
+			m.created = append(m.created, r)
+		}
+		return r, err
 	}
 	return nil, nil
 }
@@ -959,6 +985,14 @@ func (m *mockRobotClient) DeleteRobot(ctx context.Context, robotID string) error
 	return nil
 }
 
+func (m *mockRobotClient) GetConfigurations(_ context.Context) (harborclients.Configurations, error) {
+	return m.configurations, nil
+}
+
+func (m *mockRobotClient) GetRobotPermissions(_ context.Context) (*harborclients.RobotPermissions, error) {
+	return m.permissions, nil
+}
+
 func (m *mockRobotClient) Close() error {
 	if m.closeFunc != nil {
 		return m.closeFunc()
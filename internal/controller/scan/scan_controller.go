@@ -6,6 +6,7 @@ package scan
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
@@ -19,35 +20,38 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"time"
 )
 
 const (
 	errNotScan    = "managed resource is not a Scan custom resource"
 	errScanDelete = "cannot delete Harbor scan"
+	errRescan     = "cannot scan artifact again"
 	errNewClient  = "cannot create new Harbor client"
 )
 
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.ScanGroupVersionKind.Kind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.ScanGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
-		}),
+		})))))))),
 		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
-		managed.WithPollInterval(1*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ScanGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		WithEventFilter(resource.DesiredStateChanged()).
 		For(&v1beta1.Scan{}).
-		Complete(ratelimiter.NewReconciler(name, r, nil))
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
 type connector struct {
@@ -101,7 +105,9 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	// Set external name for adoption tracking
 	ctrlutil.SetExternalName(cr, status.ID)
-	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	// An artifact without a scan report has never been scanned, and is
+	// scanned by Create.
+	return managed.ExternalObservation{ResourceExists: status.ID != "" || status.Status != "", ResourceUpToDate: !rescanOutdated(cr)}, nil
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -127,9 +133,38 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		tracing.SpanAttrs("Scan", tracing.ResourceName(mg), "update")...)
 	defer span.End()
 
+	cr, ok := mg.(*v1beta1.Scan)
+	if !ok || !rescanOutdated(cr) {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	// The first generation is satisfied by a scan that is still running,
+	// such as the one Create started.
+	if cr.Status.AtProvider.RescanGeneration != nil || !scanOngoing(cr.Status.AtProvider.Status) {
+		if err := c.service.TriggerScan(ctx, cr.Spec.ForProvider.ProjectID, cr.Spec.ForProvider.RepositoryName, cr.Spec.ForProvider.Reference); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRescan)
+		}
+	}
+	gen := *cr.Spec.ForProvider.RescanGeneration
+	cr.Status.AtProvider.RescanGeneration = &gen
 	return managed.ExternalUpdate{}, nil
 }
 
+// rescanOutdated reports whether a Scan asks for a rescan generation that
+// has not been scanned yet.
+func rescanOutdated(cr *v1beta1.Scan) bool {
+	want := cr.Spec.ForProvider.RescanGeneration
+	if want == nil {
+		return false
+	}
+	have := cr.Status.AtProvider.RescanGeneration
+	return have == nil || *have != *want
+}
+
+func scanOngoing(status *string) bool {
+	return status != nil && (*status == harborclients.ScanStatusPending || *status == harborclients.ScanStatusRunning)
+}
+
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
 	_, span := tracing.StartSpan(ctx, "scan.delete",
 		tracing.SpanAttrs("Scan", tracing.ResourceName(mg), "delete")...)
@@ -719,3 +719,51 @@ func (m *mockScanClient) GetBaseURL() string {
 func ptrString(s string) *string {
 	return &s
 }
+
+func TestUpdateRescan(t *testing.T) {
+	gen := func(g int64) *int64 { return &g }
+	running := harborclients.ScanStatusRunning
+	done := harborclients.ScanStatusSuccess
+
+	cases := map[string]struct {
+		want        *int64
+		have        *int64
+		status      *string
+		wantTrigger bool
+		wantHave    *int64
+	}{
+		"NotAsked":          {status: &done},
+		"AlreadyScanned":    {want: gen(2), have: gen(2), status: &done, wantHave: gen(2)},
+		"Increased":         {want: gen(3), have: gen(2), status: &done, wantTrigger: true, wantHave: gen(3)},
+		"IncreasedRunning":  {want: gen(3), have: gen(2), status: &running, wantTrigger: true, wantHave: gen(3)},
+		"FirstWhileRunning": {want: gen(1), status: &running, wantHave: gen(1)},
+		"FirstAfterScan":    {want: gen(1), status: &done, wantTrigger: true, wantHave: gen(1)},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.Scan{
+				Spec: v1beta1.ScanSpec{ForProvider: v1beta1.ScanParameters{
+					ProjectID: "library", RepositoryName: "alpine", Reference: "latest", RescanGeneration: tc.want,
+				}},
+				Status: v1beta1.ScanStatus{AtProvider: v1beta1.ScanObservation{Status: tc.status, RescanGeneration: tc.have}},
+			}
+			triggered := false
+			ext := &external{service: &mockScanClient{
+				triggerScanFunc: func(context.Context, string, string, string) error {
+					triggered = true
+					return nil
+				},
+			}}
+			if _, err := ext.Update(context.Background(), cr); err != nil {
+				t.Fatal(err)
+			}
+			if triggered != tc.wantTrigger {
+				t.Errorf("triggered = %v, want %v", triggered, tc.wantTrigger)
+			}
+			got := cr.Status.AtProvider.RescanGeneration
+			if (got == nil) != (tc.wantHave == nil) || (got != nil && *got != *tc.wantHave) {
+				t.Errorf("status rescanGeneration = %v, want %v", got, tc.wantHave)
+			}
+		})
+	}
+}
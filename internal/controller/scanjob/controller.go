@@ -0,0 +1,226 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package scanjob starts, stops and follows vulnerability scans of a
+// project's artifacts or of all artifacts.
+package scanjob
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/scan/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"github.com/rossigee/provider-harbor/internal/tracing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errNotScanJob = "managed resource is not a ScanJob custom resource"
+	errNewClient  = "cannot create new Service"
+	errGetMetrics = "cannot get Harbor scan progress"
+	errStart      = "cannot start Harbor scan"
+	errStop       = "cannot stop Harbor scan"
+)
+
+// Setup adds a controller that reconciles ScanJob managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1beta1.ScanJobGroupVersionKind.Kind)
+	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
+
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), clients.CapabilityManageSystem, ctrlutil.WithDeletionGuard(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
+			kube:         mgr.GetClient(),
+			logger:       log,
+			newServiceFn: clients.NewHarborClientFromProviderConfig,
+		})))))))),
+		managed.WithLogger(log),
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ScanJobGroupVersionKind), opts...)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ScanJob{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// connector is responsible for producing ExternalClients.
+type connector struct {
+	kube         client.Client
+	logger       logging.Logger
+	newServiceFn func(ctx context.Context, kube client.Client, mg resource.Managed) (clients.HarborClienter, error)
+}
+
+// Connect produces an ExternalClient by creating a Harbor client
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1beta1.ScanJob); !ok {
+		return nil, errors.New(errNotScanJob)
+	}
+
+	harborClient, err := c.newServiceFn(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewClient)
+	}
+
+	return &external{service: harborClient, logger: c.logger}, nil
+}
+
+// external runs a ScanJob. The job always exists, so that scans are started
+// by Update, whose status changes are kept. It is up to date while its
+// generation has been started and it is not asked to stop an ongoing scan.
+type external struct {
+	service clients.HarborClienter
+	logger  logging.Logger
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	_, span := tracing.StartSpan(ctx, "scanjob.observe",
+		tracing.SpanAttrs("ScanJob", tracing.ResourceName(mg), "observe")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ScanJob)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotScanJob)
+	}
+
+	// Harbor keeps the results of the scan, so there is nothing to delete.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	m, err := c.metrics(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetMetrics)
+	}
+	observe(cr, m)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: !generationOutdated(cr) && !(stop(cr) && m.Ongoing),
+	}, nil
+}
+
+// Create does nothing; a ScanJob always exists.
+func (c *external) Create(_ context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if _, ok := mg.(*v1beta1.ScanJob); !ok {
+		return managed.ExternalCreation{}, errors.New(errNotScanJob)
+	}
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	_, span := tracing.StartSpan(ctx, "scanjob.update",
+		tracing.SpanAttrs("ScanJob", tracing.ResourceName(mg), "update")...)
+	defer span.End()
+
+	cr, ok := mg.(*v1beta1.ScanJob)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotScanJob)
+	}
+
+	if generationOutdated(cr) {
+		return managed.ExternalUpdate{}, c.start(ctx, cr)
+	}
+	if stop(cr) {
+		var err error
+		if p := cr.Spec.ForProvider.ProjectName; p != nil {
+			_, err = c.service.StopProjectScans(ctx, *p)
+		} else {
+			err = c.service.StopScanAll(ctx)
+		}
+		return managed.ExternalUpdate{}, errors.Wrap(err, errStop)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete does nothing; an ongoing scan keeps running.
+func (c *external) Delete(_ context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	if _, ok := mg.(*v1beta1.ScanJob); !ok {
+		return managed.ExternalDelete{}, errors.New(errNotScanJob)
+	}
+	return managed.ExternalDelete{}, nil
+}
+
+func (c *external) Disconnect(_ context.Context) error {
+	return c.service.Close()
+}
+
+// start starts the scan and records the generation it was started for. A
+// ScanJob asked to stop starts nothing.
+func (c *external) start(ctx context.Context, cr *v1beta1.ScanJob) error {
+	gen := generation(cr)
+	if !stop(cr) {
+		var err error
+		if p := cr.Spec.ForProvider.ProjectName; p != nil {
+			_, err = c.service.ScanProject(ctx, *p)
+		} else {
+			err = c.service.TriggerScanAll(ctx)
+		}
+		if err != nil {
+			return errors.Wrap(err, errStart)
+		}
+		now := metav1.Now()
+		cr.Status.AtProvider.StartTime = &now
+	}
+	cr.Status.AtProvider.Generation = &gen
+	return nil
+}
+
+func (c *external) metrics(ctx context.Context, cr *v1beta1.ScanJob) (*clients.ScanMetrics, error) {
+	if p := cr.Spec.ForProvider.ProjectName; p != nil {
+		return c.service.GetProjectScanMetrics(ctx, *p)
+	}
+	return c.service.GetScanAllMetrics(ctx)
+}
+
+// observe records the progress of the scan in the status of cr.
+func observe(cr *v1beta1.ScanJob, m *clients.ScanMetrics) {
+	o := &cr.Status.AtProvider
+	o.Ongoing = &m.Ongoing
+	o.Total = &m.Total
+	o.Completed = &m.Completed
+	o.Statuses = m.Statuses
+	o.Trigger = nil
+	if m.Trigger != "" {
+		o.Trigger = &m.Trigger
+	}
+}
+
+// generation returns the generation cr asks to be started, which is 1 when
+// unset.
+func generation(cr *v1beta1.ScanJob) int64 {
+	if g := cr.Spec.ForProvider.Generation; g != nil {
+		return *g
+	}
+	return 1
+}
+
+// generationOutdated reports whether cr asks for a generation that has not
+// been started.
+func generationOutdated(cr *v1beta1.ScanJob) bool {
+	have := cr.Status.AtProvider.Generation
+	return have == nil || *have != generation(cr)
+}
+
+func stop(cr *v1beta1.ScanJob) bool {
+	return cr.Spec.ForProvider.Stop != nil && *cr.Spec.ForProvider.Stop
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package scanjob
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/rossigee/provider-harbor/apis/scan/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// recordingService records which scan operations were called.
+type recordingService struct {
+	harborclients.MockHarborClient
+	metrics *harborclients.ScanMetrics
+	called  []string
+}
+
+func newRecordingService(m *harborclients.ScanMetrics) *recordingService {
+	s := &recordingService{metrics: m}
+	s.GetScanAllMetricsFunc = func(context.Context) (*harborclients.ScanMetrics, error) {
+		s.called = append(s.called, "GetScanAllMetrics")
+		return s.metrics, nil
+	}
+	s.GetProjectScanMetricsFunc = func(_ context.Context, p string) (*harborclients.ScanMetrics, error) {
+		s.called = append(s.called, "GetProjectScanMetrics "+p)
+		return s.metrics, nil
+	}
+	s.TriggerScanAllFunc = func(context.Context) error {
+		s.called = append(s.called, "TriggerScanAll")
+		return nil
+	}
+	s.StopScanAllFunc = func(context.Context) error {
+		s.called = append(s.called, "StopScanAll")
+		return nil
+	}
+	s.ScanProjectFunc = func(_ context.Context, p string) (int64, error) {
+		s.called = append(s.called, "ScanProject "+p)
+		return 1, nil
+	}
+	s.StopProjectScansFunc = func(_ context.Context, p string) (int64, error) {
+		s.called = append(s.called, "StopProjectScans "+p)
+		return 1, nil
+	}
+	return s
+}
+
+func TestScanJob(t *testing.T) {
+	cases := map[string]struct {
+		params       v1beta1.ScanJobParameters
+		started      *int64
+		ongoing      bool
+		wantUpToDate bool
+		wantCalled   []string
+		wantStarted  int64
+	}{
+		"NewScanAll": {
+			wantCalled:  []string{"GetScanAllMetrics", "TriggerScanAll"},
+			wantStarted: 1,
+		},
+		"NewProject": {
+			params:      v1beta1.ScanJobParameters{ProjectName: ptr("library")},
+			wantCalled:  []string{"GetProjectScanMetrics library", "ScanProject library"},
+			wantStarted: 1,
+		},
+		"Started": {
+			started:      ptr(int64(1)),
+			ongoing:      true,
+			wantUpToDate: true,
+			wantCalled:   []string{"GetScanAllMetrics"},
+			wantStarted:  1,
+		},
+		"GenerationIncreased": {
+			params:      v1beta1.ScanJobParameters{Generation: ptr(int64(2))},
+			started:     ptr(int64(1)),
+			wantCalled:  []string{"GetScanAllMetrics", "TriggerScanAll"},
+			wantStarted: 2,
+		},
+		"StopOngoing": {
+			params:      v1beta1.ScanJobParameters{Stop: ptr(true)},
+			started:     ptr(int64(1)),
+			ongoing:     true,
+			wantCalled:  []string{"GetScanAllMetrics", "StopScanAll"},
+			wantStarted: 1,
+		},
+		"StopOngoingProject": {
+			params:      v1beta1.ScanJobParameters{ProjectName: ptr("library"), Stop: ptr(true)},
+			started:     ptr(int64(1)),
+			ongoing:     true,
+			wantCalled:  []string{"GetProjectScanMetrics library", "StopProjectScans library"},
+			wantStarted: 1,
+		},
+		"StopFinished": {
+			params:       v1beta1.ScanJobParameters{Stop: ptr(true)},
+			started:      ptr(int64(1)),
+			wantUpToDate: true,
+			wantCalled:   []string{"GetScanAllMetrics"},
+			wantStarted:  1,
+		},
+		"NewStopped": {
+			params:      v1beta1.ScanJobParameters{Stop: ptr(true)},
+			wantCalled:  []string{"GetScanAllMetrics"},
+			wantStarted: 1,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.ScanJob{
+				Spec:   v1beta1.ScanJobSpec{ForProvider: tc.params},
+				Status: v1beta1.ScanJobStatus{AtProvider: v1beta1.ScanJobObservation{Generation: tc.started}},
+			}
+			svc := newRecordingService(&harborclients.ScanMetrics{Ongoing: tc.ongoing, Total: 4, Completed: 1})
+			ext := &external{service: svc, logger: logging.NewNopLogger()}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatalf("Observe() error = %v", err)
+			}
+			if !obs.ResourceExists || obs.ResourceUpToDate != tc.wantUpToDate {
+				t.Errorf("Observe() = %+v, want exists, up to date %v", obs, tc.wantUpToDate)
+			}
+			if *cr.Status.AtProvider.Total != 4 || *cr.Status.AtProvider.Completed != 1 {
+				t.Errorf("status = %+v", cr.Status.AtProvider)
+			}
+			if !obs.ResourceUpToDate {
+				if _, err := ext.Update(context.Background(), cr); err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+			}
+			if !reflect.DeepEqual(svc.called, tc.wantCalled) {
+				t.Errorf("calls = %v, want %v", svc.called, tc.wantCalled)
+			}
+			if got := cr.Status.AtProvider.Generation; got == nil || *got != tc.wantStarted {
+				t.Errorf("status generation = %v, want %d", got, tc.wantStarted)
+			}
+		})
+	}
+}
+
+func TestObserveDeleted(t *testing.T) {
+	now := metav1.Now()
+	cr := &v1beta1.ScanJob{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}}
+	svc := newRecordingService(&harborclients.ScanMetrics{Ongoing: true})
+	ext := &external{service: svc, logger: logging.NewNopLogger()}
+
+	obs, err := ext.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceExists || len(svc.called) != 0 {
+		t.Errorf("Observe() of a deleted ScanJob = %+v, calls %v", obs, svc.called)
+	}
+}
@@ -18,18 +18,24 @@ package scanner
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
 	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
 	"github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
 	"github.com/rossigee/provider-harbor/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"time"
 )
 
@@ -46,21 +52,29 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.ScannerRegistrationGroupVersionKind.Kind)
 	log := logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.ScannerRegistrationGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), clients.CapabilityManageSystem, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:   mgr.GetClient(),
 			logger: log,
-		}),
+		}))))))))),
 		managed.WithLogger(log),
-		managed.WithPollInterval(10*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(10 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.ScannerRegistrationGroupVersionKind), opts...)
+	newList := func() client.ObjectList { return &v1beta1.ScannerRegistrationList{} }
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.ScannerRegistration{}).
-		Complete(r)
+		Watches(&v1beta1.ScannerRegistration{}, enqueueDefaultCandidates(mgr.GetClient()),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WatchesMetadata(&corev1.Secret{}, ctrlutil.EnqueueCABundleReferrers(mgr.GetClient(), common.CABundleKindSecret, newList)).
+		WatchesMetadata(&corev1.ConfigMap{}, ctrlutil.EnqueueCABundleReferrers(mgr.GetClient(), common.CABundleKindConfigMap, newList)).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
 // connector is responsible for producing ExternalClients.
@@ -81,12 +95,13 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{service: harborClient, logger: c.logger}, nil
+	return &external{kube: c.kube, service: harborClient, logger: c.logger}, nil
 }
 
 // external observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
+	kube    client.Reader
 	service clients.HarborClienter
 	logger  logging.Logger
 }
@@ -126,10 +141,40 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if status.UpdateTime != (time.Time{}) {
 		cr.Status.AtProvider.UpdateTime = &metav1.Time{Time: status.UpdateTime}
 	}
+	c.observeMetadata(ctx, cr, status.UUID)
+
+	upToDate := c.isUpToDate(cr, status)
+	defaultUpToDate, err := c.observeDefault(ctx, cr, status)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	upToDate = upToDate && defaultUpToDate
+	if cr.Spec.ForProvider.CredentialRobot != nil {
+		r, err := c.observeCredentialRobot(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errCredentialRobot)
+		}
+		if needsRenewal(r, time.Now()) || (status.Auth != nil && *status.Auth != authBasic) {
+			upToDate = false
+		}
+	} else if cr.Status.AtProvider.CredentialRobotID != nil {
+		// credentialRobot was removed; Update deletes the robot.
+		upToDate = false
+	}
+
+	ca, err := ctrlutil.ResolveCABundle(ctx, c.kube, cr.GetNamespace(), cr.Spec.ForProvider.CABundleRef)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if ca == nil {
+		cr.Status.AtProvider.CABundle = nil
+	} else if ca.Changed(cr.Status.AtProvider.CABundle) {
+		upToDate = false
+	}
 
 	return managed.ExternalObservation{
 		ResourceExists:    true,
-		ResourceUpToDate:  c.isUpToDate(cr, status),
+		ResourceUpToDate:  upToDate,
 		ConnectionDetails: managed.ConnectionDetails{},
 	}, nil
 }
@@ -141,10 +186,14 @@ func (c *external) isUpToDate(cr *v1beta1.ScannerRegistration, status *clients.S
 	if cr.Spec.ForProvider.Description != nil && status.Description != nil && *cr.Spec.ForProvider.Description != *status.Description {
 		return false
 	}
-	if cr.Spec.ForProvider.Auth != nil && status.Auth != nil && *cr.Spec.ForProvider.Auth != *status.Auth {
+	if cr.Spec.ForProvider.Name != status.Name {
 		return false
 	}
-	if cr.Spec.ForProvider.Name != status.Name {
+	if cr.Spec.ForProvider.CredentialRobot != nil {
+		// Auth and credential are owned by the credential robot.
+		return true
+	}
+	if cr.Spec.ForProvider.Auth != nil && status.Auth != nil && *cr.Spec.ForProvider.Auth != *status.Auth {
 		return false
 	}
 	if cr.Spec.ForProvider.AccessCredential != nil && status.AccessCredential != nil && *cr.Spec.ForProvider.AccessCredential != *status.AccessCredential {
@@ -153,6 +202,35 @@ func (c *external) isUpToDate(cr *v1beta1.ScannerRegistration, status *clients.S
 	return true
 }
 
+// applyCABundle turns certificate verification on when the scanner has a CA
+// bundle. The returned bundle should be recorded once the registration has
+// been saved.
+func (c *external) applyCABundle(ctx context.Context, cr *v1beta1.ScannerRegistration, spec *clients.ScannerSpec) (*ctrlutil.CABundle, error) {
+	ca, err := ctrlutil.ResolveCABundle(ctx, c.kube, cr.GetNamespace(), cr.Spec.ForProvider.CABundleRef)
+	if err != nil || ca == nil {
+		return nil, err
+	}
+	verify := false
+	spec.SkipCertVerify = &verify
+	return ca, nil
+}
+
+// applyCredential points spec at the scanner's credential robot when one is
+// configured.
+func (c *external) applyCredential(ctx context.Context, cr *v1beta1.ScannerRegistration, spec *clients.ScannerSpec) error {
+	if cr.Spec.ForProvider.CredentialRobot == nil {
+		return nil
+	}
+	cred, err := c.credential(ctx, cr)
+	if err != nil {
+		return err
+	}
+	auth := authBasic
+	spec.Auth = &auth
+	spec.AccessCredential = &cred
+	return nil
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	_, span := tracing.StartSpan(ctx, "scanner.create",
 		tracing.SpanAttrs("Scanner", tracing.ResourceName(mg), "create")...)
@@ -180,10 +258,31 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		spec.AccessCredential = cr.Spec.ForProvider.AccessCredential
 	}
 
+	if cr.Spec.ForProvider.SkipCertVerify != nil {
+		spec.SkipCertVerify = cr.Spec.ForProvider.SkipCertVerify
+	}
+	ca, err := c.applyCABundle(ctx, cr, spec)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	if err := c.applyCredential(ctx, cr, spec); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
 	status, err := c.service.CreateScannerRegistration(ctx, spec)
 	if err != nil {
+		// Don't leave an unused credential robot behind.
+		_ = c.deleteCredentialRobot(ctx, cr)
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot create Harbor scanner registration")
 	}
+	if ca != nil {
+		cr.Status.AtProvider.CABundle = ca.Observation()
+	}
+	cr.Status.AtProvider.UUID = &status.UUID
+	if err := c.applyDefault(ctx, cr, status.UUID); err != nil {
+		return managed.ExternalCreation{}, err
+	}
 
 	c.logger.Info("Successfully created Harbor scanner registration", "name", status.Name, "uuid", status.UUID)
 
@@ -225,10 +324,34 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		scannerID = *cr.Status.AtProvider.UUID
 	}
 
+	if cr.Spec.ForProvider.SkipCertVerify != nil {
+		spec.SkipCertVerify = cr.Spec.ForProvider.SkipCertVerify
+	}
+	ca, err := c.applyCABundle(ctx, cr, spec)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	if err := c.applyCredential(ctx, cr, spec); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
 	status, err := c.service.UpdateScannerRegistration(ctx, scannerID, spec)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot update Harbor scanner registration")
 	}
+	if ca != nil {
+		cr.Status.AtProvider.CABundle = ca.Observation()
+	}
+
+	if cr.Spec.ForProvider.CredentialRobot == nil {
+		if err := c.deleteCredentialRobot(ctx, cr); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+	if err := c.applyDefault(ctx, cr, scannerID); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
 
 	c.logger.Info("Successfully updated Harbor scanner registration", "name", status.Name, "uuid", status.UUID)
 
@@ -259,6 +382,9 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 	if err != nil {
 		return managed.ExternalDelete{}, errors.Wrap(err, "cannot delete Harbor scanner registration")
 	}
+	if err := c.deleteCredentialRobot(ctx, cr); err != nil {
+		return managed.ExternalDelete{}, err
+	}
 
 	c.logger.Info("Successfully deleted Harbor scanner registration", "name", cr.Spec.ForProvider.Name)
 
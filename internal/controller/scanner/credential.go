@@ -0,0 +1,125 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package scanner
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	errCredentialRobot       = "cannot provision scanner credential robot"
+	errDeleteCredentialRobot = "cannot delete scanner credential robot"
+
+	// credentialRenewBefore is how long before it expires the credential
+	// robot is replaced.
+	credentialRenewBefore = 7 * 24 * time.Hour
+
+	authBasic = "Basic"
+)
+
+// scannerPullAccess is what a scanner needs to fetch artifacts it scans.
+var scannerPullAccess = []string{"pull", "scanner-pull"}
+
+func credentialRobotName(p v1beta1.ScannerRegistrationParameters) string {
+	if p.CredentialRobot.Name != nil && *p.CredentialRobot.Name != "" {
+		return *p.CredentialRobot.Name
+	}
+	return "scanner-" + p.Name
+}
+
+func credentialRobotSpec(p v1beta1.ScannerRegistrationParameters) *clients.RobotSpec {
+	desc := "Pull credential for scanner " + p.Name
+	return &clients.RobotSpec{
+		Name:        credentialRobotName(p),
+		Description: &desc,
+		ExpiresIn:   p.CredentialRobot.Duration,
+		Permissions: []clients.RobotPermission{{Namespace: "*", Access: scannerPullAccess}},
+	}
+}
+
+// needsRenewal reports whether the credential robot must be (re)created.
+func needsRenewal(r *clients.RobotStatus, now time.Time) bool {
+	return r == nil || (r.ExpiresAt != nil && now.Add(credentialRenewBefore).After(*r.ExpiresAt))
+}
+
+// observeCredentialRobot returns the scanner's credential robot, or nil if it
+// does not exist.
+func (c *external) observeCredentialRobot(ctx context.Context, cr *v1beta1.ScannerRegistration) (*clients.RobotStatus, error) {
+	robots, err := c.service.ListRobots(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	id := cr.Status.AtProvider.CredentialRobotID
+	name := "robot$" + credentialRobotName(cr.Spec.ForProvider)
+	for _, r := range robots {
+		if (id != nil && r.ID == *id) || r.Name == name {
+			setCredentialRobotStatus(cr, r)
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// credential returns a fresh access credential for the scanner. Harbor never
+// returns a robot's secret after creation, so the secret is refreshed each
+// time; an expiring or missing robot is replaced instead.
+func (c *external) credential(ctx context.Context, cr *v1beta1.ScannerRegistration) (string, error) {
+	r, err := c.observeCredentialRobot(ctx, cr)
+	if err != nil {
+		return "", errors.Wrap(err, errCredentialRobot)
+	}
+
+	if !needsRenewal(r, time.Now()) {
+		secret, err := c.service.RefreshRobotSecret(ctx, r.ID)
+		if err != nil {
+			return "", errors.Wrap(err, errCredentialRobot)
+		}
+		return r.Name + ":" + secret, nil
+	}
+
+	if r != nil {
+		if err := c.service.DeleteRobot(ctx, r.ID); err != nil {
+			return "", errors.Wrap(err, errDeleteCredentialRobot)
+		}
+	}
+	r, err = c.service.CreateRobot(ctx, credentialRobotSpec(cr.Spec.ForProvider))
+	if err != nil {
+		return "", errors.Wrap(err, errCredentialRobot)
+	}
+	setCredentialRobotStatus(cr, r)
+	c.logger.Info("Provisioned scanner credential robot", "scanner", cr.Spec.ForProvider.Name, "robot", r.Name)
+	return r.Name + ":" + r.Secret, nil
+}
+
+// deleteCredentialRobot removes the robot recorded in the scanner's status.
+func (c *external) deleteCredentialRobot(ctx context.Context, cr *v1beta1.ScannerRegistration) error {
+	id := cr.Status.AtProvider.CredentialRobotID
+	if id == nil {
+		return nil
+	}
+	if err := c.service.DeleteRobot(ctx, *id); err != nil {
+		return errors.Wrap(err, errDeleteCredentialRobot)
+	}
+	cr.Status.AtProvider.CredentialRobotID = nil
+	cr.Status.AtProvider.CredentialRobotName = nil
+	cr.Status.AtProvider.CredentialExpiresAt = nil
+	return nil
+}
+
+func setCredentialRobotStatus(cr *v1beta1.ScannerRegistration, r *clients.RobotStatus) {
+	id, name := r.ID, r.Name
+	cr.Status.AtProvider.CredentialRobotID = &id
+	cr.Status.AtProvider.CredentialRobotName = &name
+	cr.Status.AtProvider.CredentialExpiresAt = nil
+	if r.ExpiresAt != nil {
+		cr.Status.AtProvider.CredentialExpiresAt = &metav1.Time{Time: *r.ExpiresAt}
+	}
+}
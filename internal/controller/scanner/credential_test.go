@@ -0,0 +1,219 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package scanner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+func robotScanner() *v1beta1.ScannerRegistration {
+	cr := &v1beta1.ScannerRegistration{}
+	cr.Spec.ForProvider = v1beta1.ScannerRegistrationParameters{
+		Name:            "trivy",
+		URL:             "http://trivy:8080",
+		Auth:            ptrString("Bearer"),
+		CredentialRobot: &v1beta1.ScannerCredentialRobot{},
+	}
+	return cr
+}
+
+func TestCreateScannerWithCredentialRobot(t *testing.T) {
+	cr := robotScanner()
+	var robot *harborclients.RobotSpec
+	var scanner *harborclients.ScannerSpec
+	ext := &external{
+		logger: logging.NewNopLogger(),
+		service: &mockScannerClient{
+			createRobotFunc: func(_ context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+				robot = spec
+				return &harborclients.RobotStatus{ID: "9", Name: "robot$" + spec.Name, Secret: "s3cret"}, nil
+			},
+			createScannerRegistrationFunc: func(_ context.Context, spec *harborclients.ScannerSpec) (*harborclients.ScannerStatus, error) {
+				scanner = spec
+				return &harborclients.ScannerStatus{UUID: "u", Name: spec.Name}, nil
+			},
+		},
+	}
+
+	if _, err := ext.Create(context.Background(), cr); err != nil {
+		t.Fatal(err)
+	}
+	if robot == nil || robot.Name != "scanner-trivy" || robot.ProjectID != nil {
+		t.Fatalf("robot spec = %+v, want system robot scanner-trivy", robot)
+	}
+	if *scanner.Auth != "Basic" || *scanner.AccessCredential != "robot$scanner-trivy:s3cret" {
+		t.Errorf("scanner auth = %s %s", *scanner.Auth, *scanner.AccessCredential)
+	}
+	if id := cr.Status.AtProvider.CredentialRobotID; id == nil || *id != "9" {
+		t.Errorf("CredentialRobotID = %v, want 9", id)
+	}
+}
+
+func TestCreateScannerFailureDeletesCredentialRobot(t *testing.T) {
+	var deleted []string
+	ext := &external{
+		logger: logging.NewNopLogger(),
+		service: &mockScannerClient{
+			createScannerRegistrationFunc: func(context.Context, *harborclients.ScannerSpec) (*harborclients.ScannerStatus, error) {
+				return nil, errors.New("boom")
+			},
+			deleteRobotFunc: func(_ context.Context, id string) error {
+				deleted = append(deleted, id)
+				return nil
+			},
+		},
+	}
+
+	if _, err := ext.Create(context.Background(), robotScanner()); err == nil {
+		t.Fatal("Create should fail")
+	}
+	if len(deleted) != 1 || deleted[0] != "1" {
+		t.Errorf("deleted robots = %v, want [1]", deleted)
+	}
+}
+
+func TestUpdateScannerRotatesCredential(t *testing.T) {
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(60 * 24 * time.Hour)
+
+	cases := map[string]struct {
+		robots      []*harborclients.RobotStatus
+		wantCred    string
+		wantDeleted []string
+		wantCreated bool
+	}{
+		"Refresh": {
+			robots:   []*harborclients.RobotStatus{{ID: "3", Name: "robot$scanner-trivy", ExpiresAt: &later}},
+			wantCred: "robot$scanner-trivy:refreshed",
+		},
+		"ReplaceExpiring": {
+			robots:      []*harborclients.RobotStatus{{ID: "3", Name: "robot$scanner-trivy", ExpiresAt: &soon}},
+			wantCred:    "robot$scanner-trivy:secret",
+			wantDeleted: []string{"3"},
+			wantCreated: true,
+		},
+		"RecreateMissing": {
+			wantCred:    "robot$scanner-trivy:secret",
+			wantCreated: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var cred string
+			var deleted []string
+			created := false
+			ext := &external{
+				logger: logging.NewNopLogger(),
+				service: &mockScannerClient{
+					listRobotsFunc: func(context.Context, *string) ([]*harborclients.RobotStatus, error) {
+						return tc.robots, nil
+					},
+					createRobotFunc: func(_ context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+						created = true
+						return &harborclients.RobotStatus{ID: "4", Name: "robot$" + spec.Name, Secret: "secret"}, nil
+					},
+					deleteRobotFunc: func(_ context.Context, id string) error {
+						deleted = append(deleted, id)
+						return nil
+					},
+					updateScannerRegistrationFunc: func(_ context.Context, _ string, spec *harborclients.ScannerSpec) (*harborclients.ScannerStatus, error) {
+						cred = *spec.AccessCredential
+						return &harborclients.ScannerStatus{Name: spec.Name}, nil
+					},
+				},
+			}
+
+			if _, err := ext.Update(context.Background(), robotScanner()); err != nil {
+				t.Fatal(err)
+			}
+			if cred != tc.wantCred {
+				t.Errorf("credential = %q, want %q", cred, tc.wantCred)
+			}
+			if created != tc.wantCreated {
+				t.Errorf("created = %v, want %v", created, tc.wantCreated)
+			}
+			if len(deleted) != len(tc.wantDeleted) || (len(deleted) > 0 && deleted[0] != tc.wantDeleted[0]) {
+				t.Errorf("deleted = %v, want %v", deleted, tc.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestObserveScannerCredentialRobot(t *testing.T) {
+	soon := time.Now().Add(time.Hour)
+	basic := "Basic"
+
+	cases := map[string]struct {
+		robots []*harborclients.RobotStatus
+		want   bool
+	}{
+		"Healthy": {
+			robots: []*harborclients.RobotStatus{{ID: "3", Name: "robot$scanner-trivy"}},
+			want:   true,
+		},
+		"Missing": {
+			want: false,
+		},
+		"Expiring": {
+			robots: []*harborclients.RobotStatus{{ID: "3", Name: "robot$scanner-trivy", ExpiresAt: &soon}},
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := robotScanner()
+			ext := &external{
+				logger: logging.NewNopLogger(),
+				service: &mockScannerClient{
+					getScannerRegistrationFunc: func(context.Context, string) (*harborclients.ScannerStatus, error) {
+						return &harborclients.ScannerStatus{Name: "trivy", URL: "http://trivy:8080", Auth: &basic}, nil
+					},
+					listRobotsFunc: func(context.Context, *string) ([]*harborclients.RobotStatus, error) {
+						return tc.robots, nil
+					},
+				},
+			}
+
+			obs, err := ext.Observe(context.Background(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if obs.ResourceUpToDate != tc.want {
+				t.Errorf("ResourceUpToDate = %v, want %v", obs.ResourceUpToDate, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeleteScannerDeletesCredentialRobot(t *testing.T) {
+	cr := robotScanner()
+	cr.Status.AtProvider.CredentialRobotID = ptrString("3")
+	var deleted []string
+	ext := &external{
+		logger: logging.NewNopLogger(),
+		service: &mockScannerClient{
+			deleteRobotFunc: func(_ context.Context, id string) error {
+				deleted = append(deleted, id)
+				return nil
+			},
+		},
+	}
+
+	if _, err := ext.Delete(context.Background(), cr); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != "3" {
+		t.Errorf("deleted robots = %v, want [3]", deleted)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package scanner
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errListScanners = "cannot list ScannerRegistrations"
+	errSetDefault   = "cannot make scanner the default"
+
+	defaultProviderConfig = "default"
+)
+
+func wantsDefault(cr *v1beta1.ScannerRegistration) bool {
+	return cr.Spec.ForProvider.IsDefault != nil && *cr.Spec.ForProvider.IsDefault
+}
+
+func providerConfigName(cr *v1beta1.ScannerRegistration) string {
+	if ref := cr.Spec.ProviderConfigReference; ref != nil && ref.Name != "" {
+		return ref.Name
+	}
+	return defaultProviderConfig
+}
+
+func key(cr *v1beta1.ScannerRegistration) types.NamespacedName {
+	return types.NamespacedName{Namespace: cr.GetNamespace(), Name: cr.GetName()}
+}
+
+// olderThan orders ScannerRegistrations by creation time, then by namespace
+// and name, so that every reconcile elects the same default.
+func olderThan(a, b *v1beta1.ScannerRegistration) bool {
+	ta, tb := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !ta.Equal(&tb) {
+		return ta.Before(&tb)
+	}
+	return key(a).String() < key(b).String()
+}
+
+// defaultWinner returns the ScannerRegistration that should be the default
+// scanner of cr's Harbor instance: the oldest one for the same ProviderConfig
+// that sets isDefault. It returns nil if cr does not set isDefault.
+func (c *external) defaultWinner(ctx context.Context, cr *v1beta1.ScannerRegistration) (*v1beta1.ScannerRegistration, error) {
+	if !wantsDefault(cr) {
+		return nil, nil
+	}
+	l := &v1beta1.ScannerRegistrationList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListScanners)
+	}
+	pc := providerConfigName(cr)
+	winner := cr
+	for i := range l.Items {
+		o := &l.Items[i]
+		if !wantsDefault(o) || o.GetDeletionTimestamp() != nil || providerConfigName(o) != pc {
+			continue
+		}
+		if olderThan(o, winner) {
+			winner = o
+		}
+	}
+	return winner, nil
+}
+
+// observeDefault sets the DefaultScanner condition and reports whether the
+// default scanner is as desired. Only the elected ScannerRegistration ever
+// makes itself the default, so two registrations never take it in turns.
+func (c *external) observeDefault(ctx context.Context, cr *v1beta1.ScannerRegistration, status *clients.ScannerStatus) (bool, error) {
+	isDefault := status.IsDefault
+	cr.Status.AtProvider.IsDefault = &isDefault
+
+	winner, err := c.defaultWinner(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case winner == nil:
+		if cr.GetCondition(v1beta1.TypeDefaultScanner).Status != corev1.ConditionUnknown {
+			cr.SetConditions(v1beta1.NotDefault())
+		}
+		return true, nil
+	case key(winner) == key(cr):
+		cr.SetConditions(v1beta1.DefaultElected())
+		return isDefault, nil
+	default:
+		cr.SetConditions(v1beta1.DefaultConflict(key(winner).String(), providerConfigName(cr)))
+		return true, nil
+	}
+}
+
+// applyDefault makes the scanner with the given UUID the default if cr is
+// the elected default and is not already the default.
+func (c *external) applyDefault(ctx context.Context, cr *v1beta1.ScannerRegistration, uuid string) error {
+	winner, err := c.defaultWinner(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if winner == nil || key(winner) != key(cr) {
+		return nil
+	}
+	if d := cr.Status.AtProvider.IsDefault; d != nil && *d {
+		return nil
+	}
+	if err := c.service.SetDefaultScanner(ctx, uuid); err != nil {
+		return errors.Wrap(err, errSetDefault)
+	}
+	isDefault := true
+	cr.Status.AtProvider.IsDefault = &isDefault
+	cr.SetConditions(v1beta1.DefaultElected())
+	c.logger.Info("Made scanner the default", "name", cr.Spec.ForProvider.Name, "uuid", uuid)
+	return nil
+}
+
+// enqueueDefaultCandidates returns an event handler that, when a
+// ScannerRegistration changes or is deleted, enqueues the others for the same
+// ProviderConfig that set isDefault, so a new default is elected without
+// waiting for the next poll. It must only see spec changes, or the status
+// updates of the enqueued resources would enqueue each other in turn.
+func enqueueDefaultCandidates(kube client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		changed, ok := o.(*v1beta1.ScannerRegistration)
+		if !ok {
+			return nil
+		}
+		l := &v1beta1.ScannerRegistrationList{}
+		if err := kube.List(ctx, l); err != nil {
+			return nil
+		}
+		var reqs []reconcile.Request
+		for i := range l.Items {
+			other := &l.Items[i]
+			if key(other) != key(changed) && wantsDefault(other) && providerConfigName(other) == providerConfigName(changed) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: key(other)})
+			}
+		}
+		return reqs
+	})
+}
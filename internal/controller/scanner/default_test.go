@@ -0,0 +1,143 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package scanner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func defaultScanner(ns, name, pc string, age time.Duration, isDefault bool) *v1beta1.ScannerRegistration {
+	return &v1beta1.ScannerRegistration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, CreationTimestamp: metav1.NewTime(epoch.Add(-age))},
+		Spec: v1beta1.ScannerRegistrationSpec{
+			ManagedResourceSpec: xpv1.ManagedResourceSpec{ProviderConfigReference: &xpv1.ProviderConfigReference{Name: pc}},
+			ForProvider:         v1beta1.ScannerRegistrationParameters{Name: name, URL: "http://" + name, IsDefault: &isDefault},
+		},
+	}
+}
+
+func scannerKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := v1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestDefaultScannerElection(t *testing.T) {
+	ctx := context.Background()
+	older := defaultScanner("team-a", "trivy", "harbor", 2*time.Hour, true)
+	newer := defaultScanner("team-b", "clair", "harbor", time.Hour, true)
+	// Equal age: the tie is broken by namespace and name.
+	tied := defaultScanner("team-a", "aqua", "harbor", 2*time.Hour, true)
+	other := defaultScanner("team-c", "grype", "other-harbor", 0, true)
+	kube := scannerKube(t, older, newer, tied, other)
+
+	// Harbor's default is currently clair.
+	harborDefault := "clair"
+	var setDefaults []string
+	e := &external{
+		kube:   kube,
+		logger: logging.NewNopLogger(),
+		service: &mockScannerClient{
+			getScannerRegistrationFunc: func(_ context.Context, name string) (*harborclients.ScannerStatus, error) {
+				return &harborclients.ScannerStatus{UUID: "uuid-" + name, Name: name, URL: "http://" + name, IsDefault: name == harborDefault}, nil
+			},
+			updateScannerRegistrationFunc: func(_ context.Context, id string, spec *harborclients.ScannerSpec) (*harborclients.ScannerStatus, error) {
+				return &harborclients.ScannerStatus{UUID: id, Name: spec.Name}, nil
+			},
+			setDefaultScannerFunc: func(_ context.Context, id string) error {
+				setDefaults = append(setDefaults, id)
+				return nil
+			},
+		},
+	}
+
+	// Reconcile the losers first and repeatedly; they must never take the
+	// default, even though Harbor's default is not theirs.
+	for i := 0; i < 3; i++ {
+		for _, cr := range []*v1beta1.ScannerRegistration{newer, older} {
+			obs, err := e.Observe(ctx, cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !obs.ResourceUpToDate {
+				t.Errorf("%s: ResourceUpToDate should be true for a losing candidate", cr.GetName())
+			}
+			if c := cr.GetCondition(v1beta1.TypeDefaultScanner); c.Reason != v1beta1.ReasonDefaultConflict {
+				t.Errorf("%s: DefaultScanner condition = %+v, want DefaultConflict", cr.GetName(), c)
+			}
+		}
+	}
+
+	obs, err := e.Observe(ctx, tied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false while the elected scanner is not Harbor's default")
+	}
+	if c := tied.GetCondition(v1beta1.TypeDefaultScanner); c.Status != corev1.ConditionTrue {
+		t.Errorf("DefaultScanner condition = %+v, want True", c)
+	}
+	if _, err := e.Update(ctx, tied); err != nil {
+		t.Fatal(err)
+	}
+	if len(setDefaults) != 1 || setDefaults[0] != "uuid-aqua" {
+		t.Fatalf("SetDefaultScanner calls = %v, want [uuid-aqua]", setDefaults)
+	}
+
+	harborDefault = "aqua"
+	if obs, _ = e.Observe(ctx, tied); !obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be true once the elected scanner is the default")
+	}
+
+	// A different Harbor instance elects independently.
+	if _, err := e.Observe(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	if c := other.GetCondition(v1beta1.TypeDefaultScanner); c.Status != corev1.ConditionTrue {
+		t.Errorf("other Harbor: DefaultScanner condition = %+v, want True", c)
+	}
+}
+
+func TestEnqueueDefaultCandidates(t *testing.T) {
+	a := defaultScanner("ns", "a", "harbor", 2*time.Hour, true)
+	b := defaultScanner("ns", "b", "harbor", time.Hour, true)
+	c := defaultScanner("ns", "c", "harbor", time.Hour, false)
+	d := defaultScanner("ns", "d", "other-harbor", time.Hour, true)
+	h := enqueueDefaultCandidates(scannerKube(t, a, b, c, d))
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	// a stopped asking to be the default.
+	notDefault := false
+	a.Spec.ForProvider.IsDefault = &notDefault
+	h.Update(context.Background(), event.UpdateEvent{ObjectOld: a, ObjectNew: a}, q)
+	if q.Len() != 1 {
+		t.Fatalf("enqueued %d requests, want 1", q.Len())
+	}
+	if got, _ := q.Get(); got.Name != "b" {
+		t.Errorf("enqueued %v, want ns/b", got)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package scanner
+
+import (
+	"context"
+
+	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+)
+
+// Scanner health as Harbor reports it: a scanner is healthy when Harbor can
+// read its adapter's metadata.
+const (
+	healthHealthy   = "healthy"
+	healthUnhealthy = "unhealthy"
+)
+
+// observeMetadata records the scanner adapter's declared identity and
+// capabilities in status. An adapter that cannot be reached marks the
+// scanner unhealthy but keeps the capabilities last read from it, so
+// compositions branching on them do not flap while it restarts.
+func (c *external) observeMetadata(ctx context.Context, cr *v1beta1.ScannerRegistration, uuid string) {
+	md, err := c.service.GetScannerMetadata(ctx, uuid)
+	if err != nil || md == nil {
+		c.logger.Debug("Cannot read scanner adapter metadata", "name", cr.Spec.ForProvider.Name, "error", err)
+		health := healthUnhealthy
+		cr.Status.AtProvider.Health = &health
+		return
+	}
+
+	health := healthHealthy
+	cr.Status.AtProvider.Health = &health
+	cr.Status.AtProvider.Adapter = optional(md.Adapter)
+	cr.Status.AtProvider.Vendor = optional(md.Vendor)
+	cr.Status.AtProvider.Version = optional(md.Version)
+
+	caps := make([]v1beta1.ScannerCapability, 0, len(md.Capabilities))
+	for _, sc := range md.Capabilities {
+		caps = append(caps, v1beta1.ScannerCapability{
+			Type:              sc.Type,
+			ConsumesMimeTypes: sc.ConsumesMimeTypes,
+			ProducesMimeTypes: sc.ProducesMimeTypes,
+		})
+	}
+	cr.Status.AtProvider.Capabilities = caps
+	sbom := md.SupportsSBOM()
+	cr.Status.AtProvider.SupportsSBOM = &sbom
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
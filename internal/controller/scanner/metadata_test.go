@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package scanner
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+func TestObserveScannerMetadata(t *testing.T) {
+	trivy := &harborclients.ScannerMetadata{
+		Adapter: "Trivy",
+		Vendor:  "Aqua Security",
+		Version: "v0.50.1",
+		Capabilities: []harborclients.ScannerCapability{
+			{Type: "vulnerability", ConsumesMimeTypes: []string{"application/vnd.oci.image.manifest.v1+json"}},
+			{Type: "sbom", ConsumesMimeTypes: []string{"application/vnd.oci.image.manifest.v1+json"}},
+		},
+	}
+	trivyCaps := []v1beta1.ScannerCapability{
+		{Type: "vulnerability", ConsumesMimeTypes: []string{"application/vnd.oci.image.manifest.v1+json"}},
+		{Type: "sbom", ConsumesMimeTypes: []string{"application/vnd.oci.image.manifest.v1+json"}},
+	}
+	clair := &harborclients.ScannerMetadata{
+		Adapter:      "Clair",
+		Capabilities: []harborclients.ScannerCapability{{Type: "vulnerability"}},
+	}
+
+	cases := map[string]struct {
+		md       *harborclients.ScannerMetadata
+		err      error
+		previous []v1beta1.ScannerCapability
+		health   string
+		caps     []v1beta1.ScannerCapability
+		sbom     *bool
+	}{
+		"SBOM": {
+			md:     trivy,
+			health: healthHealthy,
+			caps:   trivyCaps,
+			sbom:   ptrBool(true),
+		},
+		"NoSBOM": {
+			md:     clair,
+			health: healthHealthy,
+			caps:   []v1beta1.ScannerCapability{{Type: "vulnerability"}},
+			sbom:   ptrBool(false),
+		},
+		"UnreachableKeepsCapabilities": {
+			err:      errors.New("connection refused"),
+			previous: trivyCaps,
+			health:   healthUnhealthy,
+			caps:     trivyCaps,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1beta1.ScannerRegistration{
+				Spec: v1beta1.ScannerRegistrationSpec{
+					ForProvider: v1beta1.ScannerRegistrationParameters{Name: "trivy", URL: "http://trivy:8080"},
+				},
+			}
+			cr.Status.AtProvider.Capabilities = tc.previous
+			var gotUUID string
+			ext := &external{
+				logger: logging.NewNopLogger(),
+				service: &mockScannerClient{
+					getScannerRegistrationFunc: func(context.Context, string) (*harborclients.ScannerStatus, error) {
+						return &harborclients.ScannerStatus{UUID: "uuid-trivy", Name: "trivy", URL: "http://trivy:8080"}, nil
+					},
+					getScannerMetadataFunc: func(_ context.Context, id string) (*harborclients.ScannerMetadata, error) {
+						gotUUID = id
+						return tc.md, tc.err
+					},
+				},
+			}
+
+			if _, err := ext.Observe(context.Background(), cr); err != nil {
+				t.Fatal(err)
+			}
+			at := cr.Status.AtProvider
+			if gotUUID != "uuid-trivy" {
+				t.Errorf("GetScannerMetadata() called with %q, want uuid-trivy", gotUUID)
+			}
+			if at.Health == nil || *at.Health != tc.health {
+				t.Errorf("Health = %v, want %s", at.Health, tc.health)
+			}
+			if !reflect.DeepEqual(at.Capabilities, tc.caps) {
+				t.Errorf("Capabilities = %+v, want %+v", at.Capabilities, tc.caps)
+			}
+			if !reflect.DeepEqual(at.SupportsSBOM, tc.sbom) {
+				t.Errorf("SupportsSBOM = %v, want %v", at.SupportsSBOM, tc.sbom)
+			}
+		})
+	}
+}
+
+func ptrBool(b bool) *bool {
+	return &b
+}
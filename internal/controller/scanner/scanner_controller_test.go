@@ -771,6 +771,57 @@ type mockScannerClient struct {
 	updateScannerRegistrationFunc func(ctx context.Context, scannerID string, spec *harborclients.ScannerSpec) (*harborclients.ScannerStatus, error)
 	deleteScannerRegistrationFunc func(ctx context.Context, scannerID string) error
 	closeFunc                     func() error
+
+	listRobotsFunc         func(ctx context.Context, projectID *string) ([]*harborclients.RobotStatus, error)
+	createRobotFunc        func(ctx context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error)
+	deleteRobotFunc        func(ctx context.Context, robotID string) error
+	refreshRobotSecretFunc func(ctx context.Context, robotID string) (string, error)
+
+	setDefaultScannerFunc func(ctx context.Context, scannerID string) error
+
+	getScannerMetadataFunc func(ctx context.Context, scannerID string) (*harborclients.ScannerMetadata, error)
+}
+
+func (m *mockScannerClient) GetScannerMetadata(ctx context.Context, scannerID string) (*harborclients.ScannerMetadata, error) {
+	if m.getScannerMetadataFunc != nil {
+		return m.getScannerMetadataFunc(ctx, scannerID)
+	}
+	return &harborclients.ScannerMetadata{}, nil
+}
+
+func (m *mockScannerClient) SetDefaultScanner(ctx context.Context, scannerID string) error {
+	if m.setDefaultScannerFunc != nil {
+		return m.setDefaultScannerFunc(ctx, scannerID)
+	}
+	return nil
+}
+
+func (m *mockScannerClient) ListRobots(ctx context.Context, projectID *string) ([]*harborclients.RobotStatus, error) {
+	if m.listRobotsFunc != nil {
+		return m.listRobotsFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+func (m *mockScannerClient) CreateRobot(ctx context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
+	if m.createRobotFunc != nil {
+		return m.createRobotFunc(ctx, spec)
+	}
+	return &harborclients.RobotStatus{ID: "1", Name: "robot$" + spec.Name, Secret: "secret"}, nil
+}
+
+func (m *mockScannerClient) DeleteRobot(ctx context.Context, robotID string) error {
+	if m.deleteRobotFunc != nil {
+		return m.deleteRobotFunc(ctx, robotID)
+	}
+	return nil
+}
+
+func (m *mockScannerClient) RefreshRobotSecret(ctx context.Context, robotID string) (string, error) {
+	if m.refreshRobotSecretFunc != nil {
+		return m.refreshRobotSecretFunc(ctx, robotID)
+	}
+	return "refreshed", nil
 }
 
 func (m *mockScannerClient) GetScannerRegistration(ctx context.Context, scannerID string) (*harborclients.ScannerStatus, error) {
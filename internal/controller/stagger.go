@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+)
+
+// startupStagger spreads the first reconcile of each resource across a window
+// after the provider starts. Without it every managed resource is observed at
+// once when the provider is restarted or upgraded.
+type startupStagger struct {
+	ratelimiter.RateLimiter
+
+	start  time.Time
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewStartupStagger wraps l so that, until window has passed since it was
+// created, the first request for each item is delayed by an offset within the
+// window derived from the item's name. The same resource therefore lands in
+// the same slot on every restart. A window of zero returns l unchanged.
+func NewStartupStagger(l ratelimiter.RateLimiter, window time.Duration) ratelimiter.RateLimiter {
+	if window <= 0 {
+		return l
+	}
+	return &startupStagger{RateLimiter: l, start: time.Now(), window: window, now: time.Now, seen: map[string]bool{}}
+}
+
+func (s *startupStagger) When(item string) time.Duration {
+	d := s.RateLimiter.When(item)
+
+	elapsed := s.now().Sub(s.start)
+	if elapsed >= s.window {
+		return d
+	}
+
+	s.mu.Lock()
+	first := !s.seen[item]
+	s.seen[item] = true
+	s.mu.Unlock()
+	if !first {
+		return d
+	}
+
+	if wait := staggerOffset(item, s.window) - elapsed; wait > d {
+		return wait
+	}
+	return d
+}
+
+func staggerOffset(item string, window time.Duration) time.Duration {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(item))
+	return time.Duration(h.Sum64() % uint64(window))
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
+)
+
+type noLimit struct{ ratelimiter.RateLimiter }
+
+func (noLimit) When(string) time.Duration { return 0 }
+
+func TestStartupStagger(t *testing.T) {
+	window := time.Minute
+	l := NewStartupStagger(noLimit{}, window).(*startupStagger)
+	now := l.start
+	l.now = func() time.Time { return now }
+
+	items := []string{"project/default/a", "project/default/b", "robot/default/c", "webhook/default/d"}
+	distinct := map[time.Duration]bool{}
+	for _, item := range items {
+		d := l.When(item)
+		if d < 0 || d >= window {
+			t.Errorf("When(%q) = %v, want within [0, %v)", item, d, window)
+		}
+		if d != staggerOffset(item, window) {
+			t.Errorf("When(%q) = %v, want stable offset %v", item, d, staggerOffset(item, window))
+		}
+		distinct[d] = true
+
+		// Only the first request is staggered.
+		if d := l.When(item); d != 0 {
+			t.Errorf("second When(%q) = %v, want 0", item, d)
+		}
+	}
+	if len(distinct) < 2 {
+		t.Errorf("offsets not spread: %v", distinct)
+	}
+
+	now = now.Add(window)
+	if d := l.When("project/default/new"); d != 0 {
+		t.Errorf("When() after window = %v, want 0", d)
+	}
+}
+
+func TestStartupStaggerDisabled(t *testing.T) {
+	var l ratelimiter.RateLimiter = noLimit{}
+	if got := NewStartupStagger(l, 0); got != l {
+		t.Error("NewStartupStagger() with zero window should return the limiter unchanged")
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
+)
+
+// strictFields is whether writes are read back and fail when Harbor ignored
+// fields of them.
+var strictFields atomic.Bool
+
+// SetStrictFields turns strict mode on or off. In strict mode the controllers
+// that write free-form payloads read them back after writing, and fail the
+// reconcile, setting the FieldsIgnored condition, when Harbor ignored any of
+// the fields they wrote.
+func SetStrictFields(strict bool) {
+	strictFields.Store(strict)
+}
+
+// StrictFields reports whether strict mode is on.
+func StrictFields() bool {
+	return strictFields.Load()
+}
+
+// IgnoredFields returns the paths, such as metadata.auto_scan, of the fields
+// written sets to a value other than a JSON zero value that readBack lacks,
+// sorted. Both are compared as JSON. Fields readBack has with a different
+// value are not ignored, only changed, and are left to drift detection.
+func IgnoredFields(written, readBack any) ([]string, error) {
+	w, err := asJSON(written)
+	if err != nil {
+		return nil, err
+	}
+	r, err := asJSON(readBack)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	ignoredFields(w, r, "", &fields)
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func ignoredFields(written, readBack any, prefix string, fields *[]string) {
+	w, ok := written.(map[string]any)
+	if !ok {
+		return
+	}
+	r, _ := readBack.(map[string]any)
+	for k, wv := range w {
+		rv, ok := r[k]
+		if !ok {
+			if !isZeroJSON(wv) {
+				*fields = append(*fields, prefix+k)
+			}
+			continue
+		}
+		ignoredFields(wv, rv, prefix+k+".", fields)
+	}
+}
+
+// ReportIgnoredFields sets the FieldsIgnored condition of mg from fields and
+// returns an error naming them, if there are any. Outside strict mode it
+// does nothing.
+func ReportIgnoredFields(mg resource.Managed, fields []string) error {
+	if !StrictFields() {
+		return nil
+	}
+	if len(fields) == 0 {
+		mg.SetConditions(common.NoFieldsIgnored())
+		return nil
+	}
+	mg.SetConditions(common.FieldsIgnored(fields))
+	return errors.Errorf("strict mode: Harbor ignored %s", strings.Join(fields, ", "))
+}
+
+// CheckReadBack, in strict mode, reads back what was written to Harbor for
+// mg with read and reports the fields of written Harbor ignored with
+// ReportIgnoredFields. Outside strict mode it does nothing.
+func CheckReadBack(mg resource.Managed, written any, read func() (any, error)) error {
+	if !StrictFields() {
+		return nil
+	}
+	readBack, err := read()
+	if err != nil {
+		return errors.Wrap(err, "cannot read back from Harbor")
+	}
+	fields, err := IgnoredFields(written, readBack)
+	if err != nil {
+		return err
+	}
+	return ReportIgnoredFields(mg, fields)
+}
+
+// asJSON returns v as encoding/json decodes it into an interface value.
+// Raw JSON documents are decoded as they are.
+func asJSON(v any) (any, error) {
+	raw, ok := v.([]byte)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(v); err != nil {
+			return nil, errors.Wrap(err, "cannot encode as JSON")
+		}
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON")
+	}
+	return out, nil
+}
+
+// isZeroJSON reports whether v is null, false, 0, "", or an empty list or
+// object.
+func isZeroJSON(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !t
+	case float64:
+		return t == 0
+	case string:
+		return t == ""
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	}
+	return false
+}
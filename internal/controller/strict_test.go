@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/apis/common"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIgnoredFields(t *testing.T) {
+	cases := map[string]struct {
+		written  any
+		readBack any
+		want     []string
+	}{
+		"Kept": {
+			written:  []byte(`{"name": "web", "public": true}`),
+			readBack: []byte(`{"id": 1, "name": "web", "public": true}`),
+		},
+		"Changed": {
+			written:  []byte(`{"name": "web"}`),
+			readBack: []byte(`{"name": "api"}`),
+		},
+		"ZeroLeftOut": {
+			written:  []byte(`{"name": "web", "public": false, "tags": []}`),
+			readBack: []byte(`{"name": "web"}`),
+		},
+		"Ignored": {
+			written:  []byte(`{"name": "web", "metadata": {"auto_sbom_generation": "true", "public": "true"}, "retention": 7}`),
+			readBack: []byte(`{"name": "web", "metadata": {"public": "true"}}`),
+			want:     []string{"metadata.auto_sbom_generation", "retention"},
+		},
+		"Maps": {
+			written:  map[string]any{"read_only": true, "banner_message": "{}"},
+			readBack: map[string]any{"read_only": false},
+			want:     []string{"banner_message"},
+		},
+		"EmptyReadBack": {
+			written: []byte(`{"name": "web"}`),
+			want:    []string{"name"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := IgnoredFields(tc.written, tc.readBack)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("IgnoredFields() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckReadBack(t *testing.T) {
+	written := []byte(`{"name": "web", "retention": 7}`)
+	read := func() (any, error) { return []byte(`{"name": "web"}`), nil }
+
+	mg := managedProject("team-a", "default", time.Hour)
+	if err := CheckReadBack(mg, written, func() (any, error) {
+		t.Error("read back outside strict mode")
+		return nil, nil
+	}); err != nil {
+		t.Errorf("CheckReadBack() outside strict mode error = %v", err)
+	}
+	if c := mg.GetCondition(common.TypeFieldsIgnored); c.Status != corev1.ConditionUnknown {
+		t.Errorf("FieldsIgnored condition set outside strict mode: %+v", c)
+	}
+
+	SetStrictFields(true)
+	t.Cleanup(func() { SetStrictFields(false) })
+
+	if err := CheckReadBack(mg, written, read); err == nil || err.Error() != "strict mode: Harbor ignored retention" {
+		t.Errorf("CheckReadBack() error = %v", err)
+	}
+	if c := mg.GetCondition(common.TypeFieldsIgnored); c.Status != corev1.ConditionTrue || c.Reason != common.ReasonIgnoredByHarbor {
+		t.Errorf("FieldsIgnored condition = %+v", c)
+	}
+
+	if err := CheckReadBack(mg, []byte(`{"name": "web"}`), read); err != nil {
+		t.Errorf("CheckReadBack() error = %v", err)
+	}
+	if c := mg.GetCondition(common.TypeFieldsIgnored); c.Status != corev1.ConditionFalse {
+		t.Errorf("FieldsIgnored condition = %+v, want False", c)
+	}
+
+	failed := errors.New("boom")
+	if err := CheckReadBack(mg, written, func() (any, error) { return nil, failed }); !errors.Is(err, failed) {
+		t.Errorf("CheckReadBack() error = %v, want %v", err, failed)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/rossigee/provider-harbor/apis/common"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A SyncStatusHolder is a managed resource that records its last observation.
+type SyncStatusHolder interface {
+	GetSyncStatus() *common.SyncStatus
+}
+
+// WithSyncStatus wraps c so that each successful Observe of an existing
+// external resource records the time, and whether the resource had drifted,
+// in the managed resource's status. It also records in
+// harbor_sync_latency_seconds how long each new generation of the spec took
+// to be applied in Harbor.
+func WithSyncStatus(c managed.ExternalConnector) managed.ExternalConnector {
+	return &syncStatusConnector{ExternalConnector: c, now: time.Now}
+}
+
+type syncStatusConnector struct {
+	managed.ExternalConnector
+	now func() time.Time
+}
+
+func (c *syncStatusConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ext, err := c.ExternalConnector.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &syncStatusClient{ExternalClient: ext, now: c.now}, nil
+}
+
+type syncStatusClient struct {
+	managed.ExternalClient
+	now func() time.Time
+}
+
+func (e *syncStatusClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	obs, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return obs, err
+	}
+	h, ok := mg.(SyncStatusHolder)
+	if !ok {
+		return obs, nil
+	}
+	now := e.now()
+	trackGeneration(mg, h.GetSyncStatus(), obs, now)
+	if obs.ResourceExists {
+		h.GetSyncStatus().SetSynced(metav1.NewTime(now), !obs.ResourceUpToDate)
+	}
+	return obs, nil
+}
+
+// trackGeneration records when a new generation of mg's spec is first seen
+// not yet applied in Harbor and, once an Observe finds it applied, how long
+// that took. A resource that does not exist yet has been pending since it
+// was created. A resource observed applied before its generation was
+// tracked is not counted, since when its spec changed is unknown.
+func trackGeneration(mg resource.Managed, s *common.SyncStatus, obs managed.ExternalObservation, now time.Time) {
+	gen := mg.GetGeneration()
+	if s.AppliedGeneration != nil && *s.AppliedGeneration >= gen {
+		return
+	}
+	if obs.ResourceExists && obs.ResourceUpToDate {
+		if s.PendingSince != nil {
+			metrics.ObserveSyncLatency(kindOf(mg), now.Sub(s.PendingSince.Time))
+		}
+		s.AppliedGeneration = &gen
+		s.PendingSince = nil
+		return
+	}
+	if s.PendingSince != nil {
+		return
+	}
+	since := metav1.NewTime(now)
+	if created := mg.GetCreationTimestamp(); !obs.ResourceExists && s.AppliedGeneration == nil && !created.IsZero() {
+		since = created
+	}
+	s.PendingSince = &since
+}
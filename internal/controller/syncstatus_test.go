@@ -0,0 +1,154 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeExternal struct {
+	managed.ExternalClient
+	obs managed.ExternalObservation
+	err error
+}
+
+func (f *fakeExternal) Observe(context.Context, resource.Managed) (managed.ExternalObservation, error) {
+	return f.obs, f.err
+}
+
+type fakeConnector struct{ ext managed.ExternalClient }
+
+func (f *fakeConnector) Connect(context.Context, resource.Managed) (managed.ExternalClient, error) {
+	return f.ext, nil
+}
+
+func TestWithSyncStatus(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		obs       managed.ExternalObservation
+		err       error
+		wantSync  bool
+		wantDrift bool
+	}{
+		"UpToDate": {
+			obs:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			wantSync: true,
+		},
+		"Drifted": {
+			obs:       managed.ExternalObservation{ResourceExists: true},
+			wantSync:  true,
+			wantDrift: true,
+		},
+		"NotFound": {
+			obs: managed.ExternalObservation{},
+		},
+		"Error": {
+			obs: managed.ExternalObservation{ResourceExists: true},
+			err: errors.New("boom"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := WithSyncStatus(&fakeConnector{ext: &fakeExternal{obs: tc.obs, err: tc.err}}).(*syncStatusConnector)
+			c.now = func() time.Time { return now }
+
+			cr := &projectv1beta1.Project{}
+			ext, err := c.Connect(context.Background(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ext.Observe(context.Background(), cr); !errors.Is(err, tc.err) {
+				t.Fatalf("Observe() error = %v, want %v", err, tc.err)
+			}
+
+			s := cr.GetSyncStatus()
+			if (s.LastSyncTime != nil) != tc.wantSync {
+				t.Fatalf("LastSyncTime = %v, want set: %v", s.LastSyncTime, tc.wantSync)
+			}
+			if !tc.wantSync {
+				return
+			}
+			if !s.LastSyncTime.Time.Equal(now) {
+				t.Errorf("LastSyncTime = %v, want %v", s.LastSyncTime, now)
+			}
+			if *s.Drift != tc.wantDrift {
+				t.Errorf("Drift = %v, want %v", *s.Drift, tc.wantDrift)
+			}
+		})
+	}
+}
+
+func syncLatency(t *testing.T) (uint64, float64) {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := metrics.SyncLatency.WithLabelValues("Project").(prometheus.Metric).Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
+
+func TestTrackGeneration(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cr := &projectv1beta1.Project{}
+	cr.SetCreationTimestamp(metav1.NewTime(created))
+	cr.SetGeneration(1)
+	s := cr.GetSyncStatus()
+
+	missing := managed.ExternalObservation{}
+	drifted := managed.ExternalObservation{ResourceExists: true}
+	applied := managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+
+	steps := []struct {
+		reason     string
+		generation int64
+		obs        managed.ExternalObservation
+		after      time.Duration
+		wantCount  uint64
+		wantSum    float64
+	}{
+		{reason: "A new resource is pending since it was created.", generation: 1, obs: missing, after: 10 * time.Second},
+		{reason: "Creating it took 40s.", generation: 1, obs: applied, after: 40 * time.Second, wantCount: 1, wantSum: 40},
+		{reason: "Drift in Harbor is not a spec change.", generation: 1, obs: drifted, after: 50 * time.Second, wantCount: 1, wantSum: 40},
+		{reason: "A new generation is pending since it was first seen.", generation: 2, obs: drifted, after: 100 * time.Second, wantCount: 1, wantSum: 40},
+		{reason: "Later observations do not move the start.", generation: 2, obs: drifted, after: 110 * time.Second, wantCount: 1, wantSum: 40},
+		{reason: "Applying it took 30s.", generation: 2, obs: applied, after: 130 * time.Second, wantCount: 2, wantSum: 70},
+		{reason: "An applied generation is counted once.", generation: 2, obs: applied, after: 140 * time.Second, wantCount: 2, wantSum: 70},
+	}
+
+	countBefore, sumBefore := syncLatency(t)
+	for _, step := range steps {
+		cr.SetGeneration(step.generation)
+		trackGeneration(cr, s, step.obs, created.Add(step.after))
+		count, sum := syncLatency(t)
+		if count-countBefore != step.wantCount || sum-sumBefore != step.wantSum {
+			t.Fatalf("%s\nrecorded %d latencies totalling %vs, want %d totalling %vs", step.reason, count-countBefore, sum-sumBefore, step.wantCount, step.wantSum)
+		}
+	}
+	if s.AppliedGeneration == nil || *s.AppliedGeneration != 2 || s.PendingSince != nil {
+		t.Errorf("AppliedGeneration = %v, PendingSince = %v, want 2 and nil", s.AppliedGeneration, s.PendingSince)
+	}
+
+	// A resource found applied before its generation was tracked has no
+	// known start and is not counted.
+	untracked := &projectv1beta1.Project{}
+	untracked.SetGeneration(3)
+	trackGeneration(untracked, untracked.GetSyncStatus(), applied, created)
+	if count, _ := syncLatency(t); count-countBefore != 2 {
+		t.Error("recorded the latency of a resource whose generation was not tracked")
+	}
+}
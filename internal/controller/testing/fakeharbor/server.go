@@ -0,0 +1,407 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package fakeharbor provides an in-memory Harbor API server for controller
+// tests. It implements the subset of the v2.0 API used by the robot and
+// webhook controllers and can inject rate limiting, server errors and
+// timeouts to exercise retry paths.
+package fakeharbor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// Username accepted by the fake server.
+	Username = "admin"
+	// Password accepted by the fake server.
+	Password = "Harbor12345"
+
+	apiPrefix = "/api/v2.0"
+)
+
+// Faults configures how often requests fail. Rates are probabilities in the
+// range [0, 1] and are evaluated in order: rate limit, server error, timeout.
+type Faults struct {
+	// RateLimit is the rate of requests answered with 429 Too Many Requests.
+	RateLimit float64
+	// ServerError is the rate of requests answered with 500.
+	ServerError float64
+	// Timeout is the rate of requests that hang until the client gives up.
+	Timeout float64
+	// LostResponse is the probability that a faulted write is applied
+	// before the failure is returned, as when a response is lost in transit.
+	LostResponse float64
+	// Seed seeds the fault generator so runs are reproducible.
+	Seed int64
+}
+
+type robot struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Level       string `json:"level"`
+	Duration    int64  `json:"duration"`
+	Secret      string `json:"-"`
+	Created     string `json:"creation_time"`
+	Updated     string `json:"update_time"`
+}
+
+type webhookPolicy struct {
+	ID          int64           `json:"id"`
+	ProjectID   int64           `json:"project_id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	EventTypes  []string        `json:"event_types"`
+	Enabled     bool            `json:"enabled"`
+	Targets     json.RawMessage `json:"targets"`
+	Created     string          `json:"creation_time"`
+	Updated     string          `json:"update_time"`
+}
+
+// Server is a fake Harbor API server.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	faults   Faults
+	rng      *rand.Rand
+	nextID   int64
+	robots   map[int64]*robot
+	webhooks map[string]map[int64]*webhookPolicy
+	requests int
+	injected int
+}
+
+// New starts a fake Harbor server that is closed when the test finishes.
+func New(t *testing.T, faults Faults) *Server {
+	t.Helper()
+
+	s := &Server{
+		faults:   faults,
+		rng:      rand.New(rand.NewSource(faults.Seed)), //nolint:gosec // deterministic test faults
+		robots:   map[int64]*robot{},
+		webhooks: map[string]map[int64]*webhookPolicy{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+apiPrefix+"/robots", s.listRobots)
+	mux.HandleFunc("POST "+apiPrefix+"/robots", s.createRobot)
+	mux.HandleFunc("DELETE "+apiPrefix+"/robots/{id}", s.deleteRobot)
+	mux.HandleFunc("GET "+apiPrefix+"/projects/{project}/webhook/policies", s.listWebhooks)
+	mux.HandleFunc("POST "+apiPrefix+"/projects/{project}/webhook/policies", s.createWebhook)
+	mux.HandleFunc("GET "+apiPrefix+"/projects/{project}/webhook/policies/{id}", s.getWebhook)
+	mux.HandleFunc("DELETE "+apiPrefix+"/projects/{project}/webhook/policies/{id}", s.deleteWebhook)
+
+	s.Server = httptest.NewServer(s.withFaults(s.withAuth(mux)))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetFaults replaces the fault configuration, e.g. to let a test settle.
+func (s *Server) SetFaults(f Faults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = f
+	s.rng = rand.New(rand.NewSource(f.Seed)) //nolint:gosec // deterministic test faults
+}
+
+// RobotNames returns the names of all robot accounts, including duplicates.
+func (s *Server) RobotNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.robots))
+	for _, r := range s.robots {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// WebhookNames returns the names of all webhook policies in a project.
+func (s *Server) WebhookNames(project string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.webhooks[project]))
+	for _, p := range s.webhooks[project] {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Stats returns the number of requests served and how many were faulted.
+func (s *Server) Stats() (requests, injected int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests, s.injected
+}
+
+type fault int
+
+const (
+	faultNone fault = iota
+	faultRateLimit
+	faultServerError
+	faultTimeout
+)
+
+func (s *Server) roll(write bool) (fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	f := faultNone
+	switch r := s.rng.Float64(); {
+	case r < s.faults.RateLimit:
+		f = faultRateLimit
+	case r < s.faults.RateLimit+s.faults.ServerError:
+		f = faultServerError
+	case r < s.faults.RateLimit+s.faults.ServerError+s.faults.Timeout:
+		f = faultTimeout
+	}
+	if f == faultNone {
+		return f, false
+	}
+	s.injected++
+
+	// Rate limiting is decided before Harbor does any work; other faults
+	// may strike after a write has been committed.
+	lost := write && f != faultRateLimit && s.rng.Float64() < s.faults.LostResponse
+	return f, lost
+}
+
+func (s *Server) withFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, lost := s.roll(r.Method != http.MethodGet)
+		if f == faultNone {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if lost {
+			next.ServeHTTP(httptest.NewRecorder(), r)
+		}
+
+		switch f {
+		case faultRateLimit:
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "rate limited")
+		case faultServerError:
+			writeError(w, http.StatusInternalServerError, "UNKNOWN", "internal error")
+		case faultTimeout:
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			writeError(w, http.StatusGatewayTimeout, "UNKNOWN", "timeout")
+		}
+	})
+}
+
+func (s *Server) withAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok || u != Username || p != Password {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) listRobots(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	list := make([]*robot, 0, len(s.robots))
+	for _, r := range s.robots {
+		list = append(list, r)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(list)))
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) createRobot(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Level       string `json:"level"`
+		Duration    int64  `json:"duration"`
+		Permissions []struct {
+			Namespace string `json:"namespace"`
+		} `json:"permissions"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	// Harbor prefixes robot names: robot$name for system robots and
+	// robot$project+name for project robots.
+	name := "robot$" + req.Name
+	if req.Level == "project" && len(req.Permissions) > 0 {
+		name = "robot$" + req.Permissions[0].Namespace + "+" + req.Name
+	}
+
+	s.mu.Lock()
+	for _, existing := range s.robots {
+		if existing.Name == name {
+			s.mu.Unlock()
+			writeError(w, http.StatusConflict, "CONFLICT", "robot "+name+" already exists")
+			return
+		}
+	}
+	s.nextID++
+	now := timestamp()
+	rb := &robot{
+		ID:          s.nextID,
+		Name:        name,
+		Description: req.Description,
+		Level:       req.Level,
+		Duration:    req.Duration,
+		Secret:      fmt.Sprintf("secret-%d", s.nextID),
+		Created:     now,
+		Updated:     now,
+	}
+	s.robots[rb.ID] = rb
+	s.mu.Unlock()
+
+	w.Header().Set("Location", fmt.Sprintf("%s/robots/%d", apiPrefix, rb.ID))
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":            rb.ID,
+		"name":          rb.Name,
+		"secret":        rb.Secret,
+		"creation_time": rb.Created,
+	})
+}
+
+func (s *Server) deleteRobot(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	_, ok := s.robots[id]
+	delete(s.robots, id)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "robot not found")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	project := r.PathValue("project")
+
+	s.mu.Lock()
+	list := make([]*webhookPolicy, 0, len(s.webhooks[project]))
+	for _, p := range s.webhooks[project] {
+		list = append(list, p)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(list)))
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	project := r.PathValue("project")
+
+	policy := &webhookPolicy{}
+	if err := decode(r, policy); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	if s.webhooks[project] == nil {
+		s.webhooks[project] = map[int64]*webhookPolicy{}
+	}
+	for _, existing := range s.webhooks[project] {
+		if existing.Name == policy.Name {
+			s.mu.Unlock()
+			writeError(w, http.StatusConflict, "CONFLICT", "webhook policy "+policy.Name+" already exists")
+			return
+		}
+	}
+	s.nextID++
+	now := timestamp()
+	policy.ID = s.nextID
+	policy.ProjectID, _ = strconv.ParseInt(project, 10, 64)
+	policy.Created = now
+	policy.Updated = now
+	s.webhooks[project][policy.ID] = policy
+	s.mu.Unlock()
+
+	w.Header().Set("Location", fmt.Sprintf("%s/projects/%s/webhook/policies/%d", apiPrefix, project, policy.ID))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) getWebhook(w http.ResponseWriter, r *http.Request) {
+	policy, ok := s.lookupWebhook(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "webhook policy not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, policy)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	policy, ok := s.lookupWebhook(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "webhook policy not found")
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.webhooks[r.PathValue("project")], policy.ID)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) lookupWebhook(r *http.Request) (*webhookPolicy, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.webhooks[r.PathValue("project")][id]
+	return p, ok
+}
+
+func decode(r *http.Request, v any) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, errCode, msg string) {
+	writeJSON(w, code, map[string]any{
+		"errors": []map[string]string{{"code": errCode, "message": msg}},
+	})
+}
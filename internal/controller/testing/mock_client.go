@@ -13,24 +13,46 @@ import (
 // MockHarborClient is a mock implementation of the Harbor client for testing
 type MockHarborClient struct {
 	// Base client methods
-	GetBaseURLFunc         func() string
-	CloseFunc              func() error
-	TestConnectionFunc     func(ctx context.Context) error
-	GetVersionFunc         func(ctx context.Context) (string, error)
-	GetMemoryFootprintFunc func() string
+	GetBaseURLFunc            func() string
+	CloseFunc                 func() error
+	TestConnectionFunc        func(ctx context.Context) error
+	GetVersionFunc            func(ctx context.Context) (string, error)
+	GetCurrentUserFunc        func(ctx context.Context) (*harborclients.CurrentUser, error)
+	GetSystemInfoFunc         func(ctx context.Context) (*harborclients.SystemInfo, error)
+	GetConfigurationsFunc     func(ctx context.Context) (harborclients.Configurations, error)
+	GetRobotPermissionsFunc   func(ctx context.Context) (*harborclients.RobotPermissions, error)
+	GetProjectPermissionsFunc func(ctx context.Context, projectID string) ([]harborclients.RobotAccess, error)
+	UpdateConfigurationsFunc  func(ctx context.Context, cfg harborclients.Configurations) error
+	PingOIDCFunc              func(ctx context.Context, endpoint string, verifyCert bool) error
+	InvalidateSystemCacheFunc func()
+	GetMemoryFootprintFunc    func() string
 
 	// User operations
-	GetUserFunc    func(ctx context.Context, username string) (*harborclients.UserStatus, error)
-	CreateUserFunc func(ctx context.Context, spec *harborclients.UserSpec) (*harborclients.UserStatus, error)
-	UpdateUserFunc func(ctx context.Context, username string, spec *harborclients.UserSpec) (*harborclients.UserStatus, error)
-	DeleteUserFunc func(ctx context.Context, username string) error
+	GetUserFunc                 func(ctx context.Context, username string) (*harborclients.UserStatus, error)
+	CreateUserFunc              func(ctx context.Context, spec *harborclients.UserSpec) (*harborclients.UserStatus, error)
+	UpdateUserFunc              func(ctx context.Context, username string, spec *harborclients.UserSpec) (*harborclients.UserStatus, error)
+	DeleteUserFunc              func(ctx context.Context, username string) error
+	RegenerateUserCLISecretFunc func(ctx context.Context, username string) (string, error)
 
 	// Project operations
-	GetProjectFunc    func(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error)
-	CreateProjectFunc func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error)
-	UpdateProjectFunc func(ctx context.Context, projectID string, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error)
-	DeleteProjectFunc func(ctx context.Context, projectID string) error
-	ListProjectsFunc  func(ctx context.Context) ([]*harborclients.ProjectStatus, error)
+	GetProjectFunc            func(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error)
+	CreateProjectFunc         func(ctx context.Context, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error)
+	UpdateProjectFunc         func(ctx context.Context, projectID string, spec *harborclients.ProjectSpec) (*harborclients.ProjectStatus, error)
+	DeleteProjectFunc         func(ctx context.Context, projectID string) error
+	ListProjectsFunc          func(ctx context.Context) ([]*harborclients.ProjectStatus, error)
+	ListProjectMetadataFunc   func(ctx context.Context, projectID string) (map[string]string, error)
+	SetProjectMetadataFunc    func(ctx context.Context, projectID, key, value string) error
+	DeleteProjectMetadataFunc func(ctx context.Context, projectID, key string) error
+	SampleProjectSBOMsFunc    func(ctx context.Context, projectName string, maxRepos int64) (*harborclients.SBOMSample, error)
+	InventoryRepositoriesFunc func(ctx context.Context, projects []string) (*harborclients.Partial[harborclients.InventoryRepository], error)
+	GetProjectSummaryFunc     func(ctx context.Context, projectName string) (*harborclients.ProjectSummary, error)
+	ListProjectAuditLogsFunc  func(ctx context.Context, projectName string, since time.Time, pageSize int64) ([]*harborclients.AuditLogEntry, error)
+	EnsureProjectLabelFunc    func(ctx context.Context, projectID int64, name, description string) (int64, error)
+	DeleteLabelFunc           func(ctx context.Context, labelID int64) error
+	FindLabelFunc             func(ctx context.Context, name, projectName string) (id int64, found bool, err error)
+	ListArtifactsByLabelFunc  func(ctx context.Context, projectName, repositoryName string, labelID int64) (with, without []string, err error)
+	GetArtifactLabelsFunc     func(ctx context.Context, projectName, repositoryName, reference string) (digest string, labelIDs []int64, err error)
+	SetArtifactLabelFunc      func(ctx context.Context, projectName, repositoryName, reference string, labelID int64, attached bool) error
 
 	// Scanner operations
 	CreateScannerRegistrationFunc func(ctx context.Context, spec *harborclients.ScannerSpec) (*harborclients.ScannerStatus, error)
@@ -38,12 +60,28 @@ type MockHarborClient struct {
 	UpdateScannerRegistrationFunc func(ctx context.Context, scannerID string, spec *harborclients.ScannerSpec) (*harborclients.ScannerStatus, error)
 	DeleteScannerRegistrationFunc func(ctx context.Context, scannerID string) error
 	ListScannerRegistrationsFunc  func(ctx context.Context) ([]*harborclients.ScannerStatus, error)
+	SetDefaultScannerFunc         func(ctx context.Context, scannerID string) error
+	GetProjectScannerFunc         func(ctx context.Context, projectName string) (*harborclients.ScannerStatus, error)
+	SetProjectScannerFunc         func(ctx context.Context, projectName, scannerID string) error
+	GetScannerMetadataFunc        func(ctx context.Context, scannerID string) (*harborclients.ScannerMetadata, error)
 
 	// Registry operations
 	CreateRegistryFunc func(ctx context.Context, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error)
 	GetRegistryFunc    func(ctx context.Context, registryName string) (*harborclients.RegistryStatus, error)
 	UpdateRegistryFunc func(ctx context.Context, registryName string, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error)
 	DeleteRegistryFunc func(ctx context.Context, registryName string) error
+	PingRegistryFunc   func(ctx context.Context, id int64) error
+
+	// Garbage collection operations
+	GetGCScheduleFunc    func(ctx context.Context) (*harborclients.GCSchedule, error)
+	CreateGCScheduleFunc func(ctx context.Context, s *harborclients.GCSchedule) error
+	UpdateGCScheduleFunc func(ctx context.Context, s *harborclients.GCSchedule) error
+
+	// Immutable tag rule operations
+	ListImmutableTagRulesFunc  func(ctx context.Context, projectID string) ([]*harborclients.ImmutableTagRule, error)
+	CreateImmutableTagRuleFunc func(ctx context.Context, projectID string, rule *harborclients.ImmutableTagRule) (int64, error)
+	UpdateImmutableTagRuleFunc func(ctx context.Context, projectID string, rule *harborclients.ImmutableTagRule) error
+	DeleteImmutableTagRuleFunc func(ctx context.Context, projectID string, id int64) error
 
 	// Repository operations
 	ListRepositoriesFunc func(ctx context.Context, projectID string) ([]*harborclients.RepositoryStatus, error)
@@ -58,11 +96,15 @@ type MockHarborClient struct {
 	GetArtifactVulnerabilitiesFunc func(ctx context.Context, projectID, repoName, reference string) (*harborclients.ArtifactStatus, error)
 
 	// Member operations
-	AddProjectMemberFunc    func(ctx context.Context, projectID, username, role string) error
-	ListProjectMembersFunc  func(ctx context.Context, projectID string) ([]*harborclients.MemberStatus, error)
-	GetProjectMemberFunc    func(ctx context.Context, projectID, username string) (*harborclients.MemberStatus, error)
-	UpdateProjectMemberFunc func(ctx context.Context, projectID, username, role string) error
-	DeleteProjectMemberFunc func(ctx context.Context, projectID, username string) error
+	AddProjectMemberFunc         func(ctx context.Context, projectID, username, role string) error
+	ListProjectMembersFunc       func(ctx context.Context, projectID string) ([]*harborclients.MemberStatus, error)
+	GetProjectMemberFunc         func(ctx context.Context, projectID, username string) (*harborclients.MemberStatus, error)
+	UpdateProjectMemberFunc      func(ctx context.Context, projectID, username, role string) error
+	DeleteProjectMemberFunc      func(ctx context.Context, projectID, username string) error
+	AddProjectGroupMemberFunc    func(ctx context.Context, projectName, groupName string, groupType int64, role string) error
+	GetProjectGroupMemberFunc    func(ctx context.Context, projectID, groupName string) (*harborclients.MemberStatus, error)
+	UpdateProjectGroupMemberFunc func(ctx context.Context, projectID, groupName, role string) error
+	DeleteProjectGroupMemberFunc func(ctx context.Context, projectID, groupName string) error
 
 	// Scan operations
 	TriggerScanFunc func(ctx context.Context, projectID, repoName, reference string) error
@@ -70,12 +112,20 @@ type MockHarborClient struct {
 	GetScanFunc     func(ctx context.Context, projectID, repoName, reference string) (*harborclients.ScanStatus, error)
 	StopScanFunc    func(ctx context.Context, projectID, repoName, reference string) error
 
+	ScanProjectFunc           func(ctx context.Context, projectName string) (int64, error)
+	StopProjectScansFunc      func(ctx context.Context, projectName string) (int64, error)
+	GetProjectScanMetricsFunc func(ctx context.Context, projectName string) (*harborclients.ScanMetrics, error)
+	TriggerScanAllFunc        func(ctx context.Context) error
+	StopScanAllFunc           func(ctx context.Context) error
+	GetScanAllMetricsFunc     func(ctx context.Context) (*harborclients.ScanMetrics, error)
+
 	// Robot operations
-	CreateRobotFunc func(ctx context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error)
-	ListRobotsFunc  func(ctx context.Context, projectID *string) ([]*harborclients.RobotStatus, error)
-	GetRobotFunc    func(ctx context.Context, robotID string) (*harborclients.RobotStatus, error)
-	UpdateRobotFunc func(ctx context.Context, robotID string, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error)
-	DeleteRobotFunc func(ctx context.Context, robotID string) error
+	CreateRobotFunc        func(ctx context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error)
+	ListRobotsFunc         func(ctx context.Context, projectID *string) ([]*harborclients.RobotStatus, error)
+	GetRobotFunc           func(ctx context.Context, robotID string) (*harborclients.RobotStatus, error)
+	UpdateRobotFunc        func(ctx context.Context, robotID string, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error)
+	DeleteRobotFunc        func(ctx context.Context, robotID string) error
+	RefreshRobotSecretFunc func(ctx context.Context, robotID string) (string, error)
 
 	// Webhook operations
 	CreateWebhookFunc func(ctx context.Context, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error)
@@ -94,11 +144,18 @@ type MockHarborClient struct {
 	ListReplicationExecutionsFunc func(ctx context.Context, policyID string) ([]*harborclients.ReplicationExecution, error)
 
 	// Retention operations
-	CreateRetentionPolicyFunc func(ctx context.Context, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error)
-	ListRetentionPoliciesFunc func(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error)
-	GetRetentionPolicyFunc    func(ctx context.Context, projectID, policyID string) (*harborclients.RetentionPolicyStatus, error)
-	UpdateRetentionPolicyFunc func(ctx context.Context, projectID, policyID string, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error)
-	DeleteRetentionPolicyFunc func(ctx context.Context, projectID, policyID string) error
+	CreateRetentionPolicyFunc   func(ctx context.Context, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error)
+	ListRetentionPoliciesFunc   func(ctx context.Context, projectID string) ([]*harborclients.RetentionPolicyStatus, error)
+	GetRetentionPolicyFunc      func(ctx context.Context, projectID, policyID string) (*harborclients.RetentionPolicyStatus, error)
+	UpdateRetentionPolicyFunc   func(ctx context.Context, projectID, policyID string, spec *harborclients.RetentionPolicySpec) (*harborclients.RetentionPolicyStatus, error)
+	DeleteRetentionPolicyFunc   func(ctx context.Context, projectID, policyID string) error
+	GetProjectRetentionIDFunc   func(ctx context.Context, projectID string) (string, error)
+	SetProjectRetentionIDFunc   func(ctx context.Context, projectID, policyID string) error
+	ListRetentionExecutionsFunc func(ctx context.Context, policyID string) ([]*harborclients.RetentionExecution, error)
+	CountRetentionTasksFunc     func(ctx context.Context, policyID, executionID string) (*harborclients.RetentionTaskCounts, error)
+	GetRawFunc                  func(ctx context.Context, path string) ([]byte, error)
+	PutRawFunc                  func(ctx context.Context, path string, body []byte) error
+	DeleteRawFunc               func(ctx context.Context, path string) error
 }
 
 // GetBaseURL calls GetBaseURLFunc
@@ -133,6 +190,69 @@ func (m *MockHarborClient) GetVersion(ctx context.Context) (string, error) {
 	return "v2.8.0", nil
 }
 
+// GetCurrentUser calls GetCurrentUserFunc
+func (m *MockHarborClient) GetCurrentUser(ctx context.Context) (*harborclients.CurrentUser, error) {
+	if m.GetCurrentUserFunc != nil {
+		return m.GetCurrentUserFunc(ctx)
+	}
+	return &harborclients.CurrentUser{Username: "admin", SysAdmin: true}, nil
+}
+
+// GetSystemInfo calls GetSystemInfoFunc
+func (m *MockHarborClient) GetSystemInfo(ctx context.Context) (*harborclients.SystemInfo, error) {
+	if m.GetSystemInfoFunc != nil {
+		return m.GetSystemInfoFunc(ctx)
+	}
+	return &harborclients.SystemInfo{HarborVersion: "v2.8.0", AuthMode: "db_auth"}, nil
+}
+
+// GetConfigurations calls GetConfigurationsFunc
+func (m *MockHarborClient) GetConfigurations(ctx context.Context) (harborclients.Configurations, error) {
+	if m.GetConfigurationsFunc != nil {
+		return m.GetConfigurationsFunc(ctx)
+	}
+	return harborclients.Configurations{}, nil
+}
+
+// GetRobotPermissions calls GetRobotPermissionsFunc
+func (m *MockHarborClient) GetRobotPermissions(ctx context.Context) (*harborclients.RobotPermissions, error) {
+	if m.GetRobotPermissionsFunc != nil {
+		return m.GetRobotPermissionsFunc(ctx)
+	}
+	return nil, nil
+}
+
+// GetProjectPermissions calls GetProjectPermissionsFunc
+func (m *MockHarborClient) GetProjectPermissions(ctx context.Context, projectID string) ([]harborclients.RobotAccess, error) {
+	if m.GetProjectPermissionsFunc != nil {
+		return m.GetProjectPermissionsFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+// UpdateConfigurations calls UpdateConfigurationsFunc
+func (m *MockHarborClient) UpdateConfigurations(ctx context.Context, cfg harborclients.Configurations) error {
+	if m.UpdateConfigurationsFunc != nil {
+		return m.UpdateConfigurationsFunc(ctx, cfg)
+	}
+	return nil
+}
+
+// PingOIDC calls PingOIDCFunc
+func (m *MockHarborClient) PingOIDC(ctx context.Context, endpoint string, verifyCert bool) error {
+	if m.PingOIDCFunc != nil {
+		return m.PingOIDCFunc(ctx, endpoint, verifyCert)
+	}
+	return nil
+}
+
+// InvalidateSystemCache calls InvalidateSystemCacheFunc
+func (m *MockHarborClient) InvalidateSystemCache() {
+	if m.InvalidateSystemCacheFunc != nil {
+		m.InvalidateSystemCacheFunc()
+	}
+}
+
 // GetMemoryFootprint calls GetMemoryFootprintFunc
 func (m *MockHarborClient) GetMemoryFootprint() string {
 	if m.GetMemoryFootprintFunc != nil {
@@ -182,6 +302,14 @@ func (m *MockHarborClient) DeleteUser(ctx context.Context, username string) erro
 	return nil
 }
 
+// RegenerateUserCLISecret calls RegenerateUserCLISecretFunc
+func (m *MockHarborClient) RegenerateUserCLISecret(ctx context.Context, username string) (string, error) {
+	if m.RegenerateUserCLISecretFunc != nil {
+		return m.RegenerateUserCLISecretFunc(ctx, username)
+	}
+	return "new-cli-secret", nil
+}
+
 // GetProject calls GetProjectFunc
 func (m *MockHarborClient) GetProject(ctx context.Context, projectName string) (*harborclients.ProjectStatus, error) {
 	if m.GetProjectFunc != nil {
@@ -228,6 +356,110 @@ func (m *MockHarborClient) ListProjects(ctx context.Context) ([]*harborclients.P
 	return nil, nil
 }
 
+// ListProjectMetadata calls ListProjectMetadataFunc
+func (m *MockHarborClient) ListProjectMetadata(ctx context.Context, projectID string) (map[string]string, error) {
+	if m.ListProjectMetadataFunc != nil {
+		return m.ListProjectMetadataFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+// SetProjectMetadata calls SetProjectMetadataFunc
+func (m *MockHarborClient) SetProjectMetadata(ctx context.Context, projectID, key, value string) error {
+	if m.SetProjectMetadataFunc != nil {
+		return m.SetProjectMetadataFunc(ctx, projectID, key, value)
+	}
+	return nil
+}
+
+// DeleteProjectMetadata calls DeleteProjectMetadataFunc
+func (m *MockHarborClient) DeleteProjectMetadata(ctx context.Context, projectID, key string) error {
+	if m.DeleteProjectMetadataFunc != nil {
+		return m.DeleteProjectMetadataFunc(ctx, projectID, key)
+	}
+	return nil
+}
+
+// SampleProjectSBOMs calls SampleProjectSBOMsFunc
+func (m *MockHarborClient) SampleProjectSBOMs(ctx context.Context, projectName string, maxRepos int64) (*harborclients.SBOMSample, error) {
+	if m.SampleProjectSBOMsFunc != nil {
+		return m.SampleProjectSBOMsFunc(ctx, projectName, maxRepos)
+	}
+	return &harborclients.SBOMSample{}, nil
+}
+
+// InventoryRepositories calls InventoryRepositoriesFunc
+func (m *MockHarborClient) InventoryRepositories(ctx context.Context, projects []string) (*harborclients.Partial[harborclients.InventoryRepository], error) {
+	if m.InventoryRepositoriesFunc != nil {
+		return m.InventoryRepositoriesFunc(ctx, projects)
+	}
+	return &harborclients.Partial[harborclients.InventoryRepository]{}, nil
+}
+
+// GetProjectSummary calls GetProjectSummaryFunc
+func (m *MockHarborClient) GetProjectSummary(ctx context.Context, projectName string) (*harborclients.ProjectSummary, error) {
+	if m.GetProjectSummaryFunc != nil {
+		return m.GetProjectSummaryFunc(ctx, projectName)
+	}
+	return &harborclients.ProjectSummary{}, nil
+}
+
+// ListProjectAuditLogs calls ListProjectAuditLogsFunc
+func (m *MockHarborClient) ListProjectAuditLogs(ctx context.Context, projectName string, since time.Time, pageSize int64) ([]*harborclients.AuditLogEntry, error) {
+	if m.ListProjectAuditLogsFunc != nil {
+		return m.ListProjectAuditLogsFunc(ctx, projectName, since, pageSize)
+	}
+	return nil, nil
+}
+
+// EnsureProjectLabel calls EnsureProjectLabelFunc
+func (m *MockHarborClient) EnsureProjectLabel(ctx context.Context, projectID int64, name, description string) (int64, error) {
+	if m.EnsureProjectLabelFunc != nil {
+		return m.EnsureProjectLabelFunc(ctx, projectID, name, description)
+	}
+	return 1, nil
+}
+
+// DeleteLabel calls DeleteLabelFunc
+func (m *MockHarborClient) DeleteLabel(ctx context.Context, labelID int64) error {
+	if m.DeleteLabelFunc != nil {
+		return m.DeleteLabelFunc(ctx, labelID)
+	}
+	return nil
+}
+
+// FindLabel calls FindLabelFunc
+func (m *MockHarborClient) FindLabel(ctx context.Context, name, projectName string) (id int64, found bool, err error) {
+	if m.FindLabelFunc != nil {
+		return m.FindLabelFunc(ctx, name, projectName)
+	}
+	return 0, false, nil
+}
+
+// ListArtifactsByLabel calls ListArtifactsByLabelFunc
+func (m *MockHarborClient) ListArtifactsByLabel(ctx context.Context, projectName, repositoryName string, labelID int64) (with, without []string, err error) {
+	if m.ListArtifactsByLabelFunc != nil {
+		return m.ListArtifactsByLabelFunc(ctx, projectName, repositoryName, labelID)
+	}
+	return nil, nil, nil
+}
+
+// GetArtifactLabels calls GetArtifactLabelsFunc
+func (m *MockHarborClient) GetArtifactLabels(ctx context.Context, projectName, repositoryName, reference string) (digest string, labelIDs []int64, err error) {
+	if m.GetArtifactLabelsFunc != nil {
+		return m.GetArtifactLabelsFunc(ctx, projectName, repositoryName, reference)
+	}
+	return "", nil, nil
+}
+
+// SetArtifactLabel calls SetArtifactLabelFunc
+func (m *MockHarborClient) SetArtifactLabel(ctx context.Context, projectName, repositoryName, reference string, labelID int64, attached bool) error {
+	if m.SetArtifactLabelFunc != nil {
+		return m.SetArtifactLabelFunc(ctx, projectName, repositoryName, reference, labelID, attached)
+	}
+	return nil
+}
+
 // CreateScannerRegistration calls CreateScannerRegistrationFunc
 func (m *MockHarborClient) CreateScannerRegistration(ctx context.Context, spec *harborclients.ScannerSpec) (*harborclients.ScannerStatus, error) {
 	if m.CreateScannerRegistrationFunc != nil {
@@ -280,6 +512,38 @@ func (m *MockHarborClient) ListScannerRegistrations(ctx context.Context) ([]*har
 	return nil, nil
 }
 
+// SetDefaultScanner calls SetDefaultScannerFunc
+func (m *MockHarborClient) SetDefaultScanner(ctx context.Context, scannerID string) error {
+	if m.SetDefaultScannerFunc != nil {
+		return m.SetDefaultScannerFunc(ctx, scannerID)
+	}
+	return nil
+}
+
+// GetProjectScanner calls GetProjectScannerFunc
+func (m *MockHarborClient) GetProjectScanner(ctx context.Context, projectName string) (*harborclients.ScannerStatus, error) {
+	if m.GetProjectScannerFunc != nil {
+		return m.GetProjectScannerFunc(ctx, projectName)
+	}
+	return nil, nil
+}
+
+// SetProjectScanner calls SetProjectScannerFunc
+func (m *MockHarborClient) SetProjectScanner(ctx context.Context, projectName, scannerID string) error {
+	if m.SetProjectScannerFunc != nil {
+		return m.SetProjectScannerFunc(ctx, projectName, scannerID)
+	}
+	return nil
+}
+
+// GetScannerMetadata calls GetScannerMetadataFunc
+func (m *MockHarborClient) GetScannerMetadata(ctx context.Context, scannerID string) (*harborclients.ScannerMetadata, error) {
+	if m.GetScannerMetadataFunc != nil {
+		return m.GetScannerMetadataFunc(ctx, scannerID)
+	}
+	return &harborclients.ScannerMetadata{}, nil
+}
+
 // CreateRegistry calls CreateRegistryFunc
 func (m *MockHarborClient) CreateRegistry(ctx context.Context, spec *harborclients.RegistrySpec) (*harborclients.RegistryStatus, error) {
 	if m.CreateRegistryFunc != nil {
@@ -324,6 +588,70 @@ func (m *MockHarborClient) DeleteRegistry(ctx context.Context, registryName stri
 	return nil
 }
 
+// PingRegistry calls PingRegistryFunc
+func (m *MockHarborClient) PingRegistry(ctx context.Context, id int64) error {
+	if m.PingRegistryFunc != nil {
+		return m.PingRegistryFunc(ctx, id)
+	}
+	return nil
+}
+
+// GetGCSchedule calls GetGCScheduleFunc
+func (m *MockHarborClient) GetGCSchedule(ctx context.Context) (*harborclients.GCSchedule, error) {
+	if m.GetGCScheduleFunc != nil {
+		return m.GetGCScheduleFunc(ctx)
+	}
+	return &harborclients.GCSchedule{Type: harborclients.GCScheduleTypeNone}, nil
+}
+
+// CreateGCSchedule calls CreateGCScheduleFunc
+func (m *MockHarborClient) CreateGCSchedule(ctx context.Context, s *harborclients.GCSchedule) error {
+	if m.CreateGCScheduleFunc != nil {
+		return m.CreateGCScheduleFunc(ctx, s)
+	}
+	return nil
+}
+
+// UpdateGCSchedule calls UpdateGCScheduleFunc
+func (m *MockHarborClient) UpdateGCSchedule(ctx context.Context, s *harborclients.GCSchedule) error {
+	if m.UpdateGCScheduleFunc != nil {
+		return m.UpdateGCScheduleFunc(ctx, s)
+	}
+	return nil
+}
+
+// ListImmutableTagRules calls ListImmutableTagRulesFunc
+func (m *MockHarborClient) ListImmutableTagRules(ctx context.Context, projectID string) ([]*harborclients.ImmutableTagRule, error) {
+	if m.ListImmutableTagRulesFunc != nil {
+		return m.ListImmutableTagRulesFunc(ctx, projectID)
+	}
+	return nil, nil
+}
+
+// CreateImmutableTagRule calls CreateImmutableTagRuleFunc
+func (m *MockHarborClient) CreateImmutableTagRule(ctx context.Context, projectID string, rule *harborclients.ImmutableTagRule) (int64, error) {
+	if m.CreateImmutableTagRuleFunc != nil {
+		return m.CreateImmutableTagRuleFunc(ctx, projectID, rule)
+	}
+	return 1, nil
+}
+
+// UpdateImmutableTagRule calls UpdateImmutableTagRuleFunc
+func (m *MockHarborClient) UpdateImmutableTagRule(ctx context.Context, projectID string, rule *harborclients.ImmutableTagRule) error {
+	if m.UpdateImmutableTagRuleFunc != nil {
+		return m.UpdateImmutableTagRuleFunc(ctx, projectID, rule)
+	}
+	return nil
+}
+
+// DeleteImmutableTagRule calls DeleteImmutableTagRuleFunc
+func (m *MockHarborClient) DeleteImmutableTagRule(ctx context.Context, projectID string, id int64) error {
+	if m.DeleteImmutableTagRuleFunc != nil {
+		return m.DeleteImmutableTagRuleFunc(ctx, projectID, id)
+	}
+	return nil
+}
+
 // ListRepositories calls ListRepositoriesFunc
 func (m *MockHarborClient) ListRepositories(ctx context.Context, projectID string) ([]*harborclients.RepositoryStatus, error) {
 	if m.ListRepositoriesFunc != nil {
@@ -435,6 +763,38 @@ func (m *MockHarborClient) DeleteProjectMember(ctx context.Context, projectID, u
 	return nil
 }
 
+// AddProjectGroupMember calls AddProjectGroupMemberFunc
+func (m *MockHarborClient) AddProjectGroupMember(ctx context.Context, projectName, groupName string, groupType int64, role string) error {
+	if m.AddProjectGroupMemberFunc != nil {
+		return m.AddProjectGroupMemberFunc(ctx, projectName, groupName, groupType, role)
+	}
+	return nil
+}
+
+// GetProjectGroupMember calls GetProjectGroupMemberFunc
+func (m *MockHarborClient) GetProjectGroupMember(ctx context.Context, projectID, groupName string) (*harborclients.MemberStatus, error) {
+	if m.GetProjectGroupMemberFunc != nil {
+		return m.GetProjectGroupMemberFunc(ctx, projectID, groupName)
+	}
+	return nil, nil
+}
+
+// UpdateProjectGroupMember calls UpdateProjectGroupMemberFunc
+func (m *MockHarborClient) UpdateProjectGroupMember(ctx context.Context, projectID, groupName, role string) error {
+	if m.UpdateProjectGroupMemberFunc != nil {
+		return m.UpdateProjectGroupMemberFunc(ctx, projectID, groupName, role)
+	}
+	return nil
+}
+
+// DeleteProjectGroupMember calls DeleteProjectGroupMemberFunc
+func (m *MockHarborClient) DeleteProjectGroupMember(ctx context.Context, projectID, groupName string) error {
+	if m.DeleteProjectGroupMemberFunc != nil {
+		return m.DeleteProjectGroupMemberFunc(ctx, projectID, groupName)
+	}
+	return nil
+}
+
 // TriggerScan calls TriggerScanFunc
 func (m *MockHarborClient) TriggerScan(ctx context.Context, projectID, repoName, reference string) error {
 	if m.TriggerScanFunc != nil {
@@ -467,6 +827,54 @@ func (m *MockHarborClient) StopScan(ctx context.Context, projectID, repoName, re
 	return nil
 }
 
+// ScanProject calls ScanProjectFunc
+func (m *MockHarborClient) ScanProject(ctx context.Context, projectName string) (int64, error) {
+	if m.ScanProjectFunc != nil {
+		return m.ScanProjectFunc(ctx, projectName)
+	}
+	return 0, nil
+}
+
+// StopProjectScans calls StopProjectScansFunc
+func (m *MockHarborClient) StopProjectScans(ctx context.Context, projectName string) (int64, error) {
+	if m.StopProjectScansFunc != nil {
+		return m.StopProjectScansFunc(ctx, projectName)
+	}
+	return 0, nil
+}
+
+// GetProjectScanMetrics calls GetProjectScanMetricsFunc
+func (m *MockHarborClient) GetProjectScanMetrics(ctx context.Context, projectName string) (*harborclients.ScanMetrics, error) {
+	if m.GetProjectScanMetricsFunc != nil {
+		return m.GetProjectScanMetricsFunc(ctx, projectName)
+	}
+	return &harborclients.ScanMetrics{}, nil
+}
+
+// TriggerScanAll calls TriggerScanAllFunc
+func (m *MockHarborClient) TriggerScanAll(ctx context.Context) error {
+	if m.TriggerScanAllFunc != nil {
+		return m.TriggerScanAllFunc(ctx)
+	}
+	return nil
+}
+
+// StopScanAll calls StopScanAllFunc
+func (m *MockHarborClient) StopScanAll(ctx context.Context) error {
+	if m.StopScanAllFunc != nil {
+		return m.StopScanAllFunc(ctx)
+	}
+	return nil
+}
+
+// GetScanAllMetrics calls GetScanAllMetricsFunc
+func (m *MockHarborClient) GetScanAllMetrics(ctx context.Context) (*harborclients.ScanMetrics, error) {
+	if m.GetScanAllMetricsFunc != nil {
+		return m.GetScanAllMetricsFunc(ctx)
+	}
+	return &harborclients.ScanMetrics{}, nil
+}
+
 // CreateRobot calls CreateRobotFunc
 func (m *MockHarborClient) CreateRobot(ctx context.Context, spec *harborclients.RobotSpec) (*harborclients.RobotStatus, error) {
 	if m.CreateRobotFunc != nil {
@@ -522,6 +930,14 @@ func (m *MockHarborClient) DeleteRobot(ctx context.Context, robotID string) erro
 	return nil
 }
 
+// RefreshRobotSecret calls RefreshRobotSecretFunc
+func (m *MockHarborClient) RefreshRobotSecret(ctx context.Context, robotID string) (string, error) {
+	if m.RefreshRobotSecretFunc != nil {
+		return m.RefreshRobotSecretFunc(ctx, robotID)
+	}
+	return "new-secret", nil
+}
+
 // CreateWebhook calls CreateWebhookFunc
 func (m *MockHarborClient) CreateWebhook(ctx context.Context, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error) {
 	if m.CreateWebhookFunc != nil {
@@ -661,12 +1077,10 @@ func (m *MockHarborClient) CreateRetentionPolicy(ctx context.Context, spec *harb
 		return m.CreateRetentionPolicyFunc(ctx, spec)
 	}
 	return &harborclients.RetentionPolicyStatus{
-		ID:           "mock-retention-id",
-		ProjectID:    spec.ProjectID,
-		Description:  spec.Description,
-		Enabled:      spec.Enabled != nil && *spec.Enabled,
-		CreationTime: time.Now(),
-		UpdateTime:   time.Now(),
+		ID:        "mock-retention-id",
+		ProjectID: spec.ProjectID,
+		Rules:     spec.Rules,
+		Schedule:  spec.Schedule,
 	}, nil
 }
 
@@ -692,12 +1106,10 @@ func (m *MockHarborClient) UpdateRetentionPolicy(ctx context.Context, projectID,
 		return m.UpdateRetentionPolicyFunc(ctx, projectID, policyID, spec)
 	}
 	return &harborclients.RetentionPolicyStatus{
-		ID:           policyID,
-		ProjectID:    projectID,
-		Description:  spec.Description,
-		Enabled:      spec.Enabled != nil && *spec.Enabled,
-		CreationTime: time.Now(),
-		UpdateTime:   time.Now(),
+		ID:        policyID,
+		ProjectID: projectID,
+		Rules:     spec.Rules,
+		Schedule:  spec.Schedule,
 	}, nil
 }
 
@@ -708,3 +1120,59 @@ func (m *MockHarborClient) DeleteRetentionPolicy(ctx context.Context, projectID,
 	}
 	return nil
 }
+
+// GetProjectRetentionID calls GetProjectRetentionIDFunc
+func (m *MockHarborClient) GetProjectRetentionID(ctx context.Context, projectID string) (string, error) {
+	if m.GetProjectRetentionIDFunc != nil {
+		return m.GetProjectRetentionIDFunc(ctx, projectID)
+	}
+	return "", nil
+}
+
+// SetProjectRetentionID calls SetProjectRetentionIDFunc
+func (m *MockHarborClient) SetProjectRetentionID(ctx context.Context, projectID, policyID string) error {
+	if m.SetProjectRetentionIDFunc != nil {
+		return m.SetProjectRetentionIDFunc(ctx, projectID, policyID)
+	}
+	return nil
+}
+
+// ListRetentionExecutions calls ListRetentionExecutionsFunc
+func (m *MockHarborClient) ListRetentionExecutions(ctx context.Context, policyID string) ([]*harborclients.RetentionExecution, error) {
+	if m.ListRetentionExecutionsFunc != nil {
+		return m.ListRetentionExecutionsFunc(ctx, policyID)
+	}
+	return nil, nil
+}
+
+// CountRetentionTasks calls CountRetentionTasksFunc
+func (m *MockHarborClient) CountRetentionTasks(ctx context.Context, policyID, executionID string) (*harborclients.RetentionTaskCounts, error) {
+	if m.CountRetentionTasksFunc != nil {
+		return m.CountRetentionTasksFunc(ctx, policyID, executionID)
+	}
+	return &harborclients.RetentionTaskCounts{}, nil
+}
+
+// GetRaw calls GetRawFunc
+func (m *MockHarborClient) GetRaw(ctx context.Context, path string) ([]byte, error) {
+	if m.GetRawFunc != nil {
+		return m.GetRawFunc(ctx, path)
+	}
+	return nil, nil
+}
+
+// PutRaw calls PutRawFunc
+func (m *MockHarborClient) PutRaw(ctx context.Context, path string, body []byte) error {
+	if m.PutRawFunc != nil {
+		return m.PutRawFunc(ctx, path, body)
+	}
+	return nil
+}
+
+// DeleteRaw calls DeleteRawFunc
+func (m *MockHarborClient) DeleteRaw(ctx context.Context, path string) error {
+	if m.DeleteRawFunc != nil {
+		return m.DeleteRawFunc(ctx, path)
+	}
+	return nil
+}
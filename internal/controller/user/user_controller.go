@@ -6,6 +6,7 @@ package user
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
@@ -21,42 +22,53 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"time"
 )
 
 const (
-	errNotUser      = "managed resource is not a User custom resource"
-	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
-	errNewClient    = "cannot create new Harbor client"
-	errUserCreate   = "cannot create Harbor user"
-	errUserGet      = "cannot get Harbor user"
-	errUserUpdate   = "cannot update Harbor user"
-	errUserDelete   = "cannot delete Harbor user"
+	errNotUser       = "managed resource is not a User custom resource"
+	errTrackPCUsage  = "cannot track ProviderConfig usage"
+	errGetPC         = "cannot get ProviderConfig"
+	errGetCreds      = "cannot get credentials"
+	errNewClient     = "cannot create new Harbor client"
+	errUserCreate    = "cannot create Harbor user"
+	errUserGet       = "cannot get Harbor user"
+	errUserUpdate    = "cannot update Harbor user"
+	errUserDelete    = "cannot delete Harbor user"
+	errCLISecret     = "cannot regenerate Harbor user CLI secret"
+	errDeleteSecrets = "cannot delete password Secrets generated for Harbor user"
 )
 
+// cliSecretKey is the connection detail that holds a generated CLI secret.
+const cliSecretKey = "cli_secret"
+
+// sysAdminField is the user's admin flag, which Harbor leaves out of its
+// responses for ordinary users.
+var sysAdminField = ctrlutil.BoolField{}
+
 // Setup adds a controller that reconciles User managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.UserGroupVersionKind.Kind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.UserGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithRequiredCapability(mgr.GetClient(), harborclients.CapabilityManageUsers, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
-		}),
+		}))))))))),
 		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
-		managed.WithPollInterval(1*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.UserGroupVersionKind), opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
+		WithOptions(o.ForControllerRuntime()).
 		WithEventFilter(resource.DesiredStateChanged()).
 		For(&v1beta1.User{}).
-		Complete(ratelimiter.NewReconciler(name, r, nil))
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
@@ -129,7 +141,8 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	// Check if resource is up to date
 	upToDate := cr.Spec.ForProvider.Email == user.Email &&
-		(cr.Spec.ForProvider.SysAdminFlag == nil || *cr.Spec.ForProvider.SysAdminFlag == user.AdminFlag)
+		sysAdminField.Matches(cr.Spec.ForProvider.SysAdminFlag, &user.AdminFlag) &&
+		!cliSecretOutdated(cr)
 
 	return managed.ExternalObservation{
 		ResourceExists:   true,
@@ -230,12 +243,35 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		cr.Status.AtProvider.UpdateTime = &metav1.Time{Time: time.Now()}
 	}
 
-	return managed.ExternalUpdate{
-		ConnectionDetails: managed.ConnectionDetails{
-			"username": []byte(status.Username),
-			"user_id":  []byte("1"), // Mock ID
-		},
-	}, nil
+	details := managed.ConnectionDetails{
+		"username": []byte(status.Username),
+		"user_id":  []byte("1"), // Mock ID
+	}
+
+	// Connection details are merged into the secret, so a CLI secret
+	// published once stays there until the next generation replaces it.
+	if cliSecretOutdated(cr) {
+		secret, err := c.service.RegenerateUserCLISecret(ctx, cr.Spec.ForProvider.Username)
+		if err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errCLISecret)
+		}
+		details[cliSecretKey] = []byte(secret)
+		gen := *cr.Spec.ForProvider.CLISecretGeneration
+		cr.Status.AtProvider.CLISecretGeneration = &gen
+	}
+
+	return managed.ExternalUpdate{ConnectionDetails: details}, nil
+}
+
+// cliSecretOutdated reports whether a User asks for a CLI secret generation
+// that has not been generated yet.
+func cliSecretOutdated(cr *v1beta1.User) bool {
+	want := cr.Spec.ForProvider.CLISecretGeneration
+	if want == nil {
+		return false
+	}
+	have := cr.Status.AtProvider.CLISecretGeneration
+	return have == nil || *have != *want
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
@@ -256,6 +292,12 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.Wrap(err, errUserDelete)
 	}
 
+	// Password Secrets generated for the User in another namespace cannot
+	// be garbage collected with it.
+	if err := ctrlutil.DeleteTrackedChildren(ctx, c.kube, cr, &corev1.SecretList{}); err != nil {
+		return managed.ExternalDelete{}, errors.Wrap(err, errDeleteSecrets)
+	}
+
 	return managed.ExternalDelete{}, nil
 }
 
@@ -270,6 +270,85 @@ func TestUpdateUserSuccess(t *testing.T) {
 	}
 }
 
+func TestUpdateUserCLISecret(t *testing.T) {
+	ctx := context.Background()
+	gen := int64(2)
+	user := &v1beta1.User{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-user",
+		},
+		Spec: v1beta1.UserSpec{
+			ForProvider: v1beta1.UserParameters{
+				Username:            "testuser",
+				Email:               "test@example.com",
+				CLISecretGeneration: &gen,
+			},
+		},
+	}
+
+	regenerated := 0
+	svc := &mockUserClient{
+		getUserFunc: func(ctx context.Context, username string) (*harborclients.UserStatus, error) {
+			return &harborclients.UserStatus{Username: username, Email: "test@example.com"}, nil
+		},
+		updateUserFunc: func(ctx context.Context, username string, spec *harborclients.UserSpec) (*harborclients.UserStatus, error) {
+			return &harborclients.UserStatus{Username: spec.Username, Email: spec.Email}, nil
+		},
+		regenerateCLISecretFunc: func(ctx context.Context, username string) (string, error) {
+			regenerated++
+			if username != "testuser" {
+				t.Errorf("CLI secret regenerated for %q", username)
+			}
+			return "Secret123", nil
+		},
+	}
+	ext := &external{service: svc}
+
+	obs, err := ext.Observe(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Error("a User with an ungenerated CLI secret generation should not be up to date")
+	}
+
+	upd, err := ext.Update(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(upd.ConnectionDetails[cliSecretKey]); got != "Secret123" {
+		t.Errorf("connection detail %s = %q, want the generated secret", cliSecretKey, got)
+	}
+	if got := user.Status.AtProvider.CLISecretGeneration; got == nil || *got != gen {
+		t.Errorf("status cliSecretGeneration = %v, want %d", got, gen)
+	}
+
+	obs, err = ext.Observe(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Error("a User whose CLI secret generation was generated should be up to date")
+	}
+	if _, err := ext.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if regenerated != 1 {
+		t.Errorf("CLI secret regenerated %d times, want once", regenerated)
+	}
+
+	svc.regenerateCLISecretFunc = func(ctx context.Context, username string) (string, error) {
+		return "", errors.New("not in OIDC mode")
+	}
+	gen = 3
+	if _, err := ext.Update(ctx, user); err == nil {
+		t.Error("Update should fail when the CLI secret cannot be regenerated")
+	}
+	if got := user.Status.AtProvider.CLISecretGeneration; *got != 2 {
+		t.Errorf("status cliSecretGeneration = %d after a failed regeneration, want 2", *got)
+	}
+}
+
 func TestDeleteUserSuccess(t *testing.T) {
 	ctx := context.Background()
 	user := &v1beta1.User{
@@ -1209,6 +1288,12 @@ func TestObserveUserAllAdminFlagCombinations(t *testing.T) {
 			observedAdminFlag: false,
 			expectUpToDate:    false,
 		},
+		{
+			name:              "false spec vs omitted observed",
+			specAdminFlag:     ptrBool(false),
+			observedAdminFlag: false,
+			expectUpToDate:    true,
+		},
 		{
 			name:              "false spec vs true observed",
 			specAdminFlag:     ptrBool(false),
@@ -1268,6 +1353,8 @@ type mockUserClient struct {
 	createUserFunc func(ctx context.Context, spec *harborclients.UserSpec) (*harborclients.UserStatus, error)
 	updateUserFunc func(ctx context.Context, username string, spec *harborclients.UserSpec) (*harborclients.UserStatus, error)
 	deleteUserFunc func(ctx context.Context, username string) error
+
+	regenerateCLISecretFunc func(ctx context.Context, username string) (string, error)
 }
 
 func (m *mockUserClient) GetUser(ctx context.Context, username string) (*harborclients.UserStatus, error) {
@@ -1314,3 +1401,10 @@ func ptrBool(b bool) *bool {
 func ptrInt64(i int64) *int64 {
 	return &i
 }
+
+func (m *mockUserClient) RegenerateUserCLISecret(ctx context.Context, username string) (string, error) {
+	if m.regenerateCLISecretFunc != nil {
+		return m.regenerateCLISecretFunc(ctx, username)
+	}
+	return "", nil
+}
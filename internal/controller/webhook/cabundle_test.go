@@ -0,0 +1,124 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/apis/common"
+	"github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func caSecret(t *testing.T) *corev1.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "endpoint-ca"},
+		Data:       map[string][]byte{"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+}
+
+// TestCABundleRenewal checks that a renewed CA makes the webhook out of date,
+// and that saving it keeps certificate verification on and records the CA.
+func TestCABundleRenewal(t *testing.T) {
+	ctx := context.Background()
+	cr := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "hook"},
+		Spec: v1beta1.WebhookSpec{ForProvider: v1beta1.WebhookParameters{
+			ProjectID:      "1",
+			Name:           "hook",
+			URL:            "https://hooks.example.com",
+			SkipCertVerify: ptrBool(true),
+			CABundleRef:    &common.CABundleSource{Name: "endpoint-ca"},
+		}},
+		Status: v1beta1.WebhookStatus{AtProvider: v1beta1.WebhookObservation{
+			ID:       ptrString("7"),
+			CABundle: &common.CABundleObservation{Fingerprint: "previous"},
+		}},
+	}
+
+	var saved *harborclients.WebhookSpec
+	e := &external{
+		kube: fake.NewClientBuilder().WithObjects(caSecret(t)).Build(),
+		service: &mockWebhookClient{
+			getWebhookFunc: func(_ context.Context, _, _ string) (*harborclients.WebhookStatus, error) {
+				return &harborclients.WebhookStatus{ID: "7", Name: "hook", URL: "https://hooks.example.com"}, nil
+			},
+			updateWebhookFunc: func(_ context.Context, _, _ string, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error) {
+				saved = spec
+				return &harborclients.WebhookStatus{ID: "7"}, nil
+			},
+		},
+	}
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.ResourceUpToDate {
+		t.Fatal("ResourceUpToDate should be false after the CA is renewed")
+	}
+
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatal(err)
+	}
+	if saved == nil || saved.SkipCertVerify {
+		t.Errorf("Update() saved %+v, want certificate verification on", saved)
+	}
+	if ca := cr.Status.AtProvider.CABundle; ca == nil || ca.Fingerprint == "previous" || ca.Certificates != 1 {
+		t.Errorf("Update() recorded CA bundle %+v", ca)
+	}
+
+	obs, err = e.Observe(ctx, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !obs.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be true once the renewed CA is recorded")
+	}
+}
+
+func TestCABundleMissing(t *testing.T) {
+	cr := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "hook"},
+		Spec: v1beta1.WebhookSpec{ForProvider: v1beta1.WebhookParameters{
+			ProjectID:   "1",
+			Name:        "hook",
+			CABundleRef: &common.CABundleSource{Name: "endpoint-ca"},
+		}},
+		Status: v1beta1.WebhookStatus{AtProvider: v1beta1.WebhookObservation{ID: ptrString("7")}},
+	}
+	e := &external{
+		kube: fake.NewClientBuilder().Build(),
+		service: &mockWebhookClient{
+			getWebhookFunc: func(_ context.Context, _, _ string) (*harborclients.WebhookStatus, error) {
+				return &harborclients.WebhookStatus{ID: "7", Name: "hook"}, nil
+			},
+		},
+	}
+	if _, err := e.Observe(context.Background(), cr); err == nil {
+		t.Error("Observe should fail when the CA bundle Secret does not exist")
+	}
+}
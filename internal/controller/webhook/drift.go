@@ -0,0 +1,71 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package webhook
+
+import (
+	"sort"
+
+	"github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+)
+
+// enabledField is a policy's enabled flag. Harbor leaves it out of its
+// responses when it is false, while the CRD defaults it to true.
+var enabledField = ctrlutil.BoolField{}
+
+// isUpToDate reports whether the live policy matches the non-secret fields
+// of p. Harbor never returns the auth header, so it cannot drift. Optional
+// fields are compared only when set; the CRD defaults them.
+func isUpToDate(p v1beta1.WebhookParameters, webhook *harborclients.WebhookStatus) bool {
+	switch {
+	case p.Name != webhook.Name:
+		return false
+	case p.Description != nil && webhook.Description != nil && *p.Description != *webhook.Description:
+		return false
+	case p.URL != "" && p.URL != webhook.URL:
+		return false
+	case !sameEventTypes(p.EventTypes, webhook.EventTypes):
+		return false
+	case p.NotifyType != nil && *p.NotifyType != webhook.NotifyType:
+		return false
+	case p.PayloadFormat != nil && *p.PayloadFormat != payloadFormat(webhook):
+		return false
+	case !enabledField.Matches(p.Enabled, &webhook.Enabled):
+		return false
+	}
+	return true
+}
+
+// sameEventTypes reports whether want and got hold the same event types.
+// Harbor returns them in its own order, so order is ignored.
+func sameEventTypes(want, got []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	if len(want) != len(got) {
+		return false
+	}
+	w := append([]string(nil), want...)
+	g := append([]string(nil), got...)
+	sort.Strings(w)
+	sort.Strings(g)
+	for i := range w {
+		if w[i] != g[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// payloadFormat returns the payload format of the policy's target. Policies
+// created before Harbor supported CloudEvents have none, which Harbor treats
+// as Default.
+func payloadFormat(webhook *harborclients.WebhookStatus) string {
+	if webhook.PayloadFormat == "" {
+		return "Default"
+	}
+	return webhook.PayloadFormat
+}
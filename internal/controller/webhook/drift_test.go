@@ -0,0 +1,127 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+)
+
+func TestIsUpToDate(t *testing.T) {
+	params := func(mod func(*v1beta1.WebhookParameters)) v1beta1.WebhookParameters {
+		p := v1beta1.WebhookParameters{
+			Name:          "ci",
+			URL:           "https://hooks.example.com/harbor",
+			EventTypes:    []string{"PUSH_ARTIFACT", "SCANNING_COMPLETED"},
+			NotifyType:    ptrString("http"),
+			PayloadFormat: ptrString("Default"),
+			Enabled:       ptrBool(true),
+		}
+		if mod != nil {
+			mod(&p)
+		}
+		return p
+	}
+	live := func(mod func(*harborclients.WebhookStatus)) *harborclients.WebhookStatus {
+		w := &harborclients.WebhookStatus{
+			Name:          "ci",
+			URL:           "https://hooks.example.com/harbor",
+			EventTypes:    []string{"SCANNING_COMPLETED", "PUSH_ARTIFACT"},
+			NotifyType:    "http",
+			PayloadFormat: "Default",
+			Enabled:       true,
+		}
+		if mod != nil {
+			mod(w)
+		}
+		return w
+	}
+
+	cases := map[string]struct {
+		params v1beta1.WebhookParameters
+		live   *harborclients.WebhookStatus
+		want   bool
+	}{
+		"DisabledOmitted": {
+			params: params(func(p *v1beta1.WebhookParameters) { p.Enabled = ptrBool(false) }),
+			live:   live(func(w *harborclients.WebhookStatus) { w.Enabled = false }),
+			want:   true,
+		},
+		"EventTypesReordered": {
+			params: params(nil),
+			live:   live(nil),
+			want:   true,
+		},
+		"EventTypeRemoved": {
+			params: params(nil),
+			live:   live(func(w *harborclients.WebhookStatus) { w.EventTypes = []string{"PUSH_ARTIFACT"} }),
+			want:   false,
+		},
+		"EventTypeReplaced": {
+			params: params(nil),
+			live:   live(func(w *harborclients.WebhookStatus) { w.EventTypes = []string{"PUSH_ARTIFACT", "PULL_ARTIFACT"} }),
+			want:   false,
+		},
+		"URLChanged": {
+			params: params(nil),
+			live:   live(func(w *harborclients.WebhookStatus) { w.URL = "https://other.example.com" }),
+			want:   false,
+		},
+		"NotifyTypeChanged": {
+			params: params(nil),
+			live:   live(func(w *harborclients.WebhookStatus) { w.NotifyType = "slack" }),
+			want:   false,
+		},
+		"PayloadFormatChanged": {
+			params: params(nil),
+			live:   live(func(w *harborclients.WebhookStatus) { w.PayloadFormat = "CloudEvents" }),
+			want:   false,
+		},
+		"PayloadFormatUnset": {
+			params: params(nil),
+			live:   live(func(w *harborclients.WebhookStatus) { w.PayloadFormat = "" }),
+			want:   true,
+		},
+		"Disabled": {
+			params: params(nil),
+			live:   live(func(w *harborclients.WebhookStatus) { w.Enabled = false }),
+			want:   false,
+		},
+		"DisabledAsDesired": {
+			params: params(func(p *v1beta1.WebhookParameters) { p.Enabled = ptrBool(false) }),
+			live:   live(func(w *harborclients.WebhookStatus) { w.Enabled = false }),
+			want:   true,
+		},
+		"OptionalFieldsUnset": {
+			params: params(func(p *v1beta1.WebhookParameters) { p.NotifyType, p.PayloadFormat, p.Enabled = nil, nil, nil }),
+			live:   live(func(w *harborclients.WebhookStatus) { w.NotifyType, w.Enabled = "slack", false }),
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := isUpToDate(tc.params, tc.live); got != tc.want {
+				t.Errorf("isUpToDate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildWebhookSpecDisabled(t *testing.T) {
+	cr := &v1beta1.Webhook{}
+	cr.Spec.ForProvider.Enabled = ptrBool(false)
+	cr.Spec.ForProvider.NotifyType = ptrString("slack")
+
+	spec := buildWebhookSpec(cr)
+	if spec.Enabled {
+		t.Error("buildWebhookSpec().Enabled = true, want the policy disabled")
+	}
+	if spec.NotifyType != "slack" {
+		t.Errorf("buildWebhookSpec().NotifyType = %q, want slack", spec.NotifyType)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	"github.com/rossigee/provider-harbor/internal/controller/testing/fakeharbor"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reconcileWebhook runs one Observe/Create pass the way the managed
+// reconciler does, with a short deadline so injected timeouts fail fast.
+func reconcileWebhook(e *external, cr *v1beta1.Webhook) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	if !obs.ResourceExists {
+		_, err := e.Create(ctx, cr)
+		return false, err
+	}
+	return obs.ResourceUpToDate, nil
+}
+
+func TestWebhookConvergesUnderAPIFlaps(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		t.Run(fmt.Sprintf("seed-%d", seed), func(t *testing.T) {
+			srv := fakeharbor.New(t, fakeharbor.Faults{
+				RateLimit:    0.15,
+				ServerError:  0.15,
+				Timeout:      0.1,
+				LostResponse: 0.5,
+				Seed:         seed,
+			})
+			svc, err := harborclients.NewHarborClient(&harborclients.HarborConfig{
+				URL:      srv.URL,
+				Username: fakeharbor.Username,
+				Password: fakeharbor.Password,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cr := &v1beta1.Webhook{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack"},
+				Spec: v1beta1.WebhookSpec{
+					ForProvider: v1beta1.WebhookParameters{
+						ProjectID:  "1",
+						Name:       "slack",
+						URL:        "https://hooks.slack.com/services/x",
+						EventTypes: []string{"PUSH_ARTIFACT"},
+					},
+				},
+			}
+			e := &external{service: svc}
+
+			converged := false
+			for i := 0; i < 60; i++ {
+				if ok, _ := reconcileWebhook(e, cr); ok {
+					converged = true
+				}
+			}
+
+			if !converged {
+				t.Error("webhook never converged")
+			}
+			if names := srv.WebhookNames("1"); len(names) != 1 {
+				t.Errorf("webhook policies in Harbor = %v, want exactly one", names)
+			}
+			if _, injected := srv.Stats(); injected == 0 {
+				t.Error("no faults were injected")
+			}
+		})
+	}
+}
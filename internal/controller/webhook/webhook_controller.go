@@ -6,25 +6,30 @@ package webhook
 
 import (
 	"context"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/pkg/errors"
+	"github.com/rossigee/provider-harbor/apis/common"
 	"github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
 	harborclients "github.com/rossigee/provider-harbor/internal/clients"
 	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
 	"github.com/rossigee/provider-harbor/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"strconv"
 	"time"
 )
 
 const (
 	errNotWebhook    = "managed resource is not a Webhook custom resource"
+	errWebhookGet    = "cannot get Harbor webhook"
 	errWebhookDelete = "cannot delete Harbor webhook"
 	errNewClient     = "cannot create new Harbor client"
 )
@@ -32,22 +37,40 @@ const (
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1beta1.WebhookGroupVersionKind.Kind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1beta1.WebhookGroupVersionKind),
-		managed.WithExternalConnector(&connector{
+	opts := []managed.ReconcilerOption{
+		managed.WithExternalConnector(ctrlutil.WithProtection(mgr.GetClient(), webhookTarget, ctrlutil.WithDeletionGuard(ctrlutil.WithExternalNamePatches(mgr.GetClient(), ctrlutil.WithUpdateDebounce(ctrlutil.WithMaintenanceWindows(ctrlutil.WithHarborAvailability(ctrlutil.WithSyncStatus(ctrlutil.WithLifecycleEvents(ctrlutil.WithMetrics(&connector{
 			kube:         mgr.GetClient(),
 			newServiceFn: harborclients.NewHarborClientFromProviderConfig,
-		}),
+		})))))))))),
 		managed.WithLogger(logging.NewLogrLogger(mgr.GetLogger().WithValues("controller", name))),
-		managed.WithPollInterval(1*time.Minute),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))))
+		managed.WithManagementPolicies(),
+		// The external name is the Harbor policy ID, recorded by Observe and
+		// Create; defaulting it to metadata.name would hide the policy name.
+		managed.WithInitializers(),
+		managed.WithPollInterval(1 * time.Minute),
+		managed.WithPollIntervalHook(ctrlutil.DebouncedPollInterval),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorder(name))),
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1beta1.WebhookGroupVersionKind), opts...)
+	newList := func() client.ObjectList { return &v1beta1.WebhookList{} }
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1beta1.Webhook{}).
-		Complete(ratelimiter.NewReconciler(name, r, ratelimiter.NewGlobal(10)))
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.Webhook{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		WatchesMetadata(&corev1.Secret{}, ctrlutil.EnqueueCABundleReferrers(mgr.GetClient(), common.CABundleKindSecret, newList)).
+		WatchesMetadata(&corev1.ConfigMap{}, ctrlutil.EnqueueCABundleReferrers(mgr.GetClient(), common.CABundleKindConfigMap, newList)).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// webhookTarget returns the project a Webhook belongs to.
+func webhookTarget(mg resource.Managed) ctrlutil.Target {
+	cr, ok := mg.(*v1beta1.Webhook)
+	if !ok {
+		return ctrlutil.Target{}
+	}
+	return ctrlutil.Target{Project: cr.Spec.ForProvider.ProjectID}
 }
 
 type connector struct {
@@ -66,10 +89,11 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
-	return &external{service: svc}, nil
+	return &external{kube: c.kube, service: svc}, nil
 }
 
 type external struct {
+	kube    client.Reader
 	service harborclients.HarborClienter
 }
 
@@ -83,46 +107,71 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotWebhook)
 	}
 
-	webhooks, err := c.service.ListWebhooks(ctx, cr.Spec.ForProvider.ProjectID)
+	webhook, err := c.findWebhook(ctx, cr)
 	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
+	if webhook == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
 
-	for _, webhook := range webhooks {
-		if webhook.Name == cr.Spec.ForProvider.Name {
-			cr.Status.AtProvider.ID = &webhook.ID
-			t := metav1.NewTime(webhook.CreationTime)
-			cr.Status.AtProvider.CreationTime = &t
-			ut := metav1.NewTime(webhook.UpdateTime)
-			cr.Status.AtProvider.UpdateTime = &ut
-
-			upToDate := true
-			if cr.Spec.ForProvider.Description != nil && webhook.Description != nil && *cr.Spec.ForProvider.Description != *webhook.Description {
-				upToDate = false
-			}
-			if cr.Spec.ForProvider.URL != "" && cr.Spec.ForProvider.URL != webhook.URL {
-				upToDate = false
-			}
-			if len(cr.Spec.ForProvider.EventTypes) > 0 && len(webhook.EventTypes) > 0 {
-				if len(cr.Spec.ForProvider.EventTypes) != len(webhook.EventTypes) {
-					upToDate = false
-				} else {
-					for i, e := range cr.Spec.ForProvider.EventTypes {
-						if e != webhook.EventTypes[i] {
-							upToDate = false
-							break
-						}
-					}
-				}
-			}
-
-			// Set external name for adoption tracking
-			ctrlutil.SetExternalName(cr, webhook.Name)
-			return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate}, nil
+	// The Harbor policy ID is the stable identifier; names can be edited in
+	// the UI, so record the ID as external-name once the policy is known.
+	ctrlutil.SetExternalName(cr, webhook.ID)
+
+	cr.Status.AtProvider.ID = &webhook.ID
+	t := metav1.NewTime(webhook.CreationTime)
+	cr.Status.AtProvider.CreationTime = &t
+	ut := metav1.NewTime(webhook.UpdateTime)
+	cr.Status.AtProvider.UpdateTime = &ut
+
+	upToDate := isUpToDate(cr.Spec.ForProvider, webhook)
+
+	ca, err := ctrlutil.ResolveCABundle(ctx, c.kube, cr.GetNamespace(), cr.Spec.ForProvider.CABundleRef)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	if ca == nil {
+		cr.Status.AtProvider.CABundle = nil
+	} else if ca.Changed(cr.Status.AtProvider.CABundle) {
+		upToDate = false
+	}
+
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate}, nil
+}
+
+// findWebhook looks up the Harbor policy backing cr. When the external-name
+// holds a policy ID the policy is fetched directly; otherwise an existing
+// policy with the same name in the project is adopted. An external-name equal
+// to metadata.name is the default Crossplane used to set, not a policy name.
+func (c *external) findWebhook(ctx context.Context, cr *v1beta1.Webhook) (*harborclients.WebhookStatus, error) {
+	if id := webhookID(cr); id != "" {
+		webhook, err := c.service.GetWebhook(ctx, cr.Spec.ForProvider.ProjectID, id)
+		if harborclients.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errWebhookGet)
 		}
+		return webhook, nil
 	}
 
-	return managed.ExternalObservation{ResourceExists: false}, nil
+	webhooks, err := c.service.ListWebhooks(ctx, cr.Spec.ForProvider.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := cr.Spec.ForProvider.Name
+	if en := ctrlutil.GetExternalName(cr); en != "" && en != cr.GetName() {
+		// Older resources recorded the policy name as external-name.
+		name = en
+	}
+	for _, webhook := range webhooks {
+		if webhook.Name == name {
+			return webhook, nil
+		}
+	}
+	return nil, nil
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -135,20 +184,30 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotWebhook)
 	}
 
-	spec := &harborclients.WebhookSpec{
-		ProjectID:      cr.Spec.ForProvider.ProjectID,
-		Name:           cr.Spec.ForProvider.Name,
-		Description:    cr.Spec.ForProvider.Description,
-		URL:            cr.Spec.ForProvider.URL,
-		EventTypes:     cr.Spec.ForProvider.EventTypes,
-		AuthHeader:     cr.Spec.ForProvider.AuthHeader,
-		SkipCertVerify: *cr.Spec.ForProvider.SkipCertVerify,
+	ca, err := ctrlutil.ResolveCABundle(ctx, c.kube, cr.GetNamespace(), cr.Spec.ForProvider.CABundleRef)
+	if err != nil {
+		return managed.ExternalCreation{}, err
 	}
 
-	_, err := c.service.CreateWebhook(ctx, spec)
+	// Harbor does not reject a second policy with the same name, so look for
+	// one made by an earlier Create whose response was lost.
+	spec := buildWebhookSpec(cr)
+	webhook, err := ctrlutil.CreateOnce(ctx,
+		func(ctx context.Context) (*harborclients.WebhookStatus, error) { return c.findWebhook(ctx, cr) },
+		func(ctx context.Context) (*harborclients.WebhookStatus, error) {
+			return c.service.CreateWebhook(ctx, spec)
+		})
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
+	if ca != nil {
+		cr.Status.AtProvider.CABundle = ca.Observation()
+	}
+
+	if webhook != nil && webhook.ID != "" {
+		ctrlutil.SetExternalName(cr, webhook.ID)
+		cr.Status.AtProvider.ID = &webhook.ID
+	}
 
 	return managed.ExternalCreation{}, nil
 }
@@ -163,24 +222,22 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotWebhook)
 	}
 
-	if cr.Status.AtProvider.ID == nil {
+	id := webhookID(cr)
+	if id == "" {
 		return managed.ExternalUpdate{}, errors.New("webhook ID not set")
 	}
 
-	spec := &harborclients.WebhookSpec{
-		ProjectID:      cr.Spec.ForProvider.ProjectID,
-		Name:           cr.Spec.ForProvider.Name,
-		Description:    cr.Spec.ForProvider.Description,
-		URL:            cr.Spec.ForProvider.URL,
-		EventTypes:     cr.Spec.ForProvider.EventTypes,
-		AuthHeader:     cr.Spec.ForProvider.AuthHeader,
-		SkipCertVerify: *cr.Spec.ForProvider.SkipCertVerify,
+	ca, err := ctrlutil.ResolveCABundle(ctx, c.kube, cr.GetNamespace(), cr.Spec.ForProvider.CABundleRef)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
 	}
 
-	_, err := c.service.UpdateWebhook(ctx, cr.Spec.ForProvider.ProjectID, *cr.Status.AtProvider.ID, spec)
-	if err != nil {
+	if _, err := c.service.UpdateWebhook(ctx, cr.Spec.ForProvider.ProjectID, id, buildWebhookSpec(cr)); err != nil {
 		return managed.ExternalUpdate{}, err
 	}
+	if ca != nil {
+		cr.Status.AtProvider.CABundle = ca.Observation()
+	}
 
 	return managed.ExternalUpdate{}, nil
 }
@@ -195,12 +252,13 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalDelete{}, errors.New(errNotWebhook)
 	}
 
-	if cr.Status.AtProvider.ID == nil {
+	id := webhookID(cr)
+	if id == "" {
 		return managed.ExternalDelete{}, nil
 	}
 
-	err := c.service.DeleteWebhook(ctx, cr.Spec.ForProvider.ProjectID, *cr.Status.AtProvider.ID)
-	if err != nil {
+	err := c.service.DeleteWebhook(ctx, cr.Spec.ForProvider.ProjectID, id)
+	if err != nil && !harborclients.IsNotFound(err) {
 		return managed.ExternalDelete{}, errors.Wrap(err, errWebhookDelete)
 	}
 
@@ -210,3 +268,41 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) (managed.Ext
 func (c *external) Disconnect(ctx context.Context) error {
 	return c.service.Close()
 }
+
+// webhookID returns the Harbor policy ID for cr, preferring the external-name
+// annotation and falling back to the last observed ID. Non-numeric
+// external-names (policy names recorded by earlier releases) are ignored.
+func webhookID(cr *v1beta1.Webhook) string {
+	if en := ctrlutil.GetExternalName(cr); en != "" {
+		if _, err := strconv.ParseInt(en, 10, 64); err == nil {
+			return en
+		}
+		return ""
+	}
+	if cr.Status.AtProvider.ID != nil {
+		return *cr.Status.AtProvider.ID
+	}
+	return ""
+}
+
+func buildWebhookSpec(cr *v1beta1.Webhook) *harborclients.WebhookSpec {
+	spec := &harborclients.WebhookSpec{
+		ProjectID:   cr.Spec.ForProvider.ProjectID,
+		Name:        cr.Spec.ForProvider.Name,
+		Description: cr.Spec.ForProvider.Description,
+		URL:         cr.Spec.ForProvider.URL,
+		EventTypes:  cr.Spec.ForProvider.EventTypes,
+		AuthHeader:  cr.Spec.ForProvider.AuthHeader,
+		Enabled:     cr.Spec.ForProvider.Enabled == nil || *cr.Spec.ForProvider.Enabled,
+	}
+	if cr.Spec.ForProvider.NotifyType != nil {
+		spec.NotifyType = *cr.Spec.ForProvider.NotifyType
+	}
+	if cr.Spec.ForProvider.PayloadFormat != nil {
+		spec.PayloadFormat = *cr.Spec.ForProvider.PayloadFormat
+	}
+	if cr.Spec.ForProvider.SkipCertVerify != nil && cr.Spec.ForProvider.CABundleRef == nil {
+		spec.SkipCertVerify = *cr.Spec.ForProvider.SkipCertVerify
+	}
+	return spec
+}
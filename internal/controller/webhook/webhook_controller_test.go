@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	sdkwebhook "github.com/goharbor/go-client/pkg/sdk/v2.0/client/webhook"
 	"github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
 	harborclients "github.com/rossigee/provider-harbor/internal/clients"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -701,6 +702,256 @@ func TestDeleteWebhookNoID(t *testing.T) {
 	}
 }
 
+func TestObserveWebhookByExternalID(t *testing.T) {
+	ctx := context.Background()
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-webhook",
+			Annotations: map[string]string{"crossplane.io/external-name": "42"},
+		},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID:  "project-1",
+				Name:       "test-webhook",
+				URL:        "https://webhook.example.com",
+				EventTypes: []string{"PUSH_ARTIFACT"},
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockWebhookClient{
+			getWebhookFunc: func(ctx context.Context, projectID, webhookID string) (*harborclients.WebhookStatus, error) {
+				if webhookID != "42" {
+					t.Errorf("GetWebhook called with ID %q, want 42", webhookID)
+				}
+				return &harborclients.WebhookStatus{
+					ID:         "42",
+					ProjectID:  "1",
+					Name:       "test-webhook",
+					URL:        "https://webhook.example.com",
+					EventTypes: []string{"PUSH_ARTIFACT"},
+				}, nil
+			},
+			listWebhooksFunc: func(ctx context.Context, projectID string) ([]*harborclients.WebhookStatus, error) {
+				t.Error("ListWebhooks should not be called when the policy ID is known")
+				return nil, nil
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, webhook)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Errorf("Observe() = %+v, want existing and up to date", obs)
+	}
+	if webhook.Status.AtProvider.ID == nil || *webhook.Status.AtProvider.ID != "42" {
+		t.Errorf("status ID = %v, want 42", webhook.Status.AtProvider.ID)
+	}
+}
+
+func TestObserveWebhookByExternalIDNotFound(t *testing.T) {
+	ctx := context.Background()
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-webhook",
+			Annotations: map[string]string{"crossplane.io/external-name": "42"},
+		},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID: "project-1",
+				Name:      "test-webhook",
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockWebhookClient{
+			getWebhookFunc: func(ctx context.Context, projectID, webhookID string) (*harborclients.WebhookStatus, error) {
+				return nil, sdkwebhook.NewGetWebhookPolicyOfProjectNotFound()
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, webhook)
+	if err != nil {
+		t.Fatalf("Observe should not fail on 404, got %v", err)
+	}
+	if obs.ResourceExists {
+		t.Error("ResourceExists should be false when the policy was deleted in Harbor")
+	}
+}
+
+func TestObserveWebhookAdoptsByName(t *testing.T) {
+	ctx := context.Background()
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slack",
+		},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID:  "project-1",
+				Name:       "slack",
+				URL:        "https://hooks.slack.com/services/x",
+				EventTypes: []string{"PUSH_ARTIFACT"},
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockWebhookClient{
+			listWebhooksFunc: func(ctx context.Context, projectID string) ([]*harborclients.WebhookStatus, error) {
+				return []*harborclients.WebhookStatus{
+					{ID: "7", Name: "teams", URL: "https://teams.example.com"},
+					{ID: "9", Name: "slack", URL: "https://hooks.slack.com/services/x", EventTypes: []string{"PUSH_ARTIFACT"}},
+				}, nil
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, webhook)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if !obs.ResourceExists {
+		t.Fatal("ResourceExists should be true for an adopted policy")
+	}
+	if got := webhook.GetAnnotations()["crossplane.io/external-name"]; got != "9" {
+		t.Errorf("external-name = %q, want 9", got)
+	}
+}
+
+func TestObserveWebhookMigratesNameExternalName(t *testing.T) {
+	ctx := context.Background()
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "slack",
+			Annotations: map[string]string{"crossplane.io/external-name": "slack"},
+		},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID: "project-1",
+				Name:      "slack",
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockWebhookClient{
+			listWebhooksFunc: func(ctx context.Context, projectID string) ([]*harborclients.WebhookStatus, error) {
+				return []*harborclients.WebhookStatus{{ID: "9", Name: "slack"}}, nil
+			},
+		},
+	}
+
+	if _, err := ext.Observe(ctx, webhook); err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if got := webhook.GetAnnotations()["crossplane.io/external-name"]; got != "9" {
+		t.Errorf("external-name = %q, want 9", got)
+	}
+}
+
+func TestObserveWebhookAdoptsBySpecNameWithDefaultExternalName(t *testing.T) {
+	ctx := context.Background()
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-hook",
+			Annotations: map[string]string{"crossplane.io/external-name": "team-hook"},
+		},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID: "project-1",
+				Name:      "slack",
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockWebhookClient{
+			listWebhooksFunc: func(ctx context.Context, projectID string) ([]*harborclients.WebhookStatus, error) {
+				return []*harborclients.WebhookStatus{{ID: "9", Name: "slack"}}, nil
+			},
+		},
+	}
+
+	obs, err := ext.Observe(ctx, webhook)
+	if err != nil {
+		t.Fatalf("Observe should not fail, got %v", err)
+	}
+	if !obs.ResourceExists {
+		t.Fatal("ResourceExists should be true for the policy named by spec.forProvider.name")
+	}
+	if got := webhook.GetAnnotations()["crossplane.io/external-name"]; got != "9" {
+		t.Errorf("external-name = %q, want 9", got)
+	}
+}
+
+func TestCreateWebhookRecordsExternalName(t *testing.T) {
+	ctx := context.Background()
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-webhook",
+		},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID:  "project-1",
+				Name:       "test-webhook",
+				URL:        "https://webhook.example.com",
+				EventTypes: []string{"PUSH_ARTIFACT"},
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockWebhookClient{
+			createWebhookFunc: func(ctx context.Context, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error) {
+				if spec.SkipCertVerify {
+					t.Error("SkipCertVerify should default to false when unset")
+				}
+				return &harborclients.WebhookStatus{ID: "15", Name: spec.Name}, nil
+			},
+		},
+	}
+
+	if _, err := ext.Create(ctx, webhook); err != nil {
+		t.Fatalf("Create should not fail, got %v", err)
+	}
+	if got := webhook.GetAnnotations()["crossplane.io/external-name"]; got != "15" {
+		t.Errorf("external-name = %q, want 15", got)
+	}
+}
+
+func TestDeleteWebhookAlreadyGone(t *testing.T) {
+	ctx := context.Background()
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-webhook",
+			Annotations: map[string]string{"crossplane.io/external-name": "42"},
+		},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID: "project-1",
+				Name:      "test-webhook",
+			},
+		},
+	}
+
+	ext := &external{
+		service: &mockWebhookClient{
+			deleteWebhookFunc: func(ctx context.Context, projectID, webhookID string) error {
+				return sdkwebhook.NewDeleteWebhookPolicyOfProjectNotFound()
+			},
+		},
+	}
+
+	if _, err := ext.Delete(ctx, webhook); err != nil {
+		t.Errorf("Delete should tolerate 404, got %v", err)
+	}
+}
+
 func TestWebhookHasRequiredFields(t *testing.T) {
 	webhook := &v1beta1.Webhook{
 		ObjectMeta: metav1.ObjectMeta{
@@ -841,22 +1092,38 @@ func TestWebhookParametersValidation(t *testing.T) {
 type mockWebhookClient struct {
 	harborclients.HarborClienter
 	listWebhooksFunc  func(ctx context.Context, projectID string) ([]*harborclients.WebhookStatus, error)
+	getWebhookFunc    func(ctx context.Context, projectID, webhookID string) (*harborclients.WebhookStatus, error)
 	createWebhookFunc func(ctx context.Context, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error)
 	updateWebhookFunc func(ctx context.Context, projectID, webhookID string, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error)
 	deleteWebhookFunc func(ctx context.Context, projectID, webhookID string) error
 	closeFunc         func() error
+
+	// created holds the policies CreateWebhook returned, which ListWebhooks
+	// returns when listWebhooksFunc is not set.
+	created []*harborclients.WebhookStatus
 }
 
 func (m *mockWebhookClient) ListWebhooks(ctx context.Context, projectID string) ([]*harborclients.WebhookStatus, error) {
 	if m.listWebhooksFunc != nil {
 		return m.listWebhooksFunc(ctx, projectID)
 	}
+	return m.created, nil
+}
+
+func (m *mockWebhookClient) GetWebhook(ctx context.Context, projectID, webhookID string) (*harborclients.WebhookStatus, error) {
+	if m.getWebhookFunc != nil {
+		return m.getWebhookFunc(ctx, projectID, webhookID)
+	}
 	return nil, nil
 }
 
 func (m *mockWebhookClient) CreateWebhook(ctx context.Context, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error) {
 	if m.createWebhookFunc != nil {
-		return m.createWebhookFunc(ctx, spec)
+		w, err := m.createWebhookFunc(ctx, spec)
+		if w != nil {
+			m.created = append(m.created, w)
+		}
+		return w, err
 	}
 	return nil, nil
 }
@@ -893,3 +1160,86 @@ func ptrString(s string) *string {
 func ptrBool(b bool) *bool {
 	return &b
 }
+
+func TestCreateWebhookAfterLostResponse(t *testing.T) {
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-webhook"},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID:  "project-1",
+				Name:       "test-webhook",
+				URL:        "https://webhook.example.com",
+				EventTypes: []string{"PUSH_ARTIFACT"},
+			},
+		},
+	}
+
+	creates := 0
+	ext := &external{
+		service: &mockWebhookClient{
+			createWebhookFunc: func(_ context.Context, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error) {
+				creates++
+				// Harbor made the policy but the response timed out.
+				return &harborclients.WebhookStatus{ID: "12", Name: spec.Name}, errors.New("context deadline exceeded")
+			},
+			getWebhookFunc: func(_ context.Context, _, id string) (*harborclients.WebhookStatus, error) {
+				if id != "12" {
+					return nil, nil
+				}
+				return &harborclients.WebhookStatus{ID: "12", Name: "test-webhook"}, nil
+			},
+		},
+	}
+
+	if _, err := ext.Create(context.Background(), webhook); err != nil {
+		t.Fatalf("Create should adopt the policy the timed out request made, got %v", err)
+	}
+	if _, err := ext.Create(context.Background(), webhook); err != nil {
+		t.Fatalf("retried Create should not fail, got %v", err)
+	}
+	if creates != 1 {
+		t.Errorf("CreateWebhook called %d times, want 1", creates)
+	}
+	if got := webhookID(webhook); got != "12" {
+		t.Errorf("webhook ID = %q, want 12", got)
+	}
+}
+
+func TestCreateWebhookAfterLostResponseNamedDifferently(t *testing.T) {
+	// metadata.name differs from the policy name and is still the default
+	// external-name, as on Webhooks created before initializers were off.
+	webhook := &v1beta1.Webhook{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-hook",
+			Annotations: map[string]string{"crossplane.io/external-name": "team-hook"},
+		},
+		Spec: v1beta1.WebhookSpec{
+			ForProvider: v1beta1.WebhookParameters{
+				ProjectID:  "project-1",
+				Name:       "slack",
+				URL:        "https://hooks.slack.com/services/x",
+				EventTypes: []string{"PUSH_ARTIFACT"},
+			},
+		},
+	}
+
+	creates := 0
+	svc := &mockWebhookClient{}
+	svc.createWebhookFunc = func(_ context.Context, spec *harborclients.WebhookSpec) (*harborclients.WebhookStatus, error) {
+		creates++
+		// Harbor made the policy but the response was lost.
+		svc.created = append(svc.created, &harborclients.WebhookStatus{ID: "12", Name: spec.Name})
+		return nil, errors.New("connection reset by peer")
+	}
+	ext := &external{service: svc}
+
+	if _, err := ext.Create(context.Background(), webhook); err != nil {
+		t.Fatalf("Create should adopt the policy the lost request made, got %v", err)
+	}
+	if creates != 1 {
+		t.Errorf("CreateWebhook called %d times, want 1", creates)
+	}
+	if got := webhookID(webhook); got != "12" {
+		t.Errorf("webhook ID = %q, want 12", got)
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package docgen renders the generated CRDs into a machine-readable API
+// reference, so that platform portals can build forms for Harbor resources
+// without parsing OpenAPI schemas themselves.
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	yamlv3 "go.yaml.in/yaml/v3"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// skipped are the fields every managed resource has. Crossplane documents
+// them, so they are left out of the reference.
+var skipped = map[string]bool{
+	"spec.managementPolicies":         true,
+	"spec.providerConfigRef":          true,
+	"spec.writeConnectionSecretToRef": true,
+	"status.conditions":               true,
+	"status.observedGeneration":       true,
+}
+
+// A Reference documents every kind the provider serves.
+type Reference struct {
+	Kinds []Kind `json:"kinds"`
+}
+
+// A Kind documents the storage version of one CRD.
+type Kind struct {
+	Group       string  `json:"group"`
+	Version     string  `json:"version"`
+	Kind        string  `json:"kind"`
+	Scope       string  `json:"scope"`
+	Description string  `json:"description,omitempty"`
+	Fields      []Field `json:"fields"`
+}
+
+// A Field documents one field of a kind. Path is dot separated, with []
+// marking the items of a list and * the values of a map.
+type Field struct {
+	Path        string            `json:"path"`
+	Type        string            `json:"type,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Required    bool              `json:"required,omitempty"`
+	Default     *extv1.JSON       `json:"default,omitempty"`
+	Enum        []extv1.JSON      `json:"enum,omitempty"`
+	Format      string            `json:"format,omitempty"`
+	Pattern     string            `json:"pattern,omitempty"`
+	Minimum     *float64          `json:"minimum,omitempty"`
+	Maximum     *float64          `json:"maximum,omitempty"`
+	MinLength   *int64            `json:"minLength,omitempty"`
+	MaxLength   *int64            `json:"maxLength,omitempty"`
+	Validations []FieldValidation `json:"validations,omitempty"`
+}
+
+// A FieldValidation is a CEL rule the API server enforces on a field.
+type FieldValidation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message,omitempty"`
+}
+
+// LoadCRDs reads every CRD in dir.
+func LoadCRDs(dir string) ([]*extv1.CustomResourceDefinition, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("no CRDs in %s", dir)
+	}
+	crds := make([]*extv1.CustomResourceDefinition, 0, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			return nil, err
+		}
+		crd := &extv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(data, crd); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s", f)
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// Build returns the reference for crds, ordered by group and kind.
+func Build(crds []*extv1.CustomResourceDefinition) Reference {
+	ref := Reference{Kinds: make([]Kind, 0, len(crds))}
+	for _, crd := range crds {
+		v := storageVersion(crd)
+		if v == nil || v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		s := v.Schema.OpenAPIV3Schema
+		k := Kind{
+			Group:       crd.Spec.Group,
+			Version:     v.Name,
+			Kind:        crd.Spec.Names.Kind,
+			Scope:       string(crd.Spec.Scope),
+			Description: s.Description,
+		}
+		for _, name := range []string{"spec", "status"} {
+			if p, ok := s.Properties[name]; ok {
+				k.Fields = appendFields(k.Fields, name, &p, false)
+			}
+		}
+		ref.Kinds = append(ref.Kinds, k)
+	}
+	sort.Slice(ref.Kinds, func(i, j int) bool {
+		if ref.Kinds[i].Group != ref.Kinds[j].Group {
+			return ref.Kinds[i].Group < ref.Kinds[j].Group
+		}
+		return ref.Kinds[i].Kind < ref.Kinds[j].Kind
+	})
+	return ref
+}
+
+// storageVersion returns the version of crd that is stored, which is the
+// one the provider's controllers reconcile.
+func storageVersion(crd *extv1.CustomResourceDefinition) *extv1.CustomResourceDefinitionVersion {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Storage {
+			return &crd.Spec.Versions[i]
+		}
+	}
+	return nil
+}
+
+// appendFields appends the fields of the schema s at path, depth first in
+// alphabetical order, to fields.
+func appendFields(fields []Field, path string, s *extv1.JSONSchemaProps, required bool) []Field {
+	if skipped[path] {
+		return fields
+	}
+	f := Field{
+		Path:        path,
+		Type:        s.Type,
+		Description: s.Description,
+		Required:    required,
+		Default:     s.Default,
+		Enum:        s.Enum,
+		Format:      s.Format,
+		Pattern:     s.Pattern,
+		Minimum:     s.Minimum,
+		Maximum:     s.Maximum,
+		MinLength:   s.MinLength,
+		MaxLength:   s.MaxLength,
+	}
+	for _, r := range s.XValidations {
+		f.Validations = append(f.Validations, FieldValidation{Rule: r.Rule, Message: r.Message})
+	}
+	if s.XIntOrString {
+		f.Type = "integer-or-string"
+	}
+	// The spec and status objects themselves need no documentation.
+	if strings.Contains(path, ".") {
+		fields = append(fields, f)
+	}
+
+	req := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		req[r] = true
+	}
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		p := s.Properties[n]
+		fields = appendFields(fields, path+"."+n, &p, req[n])
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		fields = appendFields(fields, path+"[]", s.Items.Schema, false)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		fields = appendFields(fields, path+".*", s.AdditionalProperties.Schema, false)
+	}
+	return fields
+}
+
+// YAML returns ref as YAML.
+func (r Reference) YAML() ([]byte, error) {
+	return yaml.Marshal(r)
+}
+
+// JSON returns ref as compact JSON.
+func (r Reference) JSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// AnnotationAPIReference is the package metadata annotation that holds the
+// reference as JSON.
+const AnnotationAPIReference = "harbor.m.crossplane.io/api-reference"
+
+// Annotate returns the package metadata pkg with the reference in its
+// AnnotationAPIReference annotation. The rest of the document, including its
+// comments and key order, is kept.
+func Annotate(pkg []byte, ref Reference) ([]byte, error) {
+	j, err := ref.JSON()
+	if err != nil {
+		return nil, err
+	}
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal(pkg, doc); err != nil {
+		return nil, errors.Wrap(err, "cannot parse package metadata")
+	}
+	if len(doc.Content) == 0 {
+		return nil, errors.New("package metadata is empty")
+	}
+	annotations := mapping(mapping(doc.Content[0], "metadata"), "annotations")
+	set(annotations, AnnotationAPIReference, string(j))
+
+	var b bytes.Buffer
+	enc := yamlv3.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), enc.Close()
+}
+
+// mapping returns the mapping at key in m, adding it if m has none.
+func mapping(m *yamlv3.Node, key string) *yamlv3.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	v := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+	m.Content = append(m.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}, v)
+	return v
+}
+
+// set sets key in m to the string value.
+func set(m *yamlv3.Node, key, value string) {
+	v := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value, Style: yamlv3.SingleQuotedStyle}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = v
+			return
+		}
+	}
+	m.Content = append(m.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}, v)
+}
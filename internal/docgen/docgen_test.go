@@ -0,0 +1,167 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func testCRD() *extv1.CustomResourceDefinition {
+	str := func(d string) extv1.JSONSchemaProps { return extv1.JSONSchemaProps{Type: "string", Description: d} }
+	def := &extv1.JSON{Raw: []byte(`"Reject"`)}
+	return &extv1.CustomResourceDefinition{
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "robot.harbor.m.crossplane.io",
+			Names: extv1.CustomResourceDefinitionNames{Kind: "Robot"},
+			Scope: extv1.NamespaceScoped,
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1"},
+				{
+					Name:    "v1beta1",
+					Storage: true,
+					Schema: &extv1.CustomResourceValidation{OpenAPIV3Schema: &extv1.JSONSchemaProps{
+						Description: "A Robot is a Harbor robot account.",
+						Properties: map[string]extv1.JSONSchemaProps{
+							"spec": {Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+								"providerConfigRef": {Type: "object", Properties: map[string]extv1.JSONSchemaProps{"name": str("")}},
+								"forProvider": {Type: "object", Required: []string{"name"}, Properties: map[string]extv1.JSONSchemaProps{
+									"name":         str("Name of the robot."),
+									"expiryPolicy": {Type: "string", Default: def, Enum: []extv1.JSON{{Raw: []byte(`"Reject"`)}, {Raw: []byte(`"Clamp"`)}}},
+									"permissions": {Type: "array", Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+										Type:       "object",
+										Properties: map[string]extv1.JSONSchemaProps{"namespace": str("")},
+									}}},
+								}},
+							}},
+							"status": {Type: "object", Properties: map[string]extv1.JSONSchemaProps{
+								"conditions": {Type: "array"},
+								"atProvider": {Type: "object", Properties: map[string]extv1.JSONSchemaProps{"id": str("")}},
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	ref := Build([]*extv1.CustomResourceDefinition{testCRD()})
+	if len(ref.Kinds) != 1 {
+		t.Fatalf("Build() returned %d kinds, want 1", len(ref.Kinds))
+	}
+	k := ref.Kinds[0]
+	if k.Version != "v1beta1" || k.Kind != "Robot" || k.Scope != "Namespaced" {
+		t.Errorf("Build() kind = %s %s %s, want the Namespaced v1beta1 Robot", k.Version, k.Kind, k.Scope)
+	}
+
+	var paths []string
+	fields := map[string]Field{}
+	for _, f := range k.Fields {
+		paths = append(paths, f.Path)
+		fields[f.Path] = f
+	}
+	want := []string{
+		"spec.forProvider",
+		"spec.forProvider.expiryPolicy",
+		"spec.forProvider.name",
+		"spec.forProvider.permissions",
+		"spec.forProvider.permissions[]",
+		"spec.forProvider.permissions[].namespace",
+		"status.atProvider",
+		"status.atProvider.id",
+	}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("Build() paths =\n%s\nwant\n%s", strings.Join(paths, "\n"), strings.Join(want, "\n"))
+	}
+	if !fields["spec.forProvider.name"].Required || fields["spec.forProvider.expiryPolicy"].Required {
+		t.Error("Build() did not mark only name as required")
+	}
+	p := fields["spec.forProvider.expiryPolicy"]
+	if p.Default == nil || string(p.Default.Raw) != `"Reject"` || len(p.Enum) != 2 {
+		t.Errorf("Build() expiryPolicy = %+v, want its default and enum", p)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	pkg := []byte(`apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-harbor
+  annotations:
+    # Shown in the marketplace.
+    meta.crossplane.io/maintainer: Ross Golder
+    harbor.m.crossplane.io/api-reference: stale
+`)
+	ref := Build([]*extv1.CustomResourceDefinition{testCRD()})
+	got, err := Annotate(pkg, ref)
+	if err != nil {
+		t.Fatalf("Annotate() error = %v", err)
+	}
+	if !bytes.Contains(got, []byte("# Shown in the marketplace.")) {
+		t.Errorf("Annotate() dropped a comment:\n%s", got)
+	}
+
+	meta := struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := yaml.Unmarshal(got, &meta); err != nil {
+		t.Fatalf("Annotate() returned invalid YAML: %v", err)
+	}
+	if meta.Metadata.Annotations["meta.crossplane.io/maintainer"] != "Ross Golder" {
+		t.Errorf("Annotate() lost the other annotations: %v", meta.Metadata.Annotations)
+	}
+	embedded := Reference{}
+	if err := json.Unmarshal([]byte(meta.Metadata.Annotations[AnnotationAPIReference]), &embedded); err != nil {
+		t.Fatalf("%s is not a JSON reference: %v", AnnotationAPIReference, err)
+	}
+	if len(embedded.Kinds) != 1 || embedded.Kinds[0].Kind != "Robot" {
+		t.Errorf("%s = %+v, want the Robot reference", AnnotationAPIReference, embedded)
+	}
+}
+
+// TestReferenceIsCurrent fails when the CRDs were regenerated without
+// regenerating the API reference.
+func TestReferenceIsCurrent(t *testing.T) {
+	crds, err := LoadCRDs("../../package/crds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := Build(crds)
+	const regenerate = "; run go run ./cmd/docgen"
+
+	want, err := ref.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../../docs/api-reference.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(got, want) {
+		t.Error("docs/api-reference.yaml is out of date" + regenerate)
+	}
+
+	pkg, err := os.ReadFile("../../package/crossplane.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotated, err := Annotate(pkg, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pkg, annotated) {
+		t.Error("the API reference in package/crossplane.yaml is out of date" + regenerate)
+	}
+}
@@ -4,7 +4,13 @@
 
 package features
 
-import "github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+import (
+	"sort"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/feature"
+	"github.com/pkg/errors"
+)
 
 // Feature flags.
 const (
@@ -13,8 +19,47 @@ const (
 	// https://github.com/crossplane/crossplane/blob/390ddd/design/design-doc-external-secret-stores.md
 	EnableAlphaExternalSecretStores feature.Flag = "EnableAlphaExternalSecretStores"
 
-	// EnableBetaManagementPolicies enables beta support for
+	// EnableBetaManagementPolicies enabled beta support for
 	// Management Policies. See the below design for more details.
 	// https://github.com/crossplane/crossplane/pull/3531
+	//
+	// Deprecated: every controller now honours management policies. The
+	// flag is still accepted so that existing deployments keep starting.
 	EnableBetaManagementPolicies feature.Flag = "EnableBetaManagementPolicies"
+
+	// EnableAlphaWebhookDriftDetection enables alpha support for comparing
+	// observed Harbor webhook policies against their spec and updating them
+	// in place when they drift.
+	EnableAlphaWebhookDriftDetection feature.Flag = "EnableAlphaWebhookDriftDetection"
 )
+
+// known is the set of flags that may be passed to --enable-feature.
+var known = map[feature.Flag]bool{
+	EnableAlphaExternalSecretStores:  true,
+	EnableBetaManagementPolicies:     true,
+	EnableAlphaWebhookDriftDetection: true,
+}
+
+// Known returns the names of all supported feature flags, sorted.
+func Known() []string {
+	names := make([]string, 0, len(known))
+	for f := range known {
+		names = append(names, string(f))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse returns the feature flags named in names. Unknown names are rejected
+// so that a typo does not silently leave a feature disabled.
+func Parse(names []string) (*feature.Flags, error) {
+	flags := &feature.Flags{}
+	for _, n := range names {
+		f := feature.Flag(strings.TrimSpace(n))
+		if !known[f] {
+			return nil, errors.Errorf("unknown feature %q, must be one of: %s", n, strings.Join(Known(), ", "))
+		}
+		flags.Enable(f)
+	}
+	return flags, nil
+}
@@ -0,0 +1,31 @@
+/*
+ Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package features
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	flags, err := Parse([]string{"EnableBetaManagementPolicies", " EnableAlphaWebhookDriftDetection "})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !flags.Enabled(EnableBetaManagementPolicies) {
+		t.Error("EnableBetaManagementPolicies not enabled")
+	}
+	if !flags.Enabled(EnableAlphaWebhookDriftDetection) {
+		t.Error("EnableAlphaWebhookDriftDetection not enabled")
+	}
+	if flags.Enabled(EnableAlphaExternalSecretStores) {
+		t.Error("EnableAlphaExternalSecretStores enabled but not requested")
+	}
+}
+
+func TestParseUnknown(t *testing.T) {
+	if _, err := Parse([]string{"EnableBetaManagmentPolicies"}); err == nil {
+		t.Error("Parse() accepted a misspelled feature")
+	}
+}
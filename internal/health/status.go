@@ -0,0 +1,158 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Keys of the status ConfigMap.
+const (
+	StatusKeyUpdated     = "updated"
+	StatusKeyControllers = "controllers.json"
+	StatusKeyClients     = "clients.json"
+)
+
+const (
+	errEncodeStatus = "cannot encode provider status"
+	errWriteStatus  = "cannot write provider status ConfigMap"
+)
+
+// ControllerStats are what a controller's metrics read when the status was
+// written.
+type ControllerStats struct {
+	// Depth is how many requests are queued.
+	Depth float64 `json:"depth"`
+
+	// Reconciles is how many reconciles have run since the provider
+	// started.
+	Reconciles float64 `json:"reconciles"`
+
+	// Errors is how many of those reconciles failed.
+	Errors float64 `json:"errors"`
+}
+
+// A StatusWriter periodically writes the workqueue stats of every
+// controller and the state shared by the Harbor clients to a ConfigMap, so
+// that they can be read with kubectl where there is no Prometheus.
+type StatusWriter struct {
+	kube      client.Client
+	name      types.NamespacedName
+	gatherer  prometheus.Gatherer
+	clientsFn func() clients.ClientStats
+	log       logging.Logger
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// A StatusOption configures a StatusWriter.
+type StatusOption func(*StatusWriter)
+
+// WithStatusLogger sets the logger.
+func WithStatusLogger(l logging.Logger) StatusOption {
+	return func(w *StatusWriter) { w.log = l }
+}
+
+// WithStatusGatherer sets where controller metrics are read from.
+func WithStatusGatherer(g prometheus.Gatherer) StatusOption {
+	return func(w *StatusWriter) { w.gatherer = g }
+}
+
+// WithStatusInterval sets how often the ConfigMap is written.
+func WithStatusInterval(d time.Duration) StatusOption {
+	return func(w *StatusWriter) { w.interval = d }
+}
+
+// NewStatusWriter returns a writer that keeps the ConfigMap name current
+// every minute.
+func NewStatusWriter(kube client.Client, name types.NamespacedName, o ...StatusOption) *StatusWriter {
+	w := &StatusWriter{
+		kube:      kube,
+		name:      name,
+		gatherer:  crmetrics.Registry,
+		clientsFn: clients.Stats,
+		log:       logging.NewNopLogger(),
+		interval:  time.Minute,
+		now:       time.Now,
+	}
+	for _, fn := range o {
+		fn(w)
+	}
+	return w
+}
+
+// NeedLeaderElection is true; only the leader's controllers do any work.
+func (w *StatusWriter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start writes the status every interval until ctx is done.
+func (w *StatusWriter) Start(ctx context.Context) error {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		if err := w.Write(ctx); err != nil {
+			w.log.Info("Cannot write provider status", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Write writes the current status to the ConfigMap, creating it if needed.
+func (w *StatusWriter) Write(ctx context.Context) error {
+	samples, err := gather(w.gatherer)
+	if err != nil {
+		return errors.Wrap(err, errGather)
+	}
+	stats := make(map[string]ControllerStats, len(samples))
+	for c, s := range samples {
+		stats[c] = ControllerStats{Depth: s.depth, Reconciles: s.reconciles, Errors: s.errors}
+	}
+	controllers, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, errEncodeStatus)
+	}
+	shared, err := json.MarshalIndent(w.clientsFn(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, errEncodeStatus)
+	}
+	data := map[string]string{
+		StatusKeyUpdated:     w.now().UTC().Format(time.RFC3339),
+		StatusKeyControllers: string(controllers),
+		StatusKeyClients:     string(shared),
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = w.kube.Get(ctx, w.name, cm)
+	if kerrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: w.name.Namespace, Name: w.name.Name},
+			Data:       data,
+		}
+		return errors.Wrap(w.kube.Create(ctx, cm), errWriteStatus)
+	}
+	if err != nil {
+		return errors.Wrap(err, errWriteStatus)
+	}
+	cm.Data = data
+	return errors.Wrap(w.kube.Update(ctx, cm), errWriteStatus)
+}
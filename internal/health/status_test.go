@@ -0,0 +1,67 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rossigee/provider-harbor/internal/clients"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStatusWriter(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).Build()
+	m := newControllerMetrics()
+	m.depth.WithLabelValues("managed/project", "managed/project", "").Set(3)
+	m.reconcile("managed/project", 8, 2)
+
+	key := types.NamespacedName{Namespace: "crossplane-system", Name: "provider-harbor-status"}
+	w := NewStatusWriter(kube, key, WithStatusGatherer(m.registry))
+	w.now = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC) }
+	w.clientsFn = func() clients.ClientStats {
+		return clients.ClientStats{SystemCacheEntries: 2, UnavailableEndpoints: []string{"https://harbor.example.com"}}
+	}
+
+	// The second write updates the ConfigMap the first created.
+	for i := 0; i < 2; i++ {
+		if err := w.Write(context.Background()); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := kube.Get(context.Background(), key, cm); err != nil {
+		t.Fatal(err)
+	}
+	if got := cm.Data[StatusKeyUpdated]; got != "2026-01-01T12:00:00Z" {
+		t.Errorf("%s = %q", StatusKeyUpdated, got)
+	}
+	var controllers map[string]ControllerStats
+	if err := json.Unmarshal([]byte(cm.Data[StatusKeyControllers]), &controllers); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]ControllerStats{"managed/project": {Depth: 3, Reconciles: 10, Errors: 2}}
+	if !reflect.DeepEqual(controllers, want) {
+		t.Errorf("%s = %+v, want %+v", StatusKeyControllers, controllers, want)
+	}
+	var shared clients.ClientStats
+	if err := json.Unmarshal([]byte(cm.Data[StatusKeyClients]), &shared); err != nil {
+		t.Fatal(err)
+	}
+	if shared.SystemCacheEntries != 2 || len(shared.UnavailableEndpoints) != 1 {
+		t.Errorf("%s = %+v", StatusKeyClients, shared)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package health checks that the provider's controllers are keeping up with
+// their work, so that a stuck controller is noticed without reading logs.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Metrics controller-runtime exports for every controller, labelled with the
+// controller's name.
+const (
+	metricDepth      = "workqueue_depth"
+	metricReconciles = "controller_runtime_reconcile_total"
+	metricErrors     = "controller_runtime_reconcile_errors_total"
+)
+
+const (
+	// DefaultMaxDepth is how many queued requests a controller may have.
+	DefaultMaxDepth = 500
+	// DefaultMaxErrorRate is the fraction of reconciles that may fail.
+	DefaultMaxErrorRate = 0.5
+	// DefaultUnhealthyAfter is how long a controller may be over a
+	// threshold before it is reported as degraded.
+	DefaultUnhealthyAfter = 10 * time.Minute
+
+	errGather = "cannot gather controller metrics"
+)
+
+// A sample is what a controller's metrics read at one point in time.
+type sample struct {
+	depth      float64
+	reconciles float64
+	errors     float64
+}
+
+// A WorkqueueMonitor samples the depth and reconcile error rate of every
+// controller's workqueue, and reports controllers that stay over either
+// threshold for a sustained period as degraded.
+type WorkqueueMonitor struct {
+	gatherer       prometheus.Gatherer
+	log            logging.Logger
+	interval       time.Duration
+	maxDepth       float64
+	maxErrorRate   float64
+	unhealthyAfter time.Duration
+	now            func() time.Time
+
+	mu       sync.Mutex
+	last     map[string]sample
+	since    map[string]time.Time
+	degraded map[string]string
+}
+
+// A MonitorOption configures a WorkqueueMonitor.
+type MonitorOption func(*WorkqueueMonitor)
+
+// WithLogger sets the logger.
+func WithLogger(l logging.Logger) MonitorOption {
+	return func(m *WorkqueueMonitor) { m.log = l }
+}
+
+// WithGatherer sets where controller metrics are read from.
+func WithGatherer(g prometheus.Gatherer) MonitorOption {
+	return func(m *WorkqueueMonitor) { m.gatherer = g }
+}
+
+// WithInterval sets how often metrics are sampled.
+func WithInterval(d time.Duration) MonitorOption {
+	return func(m *WorkqueueMonitor) { m.interval = d }
+}
+
+// WithMaxDepth sets how many queued requests a controller may have.
+func WithMaxDepth(n int) MonitorOption {
+	return func(m *WorkqueueMonitor) { m.maxDepth = float64(n) }
+}
+
+// WithMaxErrorRate sets the fraction of reconciles between two samples that
+// may fail.
+func WithMaxErrorRate(r float64) MonitorOption {
+	return func(m *WorkqueueMonitor) { m.maxErrorRate = r }
+}
+
+// WithUnhealthyAfter sets how long a controller may be over a threshold
+// before it is degraded.
+func WithUnhealthyAfter(d time.Duration) MonitorOption {
+	return func(m *WorkqueueMonitor) { m.unhealthyAfter = d }
+}
+
+// NewWorkqueueMonitor returns a monitor that samples controller-runtime's
+// metrics every 30 seconds.
+func NewWorkqueueMonitor(o ...MonitorOption) *WorkqueueMonitor {
+	m := &WorkqueueMonitor{
+		gatherer:       crmetrics.Registry,
+		log:            logging.NewNopLogger(),
+		interval:       30 * time.Second,
+		maxDepth:       DefaultMaxDepth,
+		maxErrorRate:   DefaultMaxErrorRate,
+		unhealthyAfter: DefaultUnhealthyAfter,
+		now:            time.Now,
+		last:           map[string]sample{},
+		since:          map[string]time.Time{},
+		degraded:       map[string]string{},
+	}
+	for _, fn := range o {
+		fn(m)
+	}
+	return m
+}
+
+// NeedLeaderElection is false; every replica monitors its own controllers.
+func (m *WorkqueueMonitor) NeedLeaderElection() bool {
+	return false
+}
+
+// Start samples every interval until ctx is done.
+func (m *WorkqueueMonitor) Start(ctx context.Context) error {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if err := m.Sample(); err != nil {
+				m.log.Info("Cannot sample controller workqueues", "error", err)
+			}
+		}
+	}
+}
+
+// Sample reads the controllers' metrics and updates which are degraded.
+func (m *WorkqueueMonitor) Sample() error {
+	samples, err := gather(m.gatherer)
+	if err != nil {
+		return errors.Wrap(err, errGather)
+	}
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for c, s := range samples {
+		reason := m.unhealthy(s, m.last[c])
+		m.last[c] = s
+
+		if reason == "" {
+			if _, ok := m.degraded[c]; ok {
+				m.log.Info("Controller recovered", "controller", c)
+			}
+			delete(m.since, c)
+			delete(m.degraded, c)
+			metrics.SetControllerDegraded(c, false)
+			continue
+		}
+		if _, ok := m.since[c]; !ok {
+			m.since[c] = now
+		}
+		if now.Sub(m.since[c]) < m.unhealthyAfter {
+			continue
+		}
+		if _, ok := m.degraded[c]; !ok {
+			m.log.Info("Controller degraded", "controller", c, "reason", reason, "since", m.since[c])
+		}
+		m.degraded[c] = reason
+		metrics.SetControllerDegraded(c, true)
+	}
+	return nil
+}
+
+// unhealthy returns why a controller is over a threshold, or "".
+func (m *WorkqueueMonitor) unhealthy(s, last sample) string {
+	if s.depth > m.maxDepth {
+		return fmt.Sprintf("workqueue depth %.0f exceeds %.0f", s.depth, m.maxDepth)
+	}
+	reconciles, errs := s.reconciles-last.reconciles, s.errors-last.errors
+	if reconciles > 0 && errs/reconciles > m.maxErrorRate {
+		return fmt.Sprintf("%.0f of %.0f reconciles failed", errs, reconciles)
+	}
+	return ""
+}
+
+// Check is a healthz.Checker that fails while any controller is degraded.
+func (m *WorkqueueMonitor) Check(_ *http.Request) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.degraded) == 0 {
+		return nil
+	}
+	reasons := make([]string, 0, len(m.degraded))
+	for c, r := range m.degraded {
+		reasons = append(reasons, c+": "+r)
+	}
+	sort.Strings(reasons)
+	return errors.Errorf("degraded controllers: %s", strings.Join(reasons, "; "))
+}
+
+// gather reads a sample of every controller from g.
+func gather(g prometheus.Gatherer) (map[string]sample, error) {
+	families, err := g.Gather()
+	if err != nil {
+		return nil, err
+	}
+	samples := map[string]sample{}
+	for _, f := range families {
+		for _, mt := range f.GetMetric() {
+			c := label(mt, "controller")
+			if c == "" {
+				continue
+			}
+			s := samples[c]
+			switch f.GetName() {
+			case metricDepth:
+				s.depth += mt.GetGauge().GetValue()
+			case metricReconciles:
+				s.reconciles += mt.GetCounter().GetValue()
+			case metricErrors:
+				s.errors += mt.GetCounter().GetValue()
+			default:
+				continue
+			}
+			samples[c] = s
+		}
+	}
+	return samples, nil
+}
+
+func label(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package health
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rossigee/provider-harbor/internal/metrics"
+)
+
+// controllerMetrics stands in for the metrics controller-runtime registers.
+type controllerMetrics struct {
+	registry   *prometheus.Registry
+	depth      *prometheus.GaugeVec
+	reconciles *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+}
+
+func newControllerMetrics() *controllerMetrics {
+	m := &controllerMetrics{
+		registry:   prometheus.NewRegistry(),
+		depth:      prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: metricDepth}, []string{"name", "controller", "priority"}),
+		reconciles: prometheus.NewCounterVec(prometheus.CounterOpts{Name: metricReconciles}, []string{"controller", "result"}),
+		errors:     prometheus.NewCounterVec(prometheus.CounterOpts{Name: metricErrors}, []string{"controller"}),
+	}
+	m.registry.MustRegister(m.depth, m.reconciles, m.errors)
+	return m
+}
+
+func (m *controllerMetrics) reconcile(controller string, ok, failed int) {
+	m.reconciles.WithLabelValues(controller, "success").Add(float64(ok))
+	m.reconciles.WithLabelValues(controller, "error").Add(float64(failed))
+	m.errors.WithLabelValues(controller).Add(float64(failed))
+}
+
+// clock is a fake time that each sample advances.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newMonitor(m *controllerMetrics, c *clock) *WorkqueueMonitor {
+	mon := NewWorkqueueMonitor(WithGatherer(m.registry), WithMaxDepth(10), WithMaxErrorRate(0.5), WithUnhealthyAfter(time.Minute))
+	mon.now = c.now
+	return mon
+}
+
+func sampleAt(t *testing.T, mon *WorkqueueMonitor, c *clock, after time.Duration) {
+	t.Helper()
+	c.t = c.t.Add(after)
+	if err := mon.Sample(); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+}
+
+func TestSustainedDepth(t *testing.T) {
+	m := newControllerMetrics()
+	c := &clock{t: time.Unix(0, 0)}
+	mon := newMonitor(m, c)
+
+	m.depth.WithLabelValues("project", "project", "").Set(8)
+	m.depth.WithLabelValues("project", "project", "-10").Set(8)
+	sampleAt(t, mon, c, 0)
+	if err := mon.Check(nil); err != nil {
+		t.Fatalf("Check() = %v as soon as the queue is deep, want nil", err)
+	}
+
+	sampleAt(t, mon, c, time.Minute)
+	err := mon.Check(nil)
+	if err == nil || !strings.Contains(err.Error(), "project: workqueue depth 16") {
+		t.Fatalf("Check() = %v, want project degraded by its depth", err)
+	}
+	if v := testutil.ToFloat64(metrics.ControllerDegraded.WithLabelValues("project")); v != 1 {
+		t.Errorf("harbor_controller_degraded = %v, want 1", v)
+	}
+
+	m.depth.WithLabelValues("project", "project", "-10").Set(0)
+	sampleAt(t, mon, c, time.Minute)
+	if err := mon.Check(nil); err != nil {
+		t.Errorf("Check() = %v after the queue drained, want nil", err)
+	}
+	if v := testutil.ToFloat64(metrics.ControllerDegraded.WithLabelValues("project")); v != 0 {
+		t.Errorf("harbor_controller_degraded = %v after recovery, want 0", v)
+	}
+}
+
+func TestSustainedErrors(t *testing.T) {
+	m := newControllerMetrics()
+	c := &clock{t: time.Unix(0, 0)}
+	mon := newMonitor(m, c)
+
+	m.reconcile("robot", 100, 0)
+	m.reconcile("webhook", 10, 0)
+	sampleAt(t, mon, c, 0)
+
+	// Only the reconciles since the last sample count.
+	m.reconcile("robot", 1, 3)
+	m.reconcile("webhook", 10, 1)
+	sampleAt(t, mon, c, 30*time.Second)
+	m.reconcile("robot", 0, 2)
+	m.reconcile("webhook", 10, 1)
+	sampleAt(t, mon, c, 30*time.Second)
+	m.reconcile("robot", 0, 2)
+	m.reconcile("webhook", 10, 1)
+	sampleAt(t, mon, c, 30*time.Second)
+
+	err := mon.Check(nil)
+	if err == nil || !strings.Contains(err.Error(), "robot: 2 of 2 reconciles failed") {
+		t.Fatalf("Check() = %v, want robot degraded by its error rate", err)
+	}
+	if strings.Contains(err.Error(), "webhook") {
+		t.Errorf("Check() = %v, webhook is under the error rate threshold", err)
+	}
+}
+
+func TestBriefSpike(t *testing.T) {
+	m := newControllerMetrics()
+	c := &clock{t: time.Unix(0, 0)}
+	mon := newMonitor(m, c)
+
+	m.depth.WithLabelValues("user", "user", "").Set(50)
+	sampleAt(t, mon, c, 0)
+	m.depth.WithLabelValues("user", "user", "").Set(0)
+	sampleAt(t, mon, c, 30*time.Second)
+	m.depth.WithLabelValues("user", "user", "").Set(50)
+	sampleAt(t, mon, c, 45*time.Second)
+
+	if err := mon.Check(nil); err != nil {
+		t.Errorf("Check() = %v, want spikes shorter than the unhealthy period ignored", err)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package metrics defines the Prometheus metrics the provider exports. They
+// are registered with controller-runtime's registry and served on the
+// manager's metrics endpoint.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// RobotExpiry is when each managed robot account expires. Robots that never
+// expire have no series.
+var RobotExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "harbor_robot_expiry_timestamp_seconds",
+	Help: "Unix time at which a managed Harbor robot account expires.",
+}, []string{"namespace", "name", "robot"})
+
+// SetRobotExpiry records when the robot account managed by the Robot
+// namespace/name expires, replacing any series for an earlier robot name.
+func SetRobotExpiry(namespace, name, robot string, expiresAt time.Time) {
+	DeleteRobotExpiry(namespace, name)
+	RobotExpiry.WithLabelValues(namespace, name, robot).Set(float64(expiresAt.Unix()))
+}
+
+// DeleteRobotExpiry removes the expiry of the Robot namespace/name.
+func DeleteRobotExpiry(namespace, name string) {
+	RobotExpiry.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
+
+// ControllerDegraded is 1 for each controller whose workqueue has been
+// unhealthy for longer than the provider tolerates, and 0 otherwise.
+var ControllerDegraded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "harbor_controller_degraded",
+	Help: "Whether a controller's workqueue depth or reconcile error rate has stayed over its threshold.",
+}, []string{"controller"})
+
+// ExternalRequestDuration is how long the Observe, Create, Update and Delete
+// calls each kind's controller makes to Harbor take, excluding the time the
+// request spends queued and the Kubernetes API calls around it.
+var ExternalRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "harbor_external_request_duration_seconds",
+	Help:    "Duration of the external Observe, Create, Update and Delete calls of a managed resource kind.",
+	Buckets: []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+}, []string{"kind", "operation", "result"})
+
+// SyncLatency is how long a new generation of a managed resource's spec
+// took to be observed applied in Harbor, from when the provider first saw it
+// or, for a new resource, from when it was created.
+var SyncLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "harbor_sync_latency_seconds",
+	Help:    "Time from a change to a managed resource's spec until it was observed applied in Harbor.",
+	Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+}, []string{"kind"})
+
+// ManagedResources is how many managed resources of each kind have each
+// status of their Ready and Synced conditions.
+var ManagedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "harbor_managed_resources",
+	Help: "Number of managed resources of a kind by the status of a condition.",
+}, []string{"kind", "condition", "status"})
+
+// CloudEvents is how many lifecycle CloudEvents of each type were sent to the
+// configured sink, failed to be sent, or were dropped because the queue of
+// events waiting to be sent was full.
+var CloudEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "harbor_cloudevents_total",
+	Help: "Number of lifecycle CloudEvents by type and whether they were sent, failed or dropped.",
+}, []string{"type", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(RobotExpiry, ControllerDegraded, ExternalRequestDuration, SyncLatency, ManagedResources, CloudEvents)
+}
+
+// Results of an external request.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Results of a CloudEvent.
+const (
+	ResultSent    = "sent"
+	ResultFailed  = "failed"
+	ResultDropped = "dropped"
+)
+
+// ObserveExternalRequest records that an operation on a managed resource of
+// the given kind took d and returned err.
+func ObserveExternalRequest(kind, operation string, d time.Duration, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+	ExternalRequestDuration.WithLabelValues(kind, operation, result).Observe(d.Seconds())
+}
+
+// ObserveSyncLatency records that a spec change to a managed resource of the
+// given kind took d to be applied in Harbor.
+func ObserveSyncLatency(kind string, d time.Duration) {
+	SyncLatency.WithLabelValues(kind).Observe(d.Seconds())
+}
+
+// SetControllerDegraded records whether the named controller is degraded.
+func SetControllerDegraded(controller string, degraded bool) {
+	v := 0.0
+	if degraded {
+		v = 1
+	}
+	ControllerDegraded.WithLabelValues(controller).Set(v)
+}
+
+// CountCloudEvent records the result of a CloudEvent of the given type.
+func CountCloudEvent(eventType, result string) {
+	CloudEvents.WithLabelValues(eventType, result).Inc()
+}
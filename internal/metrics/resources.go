@@ -0,0 +1,174 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// groupSuffix is the suffix of the API groups of the provider's kinds.
+const groupSuffix = "harbor.m.crossplane.io"
+
+// countedConditions are the conditions ManagedResources reports.
+var countedConditions = []xpv1.ConditionType{xpv1.TypeReady, xpv1.TypeSynced}
+
+// A ResourceCounter periodically counts the provider's managed resources by
+// kind and condition and exports the counts as ManagedResources.
+type ResourceCounter struct {
+	kube     client.Reader
+	kinds    []schema.GroupVersionKind
+	scheme   *runtime.Scheme
+	log      logging.Logger
+	interval time.Duration
+}
+
+// A CounterOption configures a ResourceCounter.
+type CounterOption func(*ResourceCounter)
+
+// WithLogger sets the logger.
+func WithLogger(l logging.Logger) CounterOption {
+	return func(c *ResourceCounter) { c.log = l }
+}
+
+// WithInterval sets how often resources are counted.
+func WithInterval(d time.Duration) CounterOption {
+	return func(c *ResourceCounter) { c.interval = d }
+}
+
+// WithKinds limits counting to the named kinds, so that kinds whose
+// controllers are not running are not listed and cached.
+func WithKinds(kinds ...string) CounterOption {
+	return func(c *ResourceCounter) {
+		keep := make(map[string]bool, len(kinds))
+		for _, k := range kinds {
+			keep[k] = true
+		}
+		var filtered []schema.GroupVersionKind
+		for _, gvk := range c.kinds {
+			if keep[gvk.Kind] {
+				filtered = append(filtered, gvk)
+			}
+		}
+		c.kinds = filtered
+	}
+}
+
+// NewResourceCounter returns a counter of the managed resource kinds in
+// scheme that belong to the provider. It counts every minute.
+func NewResourceCounter(kube client.Reader, scheme *runtime.Scheme, o ...CounterOption) *ResourceCounter {
+	c := &ResourceCounter{
+		kube:     kube,
+		kinds:    managedKinds(scheme),
+		scheme:   scheme,
+		log:      logging.NewNopLogger(),
+		interval: time.Minute,
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// managedKinds returns the provider's managed resource kinds in scheme.
+func managedKinds(scheme *runtime.Scheme) []schema.GroupVersionKind {
+	var kinds []schema.GroupVersionKind
+	for gvk := range scheme.AllKnownTypes() {
+		if !strings.HasSuffix(gvk.Group, groupSuffix) || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		o, err := scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+		if _, ok := o.(resource.Managed); ok {
+			kinds = append(kinds, gvk)
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	return kinds
+}
+
+// NeedLeaderElection is true, so that only the replica whose controllers are
+// running exports the counts and they are not summed twice.
+func (c *ResourceCounter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start counts every interval until ctx is done.
+func (c *ResourceCounter) Start(ctx context.Context) error {
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+	for {
+		if err := c.Count(ctx); err != nil {
+			c.log.Info("Cannot count managed resources", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// A countKey identifies one series of ManagedResources.
+type countKey struct {
+	kind, condition, status string
+}
+
+// Count lists every managed resource kind and replaces ManagedResources with
+// the counts.
+func (c *ResourceCounter) Count(ctx context.Context) error {
+	counts := map[countKey]float64{}
+	for _, gvk := range c.kinds {
+		o, err := c.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err != nil {
+			return errors.Wrapf(err, "cannot make a list of %s", gvk.Kind)
+		}
+		l, ok := o.(client.ObjectList)
+		if !ok {
+			return errors.Errorf("%s is not a list", gvk.Kind+"List")
+		}
+		if err := c.kube.List(ctx, l); err != nil {
+			return errors.Wrapf(err, "cannot list %s", gvk.Kind)
+		}
+		items, err := meta.ExtractList(l)
+		if err != nil {
+			return errors.Wrapf(err, "cannot read list of %s", gvk.Kind)
+		}
+		for _, ct := range countedConditions {
+			// Report zero for each status so that the series do not vanish.
+			for _, s := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown} {
+				counts[countKey{gvk.Kind, string(ct), string(s)}] += 0
+			}
+		}
+		for _, item := range items {
+			mg, ok := item.(resource.Managed)
+			if !ok {
+				continue
+			}
+			for _, ct := range countedConditions {
+				counts[countKey{gvk.Kind, string(ct), string(mg.GetCondition(ct).Status)}]++
+			}
+		}
+	}
+
+	for k, n := range counts {
+		ManagedResources.WithLabelValues(k.kind, k.condition, k.status).Set(n)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	apisv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func project(name string, c ...xpv1.Condition) *projectv1beta1.Project {
+	p := &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "harbor"}}
+	p.SetConditions(c...)
+	return p
+}
+
+func TestResourceCounter(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := projectv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	// ProviderConfigs are not managed resources and are not counted.
+	if err := apisv1beta1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		project("a", xpv1.Available(), xpv1.ReconcileSuccess()),
+		project("b", xpv1.Available(), xpv1.ReconcileError(errors.New("boom"))),
+		project("c"),
+	).Build()
+
+	c := NewResourceCounter(kube, s)
+	managed := map[string]bool{
+		projectv1beta1.ProjectKind:          true,
+		projectv1beta1.ProjectAuditLogKind:  true,
+		projectv1beta1.ImmutableTagRuleKind: true,
+		projectv1beta1.ProjectSetKind:       true,
+	}
+	for _, gvk := range c.kinds {
+		if !managed[gvk.Kind] {
+			t.Errorf("counting %s, which is not a managed resource", gvk)
+		}
+	}
+	if len(c.kinds) != len(managed) {
+		t.Errorf("counting %v, want the kinds %v", c.kinds, managed)
+	}
+	if err := c.Count(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[[2]string]float64{
+		{"Ready", "True"}:     2,
+		{"Ready", "False"}:    0,
+		{"Ready", "Unknown"}:  1,
+		{"Synced", "True"}:    1,
+		{"Synced", "False"}:   1,
+		{"Synced", "Unknown"}: 1,
+	}
+	for k, n := range want {
+		if got := testutil.ToFloat64(ManagedResources.WithLabelValues("Project", k[0], k[1])); got != n {
+			t.Errorf("harbor_managed_resources{condition=%q,status=%q} = %v, want %v", k[0], k[1], got, n)
+		}
+	}
+}
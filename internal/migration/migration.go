@@ -0,0 +1,193 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package migration rewrites the provider's custom resources in the storage
+// version of their CRD. The API server refuses CRD updates that drop a
+// version still listed in status.storedVersions, so objects written by an
+// older release must be re-stored before that version can be removed.
+package migration
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/pkg/errors"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultGroupSuffix matches the API groups served by this provider.
+const DefaultGroupSuffix = "harbor.m.crossplane.io"
+
+// listPageSize is how many objects are rewritten per list call.
+const listPageSize = 100
+
+const (
+	errListCRDs      = "cannot list CustomResourceDefinitions"
+	errListObjects   = "cannot list %s"
+	errUpdateObject  = "cannot rewrite %s %s"
+	errUpdateCRD     = "cannot update stored versions of %s"
+	errNoStorageKind = "CustomResourceDefinition %s has no storage version"
+)
+
+// A Result reports the migration of one CRD.
+type Result struct {
+	// CRD is the name of the CustomResourceDefinition.
+	CRD string
+	// StorageVersion is the version objects were rewritten in.
+	StorageVersion string
+	// StaleVersions are the stored versions that were removed.
+	StaleVersions []string
+	// Objects is the number of objects rewritten.
+	Objects int
+}
+
+// A Migrator rewrites stored objects whose CRD lists stored versions other
+// than its storage version.
+type Migrator struct {
+	kube        client.Client
+	log         logging.Logger
+	groupSuffix string
+}
+
+// An Option configures a Migrator.
+type Option func(*Migrator)
+
+// WithLogger sets the logger.
+func WithLogger(l logging.Logger) Option {
+	return func(m *Migrator) { m.log = l }
+}
+
+// WithGroupSuffix sets which API groups are migrated.
+func WithGroupSuffix(s string) Option {
+	return func(m *Migrator) { m.groupSuffix = s }
+}
+
+// New returns a Migrator. kube should read from the API server rather than a
+// cache, and must be able to list and update CustomResourceDefinitions and
+// their status.
+func New(kube client.Client, o ...Option) *Migrator {
+	m := &Migrator{
+		kube:        kube,
+		log:         logging.NewNopLogger(),
+		groupSuffix: DefaultGroupSuffix,
+	}
+	for _, fn := range o {
+		fn(m)
+	}
+	return m
+}
+
+// NeedLeaderElection ensures only one replica migrates at a time.
+func (m *Migrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start migrates once. A failed migration is logged rather than stopping the
+// provider; it is retried the next time the provider starts.
+func (m *Migrator) Start(ctx context.Context) error {
+	results, err := m.Migrate(ctx)
+	for _, r := range results {
+		m.log.Info("Migrated stored versions", "crd", r.CRD, "storageVersion", r.StorageVersion, "removed", r.StaleVersions, "objects", r.Objects)
+	}
+	if err != nil {
+		m.log.Info("Storage version migration failed", "error", err)
+	}
+	return nil
+}
+
+// Migrate rewrites the objects of every provider CRD that lists a stored
+// version other than its storage version, then records the storage version
+// as the only stored version. It returns the CRDs that were migrated.
+func (m *Migrator) Migrate(ctx context.Context) ([]Result, error) {
+	crds := &extv1.CustomResourceDefinitionList{}
+	if err := m.kube.List(ctx, crds); err != nil {
+		return nil, errors.Wrap(err, errListCRDs)
+	}
+
+	var results []Result
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if !m.owns(crd.Spec.Group) {
+			continue
+		}
+		r, err := m.migrateCRD(ctx, crd)
+		if err != nil {
+			return results, err
+		}
+		if r != nil {
+			results = append(results, *r)
+		}
+	}
+	return results, nil
+}
+
+func (m *Migrator) owns(group string) bool {
+	return group == m.groupSuffix || strings.HasSuffix(group, "."+m.groupSuffix)
+}
+
+func (m *Migrator) migrateCRD(ctx context.Context, crd *extv1.CustomResourceDefinition) (*Result, error) {
+	storage := storageVersion(crd)
+	if storage == "" {
+		return nil, errors.Errorf(errNoStorageKind, crd.GetName())
+	}
+	var stale []string
+	for _, v := range crd.Status.StoredVersions {
+		if v != storage {
+			stale = append(stale, v)
+		}
+	}
+	if len(stale) == 0 {
+		return nil, nil
+	}
+
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: storage, Kind: crd.Spec.Names.ListKind}
+	if gvk.Kind == "" {
+		gvk.Kind = crd.Spec.Names.Kind + "List"
+	}
+	r := &Result{CRD: crd.GetName(), StorageVersion: storage, StaleVersions: stale}
+	for cont := ""; ; {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk)
+		if err := m.kube.List(ctx, l, client.Limit(listPageSize), client.Continue(cont)); err != nil {
+			return nil, errors.Wrapf(err, errListObjects, crd.GetName())
+		}
+		for j := range l.Items {
+			o := &l.Items[j]
+			// An unchanged update makes the API server store the object
+			// again, encoded in the storage version. A conflict or a deleted
+			// object means it was written or removed since it was listed.
+			err := m.kube.Update(ctx, o)
+			if kerrors.IsConflict(err) || kerrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, errUpdateObject, crd.Spec.Names.Kind, client.ObjectKeyFromObject(o))
+			}
+			r.Objects++
+		}
+		if cont = l.GetContinue(); cont == "" {
+			break
+		}
+	}
+
+	crd.Status.StoredVersions = []string{storage}
+	if err := m.kube.Status().Update(ctx, crd); err != nil {
+		return nil, errors.Wrapf(err, errUpdateCRD, crd.GetName())
+	}
+	return r, nil
+}
+
+func storageVersion(crd *extv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package migration
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	projectv1beta1 "github.com/rossigee/provider-harbor/apis/project/v1beta1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func crd(name, group, kind string, stored ...string) *extv1.CustomResourceDefinition {
+	return &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: extv1.CustomResourceDefinitionNames{Kind: kind, ListKind: kind + "List"},
+			Versions: []extv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+		Status: extv1.CustomResourceDefinitionStatus{StoredVersions: stored},
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{extv1.AddToScheme, projectv1beta1.SchemeBuilder.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var objs []client.Object
+	for _, n := range []string{"a", "b", "c"} {
+		objs = append(objs, &projectv1beta1.Project{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: n}})
+	}
+	objs = append(objs,
+		crd("projects.project.harbor.m.crossplane.io", "project.harbor.m.crossplane.io", "Project", "v1alpha1", "v1beta1"),
+		crd("robots.robot.harbor.m.crossplane.io", "robot.harbor.m.crossplane.io", "Robot", "v1beta1"),
+		crd("widgets.example.org", "example.org", "Widget", "v1alpha1", "v1beta1"),
+	)
+
+	var rewritten []string
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).
+		WithStatusSubresource(&extv1.CustomResourceDefinition{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, o client.Object, opts ...client.UpdateOption) error {
+				rewritten = append(rewritten, o.GetObjectKind().GroupVersionKind().Kind+"/"+o.GetName())
+				return c.Update(ctx, o, opts...)
+			},
+		}).Build()
+
+	got, err := New(kube).Migrate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Result{{
+		CRD:            "projects.project.harbor.m.crossplane.io",
+		StorageVersion: "v1beta1",
+		StaleVersions:  []string{"v1alpha1"},
+		Objects:        3,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Migrate() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(rewritten, []string{"Project/a", "Project/b", "Project/c"}) {
+		t.Errorf("rewritten objects = %v", rewritten)
+	}
+
+	for name, stored := range map[string][]string{
+		"projects.project.harbor.m.crossplane.io": {"v1beta1"},
+		"widgets.example.org":                     {"v1alpha1", "v1beta1"},
+	} {
+		c := &extv1.CustomResourceDefinition{}
+		if err := kube.Get(context.Background(), client.ObjectKey{Name: name}, c); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(c.Status.StoredVersions, stored) {
+			t.Errorf("%s stored versions = %v, want %v", name, c.Status.StoredVersions, stored)
+		}
+	}
+
+	// A second run has nothing to do.
+	rewritten = nil
+	if got, err := New(kube).Migrate(context.Background()); err != nil || len(got) != 0 || len(rewritten) != 0 {
+		t.Errorf("second Migrate() = %+v, %v, rewrote %v", got, err, rewritten)
+	}
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package sweeper finds Harbor objects that were created by this provider but
+// no longer have a managed resource, as happens after an etcd restore or when
+// a managed resource's finalizer is removed by hand.
+package sweeper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/pkg/errors"
+	robotv1beta1 "github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	scannerv1beta1 "github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	webhookv1beta1 "github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ReasonOrphanFound is the event reason for an orphan that was left in place.
+	ReasonOrphanFound event.Reason = "OrphanedExternalResource"
+	// ReasonOrphanDeleted is the event reason for an orphan that was deleted.
+	ReasonOrphanDeleted event.Reason = "DeletedOrphanedExternalResource"
+
+	// DefaultMinAge is how old a Harbor object must be before it can be
+	// reported. Newer objects may belong to a managed resource that was
+	// created after the sweep listed managed resources.
+	DefaultMinAge = 10 * time.Minute
+
+	errListProviderConfigs = "cannot list ProviderConfigs"
+	errListRobots          = "cannot list Robot managed resources"
+	errListWebhooks        = "cannot list Webhook managed resources"
+	errListScanners        = "cannot list ScannerRegistration managed resources"
+)
+
+// An Orphan is a Harbor object marked as managed by the provider for which no
+// managed resource exists.
+type Orphan struct {
+	ProviderConfig string
+	Kind           string
+	ID             string
+	Name           string
+	// ProjectID is set for project-scoped objects.
+	ProjectID string
+}
+
+func (o Orphan) String() string {
+	if o.ProjectID != "" {
+		return fmt.Sprintf("%s %q (id %s, project %s)", o.Kind, o.Name, o.ID, o.ProjectID)
+	}
+	return fmt.Sprintf("%s %q (id %s)", o.Kind, o.Name, o.ID)
+}
+
+// NewClientFn returns a Harbor client for the named ProviderConfig.
+type NewClientFn func(ctx context.Context, kube client.Client, providerConfig string) (harborclients.HarborClienter, error)
+
+// A Sweeper periodically looks for orphaned Harbor objects.
+type Sweeper struct {
+	kube      client.Client
+	newClient NewClientFn
+	record    event.Recorder
+	log       logging.Logger
+	interval  time.Duration
+	minAge    time.Duration
+	delete    bool
+	now       func() time.Time
+}
+
+// An Option configures a Sweeper.
+type Option func(*Sweeper)
+
+// WithLogger sets the logger.
+func WithLogger(l logging.Logger) Option {
+	return func(s *Sweeper) { s.log = l }
+}
+
+// WithRecorder sets the recorder used to emit events on ProviderConfigs.
+func WithRecorder(r event.Recorder) Option {
+	return func(s *Sweeper) { s.record = r }
+}
+
+// WithInterval sets how often Start sweeps.
+func WithInterval(d time.Duration) Option {
+	return func(s *Sweeper) { s.interval = d }
+}
+
+// WithMinAge sets how old an object must be before it is considered.
+func WithMinAge(d time.Duration) Option {
+	return func(s *Sweeper) { s.minAge = d }
+}
+
+// WithDelete makes the sweeper delete orphans instead of only reporting them.
+func WithDelete(d bool) Option {
+	return func(s *Sweeper) { s.delete = d }
+}
+
+// WithNewClientFn overrides how Harbor clients are built.
+func WithNewClientFn(fn NewClientFn) Option {
+	return func(s *Sweeper) { s.newClient = fn }
+}
+
+// New returns a Sweeper that reports orphans hourly.
+func New(kube client.Client, o ...Option) *Sweeper {
+	s := &Sweeper{
+		kube:      kube,
+		newClient: harborclients.NewHarborClientForProviderConfig,
+		record:    event.NewNopRecorder(),
+		log:       logging.NewNopLogger(),
+		interval:  time.Hour,
+		minAge:    DefaultMinAge,
+		now:       time.Now,
+	}
+	for _, fn := range o {
+		fn(s)
+	}
+	return s
+}
+
+// NeedLeaderElection ensures only one replica sweeps at a time.
+func (s *Sweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// Start sweeps every interval until ctx is done. Sweep failures are logged
+// and retried at the next interval.
+func (s *Sweeper) Start(ctx context.Context) error {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+			if _, err := s.Sweep(ctx); err != nil {
+				s.log.Info("Orphan sweep failed", "error", err)
+			}
+		}
+	}
+}
+
+// Sweep checks every ProviderConfig once and returns the orphans found.
+func (s *Sweeper) Sweep(ctx context.Context) ([]Orphan, error) {
+	pcs := &providerconfigv1beta1.ProviderConfigList{}
+	if err := s.kube.List(ctx, pcs); err != nil {
+		return nil, errors.Wrap(err, errListProviderConfigs)
+	}
+	robots := &robotv1beta1.RobotList{}
+	if err := s.kube.List(ctx, robots); err != nil {
+		return nil, errors.Wrap(err, errListRobots)
+	}
+	webhooks := &webhookv1beta1.WebhookList{}
+	if err := s.kube.List(ctx, webhooks); err != nil {
+		return nil, errors.Wrap(err, errListWebhooks)
+	}
+	scanners := &scannerv1beta1.ScannerRegistrationList{}
+	if err := s.kube.List(ctx, scanners); err != nil {
+		return nil, errors.Wrap(err, errListScanners)
+	}
+
+	var all []Orphan
+	for i := range pcs.Items {
+		pc := &pcs.Items[i]
+		svc, err := s.newClient(ctx, s.kube, pc.GetName())
+		if err == nil {
+			var orphans []Orphan
+			orphans, err = s.sweepProviderConfig(ctx, svc, pc.GetName(), robots, webhooks, scanners)
+			for _, o := range orphans {
+				s.handle(ctx, svc, pc, o)
+			}
+			all = append(all, orphans...)
+		}
+		if err != nil {
+			// One unreachable Harbor should not stop the others being swept.
+			s.log.Info("Cannot sweep ProviderConfig", "providerConfig", pc.GetName(), "error", err)
+		}
+	}
+	return all, nil
+}
+
+func (s *Sweeper) handle(ctx context.Context, svc harborclients.HarborClienter, pc *providerconfigv1beta1.ProviderConfig, o Orphan) {
+	if !s.delete {
+		s.log.Info("Found orphaned Harbor object", "providerConfig", o.ProviderConfig, "orphan", o.String())
+		s.record.Event(pc, event.Warning(ReasonOrphanFound, errors.Errorf("%s is marked as managed by the provider but has no managed resource", o)))
+		return
+	}
+
+	if err := deleteOrphan(ctx, svc, o); err != nil {
+		s.log.Info("Cannot delete orphaned Harbor object", "providerConfig", o.ProviderConfig, "orphan", o.String(), "error", err)
+		s.record.Event(pc, event.Warning(ReasonOrphanFound, errors.Wrapf(err, "cannot delete orphaned %s", o)))
+		return
+	}
+	s.log.Info("Deleted orphaned Harbor object", "providerConfig", o.ProviderConfig, "orphan", o.String())
+	s.record.Event(pc, event.Normal(ReasonOrphanDeleted, fmt.Sprintf("Deleted orphaned %s", o)))
+}
+
+func deleteOrphan(ctx context.Context, svc harborclients.HarborClienter, o Orphan) error {
+	switch o.Kind {
+	case robotv1beta1.RobotKind:
+		return svc.DeleteRobot(ctx, o.ID)
+	case webhookv1beta1.WebhookKind:
+		return svc.DeleteWebhook(ctx, o.ProjectID, o.ID)
+	}
+	return errors.Errorf("cannot delete unknown kind %s", o.Kind)
+}
+
+func (s *Sweeper) sweepProviderConfig(ctx context.Context, svc harborclients.HarborClienter, pc string, robots *robotv1beta1.RobotList, webhooks *webhookv1beta1.WebhookList, scanners *scannerv1beta1.ScannerRegistrationList) ([]Orphan, error) {
+	var orphans []Orphan
+
+	observed, err := svc.ListRobots(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list Harbor robot accounts")
+	}
+	for _, r := range observed {
+		if !r.ManagedByProvider || !s.oldEnough(r.CreationTime) || robotClaimed(r.Name, pc, robots) || scannerRobotClaimed(r, pc, scanners) {
+			continue
+		}
+		orphans = append(orphans, Orphan{ProviderConfig: pc, Kind: robotv1beta1.RobotKind, ID: r.ID, Name: r.Name})
+	}
+
+	// Harbor has no cross-project webhook listing, so only projects that
+	// still have Webhook managed resources are checked.
+	for _, project := range webhookProjects(pc, webhooks) {
+		observed, err := svc.ListWebhooks(ctx, project)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot list Harbor webhook policies in project %s", project)
+		}
+		for _, w := range observed {
+			if !w.ManagedByProvider || !s.oldEnough(w.CreationTime) || webhookClaimed(w, project, pc, webhooks) {
+				continue
+			}
+			orphans = append(orphans, Orphan{ProviderConfig: pc, Kind: webhookv1beta1.WebhookKind, ID: w.ID, Name: w.Name, ProjectID: project})
+		}
+	}
+
+	return orphans, nil
+}
+
+func (s *Sweeper) oldEnough(created time.Time) bool {
+	return !created.IsZero() && s.now().Sub(created) >= s.minAge
+}
+
+func providerConfigName(ref *xpv1.ProviderConfigReference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+// robotClaimed reports whether a Robot using the ProviderConfig matches the
+// Harbor robot, using the same rules as the Robot controller's Observe.
+func robotClaimed(fullName, pc string, robots *robotv1beta1.RobotList) bool {
+	for i := range robots.Items {
+		cr := &robots.Items[i]
+		if providerConfigName(cr.Spec.ProviderConfigReference) != pc {
+			continue
+		}
+		if ctrlutil.GetExternalName(cr) == fullName {
+			return true
+		}
+		name := cr.Spec.ForProvider.Name
+		if fullName == "robot$"+name || (strings.HasPrefix(fullName, "robot$") && strings.HasSuffix(fullName, "+"+name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// scannerRobotClaimed reports whether the robot is the credential robot of a
+// ScannerRegistration using the ProviderConfig.
+func scannerRobotClaimed(r *harborclients.RobotStatus, pc string, scanners *scannerv1beta1.ScannerRegistrationList) bool {
+	for i := range scanners.Items {
+		cr := &scanners.Items[i]
+		if providerConfigName(cr.Spec.ProviderConfigReference) != pc {
+			continue
+		}
+		if id := cr.Status.AtProvider.CredentialRobotID; id != nil && *id == r.ID {
+			return true
+		}
+		if cr.Spec.ForProvider.CredentialRobot == nil {
+			continue
+		}
+		name := "scanner-" + cr.Spec.ForProvider.Name
+		if n := cr.Spec.ForProvider.CredentialRobot.Name; n != nil && *n != "" {
+			name = *n
+		}
+		if r.Name == "robot$"+name {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookClaimed(w *harborclients.WebhookStatus, project, pc string, webhooks *webhookv1beta1.WebhookList) bool {
+	for i := range webhooks.Items {
+		cr := &webhooks.Items[i]
+		if providerConfigName(cr.Spec.ProviderConfigReference) != pc || cr.Spec.ForProvider.ProjectID != project {
+			continue
+		}
+		if ctrlutil.GetExternalName(cr) == w.ID || cr.Spec.ForProvider.Name == w.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func webhookProjects(pc string, webhooks *webhookv1beta1.WebhookList) []string {
+	seen := map[string]bool{}
+	var projects []string
+	for i := range webhooks.Items {
+		cr := &webhooks.Items[i]
+		p := cr.Spec.ForProvider.ProjectID
+		if providerConfigName(cr.Spec.ProviderConfigReference) != pc || p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		projects = append(projects, p)
+	}
+	return projects
+}
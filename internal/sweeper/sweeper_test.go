@@ -0,0 +1,187 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package sweeper
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane/apis/v2/core/v2"
+	"github.com/rossigee/provider-harbor/apis"
+	robotv1beta1 "github.com/rossigee/provider-harbor/apis/robot/v1beta1"
+	scannerv1beta1 "github.com/rossigee/provider-harbor/apis/scanner/v1beta1"
+	providerconfigv1beta1 "github.com/rossigee/provider-harbor/apis/v1beta1"
+	webhookv1beta1 "github.com/rossigee/provider-harbor/apis/webhook/v1beta1"
+	harborclients "github.com/rossigee/provider-harbor/internal/clients"
+	ctrlutil "github.com/rossigee/provider-harbor/internal/controller"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func pcRef(name string) *xpv1.ProviderConfigReference {
+	return &xpv1.ProviderConfigReference{Name: name}
+}
+
+func newKube(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
+func TestSweep(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-time.Hour)
+
+	pc := &providerconfigv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+
+	adopted := &robotv1beta1.Robot{ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "harbor"}}
+	adopted.Spec.ForProvider.Name = "ci"
+	adopted.Spec.ProviderConfigReference = pcRef("default")
+
+	renamed := &robotv1beta1.Robot{ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "harbor"}}
+	renamed.Spec.ForProvider.Name = "deploy"
+	renamed.Spec.ProviderConfigReference = pcRef("default")
+	ctrlutil.SetExternalName(renamed, "robot$library+deployer")
+
+	hook := &webhookv1beta1.Webhook{ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "harbor"}}
+	hook.Spec.ForProvider.Name = "slack"
+	hook.Spec.ForProvider.ProjectID = "1"
+	hook.Spec.ProviderConfigReference = pcRef("default")
+
+	scanner := &scannerv1beta1.ScannerRegistration{ObjectMeta: metav1.ObjectMeta{Name: "trivy", Namespace: "harbor"}}
+	scanner.Spec.ForProvider.Name = "trivy"
+	scanner.Spec.ForProvider.CredentialRobot = &scannerv1beta1.ScannerCredentialRobot{}
+	scanner.Spec.ProviderConfigReference = pcRef("default")
+
+	robots := []*harborclients.RobotStatus{
+		{ID: "1", Name: "robot$ci", ManagedByProvider: true, CreationTime: old},
+		{ID: "2", Name: "robot$library+deployer", ManagedByProvider: true, CreationTime: old},
+		{ID: "3", Name: "robot$gone", ManagedByProvider: true, CreationTime: old},
+		{ID: "4", Name: "robot$by-hand", CreationTime: old},
+		{ID: "5", Name: "robot$just-created", ManagedByProvider: true, CreationTime: now},
+		{ID: "8", Name: "robot$scanner-trivy", ManagedByProvider: true, CreationTime: old},
+	}
+	webhooks := []*harborclients.WebhookStatus{
+		{ID: "6", ProjectID: "1", Name: "slack", ManagedByProvider: true, CreationTime: old},
+		{ID: "7", ProjectID: "1", Name: "teams", ManagedByProvider: true, CreationTime: old},
+	}
+
+	cases := map[string]struct {
+		delete      bool
+		wantOrphans []string
+		wantDeleted []string
+	}{
+		"ReportOnly": {
+			wantOrphans: []string{"3", "7"},
+		},
+		"Delete": {
+			delete:      true,
+			wantOrphans: []string{"3", "7"},
+			wantDeleted: []string{"3", "7"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			svc := &harborclients.MockHarborClient{
+				ListRobotsFunc: func(_ context.Context, projectID *string) ([]*harborclients.RobotStatus, error) {
+					if projectID != nil {
+						t.Errorf("ListRobots() called with project %q, want all robots", *projectID)
+					}
+					return robots, nil
+				},
+				ListWebhooksFunc: func(_ context.Context, projectID string) ([]*harborclients.WebhookStatus, error) {
+					if projectID != "1" {
+						t.Errorf("ListWebhooks() called for project %q", projectID)
+					}
+					return webhooks, nil
+				},
+				DeleteRobotFunc: func(_ context.Context, id string) error {
+					deleted = append(deleted, id)
+					return nil
+				},
+				DeleteWebhookFunc: func(_ context.Context, _, id string) error {
+					deleted = append(deleted, id)
+					return nil
+				},
+			}
+
+			s := New(newKube(t, pc, adopted, renamed, hook, scanner),
+				WithDelete(tc.delete),
+				WithNewClientFn(func(_ context.Context, _ client.Client, pc string) (harborclients.HarborClienter, error) {
+					if pc != "default" {
+						t.Errorf("client requested for ProviderConfig %q", pc)
+					}
+					return svc, nil
+				}))
+			s.now = func() time.Time { return now }
+
+			orphans, err := s.Sweep(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, o := range orphans {
+				ids = append(ids, o.ID)
+			}
+			sort.Strings(ids)
+			sort.Strings(deleted)
+			if !equal(ids, tc.wantOrphans) {
+				t.Errorf("orphans = %v, want %v", ids, tc.wantOrphans)
+			}
+			if !equal(deleted, tc.wantDeleted) {
+				t.Errorf("deleted = %v, want %v", deleted, tc.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestSweepIgnoresOtherProviderConfigs(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	a := &providerconfigv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "a"}}
+	b := &providerconfigv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "b"}}
+
+	// A Robot for Harbor "a" must not claim a same-named robot in Harbor "b".
+	cr := &robotv1beta1.Robot{ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "harbor"}}
+	cr.Spec.ForProvider.Name = "ci"
+	cr.Spec.ProviderConfigReference = pcRef("a")
+
+	svc := &harborclients.MockHarborClient{
+		ListRobotsFunc: func(context.Context, *string) ([]*harborclients.RobotStatus, error) {
+			return []*harborclients.RobotStatus{{ID: "1", Name: "robot$ci", ManagedByProvider: true, CreationTime: old}}, nil
+		},
+	}
+
+	s := New(newKube(t, a, b, cr), WithNewClientFn(func(context.Context, client.Client, string) (harborclients.HarborClienter, error) {
+		return svc, nil
+	}))
+	orphans, err := s.Sweep(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 1 || orphans[0].ProviderConfig != "b" {
+		t.Errorf("orphans = %+v, want robot$ci in b only", orphans)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
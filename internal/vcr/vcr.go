@@ -0,0 +1,259 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package vcr records the provider's Harbor API requests and responses to
+// cassettes, and replays cassettes in place of Harbor. Recording a real
+// Harbor once makes its error responses, such as conflicts and failed
+// preconditions, available to unit tests without a Harbor to produce them.
+package vcr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Redacted replaces the values of secret fields in recorded bodies.
+const Redacted = "REDACTED"
+
+// secretField matches the names of JSON fields whose values are not
+// recorded.
+var secretField = regexp.MustCompile(`(?i)(password|secret|token|credential|access_key|authorization)`)
+
+// An Interaction is one request to Harbor and its response. Hosts and
+// headers other than Content-Type are not recorded.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// A Request is a recorded request.
+type Request struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// A Response is a recorded response.
+type Response struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"contentType,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// Load reads a cassette: a file with one JSON Interaction per line. Blank
+// lines and lines starting with # are skipped.
+func Load(path string) ([]Interaction, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot open cassette")
+	}
+	defer func() { _ = f.Close() }()
+
+	var out []Interaction
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; s.Scan(); n++ {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		i := Interaction{}
+		if err := json.Unmarshal(line, &i); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse line %d of %s", n, path)
+		}
+		out = append(out, i)
+	}
+	return out, errors.Wrapf(s.Err(), "cannot read %s", path)
+}
+
+// A Recorder appends the requests of the RoundTrippers it wraps, and their
+// responses, to a cassette, with secrets redacted. It is safe for concurrent
+// use.
+type Recorder struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewRecorder returns a Recorder that writes interactions to out.
+func NewRecorder(out io.Writer) *Recorder {
+	return &Recorder{out: out}
+}
+
+// Wrap returns a RoundTripper that sends requests with next and records
+// them, so that one cassette can cover every Harbor client.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return r.roundTrip(next, req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func (r *Recorder) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	i := Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.RawQuery,
+			Body:   sanitize(reqBody),
+		},
+		Response: Response{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        sanitize(respBody),
+		},
+	}
+	line, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.out.Write(append(line, '\n')); err != nil {
+		return nil, errors.Wrap(err, "cannot record Harbor interaction")
+	}
+	return resp, nil
+}
+
+// sanitize returns body as JSON with the values of secret fields redacted.
+// Bodies that are not JSON are recorded as JSON strings.
+func sanitize(body []byte) json.RawMessage {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		s, _ := json.Marshal(string(body))
+		return s
+	}
+	out, err := json.Marshal(redact(v))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+func redact(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, tv := range t {
+			if _, isString := tv.(string); isString && secretField.MatchString(k) {
+				t[k] = Redacted
+				continue
+			}
+			t[k] = redact(tv)
+		}
+	case []any:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+	}
+	return v
+}
+
+// A Replayer is a RoundTripper that answers requests from a cassette instead
+// of Harbor. Requests must arrive in the recorded order; a request whose
+// method, path or, when one was recorded, query does not match the next
+// interaction fails. It is safe for concurrent use.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	next         int
+}
+
+// NewReplayer returns a Replayer of interactions.
+func NewReplayer(interactions []Interaction) *Replayer {
+	return &Replayer{interactions: interactions}
+}
+
+// RoundTrip answers req with the next recorded response.
+func (p *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	if p.next >= len(p.interactions) {
+		return nil, errors.Errorf("unexpected request %s %s: the cassette has no more interactions", req.Method, req.URL.Path)
+	}
+	i := p.interactions[p.next]
+	if i.Request.Method != req.Method || i.Request.Path != req.URL.Path {
+		return nil, errors.Errorf("unexpected request %s %s: the cassette expects %s %s", req.Method, req.URL.Path, i.Request.Method, i.Request.Path)
+	}
+	if i.Request.Query != "" && i.Request.Query != req.URL.RawQuery {
+		return nil, errors.Errorf("unexpected query %q of %s %s: the cassette expects %q", req.URL.RawQuery, req.Method, req.URL.Path, i.Request.Query)
+	}
+	p.next++
+
+	h := http.Header{}
+	if i.Response.ContentType != "" {
+		h.Set("Content-Type", i.Response.ContentType)
+	}
+	body := []byte(i.Response.Body)
+	// Bodies that were not JSON were recorded as JSON strings.
+	var text string
+	if !strings.Contains(i.Response.ContentType, "json") && json.Unmarshal(body, &text) == nil {
+		body = []byte(text)
+	}
+	return &http.Response{
+		Status:     http.StatusText(i.Response.Status),
+		StatusCode: i.Response.Status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     h,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// Remaining returns the requests the cassette expects that have not been
+// made, as "METHOD path".
+func (p *Replayer) Remaining() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []string
+	for _, i := range p.interactions[p.next:] {
+		out = append(out, strings.TrimSpace(i.Request.Method+" "+i.Request.Path))
+	}
+	return out
+}
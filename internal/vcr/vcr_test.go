@@ -0,0 +1,128 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package vcr
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2.0/ping" {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("Pong"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"robot$ci","secret":"s3cr3t","id":4}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	cassette := &bytes.Buffer{}
+	hc := &http.Client{Transport: NewRecorder(cassette).Wrap(http.DefaultTransport)}
+	body := `{"name":"ci","permissions":[{"access":[{"action":"pull"}]}],"secret":"hunter2"}`
+	resp, err := hc.Post(srv.URL+"/api/v2.0/robots?x=1", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if !strings.Contains(string(got), "s3cr3t") {
+		t.Errorf("the recorder changed the response the client read: %s", got)
+	}
+	resp, err = hc.Get(srv.URL + "/api/v2.0/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	for _, secret := range []string{"hunter2", "s3cr3t"} {
+		if strings.Contains(cassette.String(), secret) {
+			t.Errorf("the cassette holds %q:\n%s", secret, cassette)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "robots.jsonl")
+	if err := os.WriteFile(path, append([]byte("# robots\n\n"), cassette.Bytes()...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	interactions, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interactions) != 2 {
+		t.Fatalf("Load() returned %d interactions, want 2", len(interactions))
+	}
+	if q := interactions[0].Request.Query; q != "x=1" {
+		t.Errorf("recorded query %q, want x=1", q)
+	}
+
+	p := NewReplayer(interactions)
+	hc = &http.Client{Transport: p}
+	resp, err = hc.Post("https://harbor.example.com/api/v2.0/robots?x=1", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || !strings.Contains(string(got), `"secret":"REDACTED"`) {
+		t.Errorf("replayed %d %s", resp.StatusCode, got)
+	}
+	if r := p.Remaining(); len(r) != 1 || r[0] != "GET /api/v2.0/ping" {
+		t.Errorf("Remaining() = %v", r)
+	}
+	resp, err = hc.Get("https://harbor.example.com/api/v2.0/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(got) != "Pong" {
+		t.Errorf("replayed text body %q, want Pong", got)
+	}
+	if r := p.Remaining(); len(r) != 0 {
+		t.Errorf("Remaining() = %v, want none", r)
+	}
+}
+
+func TestReplayerRejectsUnexpectedRequests(t *testing.T) {
+	cases := map[string]struct {
+		method string
+		url    string
+	}{
+		"Method": {method: http.MethodDelete, url: "https://harbor.example.com/api/v2.0/labels/1"},
+		"Path":   {method: http.MethodGet, url: "https://harbor.example.com/api/v2.0/labels/2"},
+		"Query":  {method: http.MethodGet, url: "https://harbor.example.com/api/v2.0/labels/1?scope=p"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewReplayer([]Interaction{{
+				Request:  Request{Method: http.MethodGet, Path: "/api/v2.0/labels/1", Query: "scope=g"},
+				Response: Response{Status: http.StatusOK},
+			}})
+			req, _ := http.NewRequest(tc.method, tc.url, nil)
+			if _, err := p.RoundTrip(req); err == nil {
+				t.Error("RoundTrip() succeeded, want an error")
+			}
+			if _, err := p.RoundTrip(req); err == nil {
+				t.Error("second RoundTrip() succeeded, want an error")
+			}
+		})
+	}
+
+	p := NewReplayer(nil)
+	req, _ := http.NewRequest(http.MethodGet, "https://harbor.example.com/api/v2.0/ping", nil)
+	if _, err := p.RoundTrip(req); err == nil {
+		t.Error("RoundTrip() of an empty cassette succeeded, want an error")
+	}
+}
@@ -0,0 +1,94 @@
+#!/usr/bin/env bash
+# Shared setup for the kind-based e2e scripts; source it after setting
+# KIND_CLUSTER, IMAGE, PROVIDER and HARBOR_PASSWORD.
+
+KCTX="kind-${KIND_CLUSTER}"
+ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+
+log() { printf '\033[36m==>\033[0m %s\n' "$*"; }
+k() { kubectl --context "$KCTX" "$@"; }
+
+require() { for c in "$@"; do command -v "$c" >/dev/null || { echo "missing: $c"; exit 1; }; done; }
+
+# setup_cluster creates the kind cluster and installs Crossplane and an
+# in-cluster Harbor without TLS or persistence.
+setup_cluster() {
+  log "ensure kind cluster ${KIND_CLUSTER}"
+  kind get clusters 2>/dev/null | grep -qx "$KIND_CLUSTER" || kind create cluster --name "$KIND_CLUSTER"
+
+  log "install Crossplane"
+  helm repo add crossplane-stable https://charts.crossplane.io/stable >/dev/null 2>&1 || true
+  helm repo update crossplane-stable >/dev/null 2>&1
+  helm --kube-context "$KCTX" upgrade --install crossplane crossplane-stable/crossplane \
+    -n crossplane-system --create-namespace --wait --timeout 5m >/dev/null
+
+  log "install Harbor (in-cluster, no TLS/persistence)"
+  helm repo add harbor https://helm.goharbor.io >/dev/null 2>&1 || true
+  helm repo update harbor >/dev/null 2>&1
+  k create namespace harbor --dry-run=client -o yaml | k apply -f - >/dev/null
+  helm --kube-context "$KCTX" upgrade --install my-harbor harbor/harbor -n harbor \
+    --set expose.type=clusterIP --set expose.tls.enabled=false \
+    --set externalURL=http://harbor.harbor.svc --set persistence.enabled=false \
+    --set harborAdminPassword="$HARBOR_PASSWORD" --set trivy.enabled=true \
+    --set jobservice.replicas=1 --wait --timeout 10m >/dev/null
+}
+
+# install_provider installs or upgrades the provider to the given package
+# tag and waits until the revision of that tag is healthy.
+install_provider() {
+  local version="$1"
+  log "install provider ${IMAGE}:${version}"
+  local pull_ref=""
+  if [ "${PRIVATE:-true}" = "true" ]; then
+    local token user
+    token="${GHCR_TOKEN:-$(gh auth token 2>/dev/null || true)}"
+    user="${GHCR_USER:-$(gh api user -q .login 2>/dev/null || echo x)}"
+    [ -n "$token" ] || { echo "no GHCR token (export GHCR_TOKEN or 'gh auth refresh -s read:packages')"; exit 1; }
+    k create secret docker-registry ghcr-pull -n crossplane-system \
+      --docker-server=ghcr.io --docker-username="$user" --docker-password="$token" \
+      --dry-run=client -o yaml | k apply -f - >/dev/null
+    pull_ref=$'\n  packagePullSecrets:\n    - name: ghcr-pull'
+  fi
+  cat <<YAML | k apply -f -
+apiVersion: pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: ${PROVIDER}
+spec:
+  package: ${IMAGE}:${version}${pull_ref}
+YAML
+  log "wait provider Healthy"
+  local i healthy="" current
+  for i in $(seq 1 60); do
+    current="$(k get providerrevision -l pkg.crossplane.io/package="$PROVIDER" \
+      -o jsonpath="{.items[?(@.spec.image=='${IMAGE}:${version}')].status.conditions[?(@.type=='Healthy')].status}" 2>/dev/null || true)"
+    if [ "$current" = "True" ] &&
+      [ "$(k get provider.pkg "$PROVIDER" -o jsonpath='{.status.conditions[?(@.type=="Healthy")].status}' 2>/dev/null)" = "True" ]; then
+      healthy=1
+      break
+    fi
+    sleep 5
+  done
+  [ -n "$healthy" ] || { echo "provider ${IMAGE}:${version} did not become Healthy"; exit 1; }
+  local n
+  n="$(k get crd -o name 2>/dev/null | grep -c 'harbor.m.crossplane.io' || true)"
+  [ "$n" -gt 0 ] || { echo "provider Healthy but $n CRDs registered — packaging broken"; exit 1; }
+  log "provider Healthy, ${n} CRDs"
+}
+
+# use_kind_context points the current kubectl context at the kind cluster, as
+# uptest and chainsaw need, and restores the caller's context on exit.
+use_kind_context() {
+  ORIG_CTX="$(kubectl config current-context 2>/dev/null || true)"
+  trap restore_ctx EXIT
+  kubectl config use-context "$KCTX" >/dev/null
+}
+restore_ctx() { [ -n "$ORIG_CTX" ] && kubectl config use-context "$ORIG_CTX" >/dev/null 2>&1 || true; }
+
+# teardown_cluster deletes the kind cluster unless KEEP is set.
+teardown_cluster() {
+  if [ -z "${KEEP:-}" ]; then
+    log "delete kind cluster ${KIND_CLUSTER}"
+    kind delete cluster --name "$KIND_CLUSTER" >/dev/null 2>&1 || true
+  fi
+}
@@ -0,0 +1,114 @@
+#!/usr/bin/env bash
+# Upgrade e2e for provider-harbor on KIND with a REAL Harbor.
+#
+# Installs the provider at UPGRADE_FROM (the previous release), creates the
+# examples/e2e resources and waits for them to be Ready, then upgrades the
+# provider to VERSION in place. Once every resource has been observed by the
+# new build, their external names, IDs and Ready conditions, and the objects
+# in Harbor, must be exactly as before: a CRD schema change that loses an
+# external name makes the new build create a duplicate or orphan the
+# original, which shows up here as a changed ID or an extra Harbor object.
+#
+# Env knobs (as for e2e.sh, plus):
+#   UPGRADE_FROM (required: the published package tag to upgrade from)
+#   SYNC_TIMEOUT (600, seconds to wait for the new build to observe everything)
+set -euo pipefail
+
+KIND_CLUSTER="${KIND_CLUSTER:-harbor-upgrade}"
+REGISTRY="${REGISTRY:-ghcr.io/rossigee}"
+PROVIDER="${PROVIDER:-provider-harbor}"
+VERSION="${VERSION:?set VERSION to the package tag to upgrade to}"
+UPGRADE_FROM="${UPGRADE_FROM:?set UPGRADE_FROM to the package tag to upgrade from, e.g. v0.17.0}"
+HARBOR_PASSWORD="${HARBOR_PASSWORD:-Harbor12345}"
+IMAGE="${IMAGE:-${REGISTRY}/${PROVIDER}}"
+SYNC_TIMEOUT="${SYNC_TIMEOUT:-600}"
+# shellcheck source=e2e-lib.sh
+source "$(dirname "${BASH_SOURCE[0]}")/e2e-lib.sh"
+
+require kind kubectl helm jq curl
+
+EXAMPLES="$ROOT/examples/e2e"
+WORK="$(mktemp -d)"
+HARBOR_PORT="${HARBOR_PORT:-18080}"
+
+# resource_snapshot prints what must survive the upgrade of each example.
+resource_snapshot() {
+  k get -f "$EXAMPLES" -o json | jq -S '[.items[] | {
+    kind, name: .metadata.name,
+    externalName: .metadata.annotations["crossplane.io/external-name"],
+    id: .status.atProvider.id,
+    ready: ([.status.conditions[]? | select(.type == "Ready") | .status] | first)
+  }] | sort_by(.kind, .name)'
+}
+
+# harbor_snapshot prints the IDs and names of the objects in Harbor.
+harbor_snapshot() {
+  local path
+  for path in projects registries users robots usergroups replication/policies; do
+    curl -sf -u "admin:${HARBOR_PASSWORD}" "http://localhost:${HARBOR_PORT}/api/v2.0/${path}?page_size=100" |
+      jq -S --arg p "$path" '{($p): [.[] | {
+        id: (.project_id // .user_id // .id),
+        name: (.name // .username // .group_name)
+      }] | sort_by(.id)}'
+  done | jq -S -s add
+}
+
+# wait_observed waits until the new build has recorded a sync of every
+# example after the given RFC 3339 time.
+wait_observed() {
+  local since="$1" deadline=$((SECONDS + SYNC_TIMEOUT)) stale
+  while :; do
+    stale="$(k get -f "$EXAMPLES" -o json |
+      jq -r --arg t "$since" '.items[] | select((.status.lastSyncTime // "") < $t) | "\(.kind)/\(.metadata.name)"')"
+    [ -z "$stale" ] && return 0
+    if [ "$SECONDS" -ge "$deadline" ]; then
+      echo "not observed by ${VERSION} within ${SYNC_TIMEOUT}s:"; echo "$stale"
+      return 1
+    fi
+    sleep 10
+  done
+}
+
+setup_cluster
+install_provider "$UPGRADE_FROM"
+use_kind_context
+
+KUBECTL=$(command -v kubectl) bash "$ROOT/test/e2e/uptest-setup.sh"
+
+log "port-forward Harbor to localhost:${HARBOR_PORT}"
+k -n harbor port-forward svc/harbor "${HARBOR_PORT}:80" >/dev/null 2>&1 &
+PF=$!
+trap 'kill $PF 2>/dev/null || true; restore_ctx' EXIT
+for i in $(seq 1 30); do
+  curl -sf "http://localhost:${HARBOR_PORT}/api/v2.0/ping" >/dev/null && break
+  sleep 2
+done
+
+log "create examples with ${UPGRADE_FROM}"
+k apply -f "$EXAMPLES"
+k wait -f "$EXAMPLES" --for=condition=Ready --timeout=600s
+resource_snapshot >"$WORK/resources-before.json"
+harbor_snapshot >"$WORK/harbor-before.json"
+
+UPGRADED_AT="$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+install_provider "$VERSION"
+log "wait until ${VERSION} has observed every example"
+wait_observed "$UPGRADED_AT"
+k wait -f "$EXAMPLES" --for=condition=Ready --timeout=600s
+resource_snapshot >"$WORK/resources-after.json"
+harbor_snapshot >"$WORK/harbor-after.json"
+
+rc=0
+for s in resources harbor; do
+  if ! diff -u "$WORK/$s-before.json" "$WORK/$s-after.json"; then
+    echo "upgrade from ${UPGRADE_FROM} to ${VERSION} changed the ${s} above"
+    rc=1
+  fi
+done
+[ "$rc" -eq 0 ] && log "upgrade from ${UPGRADE_FROM} to ${VERSION} left every resource and Harbor object in place"
+
+log "delete examples"
+k delete -f "$EXAMPLES" --wait --timeout=600s || rc=$?
+
+teardown_cluster
+exit $rc
@@ -3,7 +3,8 @@
 #
 # Stands up (idempotently): a kind cluster -> Crossplane -> Harbor (goharbor
 # Helm chart, in-cluster, no TLS/persistence) -> the provider package -> then
-# runs uptest (apply -> Ready -> delete) over examples/e2e/*.
+# runs uptest (apply -> Ready -> delete) over examples/e2e/* and the composite
+# resources in examples/composition.
 #
 # Runs uptest v2 (namespaced-aware): apply -> Ready -> import -> delete. The
 # import step (delete local state, re-observe the real external resource) works
@@ -24,13 +25,9 @@ PROVIDER="${PROVIDER:-provider-harbor}"
 VERSION="${VERSION:?set VERSION to a published package tag, e.g. v0.17.0}"
 HARBOR_PASSWORD="${HARBOR_PASSWORD:-Harbor12345}"
 IMAGE="${IMAGE:-${REGISTRY}/${PROVIDER}}"
-KCTX="kind-${KIND_CLUSTER}"
-ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+# shellcheck source=e2e-lib.sh
+source "$(dirname "${BASH_SOURCE[0]}")/e2e-lib.sh"
 
-log() { printf '\033[36m==>\033[0m %s\n' "$*"; }
-k() { kubectl --context "$KCTX" "$@"; }
-
-require() { for c in "$@"; do command -v "$c" >/dev/null || { echo "missing: $c"; exit 1; }; done; }
 require kind kubectl helm
 CHAINSAW="${CHAINSAW:-$(command -v chainsaw || true)}"
 [ -n "$CHAINSAW" ] || { echo "missing: chainsaw"; exit 1; }
@@ -45,62 +42,13 @@ if [ ! -x "$UPTEST" ]; then
   chmod +x "$UPTEST"
 fi
 
-log "ensure kind cluster ${KIND_CLUSTER}"
-kind get clusters 2>/dev/null | grep -qx "$KIND_CLUSTER" || kind create cluster --name "$KIND_CLUSTER"
-
-log "install Crossplane"
-helm repo add crossplane-stable https://charts.crossplane.io/stable >/dev/null 2>&1 || true
-helm repo update crossplane-stable >/dev/null 2>&1
-helm --kube-context "$KCTX" upgrade --install crossplane crossplane-stable/crossplane \
-  -n crossplane-system --create-namespace --wait --timeout 5m >/dev/null
-
-log "install Harbor (in-cluster, no TLS/persistence)"
-helm repo add harbor https://helm.goharbor.io >/dev/null 2>&1 || true
-helm repo update harbor >/dev/null 2>&1
-k create namespace harbor --dry-run=client -o yaml | k apply -f - >/dev/null
-helm --kube-context "$KCTX" upgrade --install my-harbor harbor/harbor -n harbor \
-  --set expose.type=clusterIP --set expose.tls.enabled=false \
-  --set externalURL=http://harbor.harbor.svc --set persistence.enabled=false \
-  --set harborAdminPassword="$HARBOR_PASSWORD" --set trivy.enabled=true \
-  --set jobservice.replicas=1 --wait --timeout 10m >/dev/null
-
-log "install provider ${IMAGE}:${VERSION}"
-PULL_REF=""
-if [ "${PRIVATE:-true}" = "true" ]; then
-  TOKEN="${GHCR_TOKEN:-$(gh auth token 2>/dev/null || true)}"
-  USER="${GHCR_USER:-$(gh api user -q .login 2>/dev/null || echo x)}"
-  [ -n "$TOKEN" ] || { echo "no GHCR token (export GHCR_TOKEN or 'gh auth refresh -s read:packages')"; exit 1; }
-  k create secret docker-registry ghcr-pull -n crossplane-system \
-    --docker-server=ghcr.io --docker-username="$USER" --docker-password="$TOKEN" \
-    --dry-run=client -o yaml | k apply -f - >/dev/null
-  PULL_REF=$'\n  packagePullSecrets:\n    - name: ghcr-pull'
-fi
-cat <<EOF | k apply -f -
-apiVersion: pkg.crossplane.io/v1
-kind: Provider
-metadata:
-  name: ${PROVIDER}
-spec:
-  package: ${IMAGE}:${VERSION}${PULL_REF}
-EOF
-log "wait provider Healthy"
-for i in $(seq 1 60); do
-  [ "$(k get provider.pkg "$PROVIDER" -o jsonpath='{.status.conditions[?(@.type=="Healthy")].status}' 2>/dev/null)" = "True" ] && break
-  sleep 5
-done
-n="$(k get crd -o name 2>/dev/null | grep -c 'harbor.m.crossplane.io' || true)"
-[ "$n" -gt 0 ] || { echo "provider Healthy but $n CRDs registered — packaging broken"; exit 1; }
-log "provider Healthy, ${n} CRDs"
+setup_cluster
+install_provider "$VERSION"
 
 log "run uptest e2e (apply -> Ready -> delete)"
 cd "$ROOT"   # uptest resolves manifest paths relative to cwd
 LIST="$(cd "$ROOT" && ls examples/e2e/*.yaml | paste -sd, -)"
-# uptest + chainsaw use the *current* kubectl context; point it at the kind
-# cluster and restore the caller's context on exit.
-ORIG_CTX="$(kubectl config current-context 2>/dev/null || true)"
-restore_ctx() { [ -n "$ORIG_CTX" ] && kubectl config use-context "$ORIG_CTX" >/dev/null 2>&1 || true; }
-trap restore_ctx EXIT
-kubectl config use-context "$KCTX" >/dev/null
+use_kind_context
 
 rc=0
 KUBECTL=$(command -v kubectl) CHAINSAW="$CHAINSAW" \
@@ -108,8 +56,15 @@ KUBECTL=$(command -v kubectl) CHAINSAW="$CHAINSAW" \
   --setup-script="$ROOT/test/e2e/uptest-setup.sh" \
   --default-conditions=Ready --skip-update --default-timeout=600s || rc=$?
 
-if [ -z "${KEEP:-}" ]; then
-  log "delete kind cluster ${KIND_CLUSTER}"
-  kind delete cluster --name "$KIND_CLUSTER" >/dev/null 2>&1 || true
-fi
+# The same path through Crossplane: namespaced composite resources (v2's
+# replacement for claims) compose every namespaced kind, and each is Ready
+# only once all it composes is Ready against Harbor.
+log "run uptest e2e over examples/composition"
+COMPOSITES="examples/composition/platform.yaml,examples/composition/tenant.yaml,examples/composition/image.yaml"
+KUBECTL=$(command -v kubectl) CHAINSAW="$CHAINSAW" \
+  "$UPTEST" e2e "$COMPOSITES" \
+  --setup-script="$ROOT/test/e2e/uptest-composition-setup.sh" \
+  --default-conditions=Ready --skip-update --skip-import --default-timeout=900s || rc=$?
+
+teardown_cluster
 exit $rc
@@ -0,0 +1,301 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// compositionDir holds the composite resource definitions, compositions and
+// composite resources that exercise the provider's kinds through Crossplane.
+const compositionDir = "composition"
+
+// A composition is the part of a Composition run by
+// function-patch-and-transform.
+type composition struct {
+	PatchSets []struct {
+		Name    string  `json:"name"`
+		Patches []patch `json:"patches"`
+	} `json:"patchSets"`
+	Resources []struct {
+		Name    string         `json:"name"`
+		Base    map[string]any `json:"base"`
+		Patches []patch        `json:"patches"`
+	} `json:"resources"`
+}
+
+// A patch of function-patch-and-transform. Only the patches and transforms
+// the examples use are supported.
+type patch struct {
+	Type          string `json:"type"`
+	PatchSetName  string `json:"patchSetName"`
+	FromFieldPath string `json:"fromFieldPath"`
+	ToFieldPath   string `json:"toFieldPath"`
+	Transforms    []struct {
+		Type   string `json:"type"`
+		String *struct {
+			Type string `json:"type"`
+			Fmt  string `json:"fmt"`
+		} `json:"string"`
+	} `json:"transforms"`
+}
+
+// compositionExamples returns the composite resource definitions, by the
+// GVK of their composite resources, the compositions, by the GVK of the
+// composite resources they compose, and the composite resources under
+// compositionDir.
+func compositionExamples(t *testing.T) (map[schema.GroupVersionKind]*version, map[schema.GroupVersionKind]*composition, []document) {
+	t.Helper()
+	xrds := map[schema.GroupVersionKind]*version{}
+	comps := map[schema.GroupVersionKind]*composition{}
+	var xrs []document
+	for _, d := range loadExamples(t) {
+		if filepath.Dir(d.file) != compositionDir {
+			continue
+		}
+		switch d.object["kind"] {
+		case "CompositeResourceDefinition":
+			xrd := &extv1.CustomResourceDefinition{}
+			convert(t, d, d.object, xrd)
+			for _, v := range xrd.Spec.Versions {
+				spec := v.Schema.OpenAPIV3Schema.Properties["spec"]
+				ver, err := newVersion(&spec)
+				if err != nil {
+					t.Fatalf("%s: %s: %v", d, v.Name, err)
+				}
+				xrds[schema.GroupVersionKind{Group: xrd.Spec.Group, Version: v.Name, Kind: xrd.Spec.Names.Kind}] = ver
+			}
+		case "Composition":
+			spec, _ := d.object["spec"].(map[string]any)
+			ref, _ := spec["compositeTypeRef"].(map[string]any)
+			gv, err := schema.ParseGroupVersion(fmt.Sprint(ref["apiVersion"]))
+			if err != nil {
+				t.Fatalf("%s: %v", d, err)
+			}
+			pipeline, _ := spec["pipeline"].([]any)
+			for _, s := range pipeline {
+				step, _ := s.(map[string]any)
+				input, _ := step["input"].(map[string]any)
+				if input["kind"] != "Resources" {
+					continue
+				}
+				c := &composition{}
+				convert(t, d, input, c)
+				comps[gv.WithKind(fmt.Sprint(ref["kind"]))] = c
+			}
+		case "Function":
+		default:
+			xrs = append(xrs, d)
+		}
+	}
+	return xrds, comps, xrs
+}
+
+// convert converts in to out through JSON, keeping whole numbers integers
+// as the API server does.
+func convert(t *testing.T, d document, in any, out any) {
+	t.Helper()
+	b, err := json.Marshal(in)
+	if err == nil {
+		err = json.Unmarshal(b, out)
+	}
+	if err != nil {
+		t.Fatalf("%s: %v", d, err)
+	}
+}
+
+// render returns the resources c composes for xr, as
+// function-patch-and-transform would.
+func (c *composition) render(xr map[string]any) ([]map[string]any, error) {
+	sets := map[string][]patch{}
+	for _, s := range c.PatchSets {
+		sets[s.Name] = s.Patches
+	}
+	meta, _ := xr["metadata"].(map[string]any)
+
+	var out []map[string]any
+	for _, r := range c.Resources {
+		cd := runtime.DeepCopyJSON(r.Base)
+		if err := setField(cd, "metadata.name", fmt.Sprintf("%s-%s", meta["name"], r.Name)); err != nil {
+			return nil, err
+		}
+		if err := setField(cd, "metadata.namespace", meta["namespace"]); err != nil {
+			return nil, err
+		}
+		var patches []patch
+		for _, p := range r.Patches {
+			if p.Type == "PatchSet" {
+				ps, ok := sets[p.PatchSetName]
+				if !ok {
+					return nil, fmt.Errorf("%s: no patch set %q", r.Name, p.PatchSetName)
+				}
+				patches = append(patches, ps...)
+				continue
+			}
+			patches = append(patches, p)
+		}
+		for _, p := range patches {
+			if err := p.apply(xr, cd); err != nil {
+				return nil, fmt.Errorf("%s: %w", r.Name, err)
+			}
+		}
+		out = append(out, cd)
+	}
+	return out, nil
+}
+
+// apply applies p from xr to the composed resource cd. A patch from a field
+// xr does not set is skipped.
+func (p patch) apply(xr, cd map[string]any) error {
+	if p.Type != "FromCompositeFieldPath" {
+		return fmt.Errorf("unsupported patch type %q", p.Type)
+	}
+	v, ok := fieldValue(xr, p.FromFieldPath)
+	if !ok {
+		return nil
+	}
+	for _, tr := range p.Transforms {
+		if tr.Type != "string" || tr.String == nil || tr.String.Type != "Format" {
+			return fmt.Errorf("unsupported transform of %s", p.FromFieldPath)
+		}
+		v = fmt.Sprintf(tr.String.Fmt, v)
+	}
+	return setField(cd, p.ToFieldPath, v)
+}
+
+// segment matches one element of a field path, such as projects[0].
+var segment = regexp.MustCompile(`^([^\[\]]+)(?:\[(\d+)\])?$`)
+
+// fieldValue returns the value at the dotted path in obj.
+func fieldValue(obj map[string]any, path string) (any, bool) {
+	var v any = obj
+	for _, s := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[s]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// setField sets the value at path in obj, such as spec.projects[0].name,
+// creating the objects on the way.
+func setField(obj map[string]any, path string, value any) error {
+	parts := strings.Split(path, ".")
+	m := obj
+	for i, s := range parts {
+		g := segment.FindStringSubmatch(s)
+		if g == nil {
+			return fmt.Errorf("invalid field path %q", path)
+		}
+		last := i == len(parts)-1
+		if g[2] == "" {
+			if last {
+				m[g[1]] = value
+				return nil
+			}
+			next, ok := m[g[1]].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				m[g[1]] = next
+			}
+			m = next
+			continue
+		}
+		idx, _ := strconv.Atoi(g[2])
+		list, _ := m[g[1]].([]any)
+		if idx >= len(list) {
+			return fmt.Errorf("%s: index %d out of range", path, idx)
+		}
+		if last {
+			list[idx] = value
+			return nil
+		}
+		next, ok := list[idx].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: element %d is not an object", path, idx)
+		}
+		m = next
+	}
+	return nil
+}
+
+// TestCompositionsMatchCRDs renders the resources each example composite
+// resource composes and checks them against the provider's CRDs, so that a
+// composite resource cannot pass validation only for what it composes to be
+// rejected. It also checks that together they compose every namespaced kind
+// the provider serves, the path from composite resource to managed resource
+// to Harbor the e2e suite exercises.
+func TestCompositionsMatchCRDs(t *testing.T) {
+	versions := loadCRDs(t)
+	xrds, comps, xrs := compositionExamples(t)
+	ctx := context.Background()
+
+	if len(xrs) == 0 {
+		t.Fatalf("no composite resources in %s", compositionDir)
+	}
+	composed := map[schema.GroupVersionKind]bool{}
+	for _, d := range xrs {
+		gv, err := schema.ParseGroupVersion(fmt.Sprint(d.object["apiVersion"]))
+		if err != nil {
+			t.Errorf("%s: %v", d, err)
+			continue
+		}
+		gvk := gv.WithKind(fmt.Sprint(d.object["kind"]))
+		xrd, ok := xrds[gvk]
+		if !ok {
+			t.Errorf("%s: no CompositeResourceDefinition defines %s", d, gvk)
+			continue
+		}
+		spec, _ := d.object["spec"].(map[string]any)
+		for _, e := range xrd.validate(ctx, spec) {
+			t.Errorf("%s: %s: %v", d, gvk.Kind, e)
+		}
+
+		c, ok := comps[gvk]
+		if !ok {
+			t.Errorf("%s: no Composition composes %s", d, gvk)
+			continue
+		}
+		resources, err := c.render(d.object)
+		if err != nil {
+			t.Errorf("%s: %v", d, err)
+			continue
+		}
+		for _, r := range resources {
+			rgv, _ := schema.ParseGroupVersion(fmt.Sprint(r["apiVersion"]))
+			rgvk := rgv.WithKind(fmt.Sprint(r["kind"]))
+			v, ok := versions[rgvk]
+			if !ok {
+				t.Errorf("%s: composes %s, which no CRD serves", d, rgvk)
+				continue
+			}
+			for _, e := range v.validate(ctx, r) {
+				t.Errorf("%s: composed %s: %v", d, rgvk.Kind, e)
+			}
+			composed[rgvk] = true
+		}
+	}
+
+	for gvk, v := range versions {
+		if v.namespaced && !composed[gvk] {
+			t.Errorf("no example composite resource composes %s", gvk)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+// Package conformance checks the example manifests against the generated
+// CRDs, so that examples that would be rejected by the API server are caught
+// by the unit tests, and renders the composite resources in
+// examples/composition through their Compositions to check what they compose
+// the same way. It also checks that the native provider only builds
+// against crossplane-runtime v2.
+package conformance
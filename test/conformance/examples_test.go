@@ -0,0 +1,247 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	structuralpruning "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	crdDir      = "../../package/crds"
+	examplesDir = "../../examples"
+
+	// groupSuffix is shared by every API group the provider serves.
+	groupSuffix = ".harbor.m.crossplane.io"
+)
+
+// legacyGroupSuffix is shared by the API groups of the Terraform-based
+// provider. Examples written for it are kept for reference, but the CRDs
+// they need are no longer generated.
+const legacyGroupSuffix = "harbor.crossplane.io"
+
+// A version is one served version of a CRD, ready to validate objects.
+type version struct {
+	structural *structuralschema.Structural
+	validator  validation.SchemaValidator
+	cel        *cel.Validator
+
+	// namespaced is whether the CRD's objects are namespaced.
+	namespaced bool
+}
+
+// newVersion returns a version validating objects against props.
+func newVersion(props *extv1.JSONSchemaProps) (*version, error) {
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := extv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(props, internal, nil); err != nil {
+		return nil, err
+	}
+	s, err := structuralschema.NewStructural(internal)
+	if err != nil {
+		return nil, fmt.Errorf("schema is not structural: %w", err)
+	}
+	sv, _, err := validation.NewSchemaValidator(internal)
+	if err != nil {
+		return nil, err
+	}
+	return &version{
+		structural: s,
+		validator:  sv,
+		cel:        cel.NewValidator(s, true, celconfig.PerCallLimit),
+	}, nil
+}
+
+// loadCRDs reads the generated CRDs and returns each served version by GVK.
+func loadCRDs(t *testing.T) map[schema.GroupVersionKind]*version {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(crdDir, "*.yaml"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("cannot find CRDs in %s: %v", crdDir, err)
+	}
+
+	versions := map[schema.GroupVersionKind]*version{}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		crd := &extv1.CustomResourceDefinition{}
+		if err := yaml.UnmarshalStrict(data, crd); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		for _, v := range crd.Spec.Versions {
+			if !v.Served || v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			ver, err := newVersion(v.Schema.OpenAPIV3Schema)
+			if err != nil {
+				t.Fatalf("%s %s: %v", f, v.Name, err)
+			}
+			ver.namespaced = crd.Spec.Scope == extv1.NamespaceScoped
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind}
+			versions[gvk] = ver
+		}
+	}
+	return versions
+}
+
+// A document is one YAML document of an example file.
+type document struct {
+	file   string
+	index  int
+	object map[string]interface{}
+}
+
+func (d document) String() string {
+	return fmt.Sprintf("%s#%d", d.file, d.index)
+}
+
+// loadExamples reads every YAML document under the examples directory.
+func loadExamples(t *testing.T) []document {
+	t.Helper()
+	var docs []document
+	err := filepath.WalkDir(examplesDir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(examplesDir, path)
+		r := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+		for i := 0; ; i++ {
+			raw, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			// Decode numbers as the API server does, so that integers reach
+			// CEL rules as integers rather than floats.
+			obj := map[string]interface{}{}
+			j, err := yaml.YAMLToJSON(raw)
+			if err == nil {
+				err = utiljson.Unmarshal(j, &obj)
+			}
+			if err != nil {
+				t.Errorf("%s#%d: invalid YAML: %v", rel, i, err)
+				continue
+			}
+			if len(obj) == 0 {
+				continue
+			}
+			docs = append(docs, document{file: rel, index: i, object: obj})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return docs
+}
+
+// validate checks obj as the API server would when it is created: unknown
+// fields, OpenAPI validation and then CEL rules against the defaulted object.
+func (v *version) validate(ctx context.Context, obj map[string]interface{}) field.ErrorList {
+	var errs field.ErrorList
+	for _, p := range structuralpruning.PruneWithOptions(runtime.DeepCopyJSON(obj), v.structural, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true}) {
+		errs = append(errs, field.Invalid(field.NewPath(p), nil, "unknown field"))
+	}
+
+	structuraldefaulting.Default(obj, v.structural)
+	errs = append(errs, validation.ValidateCustomResource(nil, obj, v.validator)...)
+
+	celErrs, _ := v.cel.Validate(ctx, nil, v.structural, obj, nil, celconfig.RuntimeCELCostBudget)
+	return append(errs, celErrs...)
+}
+
+func TestExamplesMatchCRDs(t *testing.T) {
+	versions := loadCRDs(t)
+	ctx := context.Background()
+
+	checked := 0
+	for _, d := range loadExamples(t) {
+		apiVersion, _ := d.object["apiVersion"].(string)
+		kind, _ := d.object["kind"].(string)
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			t.Errorf("%s: invalid apiVersion %q: %v", d, apiVersion, err)
+			continue
+		}
+		switch {
+		case strings.HasSuffix(gv.Group, groupSuffix) || gv.Group == strings.TrimPrefix(groupSuffix, "."):
+		case strings.HasSuffix(gv.Group, legacyGroupSuffix):
+			t.Logf("%s: skipping %s %s from the legacy provider", d, apiVersion, kind)
+			continue
+		default:
+			continue
+		}
+
+		v, ok := versions[gv.WithKind(kind)]
+		if !ok {
+			t.Errorf("%s: no CRD serves %s %s", d, apiVersion, kind)
+			continue
+		}
+		for _, e := range v.validate(ctx, d.object) {
+			t.Errorf("%s: %s %s: %v", d, apiVersion, kind, e)
+		}
+		checked++
+	}
+
+	if checked == 0 {
+		t.Fatal("no examples of the provider's kinds were checked")
+	}
+}
+
+// TestEveryKindHasAnExample keeps the examples covering every kind the
+// provider serves.
+func TestEveryKindHasAnExample(t *testing.T) {
+	versions := loadCRDs(t)
+	seen := map[schema.GroupVersionKind]bool{}
+	for _, d := range loadExamples(t) {
+		apiVersion, _ := d.object["apiVersion"].(string)
+		kind, _ := d.object["kind"].(string)
+		if gv, err := schema.ParseGroupVersion(apiVersion); err == nil {
+			seen[gv.WithKind(kind)] = true
+		}
+	}
+	for gvk := range versions {
+		if gvk.Kind == "ProviderConfigUsage" {
+			continue
+		}
+		if !seen[gvk] {
+			t.Errorf("no example of %s", gvk)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 Crossplane Harbor Provider.
+*/
+
+package conformance
+
+import (
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	moduleDir = "../.."
+
+	// legacyTag is the build tag the Terraform-based code needs.
+	legacyTag = "upjet"
+)
+
+// legacyImports are import path prefixes the native provider must not build
+// against. Mixing crossplane-runtime v1 and v2 types does not compile, and
+// upjet belongs to the Terraform-based provider.
+var legacyImports = []string{
+	"github.com/crossplane/crossplane-runtime/",
+	"github.com/crossplane/crossplane/apis/",
+	"github.com/crossplane/upjet",
+}
+
+// nativeImports are the v2 modules, which share a prefix with legacyImports.
+var nativeImports = []string{
+	"github.com/crossplane/crossplane-runtime/v2/",
+	"github.com/crossplane/crossplane/apis/v2/",
+}
+
+func isLegacyImport(path string) bool {
+	for _, p := range nativeImports {
+		if strings.HasPrefix(path, p) {
+			return false
+		}
+	}
+	for _, p := range legacyImports {
+		if strings.HasPrefix(path, p) || path == strings.TrimSuffix(p, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// needsLegacyTag reports whether the build constraint of the file at path
+// excludes it from builds without legacyTag.
+func needsLegacyTag(t *testing.T, path string) bool {
+	t.Helper()
+	data, err := os.ReadFile(path) //nolint:gosec // paths come from walking the module
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if !constraint.IsGoBuild(line) {
+			continue
+		}
+		expr, err := constraint.Parse(line)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		return !expr.Eval(func(tag string) bool { return tag != legacyTag })
+	}
+	return false
+}
+
+// TestNoLegacyImports checks that only files built with the upjet tag import
+// crossplane-runtime v1 or upjet, so that every controller uses the v2
+// managed reconciler and the default build never mixes the two.
+func TestNoLegacyImports(t *testing.T) {
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(moduleDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); path != moduleDir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, imp := range f.Imports {
+			p, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return err
+			}
+			if isLegacyImport(p) && !needsLegacyTag(t, path) {
+				t.Errorf("%s imports %s; use the v2 module, or build the file only with the %s tag", path, p, legacyTag)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsLegacyImport(t *testing.T) {
+	cases := map[string]bool{
+		"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed":    true,
+		"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed": false,
+		"github.com/crossplane/crossplane/apis/v2/core/v2":                   false,
+		"github.com/crossplane/crossplane/apis/apiextensions/v1":             true,
+		"github.com/crossplane/upjet/pkg/config":                             true,
+		"github.com/crossplane/crossplane-tools/cmd/angryjet":                false,
+	}
+	for path, want := range cases {
+		if got := isLegacyImport(path); got != want {
+			t.Errorf("isLegacyImport(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+#!/usr/bin/env bash
+# uptest setup for the composition examples: everything uptest-setup.sh
+# prepares, plus
+#   - the composition functions, XRDs and Compositions in examples/composition
+#   - the global "uptest-approved" label the HarborImage example attaches (a
+#     Harbor API call, like the seeded project, so uptest never deletes it)
+set -aeuo pipefail
+: "${KUBECTL:=kubectl}"
+NS="${UPTEST_NAMESPACE:-uptest}"
+HARBOR_PASSWORD="${HARBOR_PASSWORD:-Harbor12345}"
+LABEL="${LABEL:-uptest-approved}"
+HERE="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+EXAMPLES="$HERE/../../examples/composition"
+
+"$HERE/uptest-setup.sh"
+
+echo "uptest-composition-setup: functions, XRDs and Compositions"
+${KUBECTL} apply -f "$EXAMPLES/functions.yaml"
+${KUBECTL} wait function.pkg --all --for condition=Healthy --timeout 5m
+${KUBECTL} apply -f "$EXAMPLES/definitions.yaml"
+${KUBECTL} wait xrd --all --for condition=Established --timeout 2m
+${KUBECTL} apply -f "$EXAMPLES/compositions.yaml"
+
+echo "uptest-composition-setup: global label '${LABEL}'"
+${KUBECTL} -n "$NS" delete pod create-label --ignore-not-found >/dev/null
+${KUBECTL} -n "$NS" run create-label --restart=Never --quiet --rm -i \
+  --image=mirror.gcr.io/library/alpine:3.20 --command -- \
+  sh -c "apk add --no-cache curl >/dev/null && curl -s -o /dev/null -w '%{http_code}\n' -u 'admin:${HARBOR_PASSWORD}' \
+    -X POST http://harbor.harbor.svc/api/v2.0/labels -H 'Content-Type: application/json' \
+    -d '{\"name\":\"${LABEL}\",\"scope\":\"g\"}'"  # 409 if it exists
+echo "uptest-composition-setup: done"